@@ -2,19 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
 	httpAdapter "backend-go/internal/adapters/http"
+	httpMiddleware "backend-go/internal/adapters/http/middleware"
 	"backend-go/internal/config"
 	"backend-go/internal/container"
 	"backend-go/internal/shared/logger"
 	"backend-go/internal/shared/monitoring"
+	"backend-go/pkg/buildinfo"
+	"backend-go/pkg/middleware/cors"
+	"backend-go/pkg/response"
 
 	// Swagger imports
 	"backend-go/docs"
@@ -81,10 +90,191 @@ func setupSwaggerInfo(cfg *config.Config) {
 		docs.SwaggerInfo.Description = "生产环境的预测系统 API - 高性能体育比赛预测平台"
 	}
 
-	logger.Info("Swagger UI available at: %s://%s/swagger/index.html",
+	logger.Infof("Swagger UI available at: %s://%s/swagger/index.html",
 		docs.SwaggerInfo.Schemes[0], docs.SwaggerInfo.Host)
 }
 
+// bodyLimitConfig 根据配置构建请求体大小限制
+func bodyLimitConfig(cfg *config.Config) httpMiddleware.BodyLimitConfig {
+	routes := make(map[string]int64, len(cfg.Server.BodyLimit.Routes))
+	for _, route := range cfg.Server.BodyLimit.Routes {
+		routes[route.Path] = route.MaxBytes
+	}
+	return httpMiddleware.BodyLimitConfig{
+		MaxBytes: cfg.Server.BodyLimit.MaxBytes,
+		Routes:   routes,
+	}
+}
+
+// concurrencyLimitConfig 根据配置构建并发请求数限制
+func concurrencyLimitConfig(cfg *config.Config) httpMiddleware.ConcurrencyLimitConfig {
+	routes := make(map[string]int, len(cfg.Server.Concurrency.Routes))
+	for _, route := range cfg.Server.Concurrency.Routes {
+		routes[route.Path] = route.MaxInFlight
+	}
+	return httpMiddleware.ConcurrencyLimitConfig{
+		MaxInFlight:  cfg.Server.Concurrency.MaxInFlight,
+		Routes:       routes,
+		QueueTimeout: cfg.Server.Concurrency.QueueTimeout,
+		RetryAfter:   cfg.Server.Concurrency.RetryAfter,
+	}
+}
+
+// corsConfig 根据配置构建跨域设置，关闭 CORS 时返回 nil 且不注册跨域中间件
+func corsConfig(cfg *config.Config) *cors.Config {
+	if !cfg.Features.EnableCORS {
+		return nil
+	}
+	c := cfg.Features.CORSConfig
+	return &cors.Config{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+}
+
+// requestNonceConfig 将管理员写操作防重放配置转换为中间件配置，未启用时返回空配置
+func requestNonceConfig(cfg *config.Config, store httpMiddleware.NonceStore) httpMiddleware.NonceConfig {
+	nonce := cfg.Auth.RequestNonce
+	if !nonce.Enabled {
+		return httpMiddleware.NonceConfig{}
+	}
+	return httpMiddleware.NonceConfig{
+		Secret:   nonce.Secret,
+		MaxAge:   nonce.MaxAge,
+		Required: nonce.Required,
+		Store:    store,
+	}
+}
+
+// newStaleCache 按配置创建 Redis 故障时的降级响应缓存，未启用时返回 nil
+func newStaleCache(cfg *config.Config, healthy func() bool) *httpMiddleware.StaleCache {
+	stale := cfg.Cache.Stale
+	if !stale.Enabled {
+		return nil
+	}
+	return httpMiddleware.NewStaleCache(httpMiddleware.StaleCacheConfig{
+		Paths:      []string{"/api/leaderboard", "/api/matches"},
+		MaxEntries: stale.MaxEntries,
+		MaxBytes:   stale.MaxBytes,
+		MaxAge:     stale.MaxAge,
+		Healthy:    healthy,
+	})
+}
+
+// newIdempotency 按配置创建写请求幂等去重中间件，幂等键按访问令牌中的用户隔离，未启用时返回 nil
+func newIdempotency(cfg *config.Config, store httpMiddleware.IdempotencyStore, tokens httpMiddleware.IdempotencyTokenValidator) *httpMiddleware.Idempotency {
+	idempotency := cfg.Server.Idempotency
+	if !idempotency.Enabled {
+		return nil
+	}
+	return httpMiddleware.NewIdempotency(httpMiddleware.IdempotencyConfig{
+		Store:   store,
+		Tokens:  tokens,
+		TTL:     idempotency.TTL,
+		LockTTL: idempotency.LockTTL,
+	})
+}
+
+// paginationConfig 将列表分页上限配置转换为中间件配置
+func paginationConfig(cfg *config.Config) httpMiddleware.PaginationConfig {
+	return httpMiddleware.PaginationConfig{
+		MaxPageSize: cfg.Server.Pagination.MaxPageSize,
+		Reject:      cfg.Server.Pagination.RejectOversized,
+	}
+}
+
+// queryCounterConfig 将单请求语句数检查配置转换为中间件配置
+func queryCounterConfig(cfg *config.Config) httpMiddleware.QueryCounterConfig {
+	return httpMiddleware.QueryCounterConfig{
+		Enabled:   cfg.Features.QueryCounter.Enabled,
+		Threshold: cfg.Features.QueryCounter.Threshold,
+	}
+}
+
+// streamConnectionLimitConfig 将实时推送连接频率限制转换为中间件配置
+func streamConnectionLimitConfig(cfg *config.Config, store httpMiddleware.ConnectionAttemptStore) httpMiddleware.ConnectionLimitConfig {
+	return httpMiddleware.ConnectionLimitConfig{
+		MaxPerWindow: cfg.Server.StreamConnections.MaxPerWindow,
+		Window:       cfg.Server.StreamConnections.Window,
+		Store:        store,
+	}
+}
+
+// newHTTPServer 按 server 配置创建 HTTP 服务器
+//
+// 启用 HTTP/2 时，配置了 TLS 则通过 ALPN 协商 h2，否则用 h2c 包装处理器以支持明文 HTTP/2；
+// 未启用时禁用 TLS 下的自动 h2 协商，保证开关语义一致。
+func newHTTPServer(cfg *config.Config, handler http.Handler) (*http.Server, error) {
+	server := &http.Server{
+		Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:        handler,
+		ReadTimeout:    cfg.Server.ReadTimeout,
+		WriteTimeout:   cfg.Server.WriteTimeout,
+		IdleTimeout:    cfg.Server.IdleTimeout,
+		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+	}
+	server.SetKeepAlivesEnabled(cfg.Server.KeepAlive.Enabled)
+
+	if !cfg.Server.HTTP2.Enabled {
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return server, nil
+	}
+
+	h2 := &http2.Server{
+		MaxConcurrentStreams: cfg.Server.HTTP2.MaxConcurrentStreams,
+		IdleTimeout:          cfg.Server.IdleTimeout,
+	}
+	if cfg.Server.TLS.Enabled {
+		if err := http2.ConfigureServer(server, h2); err != nil {
+			return nil, fmt.Errorf("failed to configure HTTP/2: %w", err)
+		}
+		return server, nil
+	}
+	server.Handler = h2c.NewHandler(handler, h2)
+	return server, nil
+}
+
+// listen 监听服务端口并按配置设置 TCP keep-alive，关闭保活时不发送探测
+func listen(cfg *config.Config, addr string) (net.Listener, error) {
+	keepAlive := cfg.Server.KeepAlive.Period
+	if !cfg.Server.KeepAlive.Enabled {
+		keepAlive = -1
+	}
+	lc := net.ListenConfig{KeepAlive: keepAlive}
+	return lc.Listen(context.Background(), "tcp", addr)
+}
+
+// serve 在监听器上提供服务，配置了 TLS 时使用证书加密
+func serve(cfg *config.Config, server *http.Server, listener net.Listener) error {
+	if cfg.Server.TLS.Enabled {
+		return server.ServeTLS(listener, cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+	}
+	return server.Serve(listener)
+}
+
+// logBuildInfo 记录运行中服务的构建信息
+func logBuildInfo() {
+	info := buildinfo.Get()
+	logger.Infof("Build info: version=%s commit=%s built=%s go=%s env=%s",
+		info.Version, info.GitCommit, info.BuildDate, info.GoVersion, config.GetEnvironment())
+}
+
+// shutdowner 可在上下文截止前优雅关闭的服务，*http.Server 满足该接口
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// shutdownServer 在 timeout 内优雅关闭服务器，超时仍有未完成的请求时返回错误
+func shutdownServer(server shutdowner, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
 func main() {
 	// 加载配置
 	cfg, err := config.Load()
@@ -102,9 +292,17 @@ func main() {
 		MaxAge:     cfg.Log.MaxAge,
 		Compress:   cfg.Log.Compress,
 		LocalTime:  cfg.Log.LocalTime,
+
+		SamplingWindow: cfg.Log.SamplingWindow,
 	}
 	logger.InitWithConfig(logConfig)
 	logger.Info("Starting API server...")
+	logBuildInfo()
+	if configFile := config.GetLoadedConfigFile(cfg); configFile != "" {
+		logger.Infof("Loaded configuration from %s", configFile)
+	} else {
+		logger.Warn("No config file found, using default values and environment variables")
+	}
 
 	// 动态配置 Swagger 信息
 	setupSwaggerInfo(cfg)
@@ -112,29 +310,59 @@ func main() {
 	// 初始化依赖注入容器
 	container, err := container.NewContainer(cfg)
 	if err != nil {
-		logger.Fatal("Failed to initialize container: %v", err)
+		logger.Fatalf("Failed to initialize container: %v", err)
 	}
 	defer container.Close()
 
 	// 初始化监控服务
 	monitoringService := monitoring.NewMonitoringService(cfg)
+	monitoringService.SetCircuitBreakers(container.GetExternalBreakers())
 	if err := monitoringService.Initialize(container.GetDB(), container.GetRedisClient().GetRedisClient()); err != nil {
-		logger.Fatal("Failed to initialize monitoring service: %v", err)
+		logger.Fatalf("Failed to initialize monitoring service: %v", err)
 	}
 
 	// 执行启动探针
 	if err := monitoringService.StartupProbe(); err != nil {
-		logger.Fatal("Startup probe failed: %v", err)
+		logger.Fatalf("Startup probe failed: %v", err)
 	}
 
 	// 设置路由
 	router := httpAdapter.SetupRouter(httpAdapter.RouterConfig{
-		UserService:        container.GetUserService(),
-		MatchService:       container.GetMatchService(),
-		PredictionService:  container.GetPredictionService(),
-		LeaderboardService: container.GetLeaderboardService(),
-		ScoringService:     container.GetScoringService(),
-		TeamService:        container.GetTeamService(),
+		UserService:           container.GetUserService(),
+		AuthService:           container.GetAuthService(),
+		MatchService:          container.GetMatchService(),
+		PredictionService:     container.GetPredictionService(),
+		LeaderboardService:    container.GetLeaderboardService(),
+		ScoringService:        container.GetScoringService(),
+		TeamService:           container.GetTeamService(),
+		RealtimeHub:           container.GetRealtimeHub(),
+		StaleCache:            newStaleCache(cfg, container.GetRedisClient().IsHealthy),
+		Idempotency:           newIdempotency(cfg, container.GetCacheService(), container.GetJWTService()),
+		SLOTracker:            monitoringService.GetSLOTracker(),
+		ProbeState:            monitoringService.GetProbeState(),
+		AppConfig:             cfg,
+		Environment:           string(config.GetEnvironment()),
+		RedisTTLAuditor:       container.GetRedisTTLAuditor(),
+		WorkerHeartbeat:       container.GetWorkerHeartbeat(),
+		StatsRollup:           container.GetStatsRollup(),
+		FeatureFlags:          container.GetFeatureFlags(),
+		MigrationRunner:       container.GetMigrationRunner(),
+		MatchFinishService:    container.GetMatchFinishService(),
+		BadgeService:          container.GetBadgeService(),
+		FileStorage:           container.GetFileStorage(),
+		RequestNonce:          requestNonceConfig(cfg, container.GetCacheService()),
+		CORS:                  corsConfig(cfg),
+		CORSDisabled:          !cfg.Features.EnableCORS,
+		BodyLimit:             bodyLimitConfig(cfg),
+		ConcurrencyLimit:      concurrencyLimitConfig(cfg),
+		Pagination:            paginationConfig(cfg),
+		QueryCounter:          queryCounterConfig(cfg),
+		ResponseMetadata:      cfg.Server.ResponseMetadata,
+		RequestTimeout:        cfg.Server.RequestTimeout,
+		TrustedProxies:        cfg.Server.TrustedProxies,
+		JSONNaming:            response.NamingStrategy(cfg.Server.JSONNaming),
+		StreamConnectionLimit: streamConnectionLimitConfig(cfg, container.GetCacheService()),
+		UploadMaxSize:         cfg.External.FileStorage.MaxSize,
 
 		// 管理员系统服务
 		AdminService:       container.GetAdminService(),
@@ -151,19 +379,26 @@ func main() {
 	monitoringService.SetupRoutes(router)
 
 	// 创建 HTTP 服务器
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      router,
-		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
-		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
+	server, err := newHTTPServer(cfg, router)
+	if err != nil {
+		logger.Fatalf("Failed to configure server: %v", err)
+	}
+	listener, err := listen(cfg, server.Addr)
+	if err != nil {
+		logger.Fatalf("Failed to listen on %s: %v", server.Addr, err)
 	}
 
+	// 启动后台任务（比赛提醒依赖进程内的实时推送订阅中心，随 API 进程运行）
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go container.GetMatchReminderService().Run(backgroundCtx)
+	go container.GetFeatureFlags().Run(backgroundCtx, cfg.Features.Flags.RefreshInterval)
+
 	// 启动服务器
 	go func() {
-		logger.Info("Server listening on port %d", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("Failed to start server: %v", err)
+		logger.Infof("Server listening on port %d (http2=%t, tls=%t)", cfg.Server.Port, cfg.Server.HTTP2.Enabled, cfg.Server.TLS.Enabled)
+		if err := serve(cfg, server, listener); err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
@@ -173,14 +408,13 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopBackground()
 
 	// 优雅关闭服务器，等待现有连接完成
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		logger.Fatal("Server forced to shutdown: %v", err)
+	if err := shutdownServer(server, cfg.Server.ShutdownTimeout); err != nil {
+		logger.Fatalf("Server forced to shutdown after %s: %v", cfg.Server.ShutdownTimeout, err)
 	}
 
 	logger.Info("Server exited")
+	logger.Flush()
 }
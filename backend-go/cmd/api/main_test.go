@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"backend-go/internal/config"
+)
+
+// recordingServer 记录关闭时收到的上下文截止时间
+type recordingServer struct {
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (s *recordingServer) Shutdown(ctx context.Context) error {
+	s.deadline, s.hasDeadline = ctx.Deadline()
+	return nil
+}
+
+func TestShutdownServer_UsesConfiguredTimeout(t *testing.T) {
+	server := &recordingServer{}
+	start := time.Now()
+
+	if err := shutdownServer(server, 45*time.Second); err != nil {
+		t.Fatalf("shutdownServer() error = %v", err)
+	}
+	if !server.hasDeadline {
+		t.Fatal("关闭上下文缺少截止时间")
+	}
+	if remaining := server.deadline.Sub(start); remaining < 44*time.Second || remaining > 46*time.Second {
+		t.Errorf("关闭截止时间 = 启动后 %s, want 约 45s", remaining)
+	}
+}
+
+func TestShutdownServer_ForcedWhenRequestsOutlastTimeout(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	})}
+	go server.Serve(listener)
+
+	go http.Get("http://" + listener.Addr().String())
+	<-entered
+
+	// 处理中的请求超过关闭时限，应走强制关闭分支
+	start := time.Now()
+	err = shutdownServer(server, 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("shutdownServer() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("关闭耗时 %s, 应在时限到达后立即返回", elapsed)
+	}
+	server.Close()
+}
+
+func newServerTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Server.Port = 8080
+	cfg.Server.ReadTimeout = 30 * time.Second
+	cfg.Server.WriteTimeout = 30 * time.Second
+	cfg.Server.IdleTimeout = 120 * time.Second
+	cfg.Server.MaxHeaderBytes = 8192
+	cfg.Server.KeepAlive.Enabled = true
+	return cfg
+}
+
+func TestNewHTTPServer_AppliesServerConfig(t *testing.T) {
+	server, err := newHTTPServer(newServerTestConfig(), http.NotFoundHandler())
+	if err != nil {
+		t.Fatalf("newHTTPServer() error = %v", err)
+	}
+	if server.MaxHeaderBytes != 8192 {
+		t.Errorf("MaxHeaderBytes = %d, want 8192", server.MaxHeaderBytes)
+	}
+	if server.ReadTimeout != 30*time.Second || server.IdleTimeout != 120*time.Second {
+		t.Errorf("ReadTimeout = %s, IdleTimeout = %s, want 30s/120s", server.ReadTimeout, server.IdleTimeout)
+	}
+	// 未启用 HTTP/2 时 TLS 下也不协商 h2
+	if server.TLSNextProto == nil || len(server.TLSNextProto) != 0 {
+		t.Errorf("TLSNextProto = %v, want 空映射", server.TLSNextProto)
+	}
+}
+
+func TestNewHTTPServer_HTTP2OverTLS(t *testing.T) {
+	cfg := newServerTestConfig()
+	cfg.Server.HTTP2.Enabled = true
+	cfg.Server.TLS.Enabled = true
+
+	server, err := newHTTPServer(cfg, http.NotFoundHandler())
+	if err != nil {
+		t.Fatalf("newHTTPServer() error = %v", err)
+	}
+	if _, ok := server.TLSNextProto[http2.NextProtoTLS]; !ok {
+		t.Errorf("TLSNextProto = %v, want 包含 h2", server.TLSNextProto)
+	}
+	if server.TLSConfig == nil || len(server.TLSConfig.NextProtos) == 0 || server.TLSConfig.NextProtos[0] != http2.NextProtoTLS {
+		t.Errorf("TLSConfig.NextProtos 应优先协商 h2")
+	}
+}
+
+func TestNewHTTPServer_CleartextHTTP2(t *testing.T) {
+	cfg := newServerTestConfig()
+	cfg.Server.HTTP2.Enabled = true
+
+	server, err := newHTTPServer(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	if err != nil {
+		t.Fatalf("newHTTPServer() error = %v", err)
+	}
+	listener, err := listen(cfg, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	go serve(cfg, server, listener)
+	defer server.Close()
+
+	// 使用先验知识的 h2c 客户端直接发起 HTTP/2 请求
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}}
+	resp, err := client.Get("http://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("h2c 请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Errorf("响应协议 = %s, want HTTP/2.0", resp.Proto)
+	}
+}
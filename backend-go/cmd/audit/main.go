@@ -0,0 +1,316 @@
+// Package main provides a command-line tool for tailing admin audit logs.
+//
+// Usage:
+//
+//	audit -since 1h -resource matches -status failed
+//	audit -admin 3 -follow
+//	audit -json -since 24h | jq .
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/database"
+)
+
+const (
+	// auditTimeLayout 与数据库 created_at 比较时使用的时间格式
+	auditTimeLayout = "2006-01-02 15:04:05"
+
+	defaultLimit        = 50
+	defaultPollInterval = 5 * time.Second
+	followPageSize      = 100
+)
+
+// ANSI 颜色
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorGray   = "\033[90m"
+)
+
+// options 命令行参数
+type options struct {
+	configPath string
+	adminID    *uint
+	action     string
+	resource   string
+	status     *admin.AuditStatus
+	since      *time.Time
+	limit      int
+	follow     bool
+	interval   time.Duration
+	jsonOutput bool
+	noColor    bool
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:], time.Now())
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var cfg *config.Config
+	if opts.configPath != "" {
+		cfg, err = config.LoadFromFile(opts.configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := database.NewDB(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, services.NewAdminAuditService(db), opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseOptions 解析命令行参数，now 用于计算相对时间 -since
+func parseOptions(args []string, now time.Time) (*options, error) {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+
+	var (
+		configPath = fs.String("config", "", "Path to configuration file (default: environment based)")
+		adminID    = fs.Uint("admin", 0, "Filter by admin user ID")
+		action     = fs.String("action", "", "Filter by action (substring match)")
+		resource   = fs.String("resource", "", "Filter by resource")
+		status     = fs.String("status", "", "Filter by status: success, failed, partial")
+		since      = fs.String("since", "", "Only show entries since a duration ago (e.g. 30m) or an RFC3339 time")
+		limit      = fs.Int("limit", defaultLimit, "Number of recent entries to show")
+		follow     = fs.Bool("follow", false, "Keep polling for new entries")
+		interval   = fs.Duration("interval", defaultPollInterval, "Polling interval in follow mode")
+		jsonOutput = fs.Bool("json", false, "Output one JSON object per line")
+		noColor    = fs.Bool("no-color", false, "Disable colorized output")
+	)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	opts := &options{
+		configPath: *configPath,
+		action:     strings.TrimSpace(*action),
+		resource:   strings.TrimSpace(*resource),
+		limit:      *limit,
+		follow:     *follow,
+		interval:   *interval,
+		jsonOutput: *jsonOutput,
+		noColor:    *noColor,
+	}
+
+	if *adminID > 0 {
+		id := *adminID
+		opts.adminID = &id
+	}
+
+	if *status != "" {
+		s, err := parseStatus(*status)
+		if err != nil {
+			return nil, err
+		}
+		opts.status = &s
+	}
+
+	if *since != "" {
+		t, err := parseSince(*since, now)
+		if err != nil {
+			return nil, err
+		}
+		opts.since = &t
+	}
+
+	if opts.limit <= 0 {
+		return nil, fmt.Errorf("-limit must be positive")
+	}
+	if opts.follow && opts.interval <= 0 {
+		return nil, fmt.Errorf("-interval must be positive")
+	}
+
+	return opts, nil
+}
+
+// parseStatus 解析审计状态名称或数值
+func parseStatus(value string) (admin.AuditStatus, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "success", "1":
+		return admin.AuditStatusSuccess, nil
+	case "failed", "failure", "2":
+		return admin.AuditStatusFailed, nil
+	case "partial", "3":
+		return admin.AuditStatusPartial, nil
+	default:
+		return 0, fmt.Errorf("invalid -status %q (expected success, failed or partial)", value)
+	}
+}
+
+// parseSince 解析相对时长（如 30m）或绝对时间（RFC3339 / 2006-01-02 15:04:05）
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("-since duration must not be negative")
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(auditTimeLayout, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid -since %q (expected duration like 30m or RFC3339 time)", value)
+}
+
+// buildRequest 根据参数构建审计日志查询
+func buildRequest(opts *options, since *time.Time, pageSize int) *ports.ListAuditLogsRequest {
+	req := &ports.ListAuditLogsRequest{
+		Page:        1,
+		PageSize:    pageSize,
+		AdminUserID: opts.adminID,
+		Action:      opts.action,
+		Resource:    opts.resource,
+		Status:      opts.status,
+	}
+	if since != nil {
+		start := since.Local().Format(auditTimeLayout)
+		req.StartTime = &start
+	}
+	return req
+}
+
+// run 输出最近的审计日志，follow 模式下持续轮询新记录
+func run(ctx context.Context, svc ports.AdminAuditService, opts *options, out io.Writer) error {
+	resp, err := svc.ListAuditLogs(ctx, buildRequest(opts, opts.since, opts.limit))
+	if err != nil {
+		return fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	cursor := opts.since
+	var lastID uint
+	lastID, cursor = printLogs(out, opts, resp.Logs, lastID, cursor)
+
+	if !opts.follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			resp, err := svc.ListAuditLogs(ctx, buildRequest(opts, cursor, followPageSize))
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				fmt.Fprintf(os.Stderr, "Failed to poll audit logs: %v\n", err)
+				continue
+			}
+			lastID, cursor = printLogs(out, opts, resp.Logs, lastID, cursor)
+		}
+	}
+}
+
+// printLogs 按时间正序输出 ID 大于 lastID 的记录，返回新的 lastID 与时间游标
+func printLogs(out io.Writer, opts *options, logs []*admin.AdminAuditLog, lastID uint, cursor *time.Time) (uint, *time.Time) {
+	sorted := make([]*admin.AdminAuditLog, 0, len(logs))
+	for _, entry := range logs {
+		if entry != nil && entry.ID > lastID {
+			sorted = append(sorted, entry)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	encoder := json.NewEncoder(out)
+	for _, entry := range sorted {
+		if opts.jsonOutput {
+			encoder.Encode(entry)
+		} else {
+			fmt.Fprintln(out, formatLog(entry, !opts.noColor))
+		}
+		lastID = entry.ID
+		createdAt := entry.CreatedAt
+		cursor = &createdAt
+	}
+	return lastID, cursor
+}
+
+// formatLog 格式化单条审计日志
+func formatLog(entry *admin.AdminAuditLog, color bool) string {
+	paint := func(code, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+
+	resource := entry.Resource
+	if entry.ResourceID != "" {
+		resource += "/" + entry.ResourceID
+	}
+
+	var statusText string
+	switch entry.Status {
+	case admin.AuditStatusSuccess:
+		statusText = paint(colorGreen, "OK     ")
+	case admin.AuditStatusFailed:
+		statusText = paint(colorRed, "FAILED ")
+	case admin.AuditStatusPartial:
+		statusText = paint(colorYellow, "PARTIAL")
+	default:
+		statusText = "UNKNOWN"
+	}
+
+	line := fmt.Sprintf("%s %s admin=%s %-24s %-24s %s %s %s",
+		paint(colorGray, entry.CreatedAt.Local().Format(auditTimeLayout)),
+		statusText,
+		strconv.FormatUint(uint64(entry.AdminUserID), 10),
+		entry.Action,
+		resource,
+		entry.Method,
+		entry.Path,
+		paint(colorGray, fmt.Sprintf("%dms", entry.Duration)),
+	)
+	if entry.ErrorMsg != "" {
+		line += " " + paint(colorRed, entry.ErrorMsg)
+	}
+	return line
+}
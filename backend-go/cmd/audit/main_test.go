@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/ports"
+)
+
+// fakeAuditService 记录查询请求并返回固定日志
+type fakeAuditService struct {
+	ports.AdminAuditService
+	requests []*ports.ListAuditLogsRequest
+	logs     []*admin.AdminAuditLog
+}
+
+func (f *fakeAuditService) ListAuditLogs(ctx context.Context, req *ports.ListAuditLogsRequest) (*ports.ListAuditLogsResponse, error) {
+	f.requests = append(f.requests, req)
+	return &ports.ListAuditLogsResponse{Logs: f.logs, Total: int64(len(f.logs))}, nil
+}
+
+func TestParseOptions(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		check   func(t *testing.T, opts *options)
+	}{
+		{
+			name: "默认参数",
+			args: nil,
+			check: func(t *testing.T, opts *options) {
+				if opts.adminID != nil || opts.status != nil || opts.since != nil {
+					t.Errorf("默认不应设置过滤条件: %+v", opts)
+				}
+				if opts.limit != defaultLimit {
+					t.Errorf("limit = %d, want %d", opts.limit, defaultLimit)
+				}
+			},
+		},
+		{
+			name: "完整过滤条件",
+			args: []string{"-admin", "3", "-action", "update", "-resource", "matches", "-status", "failed", "-since", "90m", "-json"},
+			check: func(t *testing.T, opts *options) {
+				if opts.adminID == nil || *opts.adminID != 3 {
+					t.Errorf("adminID = %v, want 3", opts.adminID)
+				}
+				if opts.status == nil || *opts.status != admin.AuditStatusFailed {
+					t.Errorf("status = %v, want failed", opts.status)
+				}
+				if opts.since == nil || !opts.since.Equal(now.Add(-90*time.Minute)) {
+					t.Errorf("since = %v, want %v", opts.since, now.Add(-90*time.Minute))
+				}
+				if opts.action != "update" || opts.resource != "matches" || !opts.jsonOutput {
+					t.Errorf("解析结果不正确: %+v", opts)
+				}
+			},
+		},
+		{
+			name: "绝对时间",
+			args: []string{"-since", "2026-10-01T08:00:00Z"},
+			check: func(t *testing.T, opts *options) {
+				want := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+				if opts.since == nil || !opts.since.Equal(want) {
+					t.Errorf("since = %v, want %v", opts.since, want)
+				}
+			},
+		},
+		{name: "无效状态", args: []string{"-status", "unknown"}, wantErr: true},
+		{name: "无效时间", args: []string{"-since", "yesterday"}, wantErr: true},
+		{name: "无效数量", args: []string{"-limit", "0"}, wantErr: true},
+		{name: "多余参数", args: []string{"extra"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseOptions(tt.args, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, opts)
+			}
+		})
+	}
+}
+
+func TestRun_BuildsFilteredQuery(t *testing.T) {
+	now := time.Now()
+	opts, err := parseOptions([]string{"-admin", "7", "-action", "delete", "-resource", "users", "-status", "success", "-since", "1h", "-limit", "10", "-json"}, now)
+	if err != nil {
+		t.Fatalf("parseOptions() error = %v", err)
+	}
+
+	svc := &fakeAuditService{logs: []*admin.AdminAuditLog{
+		{ID: 2, AdminUserID: 7, Action: "delete", Resource: "users", Status: admin.AuditStatusSuccess, CreatedAt: now},
+		{ID: 1, AdminUserID: 7, Action: "delete", Resource: "users", Status: admin.AuditStatusSuccess, CreatedAt: now.Add(-time.Minute)},
+	}}
+
+	var out bytes.Buffer
+	if err := run(context.Background(), svc, opts, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	if len(svc.requests) != 1 {
+		t.Fatalf("查询次数 = %d, want 1", len(svc.requests))
+	}
+	req := svc.requests[0]
+	if req.AdminUserID == nil || *req.AdminUserID != 7 {
+		t.Errorf("AdminUserID = %v, want 7", req.AdminUserID)
+	}
+	if req.Action != "delete" || req.Resource != "users" {
+		t.Errorf("Action/Resource = %q/%q", req.Action, req.Resource)
+	}
+	if req.Status == nil || *req.Status != admin.AuditStatusSuccess {
+		t.Errorf("Status = %v, want success", req.Status)
+	}
+	wantStart := now.Add(-time.Hour).Local().Format(auditTimeLayout)
+	if req.StartTime == nil || *req.StartTime != wantStart {
+		t.Errorf("StartTime = %v, want %s", req.StartTime, wantStart)
+	}
+	if req.PageSize != 10 {
+		t.Errorf("PageSize = %d, want 10", req.PageSize)
+	}
+
+	// JSON 输出按时间正序，每行一条
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("输出行数 = %d, want 2", len(lines))
+	}
+	var first admin.AdminAuditLog
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("输出不是合法 JSON: %v", err)
+	}
+	if first.ID != 1 {
+		t.Errorf("首条记录 ID = %d, want 1", first.ID)
+	}
+}
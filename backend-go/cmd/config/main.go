@@ -36,6 +36,10 @@ func main() {
 		checkHealth()
 	case "profile":
 		profileConfig()
+	case "encrypt":
+		encryptValue()
+	case "decrypt":
+		decryptValue()
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		printUsage()
@@ -48,20 +52,27 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  config validate [file]           - Validate configuration file")
-	fmt.Println("  config generate <env>            - Generate configuration template")
+	fmt.Println("  config generate <env> [--format yaml|env] [--redact]")
+	fmt.Println("                                   - Generate configuration template")
 	fmt.Println("  config diff <file1> <file2>      - Compare two configuration files")
-	fmt.Println("  config export <format> [file]    - Export configuration to format (json/yaml)")
+	fmt.Println("  config export <format> [file] [--redact]")
+	fmt.Println("                                   - Export configuration to format (json/yaml/env)")
 	fmt.Println("  config import <file>             - Import configuration from file")
 	fmt.Println("  config template <name> [file]    - Apply configuration template")
 	fmt.Println("  config health [file]             - Check configuration health")
 	fmt.Println("  config profile [file]            - Profile configuration loading")
+	fmt.Println("  config encrypt <field> <value>   - Encrypt a secret value (key from $" + config.SecretKeyEnvVar + ")")
+	fmt.Println("  config decrypt <field> <value>   - Decrypt an encrypted value")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  config validate configs/config.yaml")
 	fmt.Println("  config generate development")
+	fmt.Println("  config generate production --format env")
 	fmt.Println("  config diff config.yaml config.prod.yaml")
 	fmt.Println("  config export json config.json")
+	fmt.Println("  config export env .env --redact")
 	fmt.Println("  config template production config.yaml")
+	fmt.Println("  config encrypt auth.jwt_secret 'my-production-secret'")
 }
 
 func validateConfig() {
@@ -110,9 +121,11 @@ func validateConfig() {
 }
 
 func generateConfig() {
+	redact := takeFlag("--redact")
+	format := takeFlagValue("--format", "yaml")
 	if len(os.Args) < 3 {
 		fmt.Println("Error: Environment is required")
-		fmt.Println("Usage: config generate <environment>")
+		fmt.Println("Usage: config generate <environment> [--format yaml|env] [--redact]")
 		fmt.Println("Environments: development, testing, production")
 		os.Exit(1)
 	}
@@ -146,14 +159,16 @@ func generateConfig() {
 	}
 
 	// 导出配置
-	exporter := config.NewConfigExporter(newCfg)
-	data, err := exporter.ExportToYAML()
+	data, err := exportAs(config.NewConfigExporter(newCfg), format, redact)
 	if err != nil {
 		log.Fatalf("Failed to export config: %v", err)
 	}
 
 	// 生成文件名
-	filename := fmt.Sprintf("config.%s.yaml", envStr)
+	filename := fmt.Sprintf("config.%s.%s", envStr, format)
+	if format == "env" {
+		filename = fmt.Sprintf(".env.%s", envStr)
+	}
 
 	if err := os.WriteFile(filename, data, 0644); err != nil {
 		log.Fatalf("Failed to write config file: %v", err)
@@ -200,10 +215,11 @@ func diffConfigs() {
 }
 
 func exportConfig() {
+	redact := takeFlag("--redact")
 	if len(os.Args) < 3 {
 		fmt.Println("Error: Export format is required")
-		fmt.Println("Usage: config export <format> [output_file]")
-		fmt.Println("Formats: json, yaml")
+		fmt.Println("Usage: config export <format> [output_file] [--redact]")
+		fmt.Println("Formats: json, yaml, env")
 		os.Exit(1)
 	}
 
@@ -219,33 +235,64 @@ func exportConfig() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	exporter := config.NewConfigExporter(cfg)
+	data, err := exportAs(config.NewConfigExporter(cfg), format, redact)
+	if err != nil {
+		log.Fatalf("Failed to export config: %v", err)
+	}
 
 	if outputFile != "" {
 		// 导出到文件
-		if err := exporter.ExportToFile(outputFile); err != nil {
+		if err := os.WriteFile(outputFile, data, 0644); err != nil {
 			log.Fatalf("Failed to export config to file: %v", err)
 		}
 		fmt.Printf("✅ Configuration exported to: %s\n", outputFile)
 	} else {
 		// 输出到标准输出
-		var data []byte
-		switch format {
-		case "json":
-			data, err = exporter.ExportToJSON()
-		case "yaml", "yml":
-			data, err = exporter.ExportToYAML()
-		default:
-			fmt.Printf("Error: Unsupported format '%s'\n", format)
-			os.Exit(1)
-		}
+		fmt.Println(string(data))
+	}
+}
 
-		if err != nil {
-			log.Fatalf("Failed to export config: %v", err)
+// exportAs 按格式导出配置，redact 仅对 env 格式生效
+func exportAs(exporter *config.ConfigExporter, format string, redact bool) ([]byte, error) {
+	switch format {
+	case "json":
+		return exporter.ExportToJSON()
+	case "yaml", "yml":
+		return exporter.ExportToYAML()
+	case "env":
+		return exporter.ExportToEnv(redact)
+	default:
+		fmt.Printf("Error: Unsupported format '%s'\n", format)
+		os.Exit(1)
+		return nil, nil
+	}
+}
+
+// takeFlag 从命令行参数中移除布尔标志，返回是否出现
+func takeFlag(name string) bool {
+	for i, arg := range os.Args {
+		if arg == name {
+			os.Args = append(os.Args[:i], os.Args[i+1:]...)
+			return true
 		}
+	}
+	return false
+}
 
-		fmt.Println(string(data))
+// takeFlagValue 从命令行参数中移除 "--name value" 或 "--name=value" 并返回值
+func takeFlagValue(name, defaultValue string) string {
+	for i, arg := range os.Args {
+		if arg == name && i+1 < len(os.Args) {
+			value := os.Args[i+1]
+			os.Args = append(os.Args[:i], os.Args[i+2:]...)
+			return strings.ToLower(value)
+		}
+		if strings.HasPrefix(arg, name+"=") {
+			os.Args = append(os.Args[:i], os.Args[i+1:]...)
+			return strings.ToLower(strings.TrimPrefix(arg, name+"="))
+		}
 	}
+	return defaultValue
 }
 
 func importConfig() {
@@ -414,3 +461,54 @@ func profileConfig() {
 	statsJSON, _ := json.MarshalIndent(stats, "", "  ")
 	fmt.Println(string(statsJSON))
 }
+
+func encryptValue() {
+	if len(os.Args) < 4 {
+		fmt.Println("Error: Field and value are required")
+		fmt.Println("Usage: config encrypt <field> <value>")
+		fmt.Println("Example: config encrypt auth.jwt_secret 'my-production-secret'")
+		os.Exit(1)
+	}
+
+	field, value := os.Args[2], os.Args[3]
+
+	key, err := config.SecretKeyProvider()
+	if err != nil {
+		log.Fatalf("Failed to get encryption key: %v", err)
+	}
+
+	encrypted, err := config.EncryptValue(field, value, key)
+	if err != nil {
+		log.Fatalf("Failed to encrypt value: %v", err)
+	}
+
+	// 输出可直接粘贴到配置文件的内容
+	parts := strings.Split(field, ".")
+	fmt.Printf("%s: \"%s\"\n", parts[len(parts)-1], encrypted)
+}
+
+func decryptValue() {
+	if len(os.Args) < 4 {
+		fmt.Println("Error: Field and encrypted value are required")
+		fmt.Println("Usage: config decrypt <field> <value>")
+		os.Exit(1)
+	}
+
+	field, value := os.Args[2], os.Args[3]
+	if !config.IsEncryptedValue(value) {
+		fmt.Printf("Error: Value is not encrypted (expected %s prefix)\n", config.EncryptedValuePrefix)
+		os.Exit(1)
+	}
+
+	key, err := config.SecretKeyProvider()
+	if err != nil {
+		log.Fatalf("Failed to get encryption key: %v", err)
+	}
+
+	plaintext, err := config.DecryptValue(field, value, key)
+	if err != nil {
+		log.Fatalf("Failed to decrypt value: %v", err)
+	}
+
+	fmt.Println(plaintext)
+}
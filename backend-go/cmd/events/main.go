@@ -0,0 +1,217 @@
+// Package main provides a command-line tool for inspecting and replaying
+// dead-lettered events (event records whose processing failed).
+//
+// Usage:
+//
+//	events list -type vote.cast
+//	events replay -type vote.cast -dry-run
+//	events replay -ids vote.cast_1,vote.cast_2
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend-go/internal/adapters/events"
+	"backend-go/internal/adapters/events/handlers"
+	"backend-go/internal/adapters/events/persistence"
+	"backend-go/internal/adapters/events/replay"
+	"backend-go/internal/config"
+	"backend-go/internal/container"
+	"backend-go/internal/shared/logger"
+)
+
+const defaultLimit = 100
+
+// options 命令行参数
+type options struct {
+	command    string
+	configPath string
+	eventType  string
+	eventIDs   []string
+	limit      int
+	dryRun     bool
+	jsonOutput bool
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+
+	var cfg *config.Config
+	if opts.configPath != "" {
+		cfg, err = config.LoadFromFile(opts.configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	logger.Init(cfg.Log.Level)
+
+	cont, err := container.NewContainer(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize container: %v\n", err)
+		os.Exit(1)
+	}
+	defer cont.Close()
+
+	store := persistence.NewMySQLEventStore(cont.GetDB(), logger.GetLogger())
+	bus := events.NewReplayBus(cont.GetRedisClient(), nil, handlers.PageViewConfig{TrackedPaths: cfg.Analytics.PageViews.TrackedPaths}, logger.GetLogger())
+	replayer := replay.NewDeadLetterReplayer(store, bus, events.DecodeReplayPayload, logger.GetLogger())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, replayer, opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage(out io.Writer) {
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  events list   [-type T] [-limit N] [-json]")
+	fmt.Fprintln(out, "  events replay [-type T] [-ids id1,id2] [-limit N] [-dry-run] [-json]")
+}
+
+// parseOptions 解析子命令与参数
+func parseOptions(args []string) (*options, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+
+	opts := &options{command: args[0]}
+	if opts.command != "list" && opts.command != "replay" {
+		return nil, fmt.Errorf("unknown command %q", opts.command)
+	}
+
+	fs := flag.NewFlagSet("events "+opts.command, flag.ContinueOnError)
+	var (
+		configPath = fs.String("config", "", "Path to configuration file (default: environment based)")
+		eventType  = fs.String("type", "", "Filter by event type (e.g. vote.cast)")
+		limit      = fs.Int("limit", defaultLimit, "Maximum number of dead letters to load (ignored with -ids)")
+		jsonOutput = fs.Bool("json", false, "Output JSON")
+		ids        *string
+		dryRun     *bool
+	)
+	if opts.command == "replay" {
+		ids = fs.String("ids", "", "Comma-separated event IDs to replay (default: all matching)")
+		dryRun = fs.Bool("dry-run", false, "Show what would be replayed without dispatching")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *limit <= 0 {
+		return nil, fmt.Errorf("-limit must be positive")
+	}
+
+	opts.configPath = *configPath
+	opts.eventType = strings.TrimSpace(*eventType)
+	opts.limit = *limit
+	opts.jsonOutput = *jsonOutput
+	if ids != nil {
+		for _, id := range strings.Split(*ids, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				opts.eventIDs = append(opts.eventIDs, id)
+			}
+		}
+	}
+	if dryRun != nil {
+		opts.dryRun = *dryRun
+	}
+
+	return opts, nil
+}
+
+// run 执行子命令
+func run(ctx context.Context, replayer *replay.DeadLetterReplayer, opts *options, out io.Writer) error {
+	if opts.command == "list" {
+		entries, err := replayer.List(ctx, opts.eventType, opts.limit)
+		if err != nil {
+			return fmt.Errorf("failed to list dead letters: %w", err)
+		}
+		if opts.jsonOutput {
+			return json.NewEncoder(out).Encode(entries)
+		}
+		printEntries(out, entries)
+		fmt.Fprintf(out, "%d dead-lettered event(s)\n", len(entries))
+		return nil
+	}
+
+	result, err := replayer.Replay(ctx, replay.DeadLetterOptions{
+		EventType: opts.eventType,
+		EventIDs:  opts.eventIDs,
+		Limit:     opts.limit,
+		DryRun:    opts.dryRun,
+	})
+	if err != nil && result == nil {
+		return fmt.Errorf("failed to replay dead letters: %w", err)
+	}
+
+	if opts.jsonOutput {
+		if encErr := json.NewEncoder(out).Encode(result); encErr != nil {
+			return encErr
+		}
+	} else {
+		printResult(out, result)
+	}
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d event(s) failed to replay", len(result.Failed))
+	}
+	return nil
+}
+
+// printEntries 输出死信列表
+func printEntries(out io.Writer, entries []replay.DeadLetterEntry) {
+	for _, entry := range entries {
+		fmt.Fprintf(out, "%s  %-20s retries=%d  %s  %s\n",
+			entry.CreatedAt.Local().Format(time.DateTime),
+			entry.EventType,
+			entry.RetryCount,
+			entry.EventID,
+			entry.ErrorMessage,
+		)
+	}
+}
+
+// printResult 输出重放结果
+func printResult(out io.Writer, result *replay.DeadLetterResult) {
+	if result.DryRun {
+		fmt.Fprintln(out, "Dry run: the following events would be replayed")
+		printEntries(out, result.Selected)
+		fmt.Fprintf(out, "%d event(s) selected\n", len(result.Selected))
+		return
+	}
+
+	for _, id := range result.Replayed {
+		fmt.Fprintf(out, "REPLAYED %s\n", id)
+	}
+	for id, msg := range result.Failed {
+		fmt.Fprintf(out, "FAILED   %s: %s\n", id, msg)
+	}
+	fmt.Fprintf(out, "%d replayed, %d failed, %d selected\n", len(result.Replayed), len(result.Failed), len(result.Selected))
+}
@@ -12,6 +12,10 @@ import (
 	"path/filepath"
 	"time"
 
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
 	"backend-go/internal/adapters/persistence/mysql"
 	"backend-go/internal/config"
 	"backend-go/internal/core/services"
@@ -28,12 +32,16 @@ const (
 func main() {
 	var (
 		configPath    = flag.String("config", "config.development.yaml", "Path to configuration file")
-		command       = flag.String("command", "up", "Migration command: up, down, status, validate, seed, auto")
+		command       = flag.String("command", "up", "Migration command: up, down, status, validate, seed, auto, import")
 		migrationsDir = flag.String("migrations", defaultMigrationsDir, "Path to migrations directory")
 		seedDataDir   = flag.String("seed", defaultSeedDataDir, "Path to seed data directory")
 		timeout       = flag.Duration("timeout", defaultTimeout, "Operation timeout")
 		force         = flag.Bool("force", false, "Force operation (use with caution)")
 		verbose       = flag.Bool("verbose", false, "Enable verbose logging")
+		sqliteFile    = flag.String("sqlite", "", "Path to legacy SQLite database (import command)")
+		batchSize     = flag.Int("batch-size", 500, "Rows per insert batch (import command)")
+		preserveIDs   = flag.Bool("preserve-ids", true, "Keep legacy IDs where free, remapping conflicting ones (import command)")
+		rejectLate    = flag.Bool("reject-late-predictions", false, "Skip predictions created after their match started instead of only reporting them (import command)")
 	)
 	flag.Parse()
 
@@ -63,6 +71,8 @@ func main() {
 	// Create migration repository and service
 	migrationRepo := mysql.NewMigrationRepository(db)
 	migrationService := services.NewMigrationService(db, migrationRepo)
+	migrationService.SetRequireDownMigrations(cfg.Database.Migration.RequireDownMigrations)
+	migrationService.SetSkipAutoMigrate(cfg.Database.Migration.SkipAutoMigrate)
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
@@ -70,7 +80,7 @@ func main() {
 
 	// Initialize migration system
 	if err := migrationService.InitializeMigrationSystem(ctx); err != nil {
-		log.Fatal("Failed to initialize migration system: %v", err)
+		log.Fatalf("Failed to initialize migration system: %v", err)
 	}
 
 	// Execute command
@@ -103,8 +113,13 @@ func main() {
 		if err := initializeMigrationStructure(*migrationsDir, *seedDataDir); err != nil {
 			log.Fatalf("Failed to initialize migration structure: %v", err)
 		}
+	case "import":
+		importConfig := services.LegacyImportConfig{BatchSize: *batchSize, PreserveIDs: *preserveIDs, RejectLatePredictions: *rejectLate}
+		if err := runLegacyImport(ctx, db, *sqliteFile, importConfig); err != nil {
+			log.Fatalf("Legacy import failed: %v", err)
+		}
 	default:
-		log.Fatalf("Unknown command: %s. Available commands: up, down, status, validate, seed, auto, init", *command)
+		log.Fatalf("Unknown command: %s. Available commands: up, down, status, validate, seed, auto, init, import", *command)
 	}
 
 	log.Info("Migration tool completed successfully")
@@ -114,14 +129,9 @@ func runUpMigrations(ctx context.Context, service *services.MigrationService, mi
 	log := logger.GetLogger()
 	log.Info("Running up migrations...")
 
-	// First run auto-migration to ensure GORM models are up to date
-	if err := service.AutoMigrate(ctx); err != nil {
-		return fmt.Errorf("auto-migration failed: %w", err)
-	}
-
-	// Then run manual migrations
-	if err := service.RunMigrations(ctx, migrationsDir); err != nil {
-		return fmt.Errorf("manual migrations failed: %w", err)
+	// Auto-migration (unless skipped), schema pre-flight check, then manual migrations
+	if err := service.RunUpMigrations(ctx, migrationsDir); err != nil {
+		return err
 	}
 
 	log.Info("All migrations completed successfully")
@@ -190,6 +200,54 @@ func runAutoMigration(ctx context.Context, service *services.MigrationService) e
 	return service.AutoMigrate(ctx)
 }
 
+func runLegacyImport(ctx context.Context, db *database.DB, sqliteFile string, config services.LegacyImportConfig) error {
+	log := logger.GetLogger()
+
+	if sqliteFile == "" {
+		return fmt.Errorf("-sqlite is required for import")
+	}
+	if _, err := os.Stat(sqliteFile); err != nil {
+		return fmt.Errorf("legacy SQLite file not found: %w", err)
+	}
+
+	source, err := gorm.Open(sqlite.Open(sqliteFile), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		return fmt.Errorf("failed to open legacy SQLite database: %w", err)
+	}
+
+	log.Infof("Importing legacy data from %s...", sqliteFile)
+	report, err := services.NewLegacyImporter(source, db.DB, config, log).Import(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== Legacy Import ===")
+	for _, table := range report.Tables {
+		if table.Missing {
+			fmt.Printf("%-26s missing in source\n", table.Table)
+			continue
+		}
+		fmt.Printf("%-26s read %d, imported %d, remapped %d, skipped %d\n",
+			table.Table, table.Read, table.Imported, table.Remapped, table.Skipped)
+	}
+	for _, row := range report.Skipped {
+		fmt.Printf("Skipped %s #%d: %s\n", row.Table, row.ID, row.Reason)
+	}
+	if len(report.TimingViolations) > 0 {
+		fmt.Printf("\n%d predictions were created after their match started:\n", len(report.TimingViolations))
+		for _, v := range report.TimingViolations {
+			action := "imported"
+			if v.Rejected {
+				action = "rejected"
+			}
+			fmt.Printf("  prediction #%d on match #%d: created %s, %v after start (%s)\n",
+				v.PredictionID, v.MatchID, v.CreatedAt.Format(time.RFC3339), v.Late, action)
+		}
+	}
+	fmt.Printf("Duration: %v\n", report.Duration)
+	return nil
+}
+
 func initializeMigrationStructure(migrationsDir, seedDataDir string) error {
 	log := logger.GetLogger()
 	log.Info("Initializing migration directory structure...")
@@ -310,12 +368,12 @@ go run cmd/migrate/main.go -command=auto
 
 	log.Info("Migration structure initialized successfully")
 	log.Info("Created directories:")
-	log.Info("  - %s (migrations)", migrationsDir)
-	log.Info("  - %s (seed data)", seedDataDir)
+	log.Infof("  - %s (migrations)", migrationsDir)
+	log.Infof("  - %s (seed data)", seedDataDir)
 	log.Info("Created example files:")
-	log.Info("  - %s", upFile)
-	log.Info("  - %s", downFile)
-	log.Info("  - %s", readmeFile)
+	log.Infof("  - %s", upFile)
+	log.Infof("  - %s", downFile)
+	log.Infof("  - %s", readmeFile)
 
 	return nil
 }
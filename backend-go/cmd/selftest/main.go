@@ -0,0 +1,233 @@
+// Package main provides a startup self-test command for deploy pipelines.
+//
+// It loads the configuration, connects to the database and Redis, runs the
+// health checks, verifies that no migrations are pending and that the JWT
+// secret meets the policy for the current environment. Any failed check makes
+// the command exit with status 1.
+//
+// Usage:
+//
+//	selftest -config configs/config.yaml -migrations migrations
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"backend-go/internal/adapters/persistence/mysql"
+	"backend-go/internal/config"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/database"
+	"backend-go/pkg/middleware"
+	"backend-go/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMigrationsDir = "migrations"
+	defaultTimeout       = 30 * time.Second
+)
+
+// options 命令行参数
+type options struct {
+	configPath    string
+	migrationsDir string
+	timeout       time.Duration
+}
+
+// healthChecker 执行健康检查
+type healthChecker interface {
+	Check(ctx context.Context) middleware.HealthResponse
+}
+
+// pendingMigrationLister 列出未执行的迁移
+type pendingMigrationLister interface {
+	GetPendingMigrationFiles(ctx context.Context, migrationsDir string) ([]services.MigrationFile, error)
+}
+
+// selfTest 自检所需的依赖，连接失败时对应的错误非空
+type selfTest struct {
+	cfg           *config.Config
+	env           config.Environment
+	dbErr         error
+	redisErr      error
+	health        healthChecker
+	migrations    pendingMigrationLister
+	migrationsDir string
+}
+
+// check 单项检查
+type check struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var cfg *config.Config
+	if opts.configPath != "" {
+		cfg, err = config.LoadFromFile(opts.configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "[FAIL] config: %v\n\nSelf-test FAILED\n", err)
+		os.Exit(1)
+	}
+
+	// 自检只输出汇总结果，依赖组件的日志仅保留错误
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	test := &selfTest{
+		cfg:           cfg,
+		env:           config.GetEnvironment(),
+		migrationsDir: opts.migrationsDir,
+	}
+	health := middleware.NewHealthService("selftest", cfg.External.Monitoring.HealthCheck.Timeout)
+	health.AddChecker(middleware.NewMemoryHealthChecker(cfg.External.Monitoring.HealthCheck.MaxMemoryMB))
+
+	db, err := database.NewDB(&cfg.Database)
+	if err != nil {
+		test.dbErr = err
+	} else {
+		defer db.Close()
+		health.AddChecker(middleware.NewDatabaseHealthChecker(db.DB, 5*time.Second))
+		test.migrations = services.NewMigrationService(db, mysql.NewMigrationRepository(db))
+	}
+
+	redisClient, err := redis.NewClient(&cfg.Redis, log)
+	if err != nil {
+		test.redisErr = err
+	} else {
+		defer redisClient.Close()
+		health.AddChecker(middleware.NewRedisHealthChecker(redisClient.GetRedisClient(), 5*time.Second))
+	}
+	test.health = health
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	if !test.run(ctx, os.Stdout) {
+		os.Exit(1)
+	}
+}
+
+// parseOptions 解析命令行参数
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("selftest", flag.ContinueOnError)
+
+	var (
+		configPath    = fs.String("config", "", "Path to configuration file (default: environment based)")
+		migrationsDir = fs.String("migrations", defaultMigrationsDir, "Path to migrations directory")
+		timeout       = fs.Duration("timeout", defaultTimeout, "Overall timeout for all checks")
+	)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *timeout <= 0 {
+		return nil, fmt.Errorf("-timeout must be positive")
+	}
+
+	return &options{
+		configPath:    *configPath,
+		migrationsDir: *migrationsDir,
+		timeout:       *timeout,
+	}, nil
+}
+
+// checks 按顺序返回全部检查项
+func (t *selfTest) checks() []check {
+	return []check{
+		{name: "jwt_secret", run: func(ctx context.Context) error {
+			return config.ValidateJWTSecretPolicy(t.env, t.cfg.Auth.JWTSecret)
+		}},
+		{name: "database", run: func(ctx context.Context) error { return t.dbErr }},
+		{name: "redis", run: func(ctx context.Context) error { return t.redisErr }},
+		{name: "health", run: t.checkHealth},
+		{name: "migrations", run: t.checkMigrations},
+	}
+}
+
+// run 执行全部检查并输出结果，返回是否全部通过
+func (t *selfTest) run(ctx context.Context, out io.Writer) bool {
+	checks := t.checks()
+	failed := 0
+
+	fmt.Fprintf(out, "[PASS] config: environment %s\n", t.env)
+	for _, c := range checks {
+		start := time.Now()
+		err := c.run(ctx)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "[FAIL] %s: %v (%s)\n", c.name, err, elapsed)
+			continue
+		}
+		fmt.Fprintf(out, "[PASS] %s (%s)\n", c.name, elapsed)
+	}
+
+	total := len(checks) + 1
+	if failed > 0 {
+		fmt.Fprintf(out, "\nSelf-test FAILED: %d of %d checks failed\n", failed, total)
+		return false
+	}
+	fmt.Fprintf(out, "\nSelf-test passed: %d checks\n", total)
+	return true
+}
+
+// checkHealth 执行健康检查，任一组件不健康即失败
+func (t *selfTest) checkHealth(ctx context.Context) error {
+	result := t.health.Check(ctx)
+
+	var unhealthy []string
+	for name, component := range result.Components {
+		if component.Status == middleware.HealthStatusUnhealthy {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", name, component.Message))
+		}
+	}
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return fmt.Errorf("unhealthy components: %s", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
+
+// checkMigrations 检查是否存在未执行的迁移
+func (t *selfTest) checkMigrations(ctx context.Context) error {
+	if t.migrations == nil {
+		return fmt.Errorf("database unavailable")
+	}
+
+	pending, err := t.migrations.GetPendingMigrationFiles(ctx, t.migrationsDir)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for _, file := range pending {
+			names = append(names, file.Version+"_"+file.Name)
+		}
+		return fmt.Errorf("%d pending migration(s): %s", len(pending), strings.Join(names, ", "))
+	}
+	return nil
+}
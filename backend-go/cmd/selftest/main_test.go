@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"backend-go/internal/config"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/middleware"
+)
+
+// fakeHealth 返回固定的组件状态
+type fakeHealth struct {
+	components map[string]middleware.ComponentHealth
+}
+
+func (f *fakeHealth) Check(ctx context.Context) middleware.HealthResponse {
+	return middleware.HealthResponse{Components: f.components}
+}
+
+// fakeMigrations 返回固定的未执行迁移
+type fakeMigrations struct {
+	pending []services.MigrationFile
+	err     error
+}
+
+func (f *fakeMigrations) GetPendingMigrationFiles(ctx context.Context, migrationsDir string) ([]services.MigrationFile, error) {
+	return f.pending, f.err
+}
+
+func newTestSelfTest() *selfTest {
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "production-secret-with-at-least-32-characters"
+
+	return &selfTest{
+		cfg: cfg,
+		env: config.EnvProduction,
+		health: &fakeHealth{components: map[string]middleware.ComponentHealth{
+			"database": {Status: middleware.HealthStatusHealthy},
+			"redis":    {Status: middleware.HealthStatusHealthy},
+			"memory":   {Status: middleware.HealthStatusDegraded},
+		}},
+		migrations:    &fakeMigrations{},
+		migrationsDir: "migrations",
+	}
+}
+
+func TestSelfTest_AllChecksPass(t *testing.T) {
+	var out bytes.Buffer
+	if !newTestSelfTest().run(context.Background(), &out) {
+		t.Fatalf("全部检查通过时应返回 true:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "[FAIL]") || !strings.Contains(out.String(), "Self-test passed: 6 checks") {
+		t.Errorf("输出应只包含通过项:\n%s", out.String())
+	}
+}
+
+func TestSelfTest_FailsOnPendingMigrations(t *testing.T) {
+	test := newTestSelfTest()
+	test.migrations = &fakeMigrations{pending: []services.MigrationFile{
+		{Version: "20251014000002", Name: "create_notification_preferences"},
+	}}
+
+	var out bytes.Buffer
+	if test.run(context.Background(), &out) {
+		t.Fatal("存在未执行迁移时应失败")
+	}
+	if !strings.Contains(out.String(), "[FAIL] migrations: 1 pending migration(s): 20251014000002_create_notification_preferences") {
+		t.Errorf("输出应列出未执行的迁移:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Self-test FAILED: 1 of 6 checks failed") {
+		t.Errorf("汇总不正确:\n%s", out.String())
+	}
+}
+
+func TestSelfTest_ReportsEachFailure(t *testing.T) {
+	test := newTestSelfTest()
+	test.cfg.Auth.JWTSecret = "short"
+	test.redisErr = errors.New("connection refused")
+	test.health = &fakeHealth{components: map[string]middleware.ComponentHealth{
+		"redis": {Status: middleware.HealthStatusUnhealthy, Message: "ping failed"},
+	}}
+
+	var out bytes.Buffer
+	if test.run(context.Background(), &out) {
+		t.Fatal("检查失败时应返回 false")
+	}
+	for _, want := range []string{
+		"[FAIL] jwt_secret: JWT secret must be at least 32 characters in production",
+		"[FAIL] redis: connection refused",
+		"[FAIL] health: unhealthy components: redis (ping failed)",
+		"[PASS] migrations",
+		"Self-test FAILED: 3 of 6 checks failed",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("输出缺少 %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := parseOptions([]string{"-config", "configs/config.yaml", "-migrations", "db/migrations"})
+	if err != nil {
+		t.Fatalf("parseOptions() error = %v", err)
+	}
+	if opts.configPath != "configs/config.yaml" || opts.migrationsDir != "db/migrations" || opts.timeout != defaultTimeout {
+		t.Errorf("解析结果不正确: %+v", opts)
+	}
+
+	if _, err := parseOptions([]string{"extra"}); err == nil {
+		t.Error("多余参数应返回错误")
+	}
+}
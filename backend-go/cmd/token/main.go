@@ -0,0 +1,176 @@
+// Package main provides a command-line tool for minting JWT access tokens.
+//
+// Tokens are signed with the configured auth.jwt_secret, so they are accepted
+// by the API running with the same configuration. It is intended for testing
+// protected endpoints and bootstrapping the first admin, and refuses to run
+// in production unless -allow-production is given.
+//
+// Usage:
+//
+//	token -user-id 1 -username alice
+//	token -user-id 1 -username admin -role admin -ttl 15m
+//	token -user-id 1 -json | jq -r .token
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/shared/jwt"
+)
+
+// options 命令行参数
+type options struct {
+	configPath      string
+	userID          uint
+	username        string
+	role            string
+	ttl             time.Duration // 0 表示使用 auth.jwt_expiration_hours
+	jsonOutput      bool
+	allowProduction bool
+}
+
+// tokenOutput -json 输出格式
+type tokenOutput struct {
+	Token     string    `json:"token"`
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if err := checkEnvironment(config.GetEnvironment(), opts.allowProduction); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var cfg *config.Config
+	if opts.configPath != "" {
+		cfg, err = config.LoadFromFile(opts.configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := run(cfg.Auth, opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseOptions 解析命令行参数
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("token", flag.ContinueOnError)
+
+	var (
+		configPath      = fs.String("config", "", "Path to configuration file (default: environment based)")
+		userID          = fs.Uint("user-id", 0, "User ID to issue the token for (required)")
+		username        = fs.String("username", "", "Username claim, also used as the token subject")
+		role            = fs.String("role", string(user.UserRoleUser), "Role claim: user or admin")
+		ttl             = fs.Duration("ttl", 0, "Token lifetime (default: auth.jwt_expiration_hours)")
+		jsonOutput      = fs.Bool("json", false, "Output the token and its claims as JSON")
+		allowProduction = fs.Bool("allow-production", false, "Allow minting tokens when GO_ENV is production")
+	)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *userID == 0 {
+		return nil, fmt.Errorf("-user-id is required")
+	}
+	if *role != string(user.UserRoleUser) && *role != string(user.UserRoleAdmin) {
+		return nil, fmt.Errorf("-role must be %q or %q", user.UserRoleUser, user.UserRoleAdmin)
+	}
+	if *ttl < 0 {
+		return nil, fmt.Errorf("-ttl must not be negative")
+	}
+
+	return &options{
+		configPath:      *configPath,
+		userID:          *userID,
+		username:        *username,
+		role:            *role,
+		ttl:             *ttl,
+		jsonOutput:      *jsonOutput,
+		allowProduction: *allowProduction,
+	}, nil
+}
+
+// checkEnvironment 生产环境下需显式指定 -allow-production 才允许签发
+func checkEnvironment(env config.Environment, allowProduction bool) error {
+	if env.IsProduction() && !allowProduction {
+		return fmt.Errorf("refusing to mint tokens in production without -allow-production")
+	}
+	return nil
+}
+
+// newJWTService 按认证配置创建 JWT 服务，访问令牌有效期使用 ttl
+func newJWTService(auth config.AuthConfig, ttl time.Duration) jwt.JWTService {
+	previousKeys := make([]jwt.Key, 0, len(auth.JWTPreviousKeys))
+	for _, key := range auth.JWTPreviousKeys {
+		previousKeys = append(previousKeys, jwt.Key{ID: key.ID, Secret: key.Secret})
+	}
+	return jwt.NewJWTService(jwt.Config{
+		SecretKey:       auth.JWTSecret,
+		KeyID:           auth.JWTKeyID,
+		PreviousKeys:    previousKeys,
+		AccessTokenTTL:  ttl,
+		RefreshTokenTTL: time.Duration(auth.RefreshTokenExpDays) * 24 * time.Hour,
+		Issuer:          auth.JWTIssuer,
+	})
+}
+
+// run 签发访问令牌并输出
+func run(auth config.AuthConfig, opts *options, out io.Writer) error {
+	ttl := opts.ttl
+	if ttl == 0 {
+		ttl = time.Duration(auth.JWTExpirationHours) * time.Hour
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("token TTL must be positive, set -ttl or auth.jwt_expiration_hours")
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	token, err := newJWTService(auth, ttl).GenerateAccessToken(opts.userID, opts.username, opts.role)
+	if err != nil {
+		return fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	if !opts.jsonOutput {
+		_, err = fmt.Fprintln(out, token)
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(tokenOutput{
+		Token:     token,
+		UserID:    opts.userID,
+		Username:  opts.username,
+		Role:      opts.role,
+		ExpiresAt: expiresAt.UTC().Truncate(time.Second),
+	})
+}
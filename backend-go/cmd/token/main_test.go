@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/shared/jwt"
+)
+
+func testAuthConfig() config.AuthConfig {
+	return config.AuthConfig{
+		JWTSecret:           "token-cli-test-secret-with-32-plus-characters",
+		JWTKeyID:            "k1",
+		JWTExpirationHours:  24,
+		RefreshTokenExpDays: 7,
+		JWTIssuer:           "prediction-system",
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "minimal", args: []string{"-user-id", "1"}},
+		{name: "missing user id", args: []string{"-username", "alice"}, wantErr: "-user-id is required"},
+		{name: "invalid role", args: []string{"-user-id", "1", "-role", "root"}, wantErr: "-role must be"},
+		{name: "negative ttl", args: []string{"-user-id", "1", "-ttl", "-1m"}, wantErr: "-ttl must not be negative"},
+		{name: "extra args", args: []string{"-user-id", "1", "extra"}, wantErr: "unexpected arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseOptions(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parseOptions() error = %v", err)
+				}
+				if opts.role != "user" || opts.ttl != 0 {
+					t.Errorf("默认值不正确: %+v", opts)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseOptions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckEnvironment(t *testing.T) {
+	if err := checkEnvironment(config.EnvProduction, false); err == nil {
+		t.Error("生产环境未显式允许时应拒绝签发")
+	}
+	if err := checkEnvironment(config.EnvProduction, true); err != nil {
+		t.Errorf("显式允许后不应拒绝: %v", err)
+	}
+	if err := checkEnvironment(config.EnvDevelopment, false); err != nil {
+		t.Errorf("开发环境不应拒绝: %v", err)
+	}
+}
+
+func TestRun_TokenValidatesWithConfiguredSecret(t *testing.T) {
+	auth := testAuthConfig()
+	opts, err := parseOptions([]string{"-user-id", "42", "-username", "admin", "-role", "admin", "-ttl", "15m"})
+	if err != nil {
+		t.Fatalf("parseOptions() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	before := time.Now()
+	if err := run(auth, opts, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// 与 API 相同方式构建的服务应能验证该令牌
+	claims, err := newJWTService(auth, time.Hour).ValidateToken(strings.TrimSpace(out.String()))
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if claims.UserID != 42 || claims.Username != "admin" || claims.Role != "admin" || claims.Type != "access" {
+		t.Errorf("claims = %+v, want user 42 admin access token", claims)
+	}
+	if claims.Issuer != auth.JWTIssuer || claims.Subject != "admin" {
+		t.Errorf("issuer/subject = %s/%s", claims.Issuer, claims.Subject)
+	}
+	if ttl := claims.ExpiresAt.Sub(before); ttl < 14*time.Minute || ttl > 16*time.Minute {
+		t.Errorf("令牌有效期 = %s, want 约 15m", ttl)
+	}
+
+	// 其他密钥签名的服务不应接受
+	other := auth
+	other.JWTSecret = "another-secret-that-is-also-32-characters-long"
+	other.JWTKeyID = ""
+	if _, err := newJWTService(other, time.Hour).ValidateToken(strings.TrimSpace(out.String())); err == nil {
+		t.Error("不同密钥不应验证通过")
+	}
+}
+
+func TestRun_DefaultsToConfiguredExpirationAndJSON(t *testing.T) {
+	auth := testAuthConfig()
+	opts, err := parseOptions([]string{"-user-id", "7", "-username", "alice", "-json"})
+	if err != nil {
+		t.Fatalf("parseOptions() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	before := time.Now()
+	if err := run(auth, opts, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	var result tokenOutput
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("解析 JSON 输出失败: %v", err)
+	}
+	if result.UserID != 7 || result.Username != "alice" || result.Role != "user" {
+		t.Errorf("output = %+v", result)
+	}
+
+	claims, err := jwt.NewJWTService(jwt.Config{SecretKey: auth.JWTSecret, KeyID: auth.JWTKeyID}).ValidateToken(result.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if ttl := claims.ExpiresAt.Sub(before); ttl < 23*time.Hour || ttl > 25*time.Hour {
+		t.Errorf("默认有效期 = %s, want jwt_expiration_hours (24h)", ttl)
+	}
+	if diff := claims.ExpiresAt.Time.Sub(result.ExpiresAt); diff < -time.Second || diff > time.Second {
+		t.Errorf("expires_at = %s, want %s", result.ExpiresAt, claims.ExpiresAt.Time)
+	}
+}
@@ -0,0 +1,154 @@
+// Package main provides a command-line tool for auditing Redis stat key TTLs.
+//
+// It scans stats:* and metrics:* keys and reports the keys that should expire
+// according to their key pattern but have no TTL. With -repair the expected
+// TTL is set on each of them.
+//
+// Usage:
+//
+//	ttl-audit
+//	ttl-audit -repair
+//	ttl-audit -json | jq .
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultTimeout = 5 * time.Minute
+
+// options 命令行参数
+type options struct {
+	configPath string
+	repair     bool
+	jsonOutput bool
+	timeout    time.Duration
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var cfg *config.Config
+	if opts.configPath != "" {
+		cfg, err = config.LoadFromFile(opts.configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	client, err := redis.NewClient(&cfg.Redis, log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to connect to Redis: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	auditor := services.NewRedisTTLAuditor(redis.NewCacheService(client), nil, log)
+	var report *services.TTLAuditReport
+	if opts.repair {
+		report, err = auditor.Repair(ctx)
+	} else {
+		report, err = auditor.Audit(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeReport(os.Stdout, report, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseOptions 解析命令行参数
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("ttl-audit", flag.ContinueOnError)
+
+	var (
+		configPath = fs.String("config", "", "Path to configuration file (default: environment based)")
+		repair     = fs.Bool("repair", false, "Set the expected TTL on keys that have none")
+		jsonOutput = fs.Bool("json", false, "Output the report as JSON")
+		timeout    = fs.Duration("timeout", defaultTimeout, "Overall timeout for the scan")
+	)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if *timeout <= 0 {
+		return nil, fmt.Errorf("-timeout must be positive")
+	}
+
+	return &options{
+		configPath: *configPath,
+		repair:     *repair,
+		jsonOutput: *jsonOutput,
+		timeout:    *timeout,
+	}, nil
+}
+
+// writeReport 输出审计结果
+func writeReport(out io.Writer, report *services.TTLAuditReport, opts *options) error {
+	if opts.jsonOutput {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	for _, key := range report.Missing {
+		status := "MISSING"
+		switch {
+		case key.Error != "":
+			status = "FAILED"
+		case key.Repaired:
+			status = "REPAIRED"
+		}
+		line := fmt.Sprintf("[%s] %s (pattern %s, expected TTL %s)", status, key.Key, key.Pattern, key.ExpectedTTL)
+		if key.Error != "" {
+			line += ": " + key.Error
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintf(out, "\nScanned %d keys, %d with TTL rules, %d without TTL", report.KeysScanned, report.KeysMatched, len(report.Missing))
+	if opts.repair {
+		fmt.Fprintf(out, ", %d repaired, %d failed", report.Repaired, report.Failed)
+	}
+	fmt.Fprintln(out)
+	return nil
+}
@@ -268,7 +268,7 @@ func validateForMigration() {
 	fmt.Println()
 	fmt.Println("Next steps:")
 	fmt.Println("1. Run: go run ./cmd/migrate up")
-	fmt.Println("2. Run: go run ./cmd/migrate import " + sqliteFile)
+	fmt.Println("2. Run: go run ./cmd/migrate -command import -sqlite " + sqliteFile)
 	fmt.Println("3. Run: go run ./cmd/migrate validate")
 }
 
@@ -8,10 +8,15 @@ import (
 	"syscall"
 	"time"
 
+	"backend-go/internal/adapters/events"
+	"backend-go/internal/adapters/events/handlers"
+	"backend-go/internal/adapters/events/outbox"
 	"backend-go/internal/config"
 	"backend-go/internal/container"
+	"backend-go/internal/core/domain/shared"
 	"backend-go/internal/core/services"
 	"backend-go/internal/shared/logger"
+	"backend-go/pkg/buildinfo"
 
 	"github.com/sirupsen/logrus"
 )
@@ -26,6 +31,9 @@ func main() {
 	// 初始化日志
 	logger.Init(cfg.Log.Level)
 	logger.Info("Starting background worker...")
+	info := buildinfo.Get()
+	logger.Infof("Build info: version=%s commit=%s built=%s go=%s env=%s",
+		info.Version, info.GitCommit, info.BuildDate, info.GoVersion, config.GetEnvironment())
 
 	// 初始化依赖容器
 	cont, err := container.NewContainer(cfg)
@@ -39,15 +47,24 @@ func main() {
 		nil, nil, nil, nil, nil, nil, logger.GetLogger(),
 	)
 	defer asyncPointsIntegration.Shutdown()
+	// 异步计分完成后为涉及的用户重新计算徽章
+	if err := asyncPointsIntegration.EventBus.Subscribe(shared.EventPointsCalculated, cont.GetBadgeService()); err != nil {
+		log.Fatalf("Failed to subscribe badge service: %v", err)
+	}
 
 	// 创建上下文用于优雅关闭
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// 各定时任务执行后写入心跳，API 据此判断 worker 是否存活
+	heartbeat := services.NewWorkerHeartbeat(cont.GetCacheService(), logger.GetLogger())
+
 	// 启动定时任务（如需使用具体依赖，请从 cont 中获取服务/仓储并传入）
 	go func() {
-		ticker := time.NewTicker(5 * time.Minute) // 每5分钟执行一次
+		const interval = 5 * time.Minute // 每5分钟执行一次
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
+		scheduledHeartbeat := heartbeat.Job("scheduled_tasks", interval)
 
 		for {
 			select {
@@ -57,10 +74,66 @@ func main() {
 			case <-ticker.C:
 				// 执行定时任务（按需接入实际逻辑）
 				// executeScheduledTasks(ctx, asyncPointsIntegration, deps)
+				scheduledHeartbeat.Beat(ctx)
 			}
 		}
 	}()
 
+	// 定期校正 Redis 统计计数器
+	if cfg.Cache.Reconcile.Enabled {
+		reconciler := cont.GetStatsReconciler()
+		reconciler.SetHeartbeat(heartbeat.Job("stats_reconcile", reconciler.Interval()))
+		go reconciler.Run(ctx)
+	}
+
+	// 按访问频率预热排行榜缓存，热门赛事优先且刷新更频繁
+	if cfg.Cache.Warmup.Enabled {
+		warmer := cont.GetCacheWarmer()
+		warmer.SetHeartbeat(heartbeat.Job("cache_warmup", warmer.Interval()))
+		go warmer.Run(ctx)
+	}
+
+	// 每日预测统计汇总为月度数据与预计算序列
+	if cfg.Cache.Rollup.Enabled {
+		rollup := cont.GetStatsRollup()
+		rollup.SetHeartbeat(heartbeat.Job("stats_rollup", rollup.Interval()))
+		go rollup.Run(ctx)
+	}
+
+	// 统计计数异步批量写入，事件处理不等待 Redis
+	var statsBuffer *handlers.StatsBuffer
+	if cfg.Analytics.StatsBuffer.Enabled {
+		statsBuffer = handlers.NewStatsBuffer(cont.GetRedisClient(), handlers.StatsBufferConfig{
+			Size:          cfg.Analytics.StatsBuffer.Size,
+			BatchSize:     cfg.Analytics.StatsBuffer.BatchSize,
+			FlushInterval: cfg.Analytics.StatsBuffer.FlushInterval,
+		}, logger.GetLogger())
+		go statsBuffer.Run(ctx)
+	}
+
+	// 转发事件发件箱中未投递的事件
+	if cfg.Worker.Outbox.Enabled {
+		relay := outbox.NewRelay(
+			cont.GetDB(),
+			events.NewReplayBus(cont.GetRedisClient(), statsBuffer, handlers.PageViewConfig{TrackedPaths: cfg.Analytics.PageViews.TrackedPaths}, logger.GetLogger()),
+			events.DecodeReplayPayload,
+			outbox.RelayConfig{Interval: cfg.Worker.Outbox.Interval, BatchSize: cfg.Worker.Outbox.BatchSize, Retention: cfg.Worker.Outbox.Retention},
+			logger.GetLogger(),
+		)
+		relay.SetHeartbeat(heartbeat.Job("outbox_relay", relay.Interval()))
+		go relay.Run(ctx)
+	}
+
+	// 归档并清理超过保留期的比赛与预测
+	if cfg.Worker.Retention.Enabled {
+		retention := cont.GetDataRetention()
+		retention.SetHeartbeat(heartbeat.Job("data_retention", retention.Interval()))
+		go retention.Run(ctx)
+	}
+
+	// 定期加载功能开关灰度配置，积分计算等后台任务与 API 实例按相同配置判断
+	go cont.GetFeatureFlags().Run(ctx, cfg.Features.Flags.RefreshInterval)
+
 	// 启动积分计算状态监控
 	go func() {
 		ticker := time.NewTicker(30 * time.Second) // 每30秒监控一次
@@ -90,13 +163,35 @@ func main() {
 
 	logger.Info("Shutting down background worker...")
 
-	// 取消上下文，停止所有任务
+	// 取消上下文，停止所有定时任务
 	cancel()
 
-	// 等待任务完成
-	time.Sleep(10 * time.Second)
+	// 等待队列中的积分计算任务完成
+	if err := drainWorker(asyncPointsIntegration, cfg.Worker.ShutdownTimeout); err != nil {
+		logger.WithError(err).WithField("timeout", cfg.Worker.ShutdownTimeout).Warn("Async points calculation queue not fully drained")
+	}
+
+	// 写入缓冲中剩余的统计计数
+	if statsBuffer != nil {
+		if err := drainWorker(statsBuffer, cfg.Worker.ShutdownTimeout); err != nil {
+			logger.WithError(err).WithField("pending", statsBuffer.Len()).Warn("Stats buffer not fully flushed")
+		}
+	}
 
 	logger.Info("Background worker exited")
+	logger.Flush()
+}
+
+// drainer 可在上下文截止前排空任务队列的服务
+type drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// drainWorker 在 timeout 内等待任务队列排空，超时返回错误
+func drainWorker(d drainer, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return d.Drain(ctx)
 }
 
 // executeScheduledTasks 执行定时任务
@@ -106,10 +201,7 @@ func executeScheduledTasks(ctx context.Context, asyncPointsIntegration *services
 	// 1. 检查是否有已结束但未计算积分的比赛
 	go checkUnprocessedMatches(ctx, asyncPointsIntegration, cont)
 
-	// 2. 预热排行榜缓存
-	go warmupLeaderboardCache(ctx, cont)
-
-	// 3. 清理过期数据（如果需要）
+	// 2. 清理过期数据（如果需要）
 	go cleanupExpiredData(ctx, cont)
 
 	logger.Debug("Scheduled tasks initiated")
@@ -156,19 +248,6 @@ func checkUnprocessedMatches(ctx context.Context, asyncPointsIntegration *servic
 	}
 }
 
-// warmupLeaderboardCache 预热排行榜缓存
-func warmupLeaderboardCache(ctx context.Context, cont *container.Container) {
-	tournaments := []string{"SPRING", "SUMMER", "AUTUMN", "WINTER"}
-
-	for _, tournament := range tournaments {
-		if err := cont.GetLeaderboardService().RefreshLeaderboard(ctx, tournament); err != nil {
-			logger.WithError(err).WithField("tournament", tournament).Warn("Failed to warmup leaderboard cache")
-		} else {
-			logger.WithField("tournament", tournament).Debug("Leaderboard cache warmed up")
-		}
-	}
-}
-
 // cleanupExpiredData 清理过期数据
 func cleanupExpiredData(ctx context.Context, _ *container.Container) {
 	// 这里可以实现清理逻辑
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingDrainer 直到上下文结束才返回，模拟无法排空的队列
+type blockingDrainer struct {
+	deadline time.Time
+}
+
+func (d *blockingDrainer) Drain(ctx context.Context) error {
+	d.deadline, _ = ctx.Deadline()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestDrainWorker_StopsWaitingAtConfiguredTimeout(t *testing.T) {
+	d := &blockingDrainer{}
+	start := time.Now()
+
+	err := drainWorker(d, 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("drainWorker() error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := d.deadline.Sub(start); got < 40*time.Millisecond || got > 60*time.Millisecond {
+		t.Errorf("排空截止时间 = 启动后 %s, want 约 50ms", got)
+	}
+}
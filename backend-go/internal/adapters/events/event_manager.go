@@ -9,6 +9,7 @@ import (
 	"backend-go/internal/adapters/events/monitoring"
 	"backend-go/internal/adapters/events/persistence"
 	"backend-go/internal/adapters/events/replay"
+	"backend-go/internal/adapters/events/types"
 	"backend-go/internal/core/domain/shared"
 	"backend-go/pkg/redis"
 	"github.com/sirupsen/logrus"
@@ -31,6 +32,8 @@ func NewEventManager(
 	eventBus shared.EventBus,
 	db *gorm.DB,
 	redisClient *redis.Client,
+	statsBuffer *handlers.StatsBuffer,
+	pageViews handlers.PageViewConfig,
 	logger *logrus.Logger,
 ) *EventManager {
 	// 创建事件存储
@@ -38,7 +41,7 @@ func NewEventManager(
 	redisStore := persistence.NewRedisEventStore(redisClient, logger)
 
 	// 创建事件处理器
-	statisticsHandler := handlers.NewStatisticsHandler(redisClient, logger)
+	statisticsHandler := handlers.NewStatisticsHandler(redisClient, statsBuffer, pageViews, logger)
 	notificationService := handlers.NewMockNotificationService(logger)
 	notificationHandler := handlers.NewNotificationHandler(notificationService, redisClient, logger)
 	persistentHandler := persistence.NewPersistentEventHandler(mysqlStore, redisStore, logger)
@@ -64,15 +67,9 @@ func NewEventManager(
 // registerEventHandlers 注册事件处理器
 func (m *EventManager) registerEventHandlers() {
 	// 注册统计处理器
-	m.eventBus.Subscribe(EventUserRegistered, m.statisticsHandler)
-	m.eventBus.Subscribe(EventUserLoggedIn, m.statisticsHandler)
-	m.eventBus.Subscribe(EventPredictionCreated, m.statisticsHandler)
-	m.eventBus.Subscribe(EventVoteCast, m.statisticsHandler)
-	m.eventBus.Subscribe(EventMatchViewed, m.statisticsHandler)
-	m.eventBus.Subscribe(EventLeaderboardViewed, m.statisticsHandler)
-	m.eventBus.Subscribe(EventPageViewed, m.statisticsHandler)
-	m.eventBus.Subscribe(EventFeatureUsed, m.statisticsHandler)
-	m.eventBus.Subscribe(EventErrorEncountered, m.statisticsHandler)
+	for _, eventType := range statisticsEventTypes {
+		m.eventBus.Subscribe(eventType, m.statisticsHandler)
+	}
 
 	// 注册通知处理器
 	m.eventBus.Subscribe(EventUserRegistered, m.notificationHandler)
@@ -101,20 +98,19 @@ func (m *EventManager) registerEventHandlers() {
 
 // PublishUserRegistered 发布用户注册事件
 func (m *EventManager) PublishUserRegistered(userID uint, username, email, nickname, source string) error {
-	payload := &UserRegisteredPayload{
+	payload := &types.UserRegisteredPayload{
 		UserID:             userID,
 		Username:           username,
 		Email:              email,
 		RegistrationSource: source,
 	}
 
-	event := NewUserBehaviorEvent(EventUserRegistered, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.UserRegistered, userID, payload)
 }
 
 // PublishUserLoggedIn 发布用户登录事件
 func (m *EventManager) PublishUserLoggedIn(userID uint, username, loginMethod, loginSource string, loginCount int) error {
-	payload := &UserLoggedInPayload{
+	payload := &types.UserLoggedInPayload{
 		UserID:      userID,
 		Username:    username,
 		LoginMethod: loginMethod,
@@ -122,26 +118,24 @@ func (m *EventManager) PublishUserLoggedIn(userID uint, username, loginMethod, l
 		LoginCount:  loginCount,
 	}
 
-	event := NewUserBehaviorEvent(EventUserLoggedIn, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.UserLoggedIn, userID, payload)
 }
 
 // PublishUserLoggedOut 发布用户登出事件
 func (m *EventManager) PublishUserLoggedOut(userID uint, username string, sessionDuration time.Duration) error {
-	payload := &UserLoggedOutPayload{
+	payload := &types.UserLoggedOutPayload{
 		UserID:          userID,
 		Username:        username,
 		SessionDuration: sessionDuration,
 		LoggedOutAt:     time.Now(),
 	}
 
-	event := NewUserBehaviorEvent(EventUserLoggedOut, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.UserLoggedOut, userID, payload)
 }
 
 // PublishUserProfileUpdated 发布用户资料更新事件
 func (m *EventManager) PublishUserProfileUpdated(userID uint, username string, updatedFields []string, oldValues, newValues map[string]interface{}) error {
-	payload := &UserProfileUpdatedPayload{
+	payload := &types.UserProfileUpdatedPayload{
 		UserID:        userID,
 		Username:      username,
 		UpdatedFields: updatedFields,
@@ -150,13 +144,12 @@ func (m *EventManager) PublishUserProfileUpdated(userID uint, username string, u
 		UpdatedAt:     time.Now(),
 	}
 
-	event := NewUserBehaviorEvent(EventUserProfileUpdated, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.UserProfileUpdated, userID, payload)
 }
 
 // PublishPredictionCreated 发布预测创建事件
 func (m *EventManager) PublishPredictionCreated(predictionID, userID, matchID uint, predictedWinner string, scoreA, scoreB int, tournament string, timeToStart time.Duration) error {
-	payload := &PredictionCreatedPayload{
+	payload := &types.PredictionCreatedPayload{
 		PredictionID:     predictionID,
 		UserID:           userID,
 		MatchID:          matchID,
@@ -164,13 +157,12 @@ func (m *EventManager) PublishPredictionCreated(predictionID, userID, matchID ui
 		TimeToMatchStart: timeToStart,
 	}
 
-	event := NewUserBehaviorEvent(EventPredictionCreated, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.PredictionCreated, userID, payload)
 }
 
 // PublishPredictionUpdated 发布预测更新事件
 func (m *EventManager) PublishPredictionUpdated(predictionID, userID, matchID uint, oldWinner, newWinner string, oldScoreA, newScoreA, oldScoreB, newScoreB int, modCount int, timeToStart time.Duration) error {
-	payload := &PredictionUpdatedPayload{
+	payload := &types.PredictionUpdatedPayload{
 		PredictionID:       predictionID,
 		UserID:             userID,
 		MatchID:            matchID,
@@ -185,13 +177,12 @@ func (m *EventManager) PublishPredictionUpdated(predictionID, userID, matchID ui
 		UpdatedAt:          time.Now(),
 	}
 
-	event := NewUserBehaviorEvent(EventPredictionUpdated, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.PredictionUpdated, userID, payload)
 }
 
 // PublishVoteCast 发布投票事件
 func (m *EventManager) PublishVoteCast(voteID, userID, predictionID, matchID, voterID uint, newVoteCount int) error {
-	payload := &VoteCastPayload{
+	payload := &types.VoteCastPayload{
 		VoteID:       voteID,
 		UserID:       userID,
 		PredictionID: predictionID,
@@ -199,13 +190,12 @@ func (m *EventManager) PublishVoteCast(voteID, userID, predictionID, matchID, vo
 		NewVoteCount: newVoteCount,
 	}
 
-	event := NewUserBehaviorEvent(EventVoteCast, voterID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.VoteCast, voterID, payload)
 }
 
 // PublishVoteRemoved 发布取消投票事件
 func (m *EventManager) PublishVoteRemoved(userID, predictionID, matchID, voterID uint, newVoteCount int) error {
-	payload := &VoteRemovedPayload{
+	payload := &types.VoteRemovedPayload{
 		UserID:       userID,
 		PredictionID: predictionID,
 		MatchID:      matchID,
@@ -214,39 +204,36 @@ func (m *EventManager) PublishVoteRemoved(userID, predictionID, matchID, voterID
 		RemovedAt:    time.Now(),
 	}
 
-	event := NewUserBehaviorEvent(EventVoteRemoved, voterID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.VoteRemoved, voterID, payload)
 }
 
 // PublishMatchViewed 发布比赛查看事件
 func (m *EventManager) PublishMatchViewed(matchID, userID uint, teamA, teamB, tournament, status string, viewDuration time.Duration) error {
-	payload := &MatchViewedPayload{
+	payload := &types.MatchViewedPayload{
 		MatchID:      matchID,
 		UserID:       userID,
 		Tournament:   tournament,
 		ViewDuration: viewDuration,
 	}
 
-	event := NewUserBehaviorEvent(EventMatchViewed, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.MatchViewed, userID, payload)
 }
 
 // PublishLeaderboardViewed 发布排行榜查看事件
 func (m *EventManager) PublishLeaderboardViewed(userID uint, tournament string, userRank, userPoints int, viewDuration time.Duration) error {
-	payload := &LeaderboardViewedPayload{
+	payload := &types.LeaderboardViewedPayload{
 		UserID:     userID,
 		Tournament: tournament,
 		UserRank:   userRank,
 		UserPoints: userPoints,
 	}
 
-	event := NewUserBehaviorEvent(EventLeaderboardViewed, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.LeaderboardViewed, userID, payload)
 }
 
 // PublishRankingChanged 发布排名变化事件
 func (m *EventManager) PublishRankingChanged(userID uint, username, tournament string, oldRank, newRank, oldPoints, newPoints int) error {
-	payload := &RankingChangedPayload{
+	payload := &types.RankingChangedPayload{
 		UserID:     userID,
 		Username:   username,
 		Tournament: tournament,
@@ -255,25 +242,23 @@ func (m *EventManager) PublishRankingChanged(userID uint, username, tournament s
 		RankChange: oldRank - newRank, // 正数表示排名上升
 	}
 
-	event := NewUserBehaviorEvent(EventRankingChanged, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.RankingChanged, userID, payload)
 }
 
 // PublishPageViewed 发布页面访问事件
 func (m *EventManager) PublishPageViewed(userID uint, pagePath, pageTitle, referrer string, viewDuration time.Duration) error {
-	payload := &PageViewedPayload{
+	payload := &types.PageViewedPayload{
 		UserID:   userID,
 		PagePath: pagePath,
 		Referrer: referrer,
 	}
 
-	event := NewUserBehaviorEvent(EventPageViewed, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.PageViewed, userID, payload)
 }
 
 // PublishFeatureUsed 发布功能使用事件
 func (m *EventManager) PublishFeatureUsed(userID uint, featureName, action string, parameters map[string]interface{}, success bool, duration time.Duration) error {
-	payload := &FeatureUsedPayload{
+	payload := &types.FeatureUsedPayload{
 		UserID:      userID,
 		FeatureName: featureName,
 		Action:      action,
@@ -281,13 +266,12 @@ func (m *EventManager) PublishFeatureUsed(userID uint, featureName, action strin
 		Duration:    duration,
 	}
 
-	event := NewUserBehaviorEvent(EventFeatureUsed, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.FeatureUsed, userID, payload)
 }
 
 // PublishErrorEncountered 发布错误遇到事件
 func (m *EventManager) PublishErrorEncountered(userID uint, errorType, errorCode, errorMessage, severity string, context map[string]interface{}) error {
-	payload := &ErrorEncounteredPayload{
+	payload := &types.ErrorEncounteredPayload{
 		UserID:       userID,
 		ErrorType:    errorType,
 		ErrorCode:    errorCode,
@@ -295,13 +279,12 @@ func (m *EventManager) PublishErrorEncountered(userID uint, errorType, errorCode
 		Severity:     severity,
 	}
 
-	event := NewUserBehaviorEvent(EventErrorEncountered, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.ErrorEncountered, userID, payload)
 }
 
 // PublishSearchPerformed 发布搜索执行事件
 func (m *EventManager) PublishSearchPerformed(userID uint, searchQuery, searchType string, resultCount int, searchDuration time.Duration) error {
-	payload := &SearchPerformedPayload{
+	payload := &types.SearchPerformedPayload{
 		UserID:         userID,
 		SearchQuery:    searchQuery,
 		SearchType:     searchType,
@@ -310,8 +293,7 @@ func (m *EventManager) PublishSearchPerformed(userID uint, searchQuery, searchTy
 		PerformedAt:    time.Now(),
 	}
 
-	event := NewUserBehaviorEvent(EventSearchPerformed, userID, payload)
-	return m.eventBus.Publish(event)
+	return Publish(m.eventBus, types.SearchPerformed, userID, payload)
 }
 
 // GetStatistics 获取统计数据
@@ -20,16 +20,6 @@ type NotificationService interface {
 	SendPointsEarnedNotification(userID uint, points int, matchID uint) error
 }
 
-// RankingChangedPayload 排名变化事件载荷
-type RankingChangedPayload struct {
-	UserID     uint   `json:"user_id"`
-	Username   string `json:"username"`
-	OldRank    int    `json:"old_rank"`
-	NewRank    int    `json:"new_rank"`
-	RankChange int    `json:"rank_change"`
-	Tournament string `json:"tournament"`
-}
-
 // NotificationHandler 通知事件处理器
 type NotificationHandler struct {
 	notificationService NotificationService
@@ -55,13 +45,13 @@ func (h *NotificationHandler) Handle(event shared.Event) error {
 	ctx := context.Background()
 
 	switch event.GetType() {
-	case "user.registered":
+	case types.UserRegistered.String():
 		return h.handleUserRegistered(ctx, event)
-	case "vote.cast":
+	case types.VoteCast.String():
 		return h.handleVoteCast(ctx, event)
-	case "ranking.changed":
+	case types.RankingChanged.String():
 		return h.handleRankingChanged(ctx, event)
-	case "match.viewed":
+	case types.MatchViewed.String():
 		return h.handleMatchSubscription(ctx, event)
 	default:
 		h.logger.WithField("event_type", event.GetType()).Debug("Unhandled event type in notification handler")
@@ -73,9 +63,9 @@ func (h *NotificationHandler) Handle(event shared.Event) error {
 
 // handleUserRegistered 处理用户注册通知
 func (h *NotificationHandler) handleUserRegistered(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*types.UserRegisteredPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for user registered event")
+	payload, err := types.UserRegistered.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 发送欢迎邮件
@@ -106,9 +96,9 @@ func (h *NotificationHandler) handleUserRegistered(ctx context.Context, event sh
 
 // handleVoteCast 处理投票通知
 func (h *NotificationHandler) handleVoteCast(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*types.VoteCastPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for vote cast event")
+	payload, err := types.VoteCast.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 检查是否需要发送通知（避免频繁通知）
@@ -158,9 +148,9 @@ func (h *NotificationHandler) handleVoteCast(ctx context.Context, event shared.E
 
 // handleRankingChanged 处理排名变化通知
 func (h *NotificationHandler) handleRankingChanged(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*RankingChangedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for ranking changed event")
+	payload, err := types.RankingChanged.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 只有排名提升时才发送通知
@@ -209,9 +199,9 @@ func (h *NotificationHandler) handleRankingChanged(ctx context.Context, event sh
 
 // handleMatchSubscription 处理比赛订阅通知
 func (h *NotificationHandler) handleMatchSubscription(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*MatchViewedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for match viewed event")
+	payload, err := types.MatchViewed.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 如果用户查看比赛超过30秒，自动订阅比赛通知
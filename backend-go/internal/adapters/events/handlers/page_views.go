@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strings"
+)
+
+// pageViewsOtherKey 未在白名单中的页面只累加该聚合计数，避免动态路径产生无限多的键
+const pageViewsOtherKey = "stats:page_views:other"
+
+// PageViewConfig 页面访问统计配置
+type PageViewConfig struct {
+	TrackedPaths []string // 单独计数的页面路径模板，如 /matches/:id；为空时所有页面只计入聚合计数
+}
+
+// pageViewTracker 将页面路径归一化为模板并映射到统计键
+type pageViewTracker struct {
+	tracked map[string]struct{}
+}
+
+func newPageViewTracker(config PageViewConfig) *pageViewTracker {
+	tracked := make(map[string]struct{}, len(config.TrackedPaths))
+	for _, path := range config.TrackedPaths {
+		tracked[NormalizePagePath(path)] = struct{}{}
+	}
+	return &pageViewTracker{tracked: tracked}
+}
+
+// key 返回页面访问计数键，白名单外的页面返回聚合键
+func (t *pageViewTracker) key(pagePath string) string {
+	template := NormalizePagePath(pagePath)
+	if _, ok := t.tracked[template]; !ok {
+		return pageViewsOtherKey
+	}
+	return "stats:page_views:" + template
+}
+
+// NormalizePagePath 去掉查询参数、片段和末尾斜杠，并将纯数字路径段折叠为 :id
+//
+// 例如 /match/123?tab=stats 归一化为 /match/:id。
+func NormalizePagePath(pagePath string) string {
+	if i := strings.IndexAny(pagePath, "?#"); i >= 0 {
+		pagePath = pagePath[:i]
+	}
+
+	segments := strings.Split(strings.Trim(pagePath, "/"), "/")
+	normalized := segments[:0]
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if isNumeric(segment) {
+			segment = ":id"
+		}
+		normalized = append(normalized, strings.ToLower(segment))
+	}
+	return "/" + strings.Join(normalized, "/")
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
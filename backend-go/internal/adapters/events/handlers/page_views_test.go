@@ -0,0 +1,53 @@
+package handlers
+
+import "testing"
+
+func TestNormalizePagePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/match/123", "/match/:id"},
+		{"/match/456/", "/match/:id"},
+		{"/Matches/42?tab=stats#top", "/matches/:id"},
+		{"/users/7/predictions/99", "/users/:id/predictions/:id"},
+		{"/leaderboard/spring", "/leaderboard/spring"},
+		{"", "/"},
+		{"/", "/"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePagePath(tt.path); got != tt.want {
+			t.Errorf("NormalizePagePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPageViewTracker_Key(t *testing.T) {
+	tracker := newPageViewTracker(PageViewConfig{TrackedPaths: []string{"/", "/match/:id", "/leaderboard/"}})
+
+	// 不同比赛 ID 共用同一个模板键
+	if got := tracker.key("/match/123"); got != "stats:page_views:/match/:id" {
+		t.Errorf("key(/match/123) = %q, want template key", got)
+	}
+	if got := tracker.key("/match/456"); got != "stats:page_views:/match/:id" {
+		t.Errorf("key(/match/456) = %q, want template key", got)
+	}
+	if got := tracker.key("/leaderboard?tournament=SPRING"); got != "stats:page_views:/leaderboard" {
+		t.Errorf("key(/leaderboard) = %q, want tracked key", got)
+	}
+
+	// 白名单外的页面只计入聚合计数
+	for _, path := range []string{"/admin/users/9", "/match/123/comments", "/unknown"} {
+		if got := tracker.key(path); got != pageViewsOtherKey {
+			t.Errorf("key(%q) = %q, want %q", path, got, pageViewsOtherKey)
+		}
+	}
+}
+
+func TestPageViewTracker_EmptyAllowlist(t *testing.T) {
+	tracker := newPageViewTracker(PageViewConfig{})
+	if got := tracker.key("/"); got != pageViewsOtherKey {
+		t.Errorf("key(/) = %q, want %q", got, pageViewsOtherKey)
+	}
+}
@@ -5,91 +5,28 @@ import (
 	"fmt"
 	"time"
 
+	"backend-go/internal/adapters/events/types"
 	"backend-go/internal/core/domain/shared"
 	"backend-go/pkg/redis"
 	"github.com/sirupsen/logrus"
 )
 
-// Event payload types for statistics handler
-type UserRegisteredPayload struct {
-	UserID             uint   `json:"user_id"`
-	Username           string `json:"username"`
-	Email              string `json:"email"`
-	RegistrationSource string `json:"registration_source"`
-}
-
-type UserLoggedInPayload struct {
-	UserID      uint   `json:"user_id"`
-	Username    string `json:"username"`
-	LoginMethod string `json:"login_method"`
-	LoginSource string `json:"login_source"`
-	LoginCount  int    `json:"login_count"`
-}
-
-type PredictionCreatedPayload struct {
-	PredictionID     uint          `json:"prediction_id"`
-	UserID           uint          `json:"user_id"`
-	MatchID          uint          `json:"match_id"`
-	Tournament       string        `json:"tournament"`
-	TimeToMatchStart time.Duration `json:"time_to_match_start"`
-}
-
-type VoteCastPayload struct {
-	VoteID       uint `json:"vote_id"`
-	UserID       uint `json:"user_id"`
-	PredictionID uint `json:"prediction_id"`
-	VoterID      uint `json:"voter_id"`
-	NewVoteCount int  `json:"new_vote_count"`
-}
-
-type MatchViewedPayload struct {
-	MatchID      uint          `json:"match_id"`
-	UserID       uint          `json:"user_id"`
-	Tournament   string        `json:"tournament"`
-	ViewDuration time.Duration `json:"view_duration"`
-}
-
-type LeaderboardViewedPayload struct {
-	UserID     uint   `json:"user_id"`
-	Tournament string `json:"tournament"`
-	UserRank   int    `json:"user_rank"`
-	UserPoints int    `json:"user_points"`
-}
-
-type PageViewedPayload struct {
-	UserID   uint   `json:"user_id"`
-	PagePath string `json:"page_path"`
-	Referrer string `json:"referrer"`
-}
-
-type FeatureUsedPayload struct {
-	UserID      uint          `json:"user_id"`
-	FeatureName string        `json:"feature_name"`
-	Action      string        `json:"action"`
-	Success     bool          `json:"success"`
-	Duration    time.Duration `json:"duration"`
-}
-
-type ErrorEncounteredPayload struct {
-	UserID       uint   `json:"user_id"`
-	ErrorType    string `json:"error_type"`
-	ErrorCode    string `json:"error_code"`
-	ErrorMessage string `json:"error_message"`
-	Severity     string `json:"severity"`
-}
-
-
-
 // StatisticsHandler 统计事件处理器
 type StatisticsHandler struct {
 	redisClient *redis.Client
+	buffer      *StatsBuffer
+	pageViews   *pageViewTracker
 	logger      *logrus.Logger
 }
 
 // NewStatisticsHandler 创建统计事件处理器
-func NewStatisticsHandler(redisClient *redis.Client, logger *logrus.Logger) *StatisticsHandler {
+//
+// buffer 不为 nil 时计数增量异步批量写入，否则同步写入 Redis。
+func NewStatisticsHandler(redisClient *redis.Client, buffer *StatsBuffer, pageViews PageViewConfig, logger *logrus.Logger) *StatisticsHandler {
 	return &StatisticsHandler{
 		redisClient: redisClient,
+		buffer:      buffer,
+		pageViews:   newPageViewTracker(pageViews),
 		logger:      logger,
 	}
 }
@@ -99,23 +36,23 @@ func (h *StatisticsHandler) Handle(event shared.Event) error {
 	ctx := context.Background()
 
 	switch event.GetType() {
-	case "user.registered":
+	case types.UserRegistered.String():
 		return h.handleUserRegistered(ctx, event)
-	case "user.logged_in":
+	case types.UserLoggedIn.String():
 		return h.handleUserLoggedIn(ctx, event)
-	case "prediction.created":
+	case types.PredictionCreated.String():
 		return h.handlePredictionCreated(ctx, event)
-	case "vote.cast":
+	case types.VoteCast.String():
 		return h.handleVoteCast(ctx, event)
-	case "match.viewed":
+	case types.MatchViewed.String():
 		return h.handleMatchViewed(ctx, event)
-	case "leaderboard.viewed":
+	case types.LeaderboardViewed.String():
 		return h.handleLeaderboardViewed(ctx, event)
-	case "page.viewed":
+	case types.PageViewed.String():
 		return h.handlePageViewed(ctx, event)
-	case "feature.used":
+	case types.FeatureUsed.String():
 		return h.handleFeatureUsed(ctx, event)
-	case "error.encountered":
+	case types.ErrorEncountered.String():
 		return h.handleErrorEncountered(ctx, event)
 	default:
 		h.logger.WithField("event_type", event.GetType()).Debug("Unhandled event type in statistics handler")
@@ -125,36 +62,26 @@ func (h *StatisticsHandler) Handle(event shared.Event) error {
 
 // handleUserRegistered 处理用户注册统计
 func (h *StatisticsHandler) handleUserRegistered(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*UserRegisteredPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for user registered event")
+	payload, err := types.UserRegistered.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新每日注册统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:registrations:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily registration count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour) // 保留7�?
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily registration count") // 保留7�?
 	// 更新每月注册统计
 	month := time.Now().Format("2006-01")
 	monthlyKey := fmt.Sprintf("stats:registrations:monthly:%s", month)
-	if _, err := h.redisClient.Incr(ctx, monthlyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment monthly registration count")
-	}
-	h.redisClient.Expire(ctx, monthlyKey, 365*24*time.Hour) // 保留1�?
+	h.incr(ctx, monthlyKey, 365*24*time.Hour, "Failed to increment monthly registration count") // 保留1�?
 	// 按注册来源统计
 	sourceKey := fmt.Sprintf("stats:registrations:source:%s", payload.RegistrationSource)
-	if _, err := h.redisClient.Incr(ctx, sourceKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment registration source count")
-	}
+	h.incr(ctx, sourceKey, 0, "Failed to increment registration source count")
 
 	// 更新总注册数
 	totalKey := "stats:registrations:total"
-	if _, err := h.redisClient.Incr(ctx, totalKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment total registration count")
-	}
+	h.incr(ctx, totalKey, 0, "Failed to increment total registration count")
 
 	h.logger.WithFields(logrus.Fields{
 		"user_id": payload.UserID,
@@ -166,9 +93,9 @@ func (h *StatisticsHandler) handleUserRegistered(ctx context.Context, event shar
 
 // handleUserLoggedIn 处理用户登录统计
 func (h *StatisticsHandler) handleUserLoggedIn(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*UserLoggedInPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for user logged in event")
+	payload, err := types.UserLoggedIn.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新每日活跃用户
@@ -189,15 +116,11 @@ func (h *StatisticsHandler) handleUserLoggedIn(ctx context.Context, event shared
 
 	// 按登录方式统计
 	methodKey := fmt.Sprintf("stats:logins:method:%s", payload.LoginMethod)
-	if _, err := h.redisClient.Incr(ctx, methodKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment login method count")
-	}
+	h.incr(ctx, methodKey, 0, "Failed to increment login method count")
 
 	// 按登录来源统计
 	sourceKey := fmt.Sprintf("stats:logins:source:%s", payload.LoginSource)
-	if _, err := h.redisClient.Incr(ctx, sourceKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment login source count")
-	}
+	h.incr(ctx, sourceKey, 0, "Failed to increment login source count")
 
 	// 更新用户登录次数
 	userLoginKey := fmt.Sprintf("stats:user:logins:%d", payload.UserID)
@@ -217,37 +140,28 @@ func (h *StatisticsHandler) handleUserLoggedIn(ctx context.Context, event shared
 
 // handlePredictionCreated 处理预测创建统计
 func (h *StatisticsHandler) handlePredictionCreated(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*PredictionCreatedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for prediction created event")
+	payload, err := types.PredictionCreated.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新每日预测统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:predictions:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily prediction count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour)
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily prediction count")
 
 	// 按锦标赛统计
 	tournamentKey := fmt.Sprintf("stats:predictions:tournament:%s", payload.Tournament)
-	if _, err := h.redisClient.Incr(ctx, tournamentKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment tournament prediction count")
-	}
+	h.incr(ctx, tournamentKey, 0, "Failed to increment tournament prediction count")
 
 	// 更新用户预测统计
 	userPredKey := fmt.Sprintf("stats:user:predictions:%d", payload.UserID)
-	if _, err := h.redisClient.Incr(ctx, userPredKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment user prediction count")
-	}
+	h.incr(ctx, userPredKey, 0, "Failed to increment user prediction count")
 
 	// 按预测时间距离比赛开始时间统计
 	timeCategory := h.categorizeTimeToMatch(payload.TimeToMatchStart)
 	timingKey := fmt.Sprintf("stats:predictions:timing:%s", timeCategory)
-	if _, err := h.redisClient.Incr(ctx, timingKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment prediction timing count")
-	}
+	h.incr(ctx, timingKey, 0, "Failed to increment prediction timing count")
 
 	h.logger.WithFields(logrus.Fields{
 		"prediction_id":   payload.PredictionID,
@@ -263,24 +177,19 @@ func (h *StatisticsHandler) handlePredictionCreated(ctx context.Context, event s
 
 // handleVoteCast 处理投票统计
 func (h *StatisticsHandler) handleVoteCast(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*VoteCastPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for vote cast event")
+	payload, err := types.VoteCast.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新每日投票统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:votes:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily vote count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour)
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily vote count")
 
 	// 更新用户投票统计
 	userVoteKey := fmt.Sprintf("stats:user:votes:%d", payload.VoterID)
-	if _, err := h.redisClient.Incr(ctx, userVoteKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment user vote count")
-	}
+	h.incr(ctx, userVoteKey, 0, "Failed to increment user vote count")
 
 	// 更新预测获得投票统计
 	predVoteKey := fmt.Sprintf("stats:prediction:votes:%d", payload.PredictionID)
@@ -300,30 +209,23 @@ func (h *StatisticsHandler) handleVoteCast(ctx context.Context, event shared.Eve
 
 // handleMatchViewed 处理比赛查看统计
 func (h *StatisticsHandler) handleMatchViewed(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*MatchViewedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for match viewed event")
+	payload, err := types.MatchViewed.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新比赛查看次数
 	matchViewKey := fmt.Sprintf("stats:match:views:%d", payload.MatchID)
-	if _, err := h.redisClient.Incr(ctx, matchViewKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment match view count")
-	}
+	h.incr(ctx, matchViewKey, 0, "Failed to increment match view count")
 
 	// 更新每日比赛查看统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:match_views:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily match view count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour)
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily match view count")
 
 	// 按锦标赛统计
 	tournamentKey := fmt.Sprintf("stats:match_views:tournament:%s", payload.Tournament)
-	if _, err := h.redisClient.Incr(ctx, tournamentKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment tournament match view count")
-	}
+	h.incr(ctx, tournamentKey, 0, "Failed to increment tournament match view count")
 
 	h.logger.WithFields(logrus.Fields{
 		"match_id":      payload.MatchID,
@@ -337,24 +239,19 @@ func (h *StatisticsHandler) handleMatchViewed(ctx context.Context, event shared.
 
 // handleLeaderboardViewed 处理排行榜查看统计
 func (h *StatisticsHandler) handleLeaderboardViewed(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*LeaderboardViewedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for leaderboard viewed event")
+	payload, err := types.LeaderboardViewed.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新每日排行榜查看统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:leaderboard_views:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily leaderboard view count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour)
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily leaderboard view count")
 
 	// 按锦标赛统计
 	tournamentKey := fmt.Sprintf("stats:leaderboard_views:tournament:%s", payload.Tournament)
-	if _, err := h.redisClient.Incr(ctx, tournamentKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment tournament leaderboard view count")
-	}
+	h.incr(ctx, tournamentKey, 0, "Failed to increment tournament leaderboard view count")
 
 	h.logger.WithFields(logrus.Fields{
 		"user_id":     payload.UserID,
@@ -368,24 +265,19 @@ func (h *StatisticsHandler) handleLeaderboardViewed(ctx context.Context, event s
 
 // handlePageViewed 处理页面访问统计
 func (h *StatisticsHandler) handlePageViewed(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*PageViewedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for page viewed event")
+	payload, err := types.PageViewed.Payload(event)
+	if err != nil {
+		return err
 	}
 
-	// 更新页面访问统计
-	pageKey := fmt.Sprintf("stats:page_views:%s", payload.PagePath)
-	if _, err := h.redisClient.Incr(ctx, pageKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment page view count")
-	}
+	// 更新页面访问统计，路径按模板归一化，白名单外的页面只计入聚合计数
+	pageKey := h.pageViews.key(payload.PagePath)
+	h.incr(ctx, pageKey, 0, "Failed to increment page view count")
 
 	// 更新每日页面访问统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:page_views:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily page view count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour)
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily page view count")
 
 	h.logger.WithFields(logrus.Fields{
 		"user_id":   payload.UserID,
@@ -398,22 +290,18 @@ func (h *StatisticsHandler) handlePageViewed(ctx context.Context, event shared.E
 
 // handleFeatureUsed 处理功能使用统计
 func (h *StatisticsHandler) handleFeatureUsed(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*FeatureUsedPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for feature used event")
+	payload, err := types.FeatureUsed.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新功能使用统计
 	featureKey := fmt.Sprintf("stats:feature_usage:%s:%s", payload.FeatureName, payload.Action)
-	if _, err := h.redisClient.Incr(ctx, featureKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment feature usage count")
-	}
+	h.incr(ctx, featureKey, 0, "Failed to increment feature usage count")
 
 	// 按成�?失败统计
 	statusKey := fmt.Sprintf("stats:feature_usage:%s:%s:%t", payload.FeatureName, payload.Action, payload.Success)
-	if _, err := h.redisClient.Incr(ctx, statusKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment feature usage status count")
-	}
+	h.incr(ctx, statusKey, 0, "Failed to increment feature usage status count")
 
 	h.logger.WithFields(logrus.Fields{
 		"user_id":      payload.UserID,
@@ -428,30 +316,23 @@ func (h *StatisticsHandler) handleFeatureUsed(ctx context.Context, event shared.
 
 // handleErrorEncountered 处理错误统计
 func (h *StatisticsHandler) handleErrorEncountered(ctx context.Context, event shared.Event) error {
-	payload, ok := event.GetPayload().(*ErrorEncounteredPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload type for error encountered event")
+	payload, err := types.ErrorEncountered.Payload(event)
+	if err != nil {
+		return err
 	}
 
 	// 更新错误统计
 	errorKey := fmt.Sprintf("stats:errors:%s:%s", payload.ErrorType, payload.ErrorCode)
-	if _, err := h.redisClient.Incr(ctx, errorKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment error count")
-	}
+	h.incr(ctx, errorKey, 0, "Failed to increment error count")
 
 	// 按严重程度统计
 	severityKey := fmt.Sprintf("stats:errors:severity:%s", payload.Severity)
-	if _, err := h.redisClient.Incr(ctx, severityKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment error severity count")
-	}
+	h.incr(ctx, severityKey, 0, "Failed to increment error severity count")
 
 	// 更新每日错误统计
 	today := time.Now().Format("2006-01-02")
 	dailyKey := fmt.Sprintf("stats:errors:daily:%s", today)
-	if _, err := h.redisClient.Incr(ctx, dailyKey); err != nil {
-		h.logger.WithError(err).Error("Failed to increment daily error count")
-	}
-	h.redisClient.Expire(ctx, dailyKey, 7*24*time.Hour)
+	h.incr(ctx, dailyKey, 7*24*time.Hour, "Failed to increment daily error count")
 
 	h.logger.WithFields(logrus.Fields{
 		"user_id":       payload.UserID,
@@ -464,6 +345,20 @@ func (h *StatisticsHandler) handleErrorEncountered(ctx context.Context, event sh
 	return nil
 }
 
+// incr 累加计数，ttl>0 时刷新过期时间；失败只记录日志
+func (h *StatisticsHandler) incr(ctx context.Context, key string, ttl time.Duration, failure string) {
+	if h.buffer != nil {
+		h.buffer.Incr(key, ttl)
+		return
+	}
+	if _, err := h.redisClient.Incr(ctx, key); err != nil {
+		h.logger.WithError(err).Error(failure)
+	}
+	if ttl > 0 {
+		h.redisClient.Expire(ctx, key, ttl)
+	}
+}
+
 // categorizeTimeToMatch 将预测时间距离比赛开始时间分类
 func (h *StatisticsHandler) categorizeTimeToMatch(duration time.Duration) string {
 	hours := duration.Hours()
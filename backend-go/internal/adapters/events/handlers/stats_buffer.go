@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/pkg/redis"
+)
+
+var (
+	// 缓冲区已满时丢弃的最早的计数增量
+	statsBufferDropped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "stats_buffer_dropped_total",
+			Help: "Total number of stat increments dropped because the async stats buffer was full",
+		},
+	)
+
+	// 缓冲区中等待写入的计数增量
+	statsBufferQueued = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "stats_buffer_queued",
+			Help: "Number of stat increments waiting in the async stats buffer",
+		},
+	)
+)
+
+// statsBatchWriter 批量写入计数增量，*redis.Client 满足该接口
+type statsBatchWriter interface {
+	IncrBatch(ctx context.Context, increments []redis.Increment) error
+}
+
+// StatsBufferConfig 统计写入缓冲配置
+type StatsBufferConfig struct {
+	Size          int           // 缓冲区容量，已满时丢弃最早的增量
+	BatchSize     int           // 单个 pipeline 最多写入的键数，积压达到该数量时立即写入
+	FlushInterval time.Duration // 定期写入间隔
+}
+
+// StatsBuffer 统计计数的异步写入缓冲
+//
+// 统计处理器只把增量放入有界缓冲区，由后台任务按批合并相同键后通过 pipeline
+// 写入 Redis，请求处理不再等待 Redis。统计本身是尽力而为的：缓冲区已满时丢弃
+// 最早的增量并计入 stats_buffer_dropped_total，写入失败只记录日志。
+type StatsBuffer struct {
+	writer statsBatchWriter
+	config StatsBufferConfig
+	logger *logrus.Logger
+
+	mu    sync.Mutex
+	ring  []redis.Increment
+	head  int // 最早的增量所在位置
+	count int
+
+	ready chan struct{}
+}
+
+// NewStatsBuffer 创建统计写入缓冲
+func NewStatsBuffer(writer statsBatchWriter, config StatsBufferConfig, logger *logrus.Logger) *StatsBuffer {
+	if config.Size <= 0 {
+		config.Size = 10000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	return &StatsBuffer{
+		writer: writer,
+		config: config,
+		logger: logger,
+		ring:   make([]redis.Increment, config.Size),
+		ready:  make(chan struct{}, 1),
+	}
+}
+
+// Incr 登记一次计数增量，不会阻塞；ttl>0 时写入后刷新键的过期时间
+func (b *StatsBuffer) Incr(key string, ttl time.Duration) {
+	b.mu.Lock()
+	if b.count == len(b.ring) {
+		b.head = (b.head + 1) % len(b.ring)
+		b.count--
+		statsBufferDropped.Inc()
+	}
+	b.ring[(b.head+b.count)%len(b.ring)] = redis.Increment{Key: key, Delta: 1, TTL: ttl}
+	b.count++
+	backlog := b.count
+	b.mu.Unlock()
+
+	statsBufferQueued.Set(float64(backlog))
+	if backlog >= b.config.BatchSize {
+		select {
+		case b.ready <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Len 缓冲区中等待写入的增量数
+func (b *StatsBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.count
+}
+
+// Run 定期或积压达到批量大小时写入，ctx 取消后返回
+//
+// 退出时不再写入剩余增量，需要时调用 Drain。
+func (b *StatsBuffer) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.ready:
+		}
+		// 已取出的批次不随 ctx 取消而丢失，退出时剩余的增量由 Drain 写入
+		b.flush(ctx, context.WithoutCancel(ctx))
+	}
+}
+
+// Flush 写入当前缓冲的全部增量
+func (b *StatsBuffer) Flush(ctx context.Context) {
+	b.flush(ctx, ctx)
+}
+
+// flush 逐批写入直到缓冲区为空或 ctx 结束，writeCtx 用于单次 pipeline
+func (b *StatsBuffer) flush(ctx, writeCtx context.Context) {
+	for ctx.Err() == nil {
+		batch := b.take()
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.writer.IncrBatch(writeCtx, batch); err != nil {
+			b.logger.WithError(err).WithField("keys", len(batch)).Warn("Failed to flush stats buffer")
+		}
+	}
+}
+
+// Drain 在 ctx 截止前写入剩余增量，用于进程退出
+func (b *StatsBuffer) Drain(ctx context.Context) error {
+	b.Flush(ctx)
+	return ctx.Err()
+}
+
+// take 取出最多 BatchSize 个不同键的增量，相同键合并为一次 INCRBY
+func (b *StatsBuffer) take() []redis.Increment {
+	b.mu.Lock()
+	defer func() {
+		statsBufferQueued.Set(float64(b.count))
+		b.mu.Unlock()
+	}()
+
+	var batch []redis.Increment
+	index := make(map[string]int)
+	for b.count > 0 {
+		inc := b.ring[b.head]
+		i, ok := index[inc.Key]
+		if !ok && len(batch) == b.config.BatchSize {
+			break
+		}
+		if ok {
+			batch[i].Delta += inc.Delta
+			if inc.TTL > batch[i].TTL {
+				batch[i].TTL = inc.TTL
+			}
+		} else {
+			index[inc.Key] = len(batch)
+			batch = append(batch, inc)
+		}
+		b.ring[b.head] = redis.Increment{}
+		b.head = (b.head + 1) % len(b.ring)
+		b.count--
+	}
+	return batch
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/pkg/redis"
+)
+
+// recordingBatchWriter 记录每次 pipeline 写入的批次
+type recordingBatchWriter struct {
+	mu      sync.Mutex
+	batches [][]redis.Increment
+	written chan struct{}
+	block   chan struct{}
+}
+
+func newRecordingBatchWriter() *recordingBatchWriter {
+	return &recordingBatchWriter{written: make(chan struct{}, 16)}
+}
+
+func (w *recordingBatchWriter) IncrBatch(ctx context.Context, increments []redis.Increment) error {
+	if w.block != nil {
+		<-w.block
+	}
+	w.mu.Lock()
+	w.batches = append(w.batches, append([]redis.Increment(nil), increments...))
+	w.mu.Unlock()
+	w.written <- struct{}{}
+	return nil
+}
+
+func (w *recordingBatchWriter) snapshot() [][]redis.Increment {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([][]redis.Increment(nil), w.batches...)
+}
+
+// droppedCount 读取丢弃计数指标
+func droppedCount(t *testing.T) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := statsBufferDropped.Write(&metric); err != nil {
+		t.Fatalf("读取指标失败: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestStatsBuffer_FlushMergesIncrementsIntoBatches(t *testing.T) {
+	writer := newRecordingBatchWriter()
+	buffer := NewStatsBuffer(writer, StatsBufferConfig{Size: 100, BatchSize: 2, FlushInterval: time.Hour}, logrus.New())
+
+	buffer.Incr("stats:votes:daily:2026-10-15", 7*24*time.Hour)
+	buffer.Incr("stats:user:votes:1", 0)
+	buffer.Incr("stats:votes:daily:2026-10-15", 7*24*time.Hour)
+	buffer.Incr("stats:prediction:votes:9", 0)
+
+	buffer.Flush(context.Background())
+
+	batches := writer.snapshot()
+	if len(batches) != 2 {
+		t.Fatalf("pipeline 次数 = %d, want 2: %+v", len(batches), batches)
+	}
+	first := batches[0]
+	if len(first) != 2 || first[0].Key != "stats:votes:daily:2026-10-15" || first[0].Delta != 2 || first[0].TTL != 7*24*time.Hour {
+		t.Errorf("第一批应合并相同键: %+v", first)
+	}
+	if first[1].Key != "stats:user:votes:1" || first[1].Delta != 1 {
+		t.Errorf("第一批第二个键 = %+v", first[1])
+	}
+	if second := batches[1]; len(second) != 1 || second[0].Key != "stats:prediction:votes:9" {
+		t.Errorf("第二批 = %+v", second)
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", buffer.Len())
+	}
+}
+
+func TestStatsBuffer_RunFlushesWhenBatchIsFull(t *testing.T) {
+	writer := newRecordingBatchWriter()
+	buffer := NewStatsBuffer(writer, StatsBufferConfig{Size: 100, BatchSize: 3, FlushInterval: time.Hour}, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go buffer.Run(ctx)
+
+	for _, key := range []string{"a", "b", "c"} {
+		buffer.Incr(key, 0)
+	}
+
+	select {
+	case <-writer.written:
+	case <-time.After(2 * time.Second):
+		t.Fatal("积压达到批量大小后应立即写入，而不是等待定时间隔")
+	}
+	if batches := writer.snapshot(); len(batches[0]) != 3 {
+		t.Errorf("batch = %+v, want 3 keys", batches[0])
+	}
+}
+
+func TestStatsBuffer_OverflowDropsOldestWithoutBlocking(t *testing.T) {
+	writer := newRecordingBatchWriter()
+	writer.block = make(chan struct{})
+	buffer := NewStatsBuffer(writer, StatsBufferConfig{Size: 2, BatchSize: 100, FlushInterval: time.Hour}, logrus.New())
+	before := droppedCount(t)
+
+	// Redis 写入阻塞时登记增量仍立即返回
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, key := range []string{"oldest", "middle", "newest"} {
+			buffer.Incr(key, 0)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("缓冲区已满时 Incr 不应阻塞")
+	}
+
+	if got := droppedCount(t); got != before+1 {
+		t.Errorf("dropped = %v, want %v", got, before+1)
+	}
+
+	close(writer.block)
+	buffer.Flush(context.Background())
+	batches := writer.snapshot()
+	if len(batches) != 1 || len(batches[0]) != 2 || batches[0][0].Key != "middle" || batches[0][1].Key != "newest" {
+		t.Errorf("应丢弃最早的增量，实际写入 %+v", batches)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"backend-go/internal/adapters/events/types"
 	"backend-go/internal/core/domain/shared"
 	"backend-go/pkg/redis"
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,39 +14,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Event payload types to avoid import cycles
-type UserLoggedInPayload struct {
-	UserID      uint   `json:"user_id"`
-	Username    string `json:"username"`
-	LoginMethod string `json:"login_method"`
-	LoginSource string `json:"login_source"`
-	LoginCount  int    `json:"login_count"`
-}
-
-type PredictionCreatedPayload struct {
-	PredictionID     uint          `json:"prediction_id"`
-	UserID           uint          `json:"user_id"`
-	MatchID          uint          `json:"match_id"`
-	Tournament       string        `json:"tournament"`
-	TimeToMatchStart time.Duration `json:"time_to_match_start"`
-}
-
-type VoteCastPayload struct {
-	VoteID       uint `json:"vote_id"`
-	UserID       uint `json:"user_id"`
-	PredictionID uint `json:"prediction_id"`
-	VoterID      uint `json:"voter_id"`
-	NewVoteCount int  `json:"new_vote_count"`
-}
-
-type ErrorEncounteredPayload struct {
-	UserID       uint   `json:"user_id"`
-	ErrorType    string `json:"error_type"`
-	ErrorCode    string `json:"error_code"`
-	ErrorMessage string `json:"error_message"`
-	Severity     string `json:"severity"`
-}
-
 // Prometheus 指标定义
 var (
 	// 事件计数器
@@ -161,15 +129,15 @@ func (c *MetricsCollector) Handle(event shared.Event) error {
 
 	// 根据事件类型更新特定指标
 	switch event.GetType() {
-	case "user.registered":
+	case types.UserRegistered.String():
 		c.handleUserRegisteredMetrics(event)
-	case "user.logged_in":
+	case types.UserLoggedIn.String():
 		c.handleUserLoginMetrics(event)
-	case "prediction.created":
+	case types.PredictionCreated.String():
 		c.handlePredictionCreatedMetrics(event)
-	case "vote.cast":
+	case types.VoteCast.String():
 		c.handleVoteCastMetrics(event)
-	case "error.encountered":
+	case types.ErrorEncountered.String():
 		c.handleErrorMetrics(event)
 	}
 
@@ -218,8 +186,8 @@ func (c *MetricsCollector) handleUserRegisteredMetrics(event shared.Event) {
 
 // handleUserLoginMetrics 处理用户登录指标
 func (c *MetricsCollector) handleUserLoginMetrics(event shared.Event) {
-	payload, ok := event.GetPayload().(*UserLoggedInPayload)
-	if !ok {
+	payload, err := types.UserLoggedIn.Payload(event)
+	if err != nil {
 		return
 	}
 
@@ -264,8 +232,8 @@ func (c *MetricsCollector) handleUserLoginMetrics(event shared.Event) {
 
 // handlePredictionCreatedMetrics 处理预测创建指标
 func (c *MetricsCollector) handlePredictionCreatedMetrics(event shared.Event) {
-	payload, ok := event.GetPayload().(*PredictionCreatedPayload)
-	if !ok {
+	payload, err := types.PredictionCreated.Payload(event)
+	if err != nil {
 		return
 	}
 
@@ -296,8 +264,8 @@ func (c *MetricsCollector) handlePredictionCreatedMetrics(event shared.Event) {
 
 // handleVoteCastMetrics 处理投票指标
 func (c *MetricsCollector) handleVoteCastMetrics(event shared.Event) {
-	payload, ok := event.GetPayload().(*VoteCastPayload)
-	if !ok {
+	payload, err := types.VoteCast.Payload(event)
+	if err != nil {
 		return
 	}
 
@@ -330,8 +298,8 @@ func (c *MetricsCollector) handleVoteCastMetrics(event shared.Event) {
 
 // handleErrorMetrics 处理错误指标
 func (c *MetricsCollector) handleErrorMetrics(event shared.Event) {
-	payload, ok := event.GetPayload().(*ErrorEncounteredPayload)
-	if !ok {
+	payload, err := types.ErrorEncountered.Payload(event)
+	if err != nil {
 		return
 	}
 
@@ -0,0 +1,58 @@
+// Package outbox implements a transactional outbox for reliable event publishing.
+//
+// Events are written to the event_outbox table in the same database
+// transaction as the entity change that produces them, so a crash between the
+// commit and the publish can no longer lose an event. A Relay later publishes
+// pending rows to the event bus and marks them dispatched, giving at-least-once
+// delivery: a row is re-published after a restart until it has been marked.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend-go/internal/core/domain/shared"
+	"gorm.io/gorm"
+)
+
+// Message 待投递的事件记录
+type Message struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	EventType    string     `json:"event_type" gorm:"size:100;not null;index"`
+	Payload      string     `json:"payload" gorm:"type:text;not null"`
+	Attempts     int        `json:"attempts" gorm:"not null;default:0"`
+	LastError    string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DispatchedAt *time.Time `json:"dispatched_at,omitempty" gorm:"index"`
+}
+
+// TableName 指定表名
+func (Message) TableName() string {
+	return "event_outbox"
+}
+
+// Enqueue 在 tx 所在的事务中写入一条待投递事件，需与实体写入使用同一个 tx
+func Enqueue(tx *gorm.DB, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	message := &Message{EventType: eventType, Payload: string(data)}
+	if err := tx.Create(message).Error; err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+	return nil
+}
+
+// Event 由发件箱投递的事件，MessageID 可供订阅方对重复投递去重
+type Event struct {
+	shared.BaseEvent
+	MessageID uint `json:"message_id"`
+}
+
+// GetMessageID 返回发件箱记录ID
+func (e *Event) GetMessageID() uint {
+	return e.MessageID
+}
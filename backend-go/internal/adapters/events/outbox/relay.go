@@ -0,0 +1,247 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend-go/internal/core/domain/shared"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// PayloadDecoder 将存储的 JSON 载荷还原为订阅方期望的类型
+type PayloadDecoder func(eventType string, payload string) (interface{}, error)
+
+// RelayConfig 发件箱中继配置
+type RelayConfig struct {
+	Interval  time.Duration // 轮询间隔，默认 5 秒
+	BatchSize int           // 每批投递的记录数，默认 100
+	Retention time.Duration // 已投递记录的保留时间，默认 7 天，超过后清理
+}
+
+// pruneInterval 清理已投递记录的最短间隔，避免每次轮询都执行删除
+const pruneInterval = time.Hour
+
+// RelayResult 单次投递结果
+type RelayResult struct {
+	Dispatched int `json:"dispatched"`
+	Failed     int `json:"failed"`
+}
+
+// Heartbeat 每次投递后上报心跳，services.WorkerHeartbeat.Job 的返回值满足该接口
+type Heartbeat interface {
+	Beat(ctx context.Context)
+}
+
+// Relay 将发件箱中未投递的事件发布到事件总线
+//
+// 发布成功后才标记为已投递；进程在发布与标记之间退出时，
+// 重启后会再次发布该事件，订阅方可按 MessageID 去重。
+type Relay struct {
+	db     *gorm.DB
+	bus    shared.EventBus
+	decode PayloadDecoder
+	config RelayConfig
+	logger *logrus.Logger
+
+	heartbeat  Heartbeat
+	lastPruned time.Time
+}
+
+// NewRelay 创建发件箱中继，decode 为空时载荷按通用 JSON 解码
+func NewRelay(db *gorm.DB, bus shared.EventBus, decode PayloadDecoder, config RelayConfig, logger *logrus.Logger) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Retention <= 0 {
+		config.Retention = 7 * 24 * time.Hour
+	}
+	if decode == nil {
+		decode = decodeGenericPayload
+	}
+	return &Relay{
+		db:     db,
+		bus:    bus,
+		decode: decode,
+		config: config,
+		logger: logger,
+	}
+}
+
+// SetHeartbeat 设置每次投递后上报的心跳
+func (r *Relay) SetHeartbeat(heartbeat Heartbeat) {
+	r.heartbeat = heartbeat
+}
+
+// Interval 返回轮询间隔
+func (r *Relay) Interval() time.Duration {
+	return r.config.Interval
+}
+
+// Run 按配置的间隔持续投递，直到 ctx 结束
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := r.DispatchPending(ctx)
+			r.pruneIfDue(ctx)
+			if r.heartbeat != nil {
+				r.heartbeat.Beat(ctx)
+			}
+			if err != nil {
+				r.logger.WithError(err).Warn("Failed to relay outbox events")
+				continue
+			}
+			if result.Dispatched > 0 || result.Failed > 0 {
+				r.logger.WithFields(logrus.Fields{
+					"dispatched": result.Dispatched,
+					"failed":     result.Failed,
+				}).Info("Outbox events relayed")
+			}
+		}
+	}
+}
+
+// DispatchPending 按写入顺序投递一批未投递的事件
+//
+// 单条事件发布失败时记录错误并继续投递其余事件，失败的事件留待下次重试。
+func (r *Relay) DispatchPending(ctx context.Context) (RelayResult, error) {
+	var result RelayResult
+
+	var messages []Message
+	err := r.db.WithContext(ctx).
+		Where("dispatched_at IS NULL").
+		Order("id ASC").
+		Limit(r.config.BatchSize).
+		Find(&messages).Error
+	if err != nil {
+		return result, fmt.Errorf("failed to load outbox messages: %w", err)
+	}
+
+	for _, message := range messages {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := r.publish(message); err != nil {
+			result.Failed++
+			r.recordFailure(ctx, message, err)
+			continue
+		}
+
+		// 仅标记仍未投递的记录，避免并发中继重复计数
+		now := time.Now()
+		err := r.db.WithContext(ctx).
+			Model(&Message{}).
+			Where("id = ? AND dispatched_at IS NULL", message.ID).
+			Updates(map[string]interface{}{"dispatched_at": now, "attempts": gorm.Expr("attempts + 1")}).Error
+		if err != nil {
+			return result, fmt.Errorf("failed to mark outbox message %d dispatched: %w", message.ID, err)
+		}
+		result.Dispatched++
+	}
+
+	return result, nil
+}
+
+// pruneIfDue 距上次清理超过 pruneInterval 时清理过期的已投递记录
+func (r *Relay) pruneIfDue(ctx context.Context) {
+	now := time.Now()
+	if now.Sub(r.lastPruned) < pruneInterval {
+		return
+	}
+	r.lastPruned = now
+
+	deleted, err := r.PruneDispatched(ctx, now.Add(-r.config.Retention))
+	if err != nil {
+		r.logger.WithError(err).Warn("Failed to prune dispatched outbox events")
+		return
+	}
+	if deleted > 0 {
+		r.logger.WithField("deleted", deleted).Info("Dispatched outbox events pruned")
+	}
+}
+
+// PruneDispatched 分批删除 before 之前已投递的记录，未投递的记录不受影响
+//
+// 每批先按主键取出 BatchSize 条再删除，避免一次大范围删除长时间锁表。
+func (r *Relay) PruneDispatched(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		var ids []uint
+		err := r.db.WithContext(ctx).
+			Model(&Message{}).
+			Where("dispatched_at IS NOT NULL AND dispatched_at < ?", before).
+			Order("id ASC").
+			Limit(r.config.BatchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return total, fmt.Errorf("failed to load dispatched outbox messages: %w", err)
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&Message{})
+		if result.Error != nil {
+			return total, fmt.Errorf("failed to delete dispatched outbox messages: %w", result.Error)
+		}
+		total += result.RowsAffected
+		if len(ids) < r.config.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+// publish 解码载荷并发布到事件总线
+func (r *Relay) publish(message Message) error {
+	payload, err := r.decode(message.EventType, message.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	return r.bus.Publish(&Event{
+		BaseEvent: shared.BaseEvent{
+			Type:      message.EventType,
+			Payload:   payload,
+			Timestamp: message.CreatedAt,
+		},
+		MessageID: message.ID,
+	})
+}
+
+// recordFailure 记录投递失败次数与最新错误
+func (r *Relay) recordFailure(ctx context.Context, message Message, cause error) {
+	r.logger.WithError(cause).WithFields(logrus.Fields{
+		"message_id": message.ID,
+		"event_type": message.EventType,
+		"attempts":   message.Attempts + 1,
+	}).Warn("Failed to publish outbox event")
+
+	err := r.db.WithContext(ctx).
+		Model(&Message{}).
+		Where("id = ?", message.ID).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": cause.Error()}).Error
+	if err != nil {
+		r.logger.WithError(err).WithField("message_id", message.ID).Error("Failed to record outbox failure")
+	}
+}
+
+// decodeGenericPayload 将载荷解码为通用 JSON 值
+func decodeGenericPayload(eventType string, payload string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
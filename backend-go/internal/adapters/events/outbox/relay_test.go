@@ -0,0 +1,242 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain/shared"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newOutboxTestDB 创建内存数据库并建表
+func newOutboxTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&Message{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	return db
+}
+
+// recordingBus 记录已发布的事件，failUserID 对应的事件发布失败
+type recordingBus struct {
+	shared.EventBus
+	published  []*Event
+	failUserID float64
+}
+
+func (b *recordingBus) Publish(event shared.Event) error {
+	payload, _ := event.GetPayload().(map[string]interface{})
+	if b.failUserID != 0 && payload["user_id"] == b.failUserID {
+		return errors.New("bus unavailable")
+	}
+	b.published = append(b.published, event.(*Event))
+	return nil
+}
+
+func newTestRelay(db *gorm.DB, bus shared.EventBus) *Relay {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewRelay(db, bus, nil, RelayConfig{BatchSize: 10}, log)
+}
+
+func enqueue(t *testing.T, db *gorm.DB, userID int) {
+	t.Helper()
+	if err := Enqueue(db, "vote.cast", map[string]int{"user_id": userID}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+}
+
+func pendingCount(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&Message{}).Where("dispatched_at IS NULL").Count(&count).Error; err != nil {
+		t.Fatalf("统计待投递事件失败: %v", err)
+	}
+	return count
+}
+
+func TestEnqueue_FollowsTransaction(t *testing.T) {
+	db := newOutboxTestDB(t)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := Enqueue(tx, "vote.cast", map[string]int{"user_id": 1}); err != nil {
+			return err
+		}
+		return errors.New("entity write failed")
+	})
+	if err == nil {
+		t.Fatal("Transaction() error = nil, want rollback")
+	}
+	if got := pendingCount(t, db); got != 0 {
+		t.Fatalf("回滚后 pending = %d, want 0", got)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return Enqueue(tx, "vote.cast", map[string]int{"user_id": 2})
+	})
+	if err != nil {
+		t.Fatalf("Transaction() error = %v", err)
+	}
+
+	var message Message
+	if err := db.First(&message).Error; err != nil {
+		t.Fatalf("读取事件失败: %v", err)
+	}
+	if message.EventType != "vote.cast" || message.Payload != `{"user_id":2}` || message.DispatchedAt != nil {
+		t.Errorf("message = %+v, want pending vote.cast for user 2", message)
+	}
+}
+
+func TestRelay_DispatchPending(t *testing.T) {
+	ctx := context.Background()
+	db := newOutboxTestDB(t)
+	for userID := 1; userID <= 3; userID++ {
+		enqueue(t, db, userID)
+	}
+
+	bus := &recordingBus{}
+	result, err := newTestRelay(db, bus).DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("DispatchPending() error = %v", err)
+	}
+	if result.Dispatched != 3 || result.Failed != 0 {
+		t.Errorf("result = %+v, want 3 dispatched", result)
+	}
+	for i, event := range bus.published {
+		if event.MessageID != uint(i+1) || event.GetType() != "vote.cast" {
+			t.Errorf("published[%d] = %+v, want message %d in order", i, event, i+1)
+		}
+	}
+	if got := pendingCount(t, db); got != 0 {
+		t.Errorf("pending = %d, want 0", got)
+	}
+
+	// 已标记的事件在重启后不再投递
+	restarted := &recordingBus{}
+	result, err = newTestRelay(db, restarted).DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("DispatchPending() after restart error = %v", err)
+	}
+	if result.Dispatched != 0 || len(restarted.published) != 0 {
+		t.Errorf("重启后 result = %+v, published = %d, want nothing", result, len(restarted.published))
+	}
+}
+
+func TestRelay_RedispatchesUnmarkedAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	db := newOutboxTestDB(t)
+	enqueue(t, db, 1)
+	enqueue(t, db, 2)
+
+	if _, err := newTestRelay(db, &recordingBus{}).DispatchPending(ctx); err != nil {
+		t.Fatalf("DispatchPending() error = %v", err)
+	}
+
+	// 模拟发布成功但标记前进程退出
+	if err := db.Model(&Message{}).Where("id = ?", 2).Update("dispatched_at", nil).Error; err != nil {
+		t.Fatalf("重置投递状态失败: %v", err)
+	}
+
+	bus := &recordingBus{}
+	result, err := newTestRelay(db, bus).DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("DispatchPending() error = %v", err)
+	}
+	if result.Dispatched != 1 || len(bus.published) != 1 || bus.published[0].MessageID != 2 {
+		t.Fatalf("result = %+v, published = %+v, want message 2 re-dispatched once", result, bus.published)
+	}
+
+	var message Message
+	if err := db.First(&message, 2).Error; err != nil {
+		t.Fatalf("读取事件失败: %v", err)
+	}
+	if message.DispatchedAt == nil || message.Attempts != 2 {
+		t.Errorf("message = %+v, want dispatched after 2 attempts", message)
+	}
+}
+
+func TestRelay_KeepsFailedMessagesPending(t *testing.T) {
+	ctx := context.Background()
+	db := newOutboxTestDB(t)
+	enqueue(t, db, 1)
+	enqueue(t, db, 2)
+	enqueue(t, db, 3)
+
+	bus := &recordingBus{failUserID: 2}
+	relay := newTestRelay(db, bus)
+	result, err := relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("DispatchPending() error = %v", err)
+	}
+	if result.Dispatched != 2 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 2 dispatched 1 failed", result)
+	}
+
+	var failed Message
+	if err := db.First(&failed, 2).Error; err != nil {
+		t.Fatalf("读取事件失败: %v", err)
+	}
+	if failed.DispatchedAt != nil || failed.Attempts != 1 || failed.LastError == "" {
+		t.Errorf("failed = %+v, want pending with 1 attempt and error", failed)
+	}
+
+	// 总线恢复后重试成功
+	bus.failUserID = 0
+	result, err = relay.DispatchPending(ctx)
+	if err != nil {
+		t.Fatalf("DispatchPending() retry error = %v", err)
+	}
+	if result.Dispatched != 1 || pendingCount(t, db) != 0 {
+		t.Errorf("retry result = %+v, want failed message dispatched", result)
+	}
+}
+
+func TestRelay_PruneDispatched(t *testing.T) {
+	ctx := context.Background()
+	db := newOutboxTestDB(t)
+	relay := newTestRelay(db, &recordingBus{})
+
+	for i := 1; i <= 25; i++ {
+		enqueue(t, db, i)
+	}
+	// 前 23 条早已投递，第 24 条刚投递，第 25 条尚未投递
+	old := time.Now().Add(-8 * 24 * time.Hour)
+	if err := db.Model(&Message{}).Where("id <= ?", 23).Update("dispatched_at", old).Error; err != nil {
+		t.Fatalf("更新投递时间失败: %v", err)
+	}
+	if err := db.Model(&Message{}).Where("id = ?", 24).Update("dispatched_at", time.Now()).Error; err != nil {
+		t.Fatalf("更新投递时间失败: %v", err)
+	}
+
+	deleted, err := relay.PruneDispatched(ctx, time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("PruneDispatched() error = %v", err)
+	}
+	if deleted != 23 {
+		t.Errorf("deleted = %d, want 23 across batches of 10", deleted)
+	}
+
+	var remaining []uint
+	if err := db.Model(&Message{}).Order("id ASC").Pluck("id", &remaining).Error; err != nil {
+		t.Fatalf("读取发件箱失败: %v", err)
+	}
+	if len(remaining) != 2 || remaining[0] != 24 || remaining[1] != 25 {
+		t.Errorf("remaining = %v, want [24 25]", remaining)
+	}
+}
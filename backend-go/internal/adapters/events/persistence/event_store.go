@@ -36,6 +36,8 @@ type EventStore interface {
 	MarkAsProcessed(ctx context.Context, eventID string) error
 	MarkAsFailed(ctx context.Context, eventID string, errorMessage string) error
 	GetFailedEvents(ctx context.Context, limit int) ([]EventRecord, error)
+	GetDeadLetters(ctx context.Context, eventType string, limit int) ([]EventRecord, error)
+	GetDeadLettersByIDs(ctx context.Context, eventType string, eventIDs []string) ([]EventRecord, error)
 	ReplayEvents(ctx context.Context, eventType string, start, end time.Time) ([]shared.Event, error)
 }
 
@@ -217,6 +219,44 @@ func (s *MySQLEventStore) GetFailedEvents(ctx context.Context, limit int) ([]Eve
 	return records, nil
 }
 
+// GetDeadLetters 获取死信事件（处理失败的事件，不限重试次数），可按类型过滤
+func (s *MySQLEventStore) GetDeadLetters(ctx context.Context, eventType string, limit int) ([]EventRecord, error) {
+	var records []EventRecord
+
+	query := s.db.WithContext(ctx).Where("status = ?", "failed")
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Order("created_at ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get dead letter events: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetDeadLettersByIDs 按事件ID获取死信事件，可按类型过滤，不在死信中的ID被忽略
+func (s *MySQLEventStore) GetDeadLettersByIDs(ctx context.Context, eventType string, eventIDs []string) ([]EventRecord, error) {
+	var records []EventRecord
+	if len(eventIDs) == 0 {
+		return records, nil
+	}
+
+	query := s.db.WithContext(ctx).Where("status = ? AND event_id IN ?", "failed", eventIDs)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	if err := query.Order("created_at ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get dead letter events by id: %w", err)
+	}
+
+	return records, nil
+}
+
 // ReplayEvents 重放事件
 func (s *MySQLEventStore) ReplayEvents(ctx context.Context, eventType string, start, end time.Time) ([]shared.Event, error) {
 	var records []EventRecord
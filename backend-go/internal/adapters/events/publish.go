@@ -0,0 +1,19 @@
+package events
+
+import (
+	"backend-go/internal/adapters/events/types"
+	"backend-go/internal/core/domain/shared"
+)
+
+// Publish 发布用户行为事件，载荷类型由 eventType 在编译期约束
+func Publish[T any](bus shared.EventBus, eventType types.EventType[T], userID uint, payload *T) error {
+	return bus.Publish(NewUserBehaviorEvent(eventType.String(), userID, payload))
+}
+
+// PublishEvent 发布运行时构造的事件，载荷类型与注册的不一致时拒绝发布
+func PublishEvent(bus shared.EventBus, event shared.Event) error {
+	if err := types.Validate(event); err != nil {
+		return err
+	}
+	return bus.Publish(event)
+}
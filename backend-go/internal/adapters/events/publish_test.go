@@ -0,0 +1,109 @@
+package events
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/events/types"
+	"backend-go/internal/core/domain/shared"
+	pkgEvents "backend-go/pkg/events"
+)
+
+// predictionRecorder 按注册的载荷类型取出预测创建事件
+type predictionRecorder struct {
+	payloads []*types.PredictionCreatedPayload
+	errs     []error
+}
+
+func (r *predictionRecorder) Handle(event shared.Event) error {
+	payload, err := types.PredictionCreated.Payload(event)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return err
+	}
+	r.payloads = append(r.payloads, payload)
+	return nil
+}
+
+func newRecordingBus(t *testing.T) (shared.EventBus, *predictionRecorder) {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	bus := pkgEvents.NewSyncEventBus(log)
+	recorder := &predictionRecorder{}
+	if err := bus.Subscribe(EventPredictionCreated, recorder); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	return bus, recorder
+}
+
+func TestPublish_HandlersReceiveTypedPayload(t *testing.T) {
+	bus, recorder := newRecordingBus(t)
+
+	// 载荷类型由 types.PredictionCreated 约束，传入其他载荷无法通过编译
+	err := Publish(bus, types.PredictionCreated, 7, &types.PredictionCreatedPayload{PredictionID: 3, UserID: 7, MatchID: 11})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(recorder.errs) != 0 {
+		t.Fatalf("handler errors = %v", recorder.errs)
+	}
+	if len(recorder.payloads) != 1 || recorder.payloads[0].PredictionID != 3 || recorder.payloads[0].MatchID != 11 {
+		t.Errorf("payloads = %+v, want prediction 3 on match 11", recorder.payloads)
+	}
+}
+
+func TestPublishEvent_RejectsWrongPayloadType(t *testing.T) {
+	bus, recorder := newRecordingBus(t)
+
+	event := NewUserBehaviorEvent(EventPredictionCreated, 7, &types.VoteCastPayload{PredictionID: 3})
+	if err := PublishEvent(bus, event); !errors.Is(err, types.ErrInvalidPayload) {
+		t.Fatalf("PublishEvent() error = %v, want ErrInvalidPayload", err)
+	}
+	if len(recorder.payloads)+len(recorder.errs) != 0 {
+		t.Error("载荷类型错误的事件不应投递给处理器")
+	}
+
+	// 未经校验直接发布时，处理器取载荷同样会报告类型错误
+	if err := bus.Publish(event); !errors.Is(err, types.ErrInvalidPayload) {
+		t.Errorf("Publish() error = %v, want ErrInvalidPayload", err)
+	}
+
+	valid := NewUserBehaviorEvent(EventPredictionCreated, 7, &types.PredictionCreatedPayload{PredictionID: 3})
+	if err := PublishEvent(bus, valid); err != nil {
+		t.Errorf("PublishEvent(valid) error = %v", err)
+	}
+}
+
+func TestDecodeReplayPayload_UsesRegisteredPayloads(t *testing.T) {
+	// 统计处理器订阅的事件都必须注册载荷类型，重放时才能还原为处理器期望的类型
+	for _, eventType := range statisticsEventTypes {
+		if _, ok := types.NewPayload(eventType); !ok {
+			t.Errorf("event type %s has no registered payload", eventType)
+		}
+	}
+
+	value, err := DecodeReplayPayload(EventPredictionCreated, `{"prediction_id":3,"user_id":7,"match_id":11}`)
+	if err != nil {
+		t.Fatalf("DecodeReplayPayload() error = %v", err)
+	}
+	payload, err := types.PredictionCreated.Payload(NewUserBehaviorEvent(EventPredictionCreated, 7, value))
+	if err != nil {
+		t.Fatalf("Payload() error = %v", err)
+	}
+	if payload.PredictionID != 3 || payload.MatchID != 11 {
+		t.Errorf("payload = %+v, want prediction 3 on match 11", payload)
+	}
+
+	generic, err := DecodeReplayPayload("custom.event", `{"a":1}`)
+	if err != nil {
+		t.Fatalf("DecodeReplayPayload(custom) error = %v", err)
+	}
+	if _, ok := generic.(map[string]interface{}); !ok {
+		t.Errorf("generic payload = %T, want map", generic)
+	}
+}
@@ -0,0 +1,175 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend-go/internal/adapters/events/persistence"
+	"backend-go/internal/core/domain/shared"
+	"github.com/sirupsen/logrus"
+)
+
+// PayloadDecoder 将存储的 JSON 载荷还原为处理器期望的类型
+type PayloadDecoder func(eventType string, payload string) (interface{}, error)
+
+// DeadLetterOptions 死信重放选项
+type DeadLetterOptions struct {
+	EventType string   `json:"event_type,omitempty"`
+	EventIDs  []string `json:"event_ids,omitempty"` // 为空时重放全部匹配的死信
+	Limit     int      `json:"limit"`               // 指定 EventIDs 时不生效
+	DryRun    bool     `json:"dry_run"`
+}
+
+// DeadLetterEntry 死信事件摘要
+type DeadLetterEntry struct {
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	RetryCount   int       `json:"retry_count"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// DeadLetterResult 死信重放结果
+type DeadLetterResult struct {
+	DryRun   bool              `json:"dry_run"`
+	Selected []DeadLetterEntry `json:"selected"`
+	Replayed []string          `json:"replayed"`
+	Failed   map[string]string `json:"failed"` // 事件ID -> 错误信息
+}
+
+// DeadLetterReplayer 死信事件重放器
+//
+// 重放成功的事件标记为已处理并移出死信；仍然失败的事件保留在死信中，
+// 重试次数加一并记录最新错误。
+type DeadLetterReplayer struct {
+	eventStore persistence.EventStore
+	eventBus   shared.EventBus
+	decode     PayloadDecoder
+	logger     *logrus.Logger
+}
+
+// NewDeadLetterReplayer 创建死信重放器，decode 为空时载荷按通用 JSON 解码
+func NewDeadLetterReplayer(
+	eventStore persistence.EventStore,
+	eventBus shared.EventBus,
+	decode PayloadDecoder,
+	logger *logrus.Logger,
+) *DeadLetterReplayer {
+	if decode == nil {
+		decode = decodeGenericPayload
+	}
+	return &DeadLetterReplayer{
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		decode:     decode,
+		logger:     logger,
+	}
+}
+
+// List 列出死信事件
+func (r *DeadLetterReplayer) List(ctx context.Context, eventType string, limit int) ([]DeadLetterEntry, error) {
+	records, err := r.eventStore.GetDeadLetters(ctx, eventType, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]DeadLetterEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, toDeadLetterEntry(record))
+	}
+	return entries, nil
+}
+
+// Replay 重放选中的死信事件，DryRun 时只返回将被重放的事件
+func (r *DeadLetterReplayer) Replay(ctx context.Context, options DeadLetterOptions) (*DeadLetterResult, error) {
+	selected, err := r.selectDeadLetters(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeadLetterResult{
+		DryRun:   options.DryRun,
+		Selected: make([]DeadLetterEntry, 0, len(selected)),
+		Replayed: make([]string, 0),
+		Failed:   make(map[string]string),
+	}
+	for _, record := range selected {
+		result.Selected = append(result.Selected, toDeadLetterEntry(record))
+	}
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	for _, record := range selected {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := r.replayRecord(record); err != nil {
+			result.Failed[record.EventID] = err.Error()
+			if markErr := r.eventStore.MarkAsFailed(ctx, record.EventID, err.Error()); markErr != nil {
+				r.logger.WithError(markErr).WithField("event_id", record.EventID).Error("Failed to mark dead letter as failed")
+			}
+			continue
+		}
+
+		if err := r.eventStore.MarkAsProcessed(ctx, record.EventID); err != nil {
+			// 已重放但未能移出死信，记为失败避免误报
+			result.Failed[record.EventID] = fmt.Sprintf("replayed but failed to remove from dead letters: %v", err)
+			continue
+		}
+		result.Replayed = append(result.Replayed, record.EventID)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"selected": len(result.Selected),
+		"replayed": len(result.Replayed),
+		"failed":   len(result.Failed),
+	}).Info("Dead letter replay completed")
+
+	return result, nil
+}
+
+// replayRecord 还原事件并通过事件总线重新分发
+func (r *DeadLetterReplayer) replayRecord(record persistence.EventRecord) error {
+	payload, err := r.decode(record.EventType, record.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	event := &shared.BaseEvent{
+		Type:      record.EventType,
+		Payload:   payload,
+		Timestamp: record.CreatedAt,
+	}
+	return r.eventBus.Publish(event)
+}
+
+// selectDeadLetters 加载待重放的死信，指定事件ID时直接按ID查询，不受 Limit 限制
+func (r *DeadLetterReplayer) selectDeadLetters(ctx context.Context, options DeadLetterOptions) ([]persistence.EventRecord, error) {
+	if len(options.EventIDs) > 0 {
+		return r.eventStore.GetDeadLettersByIDs(ctx, options.EventType, options.EventIDs)
+	}
+	return r.eventStore.GetDeadLetters(ctx, options.EventType, options.Limit)
+}
+
+func toDeadLetterEntry(record persistence.EventRecord) DeadLetterEntry {
+	return DeadLetterEntry{
+		EventID:      record.EventID,
+		EventType:    record.EventType,
+		RetryCount:   record.RetryCount,
+		ErrorMessage: record.ErrorMessage,
+		CreatedAt:    record.CreatedAt,
+	}
+}
+
+func decodeGenericPayload(eventType string, payload string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
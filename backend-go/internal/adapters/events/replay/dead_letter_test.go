@@ -0,0 +1,186 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"backend-go/internal/adapters/events/persistence"
+	"backend-go/internal/core/domain/shared"
+	pkgEvents "backend-go/pkg/events"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeEventStore 内存事件存储，仅实现死信相关方法
+type fakeEventStore struct {
+	persistence.EventStore
+	records []*persistence.EventRecord
+}
+
+func (s *fakeEventStore) GetDeadLetters(ctx context.Context, eventType string, limit int) ([]persistence.EventRecord, error) {
+	var result []persistence.EventRecord
+	for _, record := range s.records {
+		if record.Status != "failed" || (eventType != "" && record.EventType != eventType) {
+			continue
+		}
+		result = append(result, *record)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *fakeEventStore) GetDeadLettersByIDs(ctx context.Context, eventType string, eventIDs []string) ([]persistence.EventRecord, error) {
+	wanted := make(map[string]bool, len(eventIDs))
+	for _, id := range eventIDs {
+		wanted[id] = true
+	}
+	var result []persistence.EventRecord
+	for _, record := range s.records {
+		if record.Status == "failed" && wanted[record.EventID] && (eventType == "" || record.EventType == eventType) {
+			result = append(result, *record)
+		}
+	}
+	return result, nil
+}
+
+func (s *fakeEventStore) MarkAsProcessed(ctx context.Context, eventID string) error {
+	s.find(eventID).Status = "processed"
+	return nil
+}
+
+func (s *fakeEventStore) MarkAsFailed(ctx context.Context, eventID string, errorMsg string) error {
+	record := s.find(eventID)
+	record.Status = "failed"
+	record.RetryCount++
+	record.ErrorMessage = errorMsg
+	return nil
+}
+
+func (s *fakeEventStore) find(eventID string) *persistence.EventRecord {
+	for _, record := range s.records {
+		if record.EventID == eventID {
+			return record
+		}
+	}
+	return nil
+}
+
+// failingHandler 载荷 user_id 为 0 时处理失败
+type failingHandler struct {
+	handled int
+}
+
+func (h *failingHandler) Handle(event shared.Event) error {
+	h.handled++
+	payload, _ := event.GetPayload().(map[string]interface{})
+	if payload["user_id"] == float64(0) {
+		return errors.New("invalid user")
+	}
+	return nil
+}
+
+func newTestReplayer() (*DeadLetterReplayer, *fakeEventStore, *failingHandler) {
+	store := &fakeEventStore{records: []*persistence.EventRecord{
+		{EventID: "vote_1", EventType: "vote.cast", Payload: `{"user_id":1}`, Status: "failed", RetryCount: 3, CreatedAt: time.Now()},
+		{EventID: "vote_2", EventType: "vote.cast", Payload: `{"user_id":0}`, Status: "failed", RetryCount: 3, CreatedAt: time.Now()},
+		{EventID: "vote_3", EventType: "vote.cast", Payload: `{"user_id":3}`, Status: "failed", RetryCount: 3, CreatedAt: time.Now()},
+		{EventID: "login_1", EventType: "user.logged_in", Payload: `{"user_id":1}`, Status: "failed", RetryCount: 3, CreatedAt: time.Now()},
+		{EventID: "vote_4", EventType: "vote.cast", Payload: `{"user_id":4}`, Status: "processed", CreatedAt: time.Now()},
+	}}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	handler := &failingHandler{}
+	bus := pkgEvents.NewSyncEventBus(logger)
+	bus.Subscribe("vote.cast", handler)
+	bus.Subscribe("user.logged_in", handler)
+
+	return NewDeadLetterReplayer(store, bus, nil, logger), store, handler
+}
+
+func TestDeadLetterReplayer_ReplaySubset(t *testing.T) {
+	replayer, store, handler := newTestReplayer()
+
+	result, err := replayer.Replay(context.Background(), DeadLetterOptions{
+		EventType: "vote.cast",
+		EventIDs:  []string{"vote_1", "vote_2"},
+		Limit:     100,
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if len(result.Selected) != 2 || handler.handled != 2 {
+		t.Fatalf("选中 %d 个、分发 %d 次, want 2/2", len(result.Selected), handler.handled)
+	}
+	if len(result.Replayed) != 1 || result.Replayed[0] != "vote_1" {
+		t.Errorf("Replayed = %v, want [vote_1]", result.Replayed)
+	}
+	if _, ok := result.Failed["vote_2"]; !ok || len(result.Failed) != 1 {
+		t.Errorf("Failed = %v, want vote_2", result.Failed)
+	}
+
+	// 成功的移出死信，失败的保留并累加重试次数，未选中的不受影响
+	if got := store.find("vote_1").Status; got != "processed" {
+		t.Errorf("vote_1 状态 = %s, want processed", got)
+	}
+	if failed := store.find("vote_2"); failed.Status != "failed" || failed.RetryCount != 4 || failed.ErrorMessage == "" {
+		t.Errorf("vote_2 = %+v, 应保留在死信中且重试次数为 4", failed)
+	}
+	if store.find("vote_3").Status != "failed" || store.find("login_1").Status != "failed" {
+		t.Error("未选中的死信不应被重放")
+	}
+
+	remaining, _ := replayer.List(context.Background(), "", 100)
+	if len(remaining) != 3 {
+		t.Errorf("剩余死信 = %d, want 3", len(remaining))
+	}
+}
+
+func TestDeadLetterReplayer_DryRun(t *testing.T) {
+	replayer, store, handler := newTestReplayer()
+
+	result, err := replayer.Replay(context.Background(), DeadLetterOptions{EventType: "vote.cast", Limit: 100, DryRun: true})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if !result.DryRun || len(result.Selected) != 3 {
+		t.Errorf("DryRun = %v, Selected = %d, want true/3", result.DryRun, len(result.Selected))
+	}
+	if handler.handled != 0 || len(result.Replayed) != 0 {
+		t.Errorf("dry-run 不应分发事件, handled = %d", handler.handled)
+	}
+	for _, record := range store.records[:4] {
+		if record.Status != "failed" || record.RetryCount != 3 {
+			t.Errorf("dry-run 不应修改存储: %+v", record)
+		}
+	}
+}
+
+func TestDeadLetterReplayer_IDsIgnoreLimit(t *testing.T) {
+	replayer, _, _ := newTestReplayer()
+
+	// vote_3 排在第一页之外，指定ID时仍应被选中
+	result, err := replayer.Replay(context.Background(), DeadLetterOptions{
+		EventIDs: []string{"vote_3", "login_1", "vote_4"},
+		Limit:    1,
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	var ids []string
+	for _, entry := range result.Selected {
+		ids = append(ids, entry.EventID)
+	}
+	if len(ids) != 2 || ids[0] != "vote_3" || ids[1] != "login_1" {
+		t.Errorf("Selected = %v, want [vote_3 login_1]（已处理的 vote_4 不在死信中）", ids)
+	}
+}
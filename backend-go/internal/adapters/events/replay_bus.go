@@ -0,0 +1,53 @@
+package events
+
+import (
+	"encoding/json"
+
+	"backend-go/internal/adapters/events/handlers"
+	"backend-go/internal/adapters/events/monitoring"
+	"backend-go/internal/adapters/events/types"
+	"backend-go/internal/core/domain/shared"
+	pkgEvents "backend-go/pkg/events"
+	"backend-go/pkg/redis"
+	"github.com/sirupsen/logrus"
+)
+
+// statisticsEventTypes 统计处理器订阅的事件类型
+var statisticsEventTypes = []string{
+	EventUserRegistered, EventUserLoggedIn, EventPredictionCreated, EventVoteCast,
+	EventMatchViewed, EventLeaderboardViewed, EventPageViewed, EventFeatureUsed, EventErrorEncountered,
+}
+
+// NewReplayBus 创建用于死信重放的同步事件总线
+//
+// 只订阅统计与指标处理器：事件已在存储中无需再次持久化，通知也不应重复发送。
+// statsBuffer 不为 nil 时统计计数异步批量写入。
+func NewReplayBus(redisClient *redis.Client, statsBuffer *handlers.StatsBuffer, pageViews handlers.PageViewConfig, logger *logrus.Logger) shared.EventBus {
+	bus := pkgEvents.NewSyncEventBus(logger)
+
+	statisticsHandler := handlers.NewStatisticsHandler(redisClient, statsBuffer, pageViews, logger)
+	metricsCollector := monitoring.NewMetricsCollector(redisClient, logger)
+	for _, eventType := range statisticsEventTypes {
+		bus.Subscribe(eventType, statisticsHandler)
+		bus.Subscribe(eventType, metricsCollector)
+	}
+
+	return bus
+}
+
+// DecodeReplayPayload 将存储的载荷解码为事件类型注册的载荷，未注册的类型按通用 JSON 解码
+func DecodeReplayPayload(eventType string, payload string) (interface{}, error) {
+	value, ok := types.NewPayload(eventType)
+	if !ok {
+		var generic interface{}
+		if err := json.Unmarshal([]byte(payload), &generic); err != nil {
+			return nil, err
+		}
+		return generic, nil
+	}
+
+	if err := json.Unmarshal([]byte(payload), value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
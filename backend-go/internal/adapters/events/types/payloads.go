@@ -89,4 +89,58 @@ type ErrorEncounteredPayload struct {
 	ErrorCode    string `json:"error_code"`
 	ErrorMessage string `json:"error_message"`
 	Severity     string `json:"severity"`
-}
\ No newline at end of file
+}
+
+// UserLoggedOutPayload 用户登出事件载荷
+type UserLoggedOutPayload struct {
+	UserID          uint          `json:"user_id"`
+	Username        string        `json:"username"`
+	SessionDuration time.Duration `json:"session_duration"`
+	LoggedOutAt     time.Time     `json:"logged_out_at"`
+}
+
+// UserProfileUpdatedPayload 用户资料更新事件载荷
+type UserProfileUpdatedPayload struct {
+	UserID        uint                   `json:"user_id"`
+	Username      string                 `json:"username"`
+	UpdatedFields []string               `json:"updated_fields"`
+	OldValues     map[string]interface{} `json:"old_values"`
+	NewValues     map[string]interface{} `json:"new_values"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// PredictionUpdatedPayload 预测更新事件载荷
+type PredictionUpdatedPayload struct {
+	PredictionID       uint          `json:"prediction_id"`
+	UserID             uint          `json:"user_id"`
+	MatchID            uint          `json:"match_id"`
+	OldPredictedWinner string        `json:"old_predicted_winner"`
+	NewPredictedWinner string        `json:"new_predicted_winner"`
+	OldPredictedScoreA int           `json:"old_predicted_score_a"`
+	NewPredictedScoreA int           `json:"new_predicted_score_a"`
+	OldPredictedScoreB int           `json:"old_predicted_score_b"`
+	NewPredictedScoreB int           `json:"new_predicted_score_b"`
+	ModificationCount  int           `json:"modification_count"`
+	TimeToMatchStart   time.Duration `json:"time_to_match_start"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+}
+
+// VoteRemovedPayload 取消投票事件载荷
+type VoteRemovedPayload struct {
+	UserID       uint      `json:"user_id"`
+	PredictionID uint      `json:"prediction_id"`
+	MatchID      uint      `json:"match_id"`
+	VoterID      uint      `json:"voter_id"`
+	NewVoteCount int       `json:"new_vote_count"`
+	RemovedAt    time.Time `json:"removed_at"`
+}
+
+// SearchPerformedPayload 搜索执行事件载荷
+type SearchPerformedPayload struct {
+	UserID         uint          `json:"user_id"`
+	SearchQuery    string        `json:"search_query"`
+	SearchType     string        `json:"search_type"` // matches, users, predictions
+	ResultCount    int           `json:"result_count"`
+	SearchDuration time.Duration `json:"search_duration"`
+	PerformedAt    time.Time     `json:"performed_at"`
+}
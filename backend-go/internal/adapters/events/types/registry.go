@@ -0,0 +1,86 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"backend-go/internal/core/domain/shared"
+)
+
+// ErrInvalidPayload 事件载荷与事件类型注册的载荷类型不一致
+var ErrInvalidPayload = errors.New("invalid event payload type")
+
+// EventType 将事件类型字符串与载荷类型绑定
+//
+// 发布方通过 events.Publish 传入 *T，处理方通过 Payload 取出 *T，
+// 载荷类型不一致在编译期即可发现，不再依赖各处重复定义的载荷结构体。
+type EventType[T any] struct {
+	name string
+}
+
+// String 返回事件类型字符串
+func (t EventType[T]) String() string {
+	return t.name
+}
+
+// Payload 取出事件载荷，类型与注册的不一致时返回 ErrInvalidPayload
+func (t EventType[T]) Payload(event shared.Event) (*T, error) {
+	payload, ok := event.GetPayload().(*T)
+	if !ok || payload == nil {
+		return nil, fmt.Errorf("%w: %s expects *%T, got %T", ErrInvalidPayload, t.name, *new(T), event.GetPayload())
+	}
+	return payload, nil
+}
+
+// registry 事件类型到载荷构造函数的映射
+var registry = map[string]func() interface{}{}
+
+// register 注册事件类型，重复注册视为编程错误
+func register[T any](name string) EventType[T] {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("event type %s registered twice", name))
+	}
+	registry[name] = func() interface{} { return new(T) }
+	return EventType[T]{name: name}
+}
+
+// 已注册的用户行为事件
+var (
+	UserRegistered     = register[UserRegisteredPayload]("user.registered")
+	UserLoggedIn       = register[UserLoggedInPayload]("user.logged_in")
+	UserLoggedOut      = register[UserLoggedOutPayload]("user.logged_out")
+	UserProfileUpdated = register[UserProfileUpdatedPayload]("user.profile_updated")
+	PredictionCreated  = register[PredictionCreatedPayload]("prediction.created")
+	PredictionUpdated  = register[PredictionUpdatedPayload]("prediction.updated")
+	VoteCast           = register[VoteCastPayload]("vote.cast")
+	VoteRemoved        = register[VoteRemovedPayload]("vote.removed")
+	MatchViewed        = register[MatchViewedPayload]("match.viewed")
+	LeaderboardViewed  = register[LeaderboardViewedPayload]("leaderboard.viewed")
+	RankingChanged     = register[RankingChangedPayload]("ranking.changed")
+	PageViewed         = register[PageViewedPayload]("page.viewed")
+	FeatureUsed        = register[FeatureUsedPayload]("feature.used")
+	ErrorEncountered   = register[ErrorEncounteredPayload]("error.encountered")
+	SearchPerformed    = register[SearchPerformedPayload]("search.performed")
+)
+
+// NewPayload 创建事件类型注册的空载荷，用于反序列化；未注册的类型返回 false
+func NewPayload(eventType string) (interface{}, bool) {
+	newPayload, ok := registry[eventType]
+	if !ok {
+		return nil, false
+	}
+	return newPayload(), true
+}
+
+// Validate 检查事件载荷是否为注册的类型，未注册的事件类型不做限制
+func Validate(event shared.Event) error {
+	newPayload, ok := registry[event.GetType()]
+	if !ok {
+		return nil
+	}
+	if want := reflect.TypeOf(newPayload()); reflect.TypeOf(event.GetPayload()) != want {
+		return fmt.Errorf("%w: %s expects %s, got %T", ErrInvalidPayload, event.GetType(), want, event.GetPayload())
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,8 +9,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/admin"
 	"backend-go/internal/core/ports"
+	"backend-go/pkg/ctxkeys"
 	"backend-go/pkg/middleware"
 	"backend-go/pkg/response"
 )
@@ -217,6 +220,18 @@ func (h *AdminHandler) ListAdmins(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Admins retrieved successfully", result)
 }
 
+// CleanupOrphanedAdmins 清理用户已被删除的管理员记录
+func (h *AdminHandler) CleanupOrphanedAdmins(c *gin.Context) {
+	removed, err := h.adminService.CleanupOrphanedAdmins(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to cleanup orphaned admins")
+		response.Error(c, http.StatusInternalServerError, "Failed to cleanup orphaned admins", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Orphaned admins cleaned up successfully", gin.H{"removed": removed})
+}
+
 // GrantPermissions 授予权限
 func (h *AdminHandler) GrantPermissions(c *gin.Context) {
 	userIDStr := c.Param("id")
@@ -313,6 +328,34 @@ func (h *AdminHandler) GrantSportAccess(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Sport access granted successfully", nil)
 }
 
+// GrantSportAccessBulk 为多个管理员批量授予运动类型访问权限
+func (h *AdminHandler) GrantSportAccessBulk(c *gin.Context) {
+	var req struct {
+		UserIDs    []uint `json:"user_ids" binding:"required,min=1"`
+		SportTypes []uint `json:"sport_types" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	// 服务层从上下文读取调用者并逐个校验管理权限
+	ctx := c.Request.Context()
+	if _, ok := ctxkeys.AdminUserIDFrom(ctx); !ok {
+		currentUserID, _ := middleware.GetCurrentUserID(c)
+		ctx = ctxkeys.WithAdminUserID(ctx, currentUserID)
+	}
+
+	result, err := h.adminService.GrantSportAccessBulk(ctx, req.UserIDs, req.SportTypes)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to grant sport access in bulk")
+		response.Error(c, http.StatusForbidden, err.Error(), "PERMISSION_DENIED")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Bulk sport access processed", result)
+}
+
 // RevokeSportAccess 撤销运动类型访问权限
 func (h *AdminHandler) RevokeSportAccess(c *gin.Context) {
 	userIDStr := c.Param("id")
@@ -405,3 +448,61 @@ func (h *AdminHandler) checkPermissionManagementAccess(c *gin.Context, targetUse
 
 	return nil
 }
+
+// SaveView 保存当前管理员的查询视图
+func (h *AdminHandler) SaveView(c *gin.Context) {
+	var req ports.SaveViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	currentUserID, _ := middleware.GetCurrentUserID(c)
+	view, err := h.adminService.SaveView(c.Request.Context(), currentUserID, &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.Error(c, http.StatusBadRequest, "Invalid view", err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to save view")
+		response.Error(c, http.StatusInternalServerError, "Failed to save view", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "View saved successfully", view)
+}
+
+// ListViews 获取当前管理员保存的查询视图，可按 resource 过滤
+func (h *AdminHandler) ListViews(c *gin.Context) {
+	currentUserID, _ := middleware.GetCurrentUserID(c)
+	views, err := h.adminService.ListViews(c.Request.Context(), currentUserID, c.Query("resource"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list views")
+		response.Error(c, http.StatusInternalServerError, "Failed to list views", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Views retrieved successfully", views)
+}
+
+// DeleteView 删除当前管理员的查询视图
+func (h *AdminHandler) DeleteView(c *gin.Context) {
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid view ID", err.Error())
+		return
+	}
+
+	currentUserID, _ := middleware.GetCurrentUserID(c)
+	if err := h.adminService.DeleteView(c.Request.Context(), currentUserID, uint(viewID)); err != nil {
+		if errors.Is(err, domain.ErrResourceNotFound) {
+			response.Error(c, http.StatusNotFound, "View not found", err.Error())
+			return
+		}
+		h.logger.WithError(err).Error("Failed to delete view")
+		response.Error(c, http.StatusInternalServerError, "Failed to delete view", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "View deleted successfully", nil)
+}
@@ -1,12 +1,14 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/ports"
 	"backend-go/pkg/response"
 )
@@ -56,6 +58,10 @@ func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
 
 	result, err := h.adminAuditService.ListAuditLogs(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.Error(c, http.StatusBadRequest, "Invalid request parameters", err.Error())
+			return
+		}
 		h.logger.WithError(err).Error("Failed to list audit logs")
 		response.Error(c, http.StatusInternalServerError, "Failed to list audit logs", err.Error())
 		return
@@ -74,6 +80,10 @@ func (h *AuditHandler) GetAuditStats(c *gin.Context) {
 
 	stats, err := h.adminAuditService.GetAuditStats(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			response.Error(c, http.StatusBadRequest, "Invalid request parameters", err.Error())
+			return
+		}
 		h.logger.WithError(err).Error("Failed to get audit stats")
 		response.Error(c, http.StatusInternalServerError, "Failed to get audit stats", err.Error())
 		return
@@ -6,6 +6,7 @@ import (
 
 	"backend-go/internal/core/ports"
 	"backend-go/internal/core/types"
+	"backend-go/pkg/request"
 	"backend-go/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -157,6 +158,9 @@ func (h *ScoringRuleHandler) DeleteScoringRule(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Scoring rule deleted successfully", nil)
 }
 
+// scoringRuleSortFields 积分规则列表允许的排序字段，与 ports.ListScoringRulesRequest.OrderBy 一致
+var scoringRuleSortFields = []string{"name", "created_at"}
+
 // ListScoringRules 获取积分规则列表
 // @Summary 获取积分规则列表
 // @Description 获取积分规则列表，支持分页和过滤
@@ -166,12 +170,14 @@ func (h *ScoringRuleHandler) DeleteScoringRule(c *gin.Context) {
 // @Param sport_type_id query int false "运动类型ID"
 // @Param is_active query bool false "是否激活"
 // @Param order_by query string false "排序字段" Enums(name, created_at)
+// @Param sort_order query string false "排序方向，未指定时升序" Enums(asc, desc)
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
 // @Success 200 {object} response.Response{data=ports.ListScoringRulesResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Router /api/v1/admin/scoring-rules [get]
 func (h *ScoringRuleHandler) ListScoringRules(c *gin.Context) {
 	var req ports.ListScoringRulesRequest
@@ -187,38 +193,16 @@ func (h *ScoringRuleHandler) ListScoringRules(c *gin.Context) {
 		req.SportTypeID = &sportTypeIDUint
 	}
 
-	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
-		isActive, err := strconv.ParseBool(isActiveStr)
-		if err != nil {
-			response.Error(c, http.StatusBadRequest, "Invalid is_active parameter", err.Error())
-			return
-		}
-		req.IsActive = &isActive
-	}
-
-	req.OrderBy = c.Query("order_by")
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		page, err := strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			response.Error(c, http.StatusBadRequest, "Invalid page parameter", err.Error())
-			return
-		}
-		req.Page = page
-	} else {
-		req.Page = 1
-	}
-
-	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
-		pageSize, err := strconv.Atoi(pageSizeStr)
-		if err != nil || pageSize < 1 || pageSize > 100 {
-			response.Error(c, http.StatusBadRequest, "Invalid page_size parameter", err.Error())
-			return
-		}
-		req.PageSize = pageSize
-	} else {
-		req.PageSize = 20
+	query, err := request.ParseListQuery(c, scoringRuleSortFields)
+	if err != nil {
+		response.HandleError(c, err)
+		return
 	}
+	req.IsActive = query.IsActive
+	req.OrderBy = query.SortBy
+	req.SortOrder = query.SortOrder
+	req.Page = query.Page
+	req.PageSize = query.PageSize
 
 	result, err := h.scoringRuleService.ListScoringRules(c.Request.Context(), &req)
 	if err != nil {
@@ -359,3 +343,47 @@ func (h *ScoringRuleHandler) RecalculateScores(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Scores recalculated successfully", result)
 }
+
+// SimulateRuleRequest 积分规则模拟请求
+type SimulateRuleRequest struct {
+	Tournament string `form:"tournament" binding:"omitempty,oneof=SPRING SUMMER WORLDS GLOBAL"`
+}
+
+// SimulateRule 模拟积分规则
+// @Summary 模拟积分规则
+// @Description 使用候选积分规则重算已结束比赛的积分，返回模拟排名及与当前排名的差异，不修改任何数据
+// @Tags admin-scoring-rules
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "积分规则ID"
+// @Param tournament query string false "赛事类型，为空或 GLOBAL 时统计全部赛事" Enums(SPRING,SUMMER,WORLDS,GLOBAL)
+// @Success 200 {object} response.Response{data=ports.SimulationResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/scoring-rules/{id}/simulate [get]
+func (h *ScoringRuleHandler) SimulateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid scoring rule ID", err.Error())
+		return
+	}
+
+	var req SimulateRuleRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	result, err := h.scoringRuleService.SimulateRule(c.Request.Context(), uint(id), req.Tournament)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"id":         id,
+			"tournament": req.Tournament,
+		}).Error("Failed to simulate scoring rule")
+		response.Error(c, http.StatusInternalServerError, "Failed to simulate scoring rule", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Scoring rule simulated successfully", result)
+}
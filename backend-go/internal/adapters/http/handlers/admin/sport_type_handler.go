@@ -1,11 +1,14 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"backend-go/internal/core/ports"
 	"backend-go/internal/shared/response"
+	"backend-go/pkg/request"
+	pkgResponse "backend-go/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -36,6 +39,7 @@ func NewSportTypeHandler(sportTypeService ports.SportTypeService, logger *logrus
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/admin/sport-types [post]
 func (h *SportTypeHandler) CreateSportType(c *gin.Context) {
@@ -49,7 +53,7 @@ func (h *SportTypeHandler) CreateSportType(c *gin.Context) {
 	sportType, err := h.sportTypeService.CreateSportType(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to create sport type")
-		response.Error(c, http.StatusInternalServerError, "CREATE_FAILED", "Failed to create sport type", err)
+		h.respondServiceError(c, "CREATE_FAILED", "Failed to create sport type", err)
 		return
 	}
 
@@ -100,6 +104,7 @@ func (h *SportTypeHandler) GetSportType(c *gin.Context) {
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /api/v1/admin/sport-types/{id} [put]
 func (h *SportTypeHandler) UpdateSportType(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -118,7 +123,7 @@ func (h *SportTypeHandler) UpdateSportType(c *gin.Context) {
 	sportType, err := h.sportTypeService.UpdateSportType(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		h.logger.WithError(err).WithField("id", id).Error("Failed to update sport type")
-		response.Error(c, http.StatusInternalServerError, "UPDATE_FAILED", "Failed to update sport type", err)
+		h.respondServiceError(c, "UPDATE_FAILED", "Failed to update sport type", err)
 		return
 	}
 
@@ -137,6 +142,7 @@ func (h *SportTypeHandler) UpdateSportType(c *gin.Context) {
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /api/v1/admin/sport-types/{id} [delete]
 func (h *SportTypeHandler) DeleteSportType(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -148,13 +154,16 @@ func (h *SportTypeHandler) DeleteSportType(c *gin.Context) {
 	err = h.sportTypeService.DeleteSportType(c.Request.Context(), uint(id))
 	if err != nil {
 		h.logger.WithError(err).WithField("id", id).Error("Failed to delete sport type")
-		response.Error(c, http.StatusInternalServerError, "DELETE_FAILED", "Failed to delete sport type", err)
+		h.respondServiceError(c, "DELETE_FAILED", "Failed to delete sport type", err)
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Sport type deleted successfully", nil)
 }
 
+// sportTypeSortFields 运动类型列表允许的排序字段，与 ports.ListSportTypesRequest.OrderBy 一致
+var sportTypeSortFields = []string{"name", "code", "sort_order", "created_at"}
+
 // ListSportTypes 获取运动类型列表
 // @Summary 获取运动类型列表
 // @Description 获取运动类型列表，支持分页和过滤
@@ -164,50 +173,30 @@ func (h *SportTypeHandler) DeleteSportType(c *gin.Context) {
 // @Param category query string false "运动类别" Enums(esports, traditional)
 // @Param is_active query bool false "是否启用"
 // @Param order_by query string false "排序字段" Enums(name, code, sort_order, created_at)
+// @Param sort_order query string false "排序方向，未指定时升序" Enums(asc, desc)
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
 // @Success 200 {object} response.Response{data=ports.ListSportTypesResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Router /api/v1/admin/sport-types [get]
 func (h *SportTypeHandler) ListSportTypes(c *gin.Context) {
 	var req ports.ListSportTypesRequest
 
 	// 解析查询参数
-	req.Category = c.Query("category")
-	req.OrderBy = c.Query("order_by")
-
-	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
-		isActive, err := strconv.ParseBool(isActiveStr)
-		if err != nil {
-			response.Error(c, http.StatusBadRequest, "INVALID_PARAM", "Invalid is_active parameter", err)
-			return
-		}
-		req.IsActive = &isActive
-	}
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		page, err := strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			response.Error(c, http.StatusBadRequest, "INVALID_PARAM", "Invalid page parameter", err)
-			return
-		}
-		req.Page = page
-	} else {
-		req.Page = 1
-	}
-
-	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
-		pageSize, err := strconv.Atoi(pageSizeStr)
-		if err != nil || pageSize < 1 || pageSize > 100 {
-			response.Error(c, http.StatusBadRequest, "INVALID_PARAM", "Invalid page_size parameter", err)
-			return
-		}
-		req.PageSize = pageSize
-	} else {
-		req.PageSize = 20
+	query, err := request.ParseListQuery(c, sportTypeSortFields)
+	if err != nil {
+		pkgResponse.HandleError(c, err)
+		return
 	}
+	req.Category = c.Query("category")
+	req.OrderBy = query.SortBy
+	req.SortOrder = query.SortOrder
+	req.IsActive = query.IsActive
+	req.Page = query.Page
+	req.PageSize = query.PageSize
 
 	result, err := h.sportTypeService.ListSportTypes(c.Request.Context(), &req)
 	if err != nil {
@@ -347,4 +336,14 @@ func (h *SportTypeHandler) GetSportTypeStats(c *gin.Context) {
 	}
 
 	response.Success(c, http.StatusOK, "Sport type stats retrieved successfully", stats)
-}
\ No newline at end of file
+}
+
+// respondServiceError 业务错误按其状态码返回，其余按500处理
+func (h *SportTypeHandler) respondServiceError(c *gin.Context, code string, message string, err error) {
+	var appErr *pkgResponse.AppError
+	if errors.As(err, &appErr) {
+		response.Error(c, appErr.StatusCode, appErr.Code, appErr.Message, appErr.Details)
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, code, message, err)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/response"
+)
+
+// AdminMatchHandler 管理员比赛工作流处理器
+type AdminMatchHandler struct {
+	finisher *services.MatchFinishService
+	audit    middleware.ImpersonationAuditLogger
+	logger   *logrus.Logger
+}
+
+// NewAdminMatchHandler 创建管理员比赛工作流处理器，audit 为空时只记录日志
+func NewAdminMatchHandler(finisher *services.MatchFinishService, audit middleware.ImpersonationAuditLogger, logger *logrus.Logger) *AdminMatchHandler {
+	return &AdminMatchHandler{finisher: finisher, audit: audit, logger: logger}
+}
+
+// FinishMatch 设置比赛结果、结束比赛并为全部预测计分
+// @Summary 结束比赛并计分
+// @Description 校验比赛结果后结束比赛，为全部预测计分并刷新排行榜，返回计分汇总。比赛已按相同结果结束时不做修改；已按其他结果结束时返回 409。操作写入审计日志
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "比赛ID"
+// @Param result body match.SetResultRequest true "比赛结果"
+// @Success 200 {object} response.Response{data=services.MatchFinishSummary}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/matches/{id}/finish [post]
+func (h *AdminMatchHandler) FinishMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid match ID")
+		return
+	}
+
+	var req match.SetResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	start := time.Now()
+	summary, err := h.finisher.Finish(c.Request.Context(), uint(id), &req)
+	h.auditFinish(c, uint(id), &req, summary, err, time.Since(start))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrMatchNotFound):
+			response.NotFound(c, "Match")
+		case errors.Is(err, domain.ErrInvalidScore):
+			response.BadRequest(c, "Invalid score")
+		case errors.Is(err, domain.ErrInvalidWinner):
+			response.BadRequest(c, "Invalid winner")
+		case errors.Is(err, domain.ErrInvalidMatchStatus):
+			response.Error(c, http.StatusConflict, "Cancelled match cannot be finished", err.Error())
+		case errors.Is(err, domain.ErrMatchAlreadyFinished):
+			response.Error(c, http.StatusConflict, "Match already finished with a different result", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to finish match", err.Error())
+		}
+		return
+	}
+
+	message := "Match finished and scored successfully"
+	if summary.AlreadyFinished {
+		message = "Match already finished"
+	}
+	response.Success(c, http.StatusOK, message, summary)
+}
+
+// apiKeyFinishValues 通过 API 密钥结束比赛时写入审计的请求内容，附带调用方密钥
+type apiKeyFinishValues struct {
+	*match.SetResultRequest
+	APIKeyID   uint   `json:"api_key_id"`
+	APIKeyName string `json:"api_key_name"`
+}
+
+// auditFinish 记录结束比赛的操作，失败的请求同样记录
+//
+// 服务间调用没有管理员身份，审计记在创建该 API 密钥的管理员名下，并记录密钥 ID 与名称。
+func (h *AdminMatchHandler) auditFinish(c *gin.Context, matchID uint, req *match.SetResultRequest, summary *services.MatchFinishSummary, finishErr error, duration time.Duration) {
+	operatorID, _ := middleware.GetCurrentUserID(c)
+	fields := logrus.Fields{"match_id": matchID}
+	var newValues interface{} = req
+	if caller, ok := middleware.GetAPIKeyCaller(c); ok {
+		operatorID = caller.CreatedBy
+		fields["api_key_id"] = caller.KeyID
+		fields["api_key_name"] = caller.Name
+		newValues = apiKeyFinishValues{SetResultRequest: req, APIKeyID: caller.KeyID, APIKeyName: caller.Name}
+	}
+	fields["operator_id"] = operatorID
+	entry := &ports.LogActionRequest{
+		AdminUserID: operatorID,
+		Action:      "match.finish",
+		Resource:    "match",
+		ResourceID:  strconv.FormatUint(uint64(matchID), 10),
+		Method:      c.Request.Method,
+		Path:        c.Request.URL.Path,
+		IPAddress:   c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		NewValues:   newValues,
+		Status:      admin.AuditStatusSuccess,
+		Duration:    duration.Milliseconds(),
+	}
+	if finishErr != nil {
+		entry.Status = admin.AuditStatusFailed
+		entry.ErrorMsg = finishErr.Error()
+		h.logger.WithFields(fields).WithError(finishErr).Warn("结束比赛失败")
+	} else {
+		entry.Changes = summary
+		h.logger.WithFields(fields).Info("比赛已结束并计分")
+	}
+
+	if h.audit == nil {
+		return
+	}
+	// 客户端断开不应影响审计写入
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+	defer cancel()
+	if err := h.audit.LogAction(ctx, entry); err != nil {
+		h.logger.WithError(err).Error("Failed to audit match finish")
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/scoring"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/services"
+)
+
+// finishingMatchService 单场进行中的比赛
+type finishingMatchService struct {
+	match.Service
+	match      match.Match
+	setResults int
+}
+
+func (s *finishingMatchService) GetMatch(ctx context.Context, id uint) (*match.Match, error) {
+	if id != s.match.ID {
+		return nil, domain.ErrMatchNotFound
+	}
+	copied := s.match
+	return &copied, nil
+}
+
+func (s *finishingMatchService) SetResult(ctx context.Context, id uint, req *match.SetResultRequest) error {
+	s.setResults++
+	return s.match.SetResult(req.ScoreA, req.ScoreB, req.Winner)
+}
+
+// onceScoringService 只在首次计分时发放积分
+type onceScoringService struct {
+	scoring.Service
+	calculation *scoring.MatchPointsCalculation
+	runs        int
+}
+
+func (s *onceScoringService) CalculateMatchPoints(ctx context.Context, matchID uint) (*scoring.MatchPointsCalculation, error) {
+	if s.calculation == nil {
+		s.runs++
+		s.calculation = &scoring.MatchPointsCalculation{
+			MatchID:     matchID,
+			Results:     []scoring.PointsCalculationResult{{PredictionID: 1, Points: 3}, {PredictionID: 2, Points: 0}},
+			TotalPoints: 3,
+		}
+	}
+	return s.calculation, nil
+}
+
+type noopLeaderboardRefresher struct{}
+
+func (noopLeaderboardRefresher) RefreshLeaderboard(ctx context.Context, tournament string) error {
+	return nil
+}
+
+func newAdminMatchRouter(matches *finishingMatchService, scoringService *onceScoringService, audit *recordingMigrationAudit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	auth := middleware.NewAuthMiddleware(&migrationTokenService{identities: map[string]*user.TokenIdentity{
+		"admin-token": {User: &user.User{ID: 1, Username: "root", Role: user.UserRoleAdmin}},
+		"user-token":  {User: &user.User{ID: 2, Username: "bob", Role: user.UserRoleUser}},
+	}})
+	finisher := services.NewMatchFinishService(matches, scoringService, noopLeaderboardRefresher{}, logger)
+	handler := NewAdminMatchHandler(finisher, audit, logger)
+
+	router := gin.New()
+	group := router.Group("/api/admin", auth.RequireAuth(), auth.RequireAdmin())
+	group.POST("/matches/:id/finish", handler.FinishMatch)
+	return router
+}
+
+func finishMatchRequest(router *gin.Engine, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/matches/5/finish", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeFinishSummary(t *testing.T, w *httptest.ResponseRecorder) services.MatchFinishSummary {
+	t.Helper()
+	var body struct {
+		Data services.MatchFinishSummary `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	return body.Data
+}
+
+func TestAdminMatchHandler_FinishAndRefinish(t *testing.T) {
+	matches := &finishingMatchService{match: match.Match{ID: 5, Status: match.MatchStatusLive}}
+	scoringService := &onceScoringService{}
+	audit := &recordingMigrationAudit{}
+	router := newAdminMatchRouter(matches, scoringService, audit)
+
+	w := finishMatchRequest(router, "admin-token", `{"score_a":3,"score_b":1,"winner":"A"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body = %s", w.Code, w.Body.String())
+	}
+	if summary := decodeFinishSummary(t, w); summary.AlreadyFinished || summary.PredictionsScored != 2 || summary.TotalPoints != 3 {
+		t.Errorf("summary = %+v, want 2 个预测共 3 分", summary)
+	}
+	if matches.match.Status != match.MatchStatusFinished {
+		t.Errorf("match status = %s, want FINISHED", matches.match.Status)
+	}
+
+	// 相同结果再次提交为空操作
+	w = finishMatchRequest(router, "admin-token", `{"score_a":3,"score_b":1,"winner":"A"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("重复提交 status = %d, want 200", w.Code)
+	}
+	if summary := decodeFinishSummary(t, w); !summary.AlreadyFinished || summary.PredictionsScored != 2 {
+		t.Errorf("重复提交 summary = %+v, want AlreadyFinished", summary)
+	}
+	if matches.setResults != 1 || scoringService.runs != 1 {
+		t.Errorf("setResults = %d, scoring runs = %d, want 1/1", matches.setResults, scoringService.runs)
+	}
+
+	// 其他结果被拒绝
+	if w := finishMatchRequest(router, "admin-token", `{"score_a":0,"score_b":2}`); w.Code != http.StatusConflict {
+		t.Errorf("修改结果 status = %d, want 409", w.Code)
+	}
+
+	if len(audit.entries) != 3 {
+		t.Fatalf("audit = %d 条, want 3", len(audit.entries))
+	}
+	first := audit.entries[0]
+	if first.Action != "match.finish" || first.AdminUserID != 1 || first.ResourceID != "5" || first.Status != admin.AuditStatusSuccess {
+		t.Errorf("首次审计 = %+v, want 成功结束比赛 5", first)
+	}
+	if last := audit.entries[2]; last.Status != admin.AuditStatusFailed || last.ErrorMsg == "" {
+		t.Errorf("冲突审计 = %+v, want 记录失败原因", last)
+	}
+}
+
+func TestAdminMatchHandler_RequiresAdmin(t *testing.T) {
+	matches := &finishingMatchService{match: match.Match{ID: 5, Status: match.MatchStatusLive}}
+	audit := &recordingMigrationAudit{}
+	router := newAdminMatchRouter(matches, &onceScoringService{}, audit)
+
+	if w := finishMatchRequest(router, "user-token", `{"score_a":3,"score_b":1}`); w.Code != http.StatusForbidden {
+		t.Errorf("普通用户 status = %d, want 403", w.Code)
+	}
+	if w := finishMatchRequest(router, "", `{"score_a":3,"score_b":1}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("未登录 status = %d, want 401", w.Code)
+	}
+	if matches.setResults != 0 || len(audit.entries) != 0 {
+		t.Errorf("setResults = %d, audit = %d, want 未执行", matches.setResults, len(audit.entries))
+	}
+}
+
+// stubAPIKeyValidator 只接受一个密钥的校验器
+type stubAPIKeyValidator struct {
+	key    string
+	caller *user.APIKeyContext
+}
+
+func (v stubAPIKeyValidator) ValidateAPIKey(ctx context.Context, key string) (*user.APIKeyContext, error) {
+	if key != v.key {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return v.caller, nil
+}
+
+func TestAdminMatchHandler_AuditsAPIKeyCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	matches := &finishingMatchService{match: match.Match{ID: 5, Status: match.MatchStatusLive}}
+	audit := &recordingMigrationAudit{}
+	finisher := services.NewMatchFinishService(matches, &onceScoringService{}, noopLeaderboardRefresher{}, logger)
+	handler := NewAdminMatchHandler(finisher, audit, logger)
+	validator := stubAPIKeyValidator{key: "yk_feed", caller: &user.APIKeyContext{
+		KeyID: 9, Name: "score-feed", Scopes: []string{user.APIScopeMatchesWrite}, CreatedBy: 1,
+	}}
+
+	router := gin.New()
+	router.POST("/api/integrations/matches/:id/finish", middleware.RequireAPIKey(validator, user.APIScopeMatchesWrite), handler.FinishMatch)
+	req := httptest.NewRequest(http.MethodPost, "/api/integrations/matches/5/finish", bytes.NewBufferString(`{"score_a":3,"score_b":1}`))
+	req.Header.Set(middleware.APIKeyHeader, "yk_feed")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body = %s", w.Code, w.Body.String())
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("audit = %d 条, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	if entry.AdminUserID != 1 || entry.Action != "match.finish" || entry.Status != admin.AuditStatusSuccess {
+		t.Errorf("审计 = %+v, want 记在密钥创建者名下", entry)
+	}
+	values, err := json.Marshal(entry.NewValues)
+	if err != nil {
+		t.Fatalf("序列化审计内容失败: %v", err)
+	}
+	var recorded map[string]interface{}
+	if err := json.Unmarshal(values, &recorded); err != nil {
+		t.Fatalf("解析审计内容失败: %v", err)
+	}
+	if recorded["api_key_id"] != float64(9) || recorded["api_key_name"] != "score-feed" || recorded["score_a"] != float64(3) {
+		t.Errorf("审计内容 = %s, want 包含比分与调用方密钥", values)
+	}
+}
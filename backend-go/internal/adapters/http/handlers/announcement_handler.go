@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"backend-go/pkg/request"
 	"backend-go/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -23,6 +24,9 @@ type Announcement struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// announcementSortFields 公告列表允许的排序字段
+var announcementSortFields = []string{"priority", "created_at"}
+
 // TableName 指定表名
 func (Announcement) TableName() string {
 	return "announcements"
@@ -64,28 +68,20 @@ func (h *AnnouncementHandler) GetLatestAnnouncement(c *gin.Context) {
 
 // ListAnnouncements 获取公告列表
 func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	isActive := c.Query("is_active")
-
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
+	params, err := request.ParseListQuery(c, announcementSortFields)
+	if err != nil {
+		response.HandleError(c, err)
+		return
 	}
 
-	offset := (page - 1) * pageSize
-
 	var announcements []Announcement
 	var total int64
 
 	query := h.db.Model(&Announcement{})
 	
 	// 过滤激活状态
-	if isActive != "" {
-		active := isActive == "true"
-		query = query.Where("is_active = ?", active)
+	if params.IsActive != nil {
+		query = query.Where("is_active = ?", *params.IsActive)
 	}
 
 	// 获取总数
@@ -95,10 +91,10 @@ func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
 		return
 	}
 
-	// 获取公告列表，按优先级和创建时间排序
-	if err := query.Order("priority DESC, created_at DESC").
-		Offset(offset).
-		Limit(pageSize).
+	// 获取公告列表，默认按优先级和创建时间排序
+	if err := query.Order(params.OrderClause("priority DESC, created_at DESC")).
+		Offset(params.Offset()).
+		Limit(params.PageSize).
 		Find(&announcements).Error; err != nil {
 		h.logger.WithError(err).Error("获取公告列表失败")
 		response.Error(c, http.StatusInternalServerError, "Failed to get announcements", err.Error())
@@ -108,8 +104,8 @@ func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Announcements retrieved successfully", gin.H{
 		"announcements": announcements,
 		"total":         total,
-		"page":          page,
-		"page_size":     pageSize,
+		"page":          params.Page,
+		"page_size":     params.PageSize,
 	})
 }
 
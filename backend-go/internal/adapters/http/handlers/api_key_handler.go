@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/response"
+)
+
+// apiKeyManager 管理 API 密钥，ports.AuthService 满足该接口
+type apiKeyManager interface {
+	CreateAPIKey(ctx context.Context, adminID uint, name string, scopes []string) (*user.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]*user.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uint) error
+}
+
+// CreateAPIKeyRequest 创建 API 密钥请求
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+// CreatedAPIKey 新建的 API 密钥，明文密钥只返回这一次
+type CreatedAPIKey struct {
+	*user.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyHandler API 密钥管理处理器
+type APIKeyHandler struct {
+	keys apiKeyManager
+}
+
+// NewAPIKeyHandler 创建 API 密钥管理处理器
+func NewAPIKeyHandler(keys apiKeyManager) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+// CreateAPIKey 创建 API 密钥
+// @Summary 创建 API 密钥
+// @Description 为服务间调用（如推送比赛结果的数据源）创建 API 密钥，调用方通过 X-API-Key 请求头认证。明文密钥只在响应中返回一次，请妥善保存
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPIKeyRequest true "密钥名称与权限范围"
+// @Success 201 {object} response.Response{data=CreatedAPIKey}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	adminID, _ := middleware.GetCurrentUserID(c)
+	key, plain, err := h.keys.CreateAPIKey(c.Request.Context(), adminID, req.Name, req.Scopes)
+	if err != nil {
+		writeAPIKeyError(c, err, "创建 API 密钥失败")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "API 密钥已创建，请立即保存，之后无法再次查看", CreatedAPIKey{APIKey: key, Key: plain})
+}
+
+// ListAPIKeys 列出 API 密钥
+// @Summary API 密钥列表
+// @Description 列出全部 API 密钥（含已吊销），不返回明文与哈希
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]user.APIKey}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.keys.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		writeAPIKeyError(c, err, "获取 API 密钥失败")
+		return
+	}
+	response.Success(c, http.StatusOK, "获取 API 密钥成功", keys)
+}
+
+// RevokeAPIKey 吊销 API 密钥
+// @Summary 吊销 API 密钥
+// @Description 吊销后使用该密钥的请求立即返回 401
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "密钥ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID")
+		return
+	}
+
+	if err := h.keys.RevokeAPIKey(c.Request.Context(), uint(id)); err != nil {
+		writeAPIKeyError(c, err, "吊销 API 密钥失败")
+		return
+	}
+	response.Success(c, http.StatusOK, "API 密钥已吊销", nil)
+}
+
+func writeAPIKeyError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*response.AppError); ok {
+		response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+		return
+	}
+	response.InternalError(c, message+": "+err.Error())
+}
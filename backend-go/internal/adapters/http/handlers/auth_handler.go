@@ -8,6 +8,7 @@ import (
 
 	"backend-go/internal/adapters/http/middleware"
 	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
 	"backend-go/internal/shared/logger"
 	"backend-go/pkg/response"
 
@@ -17,12 +18,14 @@ import (
 // AuthHandler 认证处理器
 type AuthHandler struct {
 	userService user.Service
+	authService ports.AuthService
 }
 
 // NewAuthHandler 创建认证处理器
-func NewAuthHandler(userService user.Service) *AuthHandler {
+func NewAuthHandler(userService user.Service, authService ports.AuthService) *AuthHandler {
 	return &AuthHandler{
 		userService: userService,
+		authService: authService,
 	}
 }
 
@@ -98,6 +101,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	newUser, err := h.userService.Register(c.Request.Context(), domainReq)
 	if err != nil {
 		// 根据错误类型返回不同的状态码
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
 		if strings.Contains(err.Error(), "already exists") {
 			response.Error(c, http.StatusConflict, "User already exists", err.Error())
 			return
@@ -242,6 +249,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// 记录登录会话，失败不影响登录
+	if h.authService != nil {
+		if _, err := h.authService.RecordSession(c.Request.Context(), authResp.RefreshToken, c.Request.UserAgent(), c.ClientIP()); err != nil {
+			logger.Warnf("Failed to record session for user %d: %v", authResp.User.ID, err)
+		}
+	}
+
 	// 构造响应
 	resp := LoginResponse{
 		User: RegisterResponse{
@@ -388,3 +402,198 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Logout successful", nil)
 }
+
+// PasswordResetRequest 申请重置密码请求结构
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// ResetPasswordRequest 重置密码请求结构
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+// RequestPasswordReset 申请重置密码
+// @Summary 申请重置密码
+// @Description 向注册邮箱发送重置链接，无论邮箱是否存在均返回成功
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body PasswordResetRequest true "注册邮箱"
+// @Success 200 {object} response.Response "请求已受理"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
+	if h.authService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "密码重置功能未启用", "")
+		return
+	}
+
+	var req PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		logger.Errorf("Failed to request password reset: %v", err)
+	}
+
+	response.Success(c, http.StatusOK, "如果该邮箱已注册，重置链接将发送至邮箱", nil)
+}
+
+// ResetPassword 使用重置令牌设置新密码
+// @Summary 重置密码
+// @Description 校验重置令牌并设置新密码，成功后已签发的令牌全部失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "重置令牌与新密码"
+// @Success 200 {object} response.Response "密码重置成功"
+// @Failure 400 {object} response.Response "令牌无效或密码不符合策略"
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	if h.authService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "密码重置功能未启用", "")
+		return
+	}
+
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "重置密码失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "密码重置成功", nil)
+}
+
+// ImpersonateRequest 代入用户请求结构
+type ImpersonateRequest struct {
+	UserID uint `json:"user_id" binding:"required" example:"42"`
+}
+
+// Impersonate 超级管理员代入用户
+// @Summary 代入用户
+// @Description 超级管理员以目标用户身份获取限时访问令牌，用于复现用户视角；代入期间不能执行管理员操作，所有请求均记录审计日志
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ImpersonateRequest true "目标用户"
+// @Success 200 {object} response.Response "代入令牌"
+// @Failure 403 {object} response.Response "非超级管理员或目标为管理员"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Router /auth/impersonate [post]
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	if h.authService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "代入功能未启用", "")
+		return
+	}
+
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	tokenPair, err := h.authService.Impersonate(c.Request.Context(), adminID, req.UserID)
+	if err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "代入失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "代入令牌已签发", tokenPair)
+}
+
+// ListSessions 列出当前用户的登录会话
+// @Summary 登录会话列表
+// @Description 列出当前用户未过期的登录会话（设备、IP、登录时间、最近活跃时间），按最近活跃时间倒序
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]user.Session} "会话列表"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /users/me/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	if h.authService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "会话管理未启用", "")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "获取会话失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "获取会话成功", sessions)
+}
+
+// RevokeSession 吊销当前用户的登录会话
+// @Summary 吊销登录会话
+// @Description 吊销指定会话，该会话签发的访问令牌与刷新令牌立即失效
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Param jti path string true "会话ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "会话不存在"
+// @Router /users/me/sessions/{jti} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	if h.authService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "会话管理未启用", "")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("jti")); err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "吊销会话失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "会话已吊销", nil)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/services"
+	"backend-go/pkg/response"
+)
+
+// BadgeHandler 用户徽章处理器
+type BadgeHandler struct {
+	badgeService *services.BadgeService
+}
+
+// NewBadgeHandler 创建用户徽章处理器
+func NewBadgeHandler(badgeService *services.BadgeService) *BadgeHandler {
+	return &BadgeHandler{badgeService: badgeService}
+}
+
+// GetUserBadges 获取用户已获得的徽章
+// @Summary 获取用户徽章
+// @Description 根据已结束比赛的预测统计（最长连中、准确率、累计积分）返回用户已获得的徽章
+// @Tags users
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response{data=[]services.Badge}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{id}/badges [get]
+func (h *BadgeHandler) GetUserBadges(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || userID == 0 {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	badges, err := h.badgeService.ComputeBadges(c.Request.Context(), uint(userID))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to get badges", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Badges retrieved successfully", badges)
+}
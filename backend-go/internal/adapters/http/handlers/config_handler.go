@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/config"
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/response"
+)
+
+// ConfigHandler 运行时配置处理器
+type ConfigHandler struct {
+	config *config.Config
+}
+
+// NewConfigHandler 创建运行时配置处理器
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{config: cfg}
+}
+
+// GetEffectiveConfig 获取运行时生效配置
+// @Summary 获取运行时生效配置
+// @Description 返回当前进程实际加载的配置摘要、元数据及选定配置项的来源（env/file/default），敏感值已脱敏
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=object}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/config [get]
+func (h *ConfigHandler) GetEffectiveConfig(c *gin.Context) {
+	effective := config.GetEffectiveConfig(h.config)
+	// 日志级别可在运行时调整，以 logger 当前级别为准，不回写共享配置
+	if level := logger.GetLevel().Level; level != "" {
+		overrideLogLevel(effective, level)
+	}
+	response.Success(c, http.StatusOK, "Effective config retrieved successfully", effective)
+}
+
+// overrideLogLevel 将生效配置中的日志级别替换为运行时级别
+func overrideLogLevel(effective map[string]interface{}, level string) {
+	if summary, ok := effective["summary"].(map[string]interface{}); ok {
+		summary["log_level"] = level
+	}
+	if fields, ok := effective["fields"].([]config.FieldProvenance); ok {
+		for i := range fields {
+			if fields[i].Key == "log.level" {
+				fields[i].Value = level
+			}
+		}
+	}
+}
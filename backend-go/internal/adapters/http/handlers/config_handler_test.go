@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/config"
+	"backend-go/internal/shared/logger"
+)
+
+func TestConfigHandler_ReportsRuntimeLogLevel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.Init("info")
+	if _, err := logger.SetLevel("debug", 0); err != nil {
+		t.Fatalf("SetLevel(debug) error = %v", err)
+	}
+	defer logger.SetLevel("info", 0)
+
+	cfg := &config.Config{}
+	cfg.Log.Level = "info"
+	router := gin.New()
+	router.GET("/config", NewConfigHandler(cfg).GetEffectiveConfig)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/config", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var body struct {
+		Data struct {
+			Summary map[string]interface{}   `json:"summary"`
+			Fields  []config.FieldProvenance `json:"fields"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if body.Data.Summary["log_level"] != "debug" {
+		t.Errorf("summary.log_level = %v, want debug", body.Data.Summary["log_level"])
+	}
+	for _, field := range body.Data.Fields {
+		if field.Key == "log.level" && field.Value != "debug" {
+			t.Errorf("log.level = %v, want debug", field.Value)
+		}
+	}
+	// 运行时级别不回写共享配置
+	if cfg.Log.Level != "info" {
+		t.Errorf("cfg.Log.Level = %q, want info", cfg.Log.Level)
+	}
+}
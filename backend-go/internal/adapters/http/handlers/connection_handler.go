@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/adapters/realtime"
+	"backend-go/pkg/response"
+)
+
+// ConnectionHandler 实时推送连接管理处理器
+type ConnectionHandler struct {
+	hub    *realtime.Hub
+	logger *logrus.Logger
+}
+
+// NewConnectionHandler 创建实时推送连接管理处理器
+func NewConnectionHandler(hub *realtime.Hub, logger *logrus.Logger) *ConnectionHandler {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &ConnectionHandler{hub: hub, logger: logger}
+}
+
+// ListConnections 列出活跃的实时推送连接
+// @Summary 列出活跃的实时推送连接
+// @Description 返回当前所有长连接的用户 ID、建立时间、订阅主题与客户端地址，用于排查滥用
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]realtime.ConnInfo}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/ws/connections [get]
+func (h *ConnectionHandler) ListConnections(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Connections retrieved successfully", h.hub.ListConnections())
+}
+
+// TerminateConnection 终止指定的实时推送连接
+// @Summary 终止实时推送连接
+// @Description 向客户端发送关闭事件并断开连接，同时取消该连接的全部订阅
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "连接 ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/admin/ws/connections/{id}/terminate [post]
+func (h *ConnectionHandler) TerminateConnection(c *gin.Context) {
+	connID := c.Param("id")
+	if err := h.hub.Terminate(connID); err != nil {
+		if errors.Is(err, realtime.ErrConnectionNotFound) {
+			response.NotFound(c, "Connection")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to terminate connection", err.Error())
+		return
+	}
+
+	operatorID, _ := middleware.GetCurrentUserID(c)
+	h.logger.WithFields(logrus.Fields{
+		"connection_id": connID,
+		"operator_id":   operatorID,
+	}).Warn("实时推送连接已被终止")
+
+	response.Success(c, http.StatusOK, "Connection terminated successfully", nil)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/realtime"
+)
+
+func TestConnectionHandler_TerminateClosesStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := realtime.NewHub()
+	streamHandler := NewStreamHandler(hub, &fakeLeaderboardService{}, nil)
+	connectionHandler := NewConnectionHandler(hub, nil)
+
+	router := gin.New()
+	router.GET("/stream/leaderboard/:tournament", streamHandler.StreamLeaderboard)
+	router.GET("/admin/ws/connections", connectionHandler.ListConnections)
+	router.POST("/admin/ws/connections/:id/terminate", connectionHandler.TerminateConnection)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream/leaderboard/SPRING", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	if event, _ := readSSEEvent(t, reader); event != realtime.EventLeaderboardSnapshot {
+		t.Fatalf("首个事件 = %s, want 排行榜快照", event)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ws/connections", nil))
+	var listed struct {
+		Data []realtime.ConnInfo `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("解析连接列表失败: %v", err)
+	}
+	if len(listed.Data) != 1 || len(listed.Data[0].Topics) != 1 || listed.Data[0].Topics[0] != realtime.LeaderboardTopic("SPRING") {
+		t.Fatalf("connections = %+v, want one leaderboard:SPRING subscriber", listed.Data)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/ws/connections/"+listed.Data[0].ID+"/terminate", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("terminate status = %d, want 200", w.Code)
+	}
+
+	if event, _ := readSSEEvent(t, reader); event != realtime.EventConnectionClosed {
+		t.Errorf("终止后事件 = %s, want %s", event, realtime.EventConnectionClosed)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/ws/connections/"+listed.Data[0].ID+"/terminate", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("重复终止 status = %d, want 404", w.Code)
+	}
+}
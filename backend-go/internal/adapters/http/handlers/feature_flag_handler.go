@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/response"
+)
+
+// FeatureFlagHandler 功能开关灰度处理器
+type FeatureFlagHandler struct {
+	flags  *services.FeatureFlags
+	logger *logrus.Logger
+}
+
+// NewFeatureFlagHandler 创建功能开关灰度处理器
+func NewFeatureFlagHandler(flags *services.FeatureFlags, logger *logrus.Logger) *FeatureFlagHandler {
+	return &FeatureFlagHandler{flags: flags, logger: logger}
+}
+
+// SetFeatureFlagRequest 设置功能开关灰度请求
+type SetFeatureFlagRequest struct {
+	Percentage *int   `json:"percentage" binding:"required,min=0,max=100" example:"10"`
+	AllowUsers []uint `json:"allow_users" example:"1,2"`
+	DenyUsers  []uint `json:"deny_users" example:"3"`
+}
+
+// ListFeatureFlags 获取功能开关灰度配置
+// @Summary 获取功能开关灰度配置
+// @Description 返回本实例当前生效的各功能开关灰度比例与用户名单，其他实例在下次加载后与之一致
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]services.FeatureFlagStatus}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/admin/feature-flags [get]
+func (h *FeatureFlagHandler) ListFeatureFlags(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Feature flags retrieved successfully", h.flags.List())
+}
+
+// SetFeatureFlag 设置功能开关灰度配置
+// @Summary 设置功能开关灰度配置
+// @Description 按用户 ID 稳定分桶，percentage 为开启的用户比例；deny_users 中的用户始终关闭，优先于 allow_users
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param flag path string true "功能开关名"
+// @Param request body SetFeatureFlagRequest true "灰度配置"
+// @Success 200 {object} response.Response{data=services.FeatureFlagStatus}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/admin/feature-flags/{flag} [put]
+func (h *FeatureFlagHandler) SetFeatureFlag(c *gin.Context) {
+	var req SetFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	flag := c.Param("flag")
+	rollout := services.FeatureRollout{
+		Percentage: *req.Percentage,
+		AllowUsers: req.AllowUsers,
+		DenyUsers:  req.DenyUsers,
+	}
+	if err := h.flags.SetRollout(c.Request.Context(), flag, rollout); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to update feature flag", err.Error())
+		return
+	}
+
+	operatorID, _ := middleware.GetCurrentUserID(c)
+	h.logger.WithFields(logrus.Fields{
+		"flag":        flag,
+		"percentage":  rollout.Percentage,
+		"operator_id": operatorID,
+	}).Warn("功能开关灰度已调整")
+
+	response.Success(c, http.StatusOK, "Feature flag updated successfully", services.FeatureFlagStatus{Flag: flag, FeatureRollout: rollout})
+}
+
+// DeleteFeatureFlag 删除功能开关灰度配置
+// @Summary 删除功能开关灰度配置
+// @Description 删除后该功能开关对所有用户关闭
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Param flag path string true "功能开关名"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/admin/feature-flags/{flag} [delete]
+func (h *FeatureFlagHandler) DeleteFeatureFlag(c *gin.Context) {
+	flag := c.Param("flag")
+	if err := h.flags.DeleteRollout(c.Request.Context(), flag); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete feature flag", err.Error())
+		return
+	}
+
+	operatorID, _ := middleware.GetCurrentUserID(c)
+	h.logger.WithFields(logrus.Fields{
+		"flag":        flag,
+		"operator_id": operatorID,
+	}).Warn("功能开关灰度已删除")
+
+	response.Success(c, http.StatusOK, "Feature flag deleted successfully", nil)
+}
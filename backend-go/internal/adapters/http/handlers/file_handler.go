@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/response"
+	"backend-go/pkg/storage"
+)
+
+// FileHandler 本地存储的签名地址下载处理器
+type FileHandler struct {
+	storage *storage.LocalStorage
+}
+
+// NewFileHandler 创建文件下载处理器
+func NewFileHandler(local *storage.LocalStorage) *FileHandler {
+	return &FileHandler{storage: local}
+}
+
+// DownloadFile 校验下载令牌后返回文件
+// @Summary 下载文件
+// @Description 本地存储下 storage.SignedURL 生成的限时下载地址，签名覆盖文件键与过期时间
+// @Tags files
+// @Produce octet-stream
+// @Param key path string true "文件键"
+// @Param expires query int true "过期时间（Unix 秒）"
+// @Param signature query string true "下载令牌"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Router /api/files/{key} [get]
+func (h *FileHandler) DownloadFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	path, err := h.storage.Verify(key, c.Query("expires"), c.Query("signature"))
+	switch {
+	case errors.Is(err, storage.ErrInvalidKey):
+		response.BadRequest(c, "Invalid file key")
+		return
+	case errors.Is(err, storage.ErrURLExpired):
+		response.Error(c, http.StatusGone, "Download URL has expired", err.Error())
+		return
+	case err != nil:
+		response.Forbidden(c, "Invalid download signature")
+		return
+	}
+
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		response.NotFound(c, "File")
+		return
+	}
+	c.File(path)
+}
+
+// defaultDownloadURLTTL 未指定 ttl 时签发的下载地址有效期
+const defaultDownloadURLTTL = 15 * time.Minute
+
+// FileDownloadURL 限时下载地址
+type FileDownloadURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FileURLHandler 为文件存储中的文件签发限时下载地址，本地存储与 S3/MinIO 均适用
+type FileURLHandler struct {
+	storage storage.Storage
+	now     func() time.Time
+}
+
+// NewFileURLHandler 创建下载地址处理器
+func NewFileURLHandler(store storage.Storage) *FileURLHandler {
+	return &FileURLHandler{storage: store, now: time.Now}
+}
+
+// GetDownloadURL 签发文件的限时下载地址
+// @Summary 获取文件下载地址
+// @Description 对象存储返回预签名地址，由客户端直接从存储桶下载；本地存储返回带签名令牌的 /api/files 地址。可用于下载数据保留任务写入的归档文件
+// @Tags files
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "文件键"
+// @Param ttl query string false "有效期，如 15m、24h，默认 15m，最长 7 天"
+// @Success 200 {object} response.Response{data=FileDownloadURL}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/admin/files/{key} [get]
+func (h *FileURLHandler) GetDownloadURL(c *gin.Context) {
+	ttl := defaultDownloadURLTTL
+	if raw := c.Query("ttl"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid ttl")
+			return
+		}
+		ttl = parsed
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expiresAt := h.now().Add(ttl)
+	url, err := h.storage.SignedURL(c.Request.Context(), key, ttl)
+	switch {
+	case errors.Is(err, storage.ErrInvalidKey):
+		response.BadRequest(c, "Invalid file key")
+		return
+	case errors.Is(err, storage.ErrInvalidTTL):
+		response.BadRequest(c, "ttl must be positive and at most 7 days")
+		return
+	case err != nil:
+		response.Error(c, http.StatusInternalServerError, "Failed to sign download URL", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Download URL issued", FileDownloadURL{URL: url, ExpiresAt: expiresAt})
+}
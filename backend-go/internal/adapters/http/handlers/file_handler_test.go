@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/response"
+	"backend-go/pkg/storage"
+)
+
+func TestFileHandler_DownloadFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "exports"), 0o755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "exports", "report.csv"), []byte("id,score"), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	local, err := storage.NewLocalStorage(storage.LocalConfig{Dir: dir, SigningKey: "test-signing-key"})
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	router := gin.New()
+	router.GET("/api/files/*key", NewFileHandler(local).DownloadFile)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	signed, err := local.SignedURL(context.Background(), "exports/report.csv", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+	if w := get(signed); w.Code != http.StatusOK || w.Body.String() != "id,score" {
+		t.Fatalf("下载 = %d %q, want 200 文件内容", w.Code, w.Body.String())
+	}
+	if w := get(strings.Replace(signed, "signature=", "signature=0", 1)); w.Code != http.StatusForbidden {
+		t.Errorf("篡改签名 status = %d, want 403", w.Code)
+	}
+
+	missing, _ := local.SignedURL(context.Background(), "exports/missing.csv", time.Minute)
+	if w := get(missing); w.Code != http.StatusNotFound {
+		t.Errorf("文件不存在 status = %d, want 404", w.Code)
+	}
+
+	expired := "/api/files/exports/report.csv?expires=1&signature=" + expiredSignature("exports/report.csv", "1")
+	if w := get(expired); w.Code != http.StatusGone {
+		t.Errorf("已过期 status = %d, want 410", w.Code)
+	}
+}
+
+func TestFileURLHandler_IssuesDownloadableURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "archives"), 0o755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "archives", "batch.json"), []byte(`{"matches":[]}`), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	local, err := storage.NewLocalStorage(storage.LocalConfig{Dir: dir, SigningKey: "test-signing-key"})
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	urls := NewFileURLHandler(local)
+	urls.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.GET("/api/admin/files/*key", urls.GetDownloadURL)
+	router.GET("/api/files/*key", NewFileHandler(local).DownloadFile)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get("/api/admin/files/archives/batch.json?ttl=1h")
+	if w.Code != http.StatusOK {
+		t.Fatalf("签发下载地址 status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		response.Response
+		Data FileDownloadURL `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if !resp.Data.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("ExpiresAt = %v, want %v", resp.Data.ExpiresAt, now.Add(time.Hour))
+	}
+	if download := get(resp.Data.URL); download.Code != http.StatusOK || download.Body.String() != `{"matches":[]}` {
+		t.Errorf("按签发的地址下载 = %d %q, want 200 文件内容", download.Code, download.Body.String())
+	}
+
+	for _, target := range []string{
+		"/api/admin/files/archives/batch.json?ttl=soon",
+		"/api/admin/files/archives/batch.json?ttl=200h",
+		"/api/admin/files/../secret.json",
+	} {
+		if w := get(target); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", target, w.Code)
+		}
+	}
+}
+
+// expiredSignature 按下载令牌格式（文件键与过期时间的 HMAC）为过去的时间签名
+func expiredSignature(key, expires string) string {
+	mac := hmac.New(sha256.New, []byte("test-signing-key"))
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/pkg/response"
+)
+
+// IdempotencyHandler 幂等请求统计处理器
+type IdempotencyHandler struct {
+	idempotency *middleware.Idempotency
+}
+
+// NewIdempotencyHandler 创建幂等请求统计处理器
+func NewIdempotencyHandler(idempotency *middleware.Idempotency) *IdempotencyHandler {
+	return &IdempotencyHandler{idempotency: idempotency}
+}
+
+// GetStats 获取幂等请求命中统计
+// @Summary 获取幂等请求命中统计
+// @Description 返回本实例启动以来携带 Idempotency-Key 的写请求中，重试命中缓存响应（hit）与首次请求（miss）的次数，按路由细分，命中最多的路由排在最前
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=middleware.IdempotencyReport}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/admin/idempotency/stats [get]
+func (h *IdempotencyHandler) GetStats(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Idempotency stats retrieved successfully", h.idempotency.Report())
+}
@@ -81,6 +81,55 @@ func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Leaderboard retrieved successfully", entries)
 }
 
+// GetAccuracyLeaderboardRequest 获取准确率排行榜请求
+type GetAccuracyLeaderboardRequest struct {
+	Tournament     string `form:"tournament" binding:"omitempty,oneof=SPRING SUMMER GLOBAL"`
+	MinPredictions int    `form:"min_predictions" binding:"omitempty,min=1,max=1000"`
+}
+
+// GetAccuracyLeaderboard 获取准确率排行榜
+// @Summary 获取准确率排行榜
+// @Description 按已结束比赛的预测准确率排名，预测数不足 min_predictions 的用户不参与排名
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Param tournament query string false "锦标赛类型" Enums(SPRING,SUMMER,GLOBAL) default(GLOBAL)
+// @Param min_predictions query int false "最少预测数" minimum(1) maximum(1000) default(5)
+// @Success 200 {object} response.Response{data=[]leaderboard.AccuracyEntry}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/leaderboard/accuracy [get]
+func (h *LeaderboardHandler) GetAccuracyLeaderboard(c *gin.Context) {
+	var req GetAccuracyLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.WithError(err).Error("绑定准确率排行榜请求参数失败")
+		response.Error(c, http.StatusBadRequest, "请求参数无效", err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Tournament == "" {
+		req.Tournament = string(leaderboard.TournamentGlobal)
+	}
+	if req.MinPredictions == 0 {
+		req.MinPredictions = leaderboard.DefaultAccuracyMinPredictions
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"tournament":      req.Tournament,
+		"min_predictions": req.MinPredictions,
+	}).Info("获取准确率排行榜")
+
+	entries, err := h.leaderboardService.GetAccuracyLeaderboard(c.Request.Context(), req.Tournament, req.MinPredictions)
+	if err != nil {
+		h.logger.WithError(err).Error("获取准确率排行榜失败")
+		response.Error(c, http.StatusInternalServerError, "获取准确率排行榜失败", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Accuracy leaderboard retrieved successfully", entries)
+}
+
 // GetUserRank 获取用户排名
 // @Summary 获取用户排名
 // @Description 获取指定用户在指定锦标赛中的排名信息
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/response"
+)
+
+// maxLogLevelRevertAfter 自动恢复的最长等待时间
+const maxLogLevelRevertAfter = 24 * time.Hour
+
+// LogLevelHandler 运行时日志级别处理器
+type LogLevelHandler struct {
+	logger *logrus.Logger
+}
+
+// NewLogLevelHandler 创建日志级别处理器
+func NewLogLevelHandler(logger *logrus.Logger) *LogLevelHandler {
+	return &LogLevelHandler{logger: logger}
+}
+
+// SetLogLevelRequest 设置日志级别请求
+type SetLogLevelRequest struct {
+	Level       string `json:"level" binding:"required" example:"debug"`
+	RevertAfter string `json:"revert_after,omitempty" example:"15m"` // 可选，到期后恢复原级别
+}
+
+// GetLogLevel 获取当前日志级别
+// @Summary 获取当前日志级别
+// @Description 返回当前日志级别及待执行的自动恢复信息
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=logger.LevelStatus}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/log-level [get]
+func (h *LogLevelHandler) GetLogLevel(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Log level retrieved successfully", logger.GetLevel())
+}
+
+// SetLogLevel 运行时设置日志级别
+// @Summary 设置日志级别
+// @Description 运行时调整日志级别，可通过 revert_after 指定到期后自动恢复原级别
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SetLogLevelRequest true "日志级别"
+// @Success 200 {object} response.Response{data=logger.LevelStatus}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/log-level [post]
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	var req SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	var revertAfter time.Duration
+	if req.RevertAfter != "" {
+		d, err := time.ParseDuration(req.RevertAfter)
+		if err != nil || d < time.Second || d > maxLogLevelRevertAfter {
+			response.Error(c, http.StatusBadRequest, "Invalid revert_after", "revert_after must be a duration between 1s and 24h")
+			return
+		}
+		revertAfter = d
+	}
+
+	status, err := logger.SetLevel(req.Level, revertAfter)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid log level", err.Error())
+		return
+	}
+
+	operatorID, _ := middleware.GetCurrentUserID(c)
+	h.logger.WithFields(logrus.Fields{
+		"level":        status.Level,
+		"revert_after": req.RevertAfter,
+		"operator_id":  operatorID,
+	}).Warn("日志级别已调整")
+
+	response.Success(c, http.StatusOK, "Log level updated successfully", status)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	pkgMiddleware "backend-go/pkg/middleware"
+	"backend-go/pkg/response"
+)
+
+// MaintenanceHandler 维护模式处理器
+type MaintenanceHandler struct {
+	state  *pkgMiddleware.ProbeState
+	logger *logrus.Logger
+}
+
+// NewMaintenanceHandler 创建维护模式处理器
+func NewMaintenanceHandler(state *pkgMiddleware.ProbeState, logger *logrus.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{state: state, logger: logger}
+}
+
+// SetMaintenanceRequest 设置维护模式请求
+type SetMaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"`
+}
+
+// MaintenanceStatus 维护模式状态
+type MaintenanceStatus struct {
+	Maintenance bool `json:"maintenance"`
+	Started     bool `json:"started"`
+}
+
+// GetMaintenance 获取维护模式状态
+// @Summary 获取维护模式状态
+// @Description 返回当前是否处于维护模式，维护模式下 /readyz 返回 503
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=MaintenanceStatus}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Maintenance status retrieved successfully", h.status())
+}
+
+// SetMaintenance 开启或关闭维护模式
+// @Summary 设置维护模式
+// @Description 开启后 /readyz 返回 503，负载均衡摘除本实例流量，/livez 不受影响
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SetMaintenanceRequest true "维护模式"
+// @Success 200 {object} response.Response{data=MaintenanceStatus}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/maintenance [post]
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	var req SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	h.state.SetMaintenance(*req.Enabled)
+
+	operatorID, _ := middleware.GetCurrentUserID(c)
+	h.logger.WithFields(logrus.Fields{
+		"maintenance": *req.Enabled,
+		"operator_id": operatorID,
+	}).Warn("维护模式已调整")
+
+	response.Success(c, http.StatusOK, "Maintenance mode updated successfully", h.status())
+}
+
+func (h *MaintenanceHandler) status() MaintenanceStatus {
+	return MaintenanceStatus{
+		Maintenance: h.state.InMaintenance(),
+		Started:     h.state.Started(),
+	}
+}
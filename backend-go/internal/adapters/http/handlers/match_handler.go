@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -49,6 +50,8 @@ func (h *MatchHandler) CreateMatch(c *gin.Context) {
 			response.BadRequest(c, "Invalid input")
 		case domain.ErrInvalidStartTime:
 			response.BadRequest(c, "Invalid start time")
+		case domain.ErrInvalidPredictionLock:
+			response.BadRequest(c, "Prediction lock time must be before match start")
 		case domain.ErrInvalidTournament:
 			response.BadRequest(c, "Invalid tournament")
 		default:
@@ -116,19 +119,7 @@ func (h *MatchHandler) ListMatches(c *gin.Context) {
 	}
 
 	if status := c.Query("status"); status != "" {
-		// 兼容前端的 not_started/finished/live 命名
-		switch status {
-		case "not_started", "upcoming":
-			filter.Status = match.MatchStatusUpcoming
-		case "live":
-			filter.Status = match.MatchStatusLive
-		case "finished":
-			filter.Status = match.MatchStatusFinished
-		case "cancelled":
-			filter.Status = match.MatchStatusCancelled
-		default:
-			filter.Status = match.MatchStatus(status)
-		}
+		filter.Status = parseMatchStatusQuery(status)
 	}
 
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
@@ -165,6 +156,108 @@ func (h *MatchHandler) ListMatches(c *gin.Context) {
 	response.OK(c, "Matches retrieved successfully", matches)
 }
 
+// SearchMatches 搜索比赛
+// @Summary 搜索比赛
+// @Description 按选项名称（或 "A vs B" 标题）、赛事、运动类型、状态与开始时间搜索比赛，按开始时间升序键集分页
+// @Tags matches
+// @Produce json
+// @Param q query string false "搜索词"
+// @Param tournament query string false "赛事类型"
+// @Param sport_type_id query int false "运动类型ID"
+// @Param status query string false "比赛状态"
+// @Param start_from query string false "开始时间下限（RFC3339 或 2006-01-02）"
+// @Param start_to query string false "开始时间上限（RFC3339 或 2006-01-02）"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param limit query int false "每页数量，默认 20，最大 100"
+// @Success 200 {object} response.Response{data=match.MatchPage}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/matches/search [get]
+func (h *MatchHandler) SearchMatches(c *gin.Context) {
+	filter := match.MatchSearchFilter{
+		Query:      c.Query("q"),
+		Tournament: match.Tournament(c.Query("tournament")),
+		Cursor:     c.Query("cursor"),
+	}
+
+	if status := c.Query("status"); status != "" {
+		filter.Status = parseMatchStatusQuery(status)
+	}
+
+	if sportTypeStr := c.Query("sport_type_id"); sportTypeStr != "" {
+		sportTypeID, err := strconv.ParseUint(sportTypeStr, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid sport_type_id")
+			return
+		}
+		id := uint(sportTypeID)
+		filter.SportTypeID = &id
+	}
+
+	var err error
+	if filter.StartFrom, err = parseSearchTime(c.Query("start_from"), false); err != nil {
+		response.BadRequest(c, "Invalid start_from")
+		return
+	}
+	if filter.StartTo, err = parseSearchTime(c.Query("start_to"), true); err != nil {
+		response.BadRequest(c, "Invalid start_to")
+		return
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if filter.Limit, err = strconv.Atoi(limitStr); err != nil {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+	}
+
+	page, err := h.matchService.SearchMatches(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearchFilter) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to search matches")
+		return
+	}
+
+	response.OK(c, "Matches retrieved successfully", page)
+}
+
+// parseMatchStatusQuery 解析状态查询参数，兼容前端的 not_started/finished/live 命名
+func parseMatchStatusQuery(status string) match.MatchStatus {
+	switch status {
+	case "not_started", "upcoming":
+		return match.MatchStatusUpcoming
+	case "live":
+		return match.MatchStatusLive
+	case "finished":
+		return match.MatchStatusFinished
+	case "cancelled":
+		return match.MatchStatusCancelled
+	default:
+		return match.MatchStatus(status)
+	}
+}
+
+// parseSearchTime 解析 RFC3339 时间或日期，日期作为上限时取当天结束
+func parseSearchTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
 // UpdateMatch 更新比赛信息
 // @Summary 更新比赛信息
 // @Description 更新比赛信息
@@ -202,6 +295,8 @@ func (h *MatchHandler) UpdateMatch(c *gin.Context) {
 			response.BadRequest(c, "Match already started")
 		case domain.ErrInvalidStartTime:
 			response.BadRequest(c, "Invalid start time")
+		case domain.ErrInvalidPredictionLock:
+			response.BadRequest(c, "Prediction lock time must be before match start")
 		case domain.ErrInvalidTournament:
 			response.BadRequest(c, "Invalid tournament")
 		default:
@@ -389,3 +484,35 @@ func (h *MatchHandler) GetFinishedMatches(c *gin.Context) {
 
 	response.OK(c, "Finished matches retrieved successfully", matches)
 }
+
+// GetTrendingMatches 获取热门比赛
+// @Summary 获取热门比赛
+// @Description 按预测数与浏览量排序的即将开始与进行中的比赛，结果缓存 30 秒
+// @Tags matches
+// @Produce json
+// @Param tournament query string false "赛事，为空时不限"
+// @Param limit query int false "限制数量，默认 10，最大 50"
+// @Success 200 {object} response.Response{data=[]match.TrendingMatch}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/matches/trending [get]
+func (h *MatchHandler) GetTrendingMatches(c *gin.Context) {
+	limit := match.DefaultTrendingLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	matches, err := h.matchService.GetTrendingMatches(c.Request.Context(), c.Query("tournament"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTournament) {
+			response.BadRequest(c, "Invalid tournament")
+			return
+		}
+		response.InternalError(c, "Failed to get trending matches")
+		return
+	}
+
+	response.OK(c, "Trending matches retrieved successfully", matches)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	pkgMiddleware "backend-go/pkg/middleware"
+	"backend-go/pkg/response"
+)
+
+// MetricsSnapshotHandler Prometheus 指标快照处理器
+type MetricsSnapshotHandler struct {
+	gatherer prometheus.Gatherer
+}
+
+// NewMetricsSnapshotHandler 创建指标快照处理器，gatherer 为空时使用默认注册表
+func NewMetricsSnapshotHandler(gatherer prometheus.Gatherer) *MetricsSnapshotHandler {
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	return &MetricsSnapshotHandler{gatherer: gatherer}
+}
+
+// GetMetricsSnapshot 下载当前指标快照
+// @Summary 下载 Prometheus 指标快照
+// @Description 以 Prometheus 文本格式导出当前全部指标，附带快照时间与构建信息，用于无法访问 /metrics 时附加到故障工单
+// @Tags 系统监控
+// @Produce plain
+// @Security BearerAuth
+// @Success 200 {string} string "Prometheus 文本格式指标"
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/metrics/snapshot [get]
+func (h *MetricsSnapshotHandler) GetMetricsSnapshot(c *gin.Context) {
+	takenAt := time.Now()
+
+	var buf bytes.Buffer
+	if err := pkgMiddleware.WriteMetricsSnapshot(&buf, h.gatherer, takenAt); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to export metrics snapshot", err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+pkgMiddleware.MetricsSnapshotFilename(takenAt)+`"`)
+	c.Data(http.StatusOK, pkgMiddleware.MetricsSnapshotContentType, buf.Bytes())
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/events/monitoring"
+	"backend-go/internal/core/domain/shared"
+	pkgMiddleware "backend-go/pkg/middleware"
+)
+
+func TestMetricsSnapshotHandler_GetMetricsSnapshot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 产生一次 HTTP 请求指标与一次用户行为事件指标，确保两个指标族出现在注册表中
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	collector := monitoring.NewMetricsCollector(nil, logger)
+	if err := collector.Handle(shared.NewEvent("snapshot.test", nil)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	router := gin.New()
+	router.Use(pkgMiddleware.MetricsMiddleware(nil))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/admin/metrics/snapshot", NewMetricsSnapshotHandler(nil).GetMetricsSnapshot)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/metrics/snapshot", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != pkgMiddleware.MetricsSnapshotContentType {
+		t.Errorf("Content-Type = %q, want %q", got, pkgMiddleware.MetricsSnapshotContentType)
+	}
+	if got := w.Header().Get("Content-Disposition"); !strings.Contains(got, "metrics-snapshot-") {
+		t.Errorf("Content-Disposition = %q, want snapshot filename", got)
+	}
+
+	body := w.Body.Bytes()
+	if !bytes.HasPrefix(body, []byte("# Metrics snapshot taken at ")) {
+		t.Errorf("快照缺少时间戳头部: %q", firstLine(body))
+	}
+	if !bytes.Contains(body, []byte("# Go version: ")) {
+		t.Error("快照缺少构建信息")
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("快照不是合法的 Prometheus 文本格式: %v", err)
+	}
+	for _, name := range []string{"user_behavior_events_total", "http_requests_total"} {
+		if _, ok := families[name]; !ok {
+			t.Errorf("快照缺少指标 %s", name)
+		}
+	}
+}
+
+func firstLine(b []byte) string {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return string(b[:i])
+	}
+	return string(b)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/core/services"
+	"backend-go/pkg/response"
+)
+
+// MigrationHandler 数据库迁移运行处理器
+type MigrationHandler struct {
+	runner *services.MigrationRunner
+	audit  middleware.ImpersonationAuditLogger
+	logger *logrus.Logger
+}
+
+// NewMigrationHandler 创建数据库迁移运行处理器，audit 为空时只记录日志
+func NewMigrationHandler(runner *services.MigrationRunner, audit middleware.ImpersonationAuditLogger, logger *logrus.Logger) *MigrationHandler {
+	return &MigrationHandler{runner: runner, audit: audit, logger: logger}
+}
+
+// RunMigrations 在后台执行待执行的迁移
+// @Summary 执行数据库迁移
+// @Description 持有分布式锁在后台执行 AutoMigrate 与待执行的 SQL 迁移，返回任务 ID；已有迁移在执行时返回 409。仅超级管理员可用，操作写入审计日志
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} response.Response{data=services.MigrationJob}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/migrations/run [post]
+func (h *MigrationHandler) RunMigrations(c *gin.Context) {
+	start := time.Now()
+	operatorID, _ := middleware.GetCurrentUserID(c)
+
+	job, err := h.runner.Start(c.Request.Context(), operatorID)
+	h.auditRun(c, operatorID, job, err, time.Since(start))
+	if errors.Is(err, services.ErrMigrationRunning) {
+		response.Error(c, http.StatusConflict, "Migration is already running", err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to start migration", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Migration started", job)
+}
+
+// GetMigrationStatus 获取迁移状态
+// @Summary 获取数据库迁移状态
+// @Description 返回已执行与失败的迁移数、是否有迁移在执行、最后一次迁移以及最近一次通过接口发起的任务
+// @Tags 系统监控
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=services.MigrationRunStatus}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/migrations/status [get]
+func (h *MigrationHandler) GetMigrationStatus(c *gin.Context) {
+	status, err := h.runner.Status(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to get migration status", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Migration status retrieved successfully", status)
+}
+
+// auditRun 记录发起迁移的操作，被锁拒绝的请求同样记录
+func (h *MigrationHandler) auditRun(c *gin.Context, operatorID uint, job *services.MigrationJob, runErr error, duration time.Duration) {
+	fields := logrus.Fields{"operator_id": operatorID}
+	req := &ports.LogActionRequest{
+		AdminUserID: operatorID,
+		Action:      "migration.run",
+		Resource:    "migration",
+		Method:      c.Request.Method,
+		Path:        c.Request.URL.Path,
+		IPAddress:   c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		Status:      admin.AuditStatusSuccess,
+		Duration:    duration.Milliseconds(),
+	}
+	if job != nil {
+		req.ResourceID = job.ID
+		fields["job_id"] = job.ID
+	}
+	if runErr != nil {
+		req.Status = admin.AuditStatusFailed
+		req.ErrorMsg = runErr.Error()
+		h.logger.WithFields(fields).WithError(runErr).Warn("数据库迁移未能发起")
+	} else {
+		h.logger.WithFields(fields).Warn("数据库迁移已发起")
+	}
+
+	if h.audit == nil {
+		return
+	}
+	// 客户端断开不应影响审计写入
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+	defer cancel()
+	if err := h.audit.LogAction(ctx, req); err != nil {
+		h.logger.WithError(err).Error("Failed to audit migration run")
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/core/services"
+)
+
+// migrationTokenService 按令牌返回固定身份
+type migrationTokenService struct {
+	user.Service
+	identities map[string]*user.TokenIdentity
+}
+
+func (s *migrationTokenService) Authenticate(ctx context.Context, token string) (*user.TokenIdentity, error) {
+	if identity, ok := s.identities[token]; ok {
+		return identity, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// memoryLocker 进程内的分布式锁
+type memoryLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *memoryLocker) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] {
+		return false, nil
+	}
+	l.held[key] = true
+	return true, nil
+}
+
+func (l *memoryLocker) Unlock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.held, key)
+	return nil
+}
+
+// gatedMigrator 的迁移在 release 关闭前阻塞
+type gatedMigrator struct {
+	release chan struct{}
+}
+
+func (m *gatedMigrator) RunUpMigrations(ctx context.Context, migrationsDir string) error {
+	<-m.release
+	return nil
+}
+
+func (m *gatedMigrator) GetMigrationStatus(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{"applied_count": 12, "failed_count": 0, "migration_locked": false}, nil
+}
+
+// recordingMigrationAudit 记录写入的审计日志
+type recordingMigrationAudit struct {
+	mu      sync.Mutex
+	entries []*ports.LogActionRequest
+}
+
+func (a *recordingMigrationAudit) LogAction(ctx context.Context, req *ports.LogActionRequest) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, req)
+	return nil
+}
+
+func newMigrationRouter(migrator *gatedMigrator, audit *recordingMigrationAudit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	auth := middleware.NewAuthMiddleware(&migrationTokenService{identities: map[string]*user.TokenIdentity{
+		"admin-token": {User: &user.User{ID: 1, Username: "root", Role: user.UserRoleAdmin}},
+		"user-token":  {User: &user.User{ID: 2, Username: "bob", Role: user.UserRoleUser}},
+	}})
+	runner := services.NewMigrationRunner(migrator, &memoryLocker{held: map[string]bool{}}, services.MigrationRunnerConfig{}, logger)
+	handler := NewMigrationHandler(runner, audit, logger)
+
+	router := gin.New()
+	group := router.Group("/api/admin", auth.RequireAuth(), auth.RequireAdmin(), auth.RequireSuperAdmin())
+	group.POST("/migrations/run", handler.RunMigrations)
+	group.GET("/migrations/status", handler.GetMigrationStatus)
+	return router
+}
+
+func doMigrationRequest(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMigrationHandler_RequiresSuperAdmin(t *testing.T) {
+	migrator := &gatedMigrator{release: make(chan struct{})}
+	defer close(migrator.release)
+	audit := &recordingMigrationAudit{}
+	router := newMigrationRouter(migrator, audit)
+
+	if w := doMigrationRequest(router, http.MethodPost, "/api/admin/migrations/run", "user-token"); w.Code != http.StatusForbidden {
+		t.Errorf("普通用户 status = %d, want 403", w.Code)
+	}
+	if w := doMigrationRequest(router, http.MethodGet, "/api/admin/migrations/status", "user-token"); w.Code != http.StatusForbidden {
+		t.Errorf("普通用户查询状态 status = %d, want 403", w.Code)
+	}
+	if len(audit.entries) != 0 {
+		t.Errorf("audit = %d 条, want 未发起迁移", len(audit.entries))
+	}
+}
+
+func TestMigrationHandler_RunReflectedInStatus(t *testing.T) {
+	migrator := &gatedMigrator{release: make(chan struct{})}
+	audit := &recordingMigrationAudit{}
+	router := newMigrationRouter(migrator, audit)
+
+	w := doMigrationRequest(router, http.MethodPost, "/api/admin/migrations/run", "admin-token")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202, body = %s", w.Code, w.Body.String())
+	}
+	var started struct {
+		Data services.MigrationJob `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if started.Data.ID == "" || started.Data.Status != services.MigrationJobRunning {
+		t.Fatalf("job = %+v, want 运行中的任务", started.Data)
+	}
+
+	// 迁移执行期间再次发起被拒绝
+	if w := doMigrationRequest(router, http.MethodPost, "/api/admin/migrations/run", "admin-token"); w.Code != http.StatusConflict {
+		t.Errorf("并发发起 status = %d, want 409", w.Code)
+	}
+
+	status := func() services.MigrationRunStatus {
+		t.Helper()
+		w := doMigrationRequest(router, http.MethodGet, "/api/admin/migrations/status", "admin-token")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+		var body struct {
+			Data services.MigrationRunStatus `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		return body.Data
+	}
+	if got := status(); !got.Locked || got.LastJob == nil || got.LastJob.ID != started.Data.ID {
+		t.Fatalf("运行中状态 = %+v, want 锁定且最近任务为 %s", got, started.Data.ID)
+	}
+
+	close(migrator.release)
+	deadline := time.Now().Add(time.Second)
+	got := status()
+	for got.LastJob.Status == services.MigrationJobRunning && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		got = status()
+	}
+	if got.Locked || got.LastJob.Status != services.MigrationJobSucceeded {
+		t.Errorf("完成后状态 = %+v, want 已解锁且任务成功", got)
+	}
+	if got.Migrations["applied_count"] != float64(12) {
+		t.Errorf("migrations = %v, want 包含已执行迁移数", got.Migrations)
+	}
+
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+	if len(audit.entries) != 2 {
+		t.Fatalf("audit = %d 条, want 2", len(audit.entries))
+	}
+	first, second := audit.entries[0], audit.entries[1]
+	if first.Action != "migration.run" || first.AdminUserID != 1 || first.ResourceID != started.Data.ID || first.Status != admin.AuditStatusSuccess {
+		t.Errorf("首次审计 = %+v, want 成功发起的迁移", first)
+	}
+	if second.Status != admin.AuditStatusFailed || second.ErrorMsg == "" {
+		t.Errorf("并发审计 = %+v, want 记录被拒绝的请求", second)
+	}
+}
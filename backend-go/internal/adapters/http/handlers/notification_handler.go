@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/response"
+)
+
+// NotificationHandler 用户通知偏好处理器
+type NotificationHandler struct {
+	userService user.Service
+}
+
+// NewNotificationHandler 创建通知偏好处理器
+func NewNotificationHandler(userService user.Service) *NotificationHandler {
+	return &NotificationHandler{userService: userService}
+}
+
+// GetPreferences 获取当前用户的通知偏好
+// @Summary 获取通知偏好
+// @Description 获取当前用户的邮件、比赛提醒提前量与实时推送设置，未设置过时返回默认值
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=user.NotificationPreferences}
+// @Failure 401 {object} response.Response
+// @Router /api/v1/users/me/notifications [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	prefs, err := h.userService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalError(c, "获取通知偏好失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "获取通知偏好成功", prefs)
+}
+
+// UpdatePreferences 更新当前用户的通知偏好
+// @Summary 更新通知偏好
+// @Description 更新邮件开关、比赛提醒提前量（5-1440 分钟）与实时推送开关，未提供的字段保持不变
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.UpdateNotificationPreferencesRequest true "通知偏好"
+// @Success 200 {object} response.Response{data=user.NotificationPreferences}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/users/me/notifications [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	var req user.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	prefs, err := h.userService.UpdatePreferences(c.Request.Context(), userID, &req)
+	if err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "更新通知偏好失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "通知偏好已更新", prefs)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/pkg/response"
+)
+
+// SportAccessChecker 检查管理员的运动类型访问权限，ports.AdminService 满足该接口
+type SportAccessChecker interface {
+	HasSportAccess(ctx context.Context, userID uint, sportTypeID uint) (bool, error)
+}
+
+// PredictionExportHandler 比赛预测导出处理器
+type PredictionExportHandler struct {
+	predictionService prediction.Service
+	matchService      match.Service
+	sportAccess       SportAccessChecker
+}
+
+// NewPredictionExportHandler 创建比赛预测导出处理器，sportAccess 为空时不检查运动类型权限
+func NewPredictionExportHandler(predictionService prediction.Service, matchService match.Service, sportAccess SportAccessChecker) *PredictionExportHandler {
+	return &PredictionExportHandler{
+		predictionService: predictionService,
+		matchService:      matchService,
+		sportAccess:       sportAccess,
+	}
+}
+
+// ExportMatchPredictions 导出比赛预测
+// @Summary 导出比赛预测
+// @Description 以 CSV 格式下载已结束比赛的全部预测，供分析使用。比赛属于某运动类型时需要该运动类型的访问权限
+// @Tags predictions
+// @Produce text/csv
+// @Param id path int true "比赛ID"
+// @Success 200 {string} string "CSV 文件"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/matches/{id}/predictions/export [get]
+// @Security BearerAuth
+func (h *PredictionExportHandler) ExportMatchPredictions(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的比赛ID")
+		return
+	}
+
+	m, err := h.matchService.GetMatch(c.Request.Context(), uint(matchID))
+	if err != nil {
+		if errors.Is(err, domain.ErrMatchNotFound) {
+			response.NotFound(c, "Match")
+		} else {
+			response.InternalError(c, "Failed to get match")
+		}
+		return
+	}
+
+	if m.SportTypeID != nil && h.sportAccess != nil {
+		userID, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			response.Unauthorized(c, "用户未认证")
+			return
+		}
+		hasAccess, err := h.sportAccess.HasSportAccess(c.Request.Context(), userID, *m.SportTypeID)
+		if err != nil {
+			response.InternalError(c, "Failed to check sport access")
+			return
+		}
+		if !hasAccess {
+			response.Forbidden(c, "Sport type access required")
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="match-%d-predictions.csv"`, matchID))
+
+	if err := h.predictionService.ExportMatchPredictions(c.Request.Context(), uint(matchID), c.Writer); err != nil {
+		// 已开始写出时无法再返回错误响应，只能中断下载
+		if c.Writer.Written() {
+			_ = c.Error(err)
+			return
+		}
+		c.Header("Content-Type", "")
+		c.Header("Content-Disposition", "")
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "导出预测失败: "+err.Error())
+	}
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/prediction"
 	"backend-go/pkg/response"
 
@@ -65,7 +67,7 @@ func (h *PredictionHandler) CreatePrediction(c *gin.Context) {
 
 // UpdatePrediction 更新预测
 // @Summary 更新预测
-// @Description 更新指定的预测
+// @Description 更新指定的预测，携带 version 时进行乐观锁校验，版本过期返回 409
 // @Tags predictions
 // @Accept json
 // @Produce json
@@ -76,6 +78,7 @@ func (h *PredictionHandler) CreatePrediction(c *gin.Context) {
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/predictions/{id} [put]
 // @Security BearerAuth
@@ -268,6 +271,46 @@ func (h *PredictionHandler) VotePrediction(c *gin.Context) {
 	response.OK(c, "投票成功", nil)
 }
 
+// CastVotes 批量投票
+// @Summary 批量投票
+// @Description 一次为多个预测投票，逐条校验，有效投票写入、无效投票在结果中标明原因（self_vote、duplicate、not_found、match_started），允许部分成功
+// @Tags predictions
+// @Accept json
+// @Produce json
+// @Param request body prediction.CastVotesRequest true "批量投票请求"
+// @Success 200 {object} response.Response{data=prediction.BatchVoteResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/predictions/votes [post]
+// @Security BearerAuth
+func (h *PredictionHandler) CastVotes(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	var req prediction.CastVotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err.Error())
+		return
+	}
+
+	result, err := h.predictionService.CastVotes(c.Request.Context(), userID, req.Votes)
+	if err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "批量投票失败: "+err.Error())
+		return
+	}
+
+	response.OK(c, "批量投票完成", result)
+}
+
 // UnvotePrediction 取消投票
 // @Summary 取消投票
 // @Description 取消对指定预测的投票
@@ -386,3 +429,39 @@ func (h *PredictionHandler) GetFeaturedPredictions(c *gin.Context) {
 
 	response.OK(c, "获取精选预测成功", predictions)
 }
+
+// GetMatchConsensus 获取比赛预测共识
+// @Summary 获取比赛预测共识
+// @Description 获取指定比赛的预测分布、总数和加权置信度，盲预测模式下比赛开始前隐藏
+// @Tags predictions
+// @Accept json
+// @Produce json
+// @Param id path int true "比赛ID"
+// @Success 200 {object} response.Response{data=prediction.MatchConsensus}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/matches/{id}/consensus [get]
+func (h *PredictionHandler) GetMatchConsensus(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的比赛ID")
+		return
+	}
+
+	consensus, err := h.predictionService.GetMatchConsensus(c.Request.Context(), uint(matchID))
+	if err != nil {
+		if errors.Is(err, domain.ErrMatchNotFound) {
+			response.NotFound(c, "Match")
+			return
+		}
+		if appErr, ok := err.(*response.AppError); ok {
+			response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+			return
+		}
+		response.InternalError(c, "获取预测共识失败: "+err.Error())
+		return
+	}
+
+	response.OK(c, "获取预测共识成功", consensus)
+}
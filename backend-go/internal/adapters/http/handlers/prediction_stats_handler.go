@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/services"
+	"backend-go/pkg/redis"
+	"backend-go/pkg/response"
+)
+
+// PredictionStatsHandler 预测统计趋势处理器，读取每日汇总任务预计算的结果
+type PredictionStatsHandler struct {
+	rollup *services.StatsRollup
+}
+
+// NewPredictionStatsHandler 创建预测统计趋势处理器
+func NewPredictionStatsHandler(rollup *services.StatsRollup) *PredictionStatsHandler {
+	return &PredictionStatsHandler{rollup: rollup}
+}
+
+// GetPredictionSeries 获取最近 N 天的预测数序列
+// @Summary 获取预测数趋势
+// @Description 返回每日汇总任务预计算的最近 N 天预测数序列，缺失的日期计为 0。序列在首次汇总前不存在
+// @Tags 统计
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "天数，默认 30" Enums(30, 90)
+// @Success 200 {object} response.Response{data=services.StatsSeries}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/stats/predictions/series [get]
+func (h *PredictionStatsHandler) GetPredictionSeries(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		response.BadRequest(c, "days must be a positive integer")
+		return
+	}
+
+	series, err := h.rollup.CachedSeries(c.Request.Context(), days)
+	if err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			response.NotFound(c, "Prediction series")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to get prediction series", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Prediction series retrieved successfully", series)
+}
+
+// GetMonthlyPredictions 获取指定月份的预测总数
+// @Summary 获取月度预测数
+// @Description 按月度汇总返回指定月份的预测总数，汇总不受每日计数 7 天保留期限制
+// @Tags 统计
+// @Produce json
+// @Security BearerAuth
+// @Param month query string false "月份（2006-01），默认当月"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/stats/predictions/monthly [get]
+func (h *PredictionStatsHandler) GetMonthlyPredictions(c *gin.Context) {
+	month := c.DefaultQuery("month", time.Now().Format("2006-01"))
+	if _, err := time.Parse("2006-01", month); err != nil {
+		response.BadRequest(c, "month must be in YYYY-MM format")
+		return
+	}
+
+	total, err := h.rollup.MonthlyTotal(c.Request.Context(), month)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to get monthly predictions", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Monthly predictions retrieved successfully", gin.H{"month": month, "total": total})
+}
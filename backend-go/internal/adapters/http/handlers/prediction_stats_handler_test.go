@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/services"
+	"backend-go/pkg/redis"
+)
+
+// memoryRollupStore 内存版汇总存储
+type memoryRollupStore struct {
+	values map[string]string
+	hashes map[string]map[string]string
+	blobs  map[string][]byte
+}
+
+func (s *memoryRollupStore) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if value, ok := s.values[key]; ok {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *memoryRollupStore) HSet(ctx context.Context, key string, values ...interface{}) error {
+	if s.hashes[key] == nil {
+		s.hashes[key] = map[string]string{}
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		s.hashes[key][fmt.Sprint(values[i])] = fmt.Sprint(values[i+1])
+	}
+	return nil
+}
+
+func (s *memoryRollupStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.hashes[key], nil
+}
+
+func (s *memoryRollupStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+
+func (s *memoryRollupStore) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	s.blobs[key] = data
+	return err
+}
+
+func (s *memoryRollupStore) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	data, ok := s.blobs[key]
+	if !ok {
+		return redis.ErrKeyNotFound
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func TestPredictionStatsHandler_ReadsRollup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	today := time.Now().Format("2006-01-02")
+	store := &memoryRollupStore{
+		values: map[string]string{"stats:predictions:daily:" + today: "7"},
+		hashes: map[string]map[string]string{},
+		blobs:  map[string][]byte{},
+	}
+	rollup := services.NewStatsRollup(store, services.StatsRollupConfig{}, logger)
+	handler := NewPredictionStatsHandler(rollup)
+
+	router := gin.New()
+	router.GET("/series", handler.GetPredictionSeries)
+	router.GET("/monthly", handler.GetMonthlyPredictions)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// 首次汇总前序列不存在
+	if w := get("/series?days=30"); w.Code != http.StatusNotFound {
+		t.Errorf("汇总前 status = %d, want 404", w.Code)
+	}
+	if w := get("/series?days=abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("无效天数 status = %d, want 400", w.Code)
+	}
+	if w := get("/monthly?month=2025-13"); w.Code != http.StatusBadRequest {
+		t.Errorf("无效月份 status = %d, want 400", w.Code)
+	}
+
+	if _, err := rollup.Rollup(context.Background()); err != nil {
+		t.Fatalf("Rollup() error = %v", err)
+	}
+
+	w := get("/series?days=30")
+	var series struct {
+		Data services.StatsSeries `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &series); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if w.Code != http.StatusOK || series.Data.Days != 30 || series.Data.Total != 7 || series.Data.To != today {
+		t.Errorf("series status = %d, data = %+v, want 30 天共 7 个预测", w.Code, series.Data)
+	}
+
+	w = get("/monthly")
+	var monthly struct {
+		Data struct {
+			Month string `json:"month"`
+			Total int64  `json:"total"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &monthly); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if w.Code != http.StatusOK || monthly.Data.Month != today[:7] || monthly.Data.Total != 7 {
+		t.Errorf("monthly status = %d, data = %+v, want 当月 7 个预测", w.Code, monthly.Data)
+	}
+}
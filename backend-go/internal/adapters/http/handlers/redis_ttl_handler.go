@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/services"
+	"backend-go/pkg/response"
+)
+
+// RedisTTLHandler Redis 键过期时间审计处理器
+type RedisTTLHandler struct {
+	auditor *services.RedisTTLAuditor
+}
+
+// NewRedisTTLHandler 创建 Redis 键过期时间审计处理器
+func NewRedisTTLHandler(auditor *services.RedisTTLAuditor) *RedisTTLHandler {
+	return &RedisTTLHandler{auditor: auditor}
+}
+
+// AuditTTL 审计缺少过期时间的统计键
+// @Summary 审计 Redis 统计键过期时间
+// @Description 扫描 stats:* 与 metrics:* 键，列出按键模式应有过期时间但 TTL 为 -1 的键
+// @Tags 缓存管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=services.TTLAuditReport}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/redis/ttl-audit [get]
+func (h *RedisTTLHandler) AuditTTL(c *gin.Context) {
+	report, err := h.auditor.Audit(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to audit Redis TTL", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Redis TTL audit completed", report)
+}
+
+// RepairTTL 为缺少过期时间的统计键补设过期时间
+// @Summary 修复 Redis 统计键过期时间
+// @Description 扫描 stats:* 与 metrics:* 键，按键模式为 TTL 为 -1 的键补设过期时间
+// @Tags 缓存管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=services.TTLAuditReport}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/redis/ttl-audit/repair [post]
+func (h *RedisTTLHandler) RepairTTL(c *gin.Context) {
+	report, err := h.auditor.Repair(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to repair Redis TTL", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Redis TTL repair completed", report)
+}
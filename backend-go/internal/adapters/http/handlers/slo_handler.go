@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	pkgMiddleware "backend-go/pkg/middleware"
+	"backend-go/pkg/response"
+)
+
+// SLOHandler 接口延迟 SLO 报告处理器
+type SLOHandler struct {
+	tracker *pkgMiddleware.SLOTracker
+}
+
+// NewSLOHandler 创建 SLO 报告处理器
+func NewSLOHandler(tracker *pkgMiddleware.SLOTracker) *SLOHandler {
+	return &SLOHandler{tracker: tracker}
+}
+
+// GetSLO 获取各接口 SLO 达成情况
+// @Summary 获取接口延迟 SLO 达成情况
+// @Description 返回滚动窗口内各路由低于延迟目标的请求比例及 P95/P99 延迟，达成率最低的路由排在最前
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=pkgMiddleware.SLOReport}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/slo [get]
+func (h *SLOHandler) GetSLO(c *gin.Context) {
+	response.Success(c, http.StatusOK, "SLO report retrieved successfully", h.tracker.Report())
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/adapters/realtime"
+	"backend-go/internal/core/domain/leaderboard"
+	"backend-go/pkg/response"
+)
+
+// StreamHandler 实时推送处理器（SSE）
+type StreamHandler struct {
+	hub                *realtime.Hub
+	leaderboardService leaderboard.Service
+	logger             *logrus.Logger
+	heartbeat          time.Duration
+	writeWait          time.Duration
+}
+
+// NewStreamHandler 创建实时推送处理器，心跳间隔与写超时取自订阅中心配置
+func NewStreamHandler(hub *realtime.Hub, leaderboardService leaderboard.Service, logger *logrus.Logger) *StreamHandler {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &StreamHandler{
+		hub:                hub,
+		leaderboardService: leaderboardService,
+		logger:             logger,
+		heartbeat:          hub.Config().PingPeriod,
+		writeWait:          hub.Config().WriteWait,
+	}
+}
+
+// StreamLeaderboard 通过 SSE 推送排行榜变更
+// @Summary 订阅排行榜变更
+// @Description 以 text/event-stream 推送排行榜快照与增量，适用于无法使用 WebSocket 的环境
+// @Tags stream
+// @Produce text/event-stream
+// @Param tournament path string true "锦标赛类型" Enums(SPRING,SUMMER,GLOBAL)
+// @Param token query string false "访问令牌（EventSource 无法设置请求头时使用）"
+// @Success 200 {string} string "事件流"
+// @Failure 401 {object} response.Response
+// @Router /api/v1/stream/leaderboard/{tournament} [get]
+func (h *StreamHandler) StreamLeaderboard(c *gin.Context) {
+	tournament := c.Param("tournament")
+	if !leaderboard.IsValidTournament(tournament) {
+		tournament = string(leaderboard.TournamentGlobal)
+	}
+
+	// 排行榜订阅允许匿名，未认证时用户 ID 为 0
+	userID, _ := middleware.GetCurrentUserID(c)
+	conn := newSSEConn()
+	connID, err := h.hub.Register(conn, userID, c.ClientIP())
+	if err != nil {
+		response.Error(c, http.StatusServiceUnavailable, "Too many stream connections", err.Error())
+		return
+	}
+	defer h.hub.Unregister(connID)
+
+	sub := h.hub.SubscribeConn(connID, realtime.LeaderboardTopic(tournament))
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// 首个事件推送当前排行榜快照
+	entries, err := h.leaderboardService.GetLeaderboard(c.Request.Context(), tournament, 100)
+	if err != nil {
+		h.logger.WithError(err).WithField("tournament", tournament).Warn("获取排行榜快照失败")
+		entries = []leaderboard.LeaderboardEntry{}
+	}
+	h.setWriteDeadline(c)
+	if err := writeSSEEvent(c.Writer, realtime.EventLeaderboardSnapshot, entries); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	h.pump(c, sub, conn)
+}
+
+// StreamNotifications 通过 SSE 推送当前用户的通知
+// @Summary 订阅个人通知
+// @Description 以 text/event-stream 推送当前用户的实时通知（如 match.reminder 比赛提醒），受通知偏好中的推送开关控制
+// @Tags stream
+// @Produce text/event-stream
+// @Param token query string false "访问令牌（EventSource 无法设置请求头时使用）"
+// @Success 200 {string} string "事件流"
+// @Failure 401 {object} response.Response
+// @Router /api/v1/stream/notifications [get]
+func (h *StreamHandler) StreamNotifications(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	conn := newSSEConn()
+	connID, err := h.hub.Register(conn, userID, c.ClientIP())
+	if err != nil {
+		response.Error(c, http.StatusServiceUnavailable, "Too many stream connections", err.Error())
+		return
+	}
+	defer h.hub.Unregister(connID)
+
+	sub := h.hub.SubscribeConn(connID, realtime.UserTopic(userID))
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	h.pump(c, sub, conn)
+}
+
+// sseConn SSE 连接的关闭端，供订阅中心终止连接
+type sseConn struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func newSSEConn() *sseConn {
+	return &sseConn{done: make(chan struct{})}
+}
+
+// Close 通知推送循环写出关闭事件并结束事件流
+func (s *sseConn) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+// pump 转发订阅消息并定时发送心跳，直到客户端断开或连接被终止
+func (h *StreamHandler) pump(c *gin.Context, sub *realtime.Subscription, conn *sseConn) {
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-conn.done:
+			h.closeStream(c)
+			return
+		case <-ticker.C:
+			// 注释行作为心跳，防止代理断开空闲连接
+			h.setWriteDeadline(c)
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				// 订阅仅在连接被终止时提前关闭
+				h.closeStream(c)
+				return
+			}
+			h.setWriteDeadline(c)
+			if err := writeSSEEvent(c.Writer, msg.Event, msg.Data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// setWriteDeadline 为下一次写出设置超时，客户端读取过慢时写出失败并结束推送
+func (h *StreamHandler) setWriteDeadline(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(h.writeWait))
+}
+
+// closeStream 写出关闭事件，告知客户端连接由服务端终止
+func (h *StreamHandler) closeStream(c *gin.Context) {
+	h.setWriteDeadline(c)
+	if err := writeSSEEvent(c.Writer, realtime.EventConnectionClosed, gin.H{"reason": "terminated"}); err != nil {
+		return
+	}
+	c.Writer.Flush()
+}
+
+// writeSSEEvent 写入一条 SSE 事件
+func writeSSEEvent(w io.Writer, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/realtime"
+	"backend-go/internal/core/domain/leaderboard"
+)
+
+// fakeLeaderboardService 仅返回固定排行榜的测试服务
+type fakeLeaderboardService struct {
+	leaderboard.Service
+	entries []leaderboard.LeaderboardEntry
+}
+
+func (f *fakeLeaderboardService) GetLeaderboard(ctx context.Context, tournament string, limit int) ([]leaderboard.LeaderboardEntry, error) {
+	return f.entries, nil
+}
+
+func TestStreamHandler_StreamLeaderboard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := realtime.NewHub()
+	service := &fakeLeaderboardService{entries: []leaderboard.LeaderboardEntry{{UserID: 1, Username: "alice", Points: 10, Rank: 1}}}
+	handler := NewStreamHandler(hub, service, nil)
+
+	router := gin.New()
+	router.GET("/stream/leaderboard/:tournament", handler.StreamLeaderboard)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream/leaderboard/SPRING", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", cc)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	event, data := readSSEEvent(t, reader)
+	if event != realtime.EventLeaderboardSnapshot || !strings.Contains(data, "alice") {
+		t.Errorf("首个事件 = %s %s, want 排行榜快照", event, data)
+	}
+
+	// 等待订阅建立后发布增量
+	for hub.SubscriberCount(realtime.LeaderboardTopic("SPRING")) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.Publish(realtime.LeaderboardTopic("SPRING"), realtime.EventLeaderboardDelta, []leaderboard.LeaderboardEntry{{UserID: 2, Username: "bob", Points: 20, Rank: 1}})
+
+	event, data = readSSEEvent(t, reader)
+	if event != realtime.EventLeaderboardDelta || !strings.Contains(data, "bob") {
+		t.Errorf("增量事件 = %s %s, want 排行榜增量", event, data)
+	}
+}
+
+// readSSEEvent 读取一条 SSE 事件，跳过心跳注释
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("读取事件流失败: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && event != "":
+			return event, data
+		}
+	}
+}
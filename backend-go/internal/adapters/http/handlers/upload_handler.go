@@ -13,11 +13,12 @@ import (
 // UploadHandler 处理文件上传
 type UploadHandler struct {
 	baseDir string
+	maxSize int64 // 单个文件大小上限（字节），<=0 表示不限制
 }
 
 // NewUploadHandler 创建上传处理器
-func NewUploadHandler(baseDir string) *UploadHandler {
-	return &UploadHandler{baseDir: baseDir}
+func NewUploadHandler(baseDir string, maxSize int64) *UploadHandler {
+	return &UploadHandler{baseDir: baseDir, maxSize: maxSize}
 }
 
 // UploadAvatar 上传头像
@@ -27,6 +28,10 @@ func (h *UploadHandler) UploadAvatar(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "缺少文件", "error": err.Error()})
 		return
 	}
+	if h.maxSize > 0 && file.Size > h.maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": "文件过大", "max_size": h.maxSize})
+		return
+	}
 
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
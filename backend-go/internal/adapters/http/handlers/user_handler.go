@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/request"
 	"backend-go/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -27,21 +28,18 @@ func NewUserHandler(userService user.Service, db *gorm.DB, logger *logrus.Logger
 	}
 }
 
+// userSortFields 用户列表允许的排序字段（数据库列名）
+var userSortFields = []string{"id", "username", "points", "createdAt"}
+
 // ListUsers 获取用户列表
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	// 获取分页参数
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
+	// 获取分页和排序参数
+	query, err := request.ParseListQuery(c, userSortFields)
+	if err != nil {
+		response.HandleError(c, err)
+		return
 	}
 
-	offset := (page - 1) * pageSize
-
 	// 从数据库获取用户列表
 	var users []user.User
 	var total int64
@@ -54,7 +52,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	}
 
 	// 获取用户列表
-	if err := h.db.Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
+	if err := h.db.Order(query.OrderClause("id ASC")).Offset(query.Offset()).Limit(query.PageSize).Find(&users).Error; err != nil {
 		h.logger.WithError(err).Error("获取用户列表失败")
 		response.Error(c, http.StatusInternalServerError, "Failed to get users", err.Error())
 		return
@@ -63,8 +61,8 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Users retrieved successfully", gin.H{
 		"users": users,
 		"total": total,
-		"page":  page,
-		"page_size": pageSize,
+		"page":  query.Page,
+		"page_size": query.PageSize,
 	})
 }
 
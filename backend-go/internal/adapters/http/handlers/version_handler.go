@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/buildinfo"
+	"backend-go/pkg/response"
+)
+
+// VersionInfo 运行中服务的构建信息与所在环境
+type VersionInfo struct {
+	buildinfo.Info
+	Environment string `json:"environment"`
+}
+
+// VersionHandler 版本信息处理器
+type VersionHandler struct {
+	environment string
+}
+
+// NewVersionHandler 创建版本信息处理器
+func NewVersionHandler(environment string) *VersionHandler {
+	return &VersionHandler{environment: environment}
+}
+
+// GetVersion 获取运行中服务的版本
+// @Summary 版本信息
+// @Description 返回构建时注入的版本号、提交号、构建时间，以及 Go 版本与当前环境，用于确认部署的版本
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.Response{data=VersionInfo}
+// @Router /version [get]
+func (h *VersionHandler) GetVersion(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Version retrieved successfully", VersionInfo{
+		Info:        buildinfo.Get(),
+		Environment: h.environment,
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/buildinfo"
+)
+
+func getVersion(t *testing.T, environment string) VersionInfo {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/version", NewVersionHandler(environment).GetVersion)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var body struct {
+		Data VersionInfo `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	return body.Data
+}
+
+func TestVersionHandler_ReturnsInjectedBuildInfo(t *testing.T) {
+	oldVersion, oldCommit, oldDate := buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildDate
+	defer func() { buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildDate = oldVersion, oldCommit, oldDate }()
+	buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildDate = "v2.0.1", "9f8e7d6", "2025-10-18T12:00:00Z"
+
+	info := getVersion(t, "production")
+	if info.Version != "v2.0.1" || info.GitCommit != "9f8e7d6" || info.BuildDate != "2025-10-18T12:00:00Z" {
+		t.Errorf("version = %+v, want 注入的构建信息", info)
+	}
+	if info.GoVersion != runtime.Version() || info.Environment != "production" {
+		t.Errorf("goVersion = %q, environment = %q", info.GoVersion, info.Environment)
+	}
+
+	// 未注入时返回默认值
+	buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildDate = "", "", ""
+	if info := getVersion(t, "development"); info.Version != buildinfo.DefaultVersion || info.GitCommit == "" {
+		t.Errorf("version = %+v, want 默认版本 %q", info, buildinfo.DefaultVersion)
+	}
+}
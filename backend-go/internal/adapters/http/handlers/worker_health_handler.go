@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/services"
+	"backend-go/pkg/response"
+)
+
+// WorkerHealthHandler 后台任务健康检查处理器
+type WorkerHealthHandler struct {
+	heartbeat *services.WorkerHeartbeat
+}
+
+// NewWorkerHealthHandler 创建后台任务健康检查处理器
+func NewWorkerHealthHandler(heartbeat *services.WorkerHeartbeat) *WorkerHealthHandler {
+	return &WorkerHealthHandler{heartbeat: heartbeat}
+}
+
+// GetWorkerHealth 获取后台任务健康状况
+// @Summary 后台任务健康检查
+// @Description 返回 worker 各定时任务的上次运行时间，超过 2 个执行间隔未运行的任务标记为停滞，超过 5 个间隔的视为已停用不再列出。存在停滞任务或没有任何任务上报心跳时返回 503
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.Response{data=services.WorkerHealthReport}
+// @Failure 500 {object} response.Response
+// @Failure 503 {object} response.Response{data=services.WorkerHealthReport}
+// @Router /api/health/workers [get]
+func (h *WorkerHealthHandler) GetWorkerHealth(c *gin.Context) {
+	report, err := h.heartbeat.Check(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to check worker health", err.Error())
+		return
+	}
+	if !report.Healthy {
+		response.Success(c, http.StatusServiceUnavailable, "Worker jobs are stale", report)
+		return
+	}
+	response.Success(c, http.StatusOK, "Workers are healthy", report)
+}
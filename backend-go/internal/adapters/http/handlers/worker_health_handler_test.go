@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/services"
+)
+
+// memoryHeartbeatStore 内存版心跳哈希
+type memoryHeartbeatStore struct {
+	hash map[string]string
+}
+
+func (s *memoryHeartbeatStore) HSet(ctx context.Context, key string, values ...interface{}) error {
+	for i := 0; i+1 < len(values); i += 2 {
+		s.hash[fmt.Sprint(values[i])] = fmt.Sprint(values[i+1])
+	}
+	return nil
+}
+
+func (s *memoryHeartbeatStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.hash, nil
+}
+
+func (s *memoryHeartbeatStore) HDelete(ctx context.Context, key string, fields ...string) error {
+	for _, field := range fields {
+		delete(s.hash, field)
+	}
+	return nil
+}
+
+func (s *memoryHeartbeatStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+
+func TestWorkerHealthHandler_ReportsStaleJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	store := &memoryHeartbeatStore{hash: map[string]string{}}
+	heartbeat := services.NewWorkerHeartbeat(store, logger)
+
+	router := gin.New()
+	router.GET("/api/health/workers", NewWorkerHealthHandler(heartbeat).GetWorkerHealth)
+
+	check := func() (int, services.WorkerHealthReport) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/health/workers", nil))
+		var body struct {
+			Data services.WorkerHealthReport `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		return w.Code, body.Data
+	}
+
+	// 尚无任务上报心跳时 worker 视为未运行
+	if code, report := check(); code != http.StatusServiceUnavailable || report.Healthy {
+		t.Fatalf("无心跳 status = %d, report = %+v, want 503 unhealthy", code, report)
+	}
+
+	heartbeat.Job("stats_rollup", 24*time.Hour).Beat(context.Background())
+	if code, report := check(); code != http.StatusOK || !report.Healthy {
+		t.Fatalf("status = %d, report = %+v, want 200 healthy", code, report)
+	}
+
+	// 1 分钟间隔的中继 3 分钟前最后一次上报，模拟 worker 错过心跳
+	missed := fmt.Sprintf(`{"last_run":%q,"interval":%d}`, time.Now().Add(-3*time.Minute).UTC().Format(time.RFC3339), time.Minute)
+	store.hash["outbox_relay"] = missed
+
+	code, report := check()
+	if code != http.StatusServiceUnavailable || report.Healthy {
+		t.Fatalf("status = %d, healthy = %v, want 503 unhealthy", code, report.Healthy)
+	}
+	stale := map[string]bool{}
+	for _, job := range report.Jobs {
+		stale[job.Job] = job.Stale
+	}
+	if !stale["outbox_relay"] || stale["stats_rollup"] {
+		t.Errorf("stale = %v, want 仅 outbox_relay 停滞", stale)
+	}
+}
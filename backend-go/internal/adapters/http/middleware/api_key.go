@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/response"
+)
+
+// APIKeyHeader 服务间调用传递 API 密钥的请求头
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyContextKey 认证通过的 API 密钥调用方在 gin.Context 中的键
+const apiKeyContextKey = "api_key"
+
+// APIKeyValidator 校验 API 密钥，ports.AuthService 满足该接口
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*user.APIKeyContext, error)
+}
+
+// RequireAPIKey 通过 X-API-Key 请求头认证服务间调用，并要求密钥拥有全部 scopes
+//
+// 缺少或无效的密钥返回 401，已吊销的密钥返回 401，权限范围不足返回 403。
+func RequireAPIKey(validator APIKeyValidator, scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			response.Unauthorized(c, "X-API-Key header is required")
+			c.Abort()
+			return
+		}
+
+		caller, err := validator.ValidateAPIKey(c.Request.Context(), key)
+		if err != nil {
+			var appErr *response.AppError
+			switch {
+			case errors.Is(err, domain.ErrAPIKeyRevoked):
+				response.Unauthorized(c, "API key has been revoked")
+			case errors.Is(err, domain.ErrAPIKeyNotFound):
+				response.Unauthorized(c, "Invalid API key")
+			case errors.As(err, &appErr):
+				abortWithAppError(c, appErr)
+				return
+			default:
+				logger.Errorf("Failed to validate api key: %v", err)
+				response.InternalError(c, "Failed to validate API key")
+			}
+			c.Abort()
+			return
+		}
+
+		for _, scope := range scopes {
+			if !caller.HasScope(scope) {
+				logger.Warnf("API key %d (%s) lacks scope %s for %s", caller.KeyID, caller.Name, scope, c.Request.URL.Path)
+				response.Forbidden(c, "API key lacks required scope: "+scope)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set(apiKeyContextKey, caller)
+		c.Next()
+	}
+}
+
+// GetAPIKeyCaller 返回通过 API 密钥认证的调用方
+func GetAPIKeyCaller(c *gin.Context) (*user.APIKeyContext, bool) {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil, false
+	}
+	caller, ok := value.(*user.APIKeyContext)
+	return caller, ok
+}
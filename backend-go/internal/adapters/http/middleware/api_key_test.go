@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/services"
+)
+
+// memoryAPIKeyRepo 内存版 API 密钥仓储
+type memoryAPIKeyRepo struct {
+	keys []*user.APIKey
+}
+
+func (r *memoryAPIKeyRepo) Create(ctx context.Context, key *user.APIKey) error {
+	key.ID = uint(len(r.keys) + 1)
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryAPIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*user.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeyRepo) List(ctx context.Context) ([]*user.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *memoryAPIKeyRepo) Revoke(ctx context.Context, id uint, revokedAt time.Time) error {
+	for _, key := range r.keys {
+		if key.ID == id && key.IsActive {
+			key.IsActive = false
+			key.RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeyRepo) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	for _, key := range r.keys {
+		if key.ID == id {
+			key.LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func TestRequireAPIKey_EnforcesScopesAndRevocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	repo := &memoryAPIKeyRepo{}
+	auth := services.NewAuthService(nil, nil, nil, repo, nil, nil, nil, nil, services.AuthServiceConfig{})
+
+	key, plain, err := auth.CreateAPIKey(ctx, 1, "results-feed", []string{user.APIScopeMatchesRead})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(plain, key.Prefix) || repo.keys[0].KeyHash == plain || strings.Contains(repo.keys[0].KeyHash, plain) {
+		t.Fatalf("仓储中应只保存密钥哈希: %+v", repo.keys[0])
+	}
+
+	router := gin.New()
+	router.GET("/matches/:id", RequireAPIKey(auth, user.APIScopeMatchesRead), func(c *gin.Context) {
+		caller, _ := GetAPIKeyCaller(c)
+		c.String(http.StatusOK, caller.Name)
+	})
+	router.POST("/matches/:id/finish", RequireAPIKey(auth, user.APIScopeMatchesWrite), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	call := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if apiKey != "" {
+			req.Header.Set(APIKeyHeader, apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 拥有权限范围的密钥可以访问
+	if w := call(http.MethodGet, "/matches/1", plain); w.Code != http.StatusOK || w.Body.String() != "results-feed" {
+		t.Errorf("GET with read scope = %d %q, want 200", w.Code, w.Body.String())
+	}
+	if repo.keys[0].LastUsedAt == nil {
+		t.Error("认证成功后应记录最近使用时间")
+	}
+
+	// 缺少权限范围返回 403
+	if w := call(http.MethodPost, "/matches/1/finish", plain); w.Code != http.StatusForbidden {
+		t.Errorf("POST without write scope = %d, want 403", w.Code)
+	}
+
+	// 缺少或错误的密钥返回 401
+	if w := call(http.MethodGet, "/matches/1", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET without key = %d, want 401", w.Code)
+	}
+	if w := call(http.MethodGet, "/matches/1", plain+"x"); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET with wrong key = %d, want 401", w.Code)
+	}
+
+	// 吊销后立即失效
+	if err := auth.RevokeAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if w := call(http.MethodGet, "/matches/1", plain); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET with revoked key = %d, want 401", w.Code)
+	}
+	if err := auth.RevokeAPIKey(ctx, key.ID); err == nil {
+		t.Error("重复吊销应返回错误")
+	}
+}
+
+func TestCreateAPIKey_RejectsUnknownScope(t *testing.T) {
+	auth := services.NewAuthService(nil, nil, nil, &memoryAPIKeyRepo{}, nil, nil, nil, nil, services.AuthServiceConfig{})
+	if _, _, err := auth.CreateAPIKey(context.Background(), 1, "feed", []string{"users:delete"}); err == nil {
+		t.Error("未知权限范围应被拒绝")
+	}
+}
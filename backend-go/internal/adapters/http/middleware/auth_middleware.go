@@ -6,6 +6,7 @@ import (
 
 	"backend-go/internal/core/domain/user"
 	"backend-go/internal/shared/logger"
+	"backend-go/pkg/ctxkeys"
 	"backend-go/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -50,7 +51,7 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 
 		// 验证令牌
-		foundUser, err := m.userService.ValidateToken(c.Request.Context(), token)
+		identity, err := m.userService.Authenticate(c.Request.Context(), token)
 		if err != nil {
 			logger.Warnf("Invalid token: %v", err)
 			response.Unauthorized(c, "Invalid or expired token")
@@ -59,15 +60,25 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", strconv.FormatUint(uint64(foundUser.ID), 10))
-		c.Set("username", foundUser.Username)
-		c.Set("user_role", string(foundUser.Role))
-		c.Set("user", foundUser)
+		setCurrentUser(c, identity)
 
 		c.Next()
 	}
 }
 
+// RequireStreamAuth 长连接认证中间件，浏览器 EventSource 无法设置请求头时允许通过 token 查询参数传递令牌
+func (m *AuthMiddleware) RequireStreamAuth() gin.HandlerFunc {
+	requireAuth := m.RequireAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		requireAuth(c)
+	}
+}
+
 // RequireRole 需要特定角色的中间件
 func (m *AuthMiddleware) RequireRole(roles ...user.UserRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -79,6 +90,11 @@ func (m *AuthMiddleware) RequireRole(roles ...user.UserRole) gin.HandlerFunc {
 			return
 		}
 
+		// 代入令牌只能以普通用户身份访问
+		if rejectImpersonation(c) {
+			return
+		}
+
 		// 检查角色
 		currentRole := user.UserRole(userRole.(string))
 		for _, requiredRole := range roles {
@@ -107,6 +123,9 @@ func (m *AuthMiddleware) RequireSuperAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if rejectImpersonation(c) {
+			return
+		}
 
 		// 预留超级管理员标识：若上游已设置 is_super_admin=false，则阻断；未设置则视为通过
 		if isSuper, ok := c.Get("is_super_admin"); ok {
@@ -145,7 +164,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		}
 
 		// 验证令牌
-		foundUser, err := m.userService.ValidateToken(c.Request.Context(), token)
+		identity, err := m.userService.Authenticate(c.Request.Context(), token)
 		if err != nil {
 			// 令牌无效，但不阻止请求继续
 			c.Next()
@@ -153,15 +172,31 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", strconv.FormatUint(uint64(foundUser.ID), 10))
-		c.Set("username", foundUser.Username)
-		c.Set("user_role", string(foundUser.Role))
-		c.Set("user", foundUser)
+		setCurrentUser(c, identity)
 
 		c.Next()
 	}
 }
 
+// setCurrentUser 将认证用户写入 gin 上下文与请求上下文
+//
+// 代入令牌额外写入管理员ID，并通过 X-Impersonator-ID 响应头告知客户端当前处于代入状态。
+func setCurrentUser(c *gin.Context, identity *user.TokenIdentity) {
+	foundUser := identity.User
+	c.Set("user_id", strconv.FormatUint(uint64(foundUser.ID), 10))
+	c.Set("username", foundUser.Username)
+	c.Set("user_role", string(foundUser.Role))
+	c.Set("user", foundUser)
+
+	ctx := ctxkeys.WithUser(c.Request.Context(), foundUser.ID, foundUser.Username, string(foundUser.Role))
+	if identity.IsImpersonated() {
+		c.Set(impersonatorIDKey, identity.ImpersonatorID)
+		c.Header(ImpersonatorHeader, strconv.FormatUint(uint64(identity.ImpersonatorID), 10))
+		ctx = ctxkeys.WithImpersonatorID(ctx, identity.ImpersonatorID)
+	}
+	c.Request = c.Request.WithContext(ctx)
+}
+
 // GetCurrentUser 从上下文获取当前用户
 func GetCurrentUser(c *gin.Context) (*user.User, bool) {
 	userInterface, exists := c.Get("user")
@@ -175,12 +210,24 @@ func GetCurrentUser(c *gin.Context) (*user.User, bool) {
 
 // GetCurrentUserID 从上下文获取当前用户ID
 func GetCurrentUserID(c *gin.Context) (uint, bool) {
+	if c.Request != nil {
+		if userID, ok := ctxkeys.UserIDFrom(c.Request.Context()); ok {
+			return userID, true
+		}
+	}
+
+	// 兼容直接写入 gin 上下文的字符串用户ID
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		return 0, false
 	}
 
-	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	idStr, ok := userIDStr.(string)
+	if !ok {
+		return 0, false
+	}
+
+	userID, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
 		return 0, false
 	}
@@ -194,6 +241,9 @@ func IsAdmin(c *gin.Context) bool {
 	if !exists {
 		return false
 	}
+	if _, impersonated := GetImpersonatorID(c); impersonated {
+		return false
+	}
 
 	return userRole.(string) == string(user.UserRoleAdmin)
 }
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"backend-go/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitConfig 请求体大小限制配置
+type BodyLimitConfig struct {
+	MaxBytes  int64            // 默认上限（字节），<=0 表示不限制
+	Routes    map[string]int64 // 按路由模板（如 /api/admin/sport-types/batch-config）覆盖默认上限
+	SkipPaths []string         // 不限制的路由模板，如自行检查文件大小的上传接口
+}
+
+// BodyLimit 限制请求体大小，超出上限时在进入处理器前返回 413
+//
+// 声明了 Content-Length 的请求直接按长度判断；未声明长度（分块传输）的请求
+// 最多读取上限字节到内存后再交给处理器。
+func BodyLimit(config BodyLimitConfig) gin.HandlerFunc {
+	skip := make(map[string]bool, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || skip[route] {
+			c.Next()
+			return
+		}
+
+		limit := config.MaxBytes
+		if override, ok := config.Routes[route]; ok {
+			limit = override
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			abortRequestTooLarge(c, limit)
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		if c.Request.ContentLength < 0 {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					abortRequestTooLarge(c, limit)
+					return
+				}
+				response.BadRequest(c, "读取请求体失败")
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		c.Next()
+	}
+}
+
+// abortRequestTooLarge 返回请求体过大错误并中止请求
+func abortRequestTooLarge(c *gin.Context, limit int64) {
+	abortWithAppError(c, response.NewRequestTooLargeError(limit))
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter(t *testing.T, calls *int) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(BodyLimitConfig{
+		MaxBytes:  16,
+		Routes:    map[string]int64{"/bulk": 64},
+		SkipPaths: []string{"/upload"},
+	}))
+	handler := func(c *gin.Context) {
+		*calls++
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/items", handler)
+	router.POST("/bulk", handler)
+	router.POST("/upload", handler)
+	return router
+}
+
+func doPost(router *gin.Engine, path, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if chunked {
+		// 未声明长度的分块请求
+		req.ContentLength = -1
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBodyLimit_AllowsBodyUnderLimit(t *testing.T) {
+	var calls int
+	router := newBodyLimitRouter(t, &calls)
+
+	w := doPost(router, "/items", `{"id":1}`, false)
+	if w.Code != http.StatusOK || w.Body.String() != `{"id":1}` {
+		t.Fatalf("未超限的请求应正常处理: code = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = doPost(router, "/items", `{"id":2}`, true)
+	if w.Code != http.StatusOK || w.Body.String() != `{"id":2}` {
+		t.Errorf("未超限的分块请求应保留完整请求体: code = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestBodyLimit_RejectsOversizedBodyBeforeHandler(t *testing.T) {
+	var calls int
+	router := newBodyLimitRouter(t, &calls)
+	oversized := `{"name":"` + strings.Repeat("x", 32) + `"}`
+
+	for _, chunked := range []bool{false, true} {
+		w := doPost(router, "/items", oversized, chunked)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("chunked=%v: code = %d, want 413", chunked, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "REQUEST_TOO_LARGE") {
+			t.Errorf("chunked=%v: 响应应包含错误代码: %s", chunked, w.Body.String())
+		}
+	}
+	if calls != 0 {
+		t.Errorf("超限请求不应进入处理器, calls = %d", calls)
+	}
+}
+
+func TestBodyLimit_RouteOverrideAndSkip(t *testing.T) {
+	var calls int
+	router := newBodyLimitRouter(t, &calls)
+	body := strings.Repeat("x", 32)
+
+	if w := doPost(router, "/bulk", body, false); w.Code != http.StatusOK {
+		t.Errorf("路由覆盖的上限更大, code = %d", w.Code)
+	}
+	if w := doPost(router, "/bulk", strings.Repeat("x", 65), false); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("超过路由上限应被拒绝, code = %d", w.Code)
+	}
+	if w := doPost(router, "/upload", strings.Repeat("x", 1024), false); w.Code != http.StatusOK {
+		t.Errorf("上传接口不受请求体限制, code = %d", w.Code)
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// forwardedForHeader 负载均衡追加客户端地址的请求头
+const forwardedForHeader = "X-Forwarded-For"
+
+// ParseTrustedProxies 解析可信代理列表，元素为单个 IP 或 CIDR 网段
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// RealClientIP 返回请求的真实客户端 IP
+//
+// 只有直连对端属于可信代理时才读取 X-Forwarded-For，并从右向左跳过可信代理，
+// 取第一个不可信的地址；否则直接使用对端地址，客户端伪造的请求头不会生效。
+func RealClientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		peer = strings.TrimSpace(r.RemoteAddr)
+	}
+	if !isTrustedProxy(net.ParseIP(peer), trusted) {
+		return peer
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values(forwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			// 无法解析的地址之前的内容不可信
+			break
+		}
+		if i == 0 || !isTrustedProxy(ip, trusted) {
+			return hop
+		}
+	}
+	return peer
+}
+
+// TrustProxies 配置 gin 的可信代理，使 c.ClientIP() 与 RealClientIP 结果一致
+//
+// proxies 为空时不信任任何代理，c.ClientIP() 始终返回直连对端地址。gin 只读取第一个
+// X-Forwarded-For 请求头，代理另起一行追加时会取到客户端伪造的值，因此同时注册
+// 合并多个请求头的中间件，需要在其他中间件之前调用。
+func TrustProxies(engine *gin.Engine, proxies []string) error {
+	if _, err := ParseTrustedProxies(proxies); err != nil {
+		return err
+	}
+	engine.RemoteIPHeaders = []string{forwardedForHeader}
+	engine.TrustedPlatform = ""
+	if len(proxies) == 0 {
+		return engine.SetTrustedProxies(nil)
+	}
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		return err
+	}
+	engine.Use(mergeForwardedFor)
+	return nil
+}
+
+// mergeForwardedFor 把多个 X-Forwarded-For 请求头按顺序合并为一个
+func mergeForwardedFor(c *gin.Context) {
+	if values := c.Request.Header.Values(forwardedForHeader); len(values) > 1 {
+		c.Request.Header.Set(forwardedForHeader, strings.Join(values, ","))
+	}
+	c.Next()
+}
+
+func isTrustedProxy(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
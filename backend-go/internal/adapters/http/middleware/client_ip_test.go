@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newClientIPRouter(t *testing.T, proxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := TrustProxies(router, proxies); err != nil {
+		t.Fatalf("TrustProxies() error = %v", err)
+	}
+	router.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+	return router
+}
+
+func newForwardedRequest(remoteAddr string, forwardedFor ...string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = remoteAddr
+	for _, value := range forwardedFor {
+		req.Header.Add("X-Forwarded-For", value)
+	}
+	return req
+}
+
+func TestClientIP_TrustedProxyForwardsRealIP(t *testing.T) {
+	proxies := []string{"10.0.0.0/8", "192.168.1.10"}
+	trusted, err := ParseTrustedProxies(proxies)
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+	router := newClientIPRouter(t, proxies)
+
+	cases := []struct {
+		name string
+		req  *http.Request
+		want string
+	}{
+		{"经负载均衡转发", newForwardedRequest("10.0.0.5:40000", "203.0.113.7"), "203.0.113.7"},
+		{"多级可信代理", newForwardedRequest("192.168.1.10:40000", "203.0.113.7, 10.1.2.3"), "203.0.113.7"},
+		// 客户端自带的伪造地址位于真实地址左侧，不会被采用
+		{"伪造前缀", newForwardedRequest("10.0.0.5:40000", "1.2.3.4, 203.0.113.7"), "203.0.113.7"},
+		{"多个请求头", newForwardedRequest("10.0.0.5:40000", "1.2.3.4", "203.0.113.7"), "203.0.113.7"},
+		{"无转发头", newForwardedRequest("10.0.0.5:40000"), "10.0.0.5"},
+		{"无法解析的转发地址", newForwardedRequest("10.0.0.5:40000", "1.2.3.4, unknown"), "10.0.0.5"},
+	}
+	for _, tc := range cases {
+		if got := RealClientIP(tc.req, trusted); got != tc.want {
+			t.Errorf("%s: RealClientIP() = %s, want %s", tc.name, got, tc.want)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tc.req)
+		if got := w.Body.String(); got != tc.want {
+			t.Errorf("%s: c.ClientIP() = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestClientIP_UntrustedPeerCannotSpoof(t *testing.T) {
+	trusted, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	for _, proxies := range [][]string{{"10.0.0.0/8"}, nil} {
+		router := newClientIPRouter(t, proxies)
+		req := newForwardedRequest("198.51.100.2:5555", "1.2.3.4")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if got := w.Body.String(); got != "198.51.100.2" {
+			t.Errorf("proxies = %v: c.ClientIP() = %s, want 直连地址", proxies, got)
+		}
+	}
+	if got := RealClientIP(newForwardedRequest("198.51.100.2:5555", "1.2.3.4"), trusted); got != "198.51.100.2" {
+		t.Errorf("RealClientIP() = %s, want 直连地址", got)
+	}
+
+	// 更换伪造的请求头不能绕过按 IP 的限流
+	router := newClientIPRouter(t, []string{"10.0.0.0/8"})
+	router.GET("/limited", RateLimit(1, time.Minute), func(c *gin.Context) { c.Status(http.StatusOK) })
+	codes := make([]int, 0, 2)
+	for _, spoofed := range []string{"1.1.1.1", "2.2.2.2"} {
+		req := newForwardedRequest("198.51.100.2:5555", spoofed)
+		req.URL.Path = "/limited"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("status = %v, want [200 429]", codes)
+	}
+}
+
+func TestParseTrustedProxies_RejectsInvalidEntries(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "not-an-ip", ""} {
+		if _, err := ParseTrustedProxies([]string{proxy}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) error = nil, want 错误", proxy)
+		}
+	}
+	if err := TrustProxies(gin.New(), []string{"bad"}); err == nil {
+		t.Error("TrustProxies() 应拒绝无效的代理地址")
+	}
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// newConcurrencyRouter 的 /slow 接口阻塞到 release 关闭，entered 在每个请求进入处理器时收到通知
+func newConcurrencyRouter(config ConcurrencyLimitConfig, entered chan<- struct{}, release <-chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ConcurrencyLimit(config))
+	router.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// globalInFlight 读取全局并发数指标
+func globalInFlight(t *testing.T) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := concurrencyInFlight.WithLabelValues(concurrencyScopeGlobal).Write(&metric); err != nil {
+		t.Fatalf("读取指标失败: %v", err)
+	}
+	return metric.GetGauge().GetValue()
+}
+
+func serve(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+// saturate 发起 n 个慢请求并等待全部进入处理器
+func saturate(router *gin.Engine, n int, entered <-chan struct{}) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(router, "/slow")
+		}()
+	}
+	for i := 0; i < n; i++ {
+		<-entered
+	}
+	return &wg
+}
+
+func TestConcurrencyLimit_RejectsWhenSaturated(t *testing.T) {
+	entered, release := make(chan struct{}, 4), make(chan struct{})
+	router := newConcurrencyRouter(ConcurrencyLimitConfig{MaxInFlight: 2, RetryAfter: 1500 * time.Millisecond}, entered, release)
+
+	wg := saturate(router, 2, entered)
+	if got := globalInFlight(t); got != 2 {
+		t.Errorf("in-flight gauge = %v, want 2", got)
+	}
+
+	w := serve(router, "/fast")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("饱和时 status = %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if w := serve(router, "/fast"); w.Code != http.StatusOK {
+		t.Errorf("容量恢复后 status = %d, want 200", w.Code)
+	}
+	if got := globalInFlight(t); got != 0 {
+		t.Errorf("in-flight gauge = %v, want 0", got)
+	}
+}
+
+func TestConcurrencyLimit_QueuesUntilCapacityReturns(t *testing.T) {
+	entered, release := make(chan struct{}, 4), make(chan struct{})
+	router := newConcurrencyRouter(ConcurrencyLimitConfig{MaxInFlight: 1, QueueTimeout: 5 * time.Second}, entered, release)
+
+	wg := saturate(router, 1, entered)
+
+	queued := make(chan int)
+	go func() {
+		queued <- serve(router, "/fast").Code
+	}()
+
+	select {
+	case code := <-queued:
+		t.Fatalf("容量释放前排队请求不应完成, status = %d", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if code := <-queued; code != http.StatusOK {
+		t.Errorf("排队请求 status = %d, want 200", code)
+	}
+	wg.Wait()
+}
+
+func TestConcurrencyLimit_PerRouteBulkhead(t *testing.T) {
+	entered, release := make(chan struct{}, 4), make(chan struct{})
+	router := newConcurrencyRouter(ConcurrencyLimitConfig{MaxInFlight: 10, Routes: map[string]int{"/slow": 1}}, entered, release)
+
+	wg := saturate(router, 1, entered)
+
+	// 慢接口已占满自己的隔离舱，其他接口不受影响
+	if w := serve(router, "/slow"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("/slow status = %d, want 503", w.Code)
+	}
+	if w := serve(router, "/fast"); w.Code != http.StatusOK {
+		t.Errorf("/fast status = %d, want 200", w.Code)
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimit_SkipsStreamConnections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	entered, release := make(chan struct{}, 4), make(chan struct{})
+
+	router := gin.New()
+	router.Use(ConcurrencyLimit(ConcurrencyLimitConfig{MaxInFlight: 1, SkipPrefixes: []string{"/api/stream/"}}))
+	router.GET("/api/stream/leaderboard", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// 打开的推送连接数超过全局上限，普通请求仍有槽位
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(router, "/api/stream/leaderboard")
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		<-entered
+	}
+
+	if w := serve(router, "/fast"); w.Code != http.StatusOK {
+		t.Errorf("推送连接打开时 /fast status = %d, want 200", w.Code)
+	}
+	if got := globalInFlight(t); got != 0 {
+		t.Errorf("in-flight gauge = %v, want 0", got)
+	}
+
+	close(release)
+	wg.Wait()
+}
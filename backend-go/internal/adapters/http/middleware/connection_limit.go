@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"backend-go/pkg/response"
+)
+
+var connectionAttemptsRejected = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "realtime_connection_attempts_rejected_total",
+		Help: "Total number of realtime connection attempts rejected by the per-IP connection limiter",
+	},
+)
+
+// ConnectionAttemptStore 记录连接尝试次数，redis.CacheService 满足该接口
+type ConnectionAttemptStore interface {
+	Increment(ctx context.Context, key string) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+}
+
+// ConnectionLimitConfig 实时连接建立频率限制配置
+//
+// 多个实例共享同一存储，因此限制对整个集群生效。
+type ConnectionLimitConfig struct {
+	MaxPerWindow int           // 每个 IP 在一个窗口内允许建立的连接数，<=0 表示不限制
+	Window       time.Duration // 固定窗口长度，默认 1 分钟
+	Store        ConnectionAttemptStore
+}
+
+// ConnectionRateLimit 按客户端 IP 限制新建实时连接的频率，超出后返回 429 并携带 Retry-After
+//
+// 只统计连接建立请求，已建立的长连接不受影响。存储不可用时放行，避免缓存故障导致实时推送整体不可用。
+func ConnectionRateLimit(config ConnectionLimitConfig) gin.HandlerFunc {
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+
+	return func(c *gin.Context) {
+		if config.Store == nil || config.MaxPerWindow <= 0 {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		window := now.UnixNano() / int64(config.Window)
+		key := fmt.Sprintf("realtime:conn_attempts:%s:%d", c.ClientIP(), window)
+
+		ctx := c.Request.Context()
+		count, err := config.Store.Increment(ctx, key)
+		if err != nil {
+			c.Next()
+			return
+		}
+		if count == 1 {
+			// 键名包含窗口序号，过期时间只用于回收旧窗口的计数
+			_ = config.Store.Expire(ctx, key, config.Window)
+		}
+
+		if count > int64(config.MaxPerWindow) {
+			connectionAttemptsRejected.Inc()
+			resetAt := time.Unix(0, (window+1)*int64(config.Window))
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(resetAt.Sub(now).Seconds()))))
+			abortWithAppError(c, response.NewRateLimitError(config.MaxPerWindow, config.Window.String()))
+			return
+		}
+
+		c.Next()
+	}
+}
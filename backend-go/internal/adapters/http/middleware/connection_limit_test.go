@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// memoryAttemptStore 内存版连接计数存储
+type memoryAttemptStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	err    error
+}
+
+func newMemoryAttemptStore() *memoryAttemptStore {
+	return &memoryAttemptStore{counts: map[string]int64{}}
+}
+
+func (s *memoryAttemptStore) Increment(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.counts[key]++
+	return s.counts[key], nil
+}
+
+func (s *memoryAttemptStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+
+func newConnectionLimitRouter(config ConnectionLimitConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ConnectionRateLimit(config))
+	router.GET("/stream", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func connect(router *gin.Engine, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.RemoteAddr = ip + ":40000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// rejectedConnections 读取被拒绝的连接数指标
+func rejectedConnections(t *testing.T) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := connectionAttemptsRejected.Write(&metric); err != nil {
+		t.Fatalf("读取指标失败: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestConnectionRateLimit_RejectsExcessAttempts(t *testing.T) {
+	router := newConnectionLimitRouter(ConnectionLimitConfig{MaxPerWindow: 3, Window: time.Hour, Store: newMemoryAttemptStore()})
+	before := rejectedConnections(t)
+
+	for i := 0; i < 3; i++ {
+		if w := connect(router, "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("第 %d 次连接 status = %d, want 200", i+1, w.Code)
+		}
+	}
+
+	w := connect(router, "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("超出上限 status = %d, want 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("超出上限时应返回 Retry-After")
+	}
+	if got := rejectedConnections(t) - before; got != 1 {
+		t.Errorf("rejected metric delta = %v, want 1", got)
+	}
+
+	// 其他 IP 的计数相互独立
+	if w := connect(router, "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("其他 IP status = %d, want 200", w.Code)
+	}
+}
+
+func TestConnectionRateLimit_FailsOpenWhenStoreUnavailable(t *testing.T) {
+	store := newMemoryAttemptStore()
+	store.err = errors.New("redis down")
+	router := newConnectionLimitRouter(ConnectionLimitConfig{MaxPerWindow: 1, Store: store})
+
+	for i := 0; i < 3; i++ {
+		if w := connect(router, "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("存储不可用时 status = %d, want 200", w.Code)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// featureFlagsContextKey 请求上下文中功能开关判断结果的键
+const featureFlagsContextKey = "feature_flags"
+
+// featureFlagChecker 判断功能开关是否对用户开启，services.FeatureFlags 满足该接口
+type featureFlagChecker interface {
+	IsEnabledForUser(flag string, userID uint) bool
+}
+
+// requestFeatureFlags 单个请求内的功能开关判断结果
+type requestFeatureFlags struct {
+	flags   featureFlagChecker
+	mu      sync.Mutex
+	results map[string]bool
+}
+
+// FeatureFlagContext 为每个请求附加功能开关上下文，处理器通过 FeatureEnabled 判断
+func FeatureFlagContext(flags featureFlagChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(featureFlagsContextKey, &requestFeatureFlags{flags: flags, results: make(map[string]bool)})
+		c.Next()
+	}
+}
+
+// FeatureEnabled 判断功能开关是否对当前用户开启，未认证或未注册 FeatureFlagContext 时关闭
+//
+// 同一请求内首次判断的结果会被记住，请求处理期间加载了新的灰度配置也不会前后不一致。
+func FeatureEnabled(c *gin.Context, flag string) bool {
+	value, exists := c.Get(featureFlagsContextKey)
+	if !exists {
+		return false
+	}
+	request, ok := value.(*requestFeatureFlags)
+	if !ok {
+		return false
+	}
+	userID, ok := GetCurrentUserID(c)
+	if !ok {
+		return false
+	}
+
+	request.mu.Lock()
+	defer request.mu.Unlock()
+	if enabled, ok := request.results[flag]; ok {
+		return enabled
+	}
+	enabled := request.flags.IsEnabledForUser(flag, userID)
+	request.results[flag] = enabled
+	return enabled
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// toggleFeatureFlags 可在请求处理中途切换结果的功能开关
+type toggleFeatureFlags struct {
+	enabled bool
+	calls   int
+}
+
+func (f *toggleFeatureFlags) IsEnabledForUser(flag string, userID uint) bool {
+	f.calls++
+	return f.enabled && userID == 7
+}
+
+func serveFeatureFlagRequest(flags *toggleFeatureFlags, userID string, handler gin.HandlerFunc) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if flags != nil {
+		router.Use(FeatureFlagContext(flags))
+	}
+	router.GET("/", func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+		handler(c)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestFeatureEnabled_ConsistentWithinRequest(t *testing.T) {
+	flags := &toggleFeatureFlags{enabled: true}
+	var first, second bool
+	serveFeatureFlagRequest(flags, "7", func(c *gin.Context) {
+		first = FeatureEnabled(c, "new_scoring")
+		flags.enabled = false
+		second = FeatureEnabled(c, "new_scoring")
+	})
+
+	if !first || !second {
+		t.Errorf("同一请求内结果应保持一致, first=%v second=%v", first, second)
+	}
+	if flags.calls != 1 {
+		t.Errorf("同一请求内应只判断一次, calls=%d", flags.calls)
+	}
+}
+
+func TestFeatureEnabled_DisabledWithoutUserOrContext(t *testing.T) {
+	flags := &toggleFeatureFlags{enabled: true}
+	serveFeatureFlagRequest(flags, "", func(c *gin.Context) {
+		if FeatureEnabled(c, "new_scoring") {
+			t.Error("未认证的请求应关闭功能开关")
+		}
+	})
+	serveFeatureFlagRequest(nil, "7", func(c *gin.Context) {
+		if FeatureEnabled(c, "new_scoring") {
+			t.Error("未注册功能开关上下文时应关闭")
+		}
+	})
+}
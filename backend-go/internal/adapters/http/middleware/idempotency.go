@@ -0,0 +1,353 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"backend-go/internal/shared/jwt"
+	"backend-go/pkg/response"
+)
+
+// 幂等请求相关请求头
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// 幂等请求结果指标标签
+const (
+	idempotencyResultHit      = "hit"
+	idempotencyResultMiss     = "miss"
+	idempotencyResultMismatch = "mismatch"  // 幂等键已用于请求体不同的请求
+	idempotencyResultInFlight = "in_flight" // 相同幂等键的首次请求仍在处理
+)
+
+// idempotencyExcludedPrefixes 不参与幂等去重的路径前缀，登录、刷新令牌等响应携带令牌，不能写入缓存
+var idempotencyExcludedPrefixes = []string{"/api/auth/"}
+
+var idempotencyRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_idempotency_requests_total",
+		Help: "Total number of requests carrying an Idempotency-Key, by route and whether a cached response was replayed",
+	},
+	[]string{"route", "result"},
+)
+
+// IdempotencyStore 保存幂等请求的响应，redis.CacheService 满足该接口
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Lock(ctx context.Context, key string, expiration time.Duration) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
+// IdempotencyTokenValidator 校验 Bearer 访问令牌，jwt.JWTService 满足该接口
+type IdempotencyTokenValidator interface {
+	ValidateToken(tokenString string) (*jwt.Claims, error)
+}
+
+// IdempotencyConfig 幂等请求配置
+type IdempotencyConfig struct {
+	Store   IdempotencyStore
+	Tokens  IdempotencyTokenValidator // 从访问令牌解析用户以按用户隔离幂等键，为空或令牌无效时按 API Key 或客户端 IP 隔离
+	TTL     time.Duration             // 响应保留时长，期间相同幂等键的重试直接返回首次响应
+	LockTTL time.Duration             // 首次请求处理期间持有的锁时长，默认 1 分钟，应长于请求处理时限
+}
+
+// idempotentResponse 首次请求的响应
+type idempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+	BodyHash    string `json:"body_hash"` // 首次请求的请求体摘要，重试的请求体必须一致
+}
+
+// idempotencyCounter 单个路由的命中统计
+type idempotencyCounter struct {
+	hits   int64
+	misses int64
+}
+
+// IdempotencyRouteStats 单个路由的幂等请求统计
+type IdempotencyRouteStats struct {
+	Route    string  `json:"route"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// IdempotencyReport 幂等请求统计报告
+type IdempotencyReport struct {
+	Hits     int64                   `json:"hits"`
+	Misses   int64                   `json:"misses"`
+	HitRatio float64                 `json:"hit_ratio"`
+	Routes   []IdempotencyRouteStats `json:"routes"`
+}
+
+// Idempotency 基于 Idempotency-Key 请求头的重复请求去重
+//
+// 写请求携带 Idempotency-Key 时，首次响应（5xx 除外）按调用方、路由与幂等键
+// 缓存；TTL 内的重试直接返回缓存的响应并标记 Idempotent-Replayed: true。
+// 重试的请求体与首次请求不一致时返回 422；首次请求处理期间持有锁，并发的重复
+// 请求返回 409。/api/auth/ 下的接口不参与去重。
+// 命中（重试）与未命中（首次请求）按路由计数，用于评估客户端重试频率。
+type Idempotency struct {
+	config IdempotencyConfig
+
+	mu     sync.Mutex
+	routes map[string]*idempotencyCounter
+}
+
+// NewIdempotency 创建幂等请求中间件
+func NewIdempotency(config IdempotencyConfig) *Idempotency {
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTTL <= 0 {
+		config.LockTTL = time.Minute
+	}
+	return &Idempotency{
+		config: config,
+		routes: make(map[string]*idempotencyCounter),
+	}
+}
+
+// Middleware 幂等请求中间件
+func (i *Idempotency) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isIdempotencyMethod(c.Request.Method) || isIdempotencyExcluded(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		bodyHash, err := fingerprintBody(c.Request)
+		if err != nil {
+			abortWithAppError(c, response.NewBadRequestError("读取请求体失败", nil))
+			return
+		}
+
+		ctx := c.Request.Context()
+		route := c.Request.Method + " " + routeLabel(c)
+		cacheKey := i.cacheKey(c, route, key)
+
+		if i.replay(c, route, cacheKey, bodyHash) {
+			return
+		}
+
+		// 首次请求处理期间持有锁，存储不可用时不阻塞请求
+		if i.config.Store != nil {
+			locked, err := i.config.Store.Lock(ctx, cacheKey, i.config.LockTTL)
+			if err == nil && !locked {
+				idempotencyRequests.WithLabelValues(route, idempotencyResultInFlight).Inc()
+				c.Header("Retry-After", "1")
+				abortWithAppError(c, response.NewConflictError("相同幂等键的请求正在处理中", nil))
+				return
+			}
+			if err == nil {
+				defer i.config.Store.Unlock(context.WithoutCancel(ctx), cacheKey)
+				// 加锁前首次请求可能刚好完成
+				if i.replay(c, route, cacheKey, bodyHash) {
+					return
+				}
+			}
+		}
+		i.record(route, idempotencyResultMiss)
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// 5xx 视为未处理，允许客户端使用同一幂等键重试
+		if writer.Status() < http.StatusInternalServerError {
+			i.save(context.WithoutCancel(ctx), cacheKey, &idempotentResponse{
+				Status:      writer.Status(),
+				ContentType: writer.Header().Get("Content-Type"),
+				Body:        writer.body.Bytes(),
+				BodyHash:    bodyHash,
+			})
+		}
+	}
+}
+
+// replay 存在缓存的首次响应时直接返回，请求体与首次请求不一致时返回 422
+func (i *Idempotency) replay(c *gin.Context, route, cacheKey, bodyHash string) bool {
+	cached, ok := i.load(c.Request.Context(), cacheKey)
+	if !ok {
+		return false
+	}
+	if cached.BodyHash != "" && cached.BodyHash != bodyHash {
+		idempotencyRequests.WithLabelValues(route, idempotencyResultMismatch).Inc()
+		abortWithAppError(c, response.NewValidationError("幂等键已用于请求内容不同的请求", nil))
+		return true
+	}
+
+	i.record(route, idempotencyResultHit)
+	c.Header(IdempotencyReplayedHeader, "true")
+	c.Data(cached.Status, cached.ContentType, cached.Body)
+	c.Abort()
+	return true
+}
+
+// Report 返回幂等请求统计，命中次数最多的路由排在最前
+func (i *Idempotency) Report() IdempotencyReport {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	report := IdempotencyReport{Routes: make([]IdempotencyRouteStats, 0, len(i.routes))}
+	for route, counter := range i.routes {
+		report.Hits += counter.hits
+		report.Misses += counter.misses
+		report.Routes = append(report.Routes, IdempotencyRouteStats{
+			Route:    route,
+			Hits:     counter.hits,
+			Misses:   counter.misses,
+			HitRatio: hitRatio(counter.hits, counter.misses),
+		})
+	}
+	report.HitRatio = hitRatio(report.Hits, report.Misses)
+
+	sort.Slice(report.Routes, func(a, b int) bool {
+		if report.Routes[a].Hits != report.Routes[b].Hits {
+			return report.Routes[a].Hits > report.Routes[b].Hits
+		}
+		return report.Routes[a].Route < report.Routes[b].Route
+	})
+	return report
+}
+
+// record 记录一次命中或未命中
+func (i *Idempotency) record(route, result string) {
+	idempotencyRequests.WithLabelValues(route, result).Inc()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	counter, ok := i.routes[route]
+	if !ok {
+		counter = &idempotencyCounter{}
+		i.routes[route] = counter
+	}
+	if result == idempotencyResultHit {
+		counter.hits++
+	} else {
+		counter.misses++
+	}
+}
+
+// cacheKey 按调用方隔离幂等键，避免不同用户使用相同的键时互相读取响应
+func (i *Idempotency) cacheKey(c *gin.Context, route, key string) string {
+	sum := sha256.Sum256([]byte(i.caller(c) + "\n" + route + "\n" + key))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+// caller 返回请求的调用方标识
+//
+// 幂等中间件在认证之前执行，持有有效访问令牌的请求按令牌中的用户区分，
+// 同一用户刷新令牌后的重试仍能命中首次响应；代入令牌额外区分发起代入的管理员。
+func (i *Idempotency) caller(c *gin.Context) string {
+	if i.config.Tokens != nil {
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && token != "" {
+			if claims, err := i.config.Tokens.ValidateToken(token); err == nil && claims.Type == "access" {
+				caller := "user:" + strconv.FormatUint(uint64(claims.UserID), 10)
+				if claims.ImpersonatorID != 0 {
+					caller += ":impersonator:" + strconv.FormatUint(uint64(claims.ImpersonatorID), 10)
+				}
+				return caller
+			}
+		}
+	}
+	if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+		return "api_key:" + apiKey
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func (i *Idempotency) load(ctx context.Context, key string) (*idempotentResponse, bool) {
+	if i.config.Store == nil {
+		return nil, false
+	}
+	data, err := i.config.Store.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+
+	var cached idempotentResponse
+	if err := json.Unmarshal([]byte(data), &cached); err != nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+func (i *Idempotency) save(ctx context.Context, key string, resp *idempotentResponse) {
+	if i.config.Store == nil {
+		return
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	i.config.Store.Set(ctx, key, string(data), i.config.TTL)
+}
+
+// fingerprintBody 计算请求体摘要，读取后恢复请求体供处理器使用
+func fingerprintBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		sum := sha256.Sum256(nil)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isIdempotencyExcluded 判断路径是否不参与幂等去重
+func isIdempotencyExcluded(path string) bool {
+	for _, prefix := range idempotencyExcludedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotencyMethod 只有写请求需要去重
+func isIdempotencyMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// routeLabel 使用路由模板作为指标标签，避免路径参数导致标签基数膨胀
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
@@ -0,0 +1,306 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/prometheus/client_model/go"
+
+	"backend-go/internal/shared/jwt"
+)
+
+// memoryIdempotencyStore 内存版幂等响应存储
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	values map[string]string
+	locks  map[string]bool
+}
+
+func (s *memoryIdempotencyStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func (s *memoryIdempotencyStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value.(string)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks == nil {
+		s.locks = map[string]bool{}
+	}
+	if s.locks[key] {
+		return false, nil
+	}
+	s.locks[key] = true
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Unlock(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locks, key)
+	return nil
+}
+
+// idempotencyCount 读取幂等请求计数指标
+func idempotencyCount(t *testing.T, route, result string) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := idempotencyRequests.WithLabelValues(route, result).Write(&metric); err != nil {
+		t.Fatalf("读取指标失败: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestIdempotency_CountsMissThenHit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idem := NewIdempotency(IdempotencyConfig{Store: &memoryIdempotencyStore{values: map[string]string{}}})
+
+	calls := 0
+	router := gin.New()
+	router.Use(idem.Middleware())
+	router.POST("/predictions/:id", func(c *gin.Context) {
+		calls++
+		c.String(http.StatusCreated, "created-"+strconv.Itoa(calls))
+	})
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/predictions/7", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	const route = "POST /predictions/:id"
+	hits := idempotencyCount(t, route, idempotencyResultHit)
+	misses := idempotencyCount(t, route, idempotencyResultMiss)
+
+	// 首次请求计为未命中
+	first := call("retry-1")
+	if first.Code != http.StatusCreated || first.Body.String() != "created-1" {
+		t.Fatalf("first request = %d %q", first.Code, first.Body.String())
+	}
+	if got := idempotencyCount(t, route, idempotencyResultMiss); got != misses+1 {
+		t.Errorf("miss counter = %v, want %v", got, misses+1)
+	}
+
+	// 重复请求直接返回首次响应并计为命中
+	dup := call("retry-1")
+	if dup.Code != http.StatusCreated || dup.Body.String() != "created-1" || dup.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Errorf("duplicate request = %d %q, want replay of first response", dup.Code, dup.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if got := idempotencyCount(t, route, idempotencyResultHit); got != hits+1 {
+		t.Errorf("hit counter = %v, want %v", got, hits+1)
+	}
+
+	// 不带幂等键的请求不参与统计
+	call("")
+
+	report := idem.Report()
+	if report.Hits != 1 || report.Misses != 1 || report.HitRatio != 0.5 {
+		t.Errorf("report totals = %+v, want 1 hit 1 miss", report)
+	}
+	if len(report.Routes) != 1 || report.Routes[0].Route != route || report.Routes[0].Hits != 1 {
+		t.Errorf("report routes = %+v", report.Routes)
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotReplayed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idem := NewIdempotency(IdempotencyConfig{Store: &memoryIdempotencyStore{values: map[string]string{}}})
+
+	calls := 0
+	router := gin.New()
+	router.Use(idem.Middleware())
+	router.POST("/predictions", func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusInternalServerError)
+	})
+
+	for n := 0; n < 2; n++ {
+		req := httptest.NewRequest(http.MethodPost, "/predictions", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-2")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2 (5xx must not be cached)", calls)
+	}
+	if report := idem.Report(); report.Hits != 0 || report.Misses != 2 {
+		t.Errorf("report = %+v, want 0 hits 2 misses", report)
+	}
+}
+
+func TestIdempotency_RejectsReusedKeyWithDifferentBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idem := NewIdempotency(IdempotencyConfig{Store: &memoryIdempotencyStore{values: map[string]string{}}})
+
+	calls := 0
+	router := gin.New()
+	router.Use(idem.Middleware())
+	router.POST("/predictions", func(c *gin.Context) {
+		calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusCreated, string(body))
+	})
+	call := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/predictions", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "retry-3")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 处理器仍能读到完整的请求体
+	if first := call(`{"winner":"A"}`); first.Body.String() != `{"winner":"A"}` {
+		t.Fatalf("first body = %q", first.Body.String())
+	}
+	if mismatch := call(`{"winner":"B"}`); mismatch.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different body status = %d, want 422", mismatch.Code)
+	}
+	if replay := call(`{"winner":"A"}`); replay.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Errorf("same body should replay, got %d %q", replay.Code, replay.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+}
+
+func TestIdempotency_ConcurrentDuplicateIsRejectedWhileInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idem := NewIdempotency(IdempotencyConfig{Store: &memoryIdempotencyStore{values: map[string]string{}}})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := gin.New()
+	router.Use(idem.Middleware())
+	router.POST("/predictions", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusCreated, "created")
+	})
+	call := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/predictions", strings.NewReader("{}"))
+		req.Header.Set(IdempotencyKeyHeader, "retry-4")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- call() }()
+	<-started
+
+	if dup := call(); dup.Code != http.StatusConflict || dup.Header().Get("Retry-After") == "" {
+		t.Errorf("in-flight duplicate = %d, want 409 with Retry-After", dup.Code)
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first request = %d", first.Code)
+	}
+
+	// 首次请求完成后释放锁，重试返回缓存的响应
+	if replay := call(); replay.Code != http.StatusCreated || replay.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Errorf("retry after completion = %d, want replay", replay.Code)
+	}
+}
+
+func TestIdempotency_SkipsAuthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryIdempotencyStore{values: map[string]string{}}
+	idem := NewIdempotency(IdempotencyConfig{Store: store})
+
+	calls := 0
+	router := gin.New()
+	router.Use(idem.Middleware())
+	router.POST("/api/auth/login", func(c *gin.Context) {
+		calls++
+		c.String(http.StatusOK, "token-"+strconv.Itoa(calls))
+	})
+
+	for n := 0; n < 2; n++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+		req.Header.Set(IdempotencyKeyHeader, "login-1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if calls != 2 || len(store.values) != 0 {
+		t.Errorf("calls = %d, stored = %d, want auth responses never cached", calls, len(store.values))
+	}
+}
+
+// staticTokenValidator 按令牌字符串返回预设声明的令牌校验器
+type staticTokenValidator map[string]*jwt.Claims
+
+func (v staticTokenValidator) ValidateToken(token string) (*jwt.Claims, error) {
+	if claims, ok := v[token]; ok {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+func TestIdempotency_KeysOnAuthenticatedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idem := NewIdempotency(IdempotencyConfig{
+		Store: &memoryIdempotencyStore{values: map[string]string{}},
+		Tokens: staticTokenValidator{
+			"alice-old": {UserID: 1, Type: "access"},
+			"alice-new": {UserID: 1, Type: "access"},
+			"bob":       {UserID: 2, Type: "access"},
+		},
+	})
+
+	calls := 0
+	router := gin.New()
+	router.Use(idem.Middleware())
+	router.POST("/predictions", func(c *gin.Context) {
+		calls++
+		c.String(http.StatusCreated, "created-"+strconv.Itoa(calls))
+	})
+	call := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/predictions", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	call("alice-old")
+	// 同一用户刷新令牌后的重试命中首次响应
+	if w := call("alice-new"); w.Header().Get(IdempotencyReplayedHeader) != "true" || w.Body.String() != "created-1" {
+		t.Errorf("retry with refreshed token = %q, want replay of first response", w.Body.String())
+	}
+	// 其他用户使用相同幂等键不会读到该响应
+	if w := call("bob"); w.Header().Get(IdempotencyReplayedHeader) == "true" || w.Body.String() != "created-2" {
+		t.Errorf("other user's request = %q, want handled separately", w.Body.String())
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/response"
+)
+
+// ImpersonatorHeader 代入期间的响应头，值为发起代入的管理员ID
+const ImpersonatorHeader = "X-Impersonator-ID"
+
+// impersonatorIDKey 代入管理员ID在 gin.Context 中的键
+const impersonatorIDKey = "impersonator_id"
+
+// ImpersonationAuditLogger 记录代入期间的操作，ports.AdminAuditService 满足该接口
+type ImpersonationAuditLogger interface {
+	LogAction(ctx context.Context, req *ports.LogActionRequest) error
+}
+
+// GetImpersonatorID 返回代入当前用户的管理员ID，未处于代入状态时返回 false
+func GetImpersonatorID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get(impersonatorIDKey)
+	if !exists {
+		return 0, false
+	}
+	impersonatorID, ok := value.(uint)
+	return impersonatorID, ok && impersonatorID != 0
+}
+
+// rejectImpersonation 代入状态下拒绝管理员操作，已拒绝时返回 true
+func rejectImpersonation(c *gin.Context) bool {
+	impersonatorID, impersonated := GetImpersonatorID(c)
+	if !impersonated {
+		return false
+	}
+
+	logger.Warnf("Admin action blocked under impersonation: admin %d, path %s", impersonatorID, c.Request.URL.Path)
+	abortWithAppError(c, response.NewForbiddenError("代入状态下不能执行管理员操作"))
+	return true
+}
+
+// ImpersonationAudit 为代入期间的每个请求写入审计日志，同时记录管理员ID与被代入的用户ID
+//
+// 认证中间件注册在各路由组上，因此本中间件需全局注册，在请求处理完成后读取认证结果。
+func ImpersonationAudit(auditLogger ImpersonationAuditLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		impersonatorID, impersonated := GetImpersonatorID(c)
+		if !impersonated || auditLogger == nil {
+			return
+		}
+		userID, _ := GetCurrentUserID(c)
+
+		status := admin.AuditStatusSuccess
+		errorMsg := ""
+		if c.Writer.Status() >= 400 {
+			status = admin.AuditStatusFailed
+			errorMsg = http.StatusText(c.Writer.Status())
+		}
+
+		// 客户端断开不应影响审计写入
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+		defer cancel()
+		err := auditLogger.LogAction(ctx, &ports.LogActionRequest{
+			AdminUserID: impersonatorID,
+			Action:      "impersonated_request",
+			Resource:    "user",
+			ResourceID:  strconv.FormatUint(uint64(userID), 10),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			IPAddress:   c.ClientIP(),
+			UserAgent:   c.Request.UserAgent(),
+			NewValues: map[string]interface{}{
+				"impersonator_id":      impersonatorID,
+				"impersonated_user_id": userID,
+			},
+			Status:   status,
+			ErrorMsg: errorMsg,
+			Duration: time.Since(start).Milliseconds(),
+		})
+		if err != nil {
+			logger.Errorf("Failed to audit impersonated request: admin %d, user %d, %s %s: %v",
+				impersonatorID, userID, c.Request.Method, c.Request.URL.Path, err)
+		}
+	}
+}
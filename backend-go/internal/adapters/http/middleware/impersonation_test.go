@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+	"backend-go/pkg/ctxkeys"
+)
+
+// tokenUserService 按令牌字符串返回预设身份
+type tokenUserService struct {
+	user.Service
+	identities map[string]*user.TokenIdentity
+}
+
+func (s *tokenUserService) Authenticate(ctx context.Context, token string) (*user.TokenIdentity, error) {
+	if identity, ok := s.identities[token]; ok {
+		return identity, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// recordingAuditLogger 记录写入的审计日志
+type recordingAuditLogger struct {
+	mu      sync.Mutex
+	entries []*ports.LogActionRequest
+}
+
+func (l *recordingAuditLogger) LogAction(ctx context.Context, req *ports.LogActionRequest) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, req)
+	return nil
+}
+
+func newImpersonationRouter(audit *recordingAuditLogger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	auth := NewAuthMiddleware(&tokenUserService{identities: map[string]*user.TokenIdentity{
+		"user-token":          {User: &user.User{ID: 2, Username: "bob", Role: user.UserRoleUser}},
+		"impersonation-token": {User: &user.User{ID: 2, Username: "bob", Role: user.UserRoleUser}, ImpersonatorID: 1},
+		// 即使目标角色为管理员，代入令牌也不能通过管理员校验
+		"impersonated-admin-token": {User: &user.User{ID: 3, Username: "carol", Role: user.UserRoleAdmin}, ImpersonatorID: 1},
+	}})
+
+	router := gin.New()
+	router.Use(ImpersonationAudit(audit))
+	router.GET("/api/profile", auth.RequireAuth(), func(c *gin.Context) {
+		userID, _ := ctxkeys.UserIDFrom(c.Request.Context())
+		impersonatorID, _ := ctxkeys.ImpersonatorIDFrom(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "impersonator_id": impersonatorID})
+	})
+	router.GET("/api/users", auth.RequireAuth(), auth.RequireAdmin(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.DELETE("/api/users/:id", auth.RequireAuth(), auth.RequireSuperAdmin(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func doImpersonationRequest(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImpersonation_AuthenticatesAsTarget(t *testing.T) {
+	audit := &recordingAuditLogger{}
+	router := newImpersonationRouter(audit)
+
+	w := doImpersonationRequest(router, http.MethodGet, "/api/profile", "impersonation-token")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if body := w.Body.String(); body != `{"impersonator_id":1,"user_id":2}` {
+		t.Errorf("body = %s, want user 2 impersonated by 1", body)
+	}
+	if got := w.Header().Get(ImpersonatorHeader); got != "1" {
+		t.Errorf("%s = %q, want 1", ImpersonatorHeader, got)
+	}
+}
+
+func TestImpersonation_BlocksAdminRoutes(t *testing.T) {
+	audit := &recordingAuditLogger{}
+	router := newImpersonationRouter(audit)
+
+	tests := []struct {
+		method, path, token string
+	}{
+		{http.MethodGet, "/api/users", "impersonation-token"},
+		{http.MethodGet, "/api/users", "impersonated-admin-token"},
+		{http.MethodDelete, "/api/users/5", "impersonated-admin-token"},
+	}
+	for _, tt := range tests {
+		if w := doImpersonationRequest(router, tt.method, tt.path, tt.token); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s with %s: status = %d, want 403", tt.method, tt.path, tt.token, w.Code)
+		}
+	}
+}
+
+func TestImpersonation_AuditsEveryRequestWithBothIDs(t *testing.T) {
+	audit := &recordingAuditLogger{}
+	router := newImpersonationRouter(audit)
+
+	doImpersonationRequest(router, http.MethodGet, "/api/profile", "impersonation-token")
+	doImpersonationRequest(router, http.MethodGet, "/api/users", "impersonation-token")
+	// 普通令牌不产生代入审计
+	doImpersonationRequest(router, http.MethodGet, "/api/profile", "user-token")
+
+	if len(audit.entries) != 2 {
+		t.Fatalf("audit entries = %d, want 2", len(audit.entries))
+	}
+	for i, path := range []string{"/api/profile", "/api/users"} {
+		entry := audit.entries[i]
+		values, _ := entry.NewValues.(map[string]interface{})
+		if entry.AdminUserID != 1 || entry.ResourceID != "2" || entry.Path != path ||
+			values["impersonator_id"] != uint(1) || values["impersonated_user_id"] != uint(2) {
+			t.Errorf("entries[%d] = %+v, want admin 1 as user 2 on %s", i, entry, path)
+		}
+	}
+	if audit.entries[1].Status == audit.entries[0].Status {
+		t.Errorf("被拒绝的管理员请求应记录为失败")
+	}
+}
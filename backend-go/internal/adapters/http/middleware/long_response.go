@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LongResponse 清除当前连接的读写截止时间，用于导出、文件下载与实时推送等路由
+//
+// 服务器的 read_timeout/write_timeout 从连接建立时起算，耗时更长的响应会被截断，
+// 读超时还会取消请求上下文。需要限制单次写出的处理器（如实时推送）自行设置写截止时间。
+func LongResponse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		controller := http.NewResponseController(c.Writer)
+		_ = controller.SetReadDeadline(time.Time{})
+		_ = controller.SetWriteDeadline(time.Time{})
+		c.Next()
+	}
+}
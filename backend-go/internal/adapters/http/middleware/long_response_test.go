@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTimeoutServer(handlers ...gin.HandlerFunc) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/export", handlers...)
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.ReadTimeout = 50 * time.Millisecond
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	return server
+}
+
+func slowExport(c *gin.Context) {
+	select {
+	case <-time.After(150 * time.Millisecond):
+	case <-c.Request.Context().Done():
+		return
+	}
+	c.String(http.StatusOK, "done")
+}
+
+func TestLongResponse_OutlivesServerTimeouts(t *testing.T) {
+	server := newTimeoutServer(LongResponse(), slowExport)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/export")
+	if err != nil {
+		t.Fatalf("GET /export: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "done" {
+		t.Errorf("GET /export = %d %q, want 200 \"done\"", resp.StatusCode, body)
+	}
+}
+
+func TestLongResponse_WithoutMiddlewareIsCutOff(t *testing.T) {
+	server := newTimeoutServer(slowExport)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/export")
+	if err == nil {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr == nil && string(body) == "done" {
+			t.Errorf("未清除截止时间的慢响应应被服务器超时截断")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-go/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// NonceHeader 请求 nonce 请求头，格式为 <unix 秒>.<随机串>.<签名>
+const NonceHeader = "X-Request-Nonce"
+
+// NonceStore 记录已使用的 nonce
+type NonceStore interface {
+	// Lock 键不存在时写入并返回 true，已存在时返回 false
+	Lock(ctx context.Context, key string, expiration time.Duration) (bool, error)
+}
+
+// NonceConfig 请求防重放配置
+type NonceConfig struct {
+	Secret   string        // 签名密钥
+	MaxAge   time.Duration // nonce 有效期，同时也是已用 nonce 的保留时长
+	Required bool          // 为 false 时未携带 nonce 的请求直接放行
+	Store    NonceStore
+}
+
+// SignNonce 计算 nonce 签名，签名绑定请求方法与路径，防止 nonce 被挪用到其他接口
+func SignNonce(secret string, timestamp int64, random, method, path string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s.%s.%s", timestamp, random, strings.ToUpper(method), path)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewNonce 生成请求头使用的完整 nonce
+func NewNonce(secret string, timestamp time.Time, random, method, path string) string {
+	unix := timestamp.Unix()
+	return fmt.Sprintf("%d.%s.%s", unix, random, SignNonce(secret, unix, random, method, path))
+}
+
+// RequestNonce 校验写操作请求携带的一次性 nonce
+//
+// nonce 需签名有效且在有效期内，首次使用时记录到存储中，重复使用返回 409。
+// GET/HEAD/OPTIONS 请求不校验。
+func RequestNonce(config NonceConfig) gin.HandlerFunc {
+	if config.MaxAge <= 0 {
+		config.MaxAge = 5 * time.Minute
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader(NonceHeader)
+		if header == "" {
+			if config.Required {
+				abortWithAppError(c, response.NewBadRequestError("缺少请求 nonce", NonceHeader))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		random, err := verifyNonce(config, header, c.Request.Method, c.Request.URL.Path, time.Now())
+		if err != nil {
+			abortWithAppError(c, err)
+			return
+		}
+
+		fresh, storeErr := config.Store.Lock(c.Request.Context(), "nonce:"+random, config.MaxAge)
+		if storeErr != nil {
+			response.InternalError(c, "校验请求 nonce 失败")
+			c.Abort()
+			return
+		}
+		if !fresh {
+			abortWithAppError(c, response.NewConflictError("请求 nonce 已被使用", nil))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// verifyNonce 校验 nonce 格式、签名和有效期，返回随机串部分
+func verifyNonce(config NonceConfig, header, method, path string, now time.Time) (string, *response.AppError) {
+	parts := strings.Split(header, ".")
+	if len(parts) != 3 || parts[1] == "" {
+		return "", response.NewBadRequestError("无效的请求 nonce", nil)
+	}
+
+	timestamp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return "", response.NewBadRequestError("无效的请求 nonce", nil)
+	}
+
+	expected := SignNonce(config.Secret, timestamp, parts[1], method, path)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return "", response.NewBadRequestError("请求 nonce 签名无效", nil)
+	}
+
+	// 允许少量时钟偏差，但不接受超出有效期的未来时间
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > config.MaxAge || age < -config.MaxAge {
+		return "", response.NewBadRequestError("请求 nonce 已过期", nil)
+	}
+
+	return parts[1], nil
+}
+
+// abortWithAppError 返回业务错误并中止请求
+func abortWithAppError(c *gin.Context, appErr *response.AppError) {
+	response.Error(c, appErr.StatusCode, appErr.Message, appErr.Error())
+	c.Abort()
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testNonceSecret = "test-nonce-secret-with-at-least-32-chars"
+
+// memoryNonceStore 内存中的 nonce 记录
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (s *memoryNonceStore) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+func newNonceRouter(t *testing.T, required bool, calls *int) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestNonce(NonceConfig{
+		Secret:   testNonceSecret,
+		MaxAge:   time.Minute,
+		Required: required,
+		Store:    &memoryNonceStore{keys: map[string]bool{}},
+	}))
+	handler := func(c *gin.Context) {
+		*calls++
+		c.Status(http.StatusOK)
+	}
+	router.POST("/api/admin/users/:id/permissions", handler)
+	router.GET("/api/admin/users", handler)
+	return router
+}
+
+func doNonceRequest(router *gin.Engine, method, path, nonce string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	if nonce != "" {
+		req.Header.Set(NonceHeader, nonce)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestNonce_AcceptsFirstUseAndRejectsReplay(t *testing.T) {
+	var calls int
+	router := newNonceRouter(t, true, &calls)
+	path := "/api/admin/users/7/permissions"
+	nonce := NewNonce(testNonceSecret, time.Now(), "a1b2c3", http.MethodPost, path)
+
+	if w := doNonceRequest(router, http.MethodPost, path, nonce); w.Code != http.StatusOK {
+		t.Fatalf("首次请求应通过, code = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w := doNonceRequest(router, http.MethodPost, path, nonce)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "已被使用") {
+		t.Errorf("重放请求应返回 409, code = %d, body = %s", w.Code, w.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("重放请求不应进入处理器, calls = %d", calls)
+	}
+}
+
+func TestRequestNonce_RejectsExpiredAndInvalidNonce(t *testing.T) {
+	var calls int
+	router := newNonceRouter(t, true, &calls)
+	path := "/api/admin/users/7/permissions"
+
+	tests := []struct {
+		name  string
+		nonce string
+		want  string
+	}{
+		{name: "已过期", nonce: NewNonce(testNonceSecret, time.Now().Add(-2*time.Minute), "expired", http.MethodPost, path), want: "已过期"},
+		{name: "签名错误", nonce: NewNonce("another-secret-with-at-least-32-characters", time.Now(), "forged", http.MethodPost, path), want: "签名无效"},
+		{name: "签名路径不符", nonce: NewNonce(testNonceSecret, time.Now(), "moved", http.MethodPost, "/api/admin/users/8/permissions"), want: "签名无效"},
+		{name: "格式错误", nonce: "not-a-nonce", want: "无效"},
+		{name: "缺少 nonce", nonce: "", want: "缺少"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doNonceRequest(router, http.MethodPost, path, tt.nonce)
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("code = %d, body = %s, want 400 包含 %q", w.Code, w.Body.String(), tt.want)
+			}
+		})
+	}
+	if calls != 0 {
+		t.Errorf("无效 nonce 不应进入处理器, calls = %d", calls)
+	}
+}
+
+func TestRequestNonce_SkipsReadsAndOptionalMissingNonce(t *testing.T) {
+	var calls int
+	router := newNonceRouter(t, false, &calls)
+
+	if w := doNonceRequest(router, http.MethodGet, "/api/admin/users", ""); w.Code != http.StatusOK {
+		t.Errorf("读请求不校验 nonce, code = %d", w.Code)
+	}
+	if w := doNonceRequest(router, http.MethodPost, "/api/admin/users/7/permissions", ""); w.Code != http.StatusOK {
+		t.Errorf("未强制要求时缺少 nonce 应放行, code = %d", w.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/request"
+	"backend-go/pkg/response"
+)
+
+// paginationSizeParams 列表接口表示每页数量的查询参数
+var paginationSizeParams = []string{"page_size", "limit"}
+
+// PaginationConfig 列表接口每页数量上限配置
+type PaginationConfig struct {
+	MaxPageSize int  // page_size/limit 的上限，<=0 表示不限制
+	Reject      bool // 为 true 时超出上限返回 400，否则截断为上限
+}
+
+// PaginationLimit 统一限制 GET 请求的 page_size 与 limit 参数
+//
+// 截断通过改写查询串实现，需注册在读取查询参数的中间件之前。
+// 非数字或非正数的取值交给各处理器按原有规则处理。上限同时写入请求，
+// 供 request.ParseListQuery 使用相同的配置。
+func PaginationLimit(config PaginationConfig) gin.HandlerFunc {
+	maxSize := strconv.Itoa(config.MaxPageSize)
+	limit := request.PageSizeLimit{Max: config.MaxPageSize, Reject: config.Reject}
+
+	return func(c *gin.Context) {
+		request.SetPageSizeLimit(c, limit)
+		if config.MaxPageSize <= 0 || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		query := c.Request.URL.Query()
+		clamped := false
+		for _, param := range paginationSizeParams {
+			size, err := strconv.Atoi(query.Get(param))
+			if err != nil || size <= config.MaxPageSize {
+				continue
+			}
+			if config.Reject {
+				abortWithAppError(c, response.NewBadRequestError(
+					fmt.Sprintf("%s 不能超过 %d", param, config.MaxPageSize),
+					map[string]interface{}{"param": param, "max": config.MaxPageSize},
+				))
+				return
+			}
+			query.Set(param, maxSize)
+			clamped = true
+		}
+		if clamped {
+			c.Request.URL.RawQuery = query.Encode()
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newPaginationRouter 的 /list 接口以 "page_size,limit" 形式返回处理器读到的参数
+func newPaginationRouter(config PaginationConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(PaginationLimit(config))
+	list := func(c *gin.Context) {
+		var req struct {
+			Page     int `form:"page"`
+			PageSize int `form:"page_size"`
+		}
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, "%d,%s", req.PageSize, c.Query("limit"))
+	}
+	router.GET("/list", list)
+	router.POST("/list", list)
+	return router
+}
+
+func requestList(router *gin.Engine, method, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, "/list?"+query, nil))
+	return w
+}
+
+func TestPaginationLimit_ClampsOversizedRequests(t *testing.T) {
+	router := newPaginationRouter(PaginationConfig{MaxPageSize: 100})
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		want   string
+	}{
+		{"page_size 超出上限", http.MethodGet, "page=2&page_size=1000000", "100,"},
+		{"limit 超出上限", http.MethodGet, "limit=500", "0,100"},
+		{"范围内原样放行", http.MethodGet, "page_size=20&limit=100", "20,100"},
+		{"未携带参数", http.MethodGet, "", "0,"},
+		{"非法取值交给处理器", http.MethodGet, "limit=abc", "0,abc"},
+		{"非 GET 请求不处理", http.MethodPost, "limit=500", "0,500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := requestList(router, tt.method, tt.query)
+			if w.Code != http.StatusOK || w.Body.String() != tt.want {
+				t.Errorf("status = %d, body = %q, want 200 %q", w.Code, w.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginationLimit_RejectsOversizedRequests(t *testing.T) {
+	router := newPaginationRouter(PaginationConfig{MaxPageSize: 50, Reject: true})
+
+	if w := requestList(router, http.MethodGet, "page_size=51"); w.Code != http.StatusBadRequest {
+		t.Errorf("超出上限 status = %d, want 400", w.Code)
+	}
+	if w := requestList(router, http.MethodGet, "page_size=50"); w.Code != http.StatusOK || w.Body.String() != "50," {
+		t.Errorf("范围内 status = %d, body = %q, want 200 \"50,\"", w.Code, w.Body.String())
+	}
+}
+
+func TestPaginationLimit_DisabledWithoutMax(t *testing.T) {
+	router := newPaginationRouter(PaginationConfig{})
+
+	if w := requestList(router, http.MethodGet, "page_size=1000000"); w.Body.String() != "1000000," {
+		t.Errorf("body = %q, want 未限制", w.Body.String())
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/database"
+	requestid "backend-go/pkg/middleware/request_id"
+)
+
+// QueryCounterConfig 单请求语句数检查配置（开发环境排查 N+1 查询）
+type QueryCounterConfig struct {
+	Enabled   bool           // 是否启用，需同时在数据库连接上注册 database.RegisterQueryCounter
+	Threshold int            // 单个请求的语句数超过该值时输出告警
+	Logger    *logrus.Logger // 为空时使用全局日志
+}
+
+// QueryCounter 统计每个请求执行的数据库语句数，超过阈值时输出包含 SQL 的告警
+//
+// 只统计通过请求上下文（c.Request.Context()）执行的语句。
+func QueryCounter(config QueryCounterConfig) gin.HandlerFunc {
+	log := config.Logger
+	if log == nil {
+		log = logger.GetLogger()
+	}
+
+	return func(c *gin.Context) {
+		if !config.Enabled || config.Threshold <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, counter := database.WithQueryCounter(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if count := counter.Count(); count > config.Threshold {
+			log.WithFields(logrus.Fields{
+				"method":     c.Request.Method,
+				"route":      c.FullPath(),
+				"path":       c.Request.URL.Path,
+				"request_id": requestid.GetRequestID(c),
+				"queries":    count,
+				"threshold":  config.Threshold,
+				"statements": counter.Statements(),
+			}).Warn("Request issued too many database queries, possible N+1")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/pkg/database"
+)
+
+type queryCounterAuthor struct {
+	ID   uint
+	Name string
+}
+
+type queryCounterPost struct {
+	ID       uint
+	AuthorID uint
+	Title    string
+}
+
+// newQueryCounterRouter 的 /posts 按文章逐条查询作者（N+1），/posts/joined 一次查询完成
+func newQueryCounterRouter(t *testing.T, threshold int) (*gin.Engine, *test.Hook) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&queryCounterAuthor{}, &queryCounterPost{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		db.Create(&queryCounterAuthor{ID: uint(i), Name: "author"})
+		db.Create(&queryCounterPost{AuthorID: uint(i), Title: "post"})
+	}
+	if err := database.RegisterQueryCounter(db); err != nil {
+		t.Fatalf("RegisterQueryCounter() error = %v", err)
+	}
+
+	log, hook := test.NewNullLogger()
+	log.SetOutput(io.Discard)
+
+	router := gin.New()
+	router.Use(QueryCounter(QueryCounterConfig{Enabled: true, Threshold: threshold, Logger: log}))
+	router.GET("/posts", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var posts []queryCounterPost
+		db.WithContext(ctx).Find(&posts)
+		for _, post := range posts {
+			var author queryCounterAuthor
+			db.WithContext(ctx).First(&author, post.AuthorID)
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/posts/joined", func(c *gin.Context) {
+		var rows []struct {
+			Title string
+			Name  string
+		}
+		db.WithContext(c.Request.Context()).Table("query_counter_posts").
+			Select("query_counter_posts.title, query_counter_authors.name").
+			Joins("JOIN query_counter_authors ON query_counter_authors.id = query_counter_posts.author_id").
+			Scan(&rows)
+		c.Status(http.StatusOK)
+	})
+	return router, hook
+}
+
+func TestQueryCounter_WarnsOnNPlusOne(t *testing.T) {
+	router, hook := newQueryCounterRouter(t, 3)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.WarnLevel {
+		t.Fatalf("entries = %v, want 一条 warn 告警", hook.AllEntries())
+	}
+	if entry.Data["queries"] != 6 || entry.Data["route"] != "/posts" {
+		t.Errorf("告警字段 = %v, want queries=6 route=/posts", entry.Data)
+	}
+	statements, _ := entry.Data["statements"].([]string)
+	if len(statements) != 6 || !strings.Contains(statements[1], "query_counter_authors") {
+		t.Errorf("statements = %v, want 6 条且包含作者查询", statements)
+	}
+}
+
+func TestQueryCounter_SilentUnderThreshold(t *testing.T) {
+	router, hook := newQueryCounterRouter(t, 3)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/joined", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if entries := hook.AllEntries(); len(entries) != 0 {
+		t.Errorf("entries = %v, want 无告警", entries)
+	}
+}
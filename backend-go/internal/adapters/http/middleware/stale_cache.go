@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/cache"
+)
+
+// 降级缓存响应头
+const (
+	CacheStatusHeader = "X-Cache"
+	CacheStatusStale  = "stale"
+)
+
+// StaleCacheConfig 降级响应缓存配置
+type StaleCacheConfig struct {
+	Paths               []string      // 生效的路径前缀
+	MaxEntries          int           // 最大缓存条目数
+	MaxBytes            int64         // 最大内存占用
+	MaxAge              time.Duration // 超过该时长的响应不再用于降级
+	HealthCheckInterval time.Duration // Redis 健康状态刷新间隔
+	Healthy             func() bool   // Redis 健康检查
+}
+
+// staleResponse 最近一次成功的响应
+type staleResponse struct {
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+// StaleCache Redis 故障时的降级响应缓存
+//
+// 正常情况下记录热点 GET 接口最近一次成功的响应；Redis 不可用时直接返回
+// 内存中的响应并标记 X-Cache: stale，避免所有请求回源数据库。
+type StaleCache struct {
+	config StaleCacheConfig
+	store  *cache.LRUCache
+	now    func() time.Time
+
+	healthy   atomic.Bool
+	checking  atomic.Bool
+	checkedAt atomic.Int64
+}
+
+// NewStaleCache 创建降级响应缓存
+func NewStaleCache(config StaleCacheConfig) *StaleCache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 16 << 20
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = 10 * time.Minute
+	}
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = 5 * time.Second
+	}
+
+	s := &StaleCache{
+		config: config,
+		store:  cache.NewLRUCache(config.MaxEntries, config.MaxBytes),
+		now:    time.Now,
+	}
+	s.healthy.Store(true)
+	return s
+}
+
+// Middleware 降级缓存中间件
+func (s *StaleCache) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 仅缓存公开的 GET 请求，带身份的响应可能因用户而异
+		if c.Request.Method != http.MethodGet || c.GetHeader("Authorization") != "" || !s.matches(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		key := c.Request.URL.RequestURI()
+
+		if !s.isHealthy() {
+			if cached, ok := s.load(c.Request.Context(), key); ok {
+				age := int(s.now().Sub(cached.StoredAt).Seconds())
+				c.Header(CacheStatusHeader, CacheStatusStale)
+				c.Header("Age", strconv.Itoa(age))
+				c.Data(cached.Status, cached.ContentType, cached.Body)
+				c.Abort()
+				return
+			}
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() == http.StatusOK && writer.body.Len() > 0 {
+			s.save(c.Request.Context(), key, &staleResponse{
+				Status:      http.StatusOK,
+				ContentType: writer.Header().Get("Content-Type"),
+				Body:        writer.body.Bytes(),
+				StoredAt:    s.now(),
+			})
+		}
+	}
+}
+
+// Len 当前缓存的响应数量
+func (s *StaleCache) Len() int {
+	return s.store.Len()
+}
+
+// matches 判断路径是否启用降级缓存
+func (s *StaleCache) matches(path string) bool {
+	for _, prefix := range s.config.Paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHealthy 返回最近一次健康检查结果，过期时异步刷新，不阻塞请求
+func (s *StaleCache) isHealthy() bool {
+	if s.config.Healthy == nil {
+		return true
+	}
+
+	last := time.Unix(0, s.checkedAt.Load())
+	if s.now().Sub(last) >= s.config.HealthCheckInterval && s.checking.CompareAndSwap(false, true) {
+		go func() {
+			defer s.checking.Store(false)
+			s.refreshHealth()
+		}()
+	}
+	return s.healthy.Load()
+}
+
+// refreshHealth 执行一次健康检查
+func (s *StaleCache) refreshHealth() {
+	s.healthy.Store(s.config.Healthy())
+	s.checkedAt.Store(s.now().UnixNano())
+}
+
+func (s *StaleCache) load(ctx context.Context, key string) (*staleResponse, bool) {
+	data, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+
+	var cached staleResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+func (s *StaleCache) save(ctx context.Context, key string, resp *staleResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	s.store.Set(ctx, key, data, s.config.MaxAge)
+}
+
+// captureWriter 记录响应体的 ResponseWriter
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
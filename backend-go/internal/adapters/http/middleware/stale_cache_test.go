@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newStaleCacheRouter(t *testing.T) (*gin.Engine, *StaleCache, *atomic.Bool, *atomic.Int32) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var healthy atomic.Bool
+	healthy.Store(true)
+	var calls atomic.Int32
+
+	sc := NewStaleCache(StaleCacheConfig{
+		Paths:               []string{"/api/leaderboard"},
+		MaxEntries:          10,
+		HealthCheckInterval: time.Hour,
+		Healthy:             healthy.Load,
+	})
+	sc.refreshHealth()
+
+	router := gin.New()
+	router.Use(sc.Middleware())
+	router.GET("/api/leaderboard", func(c *gin.Context) {
+		n := calls.Add(1)
+		c.JSON(http.StatusOK, gin.H{"version": n})
+	})
+	return router, sc, &healthy, &calls
+}
+
+func doGet(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestStaleCache_ServesStaleDuringOutage(t *testing.T) {
+	router, sc, healthy, calls := newStaleCacheRouter(t)
+
+	// 正常请求回源并记录响应
+	w := doGet(router, "/api/leaderboard?limit=10")
+	if w.Code != http.StatusOK || w.Header().Get(CacheStatusHeader) != "" {
+		t.Fatalf("正常请求: code = %d, X-Cache = %q", w.Code, w.Header().Get(CacheStatusHeader))
+	}
+	if sc.Len() != 1 {
+		t.Fatalf("缓存条目数 = %d, want 1", sc.Len())
+	}
+
+	// 模拟 Redis 故障
+	healthy.Store(false)
+	sc.refreshHealth()
+
+	w = doGet(router, "/api/leaderboard?limit=10")
+	if got := w.Header().Get(CacheStatusHeader); got != CacheStatusStale {
+		t.Errorf("故障期间 X-Cache = %q, want %q", got, CacheStatusStale)
+	}
+	if w.Body.String() != `{"version":1}` {
+		t.Errorf("故障期间响应体 = %s, want 上次成功的响应", w.Body.String())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("故障期间不应回源, handler 调用次数 = %d", calls.Load())
+	}
+
+	// 没有缓存的请求仍然回源
+	w = doGet(router, "/api/leaderboard?limit=20")
+	if w.Header().Get(CacheStatusHeader) != "" || calls.Load() != 2 {
+		t.Errorf("未缓存请求应回源: X-Cache = %q, calls = %d", w.Header().Get(CacheStatusHeader), calls.Load())
+	}
+
+	// Redis 恢复后回源并刷新缓存
+	healthy.Store(true)
+	sc.refreshHealth()
+
+	w = doGet(router, "/api/leaderboard?limit=10")
+	if w.Header().Get(CacheStatusHeader) != "" || w.Body.String() != `{"version":3}` {
+		t.Errorf("恢复后应返回新数据: X-Cache = %q, body = %s", w.Header().Get(CacheStatusHeader), w.Body.String())
+	}
+
+	healthy.Store(false)
+	sc.refreshHealth()
+	if w = doGet(router, "/api/leaderboard?limit=10"); w.Body.String() != `{"version":3}` {
+		t.Errorf("再次故障时应返回刷新后的响应, body = %s", w.Body.String())
+	}
+}
+
+func TestStaleCache_BoundedAndScoped(t *testing.T) {
+	router, sc, _, _ := newStaleCacheRouter(t)
+	router.GET("/api/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	for i := 0; i < 50; i++ {
+		doGet(router, fmt.Sprintf("/api/leaderboard?page=%d", i))
+	}
+	if sc.Len() > 10 {
+		t.Errorf("缓存条目数 = %d, 超过上限 10", sc.Len())
+	}
+
+	before := sc.Len()
+	doGet(router, "/api/users")
+	if sc.Len() != before {
+		t.Errorf("未配置的路径不应被缓存")
+	}
+}
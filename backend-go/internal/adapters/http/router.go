@@ -6,16 +6,21 @@ import (
 	"backend-go/internal/adapters/http/handlers"
 	"backend-go/internal/adapters/http/middleware"
 	"backend-go/internal/adapters/http/routes"
+	"backend-go/internal/adapters/realtime"
+	"backend-go/internal/config"
 	"backend-go/internal/core/domain/leaderboard"
 	"backend-go/internal/core/domain/match"
 	"backend-go/internal/core/domain/prediction"
 	"backend-go/internal/core/domain/scoring"
 	"backend-go/internal/core/domain/user"
 	"backend-go/internal/core/ports"
+	coreServices "backend-go/internal/core/services"
 	"backend-go/internal/shared/logger"
+	pkgMiddleware "backend-go/pkg/middleware"
 	"backend-go/pkg/middleware/cors"
 	requestid "backend-go/pkg/middleware/request_id"
 	"backend-go/pkg/response"
+	"backend-go/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -26,11 +31,90 @@ import (
 // RouterConfig 路由配置
 type RouterConfig struct {
 	UserService        user.Service
+	AuthService        ports.AuthService
 	MatchService       match.Service
 	PredictionService  prediction.Service
 	LeaderboardService leaderboard.Service
 	ScoringService     scoring.Service
 	TeamService        ports.TeamService
+	RealtimeHub        *realtime.Hub
+
+	// Redis 故障时的降级响应缓存（可选）
+	StaleCache *middleware.StaleCache
+
+	// 写请求 Idempotency-Key 去重（可选）
+	Idempotency *middleware.Idempotency
+
+	// 接口延迟 SLO 追踪（可选）
+	SLOTracker *pkgMiddleware.SLOTracker
+
+	// 就绪探针状态（可选，用于维护模式开关）
+	ProbeState *pkgMiddleware.ProbeState
+
+	// 运行时生效配置（可选，供管理员排查配置来源）
+	AppConfig *config.Config
+
+	// 当前运行环境（GO_ENV），随版本信息返回
+	Environment string
+
+	// Redis 统计键过期时间审计（可选）
+	RedisTTLAuditor *coreServices.RedisTTLAuditor
+
+	// 后台任务心跳（可选，用于 worker 健康检查）
+	WorkerHeartbeat *coreServices.WorkerHeartbeat
+
+	// 每日预测统计汇总（可选，用于读取预计算的趋势数据）
+	StatsRollup *coreServices.StatsRollup
+
+	// 按用户灰度的功能开关（可选，超级管理员调整灰度配置）
+	FeatureFlags *coreServices.FeatureFlags
+
+	// 文件存储（可选，管理员签发下载地址；本地存储时另注册签名地址下载接口）
+	FileStorage storage.Storage
+
+	// 数据库迁移运行器（可选，超级管理员通过接口发起迁移）
+	MigrationRunner *coreServices.MigrationRunner
+
+	// 结束比赛并计分的工作流（可选）
+	MatchFinishService *coreServices.MatchFinishService
+
+	// 用户徽章（可选）
+	BadgeService *coreServices.BadgeService
+
+	// 管理员写操作防重放（可选，Store 为空时不启用）
+	RequestNonce middleware.NonceConfig
+
+	// 跨域配置（可选，为空时使用兼容的宽松策略）；CORSDisabled 为 true 时不注册跨域中间件
+	CORS         *cors.Config
+	CORSDisabled bool
+
+	// 请求体大小限制（上传接口除外）与上传文件大小上限
+	BodyLimit     middleware.BodyLimitConfig
+	UploadMaxSize int64
+
+	// 并发请求数限制（隔离舱）
+	ConcurrencyLimit middleware.ConcurrencyLimitConfig
+
+	// 单个请求的处理时限（0 表示不限制），实时推送长连接除外
+	RequestTimeout time.Duration
+
+	// 成功响应附带处理耗时与服务端时间（调试用）
+	ResponseMetadata bool
+
+	// 可信代理（IP 或 CIDR），为空时客户端 IP 取直连地址，忽略 X-Forwarded-For
+	TrustedProxies []string
+
+	// 响应数据字段命名策略（为空时保持结构体标签原样）
+	JSONNaming response.NamingStrategy
+
+	// 列表接口每页数量上限（MaxPageSize 为 0 时不限制）
+	Pagination middleware.PaginationConfig
+
+	// 单请求数据库语句数检查（开发环境排查 N+1 查询）
+	QueryCounter middleware.QueryCounterConfig
+
+	// 实时推送连接建立频率限制（可选，Store 为空时不启用）
+	StreamConnectionLimit middleware.ConnectionLimitConfig
 
 	// 管理员系统服务
 	AdminService       ports.AdminService
@@ -50,17 +134,72 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 	// 创建路由器
 	router := gin.New()
 
+	// 限流、登录锁定与审计日志依赖客户端 IP，只信任配置的代理转发的地址
+	if err := middleware.TrustProxies(router, config.TrustedProxies); err != nil {
+		logger.GetLogger().WithError(err).Error("Invalid trusted proxies, ignoring X-Forwarded-For")
+		_ = middleware.TrustProxies(router, nil)
+	}
+
 	// 添加全局中间件
 	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(pkgMiddleware.RecoveryMiddleware(logger.GetLogger()))
 	router.Use(requestid.RequestID())
-	router.Use(cors.CORS())
+	if config.ResponseMetadata {
+		router.Use(response.WithMetadata())
+	}
+	if config.JSONNaming != "" {
+		router.Use(response.WithNamingStrategy(config.JSONNaming))
+	}
+	switch {
+	case config.CORSDisabled:
+		// 关闭跨域时浏览器按同源策略处理，不注册中间件
+	case config.CORS != nil:
+		router.Use(cors.New(*config.CORS))
+	default:
+		router.Use(cors.CORS())
+	}
 	// 静态资源（头像等）
 	router.Static("/uploads", "./uploads")
 
+	// 并发请求数限制，在读取请求体等耗费资源的处理前拒绝过载请求；实时推送长连接不占用槽位
+	concurrencyLimit := config.ConcurrencyLimit
+	concurrencyLimit.SkipPrefixes = append(append([]string{}, concurrencyLimit.SkipPrefixes...), routes.StreamPathPrefixes...)
+	router.Use(middleware.ConcurrencyLimit(concurrencyLimit))
+
+	// 请求处理时限，数据库与 Redis 调用按剩余预算执行；排队时间不计入
+	router.Use(pkgMiddleware.TimeoutErrorHandler(config.RequestTimeout, routes.StreamPathPrefixes...))
+
+	// 请求体大小限制
+	bodyLimit := config.BodyLimit
+	bodyLimit.SkipPaths = append(append([]string{}, bodyLimit.SkipPaths...), routes.UploadPaths...)
+	router.Use(middleware.BodyLimit(bodyLimit))
+
+	// 列表接口每页数量上限
+	router.Use(middleware.PaginationLimit(config.Pagination))
+
 	// 添加限流中间件
 	router.Use(middleware.RateLimit(100, time.Minute)) // 每分钟100个请求
 
+	// 管理员代入期间的请求审计
+	if config.AdminAuditService != nil {
+		router.Use(middleware.ImpersonationAudit(config.AdminAuditService))
+	}
+
+	// 按用户灰度的功能开关，处理器通过 middleware.FeatureEnabled 判断
+	if config.FeatureFlags != nil {
+		router.Use(middleware.FeatureFlagContext(config.FeatureFlags))
+	}
+
+	// 接口延迟 SLO 追踪
+	if config.SLOTracker != nil {
+		router.Use(config.SLOTracker.Middleware())
+	}
+
+	// 单请求数据库语句数检查
+	if config.QueryCounter.Enabled {
+		router.Use(middleware.QueryCounter(config.QueryCounter))
+	}
+
 	// 健康检查端点
 	router.GET("/health", func(c *gin.Context) {
 		response.OK(c, "Service is healthy", gin.H{
@@ -70,12 +209,27 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 		})
 	})
 
+	// 版本信息端点，确认当前部署的构建
+	router.GET("/version", handlers.NewVersionHandler(config.Environment).GetVersion)
+
 	// API 主路由组（/api）
 	api := router.Group("/api")
+	if config.StaleCache != nil {
+		api.Use(config.StaleCache.Middleware())
+	}
+	if config.Idempotency != nil {
+		api.Use(config.Idempotency.Middleware())
+	}
+
+	// 后台任务健康检查
+	if config.WorkerHeartbeat != nil {
+		api.GET("/health/workers", handlers.NewWorkerHealthHandler(config.WorkerHeartbeat).GetWorkerHealth)
+	}
 
 	// 注册认证路由
-	authRoutes := routes.NewAuthRoutes(config.UserService)
+	authRoutes := routes.NewAuthRoutes(config.UserService, config.AuthService)
 	authRoutes.RegisterRoutes(api)
+	authRoutes.RegisterSessionRoutes(api)
 
 	// 兼容前端老路径（未带 /api 前缀的直接路由）
 	authNoPrefix := router.Group("/")
@@ -86,8 +240,11 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 	matchRoutes.RegisterRoutes(api)
 
 	// 上传路由
-	uploadRoutes := routes.NewUploadRoutes(authRoutes.GetAuthMiddleware(), "./uploads")
+	uploadRoutes := routes.NewUploadRoutes(authRoutes.GetAuthMiddleware(), "./uploads", config.UploadMaxSize)
 	uploadRoutes.RegisterRoutes(api)
+	if local, ok := config.FileStorage.(*storage.LocalStorage); ok {
+		api.GET("/files/*key", middleware.LongResponse(), handlers.NewFileHandler(local).DownloadFile)
+	}
 
 	// 注册战队路由
 	if config.TeamService != nil {
@@ -96,7 +253,11 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 	}
 
 	// 注册预测路由
-	predictionRoutes := routes.NewPredictionRoutes(config.PredictionService, authRoutes.GetAuthMiddleware())
+	var sportAccess handlers.SportAccessChecker
+	if config.AdminService != nil {
+		sportAccess = config.AdminService
+	}
+	predictionRoutes := routes.NewPredictionRoutes(config.PredictionService, config.MatchService, sportAccess, authRoutes.GetAuthMiddleware())
 	predictionRoutes.RegisterRoutes(api)
 
 	// 注册排行榜路由
@@ -107,6 +268,30 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 	)
 	routes.RegisterLeaderboardRoutes(api, leaderboardHandler, authRoutes.GetAuthMiddleware())
 
+	// 注册通知偏好路由
+	notificationHandler := handlers.NewNotificationHandler(config.UserService)
+	routes.RegisterNotificationRoutes(api, notificationHandler, authRoutes.GetAuthMiddleware())
+
+	// 注册用户徽章路由
+	if config.BadgeService != nil {
+		routes.RegisterBadgeRoutes(api, handlers.NewBadgeHandler(config.BadgeService))
+	}
+
+	// 注册服务间调用路由（API 密钥认证，结束比赛的审计记录调用方密钥）
+	if config.AuthService != nil {
+		var finishHandler *handlers.AdminMatchHandler
+		if config.MatchFinishService != nil {
+			finishHandler = handlers.NewAdminMatchHandler(config.MatchFinishService, config.AdminAuditService, logger.GetLogger())
+		}
+		routes.RegisterIntegrationRoutes(api, config.AuthService, config.MatchService, finishHandler)
+	}
+
+	// 注册实时推送路由（SSE）
+	if config.RealtimeHub != nil {
+		streamHandler := handlers.NewStreamHandler(config.RealtimeHub, config.LeaderboardService, logger.GetLogger())
+		routes.RegisterStreamRoutes(api, streamHandler, authRoutes.GetAuthMiddleware(), config.StreamConnectionLimit)
+	}
+
 	// 注册管理员路由（暂时禁用，因为服务未完全实现）
 	// if config.AdminService != nil && config.SportTypeService != nil {
 	// 	adminRoutes := routes.NewAdminRoutes(
@@ -137,6 +322,9 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 		adminAPI := router.Group("/api")
 		adminAPI.Use(authRoutes.GetAuthMiddleware().RequireAuth())
 		adminAPI.Use(authRoutes.GetAuthMiddleware().RequireAdmin())
+		if config.RequestNonce.Store != nil {
+			adminAPI.Use(middleware.RequestNonce(config.RequestNonce))
+		}
 
 		// 用户管理
 		userHandler := handlers.NewUserHandler(config.UserService, config.DB, logger.GetLogger())
@@ -171,8 +359,74 @@ func SetupRouter(config RouterConfig) *gin.Engine {
 		admin := adminAPI.Group("/admin")
 		{
 			admin.GET("/settings", systemSettingsHandler.GetSettings)
+			logLevelHandler := handlers.NewLogLevelHandler(logger.GetLogger())
+			admin.GET("/log-level", logLevelHandler.GetLogLevel)
+			admin.POST("/log-level", logLevelHandler.SetLogLevel)
+			if config.SLOTracker != nil {
+				admin.GET("/slo", handlers.NewSLOHandler(config.SLOTracker).GetSLO)
+			}
+			if config.Idempotency != nil {
+				admin.GET("/idempotency/stats", handlers.NewIdempotencyHandler(config.Idempotency).GetStats)
+			}
+			if config.RealtimeHub != nil {
+				connectionHandler := handlers.NewConnectionHandler(config.RealtimeHub, logger.GetLogger())
+				admin.GET("/ws/connections", connectionHandler.ListConnections)
+				admin.POST("/ws/connections/:id/terminate", connectionHandler.TerminateConnection)
+			}
+			admin.GET("/metrics/snapshot", handlers.NewMetricsSnapshotHandler(nil).GetMetricsSnapshot)
+			if config.FileStorage != nil {
+				admin.GET("/files/*key", handlers.NewFileURLHandler(config.FileStorage).GetDownloadURL)
+			}
+			if config.AppConfig != nil {
+				admin.GET("/config", handlers.NewConfigHandler(config.AppConfig).GetEffectiveConfig)
+			}
+			var maintenanceHandler *handlers.MaintenanceHandler
+			if config.ProbeState != nil {
+				maintenanceHandler = handlers.NewMaintenanceHandler(config.ProbeState, logger.GetLogger())
+				admin.GET("/maintenance", maintenanceHandler.GetMaintenance)
+			}
+			var redisTTLHandler *handlers.RedisTTLHandler
+			if config.RedisTTLAuditor != nil {
+				redisTTLHandler = handlers.NewRedisTTLHandler(config.RedisTTLAuditor)
+				admin.GET("/redis/ttl-audit", redisTTLHandler.AuditTTL)
+			}
+			if config.StatsRollup != nil {
+				predictionStatsHandler := handlers.NewPredictionStatsHandler(config.StatsRollup)
+				admin.GET("/stats/predictions/series", predictionStatsHandler.GetPredictionSeries)
+				admin.GET("/stats/predictions/monthly", predictionStatsHandler.GetMonthlyPredictions)
+			}
+			if config.MatchFinishService != nil {
+				adminMatchHandler := handlers.NewAdminMatchHandler(config.MatchFinishService, config.AdminAuditService, logger.GetLogger())
+				admin.POST("/matches/:id/finish", adminMatchHandler.FinishMatch)
+			}
+			var featureFlagHandler *handlers.FeatureFlagHandler
+			if config.FeatureFlags != nil {
+				featureFlagHandler = handlers.NewFeatureFlagHandler(config.FeatureFlags, logger.GetLogger())
+				admin.GET("/feature-flags", featureFlagHandler.ListFeatureFlags)
+			}
 			admin.Use(authRoutes.GetAuthMiddleware().RequireSuperAdmin())
 			admin.POST("/settings", systemSettingsHandler.UpdateSettings)
+			if maintenanceHandler != nil {
+				admin.POST("/maintenance", maintenanceHandler.SetMaintenance)
+			}
+			if redisTTLHandler != nil {
+				admin.POST("/redis/ttl-audit/repair", redisTTLHandler.RepairTTL)
+			}
+			if featureFlagHandler != nil {
+				admin.PUT("/feature-flags/:flag", featureFlagHandler.SetFeatureFlag)
+				admin.DELETE("/feature-flags/:flag", featureFlagHandler.DeleteFeatureFlag)
+			}
+			if config.AuthService != nil {
+				apiKeyHandler := handlers.NewAPIKeyHandler(config.AuthService)
+				admin.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+				admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+				admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+			}
+			if config.MigrationRunner != nil {
+				migrationHandler := handlers.NewMigrationHandler(config.MigrationRunner, config.AdminAuditService, logger.GetLogger())
+				admin.POST("/migrations/run", migrationHandler.RunMigrations)
+				admin.GET("/migrations/status", migrationHandler.GetMigrationStatus)
+			}
 		}
 	}
 
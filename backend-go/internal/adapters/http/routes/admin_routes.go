@@ -61,6 +61,21 @@ func (r *AdminRoutes) RegisterRoutes(router *gin.Engine, authMiddleware *middlew
 
 	// 审计日志路由（暂时不使用额外的权限检查）
 	r.registerAuditRoutesSimple(adminGroup)
+
+	// 保存的查询视图路由
+	r.registerViewRoutes(adminGroup)
+}
+
+// registerViewRoutes 注册查询视图路由，视图按管理员隔离，无需额外权限
+func (r *AdminRoutes) registerViewRoutes(group *gin.RouterGroup) {
+	adminHandler := adminhandlers.NewAdminHandler(r.adminService, r.adminAuditService, r.logger)
+
+	views := group.Group("/views")
+	{
+		views.GET("", adminHandler.ListViews)
+		views.POST("", adminHandler.SaveView)
+		views.DELETE("/:id", adminHandler.DeleteView)
+	}
 }
 
 // registerSportTypeRoutes 注册运动类型管理路由
@@ -109,6 +124,7 @@ func (r *AdminRoutes) registerScoringRuleRoutes(group *gin.RouterGroup) {
 
 		// 积分计算
 		scoringRules.POST("/preview", scoringRuleHandler.PreviewScore)
+		scoringRules.GET("/:id/simulate", scoringRuleHandler.SimulateRule)
 	}
 }
 
@@ -134,6 +150,9 @@ func (r *AdminRoutes) registerAdminManagementRoutes(group *gin.RouterGroup) {
 		admins.DELETE("/:id",
 			r.permissionMiddleware.RequirePermission(admin.PermissionAdminManage),
 			adminHandler.DeleteAdmin)
+		admins.POST("/cleanup-orphans",
+			r.permissionMiddleware.RequirePermission(admin.PermissionAdminManage),
+			adminHandler.CleanupOrphanedAdmins)
 
 		// 权限管理
 		admins.POST("/:id/permissions",
@@ -147,6 +166,9 @@ func (r *AdminRoutes) registerAdminManagementRoutes(group *gin.RouterGroup) {
 			adminHandler.GetUserPermissions)
 
 		// 运动类型访问权限管理
+		admins.POST("/sport-access/bulk",
+			r.permissionMiddleware.RequirePermission(admin.PermissionAdminManage),
+			adminHandler.GrantSportAccessBulk)
 		admins.POST("/:id/sport-access",
 			r.permissionMiddleware.RequirePermission(admin.PermissionAdminManage),
 			adminHandler.GrantSportAccess)
@@ -171,6 +193,7 @@ func (r *AdminRoutes) registerAdminManagementRoutesSimple(group *gin.RouterGroup
 		admins.GET("/:id", adminHandler.GetAdmin)
 		admins.PUT("/:id", adminHandler.UpdateAdmin)
 		admins.DELETE("/:id", adminHandler.DeleteAdmin)
+		admins.POST("/cleanup-orphans", adminHandler.CleanupOrphanedAdmins)
 
 		// 权限管理
 		admins.POST("/:id/permissions", adminHandler.GrantPermissions)
@@ -178,6 +201,7 @@ func (r *AdminRoutes) registerAdminManagementRoutesSimple(group *gin.RouterGroup
 		admins.GET("/:id/permissions", adminHandler.GetUserPermissions)
 
 		// 运动类型访问权限管理
+		admins.POST("/sport-access/bulk", adminHandler.GrantSportAccessBulk)
 		admins.POST("/:id/sport-access", adminHandler.GrantSportAccess)
 		admins.DELETE("/:id/sport-access", adminHandler.RevokeSportAccess)
 	}
@@ -4,6 +4,7 @@ import (
 	"backend-go/internal/adapters/http/handlers"
 	"backend-go/internal/adapters/http/middleware"
 	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,9 +15,9 @@ type AuthRoutes struct {
 }
 
 // NewAuthRoutes 创建认证路由
-func NewAuthRoutes(userService user.Service) *AuthRoutes {
+func NewAuthRoutes(userService user.Service, authService ports.AuthService) *AuthRoutes {
 	return &AuthRoutes{
-		authHandler:    handlers.NewAuthHandler(userService),
+		authHandler:    handlers.NewAuthHandler(userService, authService),
 		authMiddleware: middleware.NewAuthMiddleware(userService),
 	}
 }
@@ -30,6 +31,8 @@ func (r *AuthRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 		auth.POST("/register", r.authHandler.Register)
 		auth.POST("/login", r.authHandler.Login)
 		auth.POST("/refresh", r.authHandler.RefreshToken)
+		auth.POST("/password/forgot", r.authHandler.RequestPasswordReset)
+		auth.POST("/password/reset", r.authHandler.ResetPassword)
 
 		// 需要认证的路由
 		authenticated := auth.Group("")
@@ -39,10 +42,22 @@ func (r *AuthRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 			authenticated.PATCH("/profile", r.authHandler.UpdateProfile)
 			authenticated.POST("/change-password", r.authHandler.ChangePassword)
 			authenticated.POST("/logout", r.authHandler.Logout)
+			// 超级管理员代入用户，超级管理员校验在服务层基于管理员等级进行
+			authenticated.POST("/impersonate", r.authMiddleware.RequireAdmin(), r.authHandler.Impersonate)
 		}
 	}
 }
 
+// RegisterSessionRoutes 注册当前用户登录会话路由
+func (r *AuthRoutes) RegisterSessionRoutes(rg *gin.RouterGroup) {
+	me := rg.Group("/users/me")
+	me.Use(r.authMiddleware.RequireAuth())
+	{
+		me.GET("/sessions", r.authHandler.ListSessions)          // 登录会话列表
+		me.DELETE("/sessions/:jti", r.authHandler.RevokeSession) // 吊销登录会话
+	}
+}
+
 // GetAuthMiddleware 获取认证中间件（供其他路由使用）
 func (r *AuthRoutes) GetAuthMiddleware() *middleware.AuthMiddleware {
 	return r.authMiddleware
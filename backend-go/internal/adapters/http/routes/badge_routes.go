@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/handlers"
+)
+
+// RegisterBadgeRoutes 注册用户徽章路由
+func RegisterBadgeRoutes(r *gin.RouterGroup, handler *handlers.BadgeHandler) {
+	r.GET("/users/:id/badges", handler.GetUserBadges) // 获取用户徽章（公开）
+}
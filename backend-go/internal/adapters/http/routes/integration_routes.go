@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"backend-go/internal/adapters/http/handlers"
+	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/user"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterIntegrationRoutes 注册服务间调用路由，通过 X-API-Key 认证并按权限范围授权
+//
+// finishHandler 为空时不注册推送比赛结果的接口。
+func RegisterIntegrationRoutes(rg *gin.RouterGroup, keys middleware.APIKeyValidator, matchService match.Service, finishHandler *handlers.AdminMatchHandler) {
+	integrations := rg.Group("/integrations")
+	matchHandler := handlers.NewMatchHandler(matchService)
+
+	integrations.GET("/matches/:id", middleware.RequireAPIKey(keys, user.APIScopeMatchesRead), matchHandler.GetMatch) // 获取比赛详情
+	if finishHandler != nil {
+		integrations.POST("/matches/:id/finish", middleware.RequireAPIKey(keys, user.APIScopeMatchesWrite), finishHandler.FinishMatch) // 推送比赛结果并计分
+	}
+}
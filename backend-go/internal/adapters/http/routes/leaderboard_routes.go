@@ -14,6 +14,7 @@ func RegisterLeaderboardRoutes(r *gin.RouterGroup, handler *handlers.Leaderboard
 		// 公开路由
 		leaderboard.GET("", handler.GetLeaderboard)                                 // 获取排行榜
 		leaderboard.GET("/stats", handler.GetLeaderboardStats)                      // 获取排行榜统计
+		leaderboard.GET("/accuracy", handler.GetAccuracyLeaderboard)                // 获取准确率排行榜
 		leaderboard.GET("/users/:user_id/rank", handler.GetUserRank)                // 获取用户排名
 		leaderboard.GET("/ranks/:rank/around", handler.GetUsersAroundRank)          // 获取排名周围的用户
 		leaderboard.GET("/users/:user_id/points-history", handler.GetPointsHistory) // 获取用户积分历史
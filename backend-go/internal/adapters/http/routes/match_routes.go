@@ -27,10 +27,12 @@ func (r *MatchRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 
 	// 公开路由 - 不需要认证
 	matches.GET("", r.matchHandler.ListMatches)                 // 获取比赛列表
+	matches.GET("/search", r.matchHandler.SearchMatches)        // 搜索比赛
 	matches.GET("/:id", r.matchHandler.GetMatch)                // 获取比赛详情
 	matches.GET("/upcoming", r.matchHandler.GetUpcomingMatches) // 获取即将开始的比赛
 	matches.GET("/live", r.matchHandler.GetLiveMatches)         // 获取正在进行的比赛
 	matches.GET("/finished", r.matchHandler.GetFinishedMatches) // 获取已结束的比赛
+	matches.GET("/trending", r.matchHandler.GetTrendingMatches) // 获取热门比赛
 
 	// 写操作仅管理员
 	adminOnly := matches.Group("")
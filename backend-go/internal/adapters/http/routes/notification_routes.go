@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/handlers"
+	"backend-go/internal/adapters/http/middleware"
+)
+
+// RegisterNotificationRoutes 注册当前用户通知偏好路由
+func RegisterNotificationRoutes(r *gin.RouterGroup, handler *handlers.NotificationHandler, authMiddleware *middleware.AuthMiddleware) {
+	me := r.Group("/users/me")
+	me.Use(authMiddleware.RequireAuth())
+	{
+		me.GET("/notifications", handler.GetPreferences)    // 获取通知偏好
+		me.PUT("/notifications", handler.UpdatePreferences) // 更新通知偏好
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"backend-go/internal/adapters/http/handlers"
 	"backend-go/internal/adapters/http/middleware"
+	"backend-go/internal/core/domain/match"
 	"backend-go/internal/core/domain/prediction"
 	"github.com/gin-gonic/gin"
 )
@@ -10,13 +11,15 @@ import (
 // PredictionRoutes 预测路由
 type PredictionRoutes struct {
 	predictionHandler *handlers.PredictionHandler
+	exportHandler     *handlers.PredictionExportHandler
 	authMiddleware    *middleware.AuthMiddleware
 }
 
-// NewPredictionRoutes 创建预测路由
-func NewPredictionRoutes(predictionService prediction.Service, authMiddleware *middleware.AuthMiddleware) *PredictionRoutes {
+// NewPredictionRoutes 创建预测路由，sportAccess 用于导出时校验运动类型范围的管理员权限
+func NewPredictionRoutes(predictionService prediction.Service, matchService match.Service, sportAccess handlers.SportAccessChecker, authMiddleware *middleware.AuthMiddleware) *PredictionRoutes {
 	return &PredictionRoutes{
 		predictionHandler: handlers.NewPredictionHandler(predictionService),
+		exportHandler:     handlers.NewPredictionExportHandler(predictionService, matchService, sportAccess),
 		authMiddleware:    authMiddleware,
 	}
 }
@@ -32,6 +35,16 @@ func (r *PredictionRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 		predictions.GET("/featured", r.predictionHandler.GetFeaturedPredictions) // 获取精选预测
 	}
 
+	// 比赛预测共识
+	rg.GET("/matches/:id/consensus", r.predictionHandler.GetMatchConsensus)
+
+	// 比赛预测导出（管理员，按运动类型范围授权），导出耗时可能超过服务器读写超时
+	rg.GET("/matches/:id/predictions/export",
+		middleware.LongResponse(),
+		r.authMiddleware.RequireAuth(),
+		r.authMiddleware.RequireAdmin(),
+		r.exportHandler.ExportMatchPredictions)
+
 	// 需要认证的路由
 	authenticated := predictions.Group("")
 	authenticated.Use(r.authMiddleware.RequireAuth())
@@ -46,5 +59,6 @@ func (r *PredictionRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 		// 投票功能
 		authenticated.POST("/:id/vote", r.predictionHandler.VotePrediction)     // 投票支持预测
 		authenticated.DELETE("/:id/vote", r.predictionHandler.UnvotePrediction) // 取消投票
+		authenticated.POST("/votes", r.predictionHandler.CastVotes)             // 批量投票
 	}
 }
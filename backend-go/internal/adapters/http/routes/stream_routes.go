@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"backend-go/internal/adapters/http/handlers"
+	"backend-go/internal/adapters/http/middleware"
+)
+
+// StreamPathPrefixes 实时推送长连接路径前缀，不受请求处理时限约束
+var StreamPathPrefixes = []string{"/api/stream/"}
+
+// RegisterStreamRoutes 注册实时推送路由，建立连接前先按 IP 检查连接频率
+func RegisterStreamRoutes(r *gin.RouterGroup, handler *handlers.StreamHandler, authMiddleware *middleware.AuthMiddleware, connectionLimit middleware.ConnectionLimitConfig) {
+	stream := r.Group("/stream")
+	stream.Use(middleware.LongResponse(), middleware.ConnectionRateLimit(connectionLimit), authMiddleware.RequireStreamAuth())
+	{
+		stream.GET("/leaderboard/:tournament", handler.StreamLeaderboard) // 订阅排行榜变更
+		stream.GET("/notifications", handler.StreamNotifications)         // 订阅个人通知
+	}
+}
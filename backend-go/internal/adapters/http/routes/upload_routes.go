@@ -15,14 +15,17 @@ type UploadRoutes struct {
 	authMiddleware *middleware.AuthMiddleware
 }
 
-// NewUploadRoutes 创建上传路由
-func NewUploadRoutes(auth *middleware.AuthMiddleware, baseDir string) *UploadRoutes {
+// UploadPaths 文件上传接口，文件大小由上传处理器检查，不受请求体大小限制
+var UploadPaths = []string{"/api/uploads/avatar"}
+
+// NewUploadRoutes 创建上传路由，maxSize 为单个文件大小上限
+func NewUploadRoutes(auth *middleware.AuthMiddleware, baseDir string, maxSize int64) *UploadRoutes {
 	// 确保目录存在
 	avatarDir := filepath.Join(baseDir, "avatars")
 	_ = os.MkdirAll(avatarDir, os.ModePerm)
 
 	return &UploadRoutes{
-		handler:        handlers.NewUploadHandler(baseDir),
+		handler:        handlers.NewUploadHandler(baseDir, maxSize),
 		authMiddleware: auth,
 	}
 }
@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/database"
+)
+
+// APIKeyRepository MySQL API 密钥仓储实现
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository 创建 API 密钥仓储
+func NewAPIKeyRepository(db *gorm.DB) user.APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create 保存新密钥
+func (r *APIKeyRepository) Create(ctx context.Context, key *user.APIKey) error {
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		if appErr := database.ClassifyError(err); appErr != nil {
+			return appErr
+		}
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetByHash 根据密钥哈希获取密钥
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*user.APIKey, error) {
+	var key user.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return &key, nil
+}
+
+// List 获取全部密钥，按创建时间倒序
+func (r *APIKeyRepository) List(ctx context.Context) ([]*user.APIKey, error) {
+	keys := []*user.APIKey{}
+	if err := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke 吊销密钥
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uint, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&user.APIKey{}).
+		Where("id = ? AND is_active = ?", id, true).
+		Updates(map[string]interface{}{"is_active": false, "revoked_at": revokedAt})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke api key %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed 更新最近使用时间，不修改 updated_at
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&user.APIKey{}).Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error; err != nil {
+		return fmt.Errorf("failed to touch api key %d: %w", id, err)
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/leaderboard"
 	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/types"
 	"gorm.io/gorm"
 )
 
@@ -122,7 +124,7 @@ func (r *LeaderboardRepository) GetLeaderboardStats(ctx context.Context, tournam
 	return &leaderboard.LeaderboardStats{
 		TotalUsers:   int(stats.TotalUsers),
 		TopScore:     stats.TopScore,
-		AverageScore: stats.AverageScore,
+		AverageScore: types.NewScore(stats.AverageScore),
 		LastUpdated:  time.Now(),
 		Tournament:   tournament,
 	}, nil
@@ -177,3 +179,67 @@ func (r *LeaderboardRepository) GetUsersAroundRank(ctx context.Context, tourname
 
 	return entries, nil
 }
+
+// GetAccuracyLeaderboard 按用户分组统计已结束比赛的预测准确率
+//
+// 准确率相同时预测数多者靠前，仍相同时按用户ID升序，保证排名稳定。
+func (r *LeaderboardRepository) GetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int, limit int) ([]leaderboard.AccuracyEntry, error) {
+	if minPredictions <= 0 {
+		minPredictions = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var rows []struct {
+		UserID   uint
+		Username string
+		Nickname string
+		Avatar   string
+		Total    int
+		Correct  int
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("predictions AS p").
+		Select("p.userId AS user_id, u.username, u.nickname, u.avatar, "+
+			"COUNT(*) AS total, SUM(CASE WHEN p.isCorrect THEN 1 ELSE 0 END) AS correct").
+		Joins("JOIN users AS u ON u.id = p.userId").
+		Joins("JOIN matches AS m ON m.id = p.matchId").
+		Where("m.status = ?", domain.MatchStatusFinished)
+
+	// 全局排行榜统计所有赛事
+	if tournament != string(leaderboard.TournamentGlobal) {
+		query = query.Where("m.tournament = ?", tournament)
+	}
+
+	err := query.
+		Group("p.userId, u.username, u.nickname, u.avatar").
+		Having("COUNT(*) >= ?", minPredictions).
+		Order("SUM(CASE WHEN p.isCorrect THEN 1 ELSE 0 END) * 1.0 / COUNT(*) DESC, COUNT(*) DESC, p.userId ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("获取准确率排行榜失败: %w", err)
+	}
+
+	entries := make([]leaderboard.AccuracyEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = leaderboard.AccuracyEntry{
+			UserID:     row.UserID,
+			Username:   row.Username,
+			Nickname:   row.Nickname,
+			Avatar:     row.Avatar,
+			Total:      row.Total,
+			Correct:    row.Correct,
+			Accuracy:   types.NewScore(float64(row.Correct) * 100 / float64(row.Total)),
+			Rank:       i + 1,
+			Tournament: tournament,
+		}
+	}
+
+	return entries, nil
+}
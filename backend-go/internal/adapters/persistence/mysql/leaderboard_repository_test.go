@@ -0,0 +1,144 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/leaderboard"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+// newLeaderboardTestDB 创建内存数据库并建表
+func newLeaderboardTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&user.User{}, &domain.Match{}, &prediction.Prediction{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	return db
+}
+
+// seedAccuracy 为用户在指定比赛上写入 correct 条正确、total-correct 条错误的预测
+func seedAccuracy(t *testing.T, db *gorm.DB, userID uint, matches []domain.Match, total, correct int) {
+	t.Helper()
+	for i := 0; i < total; i++ {
+		p := &prediction.Prediction{
+			UserID:          userID,
+			MatchID:         matches[i].ID,
+			PredictedWinner: "A",
+			IsCorrect:       i < correct,
+		}
+		if err := db.Create(p).Error; err != nil {
+			t.Fatalf("创建预测失败: %v", err)
+		}
+	}
+}
+
+// createMatches 创建 n 场指定赛事与状态的比赛
+func createMatches(t *testing.T, db *gorm.DB, n int, tournament domain.Tournament, status domain.MatchStatus) []domain.Match {
+	t.Helper()
+	matches := make([]domain.Match, n)
+	for i := range matches {
+		matches[i] = domain.Match{
+			TeamA:      fmt.Sprintf("A%d", i),
+			TeamB:      fmt.Sprintf("B%d", i),
+			Tournament: tournament,
+			Status:     status,
+			StartTime:  time.Now().Add(-time.Hour),
+		}
+	}
+	if err := db.Create(&matches).Error; err != nil {
+		t.Fatalf("创建比赛失败: %v", err)
+	}
+	return matches
+}
+
+func TestLeaderboardRepository_GetAccuracyLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	db := newLeaderboardTestDB(t)
+	repo := NewLeaderboardRepository(db)
+
+	users := []*user.User{
+		{Username: "alice", Email: "alice@example.com", Password: "x", Points: 500},
+		{Username: "bob", Email: "bob@example.com", Password: "x"},
+		{Username: "carol", Email: "carol@example.com", Password: "x"},
+		{Username: "dave", Email: "dave@example.com", Password: "x"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	alice, bob, carol, dave := users[0].ID, users[1].ID, users[2].ID, users[3].ID
+
+	spring := createMatches(t, db, 10, domain.TournamentSpring, domain.MatchStatusFinished)
+	summer := createMatches(t, db, 4, domain.TournamentSummer, domain.MatchStatusFinished)
+	upcoming := createMatches(t, db, 3, domain.TournamentSpring, domain.MatchStatusUpcoming)
+
+	seedAccuracy(t, db, alice, spring, 5, 4)  // 80%，5 条
+	seedAccuracy(t, db, bob, spring, 2, 2)    // 100%，但预测数不足
+	seedAccuracy(t, db, carol, spring, 10, 8) // 80%，10 条，同准确率下排在 alice 之前
+	seedAccuracy(t, db, dave, spring, 6, 3)   // 50%
+	seedAccuracy(t, db, dave, summer, 4, 4)   // 仅计入 SUMMER 与 GLOBAL
+	seedAccuracy(t, db, bob, upcoming, 3, 3)  // 未结束的比赛不计入
+
+	entries, err := repo.GetAccuracyLeaderboard(ctx, string(leaderboard.TournamentSpring), 3, 10)
+	if err != nil {
+		t.Fatalf("GetAccuracyLeaderboard() error = %v", err)
+	}
+
+	want := []struct {
+		userID         uint
+		total, correct int
+		accuracy       float64
+	}{
+		{carol, 10, 8, 80},
+		{alice, 5, 4, 80},
+		{dave, 6, 3, 50},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %d entries", entries, len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.UserID != w.userID || e.Total != w.total || e.Correct != w.correct || e.Accuracy.Float64() != w.accuracy || e.Rank != i+1 {
+			t.Errorf("entries[%d] = %+v, want user %d %d/%d %.0f%% rank %d", i, e, w.userID, w.correct, w.total, w.accuracy, i+1)
+		}
+	}
+	if entries[0].Username != "carol" || entries[0].Tournament != string(leaderboard.TournamentSpring) {
+		t.Errorf("entries[0] = %+v, want carol in SPRING", entries[0])
+	}
+
+	// 全局排行榜合并所有赛事：dave 7/10 = 70%
+	global, err := repo.GetAccuracyLeaderboard(ctx, string(leaderboard.TournamentGlobal), 3, 10)
+	if err != nil {
+		t.Fatalf("GetAccuracyLeaderboard(GLOBAL) error = %v", err)
+	}
+	if len(global) != 3 || global[2].UserID != dave || global[2].Total != 10 || global[2].Accuracy.Float64() != 70 {
+		t.Errorf("global = %+v, want dave third with 7/10", global)
+	}
+
+	// 提高门槛后只剩预测数足够的用户
+	strict, err := repo.GetAccuracyLeaderboard(ctx, string(leaderboard.TournamentSpring), 6, 10)
+	if err != nil {
+		t.Fatalf("GetAccuracyLeaderboard(min=6) error = %v", err)
+	}
+	if len(strict) != 2 || strict[0].UserID != carol || strict[1].UserID != dave {
+		t.Errorf("strict = %+v, want carol then dave", strict)
+	}
+}
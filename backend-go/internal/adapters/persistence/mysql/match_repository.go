@@ -2,9 +2,11 @@ package mysql
 
 import (
 	"context"
+	"strings"
 
 	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/match"
+	"backend-go/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -22,7 +24,13 @@ func NewMatchRepository(db *gorm.DB) match.Repository {
 
 // Create 创建比赛
 func (r *MatchRepository) Create(ctx context.Context, m *match.Match) error {
-	return r.db.WithContext(ctx).Create(m).Error
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		if appErr := database.ClassifyError(err); appErr != nil {
+			return appErr
+		}
+		return err
+	}
+	return nil
 }
 
 // GetByID 根据 ID 获取比赛
@@ -78,6 +86,53 @@ func (r *MatchRepository) List(ctx context.Context, filter match.ListFilter) ([]
 	return matches, err
 }
 
+// Search 搜索比赛，按 (start_time, id) 键集分页
+func (r *MatchRepository) Search(ctx context.Context, filter match.MatchSearchFilter) ([]match.Match, error) {
+	var matches []match.Match
+
+	query := r.db.WithContext(ctx)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Tournament != "" {
+		query = query.Where("tournament = ?", filter.Tournament)
+	}
+	if filter.SportTypeID != nil {
+		query = query.Where("sport_type_id = ?", *filter.SportTypeID)
+	}
+	if filter.StartFrom != nil {
+		query = query.Where("start_time >= ?", *filter.StartFrom)
+	}
+	if filter.StartTo != nil {
+		query = query.Where("start_time <= ?", *filter.StartTo)
+	}
+
+	// 标题为计算字段（"A vs B"），按两个选项列匹配
+	if optionA, optionB, ok := filter.TitleOptions(); ok {
+		query = query.Where("team_a LIKE ? ESCAPE '!' AND team_b LIKE ? ESCAPE '!'", database.ContainsPattern(optionA), database.ContainsPattern(optionB))
+	} else if term := strings.TrimSpace(filter.Query); term != "" {
+		pattern := database.ContainsPattern(term)
+		query = query.Where("(team_a LIKE ? ESCAPE '!' OR team_b LIKE ? ESCAPE '!')", pattern, pattern)
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := match.DecodeSearchCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(start_time > ? OR (start_time = ? AND id > ?))", cursor.StartTime, cursor.StartTime, cursor.ID)
+	}
+
+	query = query.Order("start_time ASC").Order("id ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	err := query.Find(&matches).Error
+	return matches, err
+}
+
 // Update 更新比赛信息
 func (r *MatchRepository) Update(ctx context.Context, m *match.Match) error {
 	return r.db.WithContext(ctx).Save(m).Error
@@ -175,6 +230,51 @@ func (r *MatchRepository) GetFinishedMatches(ctx context.Context) ([]match.Match
 	return matches, err
 }
 
+// GetActiveWithPredictionCounts 获取即将开始与进行中的比赛及各自的预测数
+func (r *MatchRepository) GetActiveWithPredictionCounts(ctx context.Context, tournament string) ([]match.MatchPredictionCount, error) {
+	var matches []match.Match
+
+	query := r.db.WithContext(ctx).
+		Where("status IN ?", []match.MatchStatus{match.MatchStatusUpcoming, match.MatchStatusLive})
+	if tournament != "" {
+		query = query.Where("tournament = ?", tournament)
+	}
+	if err := query.Order("start_time ASC").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	var rows []struct {
+		MatchID uint
+		Total   int64
+	}
+	err := r.db.WithContext(ctx).
+		Table("predictions").
+		Select("matchId AS match_id, COUNT(*) AS total").
+		Where("matchId IN ?", ids).
+		Group("matchId").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.MatchID] = row.Total
+	}
+
+	result := make([]match.MatchPredictionCount, len(matches))
+	for i, m := range matches {
+		result[i] = match.MatchPredictionCount{Match: m, PredictionCount: counts[m.ID]}
+	}
+	return result, nil
+}
+
 // Delete 删除比赛
 func (r *MatchRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&match.Match{}, id).Error
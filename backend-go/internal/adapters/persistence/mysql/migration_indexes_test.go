@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+const queryPathIndexesMigration = "20251017000001_add_query_path_indexes"
+
+var createIndexPattern = regexp.MustCompile(`(?m)^CREATE INDEX (\w+) ON (\w+) `)
+
+// readMigration 读取仓库 migrations 目录下的迁移文件
+func readMigration(t *testing.T, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "migrations", name))
+	if err != nil {
+		t.Fatalf("读取迁移文件失败: %v", err)
+	}
+	return string(content)
+}
+
+func TestQueryPathIndexesMigration(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&user.User{}, &admin.AdminUser{}, &admin.AdminAuditLog{}, &prediction.Prediction{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	up := readMigration(t, queryPathIndexesMigration+".up.sql")
+	if err := db.Exec(up).Error; err != nil {
+		t.Fatalf("执行 up 迁移失败: %v", err)
+	}
+
+	created := createIndexPattern.FindAllStringSubmatch(up, -1)
+	if len(created) == 0 {
+		t.Fatal("up 迁移未创建任何索引")
+	}
+	for _, m := range created {
+		if !db.Migrator().HasIndex(m[2], m[1]) {
+			t.Errorf("索引 %s 未在 %s 上创建", m[1], m[2])
+		}
+	}
+
+	// 审计日志按管理员与时间范围查询应命中复合索引
+	var plan []struct {
+		Detail string
+	}
+	if err := db.Raw("EXPLAIN QUERY PLAN SELECT * FROM admin_audit_logs WHERE admin_user_id = ? AND created_at >= ? ORDER BY created_at DESC", 1, "2025-10-01").
+		Scan(&plan).Error; err != nil {
+		t.Fatalf("EXPLAIN 失败: %v", err)
+	}
+	if len(plan) == 0 || !strings.Contains(plan[0].Detail, "idx_audit_admin_created") {
+		t.Errorf("query plan = %+v, want idx_audit_admin_created", plan)
+	}
+
+	// down 迁移需逐一删除 up 创建的索引
+	down := readMigration(t, queryPathIndexesMigration+".down.sql")
+	for _, m := range created {
+		if drop := "DROP INDEX " + m[1] + " ON " + m[2] + ";"; !strings.Contains(down, drop) {
+			t.Errorf("down 迁移缺少 %q", drop)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"backend-go/internal/adapters/events/outbox"
 	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/prediction"
 	"backend-go/internal/core/domain/user"
@@ -49,5 +50,10 @@ func AutoMigrate(db *gorm.DB) error {
 		return err
 	}
 
+	// 迁移事件发件箱表
+	if err := db.AutoMigrate(&outbox.Message{}); err != nil {
+		return err
+	}
+
 	return nil
 }
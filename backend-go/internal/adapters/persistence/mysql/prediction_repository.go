@@ -4,9 +4,15 @@ import (
 	"context"
 	"fmt"
 
+	"backend-go/internal/adapters/events/outbox"
+	"backend-go/internal/adapters/events/types"
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/shared"
+	"backend-go/pkg/database"
 	"backend-go/pkg/response"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PredictionRepository 预测仓储 MySQL 实现
@@ -21,12 +27,30 @@ func NewPredictionRepository(db *gorm.DB) prediction.Repository {
 	}
 }
 
-// CreatePrediction 创建预测
+// CreatePrediction 创建预测，并在同一事务中写入 prediction.created 发件箱事件
 func (r *PredictionRepository) CreatePrediction(ctx context.Context, pred *prediction.Prediction) error {
-	if err := r.db.WithContext(ctx).Create(pred).Error; err != nil {
-		return fmt.Errorf("failed to create prediction: %w", err)
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pred).Error; err != nil {
+			if appErr := database.ClassifyError(err); appErr != nil {
+				return appErr
+			}
+			return fmt.Errorf("failed to create prediction: %w", err)
+		}
+
+		// 统计订阅方按赛事和距开赛时间分桶，载荷需携带比赛信息
+		var match domain.Match
+		if err := tx.Select("id", "tournament", "start_time").First(&match, pred.MatchID).Error; err != nil {
+			return fmt.Errorf("failed to load match %d for prediction event: %w", pred.MatchID, err)
+		}
+
+		return outbox.Enqueue(tx, shared.EventPredictionCreated, types.PredictionCreatedPayload{
+			PredictionID:     pred.ID,
+			UserID:           pred.UserID,
+			MatchID:          pred.MatchID,
+			Tournament:       string(match.Tournament),
+			TimeToMatchStart: match.StartTime.Sub(pred.CreatedAt),
+		})
+	})
 }
 
 // GetPredictionByID 根据 ID 获取预测
@@ -68,8 +92,23 @@ func (r *PredictionRepository) GetPredictionByUserAndMatch(ctx context.Context,
 
 // UpdatePrediction 更新预测
 func (r *PredictionRepository) UpdatePrediction(ctx context.Context, pred *prediction.Prediction) error {
-	if err := r.db.WithContext(ctx).Save(pred).Error; err != nil {
-		return fmt.Errorf("failed to update prediction: %w", err)
+	expected := pred.Version
+	pred.Version = expected + 1
+
+	// 条件更新：仅当数据库中的版本与读取时一致才写入，避免并发修改互相覆盖
+	result := r.db.WithContext(ctx).
+		Model(pred).
+		Where("version = ?", expected).
+		Select("*").
+		Omit("CreatedAt", clause.Associations).
+		Updates(pred)
+	if result.Error != nil {
+		pred.Version = expected
+		return fmt.Errorf("failed to update prediction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		pred.Version = expected
+		return response.NewConflictError("预测已被修改，请刷新后重试", nil)
 	}
 	return nil
 }
@@ -128,6 +167,23 @@ func (r *PredictionRepository) GetPredictionsByUser(ctx context.Context, userID
 	return predictions, nil
 }
 
+// GetPredictionsByMatchAfter 按 ID 键集分批获取比赛预测
+func (r *PredictionRepository) GetPredictionsByMatchAfter(ctx context.Context, matchID, afterID uint, limit int) ([]prediction.Prediction, error) {
+	var predictions []prediction.Prediction
+
+	err := r.db.WithContext(ctx).
+		Where("matchId = ? AND id > ?", matchID, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&predictions).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get predictions by match: %w", err)
+	}
+
+	return predictions, nil
+}
+
 // UpdatePredictionPoints 更新预测积分
 func (r *PredictionRepository) UpdatePredictionPoints(ctx context.Context, predictionID uint, points int, isCorrect bool) error {
 	err := r.db.WithContext(ctx).
@@ -201,3 +257,21 @@ func (r *PredictionRepository) DeletePrediction(ctx context.Context, id uint) er
 
 	return nil
 }
+
+// GetConsensusBuckets 按预测获胜方分组统计比赛预测
+func (r *PredictionRepository) GetConsensusBuckets(ctx context.Context, matchID uint) ([]prediction.ConsensusBucket, error) {
+	var buckets []prediction.ConsensusBucket
+
+	err := r.db.WithContext(ctx).
+		Model(&prediction.Prediction{}).
+		Select("predictedWinner, COUNT(*) AS count, COALESCE(SUM(vote_count), 0) AS vote_count").
+		Where("matchId = ?", matchID).
+		Group("predictedWinner").
+		Scan(&buckets).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consensus buckets: %w", err)
+	}
+
+	return buckets, nil
+}
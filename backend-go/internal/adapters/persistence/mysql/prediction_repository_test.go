@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-go/internal/adapters/events"
+	"backend-go/internal/adapters/events/outbox"
+	"backend-go/internal/adapters/events/types"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/shared"
+	"backend-go/internal/core/domain/user"
+)
+
+func TestPredictionRepository_CreatePredictionWritesOutbox(t *testing.T) {
+	ctx := context.Background()
+	db := newLeaderboardTestDB(t)
+	if err := db.AutoMigrate(&outbox.Message{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	repo := NewPredictionRepository(db)
+
+	u := &user.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+	if err := db.Create(u).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	matches := createMatches(t, db, 2, domain.TournamentSpring, domain.MatchStatusUpcoming)
+
+	pred := &prediction.Prediction{UserID: u.ID, MatchID: matches[0].ID, PredictedWinner: "A"}
+	if err := repo.CreatePrediction(ctx, pred); err != nil {
+		t.Fatalf("CreatePrediction() error = %v", err)
+	}
+
+	var messages []outbox.Message
+	if err := db.Find(&messages).Error; err != nil {
+		t.Fatalf("读取发件箱失败: %v", err)
+	}
+	if len(messages) != 1 || messages[0].EventType != shared.EventPredictionCreated {
+		t.Fatalf("messages = %+v, want one prediction.created", messages)
+	}
+	// 中继按 events.DecodeReplayPayload 解码为 types.PredictionCreatedPayload
+	decoded, err := events.DecodeReplayPayload(messages[0].EventType, messages[0].Payload)
+	if err != nil {
+		t.Fatalf("解析载荷失败: %v", err)
+	}
+	payload, ok := decoded.(*types.PredictionCreatedPayload)
+	if !ok {
+		t.Fatalf("decoded = %T, want *types.PredictionCreatedPayload", decoded)
+	}
+	if payload.PredictionID != pred.ID || payload.UserID != u.ID || payload.MatchID != matches[0].ID {
+		t.Errorf("payload = %+v, want prediction %d", payload, pred.ID)
+	}
+	if payload.Tournament != string(domain.TournamentSpring) {
+		t.Errorf("tournament = %q, want %q", payload.Tournament, domain.TournamentSpring)
+	}
+	if want := matches[0].StartTime.Sub(pred.CreatedAt); payload.TimeToMatchStart.Round(time.Second) != want.Round(time.Second) || payload.TimeToMatchStart == 0 {
+		t.Errorf("time_to_match_start = %v, want %v", payload.TimeToMatchStart, want)
+	}
+
+	// 发件箱写入失败时预测随事务一起回滚
+	if err := db.Migrator().DropTable(&outbox.Message{}); err != nil {
+		t.Fatalf("删除发件箱表失败: %v", err)
+	}
+	failed := &prediction.Prediction{UserID: u.ID, MatchID: matches[1].ID, PredictedWinner: "B"}
+	if err := repo.CreatePrediction(ctx, failed); err == nil {
+		t.Fatal("CreatePrediction() error = nil, want outbox failure")
+	}
+	var count int64
+	if err := db.Model(&prediction.Prediction{}).Where(&prediction.Prediction{MatchID: matches[1].ID}).Count(&count).Error; err != nil {
+		t.Fatalf("统计预测失败: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("预测数 = %d, want 0 after rollback", count)
+	}
+}
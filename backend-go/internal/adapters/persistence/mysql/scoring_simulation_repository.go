@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/leaderboard"
+	"backend-go/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// ScoringSimulationRepository MySQL implementation of the read-only scoring simulation repository
+type ScoringSimulationRepository struct {
+	db *gorm.DB
+}
+
+// NewScoringSimulationRepository creates a new scoring simulation repository instance
+func NewScoringSimulationRepository(db *gorm.DB) *ScoringSimulationRepository {
+	return &ScoringSimulationRepository{
+		db: db,
+	}
+}
+
+// ListSettledPredictions lists predictions on finished matches of a sport type
+func (r *ScoringSimulationRepository) ListSettledPredictions(ctx context.Context, sportTypeID uint, tournament string) ([]*ports.SettledPrediction, error) {
+	query := r.db.WithContext(ctx).
+		Table("predictions AS p").
+		Select("p.id AS prediction_id, p.userId AS user_id, u.username, u.nickname, "+
+			"p.matchId AS match_id, m.start_time AS match_start_time, p.predictedWinner AS predicted_winner, "+
+			"p.isCorrect AS is_correct, p.modification_count, p.vote_count, p.earnedPoints AS earned_points, "+
+			"p.createdAt AS created_at").
+		Joins("JOIN matches AS m ON m.id = p.matchId").
+		Joins("JOIN users AS u ON u.id = p.userId").
+		Where("m.status = ? AND m.sport_type_id = ?", domain.MatchStatusFinished, sportTypeID)
+
+	if tournament != "" && tournament != string(leaderboard.TournamentGlobal) {
+		query = query.Where("m.tournament = ?", tournament)
+	}
+
+	var predictions []*ports.SettledPrediction
+	if err := query.Order("p.id ASC").Scan(&predictions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list settled predictions: %w", err)
+	}
+
+	return predictions, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"backend-go/internal/core/domain/sport"
 	"backend-go/internal/core/ports"
+	"backend-go/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -24,6 +25,9 @@ func NewSportTypeRepository(db *gorm.DB) *SportTypeRepository {
 // Create 创建运动类型
 func (r *SportTypeRepository) Create(ctx context.Context, sportType *sport.SportType) error {
 	if err := r.db.WithContext(ctx).Create(sportType).Error; err != nil {
+		if appErr := database.ClassifyError(err); appErr != nil {
+			return appErr
+		}
 		return fmt.Errorf("failed to create sport type: %w", err)
 	}
 	return nil
@@ -149,6 +153,46 @@ func (r *SportTypeRepository) Count(ctx context.Context, options *ports.ListSpor
 	return count, nil
 }
 
+// ExistsByName 检查名称是否已被其他运动类型使用（不区分大小写）
+func (r *SportTypeRepository) ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&sport.SportType{}).Where("LOWER(name) = LOWER(?)", name)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check sport type name: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ExistsByCode 检查代码是否已被其他运动类型使用（不区分大小写）
+func (r *SportTypeRepository) ExistsByCode(ctx context.Context, code string, excludeID uint) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&sport.SportType{}).Where("LOWER(code) = LOWER(?)", code)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check sport type code: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CountReferences 统计引用该运动类型的比赛和管理员数量
+func (r *SportTypeRepository) CountReferences(ctx context.Context, id uint) (*ports.SportTypeReferences, error) {
+	refs := &ports.SportTypeReferences{}
+	if err := r.db.WithContext(ctx).Table("matches").Where("sport_type_id = ?", id).Count(&refs.MatchCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count related matches: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Table("admin_sport_access").Where("sport_type_id = ?", id).Count(&refs.ScopedAdminCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count scoped admins: %w", err)
+	}
+	return refs, nil
+}
+
 // CreateConfiguration 创建运动配置
 func (r *SportTypeRepository) CreateConfiguration(ctx context.Context, config *sport.SportConfiguration) error {
 	if err := r.db.WithContext(ctx).Create(config).Error; err != nil {
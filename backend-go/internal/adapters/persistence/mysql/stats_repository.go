@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+	"gorm.io/gorm"
+)
+
+// StatsRepository MySQL实现的统计计数仓储
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository 创建统计计数仓储实例
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{
+		db: db,
+	}
+}
+
+// groupCount 按ID分组的计数结果
+type groupCount struct {
+	ID    uint
+	Count int64
+}
+
+// ListUserIDs 按ID升序分页获取用户ID
+func (r *StatsRepository) ListUserIDs(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	ids, err := r.listIDs(ctx, &user.User{}, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user ids: %w", err)
+	}
+	return ids, nil
+}
+
+// ListPredictionIDs 按ID升序分页获取预测ID
+func (r *StatsRepository) ListPredictionIDs(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	ids, err := r.listIDs(ctx, &prediction.Prediction{}, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prediction ids: %w", err)
+	}
+	return ids, nil
+}
+
+// CountPredictionsByUsers 统计用户创建的预测数，包含数据保留任务已清理的预测
+func (r *StatsRepository) CountPredictionsByUsers(ctx context.Context, userIDs []uint) (map[uint]int64, error) {
+	counts, err := r.countBy(ctx, &prediction.Prediction{}, "user_id", userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count predictions by users: %w", err)
+	}
+	if err := r.addArchived(ctx, counts, "predictions", userIDs); err != nil {
+		return nil, fmt.Errorf("failed to count archived predictions by users: %w", err)
+	}
+	return counts, nil
+}
+
+// CountVotesByVoters 统计用户投出的票数，包含数据保留任务已清理的投票
+func (r *StatsRepository) CountVotesByVoters(ctx context.Context, userIDs []uint) (map[uint]int64, error) {
+	counts, err := r.countBy(ctx, &prediction.Vote{}, "user_id", userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count votes by voters: %w", err)
+	}
+	if err := r.addArchived(ctx, counts, "votes_cast", userIDs); err != nil {
+		return nil, fmt.Errorf("failed to count archived votes by voters: %w", err)
+	}
+	return counts, nil
+}
+
+// CountVotesByPredictions 统计预测获得的票数
+func (r *StatsRepository) CountVotesByPredictions(ctx context.Context, predictionIDs []uint) (map[uint]int64, error) {
+	counts, err := r.countBy(ctx, &prediction.Vote{}, "prediction_id", predictionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count votes by predictions: %w", err)
+	}
+	return counts, nil
+}
+
+// addArchived 累加用户归档统计中的计数
+func (r *StatsRepository) addArchived(ctx context.Context, counts map[uint]int64, column string, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var rows []groupCount
+	err := r.db.WithContext(ctx).
+		Model(&domain.UserArchivedStats{}).
+		Select("user_id AS id, "+column+" AS count").
+		Where("user_id IN ?", userIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		counts[row.ID] += row.Count
+	}
+	return nil
+}
+
+// listIDs 按ID升序获取 afterID 之后的主键
+func (r *StatsRepository) listIDs(ctx context.Context, model interface{}, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	query := r.db.WithContext(ctx).Model(model).Where("id > ?", afterID).Order("id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// countBy 按列分组计数，未出现的ID计数为0
+func (r *StatsRepository) countBy(ctx context.Context, model interface{}, column string, ids []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []groupCount
+	err := r.db.WithContext(ctx).
+		Model(model).
+		Select(column+" AS id, COUNT(*) AS count").
+		Where(column+" IN ?", ids).
+		Group(column).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
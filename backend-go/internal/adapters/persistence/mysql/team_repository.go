@@ -6,6 +6,7 @@ import (
 
 	"backend-go/internal/core/domain/team"
 	"backend-go/internal/core/ports"
+	"backend-go/pkg/database"
 	"gorm.io/gorm"
 )
 
@@ -36,6 +37,9 @@ func NewTeamRepository(db *gorm.DB) ports.TeamRepository {
 func (r *TeamRepository) Create(ctx context.Context, t *team.Team) (*team.Team, error) {
 	rec := toTeamRecord(t)
 	if err := r.db.WithContext(ctx).Create(rec).Error; err != nil {
+		if appErr := database.ClassifyError(err); appErr != nil {
+			return nil, appErr
+		}
 		return nil, fmt.Errorf("创建战队失败: %w", err)
 	}
 	return toTeamDomain(rec), nil
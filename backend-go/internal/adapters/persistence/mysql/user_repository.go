@@ -7,7 +7,9 @@ import (
 
 	"backend-go/internal/core/domain/user"
 	"backend-go/internal/shared/password"
+	"backend-go/pkg/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository MySQL 用户仓储实现
@@ -76,6 +78,9 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 
 	// 创建用户
 	if err := r.db.WithContext(ctx).Create(u).Error; err != nil {
+		if appErr := database.ClassifyError(err); appErr != nil {
+			return appErr
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -337,3 +342,32 @@ func (r *UserRepository) ChangePassword(ctx context.Context, userID uint, newPas
 
 	return nil
 }
+
+// GetNotificationPreferences 获取通知偏好，未设置过时返回默认值
+func (r *UserRepository) GetNotificationPreferences(ctx context.Context, userID uint) (*user.NotificationPreferences, error) {
+	var prefs user.NotificationPreferences
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&prefs).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user.DefaultNotificationPreferences(userID), nil
+		}
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// SaveNotificationPreferences 保存通知偏好（不存在时创建）
+func (r *UserRepository) SaveNotificationPreferences(ctx context.Context, prefs *user.NotificationPreferences) error {
+	if prefs == nil || prefs.UserID == 0 {
+		return errors.New("invalid notification preferences")
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email_enabled", "reminder_lead_minutes", "push_enabled", "updated_at"}),
+	}).Create(prefs).Error
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
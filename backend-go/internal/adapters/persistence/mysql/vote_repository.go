@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"backend-go/internal/core/domain/prediction"
+	"backend-go/pkg/database"
 	"backend-go/pkg/response"
 	"gorm.io/gorm"
 )
@@ -24,6 +25,9 @@ func NewVoteRepository(db *gorm.DB) prediction.VoteRepository {
 // CreateVote 创建投票
 func (r *VoteRepository) CreateVote(ctx context.Context, vote *prediction.Vote) error {
 	if err := r.db.WithContext(ctx).Create(vote).Error; err != nil {
+		if appErr := database.ClassifyError(err); appErr != nil {
+			return appErr
+		}
 		return fmt.Errorf("failed to create vote: %w", err)
 	}
 	return nil
@@ -137,6 +141,9 @@ func (r *VoteRepository) CreateVoteWithCount(ctx context.Context, vote *predicti
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 创建投票
 		if err := tx.Create(vote).Error; err != nil {
+			if appErr := database.ClassifyError(err); appErr != nil {
+				return appErr
+			}
 			return fmt.Errorf("failed to create vote: %w", err)
 		}
 
@@ -151,6 +158,53 @@ func (r *VoteRepository) CreateVoteWithCount(ctx context.Context, vote *predicti
 	})
 }
 
+// CreateVotesWithCounts 批量创建投票并按预测汇总更新计数（事务性操作）
+func (r *VoteRepository) CreateVotesWithCounts(ctx context.Context, votes []*prediction.Vote) error {
+	if len(votes) == 0 {
+		return nil
+	}
+
+	// 按预测汇总新增票数，每个增量只执行一次更新
+	increments := make(map[uint]int)
+	var predictionIDs []uint
+	for _, vote := range votes {
+		if increments[vote.PredictionID] == 0 {
+			predictionIDs = append(predictionIDs, vote.PredictionID)
+		}
+		increments[vote.PredictionID]++
+	}
+	byIncrement := make(map[int][]uint)
+	var deltas []int
+	for _, id := range predictionIDs {
+		delta := increments[id]
+		if len(byIncrement[delta]) == 0 {
+			deltas = append(deltas, delta)
+		}
+		byIncrement[delta] = append(byIncrement[delta], id)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 创建投票
+		if err := tx.Create(votes).Error; err != nil {
+			if appErr := database.ClassifyError(err); appErr != nil {
+				return appErr
+			}
+			return fmt.Errorf("failed to create votes: %w", err)
+		}
+
+		// 更新预测投票数
+		for _, delta := range deltas {
+			if err := tx.Model(&prediction.Prediction{}).
+				Where("id IN ?", byIncrement[delta]).
+				UpdateColumn("vote_count", gorm.Expr("vote_count + ?", delta)).Error; err != nil {
+				return fmt.Errorf("failed to update vote counts: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // DeleteVoteWithCount 删除投票并更新计数（事务性操作）
 func (r *VoteRepository) DeleteVoteWithCount(ctx context.Context, userID, predictionID uint) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
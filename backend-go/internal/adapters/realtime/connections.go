@@ -0,0 +1,128 @@
+package realtime
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// EventConnectionClosed 连接被服务端关闭时推送的事件
+const EventConnectionClosed = "connection.closed"
+
+var (
+	// ErrConnectionNotFound 连接不存在或已断开
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrTooManyConnections 连接数已达到 HubConfig.MaxConnections
+	ErrTooManyConnections = errors.New("too many connections")
+)
+
+// Conn 长连接的关闭端，由具体推送通道实现
+type Conn interface {
+	// Close 向客户端发送关闭通知并结束连接
+	Close() error
+}
+
+// ConnInfo 活跃连接信息
+type ConnInfo struct {
+	ID          string    `json:"id"`
+	UserID      uint      `json:"user_id"`
+	RemoteAddr  string    `json:"remote_addr"`
+	ConnectedAt time.Time `json:"connected_at"`
+	Topics      []string  `json:"topics"`
+}
+
+// connection 已登记的连接及其订阅
+type connection struct {
+	info ConnInfo
+	conn Conn
+	subs []*Subscription
+}
+
+// Register 登记长连接，返回连接 ID；userID 为 0 表示匿名连接，连接数已满时返回 ErrTooManyConnections
+func (h *Hub) Register(conn Conn, userID uint, remoteAddr string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.config.MaxConnections > 0 && len(h.conns) >= h.config.MaxConnections {
+		return "", ErrTooManyConnections
+	}
+
+	h.nextConnID++
+	id := strconv.FormatUint(h.nextConnID, 10)
+	h.conns[id] = &connection{
+		info: ConnInfo{
+			ID:          id,
+			UserID:      userID,
+			RemoteAddr:  remoteAddr,
+			ConnectedAt: time.Now(),
+		},
+		conn: conn,
+	}
+	return id, nil
+}
+
+// SubscribeConn 为已登记的连接订阅主题，连接不存在时等同于 Subscribe
+func (h *Hub) SubscribeConn(connID, topic string) *Subscription {
+	sub := h.Subscribe(topic)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c, ok := h.conns[connID]; ok {
+		c.subs = append(c.subs, sub)
+	}
+	return sub
+}
+
+// Unregister 注销连接，连接自行断开时调用；订阅仍需调用方取消
+func (h *Hub) Unregister(connID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.conns, connID)
+}
+
+// ListConnections 列出活跃连接，按建立时间升序
+func (h *Hub) ListConnections() []ConnInfo {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	conns := make([]ConnInfo, 0, len(h.conns))
+	for _, c := range h.conns {
+		info := c.info
+		info.Topics = make([]string, 0, len(c.subs))
+		for _, sub := range c.subs {
+			info.Topics = append(info.Topics, sub.Topic)
+		}
+		conns = append(conns, info)
+	}
+
+	sort.Slice(conns, func(i, j int) bool {
+		if !conns[i].ConnectedAt.Equal(conns[j].ConnectedAt) {
+			return conns[i].ConnectedAt.Before(conns[j].ConnectedAt)
+		}
+		return conns[i].ID < conns[j].ID
+	})
+	return conns
+}
+
+// Terminate 关闭指定连接：通知客户端关闭、取消其全部订阅并移除登记
+func (h *Hub) Terminate(connID string) error {
+	h.mu.Lock()
+	c, ok := h.conns[connID]
+	if ok {
+		delete(h.conns, connID)
+	}
+	h.mu.Unlock()
+
+	if !ok {
+		return ErrConnectionNotFound
+	}
+
+	// 先通知连接关闭，再关闭订阅通道，确保推送方能写出关闭事件
+	err := c.conn.Close()
+	for _, sub := range c.subs {
+		h.Unsubscribe(sub)
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeConn 记录关闭次数的测试连接
+type fakeConn struct {
+	closed int
+}
+
+func (f *fakeConn) Close() error {
+	f.closed++
+	return nil
+}
+
+// mustRegister 登记连接，失败时终止测试
+func mustRegister(t *testing.T, hub *Hub, conn Conn, userID uint, remoteAddr string) string {
+	t.Helper()
+	id, err := hub.Register(conn, userID, remoteAddr)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return id
+}
+
+func TestHub_ListConnectionsReflectsSubscriptions(t *testing.T) {
+	hub := NewHub()
+	aliceID := mustRegister(t, hub, &fakeConn{}, 1, "10.0.0.1")
+	anonID := mustRegister(t, hub, &fakeConn{}, 0, "10.0.0.2")
+	hub.SubscribeConn(aliceID, UserTopic(1))
+	hub.SubscribeConn(aliceID, LeaderboardTopic("GLOBAL"))
+	hub.SubscribeConn(anonID, LeaderboardTopic("SPRING"))
+
+	conns := hub.ListConnections()
+	if len(conns) != 2 {
+		t.Fatalf("len(conns) = %d, want 2", len(conns))
+	}
+	if conns[0].ID != aliceID || conns[0].UserID != 1 || conns[0].RemoteAddr != "10.0.0.1" {
+		t.Errorf("conns[0] = %+v, want alice's connection", conns[0])
+	}
+	if got := conns[0].Topics; len(got) != 2 || got[0] != "user:1" || got[1] != "leaderboard:GLOBAL" {
+		t.Errorf("alice topics = %v", got)
+	}
+	if got := conns[1].Topics; len(got) != 1 || got[0] != "leaderboard:SPRING" {
+		t.Errorf("anonymous topics = %v", got)
+	}
+	if conns[0].ConnectedAt.IsZero() {
+		t.Error("ConnectedAt 未记录")
+	}
+}
+
+func TestHub_TerminateDisconnectsTargetOnly(t *testing.T) {
+	hub := NewHub()
+	target, other := &fakeConn{}, &fakeConn{}
+	targetID := mustRegister(t, hub, target, 1, "10.0.0.1")
+	otherID := mustRegister(t, hub, other, 2, "10.0.0.2")
+	targetSub := hub.SubscribeConn(targetID, LeaderboardTopic("GLOBAL"))
+	otherSub := hub.SubscribeConn(otherID, LeaderboardTopic("GLOBAL"))
+
+	if err := hub.Terminate(targetID); err != nil {
+		t.Fatalf("Terminate() error = %v", err)
+	}
+
+	if target.closed != 1 || other.closed != 0 {
+		t.Errorf("closed target = %d, other = %d, want 1 and 0", target.closed, other.closed)
+	}
+	if _, ok := <-targetSub.Messages(); ok {
+		t.Error("被终止连接的订阅通道应已关闭")
+	}
+	if delivered := hub.Publish(LeaderboardTopic("GLOBAL"), EventLeaderboardDelta, nil); delivered != 1 {
+		t.Errorf("delivered = %d, want 1", delivered)
+	}
+	if msg := <-otherSub.Messages(); msg.Event != EventLeaderboardDelta {
+		t.Errorf("其他连接应继续收到消息: %+v", msg)
+	}
+
+	conns := hub.ListConnections()
+	if len(conns) != 1 || conns[0].ID != otherID {
+		t.Errorf("conns = %+v, want only %s", conns, otherID)
+	}
+	if err := hub.Terminate(targetID); !errors.Is(err, ErrConnectionNotFound) {
+		t.Errorf("重复终止 error = %v, want ErrConnectionNotFound", err)
+	}
+}
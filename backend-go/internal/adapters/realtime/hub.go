@@ -0,0 +1,150 @@
+// Package realtime 提供实时推送的主题订阅中心，供 SSE 等长连接通道复用
+package realtime
+
+import (
+	"sync"
+	"time"
+)
+
+// 订阅中心默认参数
+const (
+	defaultSubscriberBuffer = 16
+	defaultPingPeriod       = 15 * time.Second
+	defaultWriteWait        = 10 * time.Second
+)
+
+// HubConfig 订阅中心与长连接参数，零值字段使用默认值
+type HubConfig struct {
+	PingPeriod       time.Duration // 心跳间隔，默认 15 秒
+	WriteWait        time.Duration // 单次写出的超时，默认 10 秒
+	SubscriberBuffer int           // 每个订阅缓冲的消息数，默认 16
+	MaxConnections   int           // 同时登记的最大连接数，<=0 表示不限制
+}
+
+// Message 推送消息
+type Message struct {
+	Topic     string      `json:"topic"`
+	Event     string      `json:"event"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Subscription 主题订阅
+type Subscription struct {
+	Topic    string
+	messages chan Message
+	once     sync.Once
+}
+
+// Messages 获取消息通道，订阅取消后通道关闭
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+// close 关闭订阅通道
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		close(s.messages)
+	})
+}
+
+// Hub 主题订阅中心
+type Hub struct {
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription]struct{}
+	config HubConfig
+
+	conns      map[string]*connection
+	nextConnID uint64
+}
+
+// NewHub 使用默认参数创建订阅中心
+func NewHub() *Hub {
+	return NewHubWithConfig(HubConfig{})
+}
+
+// NewHubWithConfig 按配置创建订阅中心
+func NewHubWithConfig(config HubConfig) *Hub {
+	if config.PingPeriod <= 0 {
+		config.PingPeriod = defaultPingPeriod
+	}
+	if config.WriteWait <= 0 {
+		config.WriteWait = defaultWriteWait
+	}
+	if config.SubscriberBuffer <= 0 {
+		config.SubscriberBuffer = defaultSubscriberBuffer
+	}
+
+	return &Hub{
+		topics: make(map[string]map[*Subscription]struct{}),
+		config: config,
+		conns:  make(map[string]*connection),
+	}
+}
+
+// Config 返回生效的配置，推送通道按其设置心跳间隔与写超时
+func (h *Hub) Config() HubConfig {
+	return h.config
+}
+
+// Subscribe 订阅主题
+func (h *Hub) Subscribe(topic string) *Subscription {
+	sub := &Subscription{
+		Topic:    topic,
+		messages: make(chan Message, h.config.SubscriberBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*Subscription]struct{})
+	}
+	h.topics[topic][sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe 取消订阅
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if subs, ok := h.topics[sub.Topic]; ok {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.topics, sub.Topic)
+		}
+	}
+	sub.close()
+}
+
+// Publish 向主题发布消息，订阅者缓冲区已满时丢弃该条消息，避免慢连接阻塞发布方
+func (h *Hub) Publish(topic, event string, data interface{}) int {
+	msg := Message{
+		Topic:     topic,
+		Event:     event,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	delivered := 0
+	for sub := range h.topics[topic] {
+		select {
+		case sub.messages <- msg:
+			delivered++
+		default:
+		}
+	}
+	return delivered
+}
+
+// SubscriberCount 获取主题订阅者数量
+func (h *Hub) SubscriberCount(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
@@ -0,0 +1,47 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewHubWithConfig_UsesConfiguredValues(t *testing.T) {
+	hub := NewHubWithConfig(HubConfig{
+		PingPeriod:       30 * time.Second,
+		WriteWait:        5 * time.Second,
+		SubscriberBuffer: 2,
+		MaxConnections:   2,
+	})
+
+	config := hub.Config()
+	if config.PingPeriod != 30*time.Second || config.WriteWait != 5*time.Second {
+		t.Errorf("Config() = %+v, want configured ping period and write wait", config)
+	}
+
+	// 订阅缓冲按配置，缓冲满后发布方丢弃消息
+	sub := hub.Subscribe(LeaderboardTopic("GLOBAL"))
+	for i := 0; i < 3; i++ {
+		hub.Publish(LeaderboardTopic("GLOBAL"), EventLeaderboardDelta, i)
+	}
+	if got := len(sub.Messages()); got != 2 {
+		t.Errorf("buffered = %d, want 2", got)
+	}
+
+	// 连接数达到上限后拒绝登记，注销后可再次登记
+	first := mustRegister(t, hub, &fakeConn{}, 1, "10.0.0.1")
+	mustRegister(t, hub, &fakeConn{}, 2, "10.0.0.2")
+	if _, err := hub.Register(&fakeConn{}, 3, "10.0.0.3"); !errors.Is(err, ErrTooManyConnections) {
+		t.Fatalf("第三个连接 error = %v, want ErrTooManyConnections", err)
+	}
+	hub.Unregister(first)
+	mustRegister(t, hub, &fakeConn{}, 3, "10.0.0.3")
+}
+
+func TestNewHub_Defaults(t *testing.T) {
+	config := NewHub().Config()
+	if config.PingPeriod != defaultPingPeriod || config.WriteWait != defaultWriteWait ||
+		config.SubscriberBuffer != defaultSubscriberBuffer || config.MaxConnections != 0 {
+		t.Errorf("Config() = %+v, want defaults", config)
+	}
+}
@@ -0,0 +1,55 @@
+package realtime
+
+import (
+	"sync"
+
+	"backend-go/internal/core/domain/leaderboard"
+)
+
+// 排行榜推送事件
+const (
+	EventLeaderboardSnapshot = "leaderboard.snapshot"
+	EventLeaderboardDelta    = "leaderboard.delta"
+)
+
+// LeaderboardTopic 排行榜主题名
+func LeaderboardTopic(tournament string) string {
+	return "leaderboard:" + tournament
+}
+
+// LeaderboardPublisher 将排行榜刷新转换为增量推送
+type LeaderboardPublisher struct {
+	hub  *Hub
+	mu   sync.Mutex
+	last map[string]map[uint]leaderboard.LeaderboardEntry
+}
+
+// NewLeaderboardPublisher 创建排行榜推送器
+func NewLeaderboardPublisher(hub *Hub) *LeaderboardPublisher {
+	return &LeaderboardPublisher{
+		hub:  hub,
+		last: make(map[string]map[uint]leaderboard.LeaderboardEntry),
+	}
+}
+
+// NotifyLeaderboardUpdated 计算与上次快照的差异，仅推送排名或积分变化的条目
+func (p *LeaderboardPublisher) NotifyLeaderboardUpdated(tournament string, entries []leaderboard.LeaderboardEntry) {
+	p.mu.Lock()
+	previous := p.last[tournament]
+	current := make(map[uint]leaderboard.LeaderboardEntry, len(entries))
+	changed := make([]leaderboard.LeaderboardEntry, 0)
+	for _, entry := range entries {
+		current[entry.UserID] = entry
+		old, ok := previous[entry.UserID]
+		if !ok || old.Rank != entry.Rank || old.Points != entry.Points {
+			changed = append(changed, entry)
+		}
+	}
+	p.last[tournament] = current
+	p.mu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	p.hub.Publish(LeaderboardTopic(tournament), EventLeaderboardDelta, changed)
+}
@@ -0,0 +1,23 @@
+package realtime
+
+import "strconv"
+
+// UserTopic 用户私有通知主题名
+func UserTopic(userID uint) string {
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// UserNotifier 通过订阅中心向单个用户推送通知
+type UserNotifier struct {
+	hub *Hub
+}
+
+// NewUserNotifier 创建用户通知推送器
+func NewUserNotifier(hub *Hub) *UserNotifier {
+	return &UserNotifier{hub: hub}
+}
+
+// PushToUser 推送到用户主题，返回收到通知的连接数
+func (n *UserNotifier) PushToUser(userID uint, event string, data interface{}) int {
+	return n.hub.Publish(UserTopic(userID), event, data)
+}
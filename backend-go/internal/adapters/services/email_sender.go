@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/config"
+	"backend-go/internal/core/ports"
+	"backend-go/pkg/breaker"
+	"backend-go/pkg/mailer"
+	"backend-go/pkg/response"
+)
+
+// NewEmailSender 根据配置创建邮件发送器，未启用邮件时仅记录日志
+func NewEmailSender(cfg config.EmailConfig, logger *logrus.Logger) ports.EmailSender {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if !cfg.Enabled || cfg.Provider != "smtp" {
+		return &logEmailSender{logger: logger}
+	}
+	return &smtpEmailSender{config: cfg}
+}
+
+// smtpEmailSender SMTP 邮件发送器
+type smtpEmailSender struct {
+	config config.EmailConfig
+}
+
+// SendEmail 发送纯文本邮件
+func (s *smtpEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + s.config.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := sendMail(ctx, s.config.Host, s.config.Port, auth, s.config.From, to, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// sendMail 与 smtp.SendMail 相同，但连接与整个会话受 ctx 的截止时间约束，SMTP 服务无响应时不会一直阻塞
+func sendMail(ctx context.Context, host string, port int, auth smtp.Auth, from, to string, msg []byte) error {
+	client, err := mailer.Dial(ctx, host, port)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(auth); err != nil {
+				return err
+			}
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// NewCircuitEmailSender 为邮件发送器加上熔断，熔断打开时立即返回外部服务错误
+func NewCircuitEmailSender(next ports.EmailSender, b *breaker.Breaker) ports.EmailSender {
+	return &circuitEmailSender{next: next, breaker: b}
+}
+
+// circuitEmailSender 带熔断的邮件发送器
+type circuitEmailSender struct {
+	next    ports.EmailSender
+	breaker *breaker.Breaker
+}
+
+// SendEmail 发送邮件，SMTP 连续失败后快速失败
+func (s *circuitEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	err := s.breaker.Execute(ctx, func(ctx context.Context) error {
+		return s.next.SendEmail(ctx, to, subject, body)
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		return response.NewExternalServiceError(s.breaker.Name(), err)
+	}
+	return err
+}
+
+// logEmailSender 只记录日志的邮件发送器（开发环境或未配置邮件服务时使用）
+type logEmailSender struct {
+	logger *logrus.Logger
+}
+
+// SendEmail 记录邮件内容
+func (s *logEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	s.logger.WithFields(logrus.Fields{
+		"to":      to,
+		"subject": subject,
+	}).Info("邮件服务未启用，邮件未实际发送")
+	return nil
+}
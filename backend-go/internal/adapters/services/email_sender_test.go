@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-go/pkg/breaker"
+	"backend-go/pkg/response"
+)
+
+// flakyEmailSender 按 fail 决定是否失败，记录实际发送次数
+type flakyEmailSender struct {
+	fail  bool
+	calls int
+}
+
+func (s *flakyEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	s.calls++
+	if s.fail {
+		return errors.New("smtp: connection refused")
+	}
+	return nil
+}
+
+func TestCircuitEmailSender_OpensAfterRepeatedFailuresAndRecovers(t *testing.T) {
+	ctx := context.Background()
+	next := &flakyEmailSender{fail: true}
+	b := breaker.New("email", breaker.Config{FailureThreshold: 3, Cooldown: 50 * time.Millisecond})
+	sender := NewCircuitEmailSender(next, b)
+
+	for i := 0; i < 3; i++ {
+		if err := sender.SendEmail(ctx, "a@example.com", "subject", "body"); err == nil {
+			t.Fatal("SMTP 失败时应返回错误")
+		}
+	}
+	if b.State() != breaker.StateOpen {
+		t.Fatalf("连续失败 3 次后 state = %s, want open", b.State())
+	}
+
+	// 熔断期间不再调用 SMTP，立即返回外部服务错误
+	start := time.Now()
+	err := sender.SendEmail(ctx, "a@example.com", "subject", "body")
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) || appErr.Code != response.CodeExternalService || !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("熔断时 err = %v, want external service error wrapping ErrOpen", err)
+	}
+	if next.calls != 3 {
+		t.Errorf("熔断时不应调用 SMTP, calls = %d", next.calls)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("熔断时应快速失败, took %v", elapsed)
+	}
+
+	// 冷却结束后放行试探调用，成功即恢复
+	next.fail = false
+	time.Sleep(60 * time.Millisecond)
+	if err := sender.SendEmail(ctx, "a@example.com", "subject", "body"); err != nil {
+		t.Fatalf("冷却后试探调用 err = %v", err)
+	}
+	if b.State() != breaker.StateClosed || next.calls != 4 {
+		t.Errorf("试探成功后 state = %s, calls = %d, want closed and 4", b.State(), next.calls)
+	}
+}
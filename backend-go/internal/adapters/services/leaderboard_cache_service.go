@@ -26,6 +26,7 @@ const (
 	leaderboardKeyPrefix = "leaderboard"
 	userRankKeyPrefix    = "user_rank"
 	statsKeyPrefix       = "leaderboard_stats"
+	accuracyKeyPrefix    = "leaderboard_accuracy"
 	cacheExpiration      = 5 * time.Minute // 5分钟缓存过期时间
 )
 
@@ -44,6 +45,11 @@ func (s *leaderboardCacheService) buildStatsKey(tournament string) string {
 	return fmt.Sprintf("%s:%s", statsKeyPrefix, tournament)
 }
 
+// buildAccuracyKey 构建准确率排行榜缓存键，不同最少预测数分别缓存
+func (s *leaderboardCacheService) buildAccuracyKey(tournament string, minPredictions int) string {
+	return fmt.Sprintf("%s:%s:%d", accuracyKeyPrefix, tournament, minPredictions)
+}
+
 // GetLeaderboard 从缓存获取排行榜
 func (s *leaderboardCacheService) GetLeaderboard(ctx context.Context, tournament string) ([]leaderboard.LeaderboardEntry, error) {
 	key := s.buildLeaderboardKey(tournament)
@@ -151,3 +157,31 @@ func (s *leaderboardCacheService) SetLeaderboardStats(ctx context.Context, tourn
 
 	return nil
 }
+
+// GetAccuracyLeaderboard 从缓存获取准确率排行榜
+func (s *leaderboardCacheService) GetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int) ([]leaderboard.AccuracyEntry, error) {
+	key := s.buildAccuracyKey(tournament, minPredictions)
+
+	var entries []leaderboard.AccuracyEntry
+	err := s.cache.GetJSON(ctx, key, &entries)
+	if err != nil {
+		if err == redis.ErrKeyNotFound {
+			return nil, nil // 缓存未命中
+		}
+		return nil, fmt.Errorf("获取准确率排行榜缓存失败: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SetAccuracyLeaderboard 设置准确率排行榜缓存
+func (s *leaderboardCacheService) SetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int, entries []leaderboard.AccuracyEntry) error {
+	key := s.buildAccuracyKey(tournament, minPredictions)
+
+	err := s.cache.SetJSON(ctx, key, entries, cacheExpiration)
+	if err != nil {
+		return fmt.Errorf("设置准确率排行榜缓存失败: %w", err)
+	}
+
+	return nil
+}
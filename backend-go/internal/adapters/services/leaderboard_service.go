@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -13,6 +14,7 @@ import (
 type leaderboardService struct {
 	repo         leaderboard.Repository
 	cacheService leaderboard.CacheService
+	notifier     leaderboard.Notifier
 	logger       *logrus.Logger
 }
 
@@ -20,11 +22,13 @@ type leaderboardService struct {
 func NewLeaderboardService(
 	repo leaderboard.Repository,
 	cacheService leaderboard.CacheService,
+	notifier leaderboard.Notifier,
 	logger *logrus.Logger,
 ) leaderboard.Service {
 	return &leaderboardService{
 		repo:         repo,
 		cacheService: cacheService,
+		notifier:     notifier,
 		logger:       logger,
 	}
 }
@@ -207,6 +211,11 @@ func (s *leaderboardService) RefreshLeaderboard(ctx context.Context, tournament
 		return fmt.Errorf("设置排行榜缓存失败: %w", err)
 	}
 
+	// 推送排行榜变更
+	if s.notifier != nil {
+		s.notifier.NotifyLeaderboardUpdated(tournament, entries)
+	}
+
 	// 刷新统计信息缓存
 	stats, err := s.repo.GetLeaderboardStats(ctx, tournament)
 	if err != nil {
@@ -226,6 +235,69 @@ func (s *leaderboardService) RefreshLeaderboard(ctx context.Context, tournament
 	return nil
 }
 
+// RefreshAll 并发刷新多个赛事的排行榜缓存
+//
+// 同时进行的刷新不超过 concurrency 个（<=0 时串行），避免预热时压垮数据库。
+// 单个赛事失败只记入报告；上下文取消后不再启动新的刷新，未执行的赛事记为失败并返回上下文错误。
+func (s *leaderboardService) RefreshAll(ctx context.Context, tournaments []string, concurrency int) (leaderboard.RefreshReport, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(tournaments))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, tournament := range tournaments {
+		if err := acquire(ctx, sem); err != nil {
+			for j := i; j < len(tournaments); j++ {
+				errs[j] = err
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int, tournament string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = s.RefreshLeaderboard(ctx, tournament)
+		}(i, tournament)
+	}
+	wg.Wait()
+
+	report := leaderboard.RefreshReport{Refreshed: make([]string, 0, len(tournaments))}
+	for i, tournament := range tournaments {
+		if errs[i] == nil {
+			report.Refreshed = append(report.Refreshed, tournament)
+			continue
+		}
+		if report.Failed == nil {
+			report.Failed = make(map[string]string)
+		}
+		report.Failed[tournament] = errs[i].Error()
+		s.logger.WithError(errs[i]).WithField("tournament", tournament).Warn("批量刷新排行榜失败")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("failed to refresh %d of %d leaderboards", len(report.Failed), len(tournaments))
+	}
+	return report, nil
+}
+
+// acquire 占用一个并发名额，上下文取消时返回错误
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // UpdateUserPoints 更新用户积分并刷新排行榜
 func (s *leaderboardService) UpdateUserPoints(ctx context.Context, userID uint, points int, tournament string) error {
 	// 验证锦标赛类型
@@ -272,3 +344,57 @@ func (s *leaderboardService) GetUsersAroundRank(ctx context.Context, tournament
 
 	return s.repo.GetUsersAroundRank(ctx, tournament, rank, radius)
 }
+
+// accuracyLeaderboardLimit 准确率排行榜返回的最大条目数
+const accuracyLeaderboardLimit = 100
+
+// GetAccuracyLeaderboard 获取按预测准确率排名的排行榜，与积分排行榜分开缓存
+func (s *leaderboardService) GetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int) ([]leaderboard.AccuracyEntry, error) {
+	// 验证锦标赛类型
+	if !leaderboard.IsValidTournament(tournament) {
+		tournament = string(leaderboard.TournamentGlobal)
+	}
+	if minPredictions <= 0 {
+		minPredictions = leaderboard.DefaultAccuracyMinPredictions
+	}
+
+	// 尝试从缓存获取
+	entries, err := s.cacheService.GetAccuracyLeaderboard(ctx, tournament, minPredictions)
+	if err != nil {
+		s.logger.WithError(err).WithField("tournament", tournament).Warn("获取准确率排行榜缓存失败")
+	}
+
+	if len(entries) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"tournament":      tournament,
+			"min_predictions": minPredictions,
+			"entries":         len(entries),
+			"source":          "cache",
+		}).Debug("从缓存获取准确率排行榜成功")
+
+		return entries, nil
+	}
+
+	// 缓存未命中，从数据库获取
+	entries, err = s.repo.GetAccuracyLeaderboard(ctx, tournament, minPredictions, accuracyLeaderboardLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取准确率排行榜失败: %w", err)
+	}
+
+	// 设置缓存
+	if len(entries) > 0 {
+		err = s.cacheService.SetAccuracyLeaderboard(ctx, tournament, minPredictions, entries)
+		if err != nil {
+			s.logger.WithError(err).WithField("tournament", tournament).Warn("设置准确率排行榜缓存失败")
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tournament":      tournament,
+		"min_predictions": minPredictions,
+		"entries":         len(entries),
+		"source":          "database",
+	}).Debug("从数据库获取准确率排行榜成功")
+
+	return entries, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/leaderboard"
+)
+
+// slowLeaderboardRepo 查询耗时固定的仓储，记录同时进行的查询数峰值
+type slowLeaderboardRepo struct {
+	leaderboard.Repository
+
+	delay   time.Duration
+	failing map[string]bool
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       map[string]int
+}
+
+func (r *slowLeaderboardRepo) GetLeaderboard(ctx context.Context, tournament string, limit int) ([]leaderboard.LeaderboardEntry, error) {
+	r.mu.Lock()
+	r.calls[tournament]++
+	r.inFlight++
+	if r.inFlight > r.maxInFlight {
+		r.maxInFlight = r.inFlight
+	}
+	r.mu.Unlock()
+
+	time.Sleep(r.delay)
+
+	r.mu.Lock()
+	r.inFlight--
+	r.mu.Unlock()
+	if r.failing[tournament] {
+		return nil, errors.New("database unavailable")
+	}
+	return []leaderboard.LeaderboardEntry{{UserID: 1, Points: 10, Rank: 1, Tournament: tournament}}, nil
+}
+
+func (r *slowLeaderboardRepo) GetLeaderboardStats(ctx context.Context, tournament string) (*leaderboard.LeaderboardStats, error) {
+	return &leaderboard.LeaderboardStats{Tournament: tournament}, nil
+}
+
+// noopLeaderboardCache 只记录写入的赛事
+type noopLeaderboardCache struct {
+	leaderboard.CacheService
+
+	mu  sync.Mutex
+	set []string
+}
+
+func (c *noopLeaderboardCache) InvalidateLeaderboard(ctx context.Context, tournament string) error {
+	return nil
+}
+
+func (c *noopLeaderboardCache) SetLeaderboard(ctx context.Context, tournament string, entries []leaderboard.LeaderboardEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set = append(c.set, tournament)
+	return nil
+}
+
+func (c *noopLeaderboardCache) SetLeaderboardStats(ctx context.Context, tournament string, stats *leaderboard.LeaderboardStats) error {
+	return nil
+}
+
+func newTestLeaderboardService(repo *slowLeaderboardRepo, cache *noopLeaderboardCache) leaderboard.Service {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewLeaderboardService(repo, cache, nil, logger)
+}
+
+func TestLeaderboardService_RefreshAllCapsConcurrency(t *testing.T) {
+	repo := &slowLeaderboardRepo{delay: 20 * time.Millisecond, calls: map[string]int{}}
+	cache := &noopLeaderboardCache{}
+	service := newTestLeaderboardService(repo, cache)
+
+	tournaments := []string{"SPRING", "SUMMER", "GLOBAL", "SPRING", "SUMMER", "GLOBAL"}
+	report, err := service.RefreshAll(context.Background(), tournaments, 2)
+	if err != nil {
+		t.Fatalf("RefreshAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Refreshed, tournaments) || len(report.Failed) != 0 {
+		t.Errorf("report = %+v, want 全部按传入顺序刷新", report)
+	}
+	if repo.maxInFlight != 2 {
+		t.Errorf("最大并发 = %d, want 2", repo.maxInFlight)
+	}
+	if len(cache.set) != len(tournaments) {
+		t.Errorf("写入缓存 %d 次, want %d", len(cache.set), len(tournaments))
+	}
+}
+
+func TestLeaderboardService_RefreshAllIsolatesFailures(t *testing.T) {
+	repo := &slowLeaderboardRepo{failing: map[string]bool{"SUMMER": true}, calls: map[string]int{}}
+	service := newTestLeaderboardService(repo, &noopLeaderboardCache{})
+
+	report, err := service.RefreshAll(context.Background(), []string{"SPRING", "SUMMER", "GLOBAL"}, 3)
+	if err == nil {
+		t.Fatal("RefreshAll() 应在存在失败赛事时返回错误")
+	}
+	if want := []string{"SPRING", "GLOBAL"}; !reflect.DeepEqual(report.Refreshed, want) {
+		t.Errorf("Refreshed = %v, want %v", report.Refreshed, want)
+	}
+	if _, ok := report.Failed["SUMMER"]; !ok || len(report.Failed) != 1 {
+		t.Errorf("Failed = %v, want 仅 SUMMER", report.Failed)
+	}
+}
+
+func TestLeaderboardService_RefreshAllStopsWhenCancelled(t *testing.T) {
+	repo := &slowLeaderboardRepo{calls: map[string]int{}}
+	service := newTestLeaderboardService(repo, &noopLeaderboardCache{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := service.RefreshAll(ctx, []string{"SPRING", "SUMMER"}, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RefreshAll() error = %v, want context.Canceled", err)
+	}
+	if len(report.Refreshed) != 0 || len(report.Failed) != 2 || len(repo.calls) != 0 {
+		t.Errorf("report = %+v, calls = %v, want 取消后不再刷新", report, repo.calls)
+	}
+}
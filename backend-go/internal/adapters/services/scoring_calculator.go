@@ -34,8 +34,11 @@ func (c *scoringCalculator) Calculate(pred *prediction.Prediction, rule *predict
 
 // CalculateWithAccuracy 根据准确性计算积分
 func (c *scoringCalculator) CalculateWithAccuracy(pred *prediction.Prediction, accuracy scoring.PredictionAccuracy, rule *prediction.ScoringRule) *scoring.PointsCalculationResult {
-	// 计算基础积分
-	basePoints := accuracy.CalculateBasePoints(rule)
+	// 判断是否正确
+	isCorrect := accuracy == scoring.AccuracyPerfect || accuracy == scoring.AccuracyTeamOnly
+
+	// 计算基础积分，规则启用信心倍数时按预测的信心倍数调整
+	basePoints := rule.ApplyConfidence(accuracy.CalculateBasePoints(rule), pred.Confidence, isCorrect)
 
 	// 计算热门奖励
 	popularityBonus := scoring.CalculatePopularityBonus(pred.VoteCount)
@@ -43,9 +46,6 @@ func (c *scoringCalculator) CalculateWithAccuracy(pred *prediction.Prediction, a
 	// 总积分
 	totalPoints := basePoints + popularityBonus.Bonus
 
-	// 判断是否正确
-	isCorrect := accuracy == scoring.AccuracyPerfect || accuracy == scoring.AccuracyTeamOnly
-
 	// 构建原因
 	reason := scoring.BuildPointsReason(accuracy, basePoints, popularityBonus)
 
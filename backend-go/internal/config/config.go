@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
@@ -21,6 +22,15 @@ type Config struct {
 	Features  FeatureConfig   `mapstructure:"features" validate:"required"`
 	Cache     CacheConfig     `mapstructure:"cache"`
 	External  ExternalConfig  `mapstructure:"external"`
+	Worker    WorkerConfig    `mapstructure:"worker"`
+	Analytics AnalyticsConfig `mapstructure:"analytics"`
+	WebSocket WebSocketConfig `mapstructure:"websocket"`
+	Badges    BadgesConfig    `mapstructure:"badges"`
+
+	// 选定配置项的来源，由 Load 在加载时记录
+	sources    map[string]ValueSource
+	envPrefix  string
+	configFile string // 实际读取的配置文件路径，未找到文件时为空
 }
 
 // Environment 环境类型
@@ -67,13 +77,141 @@ func (e Environment) IsTesting() bool {
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Host         string        `mapstructure:"host" validate:"required"`
-	Port         int           `mapstructure:"port" validate:"required,min=1,max=65535"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout" validate:"required,min=1s"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout" validate:"required,min=1s"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout" validate:"required,min=1s"`
-	Mode         string        `mapstructure:"mode" validate:"required,oneof=debug release test"`
-	TLS          TLSConfig     `mapstructure:"tls"`
+	Host              string                 `mapstructure:"host" validate:"required"`
+	Port              int                    `mapstructure:"port" validate:"required,min=1,max=65535"`
+	ReadTimeout       time.Duration          `mapstructure:"read_timeout" validate:"required,min=1s"`
+	WriteTimeout      time.Duration          `mapstructure:"write_timeout" validate:"required,min=1s"`
+	IdleTimeout       time.Duration          `mapstructure:"idle_timeout" validate:"required,min=1s"`
+	ShutdownTimeout   time.Duration          `mapstructure:"shutdown_timeout" validate:"min=1s,max=10m"` // 优雅关闭等待现有请求完成的最长时间
+	RequestTimeout    time.Duration          `mapstructure:"request_timeout" validate:"min=0"`           // 单个请求的处理时限，下游查询按剩余预算执行，0 表示不限制
+	Mode              string                 `mapstructure:"mode" validate:"required,oneof=debug release test"`
+	TLS               TLSConfig              `mapstructure:"tls"`
+	MaxHeaderBytes    int                    `mapstructure:"max_header_bytes" validate:"min=4096,max=16777216"` // 请求头（含请求行）大小上限
+	HTTP2             HTTP2Config            `mapstructure:"http2"`
+	KeepAlive         KeepAliveConfig        `mapstructure:"keep_alive"`
+	Idempotency       IdempotencyConfig      `mapstructure:"idempotency"`
+	BodyLimit         BodyLimitConfig        `mapstructure:"body_limit"`
+	Concurrency       ConcurrencyConfig      `mapstructure:"concurrency"`
+	StreamConnections StreamConnectionConfig `mapstructure:"stream_connections"`
+	Pagination        PaginationConfig       `mapstructure:"pagination"`
+	ResponseMetadata  bool                   `mapstructure:"response_metadata"` // 成功响应附带处理耗时与服务端时间
+	JSONNaming        string                 `mapstructure:"json_naming" validate:"oneof=preserve snake_case"` // 响应数据字段命名策略
+	TrustedProxies    []string               `mapstructure:"trusted_proxies" validate:"dive,ip|cidr"`          // 可信代理（IP 或 CIDR），只有来自这些地址的请求才读取 X-Forwarded-For
+}
+
+// IdempotencyConfig 写请求 Idempotency-Key 去重配置
+type IdempotencyConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl" validate:"min=1m,max=168h"`     // 首次响应保留时长，期间的重试直接返回该响应
+	LockTTL time.Duration `mapstructure:"lock_ttl" validate:"min=1s,max=1h"` // 首次请求处理期间持有锁的时长，应长于 server.request_timeout
+}
+
+// BodyLimitConfig 请求体大小限制配置（文件上传接口使用 external.file_storage.max_size）
+type BodyLimitConfig struct {
+	MaxBytes int64            `mapstructure:"max_bytes" validate:"min=0"`
+	Routes   []RouteBodyLimit `mapstructure:"routes" validate:"dive"`
+}
+
+// RouteBodyLimit 单个路由的请求体大小上限
+type RouteBodyLimit struct {
+	Path     string `mapstructure:"path" validate:"required"`
+	MaxBytes int64  `mapstructure:"max_bytes" validate:"min=1"`
+}
+
+// ConcurrencyConfig 并发请求数限制（隔离舱）配置，与按速率的限流相互独立
+type ConcurrencyConfig struct {
+	MaxInFlight  int                     `mapstructure:"max_in_flight" validate:"min=0"`
+	QueueTimeout time.Duration           `mapstructure:"queue_timeout" validate:"min=0"`
+	RetryAfter   time.Duration           `mapstructure:"retry_after" validate:"min=0"`
+	Routes       []RouteConcurrencyLimit `mapstructure:"routes" validate:"dive"`
+}
+
+// RouteConcurrencyLimit 单个路由的并发上限
+type RouteConcurrencyLimit struct {
+	Path        string `mapstructure:"path" validate:"required"`
+	MaxInFlight int    `mapstructure:"max_in_flight" validate:"min=1"`
+}
+
+// PaginationConfig 列表接口每页数量上限，对所有 GET 请求的 page_size 与 limit 参数生效
+type PaginationConfig struct {
+	MaxPageSize     int  `mapstructure:"max_page_size" validate:"min=0"` // 0 表示不限制
+	RejectOversized bool `mapstructure:"reject_oversized"`               // 为 true 时超出上限返回 400，否则截断为上限
+}
+
+// StreamConnectionConfig 实时推送连接建立频率限制，计数保存在 Redis 中由所有实例共享
+type StreamConnectionConfig struct {
+	MaxPerWindow int           `mapstructure:"max_per_window" validate:"min=0"` // 每个 IP 每个窗口允许的新建连接数，0 表示不限制
+	Window       time.Duration `mapstructure:"window" validate:"min=1s,max=1h"`
+}
+
+// WorkerConfig 后台 worker 进程配置
+type WorkerConfig struct {
+	ShutdownTimeout time.Duration   `mapstructure:"shutdown_timeout" validate:"min=1s,max=10m"` // 关闭时等待积分计算队列排空的最长时间
+	Outbox          OutboxConfig    `mapstructure:"outbox"`
+	Retention       RetentionConfig `mapstructure:"retention"`
+}
+
+// RetentionConfig 旧比赛数据的归档与清理配置
+type RetentionConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Retention     time.Duration `mapstructure:"retention" validate:"min=720h"` // 开始时间早于该时长之前的已结束比赛会被清理，至少 30 天
+	Interval      time.Duration `mapstructure:"interval" validate:"min=1h,max=168h"`
+	BatchSize     int           `mapstructure:"batch_size" validate:"min=1,max=1000"` // 每批清理的比赛数
+	ArchivePrefix string        `mapstructure:"archive_prefix"`                       // 归档文件在文件存储中的键前缀
+}
+
+// OutboxConfig 事件发件箱中继配置
+type OutboxConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Interval  time.Duration `mapstructure:"interval" validate:"min=100ms,max=10m"`
+	BatchSize int           `mapstructure:"batch_size" validate:"min=1,max=10000"`
+	Retention time.Duration `mapstructure:"retention" validate:"min=1h"` // 已投递事件的保留时间，过期后清理
+}
+
+// AnalyticsConfig 行为统计配置
+type AnalyticsConfig struct {
+	PageViews   PageViewsConfig   `mapstructure:"page_views"`
+	StatsBuffer StatsBufferConfig `mapstructure:"stats_buffer"`
+}
+
+// StatsBufferConfig 统计计数异步写入缓冲配置
+type StatsBufferConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`                                   // 关闭后统计处理器同步写入 Redis
+	Size          int           `mapstructure:"size" validate:"min=1"`                     // 缓冲区容量，已满时丢弃最早的增量
+	BatchSize     int           `mapstructure:"batch_size" validate:"min=1,max=10000"`     // 单个 pipeline 最多写入的键数
+	FlushInterval time.Duration `mapstructure:"flush_interval" validate:"min=10ms,max=1m"` // 定期写入间隔
+}
+
+// PageViewsConfig 页面访问统计配置
+type PageViewsConfig struct {
+	// 单独计数的页面路径模板，纯数字路径段按 :id 匹配；其他页面只计入聚合计数
+	TrackedPaths []string `mapstructure:"tracked_paths"`
+}
+
+// WebSocketConfig 长连接参数，实时推送订阅中心按此设置心跳、写超时与连接上限
+type WebSocketConfig struct {
+	PingPeriod     time.Duration `mapstructure:"ping_period" validate:"min=1s"`     // 心跳间隔，必须小于 pong_wait
+	PongWait       time.Duration `mapstructure:"pong_wait" validate:"min=1s"`       // 等待客户端响应心跳的最长时间
+	WriteWait      time.Duration `mapstructure:"write_wait" validate:"min=1s"`      // 单次写出的超时
+	MaxMessageSize int64         `mapstructure:"max_message_size" validate:"min=1"` // 客户端单条消息的最大字节数
+	ReadBufferSize int           `mapstructure:"read_buffer_size" validate:"min=1"` // 读缓冲区字节数
+	MaxConnections int           `mapstructure:"max_connections" validate:"min=0"`  // 单实例最大同时连接数，0 表示不限制
+}
+
+// BadgesConfig 用户徽章配置
+type BadgesConfig struct {
+	CacheTTL    time.Duration           `mapstructure:"cache_ttl" validate:"min=1m"` // 徽章缓存时间，计分后会提前刷新
+	Definitions []BadgeDefinitionConfig `mapstructure:"definitions" validate:"dive"` // 为空时使用内置徽章
+}
+
+// BadgeDefinitionConfig 徽章及其获得门槛
+type BadgeDefinitionConfig struct {
+	ID             string  `mapstructure:"id" validate:"required"`
+	Name           string  `mapstructure:"name" validate:"required"`
+	Description    string  `mapstructure:"description"`
+	Kind           string  `mapstructure:"kind" validate:"oneof=streak accuracy points"`
+	Threshold      float64 `mapstructure:"threshold" validate:"gt=0"`
+	MinPredictions int     `mapstructure:"min_predictions" validate:"min=0"` // 已计分预测数下限
 }
 
 // TLSConfig TLS 配置
@@ -83,6 +221,18 @@ type TLSConfig struct {
 	KeyFile  string `mapstructure:"key_file"`
 }
 
+// HTTP2Config HTTP/2 配置，启用 TLS 时通过 ALPN 协商 h2，否则使用明文 h2c
+type HTTP2Config struct {
+	Enabled              bool   `mapstructure:"enabled"`
+	MaxConcurrentStreams uint32 `mapstructure:"max_concurrent_streams" validate:"max=10000"` // 单连接并发流上限，0 使用默认值 250
+}
+
+// KeepAliveConfig 连接保活配置
+type KeepAliveConfig struct {
+	Enabled bool          `mapstructure:"enabled"`                       // 关闭后每个 HTTP/1.1 请求处理完即断开连接
+	Period  time.Duration `mapstructure:"period" validate:"min=0,max=10m"` // TCP keep-alive 探测间隔，0 使用系统默认值
+}
+
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
 	Host            string          `mapstructure:"host" validate:"required"`
@@ -96,6 +246,8 @@ type DatabaseConfig struct {
 	MaxIdleConns    int             `mapstructure:"max_idle_conns" validate:"min=1,max=50"`
 	ConnMaxLifetime time.Duration   `mapstructure:"conn_max_lifetime" validate:"min=1m"`
 	ConnMaxIdleTime time.Duration   `mapstructure:"conn_max_idle_time"`
+	QueryTimeout    time.Duration   `mapstructure:"query_timeout" validate:"min=0"`    // 单条语句超时，作用于共享连接上的所有语句（含后台任务），0 表示只受请求剩余预算约束
+	MinQueryBudget  time.Duration   `mapstructure:"min_query_budget" validate:"min=0"` // 剩余预算低于该值时不再发起语句，直接超时失败
 	SSL             SSLConfig       `mapstructure:"ssl"`
 	Migration       MigrationConfig `mapstructure:"migration"`
 }
@@ -113,6 +265,11 @@ type MigrationConfig struct {
 	Enabled    bool   `mapstructure:"enabled"`
 	AutoCreate bool   `mapstructure:"auto_create"`
 	Path       string `mapstructure:"path"`
+	// RequireDownMigrations 为 true 时，存在缺少 down 文件的待执行迁移则拒绝执行 up
+	RequireDownMigrations bool `mapstructure:"require_down_migrations"`
+	// SkipAutoMigrate 为 true 时 up 命令跳过 GORM AutoMigrate，只执行手动迁移；
+	// 基础表只由 AutoMigrate 创建，仅适用于已建好基础结构的数据库
+	SkipAutoMigrate bool `mapstructure:"skip_auto_migrate"`
 }
 
 // RedisConfig Redis 配置
@@ -131,7 +288,11 @@ type RedisConfig struct {
 	IdleTimeout   time.Duration `mapstructure:"idle_timeout" validate:"min=1m"`
 	MaxConnAge    time.Duration `mapstructure:"max_conn_age"`
 	IdleCheckFreq time.Duration `mapstructure:"idle_check_freq"`
-	Cluster       ClusterConfig `mapstructure:"cluster"`
+	// 单次命令超时，请求剩余预算更短时以剩余预算为准
+	OperationTimeout time.Duration `mapstructure:"operation_timeout" validate:"min=0"`
+	// 剩余预算低于该值时不再发起命令，直接超时失败
+	MinOperationBudget time.Duration `mapstructure:"min_operation_budget" validate:"min=0"`
+	Cluster            ClusterConfig `mapstructure:"cluster"`
 }
 
 // ClusterConfig Redis 集群配置
@@ -142,15 +303,45 @@ type ClusterConfig struct {
 
 // AuthConfig 认证配置
 type AuthConfig struct {
-	JWTSecret           string         `mapstructure:"jwt_secret" validate:"required,min=32"`
-	JWTExpirationHours  int            `mapstructure:"jwt_expiration_hours" validate:"required,min=1,max=168"`
-	RefreshTokenExpDays int            `mapstructure:"refresh_token_exp_days" validate:"required,min=1,max=365"`
-	JWTIssuer           string         `mapstructure:"jwt_issuer" validate:"required"`
-	BcryptCost          int            `mapstructure:"bcrypt_cost" validate:"required,min=4,max=31"`
-	SessionTimeout      time.Duration  `mapstructure:"session_timeout" validate:"min=5m"`
-	MaxLoginAttempts    int            `mapstructure:"max_login_attempts" validate:"min=3,max=10"`
-	LockoutDuration     time.Duration  `mapstructure:"lockout_duration" validate:"min=5m"`
-	PasswordPolicy      PasswordPolicy `mapstructure:"password_policy"`
+	JWTSecret           string              `mapstructure:"jwt_secret" validate:"required,min=32"`
+	JWTKeyID            string              `mapstructure:"jwt_key_id" validate:"max=64"`
+	JWTPreviousKeys     []JWTKey            `mapstructure:"jwt_previous_keys" validate:"dive"`
+	JWTExpirationHours  int                 `mapstructure:"jwt_expiration_hours" validate:"required,min=1,max=168"`
+	RefreshTokenExpDays int                 `mapstructure:"refresh_token_exp_days" validate:"required,min=1,max=365"`
+	JWTIssuer           string              `mapstructure:"jwt_issuer" validate:"required"`
+	BcryptCost          int                 `mapstructure:"bcrypt_cost" validate:"required,min=4,max=31"`
+	SessionTimeout      time.Duration       `mapstructure:"session_timeout" validate:"min=5m"`
+	MaxLoginAttempts    int                 `mapstructure:"max_login_attempts" validate:"min=3,max=10"`
+	LockoutDuration     time.Duration       `mapstructure:"lockout_duration" validate:"min=5m"`
+	PasswordPolicy      PasswordPolicy      `mapstructure:"password_policy"`
+	PasswordReset       PasswordResetConfig `mapstructure:"password_reset"`
+	RequestNonce        RequestNonceConfig  `mapstructure:"request_nonce"`
+	Impersonation       ImpersonationConfig `mapstructure:"impersonation"`
+}
+
+// ImpersonationConfig 管理员代入用户配置
+type ImpersonationConfig struct {
+	TokenTTL time.Duration `mapstructure:"token_ttl" validate:"min=1m,max=1h"` // 代入令牌有效期，不可刷新
+}
+
+// RequestNonceConfig 管理员写操作防重放配置
+type RequestNonceConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Secret   string        `mapstructure:"secret"`
+	MaxAge   time.Duration `mapstructure:"max_age" validate:"min=1m,max=1h"`
+	Required bool          `mapstructure:"required"` // 为 false 时只校验携带了 nonce 的请求
+}
+
+// JWTKey 已轮换的 JWT 密钥，在其签发的令牌过期前继续用于验证
+type JWTKey struct {
+	ID     string `mapstructure:"id" validate:"required,max=64"`
+	Secret string `mapstructure:"secret" validate:"required,min=32"`
+}
+
+// PasswordResetConfig 密码重置配置
+type PasswordResetConfig struct {
+	TokenTTL time.Duration `mapstructure:"token_ttl" validate:"min=5m,max=24h"`
+	ResetURL string        `mapstructure:"reset_url"`
 }
 
 // PasswordPolicy 密码策略
@@ -176,23 +367,42 @@ type LogConfig struct {
 	ServiceName   string        `mapstructure:"service_name"`
 	Version       string        `mapstructure:"version"`
 	SlowThreshold time.Duration `mapstructure:"slow_threshold"`
+	// SamplingWindow 相同 warn 日志的合并窗口，窗口内只输出首条，0 表示不采样
+	SamplingWindow time.Duration `mapstructure:"sampling_window" validate:"min=0,max=1h"`
 }
 
 
 
 // FeatureConfig 功能开关配置
 type FeatureConfig struct {
-	EnableSwagger          bool            `mapstructure:"enable_swagger"`
-	EnablePprof            bool            `mapstructure:"enable_pprof"`
-	EnableMetrics          bool            `mapstructure:"enable_metrics"`
-	EnableCORS             bool            `mapstructure:"enable_cors"`
-	EnableRateLimit        bool            `mapstructure:"enable_rate_limit"`
-	EnableHealthCheck      bool            `mapstructure:"enable_health_check"`
-	EnableGracefulShutdown bool            `mapstructure:"enable_graceful_shutdown"`
-	CacheLeaderboard       bool            `mapstructure:"cache_leaderboard"`
-	CacheMatchData         bool            `mapstructure:"cache_match_data"`
-	RateLimitConfig        RateLimitConfig `mapstructure:"rate_limit"`
-	CORSConfig             CORSConfig      `mapstructure:"cors"`
+	EnableSwagger          bool               `mapstructure:"enable_swagger"`
+	EnablePprof            bool               `mapstructure:"enable_pprof"`
+	EnableMetrics          bool               `mapstructure:"enable_metrics"`
+	EnableCORS             bool               `mapstructure:"enable_cors"`
+	EnableRateLimit        bool               `mapstructure:"enable_rate_limit"`
+	EnableHealthCheck      bool               `mapstructure:"enable_health_check"`
+	EnableGracefulShutdown bool               `mapstructure:"enable_graceful_shutdown"`
+	CacheLeaderboard       bool               `mapstructure:"cache_leaderboard"`
+	CacheMatchData         bool               `mapstructure:"cache_match_data"`
+	BlindPrediction        bool               `mapstructure:"blind_prediction"`
+	ScorePrecision         int                `mapstructure:"score_precision" validate:"min=0,max=6"`  // 非整数分值返回的小数位数
+	PredictionLockBefore   time.Duration      `mapstructure:"prediction_lock_before" validate:"min=0"` // 比赛开始前多久锁定预测，可被单场锁定时间覆盖
+	DailyPredictionLimit   int                `mapstructure:"daily_prediction_limit" validate:"min=0"` // 每个用户每天最多创建的预测数，0 表示不限制
+	RateLimitConfig        RateLimitConfig    `mapstructure:"rate_limit"`
+	CORSConfig             CORSConfig         `mapstructure:"cors"`
+	QueryCounter           QueryCounterConfig `mapstructure:"query_counter"`
+	Flags                  FeatureFlagsConfig `mapstructure:"flags"`
+}
+
+// FeatureFlagsConfig 按用户灰度的功能开关，灰度配置保存在 Redis 中
+type FeatureFlagsConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" validate:"min=1s"` // 各实例从 Redis 加载灰度配置的间隔
+}
+
+// QueryCounterConfig 单请求数据库语句数检查（排查 N+1 查询），生产环境强制关闭
+type QueryCounterConfig struct {
+	Enabled   bool `mapstructure:"enabled"`
+	Threshold int  `mapstructure:"threshold" validate:"min=1"` // 单个请求的语句数超过该值时输出告警
 }
 
 // RateLimitConfig 限流配置
@@ -217,12 +427,59 @@ type CORSConfig struct {
 type CacheConfig struct {
 	Leaderboard LeaderboardCacheConfig `mapstructure:"leaderboard"`
 	Monitoring  CacheMonitoringConfig  `mapstructure:"monitoring"`
+	MultiLevel  MultiLevelCacheConfig  `mapstructure:"multi_level"`
+	Stale       StaleCacheConfig       `mapstructure:"stale"`
+	Reconcile   StatsReconcileConfig   `mapstructure:"reconcile"`
+	Rollup      StatsRollupConfig      `mapstructure:"rollup"`
+	Warmup      CacheWarmupConfig      `mapstructure:"warmup"`
+}
+
+// CacheWarmupConfig 排行榜缓存预热配置
+type CacheWarmupConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Interval       time.Duration `mapstructure:"interval" validate:"min=1m,max=1h"`
+	Budget         int `mapstructure:"budget" validate:"min=0"`                 // 每轮最多刷新的赛事数，0 表示全部
+	MaxStaleCycles int `mapstructure:"max_stale_cycles" validate:"min=1,max=48"` // 冷门赛事最多连续跳过的轮数
+	Concurrency    int `mapstructure:"concurrency" validate:"min=1,max=16"`      // 同时刷新的赛事数上限
+}
+
+// StatsReconcileConfig Redis 统计计数器与数据库的定期核对配置
+type StatsReconcileConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Interval   time.Duration `mapstructure:"interval" validate:"min=1m,max=24h"`
+	SampleSize int           `mapstructure:"sample_size" validate:"min=0"`
+}
+
+// StatsRollupConfig 每日预测统计汇总为月度数据与预计算序列的配置
+type StatsRollupConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Interval     time.Duration `mapstructure:"interval" validate:"min=1h,max=24h"`
+	LookbackDays int           `mapstructure:"lookback_days" validate:"min=1,max=7"` // 每日计数只保留 7 天
+}
+
+// StaleCacheConfig Redis 故障时的降级响应缓存配置
+type StaleCacheConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	MaxEntries int           `mapstructure:"max_entries" validate:"min=1"`
+	MaxBytes   int64         `mapstructure:"max_bytes" validate:"min=1024"`
+	MaxAge     time.Duration `mapstructure:"max_age" validate:"min=1s,max=1h"`
+}
+
+// MultiLevelCacheConfig 多级缓存配置 (内存 L1 + Redis L2)
+//
+// 失效只清除本实例的 L1，多实例部署时其他实例最长 l1_ttl 内仍可能返回旧值，因此默认关闭。
+type MultiLevelCacheConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	L1TTL        time.Duration `mapstructure:"l1_ttl" validate:"min=1s,max=10m"`
+	L1MaxEntries int           `mapstructure:"l1_max_entries" validate:"min=1"`
+	L1MaxBytes   int64         `mapstructure:"l1_max_bytes" validate:"min=1024"`
 }
 
 // LeaderboardCacheConfig 排行榜缓存配置
 type LeaderboardCacheConfig struct {
 	CacheExpiration time.Duration `mapstructure:"cache_expiration" validate:"min=1m,max=1h"`
 	RefreshInterval time.Duration `mapstructure:"refresh_interval" validate:"min=30s,max=30m"`
+	FreshFor        time.Duration `mapstructure:"fresh_for" validate:"min=0,ltfield=CacheExpiration"` // 缓存超过该时长后读取时仍直接返回，并在后台刷新，0 表示不提前刷新
 }
 
 // CacheMonitoringConfig 缓存监控配置
@@ -233,9 +490,18 @@ type CacheMonitoringConfig struct {
 
 // ExternalConfig 外部服务配置
 type ExternalConfig struct {
-	Email       EmailConfig       `mapstructure:"email"`
-	FileStorage FileStorageConfig `mapstructure:"file_storage"`
-	Monitoring  MonitoringConfig  `mapstructure:"monitoring"`
+	Email          EmailConfig          `mapstructure:"email"`
+	FileStorage    FileStorageConfig    `mapstructure:"file_storage"`
+	Monitoring     MonitoringConfig     `mapstructure:"monitoring"`
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+// CircuitBreakerConfig 邮件与对象存储调用的熔断配置
+type CircuitBreakerConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	FailureThreshold int           `mapstructure:"failure_threshold" validate:"min=1"` // 连续失败多少次后熔断
+	Cooldown         time.Duration `mapstructure:"cooldown" validate:"min=1s"`         // 熔断后多久放行一次试探调用
+	Timeout          time.Duration `mapstructure:"timeout" validate:"min=0"`           // 单次调用超时，超时计为失败，0 表示不限制
 }
 
 // EmailConfig 邮件配置
@@ -257,6 +523,8 @@ type FileStorageConfig struct {
 	S3Config   S3Config `mapstructure:"s3"`
 	MaxSize    int64    `mapstructure:"max_size" validate:"min=1024"`
 	AllowedExt []string `mapstructure:"allowed_ext"`
+	// URLSigningKey 本地存储下载令牌的签名密钥，为空时由 auth.jwt_secret 经 HKDF 派生
+	URLSigningKey string `mapstructure:"url_signing_key"`
 }
 
 // S3Config S3 配置
@@ -277,6 +545,15 @@ type MonitoringConfig struct {
 	SampleRate    float64       `mapstructure:"sample_rate" validate:"min=0,max=1"`
 	HealthCheck   HealthConfig  `mapstructure:"health_check"`
 	Prometheus    PrometheusConfig `mapstructure:"prometheus"`
+	SLO           SLOConfig        `mapstructure:"slo"`
+}
+
+// SLOConfig 接口延迟 SLO 配置
+type SLOConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Target     time.Duration `mapstructure:"target" validate:"min=1ms"`
+	Window     time.Duration `mapstructure:"window" validate:"min=1m,max=24h"`
+	MaxSamples int           `mapstructure:"max_samples" validate:"min=100"`
 }
 
 // HealthConfig 健康检查配置
@@ -285,8 +562,9 @@ type HealthConfig struct {
 	Timeout         time.Duration `mapstructure:"timeout"`
 	MaxMemoryMB     uint64        `mapstructure:"max_memory_mb"`
 	CheckInterval   time.Duration `mapstructure:"check_interval"`
-	StartupRetries  int           `mapstructure:"startup_retries"`
-	StartupInterval time.Duration `mapstructure:"startup_interval"`
+	StartupRetries  int           `mapstructure:"startup_retries"`  // 启动探针与初始连接数据库/Redis 的最大尝试次数
+	StartupInterval time.Duration `mapstructure:"startup_interval"` // 首次重试间隔，初始连接按指数退避
+	CacheTTL        time.Duration `mapstructure:"cache_ttl"`        // 详细健康检查结果的缓存时长，0 表示不缓存；/livez 与 /readyz 不使用缓存
 }
 
 // PrometheusConfig Prometheus配置
@@ -332,12 +610,16 @@ func Load(opts ...*LoadOptions) (*Config, error) {
 
 // LoadFromFile 从指定文件加载配置
 func LoadFromFile(filePath string) (*Config, error) {
+	return loadWithOptions(fileLoadOptions(filePath))
+}
+
+// fileLoadOptions 指定配置文件的加载选项
+func fileLoadOptions(filePath string) *LoadOptions {
 	opts := DefaultLoadOptions()
 	opts.ConfigPath = filepath.Dir(filePath)
 	opts.ConfigName = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
 	opts.ConfigType = strings.TrimPrefix(filepath.Ext(filePath), ".")
-
-	return loadWithOptions(opts)
+	return opts
 }
 
 // LoadForEnvironment 为特定环境加载配置
@@ -379,6 +661,7 @@ func loadWithOptions(opts *LoadOptions) (*Config, error) {
 	v.SetEnvPrefix(opts.EnvPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	v.AutomaticEnv()
+	bindConfigEnv(v)
 
 	// 设置默认值
 	setDefaults(v)
@@ -395,6 +678,9 @@ func loadWithOptions(opts *LoadOptions) (*Config, error) {
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	config.sources = resolveSources(v, opts.EnvPrefix)
+	config.envPrefix = opts.EnvPrefix
+	config.configFile = v.ConfigFileUsed()
 
 	// 后处理配置
 	if err := postProcessConfig(&config); err != nil {
@@ -411,6 +697,15 @@ func loadWithOptions(opts *LoadOptions) (*Config, error) {
 	return &config, nil
 }
 
+// 各环境默认使用的 MySQL 数据库名与 Redis 数据库索引
+const (
+	defaultDatabaseName      = "prediction_system"
+	defaultDevDatabaseName   = "prediction_system_dev"
+	defaultTestDatabaseName  = "prediction_system_test"
+	defaultRedisDatabase     = 0
+	defaultTestRedisDatabase = 1
+)
+
 // setDefaults 设置默认配置值
 func setDefaults(v *viper.Viper) {
 	env := GetEnvironment()
@@ -421,12 +716,32 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.read_timeout", "30s")
 	v.SetDefault("server.write_timeout", "30s")
 	v.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.shutdown_timeout", "30s")
+	v.SetDefault("server.request_timeout", "15s")
 	if env.IsDevelopment() {
 		v.SetDefault("server.mode", "debug")
 	} else {
 		v.SetDefault("server.mode", "release")
 	}
 	v.SetDefault("server.tls.enabled", false)
+	v.SetDefault("server.max_header_bytes", 1<<20) // 与 net/http 默认值一致
+	v.SetDefault("server.http2.enabled", false)
+	v.SetDefault("server.http2.max_concurrent_streams", 250)
+	v.SetDefault("server.keep_alive.enabled", true)
+	v.SetDefault("server.keep_alive.period", "15s")
+	v.SetDefault("server.idempotency.enabled", false)
+	v.SetDefault("server.idempotency.ttl", "24h")
+	v.SetDefault("server.idempotency.lock_ttl", "1m")
+	v.SetDefault("server.response_metadata", !env.IsProduction()) // 生产环境默认关闭以保持响应精简
+	v.SetDefault("server.json_naming", "preserve")                // 旧客户端依赖 camelCase 字段，默认保持原样
+	v.SetDefault("server.body_limit.max_bytes", 1<<20) // 1MB
+	v.SetDefault("server.concurrency.max_in_flight", 0) // 0 表示不限制
+	v.SetDefault("server.concurrency.queue_timeout", "100ms")
+	v.SetDefault("server.concurrency.retry_after", "1s")
+	v.SetDefault("server.stream_connections.max_per_window", 30)
+	v.SetDefault("server.stream_connections.window", "1m")
+	v.SetDefault("server.pagination.max_page_size", 100)
+	v.SetDefault("server.pagination.reject_oversized", false)
 
 	// 数据库默认配置
 	v.SetDefault("database.host", "localhost")
@@ -434,11 +749,11 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.username", "root")
 	v.SetDefault("database.password", "")
 	if env.IsTesting() {
-		v.SetDefault("database.database", "prediction_system_test")
+		v.SetDefault("database.database", defaultTestDatabaseName)
 	} else if env.IsDevelopment() {
-		v.SetDefault("database.database", "prediction_system_dev")
+		v.SetDefault("database.database", defaultDevDatabaseName)
 	} else {
-		v.SetDefault("database.database", "prediction_system")
+		v.SetDefault("database.database", defaultDatabaseName)
 	}
 	v.SetDefault("database.charset", "utf8mb4")
 	v.SetDefault("database.collation", "utf8mb4_unicode_ci")
@@ -446,18 +761,22 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_idle_conns", 10)
 	v.SetDefault("database.conn_max_lifetime", "1h")
 	v.SetDefault("database.conn_max_idle_time", "30m")
+	v.SetDefault("database.query_timeout", 0)
+	v.SetDefault("database.min_query_budget", "20ms")
 	v.SetDefault("database.ssl.mode", "disable")
 	v.SetDefault("database.migration.enabled", true)
 	v.SetDefault("database.migration.auto_create", env.IsDevelopment())
+	v.SetDefault("database.migration.require_down_migrations", false)
+	v.SetDefault("database.migration.skip_auto_migrate", false)
 
 	// Redis 默认配置
 	v.SetDefault("redis.host", "localhost")
 	v.SetDefault("redis.port", 6379)
 	v.SetDefault("redis.password", "")
 	if env.IsTesting() {
-		v.SetDefault("redis.database", 1)
+		v.SetDefault("redis.database", defaultTestRedisDatabase)
 	} else {
-		v.SetDefault("redis.database", 0)
+		v.SetDefault("redis.database", defaultRedisDatabase)
 	}
 	v.SetDefault("redis.pool_size", 10)
 	v.SetDefault("redis.min_idle_conns", 5)
@@ -469,6 +788,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("redis.idle_timeout", "5m")
 	v.SetDefault("redis.max_conn_age", "30m")
 	v.SetDefault("redis.idle_check_freq", "1m")
+	v.SetDefault("redis.operation_timeout", "3s")
+	v.SetDefault("redis.min_operation_budget", "5ms")
 	v.SetDefault("redis.cluster.enabled", false)
 
 	// 认证默认配置
@@ -489,6 +810,12 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("auth.password_policy.require_lower", true)
 	v.SetDefault("auth.password_policy.require_number", true)
 	v.SetDefault("auth.password_policy.require_special", false)
+	v.SetDefault("auth.password_reset.token_ttl", "30m")
+	v.SetDefault("auth.password_reset.reset_url", "http://localhost:3000/reset-password")
+	v.SetDefault("auth.request_nonce.enabled", false)
+	v.SetDefault("auth.request_nonce.max_age", "5m")
+	v.SetDefault("auth.request_nonce.required", false)
+	v.SetDefault("auth.impersonation.token_ttl", "15m")
 
 	// 日志默认配置
 	if env.IsDevelopment() {
@@ -508,6 +835,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("log.service_name", "prediction-system")
 	v.SetDefault("log.version", "1.0.0")
 	v.SetDefault("log.slow_threshold", "1s")
+	v.SetDefault("log.sampling_window", "1m")
 
 
 
@@ -521,6 +849,13 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("features.enable_graceful_shutdown", true)
 	v.SetDefault("features.cache_leaderboard", true)
 	v.SetDefault("features.cache_match_data", true)
+	v.SetDefault("features.blind_prediction", false)
+	v.SetDefault("features.score_precision", 2)
+	v.SetDefault("features.prediction_lock_before", "0s")
+	v.SetDefault("features.daily_prediction_limit", 0)
+	v.SetDefault("features.query_counter.enabled", env.IsDevelopment())
+	v.SetDefault("features.query_counter.threshold", 20)
+	v.SetDefault("features.flags.refresh_interval", "30s")
 
 	// 限流配置
 	v.SetDefault("features.rate_limit.requests_per_second", 100)
@@ -529,8 +864,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("features.rate_limit.cleanup_interval", "5m")
 
 	// CORS 配置
-	v.SetDefault("features.cors.allowed_origins", []string{"*"})
-	v.SetDefault("features.cors.allowed_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	v.SetDefault("features.cors.allowed_origins", defaultCORSOrigins(env))
+	v.SetDefault("features.cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
 	v.SetDefault("features.cors.allowed_headers", []string{"*"})
 	v.SetDefault("features.cors.allow_credentials", true)
 	v.SetDefault("features.cors.max_age", "12h")
@@ -538,8 +873,61 @@ func setDefaults(v *viper.Viper) {
 	// 缓存默认配置
 	v.SetDefault("cache.leaderboard.cache_expiration", "5m")
 	v.SetDefault("cache.leaderboard.refresh_interval", "2m")
+	v.SetDefault("cache.leaderboard.fresh_for", "1m")
 	v.SetDefault("cache.monitoring.monitor_interval", "1m")
 	v.SetDefault("cache.monitoring.hit_rate_threshold", 90.0)
+	v.SetDefault("cache.multi_level.enabled", false)
+	v.SetDefault("cache.multi_level.l1_ttl", "30s")
+	v.SetDefault("cache.multi_level.l1_max_entries", 10000)
+	v.SetDefault("cache.multi_level.l1_max_bytes", 64<<20)
+	v.SetDefault("cache.stale.enabled", true)
+	v.SetDefault("cache.stale.max_entries", 1000)
+	v.SetDefault("cache.stale.max_bytes", 16<<20)
+	v.SetDefault("cache.stale.max_age", "10m")
+	v.SetDefault("cache.reconcile.enabled", true)
+	v.SetDefault("cache.reconcile.interval", "1h")
+	v.SetDefault("cache.reconcile.sample_size", 500)
+	v.SetDefault("cache.rollup.enabled", true)
+	v.SetDefault("cache.rollup.interval", "24h")
+	v.SetDefault("cache.rollup.lookback_days", 7)
+	v.SetDefault("cache.warmup.enabled", true)
+	v.SetDefault("cache.warmup.interval", "5m")
+	v.SetDefault("cache.warmup.budget", 2)
+	v.SetDefault("cache.warmup.max_stale_cycles", 6)
+	v.SetDefault("cache.warmup.concurrency", 2)
+
+	// worker 默认配置
+	v.SetDefault("worker.shutdown_timeout", "10s")
+	v.SetDefault("worker.outbox.enabled", true)
+	v.SetDefault("worker.outbox.interval", "5s")
+	v.SetDefault("worker.outbox.batch_size", 100)
+	v.SetDefault("worker.outbox.retention", "168h")
+	v.SetDefault("worker.retention.enabled", false)
+	v.SetDefault("worker.retention.retention", "8760h")
+	v.SetDefault("worker.retention.interval", "24h")
+	v.SetDefault("worker.retention.batch_size", 100)
+	v.SetDefault("worker.retention.archive_prefix", "archives/retention")
+
+	// 行为统计默认配置
+	v.SetDefault("analytics.page_views.tracked_paths", []string{
+		"/", "/matches", "/matches/:id", "/upcoming-matches", "/leaderboard",
+		"/prediction-history", "/prediction-rules", "/profile",
+	})
+	v.SetDefault("analytics.stats_buffer.enabled", true)
+	v.SetDefault("analytics.stats_buffer.size", 10000)
+	v.SetDefault("analytics.stats_buffer.batch_size", 500)
+	v.SetDefault("analytics.stats_buffer.flush_interval", "1s")
+
+	// WebSocket 默认配置
+	v.SetDefault("websocket.ping_period", "54s")
+	v.SetDefault("websocket.pong_wait", "60s")
+	v.SetDefault("websocket.write_wait", "10s")
+	v.SetDefault("websocket.max_message_size", 512)
+	v.SetDefault("websocket.read_buffer_size", 1024)
+	v.SetDefault("websocket.max_connections", 1000)
+
+	// 徽章默认配置
+	v.SetDefault("badges.cache_ttl", "10m")
 
 	// 外部服务默认配置
 	v.SetDefault("external.email.enabled", false)
@@ -547,6 +935,10 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("external.email.host", "localhost")
 	v.SetDefault("external.email.port", 587)
 	v.SetDefault("external.email.from", "noreply@example.com")
+	v.SetDefault("external.circuit_breaker.enabled", true)
+	v.SetDefault("external.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("external.circuit_breaker.cooldown", "30s")
+	v.SetDefault("external.circuit_breaker.timeout", "10s")
 	v.SetDefault("external.file_storage.provider", "local")
 	v.SetDefault("external.file_storage.local_path", "./uploads")
 	v.SetDefault("external.file_storage.max_size", 10*1024*1024) // 10MB
@@ -561,11 +953,16 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("external.monitoring.health_check.check_interval", "30s")
 	v.SetDefault("external.monitoring.health_check.startup_retries", 10)
 	v.SetDefault("external.monitoring.health_check.startup_interval", "5s")
+	v.SetDefault("external.monitoring.health_check.cache_ttl", "3s")
 	v.SetDefault("external.monitoring.prometheus.enabled", true)
 	v.SetDefault("external.monitoring.prometheus.path", "/metrics")
 	v.SetDefault("external.monitoring.prometheus.skip_paths", []string{"/metrics", "/health", "/favicon.ico"})
 	v.SetDefault("external.monitoring.prometheus.normalize_path", true)
 	v.SetDefault("external.monitoring.prometheus.max_path_labels", 100)
+	v.SetDefault("external.monitoring.slo.enabled", true)
+	v.SetDefault("external.monitoring.slo.target", "100ms")
+	v.SetDefault("external.monitoring.slo.window", "5m")
+	v.SetDefault("external.monitoring.slo.max_samples", 10000)
 }
 
 // generateDefaultSecret 生成默认密钥（仅用于开发环境）
@@ -575,16 +972,21 @@ func generateDefaultSecret() string {
 		// 生产环境必须设置环境变量
 		return ""
 	}
-	return "dev-jwt-secret-key-change-this-in-production"
+	return devJWTSecret
 }
 
 // postProcessConfig 配置后处理
 func postProcessConfig(config *Config) error {
 	env := GetEnvironment()
 
+	// 解密加密的敏感配置，需在安全检查与验证之前完成
+	if err := decryptSecrets(config); err != nil {
+		return err
+	}
+
 	// 生产环境安全检查
 	if env.IsProduction() {
-		if config.Auth.JWTSecret == "" || config.Auth.JWTSecret == "dev-jwt-secret-key-change-this-in-production" {
+		if config.Auth.JWTSecret == "" || config.Auth.JWTSecret == devJWTSecret {
 			return fmt.Errorf("JWT secret must be set in production environment")
 		}
 
@@ -599,8 +1001,13 @@ func postProcessConfig(config *Config) error {
 		if config.Features.EnableSwagger {
 			config.Features.EnableSwagger = false
 		}
+
+		config.Features.QueryCounter.Enabled = false
 	}
 
+	// CORS 来源环境变量覆盖与开发环境安全默认值
+	normalizeCORS(config, env)
+
 	// 设置依赖关系
 	if config.Features.EnableRateLimit && config.Features.RateLimitConfig.RequestsPerSecond == 0 {
 		config.Features.RateLimitConfig.RequestsPerSecond = 100
@@ -679,6 +1086,70 @@ func validateFilePath(fl validator.FieldLevel) bool {
 	return err == nil
 }
 
+// validateJWTKeys 验证 JWT 密钥集：旧密钥需要当前密钥ID，且ID不能重复
+func validateJWTKeys(auth *AuthConfig) error {
+	if len(auth.JWTPreviousKeys) == 0 {
+		return nil
+	}
+	if auth.JWTKeyID == "" {
+		return fmt.Errorf("jwt_key_id is required when jwt_previous_keys are configured")
+	}
+
+	seen := map[string]bool{auth.JWTKeyID: true}
+	for _, key := range auth.JWTPreviousKeys {
+		if seen[key.ID] {
+			return fmt.Errorf("duplicate JWT key id: %s", key.ID)
+		}
+		seen[key.ID] = true
+	}
+	return nil
+}
+
+// devJWTSecret 开发环境默认 JWT 密钥
+const devJWTSecret = "dev-jwt-secret-key-change-this-in-production"
+
+// minStrictJWTSecretLength 生产与预发布环境 JWT 密钥的最小长度
+const minStrictJWTSecretLength = 32
+
+// ValidateJWTSecretPolicy 按环境检查 JWT 密钥策略
+//
+// 所有环境都必须设置密钥；生产与预发布环境的密钥至少 32 个字符且不能使用开发默认值。
+func ValidateJWTSecretPolicy(env Environment, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("JWT secret is not set")
+	}
+	if env != EnvProduction && env != EnvStaging {
+		return nil
+	}
+	if secret == devJWTSecret {
+		return fmt.Errorf("JWT secret uses the development default in %s", env)
+	}
+	if len(secret) < minStrictJWTSecretLength {
+		return fmt.Errorf("JWT secret must be at least %d characters in %s", minStrictJWTSecretLength, env)
+	}
+	return nil
+}
+
+// validateTestIsolation 测试环境不得使用其他环境默认的 MySQL 数据库与 Redis 数据库，
+// 防止测试中的 FlushDB 或清表误删开发数据
+func validateTestIsolation(env Environment, config *Config) error {
+	if !env.IsTesting() {
+		return nil
+	}
+
+	if config.Redis.Database == defaultRedisDatabase {
+		return fmt.Errorf("redis database %d is shared with non-test environments, use a dedicated index (default %d) in testing",
+			config.Redis.Database, defaultTestRedisDatabase)
+	}
+
+	switch config.Database.Database {
+	case defaultDatabaseName, defaultDevDatabaseName:
+		return fmt.Errorf("database %q belongs to a non-test environment, use a dedicated database (default %q) in testing",
+			config.Database.Database, defaultTestDatabaseName)
+	}
+	return nil
+}
+
 // validateBusinessLogic 业务逻辑验证
 func validateBusinessLogic(config *Config) error {
 	env := GetEnvironment()
@@ -698,6 +1169,28 @@ func validateBusinessLogic(config *Config) error {
 		}
 	}
 
+	// JWT 密钥集验证
+	if err := validateJWTKeys(&config.Auth); err != nil {
+		return err
+	}
+
+	// 防重放签名密钥验证
+	if config.Auth.RequestNonce.Enabled && len(config.Auth.RequestNonce.Secret) < 32 {
+		return fmt.Errorf("request nonce secret must be at least 32 characters when enabled")
+	}
+
+	// CORS 配置验证
+	if config.Features.EnableCORS {
+		if err := ValidateCORSPolicy(env, config.Features.CORSConfig); err != nil {
+			return err
+		}
+	}
+
+	// 测试环境数据隔离验证
+	if err := validateTestIsolation(env, config); err != nil {
+		return err
+	}
+
 	// 数据库配置验证
 	if config.Database.MaxIdleConns > config.Database.MaxOpenConns {
 		return fmt.Errorf("max_idle_conns (%d) cannot be greater than max_open_conns (%d)",
@@ -710,11 +1203,11 @@ func validateBusinessLogic(config *Config) error {
 			config.Redis.MinIdleConns, config.Redis.PoolSize)
 	}
 
-	// WebSocket 配置验证 (暂时注释掉，因为WebSocket配置未定义)
-	// if config.WebSocket.PingPeriod >= config.WebSocket.PongWait {
-	// 	return fmt.Errorf("websocket ping_period (%v) must be less than pong_wait (%v)",
-	// 		config.WebSocket.PingPeriod, config.WebSocket.PongWait)
-	// }
+	// WebSocket 配置验证
+	if config.WebSocket.PingPeriod >= config.WebSocket.PongWait {
+		return fmt.Errorf("websocket ping_period (%v) must be less than pong_wait (%v)",
+			config.WebSocket.PingPeriod, config.WebSocket.PongWait)
+	}
 
 	// 限流配置验证
 	if config.Features.EnableRateLimit {
@@ -835,17 +1328,23 @@ func containsSpecial(s string) bool {
 	return false
 }
 
+// DefaultConfigHistorySize ConfigManager 默认保留的历史配置数量
+const DefaultConfigHistorySize = 10
+
 // ConfigManager 配置管理器
 type ConfigManager struct {
-	config   *Config
-	watchers []func(*Config)
+	config      *Config
+	watchers    []func(*Config)
+	history     []*Config // 曾经生效的配置，按生效顺序排列，最后一个是上一份配置
+	historySize int
 }
 
 // NewConfigManager 创建配置管理器
 func NewConfigManager(config *Config) *ConfigManager {
 	return &ConfigManager{
-		config:   config,
-		watchers: make([]func(*Config), 0),
+		config:      config,
+		watchers:    make([]func(*Config), 0),
+		historySize: DefaultConfigHistorySize,
 	}
 }
 
@@ -860,13 +1359,8 @@ func (cm *ConfigManager) UpdateConfig(newConfig *Config) error {
 		return err
 	}
 
-	cm.config = newConfig
-
-	// 通知观察者
-	for _, watcher := range cm.watchers {
-		watcher(newConfig)
-	}
-
+	cm.recordHistory()
+	cm.apply(newConfig)
 	return nil
 }
 
@@ -875,14 +1369,169 @@ func (cm *ConfigManager) AddWatcher(watcher func(*Config)) {
 	cm.watchers = append(cm.watchers, watcher)
 }
 
+// SetHistorySize 设置保留的历史配置数量，小于等于 0 时不保留历史
+func (cm *ConfigManager) SetHistorySize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	cm.historySize = size
+	cm.trimHistory()
+}
+
+// History 返回保留的历史配置，下标 0 为上一份配置，与 RollbackTo 的下标一致
+func (cm *ConfigManager) History() []*Config {
+	history := make([]*Config, len(cm.history))
+	for i, cfg := range cm.history {
+		history[len(cm.history)-1-i] = cfg
+	}
+	return history
+}
+
+// RollbackToPrevious 回滚到上一份生效的配置
+func (cm *ConfigManager) RollbackToPrevious() error {
+	return cm.RollbackTo(0)
+}
+
+// RollbackTo 回滚到 History()[index] 对应的配置
+//
+// 目标配置按 ApplyChangedSections 的规则重新校验，通过后生效并通知观察者。
+// 回滚后目标配置及其之后的历史记录被移除，当前配置不进入历史，
+// 因此连续调用 RollbackToPrevious 会逐步回退到更早的配置。
+func (cm *ConfigManager) RollbackTo(index int) error {
+	if index < 0 || index >= len(cm.history) {
+		return fmt.Errorf("config history index %d out of range, %d configs available", index, len(cm.history))
+	}
+
+	pos := len(cm.history) - 1 - index
+	target, _, err := cm.mergeChangedSections(cm.history[pos])
+	if err != nil {
+		return fmt.Errorf("rollback to config history %d: %w", index, err)
+	}
+	if target == nil {
+		target = cm.history[pos]
+	}
+
+	cm.history = cm.history[:pos]
+	cm.apply(target)
+	return nil
+}
+
 // ReloadFromFile 从文件重新加载配置
+//
+// 只校验与当前配置相比发生变化的配置段，未变化配置段中已有的无效值不会阻止重新加载，
+// 详见 ApplyChangedSections
 func (cm *ConfigManager) ReloadFromFile(filePath string) error {
-	newConfig, err := LoadFromFile(filePath)
+	opts := fileLoadOptions(filePath)
+	opts.SkipValidate = true
+	newConfig, err := loadWithOptions(opts)
 	if err != nil {
 		return err
 	}
 
-	return cm.UpdateConfig(newConfig)
+	_, err = cm.ApplyChangedSections(newConfig)
+	return err
+}
+
+// ApplyChangedSections 校验并应用 newConfig 中发生变化的配置段，返回生效的配置段
+//
+// 配置段为 Config 的顶层字段（如 Log、Features）。变化的配置段全部通过校验后才一起生效，
+// 任一配置段无效时返回错误并保留原配置；其余配置段保持当前值。跨配置段的业务规则
+// 在合并后的配置上检查，只拦截本次变化引入的错误。
+func (cm *ConfigManager) ApplyChangedSections(newConfig *Config) ([]string, error) {
+	merged, sections, err := cm.mergeChangedSections(newConfig)
+	if err != nil || merged == nil {
+		return nil, err
+	}
+
+	cm.recordHistory()
+	cm.apply(merged)
+	return sections, nil
+}
+
+// mergeChangedSections 校验 newConfig 中发生变化的配置段并合并到当前配置，没有变化时返回 nil
+func (cm *ConfigManager) mergeChangedSections(newConfig *Config) (*Config, []string, error) {
+	sections := changedSections(CompareConfigs(cm.config, newConfig))
+	if len(sections) == 0 {
+		return nil, nil, nil
+	}
+
+	// StructPartial 只校验列出的字段，需要展开到配置段内的每个字段
+	var fields []string
+	for _, section := range sections {
+		collectFieldPaths(reflect.ValueOf(newConfig).Elem().FieldByName(section), section, &fields)
+	}
+	if err := NewConfigValidator().ValidatePartial(newConfig, fields...); err != nil {
+		return nil, nil, fmt.Errorf("config validation failed for %s: %w", strings.Join(sections, ", "), err)
+	}
+
+	merged := *cm.config
+	src := reflect.ValueOf(newConfig).Elem()
+	dst := reflect.ValueOf(&merged).Elem()
+	for _, section := range sections {
+		dst.FieldByName(section).Set(src.FieldByName(section))
+	}
+
+	if err := validateBusinessLogic(&merged); err != nil {
+		if prev := validateBusinessLogic(cm.config); prev == nil || prev.Error() != err.Error() {
+			return nil, nil, fmt.Errorf("business logic validation failed: %w", err)
+		}
+	}
+
+	return &merged, sections, nil
+}
+
+// recordHistory 把当前配置记入历史，超出容量时丢弃最早的配置
+func (cm *ConfigManager) recordHistory() {
+	if cm.config == nil || cm.historySize == 0 {
+		return
+	}
+	cm.history = append(cm.history, cm.config)
+	cm.trimHistory()
+}
+
+func (cm *ConfigManager) trimHistory() {
+	if extra := len(cm.history) - cm.historySize; extra > 0 {
+		cm.history = append(cm.history[:0:0], cm.history[extra:]...)
+	}
+}
+
+// apply 使配置生效并通知观察者
+func (cm *ConfigManager) apply(newConfig *Config) {
+	cm.config = newConfig
+	for _, watcher := range cm.watchers {
+		watcher(newConfig)
+	}
+}
+
+// collectFieldPaths 递归收集结构体内所有导出字段的校验路径，结构体切片按元素展开
+func collectFieldPaths(v reflect.Value, prefix string, paths *[]string) {
+	*paths = append(*paths, prefix)
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{}):
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				collectFieldPaths(v.Field(i), prefix+"."+field.Name, paths)
+			}
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		for i := 0; i < v.Len(); i++ {
+			collectFieldPaths(v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), paths)
+		}
+	}
+}
+
+// changedSections 按出现顺序返回差异所在的顶层配置段
+func changedSections(diffs []ConfigDiff) []string {
+	var sections []string
+	seen := make(map[string]bool)
+	for _, diff := range diffs {
+		section, _, _ := strings.Cut(diff.Field, ".")
+		if !seen[section] {
+			seen[section] = true
+			sections = append(sections, section)
+		}
+	}
+	return sections
 }
 
 // GetConfigSummary 获取配置摘要（用于日志和调试）
@@ -909,6 +1558,7 @@ func GetConfigSummary(config *Config) map[string]interface{} {
 func (c *AuthConfig) GetJWTConfig() map[string]interface{} {
 	return map[string]interface{}{
 		"secret_key":        c.JWTSecret,
+		"key_id":            c.JWTKeyID,
 		"access_token_ttl":  time.Duration(c.JWTExpirationHours) * time.Hour,
 		"refresh_token_ttl": time.Duration(c.RefreshTokenExpDays) * 24 * time.Hour,
 		"issuer":            c.JWTIssuer,
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateConfig_ShutdownTimeoutRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  time.Duration
+		worker  time.Duration
+		wantErr string
+	}{
+		{name: "defaults", server: 30 * time.Second, worker: 10 * time.Second},
+		{name: "server too short", server: 500 * time.Millisecond, worker: 10 * time.Second, wantErr: "Server.ShutdownTimeout"},
+		{name: "server too long", server: 11 * time.Minute, worker: 10 * time.Second, wantErr: "Server.ShutdownTimeout"},
+		{name: "worker unset", server: 30 * time.Second, worker: 0, wantErr: "Worker.ShutdownTimeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+			cfg.Server.ShutdownTimeout = tt.server
+			cfg.Worker.ShutdownTimeout = tt.worker
+
+			err := validateConfig(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateConfig() error = %v, want %s error", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_ShutdownTimeoutDefaults(t *testing.T) {
+	cfg := loadProvenanceConfig(t, "worker:\n  shutdown_timeout: 45s\n")
+
+	if cfg.Server.ShutdownTimeout != 30*time.Second {
+		t.Errorf("server.shutdown_timeout = %s, want 30s", cfg.Server.ShutdownTimeout)
+	}
+	if cfg.Worker.ShutdownTimeout != 45*time.Second {
+		t.Errorf("worker.shutdown_timeout = %s, want 45s", cfg.Worker.ShutdownTimeout)
+	}
+}
+
+func TestValidateTestIsolation(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      Environment
+		database string
+		redisDB  int
+		wantErr  string
+	}{
+		{name: "testing defaults", env: EnvTesting, database: "prediction_system_test", redisDB: 1},
+		{name: "testing custom", env: EnvTesting, database: "ci_prediction", redisDB: 5},
+		{name: "testing shares redis db", env: EnvTesting, database: "prediction_system_test", redisDB: 0, wantErr: "redis database 0"},
+		{name: "testing uses dev database", env: EnvTesting, database: "prediction_system_dev", redisDB: 1, wantErr: `"prediction_system_dev"`},
+		{name: "testing uses production database", env: EnvTesting, database: "prediction_system", redisDB: 1, wantErr: `"prediction_system"`},
+		{name: "development not checked", env: EnvDevelopment, database: "prediction_system_dev", redisDB: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Database: DatabaseConfig{Database: tt.database}, Redis: RedisConfig{Database: tt.redisDB}}
+
+			err := validateTestIsolation(tt.env, cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateTestIsolation() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateTestIsolation() error = %v, want %s error", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_TestingDefaultsAreIsolated(t *testing.T) {
+	t.Setenv("GO_ENV", "testing")
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+
+	if cfg.Database.Database != defaultTestDatabaseName || cfg.Redis.Database != defaultTestRedisDatabase {
+		t.Errorf("testing defaults = %q/%d, want %q/%d", cfg.Database.Database, cfg.Redis.Database, defaultTestDatabaseName, defaultTestRedisDatabase)
+	}
+	if err := validateTestIsolation(EnvTesting, cfg); err != nil {
+		t.Errorf("validateTestIsolation() error = %v, want nil", err)
+	}
+}
+
+func TestLoad_WebSocketDefaults(t *testing.T) {
+	cfg := loadProvenanceConfig(t, "websocket:\n  max_connections: 50\n")
+
+	ws := cfg.WebSocket
+	if ws.PingPeriod != 54*time.Second || ws.PongWait != 60*time.Second || ws.WriteWait != 10*time.Second {
+		t.Errorf("websocket 时间参数 = %+v, want 54s/60s/10s", ws)
+	}
+	if ws.MaxMessageSize != 512 || ws.ReadBufferSize != 1024 || ws.MaxConnections != 50 {
+		t.Errorf("websocket 大小参数 = %+v", ws)
+	}
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("validateConfig() error = %v, want nil", err)
+	}
+}
+
+func TestValidateConfig_WebSocketPingBeforePong(t *testing.T) {
+	tests := []struct {
+		name    string
+		ping    time.Duration
+		pong    time.Duration
+		wantErr bool
+	}{
+		{name: "ping less than pong", ping: 54 * time.Second, pong: 60 * time.Second},
+		{name: "ping equals pong", ping: 60 * time.Second, pong: 60 * time.Second, wantErr: true},
+		{name: "ping greater than pong", ping: 90 * time.Second, pong: 60 * time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+			cfg.WebSocket.PingPeriod = tt.ping
+			cfg.WebSocket.PongWait = tt.pong
+
+			err := validateConfig(cfg)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("validateConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "ping_period") {
+				t.Errorf("validateConfig() error = %v, want ping_period error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// corsWildcard 允许任意来源
+const corsWildcard = "*"
+
+// devCORSOrigins 开发与测试环境默认允许的本地前端来源
+var devCORSOrigins = []string{
+	"http://localhost:5173",
+	"http://127.0.0.1:5173",
+	"http://localhost:3000",
+	"http://localhost:8080",
+}
+
+// defaultCORSOrigins 按环境返回默认允许的来源，预发布与生产环境必须显式配置
+func defaultCORSOrigins(env Environment) []string {
+	if env.IsDevelopment() || env.IsTesting() {
+		return append([]string(nil), devCORSOrigins...)
+	}
+	return []string{}
+}
+
+// ParseOriginList 解析逗号分隔的来源列表，去除空白与空项
+//
+// 例如 BACKEND_FEATURES_CORS_ALLOWED_ORIGINS="https://a.com, https://b.com"。
+func ParseOriginList(value string) []string {
+	origins := make([]string, 0)
+	for _, origin := range strings.Split(value, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// hasWildcardOrigin 来源列表是否包含通配符
+func hasWildcardOrigin(origins []string) bool {
+	for _, origin := range origins {
+		if origin == corsWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCORS 应用环境变量覆盖并规范来源列表
+//
+// 开发与测试环境下通配符加凭证的组合会被浏览器拒绝，替换为本地前端来源列表。
+func normalizeCORS(config *Config, env Environment) {
+	cors := &config.Features.CORSConfig
+	if raw := os.Getenv(envVarName(config.envPrefix, "features.cors.allowed_origins")); raw != "" {
+		cors.AllowedOrigins = ParseOriginList(raw)
+	} else {
+		cors.AllowedOrigins = ParseOriginList(strings.Join(cors.AllowedOrigins, ","))
+	}
+
+	if !env.IsProduction() && env != EnvStaging && cors.AllowCredentials && hasWildcardOrigin(cors.AllowedOrigins) {
+		cors.AllowedOrigins = defaultCORSOrigins(env)
+	}
+}
+
+// ValidateCORSPolicy 按环境检查 CORS 配置
+//
+// 通配符来源不能与 allow_credentials 同时使用；预发布与生产环境必须显式列出允许的来源。
+func ValidateCORSPolicy(env Environment, cors CORSConfig) error {
+	if cors.AllowCredentials && hasWildcardOrigin(cors.AllowedOrigins) {
+		return fmt.Errorf("cors allowed_origins cannot contain %q when allow_credentials is true", corsWildcard)
+	}
+	if env == EnvProduction || env == EnvStaging {
+		if len(cors.AllowedOrigins) == 0 {
+			return fmt.Errorf("cors allowed_origins must be set explicitly in %s", env)
+		}
+		if hasWildcardOrigin(cors.AllowedOrigins) {
+			return fmt.Errorf("cors allowed_origins cannot contain %q in %s", corsWildcard, env)
+		}
+	}
+	return nil
+}
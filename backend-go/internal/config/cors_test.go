@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateCORSPolicy_ProductionRequiresExplicitOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		cors    CORSConfig
+		wantErr bool
+	}{
+		{name: "通配符加凭证", cors: CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, wantErr: true},
+		{name: "通配符不带凭证", cors: CORSConfig{AllowedOrigins: []string{"*"}}, wantErr: true},
+		{name: "未配置来源", cors: CORSConfig{AllowCredentials: true}, wantErr: true},
+		{name: "显式来源", cors: CORSConfig{AllowedOrigins: []string{"https://yuce.example.com"}, AllowCredentials: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCORSPolicy(EnvProduction, tt.cors)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCORSPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := ValidateCORSPolicy(EnvDevelopment, CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}); err == nil {
+		t.Error("任何环境都不应接受通配符加凭证")
+	}
+}
+
+func TestLoad_DevelopmentGetsLocalhostCORSDefault(t *testing.T) {
+	t.Setenv("GO_ENV", "development")
+
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+	if !reflect.DeepEqual(cfg.Features.CORSConfig.AllowedOrigins, devCORSOrigins) {
+		t.Errorf("开发环境默认来源 = %v, want %v", cfg.Features.CORSConfig.AllowedOrigins, devCORSOrigins)
+	}
+
+	// 配置文件中的通配符加凭证替换为本地前端来源
+	cfg = loadProvenanceConfig(t, "features:\n  cors:\n    allowed_origins: [\"*\"]\n    allow_credentials: true\n")
+	if hasWildcardOrigin(cfg.Features.CORSConfig.AllowedOrigins) {
+		t.Errorf("开发环境不应保留通配符来源: %v", cfg.Features.CORSConfig.AllowedOrigins)
+	}
+	if err := ValidateCORSPolicy(EnvDevelopment, cfg.Features.CORSConfig); err != nil {
+		t.Errorf("开发环境默认 CORS 配置应通过校验: %v", err)
+	}
+}
+
+func TestLoad_CORSOriginsEnvOverride(t *testing.T) {
+	t.Setenv("GO_ENV", "production")
+	t.Setenv("BACKEND_FEATURES_CORS_ALLOWED_ORIGINS", " https://a.example.com, https://b.example.com/ ,")
+
+	cfg := loadProvenanceConfig(t, "auth:\n  jwt_secret: production-secret-with-at-least-32-characters\n")
+	want := []string{"https://a.example.com", "https://b.example.com"}
+	if !reflect.DeepEqual(cfg.Features.CORSConfig.AllowedOrigins, want) {
+		t.Errorf("环境变量覆盖后的来源 = %v, want %v", cfg.Features.CORSConfig.AllowedOrigins, want)
+	}
+}
+
+func TestParseOriginList(t *testing.T) {
+	if got := ParseOriginList(""); len(got) != 0 {
+		t.Errorf("空字符串应返回空列表, got %v", got)
+	}
+	if got := ParseOriginList("http://localhost:5173,,  https://yuce.example.com/ "); !reflect.DeepEqual(got, []string{"http://localhost:5173", "https://yuce.example.com"}) {
+		t.Errorf("ParseOriginList() = %v", got)
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ExportToEnv 导出为 .env 格式，每个配置项一行 BACKEND_<键名>=<值>
+//
+// 变量名与加载时 viper 的映射一致（键名大写，. 和 - 替换为 _），切片以逗号分隔；
+// 结构体切片等无法用环境变量表示的配置项输出为注释。redact 为 true 时密码、
+// 密钥类配置项的值替换为 ******。
+func (e *ConfigExporter) ExportToEnv(redact bool) ([]byte, error) {
+	prefix := e.config.envPrefix
+	if prefix == "" {
+		prefix = DefaultLoadOptions().EnvPrefix
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# 由 config export env 生成，加载时环境变量优先于配置文件\n")
+
+	section := ""
+	walkConfigKeys("", reflect.TypeOf(Config{}), func(key string) {
+		if top := strings.SplitN(key, ".", 2)[0]; top != section {
+			section = top
+			fmt.Fprintf(&buf, "\n# %s\n", section)
+		}
+
+		name := envVarName(prefix, key)
+		value, _ := lookupField(reflect.ValueOf(e.config).Elem(), key)
+		formatted, ok := formatEnvValue(reflect.ValueOf(value))
+		if !ok {
+			fmt.Fprintf(&buf, "# %s 无法用环境变量表示，请在配置文件中设置\n", name)
+			return
+		}
+		if redact && formatted != "" && isSecretKey(key) {
+			formatted = redactedValue
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", name, quoteEnvValue(formatted))
+	})
+	return buf.Bytes(), nil
+}
+
+// bindConfigEnv 为所有配置项绑定环境变量
+//
+// AutomaticEnv 只对已有默认值或出现在配置文件中的键生效，绑定后没有默认值的
+// 配置项也能通过导出的 .env 设置。
+func bindConfigEnv(v *viper.Viper) {
+	walkConfigKeys("", reflect.TypeOf(Config{}), func(key string) {
+		_ = v.BindEnv(key)
+	})
+}
+
+// walkConfigKeys 按 mapstructure 路径遍历配置结构体的叶子字段
+func walkConfigKeys(prefix string, t reflect.Type, fn func(key string)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			walkConfigKeys(name, field.Type, fn)
+			continue
+		}
+		fn(name)
+	}
+}
+
+// formatEnvValue 将配置值格式化为环境变量值，无法表示时返回 false
+func formatEnvValue(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return "", false
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.Slice:
+		// 加载时按逗号拆分，元素本身含逗号时无法还原
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, ok := formatEnvValue(v.Index(i))
+			if !ok || v.Index(i).Kind() == reflect.Slice || strings.Contains(item, ",") {
+				return "", false
+			}
+			items = append(items, item)
+		}
+		return strings.Join(items, ","), true
+	}
+	return "", false
+}
+
+// quoteEnvValue 值含空白、引号、# 或 $ 等字符时加双引号
+func quoteEnvValue(value string) string {
+	if strings.ContainsAny(value, " \t\r\n\"'#$\\`") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// isSecretKey 判断配置项是否为密码、密钥等敏感值
+func isSecretKey(key string) bool {
+	if secretKeys[key] {
+		return true
+	}
+	name := key[strings.LastIndex(key, ".")+1:]
+	return name == "password" || name == "secret" ||
+		strings.HasSuffix(name, "_password") || strings.HasSuffix(name, "_secret") || strings.HasSuffix(name, "_key")
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadDefaultConfig 在没有配置文件的目录中加载默认值与环境变量
+func loadDefaultConfig(t *testing.T) *Config {
+	t.Helper()
+	opts := DefaultLoadOptions()
+	opts.ConfigPath = t.TempDir()
+	opts.SkipValidate = true
+	cfg, err := Load(opts)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	return cfg
+}
+
+// parseDotenv 解析 ExportToEnv 的输出
+func parseDotenv(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			t.Fatalf("无效的 .env 行: %q", line)
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				t.Fatalf("%s 的值无法解析: %v", name, err)
+			}
+			value = unquoted
+		}
+		vars[name] = value
+	}
+	return vars
+}
+
+func TestExportToEnv_UsesViperKeyMapping(t *testing.T) {
+	cfg := loadDefaultConfig(t)
+	cfg.Database.MaxOpenConns = 37
+
+	data, err := NewConfigExporter(cfg).ExportToEnv(false)
+	if err != nil {
+		t.Fatalf("ExportToEnv() error = %v", err)
+	}
+
+	if !strings.Contains(string(data), "\nBACKEND_DATABASE_MAX_OPEN_CONNS=37\n") {
+		t.Errorf("缺少 BACKEND_DATABASE_MAX_OPEN_CONNS=37:\n%s", data)
+	}
+}
+
+func TestExportToEnv_RoundTripsThroughEnvLoading(t *testing.T) {
+	cfg := loadDefaultConfig(t)
+	cfg.Database.MaxOpenConns = 37
+	cfg.Database.ConnMaxLifetime = 90 * time.Second
+	cfg.Database.Password = "p@ss word#1"
+
+	exported, err := NewConfigExporter(cfg).ExportToEnv(false)
+	if err != nil {
+		t.Fatalf("ExportToEnv() error = %v", err)
+	}
+	for name, value := range parseDotenv(t, exported) {
+		t.Setenv(name, value)
+	}
+
+	loaded := loadDefaultConfig(t)
+	if loaded.Database.MaxOpenConns != 37 {
+		t.Errorf("MaxOpenConns = %d, want 37", loaded.Database.MaxOpenConns)
+	}
+	if loaded.Database.ConnMaxLifetime != cfg.Database.ConnMaxLifetime {
+		t.Errorf("ConnMaxLifetime = %v, want %v", loaded.Database.ConnMaxLifetime, cfg.Database.ConnMaxLifetime)
+	}
+	if loaded.Database.Password != cfg.Database.Password {
+		t.Errorf("Password = %q, want %q", loaded.Database.Password, cfg.Database.Password)
+	}
+
+	// 重新导出的结果应与原导出完全一致
+	reexported, err := NewConfigExporter(loaded).ExportToEnv(false)
+	if err != nil {
+		t.Fatalf("ExportToEnv() error = %v", err)
+	}
+	if string(reexported) != string(exported) {
+		t.Errorf("往返后配置不一致:\n--- 原导出\n%s\n--- 重新导出\n%s", exported, reexported)
+	}
+}
+
+func TestExportToEnv_RedactsSecrets(t *testing.T) {
+	cfg := loadDefaultConfig(t)
+	cfg.Database.Password = "db-password-that-must-not-leak"
+	cfg.Auth.JWTSecret = "jwt-secret-that-must-not-leak"
+
+	data, err := NewConfigExporter(cfg).ExportToEnv(true)
+	if err != nil {
+		t.Fatalf("ExportToEnv() error = %v", err)
+	}
+
+	vars := parseDotenv(t, data)
+	for _, name := range []string{"BACKEND_DATABASE_PASSWORD", "BACKEND_AUTH_JWT_SECRET"} {
+		if vars[name] != redactedValue {
+			t.Errorf("%s = %q, want %q", name, vars[name], redactedValue)
+		}
+	}
+	if strings.Contains(string(data), "must-not-leak") {
+		t.Errorf("脱敏导出包含明文密钥:\n%s", data)
+	}
+	if vars["BACKEND_DATABASE_MAX_OPEN_CONNS"] == "" {
+		t.Error("非敏感配置项不应被脱敏")
+	}
+}
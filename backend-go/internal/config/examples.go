@@ -115,12 +115,13 @@ func ExampleConfigValidation() {
 	// 创建测试配置
 	config := &Config{
 		Server: ServerConfig{
-			Host:         "localhost",
-			Port:         8080,
-			ReadTimeout:  30 * time.Second,
-			WriteTimeout: 30 * time.Second,
-			IdleTimeout:  120 * time.Second,
-			Mode:         "debug",
+			Host:            "localhost",
+			Port:            8080,
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			Mode:            "debug",
 		},
 		Database: DatabaseConfig{
 			Host:            "localhost",
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ValueSource 配置值来源
+type ValueSource string
+
+const (
+	SourceEnv     ValueSource = "env"     // 环境变量
+	SourceFile    ValueSource = "file"    // 配置文件
+	SourceDefault ValueSource = "default" // 默认值
+)
+
+// redactedValue 脱敏后的敏感配置值
+const redactedValue = "******"
+
+// provenanceKeys 需要记录来源的配置项
+var provenanceKeys = []string{
+	"server.host",
+	"server.port",
+	"server.mode",
+	"database.host",
+	"database.port",
+	"database.username",
+	"database.password",
+	"database.database",
+	"redis.host",
+	"redis.port",
+	"redis.password",
+	"redis.database",
+	"auth.jwt_secret",
+	"auth.jwt_expiration_hours",
+	"log.level",
+	"features.enable_swagger",
+	"features.enable_metrics",
+	"features.enable_rate_limit",
+}
+
+// secretKeys 值需要脱敏的配置项
+var secretKeys = map[string]bool{
+	"database.password": true,
+	"redis.password":    true,
+	"auth.jwt_secret":   true,
+}
+
+// FieldProvenance 单个配置项的生效值与来源
+type FieldProvenance struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source ValueSource `json:"source"`
+	EnvVar string      `json:"env_var,omitempty"` // 来源为环境变量时的变量名
+}
+
+// resolveSources 判断各配置项的来源，优先级与 viper 一致：环境变量 > 配置文件 > 默认值
+func resolveSources(v *viper.Viper, envPrefix string) map[string]ValueSource {
+	sources := make(map[string]ValueSource, len(provenanceKeys))
+	for _, key := range provenanceKeys {
+		switch {
+		case os.Getenv(envVarName(envPrefix, key)) != "":
+			sources[key] = SourceEnv
+		case v.InConfig(key):
+			sources[key] = SourceFile
+		default:
+			sources[key] = SourceDefault
+		}
+	}
+	return sources
+}
+
+// envVarName 返回配置项对应的环境变量名，例如 BACKEND_SERVER_PORT
+func envVarName(envPrefix, key string) string {
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if envPrefix == "" {
+		return name
+	}
+	return strings.ToUpper(envPrefix) + "_" + name
+}
+
+// GetConfigProvenance 返回选定配置项的生效值与来源，敏感值已脱敏
+//
+// 未通过 Load 加载的配置（如手动构造）不记录来源，返回空列表。
+func GetConfigProvenance(config *Config) []FieldProvenance {
+	fields := make([]FieldProvenance, 0, len(config.sources))
+	for _, key := range provenanceKeys {
+		source, ok := config.sources[key]
+		if !ok {
+			continue
+		}
+
+		field := FieldProvenance{Key: key, Source: source}
+		if source == SourceEnv {
+			field.EnvVar = envVarName(config.envPrefix, key)
+		}
+		if value, ok := lookupField(reflect.ValueOf(config).Elem(), key); ok {
+			field.Value = value
+		}
+		if secretKeys[key] && field.Value != "" {
+			field.Value = redactedValue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// GetLoadedConfigFile 返回加载时实际读取的配置文件路径，未找到配置文件时为空
+func GetLoadedConfigFile(config *Config) string {
+	return config.configFile
+}
+
+// GetLoadedSources 返回加载配置时实际使用的来源
+//
+// 仅在读取到配置文件时列出配置文件，仅在存在带前缀的环境变量时列出环境变量，默认值始终参与。
+func GetLoadedSources(config *Config) []string {
+	var sources []string
+	if config.configFile != "" {
+		sources = append(sources, "config file: "+config.configFile)
+	}
+	if hasPrefixedEnv(config.envPrefix) {
+		sources = append(sources, "environment variables")
+	}
+	return append(sources, "default values")
+}
+
+// hasPrefixedEnv 判断是否设置了带前缀的环境变量
+func hasPrefixedEnv(envPrefix string) bool {
+	if envPrefix == "" {
+		return false
+	}
+	prefix := strings.ToUpper(envPrefix) + "_"
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEffectiveConfig 返回脱敏后的运行时生效配置，用于排查环境变量覆盖是否生效
+func GetEffectiveConfig(config *Config) map[string]interface{} {
+	return map[string]interface{}{
+		"summary":  GetConfigSummary(config),
+		"metadata": GetConfigMetadata(config),
+		"fields":   GetConfigProvenance(config),
+	}
+}
+
+// lookupField 按 mapstructure 路径读取配置字段值
+func lookupField(v reflect.Value, key string) (interface{}, bool) {
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return nil, false
+		}
+
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			fieldType := v.Type().Field(i)
+			if fieldType.IsExported() && strings.Split(fieldType.Tag.Get("mapstructure"), ",")[0] == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return v.Interface(), true
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadProvenanceConfig 写入配置文件并加载
+func loadProvenanceConfig(t *testing.T, content string) *Config {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	opts := DefaultLoadOptions()
+	opts.ConfigPath = dir
+	opts.SkipValidate = true
+	cfg, err := Load(opts)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	return cfg
+}
+
+// findField 按配置项查找来源信息
+func findField(t *testing.T, fields []FieldProvenance, key string) FieldProvenance {
+	t.Helper()
+	for _, field := range fields {
+		if field.Key == key {
+			return field
+		}
+	}
+	t.Fatalf("缺少配置项 %s", key)
+	return FieldProvenance{}
+}
+
+func TestGetConfigProvenance_ReportsSources(t *testing.T) {
+	t.Setenv("BACKEND_SERVER_PORT", "9091")
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\ndatabase:\n  host: db.internal\n")
+
+	fields := GetConfigProvenance(cfg)
+
+	port := findField(t, fields, "server.port")
+	if port.Source != SourceEnv || port.EnvVar != "BACKEND_SERVER_PORT" || port.Value != 9091 {
+		t.Errorf("环境变量覆盖的配置项来源不正确: %+v", port)
+	}
+	if host := findField(t, fields, "database.host"); host.Source != SourceFile || host.Value != "db.internal" {
+		t.Errorf("配置文件中的配置项来源不正确: %+v", host)
+	}
+	if level := findField(t, fields, "log.level"); level.Source != SourceDefault {
+		t.Errorf("未设置的配置项应来自默认值: %+v", level)
+	}
+}
+
+func TestGetEffectiveConfig_RedactsSecrets(t *testing.T) {
+	jwtSecret := "file-jwt-secret-that-must-not-leak-0123456789"
+	dbPassword := "env-database-password-must-not-leak"
+	t.Setenv("BACKEND_DATABASE_PASSWORD", dbPassword)
+	cfg := loadProvenanceConfig(t, "auth:\n  jwt_secret: \""+jwtSecret+"\"\n")
+
+	data, err := json.Marshal(GetEffectiveConfig(cfg))
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	if strings.Contains(string(data), jwtSecret) || strings.Contains(string(data), dbPassword) {
+		t.Fatalf("输出不应包含敏感值: %s", data)
+	}
+
+	fields := GetConfigProvenance(cfg)
+	if secret := findField(t, fields, "auth.jwt_secret"); secret.Value != redactedValue || secret.Source != SourceFile {
+		t.Errorf("jwt_secret 应脱敏并标记来源: %+v", secret)
+	}
+	if password := findField(t, fields, "database.password"); password.Value != redactedValue || password.Source != SourceEnv {
+		t.Errorf("database.password 应脱敏并标记来源: %+v", password)
+	}
+	if password := findField(t, fields, "redis.password"); password.Value != "" {
+		t.Errorf("未设置的敏感值应保持为空: %+v", password)
+	}
+}
+
+func TestGetLoadedSources_ConfigFileFound(t *testing.T) {
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+
+	configFile := GetLoadedConfigFile(cfg)
+	if filepath.Base(configFile) != "config.yaml" {
+		t.Fatalf("GetLoadedConfigFile() = %q, want path to config.yaml", configFile)
+	}
+	sources := GetLoadedSources(cfg)
+	if len(sources) == 0 || sources[0] != "config file: "+configFile {
+		t.Errorf("sources = %v, want config file listed first", sources)
+	}
+	if metadata := GetConfigMetadata(cfg); metadata["config_file"] != configFile {
+		t.Errorf("metadata config_file = %v, want %s", metadata["config_file"], configFile)
+	}
+}
+
+func TestGetLoadedSources_ConfigFileAbsent(t *testing.T) {
+	t.Setenv("BACKEND_SERVER_PORT", "9091")
+
+	opts := DefaultLoadOptions()
+	opts.ConfigPath = t.TempDir()
+	opts.SkipValidate = true
+	cfg, err := Load(opts)
+	if err != nil {
+		t.Fatalf("缺少配置文件时应回退到默认值和环境变量: %v", err)
+	}
+
+	if configFile := GetLoadedConfigFile(cfg); configFile != "" {
+		t.Errorf("GetLoadedConfigFile() = %q, want empty", configFile)
+	}
+	want := []string{"environment variables", "default values"}
+	if got := GetLoadedSources(cfg); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("sources = %v, want %v", got, want)
+	}
+	if cfg.Server.Port != 9091 {
+		t.Errorf("Server.Port = %d, want 9091 from environment", cfg.Server.Port)
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigManager_ReloadFromFileValidatesChangedSections(t *testing.T) {
+	// worker.shutdown_timeout 为 0 不满足校验，但与本次重新加载无关
+	const base = "log:\n  level: info\nworker:\n  shutdown_timeout: 0s\nfeatures:\n  rate_limit:\n    requests_per_second: 100\n"
+	cfg := loadProvenanceConfig(t, base)
+	if err := validateConfig(cfg); err == nil || !strings.Contains(err.Error(), "Worker.ShutdownTimeout") {
+		t.Fatalf("validateConfig() error = %v, want Worker.ShutdownTimeout error", err)
+	}
+
+	manager := NewConfigManager(cfg)
+	var notified []*Config
+	manager.AddWatcher(func(c *Config) { notified = append(notified, c) })
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	reload := func(content string) error {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("写入配置文件失败: %v", err)
+		}
+		return manager.ReloadFromFile(path)
+	}
+
+	// 只修改日志级别时生效
+	if err := reload(strings.Replace(base, "level: info", "level: debug", 1)); err != nil {
+		t.Fatalf("ReloadFromFile() error = %v, want nil", err)
+	}
+	if got := manager.GetConfig(); got.Log.Level != "debug" || got.Worker.ShutdownTimeout != 0 {
+		t.Errorf("log.level = %q, worker.shutdown_timeout = %s, want debug/0s", got.Log.Level, got.Worker.ShutdownTimeout)
+	}
+	if len(notified) != 1 {
+		t.Errorf("观察者通知次数 = %d, want 1", len(notified))
+	}
+
+	// 变化的限流配置无效时整体拒绝，保留原配置
+	invalid := strings.Replace(base, "level: info", "level: warn", 1)
+	invalid = strings.Replace(invalid, "requests_per_second: 100", "requests_per_second: 0", 1)
+	err := reload(invalid)
+	if err == nil || !strings.Contains(err.Error(), "RequestsPerSecond") {
+		t.Fatalf("ReloadFromFile() error = %v, want RequestsPerSecond error", err)
+	}
+	got := manager.GetConfig()
+	if got.Log.Level != "debug" || got.Features.RateLimitConfig.RequestsPerSecond != 100 {
+		t.Errorf("log.level = %q, requests_per_second = %d, want 保留 debug/100", got.Log.Level, got.Features.RateLimitConfig.RequestsPerSecond)
+	}
+	if len(notified) != 1 {
+		t.Errorf("拒绝后观察者通知次数 = %d, want 1", len(notified))
+	}
+}
+
+func TestConfigManager_ApplyChangedSectionsChecksBusinessRules(t *testing.T) {
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+	manager := NewConfigManager(cfg)
+
+	// ping 周期不小于 pong 等待时间，由跨字段业务规则拦截
+	next := *cfg
+	next.WebSocket.PingPeriod = 2 * next.WebSocket.PongWait
+	if _, err := manager.ApplyChangedSections(&next); err == nil || !strings.Contains(err.Error(), "ping_period") {
+		t.Fatalf("ApplyChangedSections() error = %v, want ping_period error", err)
+	}
+
+	next = *cfg
+	next.Server.Port = 9090
+	sections, err := manager.ApplyChangedSections(&next)
+	if err != nil {
+		t.Fatalf("ApplyChangedSections() error = %v", err)
+	}
+	if len(sections) != 1 || sections[0] != "Server" || manager.GetConfig().Server.Port != 9090 {
+		t.Errorf("sections = %v, port = %d, want [Server]/9090", sections, manager.GetConfig().Server.Port)
+	}
+	if sections, err := manager.ApplyChangedSections(&next); err != nil || len(sections) != 0 {
+		t.Errorf("无变化时 sections = %v, err = %v", sections, err)
+	}
+}
+
+func TestConfigManager_RollbackRestoresPreviousConfig(t *testing.T) {
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+	manager := NewConfigManager(cfg)
+	var notified []int
+	manager.AddWatcher(func(c *Config) { notified = append(notified, c.Server.Port) })
+
+	for _, port := range []int{8081, 8082, 8083} {
+		next := *manager.GetConfig()
+		next.Server.Port = port
+		if _, err := manager.ApplyChangedSections(&next); err != nil {
+			t.Fatalf("ApplyChangedSections(port=%d) error = %v", port, err)
+		}
+	}
+	if history := manager.History(); len(history) != 3 || history[0].Server.Port != 8082 || history[2].Server.Port != 8080 {
+		t.Fatalf("History() 长度 = %d, want 3 且最新在前", len(history))
+	}
+
+	if err := manager.RollbackToPrevious(); err != nil {
+		t.Fatalf("RollbackToPrevious() error = %v", err)
+	}
+	if got := manager.GetConfig().Server.Port; got != 8082 {
+		t.Errorf("回滚后 port = %d, want 8082", got)
+	}
+	if len(notified) != 4 || notified[3] != 8082 {
+		t.Errorf("观察者收到 %v, want 最后一次为 8082", notified)
+	}
+
+	// 回滚移除已撤销的配置，再次回滚继续后退
+	if err := manager.RollbackTo(1); err != nil {
+		t.Fatalf("RollbackTo(1) error = %v", err)
+	}
+	if got := manager.GetConfig().Server.Port; got != 8080 {
+		t.Errorf("回滚后 port = %d, want 8080", got)
+	}
+	if len(notified) != 5 || notified[4] != 8080 {
+		t.Errorf("观察者收到 %v, want 最后一次为 8080", notified)
+	}
+	if err := manager.RollbackToPrevious(); err == nil {
+		t.Error("历史为空时应返回错误")
+	}
+}
+
+func TestConfigManager_RollbackRevalidatesAndBoundsHistory(t *testing.T) {
+	cfg := loadProvenanceConfig(t, "server:\n  port: 8080\n")
+	manager := NewConfigManager(cfg)
+	manager.SetHistorySize(2)
+
+	// 历史配置在回滚时重新校验，无效时保留当前配置
+	invalid := *cfg
+	invalid.Features.RateLimitConfig.RequestsPerSecond = 0
+	manager.history = append(manager.history, &invalid)
+	if err := manager.RollbackToPrevious(); err == nil || !strings.Contains(err.Error(), "RequestsPerSecond") {
+		t.Fatalf("RollbackToPrevious() error = %v, want RequestsPerSecond error", err)
+	}
+	if manager.GetConfig() != cfg {
+		t.Error("校验失败时应保留当前配置")
+	}
+	manager.history = nil
+
+	for _, port := range []int{8081, 8082, 8083} {
+		next := *manager.GetConfig()
+		next.Server.Port = port
+		if _, err := manager.ApplyChangedSections(&next); err != nil {
+			t.Fatalf("ApplyChangedSections(port=%d) error = %v", port, err)
+		}
+	}
+	history := manager.History()
+	if len(history) != 2 || history[0].Server.Port != 8082 || history[1].Server.Port != 8081 {
+		t.Errorf("History() = %d 份配置, want 保留最近 2 份", len(history))
+	}
+	if err := manager.RollbackTo(2); err == nil {
+		t.Error("超出历史范围时应返回错误")
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	// EncryptedValuePrefix 加密配置值前缀，例如 jwt_secret: "enc:..."
+	EncryptedValuePrefix = "enc:"
+
+	// SecretKeyEnvVar 配置解密密钥环境变量（base64 编码的 32 字节密钥）
+	SecretKeyEnvVar = "CONFIG_ENCRYPTION_KEY"
+)
+
+// SecretKeyProvider 提供配置解密密钥，默认读取环境变量；接入 KMS 时可替换
+var SecretKeyProvider = EnvSecretKey
+
+// EnvSecretKey 从环境变量读取解密密钥
+func EnvSecretKey() ([]byte, error) {
+	encoded := strings.TrimSpace(os.Getenv(SecretKeyEnvVar))
+	if encoded == "" {
+		return nil, fmt.Errorf("%s is not set", SecretKeyEnvVar)
+	}
+	return ParseSecretKey(encoded)
+}
+
+// ParseSecretKey 解析 base64 编码的 AES-256 密钥
+func ParseSecretKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid encryption key: expected 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// IsEncryptedValue 判断配置值是否为加密值
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, EncryptedValuePrefix)
+}
+
+// EncryptValue 使用 AES-GCM 加密配置值，field 作为附加数据绑定密文与字段
+func EncryptValue(field, plaintext string, key []byte) (string, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+	return EncryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue 解密配置值，非加密值原样返回
+func DecryptValue(field, value string, key []byte) (string, error) {
+	if !IsEncryptedValue(value) {
+		return value, nil
+	}
+
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedValuePrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted value: ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: wrong key or tampered ciphertext")
+	}
+	return string(plaintext), nil
+}
+
+// newSecretCipher 创建 AES-GCM 实例
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// decryptSecrets 解密配置中所有以 enc: 开头的字符串字段
+func decryptSecrets(config *Config) error {
+	var key []byte
+	return walkStringFields("", reflect.ValueOf(config).Elem(), func(field string, value reflect.Value) error {
+		if !IsEncryptedValue(value.String()) {
+			return nil
+		}
+
+		if key == nil {
+			k, err := SecretKeyProvider()
+			if err != nil {
+				return fmt.Errorf("config field %s is encrypted but no decryption key is available: %w", field, err)
+			}
+			key = k
+		}
+
+		plaintext, err := DecryptValue(field, value.String(), key)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt config field %s: %w", field, err)
+		}
+		value.SetString(plaintext)
+		return nil
+	})
+}
+
+// walkStringFields 递归遍历结构体中的字符串字段，字段路径使用 mapstructure 标签
+func walkStringFields(prefix string, v reflect.Value, fn func(field string, value reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fieldType := v.Type().Field(i)
+			if !fieldType.IsExported() {
+				continue
+			}
+
+			name := strings.Split(fieldType.Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				name = strings.ToLower(fieldType.Name)
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+
+			if err := walkStringFields(name, v.Field(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return walkStringFields(prefix, v.Elem(), fn)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStringFields(fmt.Sprintf("%s.%d", prefix, i), v.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if v.CanSet() {
+			return fn(prefix, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupSecretKey 生成测试密钥并写入环境变量
+func setupSecretKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	t.Setenv(SecretKeyEnvVar, base64.StdEncoding.EncodeToString(key))
+	return key
+}
+
+// loadSecretConfig 写入只包含 jwt_secret 的配置文件并加载
+func loadSecretConfig(t *testing.T, jwtSecret string) (*Config, error) {
+	t.Helper()
+	dir := t.TempDir()
+	content := "auth:\n  jwt_secret: \"" + jwtSecret + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	opts := DefaultLoadOptions()
+	opts.ConfigPath = dir
+	opts.SkipValidate = true
+	return Load(opts)
+}
+
+func TestEncryptedSecret_RoundTrip(t *testing.T) {
+	key := setupSecretKey(t)
+	secret := "production-jwt-secret-with-enough-length"
+
+	encrypted, err := EncryptValue("auth.jwt_secret", secret, key)
+	if err != nil {
+		t.Fatalf("EncryptValue() error = %v", err)
+	}
+	if !IsEncryptedValue(encrypted) || strings.Contains(encrypted, secret) {
+		t.Fatalf("加密结果不正确: %s", encrypted)
+	}
+
+	cfg, err := loadSecretConfig(t, encrypted)
+	if err != nil {
+		t.Fatalf("加载加密配置失败: %v", err)
+	}
+	if cfg.Auth.JWTSecret != secret {
+		t.Errorf("JWTSecret = %q, want %q", cfg.Auth.JWTSecret, secret)
+	}
+
+	decrypted, err := DecryptValue("auth.jwt_secret", encrypted, key)
+	if err != nil || decrypted != secret {
+		t.Errorf("DecryptValue() = %q, %v", decrypted, err)
+	}
+}
+
+func TestEncryptedSecret_PlaintextStillWorks(t *testing.T) {
+	t.Setenv(SecretKeyEnvVar, "")
+
+	cfg, err := loadSecretConfig(t, "plain-development-secret-value-123456")
+	if err != nil {
+		t.Fatalf("明文配置应可正常加载: %v", err)
+	}
+	if cfg.Auth.JWTSecret != "plain-development-secret-value-123456" {
+		t.Errorf("JWTSecret = %q", cfg.Auth.JWTSecret)
+	}
+}
+
+func TestEncryptedSecret_TamperedCiphertextFails(t *testing.T) {
+	key := setupSecretKey(t)
+
+	encrypted, err := EncryptValue("auth.jwt_secret", "production-jwt-secret-with-enough-length", key)
+	if err != nil {
+		t.Fatalf("EncryptValue() error = %v", err)
+	}
+
+	// 修改密文中的一个字节
+	raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, EncryptedValuePrefix))
+	raw[len(raw)-1] ^= 0xff
+	tampered := EncryptedValuePrefix + base64.StdEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"篡改密文", tampered},
+		{"非法编码", EncryptedValuePrefix + "not-base64!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSecretConfig(t, tt.value)
+			if err == nil || !strings.Contains(err.Error(), "auth.jwt_secret") {
+				t.Errorf("应加载失败并指明字段，实际 error = %v", err)
+			}
+		})
+	}
+}
+
+func TestEncryptedSecret_MissingKeyFails(t *testing.T) {
+	key := setupSecretKey(t)
+	encrypted, _ := EncryptValue("auth.jwt_secret", "production-jwt-secret-with-enough-length", key)
+
+	t.Setenv(SecretKeyEnvVar, "")
+	if _, err := loadSecretConfig(t, encrypted); err == nil || !strings.Contains(err.Error(), SecretKeyEnvVar) {
+		t.Errorf("缺少密钥时应加载失败，实际 error = %v", err)
+	}
+}
+
+func TestEncryptedSecret_BoundToField(t *testing.T) {
+	key := setupSecretKey(t)
+	encrypted, _ := EncryptValue("database.password", "db-password", key)
+
+	// 密文不能挪用到其他字段
+	if _, err := DecryptValue("auth.jwt_secret", encrypted, key); err == nil {
+		t.Errorf("不同字段解密应失败")
+	}
+}
+
+func TestEncryptedSecret_PreviousJWTKeys(t *testing.T) {
+	key := setupSecretKey(t)
+	secret := "previous-jwt-secret-with-enough-length"
+	encrypted, _ := EncryptValue("auth.jwt_previous_keys.0.secret", secret, key)
+
+	dir := t.TempDir()
+	content := "auth:\n  jwt_key_id: \"2025-10\"\n  jwt_previous_keys:\n    - id: \"2025-04\"\n      secret: \"" + encrypted + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	opts := DefaultLoadOptions()
+	opts.ConfigPath = dir
+	opts.SkipValidate = true
+	cfg, err := Load(opts)
+	if err != nil {
+		t.Fatalf("加载加密配置失败: %v", err)
+	}
+	if len(cfg.Auth.JWTPreviousKeys) != 1 || cfg.Auth.JWTPreviousKeys[0].Secret != secret {
+		t.Errorf("JWTPreviousKeys = %+v, 旧密钥应被解密", cfg.Auth.JWTPreviousKeys)
+	}
+}
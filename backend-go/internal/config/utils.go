@@ -72,11 +72,8 @@ func GetConfigMetadata(config *Config) map[string]interface{} {
 			"config_watch": true,
 			"env_override": true,
 		},
-		"sources": []string{
-			"config file",
-			"environment variables",
-			"default values",
-		},
+		"config_file": GetLoadedConfigFile(config),
+		"sources":     GetLoadedSources(config),
 	}
 }
 
@@ -297,6 +294,8 @@ func (e *ConfigExporter) ExportToFile(filename string) error {
 		data, err = e.ExportToJSON()
 	case "yaml", "yml":
 		data, err = e.ExportToYAML()
+	case "env":
+		data, err = e.ExportToEnv(false)
 	default:
 		return fmt.Errorf("unsupported file format: %s", ext)
 	}
@@ -1,12 +1,18 @@
 package container
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"time"
 
 	"backend-go/internal/adapters/persistence/mysql"
+	"backend-go/internal/adapters/realtime"
 	"backend-go/internal/adapters/services"
 	"backend-go/internal/config"
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/leaderboard"
 	"backend-go/internal/core/domain/match"
 	"backend-go/internal/core/domain/prediction"
@@ -15,15 +21,24 @@ import (
 	"backend-go/internal/core/domain/user"
 	"backend-go/internal/core/ports"
 	coreServices "backend-go/internal/core/services"
+	"backend-go/internal/core/types"
 	"backend-go/internal/shared/jwt"
 	"backend-go/internal/shared/logger"
 	"backend-go/internal/shared/password"
+	"backend-go/pkg/breaker"
+	"backend-go/pkg/cache"
 	"backend-go/pkg/database"
 	"backend-go/pkg/redis"
+	"backend-go/pkg/retry"
+	"backend-go/pkg/storage"
 
+	"golang.org/x/crypto/hkdf"
 	"gorm.io/gorm"
 )
 
+// maxStartupRetryInterval 初始连接重试的单次等待上限
+const maxStartupRetryInterval = 30 * time.Second
+
 // Container 依赖注入容器
 type Container struct {
 	config             *config.Config
@@ -47,6 +62,46 @@ type Container struct {
 	teamService        ports.TeamService
 	jwtService         jwt.JWTService
 	passwordService    password.Service
+	realtimeHub        *realtime.Hub
+	authService        ports.AuthService
+
+	// 通知
+	matchReminderService *coreServices.MatchReminderService
+
+	// 统计计数核对
+	statsReconciler *coreServices.StatsReconciler
+	statsRollup     *coreServices.StatsRollup
+	cacheWarmer     *coreServices.CacheWarmer
+
+	// Redis 键过期时间审计
+	redisTTLAuditor *coreServices.RedisTTLAuditor
+
+	// 后台任务心跳
+	workerHeartbeat *coreServices.WorkerHeartbeat
+
+	// 按用户灰度的功能开关
+	featureFlags *coreServices.FeatureFlags
+
+	// 通过管理接口发起的数据库迁移
+	migrationRunner *coreServices.MigrationRunner
+
+	// 管理员结束比赛并计分
+	matchFinishService *coreServices.MatchFinishService
+
+	// 用户徽章
+	badgeService *coreServices.BadgeService
+
+	// 通用缓存服务
+	cacheService redis.CacheService
+
+	// 文件存储（生成限时下载地址）
+	fileStorage storage.Storage
+
+	// 邮件与对象存储调用的熔断器，供外部依赖健康检查展示状态
+	externalBreakers []*breaker.Breaker
+
+	// 过期比赛与预测的归档清理
+	dataRetention *coreServices.DataRetention
 
 	// 管理员系统
 	adminService         ports.AdminService
@@ -63,6 +118,11 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		config: cfg,
 	}
 
+	// 统一接口返回的分值精度
+	types.SetScorePrecision(cfg.Features.ScorePrecision)
+	// 全局预测锁定窗口，单场比赛可通过 prediction_lock_at 覆盖
+	domain.SetPredictionLockBeforeStart(cfg.Features.PredictionLockBefore)
+
 	// 初始化数据库连接
 	if err := container.initDatabase(); err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -81,6 +141,16 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	return container, nil
 }
 
+// startupRetryPolicy 初始连接数据库与 Redis 的重试策略，沿用启动探针的次数与间隔
+func (c *Container) startupRetryPolicy() retry.Policy {
+	healthCheck := c.config.External.Monitoring.HealthCheck
+	return retry.Policy{
+		Attempts:    healthCheck.StartupRetries,
+		Interval:    healthCheck.StartupInterval,
+		MaxInterval: maxStartupRetryInterval,
+	}
+}
+
 // initDatabase 初始化数据库连接
 func (c *Container) initDatabase() error {
 	dbConfig := database.Config{
@@ -93,9 +163,16 @@ func (c *Container) initDatabase() error {
 		MaxIdleConns:    c.config.Database.MaxIdleConns,
 		MaxOpenConns:    c.config.Database.MaxOpenConns,
 		ConnMaxLifetime: c.config.Database.ConnMaxLifetime,
+		QueryTimeout:    c.config.Database.QueryTimeout,
+		MinQueryBudget:  c.config.Database.MinQueryBudget,
+		CountQueries:    c.config.Features.QueryCounter.Enabled,
 	}
 
-	db, err := database.NewConnection(dbConfig)
+	var db *gorm.DB
+	err := retry.Do(context.Background(), "database", c.startupRetryPolicy(), logger.GetLogger(), func() (err error) {
+		db, err = database.NewConnection(dbConfig)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -121,9 +198,16 @@ func (c *Container) initRedis() error {
 		PoolTimeout:  c.config.Redis.PoolTimeout,
 		IdleTimeout:  c.config.Redis.IdleTimeout,
 		MaxConnAge:   c.config.Redis.MaxConnAge,
+
+		OperationTimeout:   c.config.Redis.OperationTimeout,
+		MinOperationBudget: c.config.Redis.MinOperationBudget,
 	}
 
-	client, err := redis.NewClient(redisConfig, logger.GetLogger())
+	var client *redis.Client
+	err := retry.Do(context.Background(), "redis", c.startupRetryPolicy(), logger.GetLogger(), func() (err error) {
+		client, err = redis.NewClient(redisConfig, logger.GetLogger())
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to redis: %w", err)
 	}
@@ -141,8 +225,14 @@ func (c *Container) initServices() error {
 	})
 
 	// 初始化 JWT 服务
+	previousKeys := make([]jwt.Key, 0, len(c.config.Auth.JWTPreviousKeys))
+	for _, key := range c.config.Auth.JWTPreviousKeys {
+		previousKeys = append(previousKeys, jwt.Key{ID: key.ID, Secret: key.Secret})
+	}
 	c.jwtService = jwt.NewJWTService(jwt.Config{
 		SecretKey:       c.config.Auth.JWTSecret,
+		KeyID:           c.config.Auth.JWTKeyID,
+		PreviousKeys:    previousKeys,
 		AccessTokenTTL:  time.Duration(c.config.Auth.JWTExpirationHours) * time.Hour,
 		RefreshTokenTTL: time.Duration(c.config.Auth.RefreshTokenExpDays) * 24 * time.Hour,
 		Issuer:          c.config.Auth.JWTIssuer,
@@ -165,6 +255,7 @@ func (c *Container) initServices() error {
 
 	// 初始化缓存服务
 	cacheService := redis.NewCacheService(c.redisClient)
+	c.cacheService = cacheService
 	// 用于排行榜领域的缓存（适配器层实现）
 	c.leaderboardCache = services.NewLeaderboardCacheService(cacheService)
 	// 用于用户服务的排行榜缓存（核心服务实现）
@@ -174,6 +265,7 @@ func (c *Container) initServices() error {
 		coreServices.LeaderboardCacheConfig{
 			CacheExpiration: c.config.Cache.Leaderboard.CacheExpiration,
 			RefreshInterval: c.config.Cache.Leaderboard.RefreshInterval,
+			FreshFor:        c.config.Cache.Leaderboard.FreshFor,
 		},
 	)
 
@@ -181,20 +273,36 @@ func (c *Container) initServices() error {
 	c.scoringCalculator = services.NewScoringCalculator()
 
 	// 初始化服务
+	sessionStore := coreServices.NewSessionStore(cacheService, time.Duration(c.config.Auth.RefreshTokenExpDays)*24*time.Hour)
 	c.userService = coreServices.NewUserService(
 		c.userRepo,
 		c.jwtService,
 		c.passwordService,
 		userLeaderboardCache,
+		sessionStore,
 		coreServices.Config{
 			MaxLoginAttempts: c.config.Auth.MaxLoginAttempts,
 			LockoutDuration:  c.config.Auth.LockoutDuration,
 		},
 	)
+	emailSender := services.NewEmailSender(c.config.External.Email, logger.GetLogger())
+	if email := c.config.External.Email; email.Enabled && email.Provider == "smtp" {
+		if b := c.newExternalBreaker("email"); b != nil {
+			emailSender = services.NewCircuitEmailSender(emailSender, b)
+		}
+	}
 	// Match service requires cache and event bus; pass nils if not available
 	var matchCache *coreServices.MatchCacheService
+	if c.config.Features.CacheMatchData && c.config.Cache.MultiLevel.Enabled {
+		multiLevelCache := cache.NewMultiLevelCache(cacheService, cache.MultiLevelConfig{
+			L1TTL:        c.config.Cache.MultiLevel.L1TTL,
+			L1MaxEntries: c.config.Cache.MultiLevel.L1MaxEntries,
+			L1MaxBytes:   c.config.Cache.MultiLevel.L1MaxBytes,
+		}, logger.GetLogger())
+		matchCache = coreServices.NewMatchCacheService(multiLevelCache, c.matchRepo, logger.GetLogger())
+	}
 	var eventBus shared.EventBus
-	c.matchService = coreServices.NewMatchService(c.matchRepo, matchCache, eventBus, logger.GetLogger())
+	c.matchService = coreServices.NewMatchService(c.matchRepo, matchCache, cacheService, cacheService, eventBus, logger.GetLogger())
 	c.predictionService = coreServices.NewPredictionService(
 		c.predictionRepo,
 		c.voteRepo,
@@ -202,10 +310,70 @@ func (c *Container) initServices() error {
 		c.userRepo,
 		c.scoringRuleRepo,
 		eventBus,
+		cacheService,
+		coreServices.PredictionServiceConfig{
+			BlindPrediction: c.config.Features.BlindPrediction,
+			DailyLimit:      c.config.Features.DailyPredictionLimit,
+		},
+		logger.GetLogger(),
 	)
+	// 实时推送订阅中心
+	c.realtimeHub = realtime.NewHubWithConfig(realtime.HubConfig{
+		PingPeriod:     c.config.WebSocket.PingPeriod,
+		WriteWait:      c.config.WebSocket.WriteWait,
+		MaxConnections: c.config.WebSocket.MaxConnections,
+	})
+	// 比赛开始前提醒（按用户通知偏好发送邮件/实时推送）
+	c.matchReminderService = coreServices.NewMatchReminderService(
+		c.matchRepo,
+		c.predictionRepo,
+		c.userRepo,
+		emailSender,
+		realtime.NewUserNotifier(c.realtimeHub),
+		cacheService,
+		coreServices.MatchReminderConfig{},
+		logger.GetLogger(),
+	)
+	// Redis 统计计数器与数据库核对
+	c.statsReconciler = coreServices.NewStatsReconciler(
+		mysql.NewStatsRepository(c.db),
+		cacheService,
+		coreServices.StatsReconcilerConfig{
+			Interval:   c.config.Cache.Reconcile.Interval,
+			SampleSize: c.config.Cache.Reconcile.SampleSize,
+		},
+		logger.GetLogger(),
+	)
+	// 每日预测统计汇总
+	c.statsRollup = coreServices.NewStatsRollup(
+		cacheService,
+		coreServices.StatsRollupConfig{
+			Interval:     c.config.Cache.Rollup.Interval,
+			LookbackDays: c.config.Cache.Rollup.LookbackDays,
+		},
+		logger.GetLogger(),
+	)
+	c.redisTTLAuditor = coreServices.NewRedisTTLAuditor(cacheService, nil, logger.GetLogger())
+	// 后台任务心跳
+	c.workerHeartbeat = coreServices.NewWorkerHeartbeat(cacheService, logger.GetLogger())
+	// 按用户灰度的功能开关
+	c.featureFlags = coreServices.NewFeatureFlags(cacheService, logger.GetLogger())
 	c.leaderboardService = services.NewLeaderboardService(
 		c.leaderboardRepo,
 		c.leaderboardCache,
+		realtime.NewLeaderboardPublisher(c.realtimeHub),
+		logger.GetLogger(),
+	)
+	// 按访问频率预热排行榜缓存
+	c.cacheWarmer = coreServices.NewCacheWarmer(
+		cacheService,
+		c.leaderboardService,
+		coreServices.CacheWarmerConfig{
+			Budget:         c.config.Cache.Warmup.Budget,
+			MaxStaleCycles: c.config.Cache.Warmup.MaxStaleCycles,
+			Concurrency:    c.config.Cache.Warmup.Concurrency,
+			Interval:       c.config.Cache.Warmup.Interval,
+		},
 		logger.GetLogger(),
 	)
 	c.scoringService = services.NewScoringService(
@@ -217,28 +385,168 @@ func (c *Container) initServices() error {
 		c.scoringCalculator,
 		logger.GetLogger(),
 	)
+	c.matchFinishService = coreServices.NewMatchFinishService(c.matchService, c.scoringService, c.leaderboardService, logger.GetLogger())
+	c.badgeService = coreServices.NewBadgeService(c.predictionRepo, cacheService, badgeConfig(c.config.Badges), logger.GetLogger())
+	c.matchFinishService.SetBadgeRefresher(c.badgeService)
 	c.teamService = coreServices.NewTeamService(c.teamRepo)
 
 	// 初始化管理员系统服务
 	dbWrapper := &database.DB{DB: c.db}
-	c.adminService = coreServices.NewAdminService(dbWrapper)
+	c.adminService = coreServices.NewAdminService(dbWrapper, logger.GetLogger())
 	c.adminAuditService = coreServices.NewAdminAuditService(dbWrapper)
+	migrationService := coreServices.NewMigrationService(dbWrapper, mysql.NewMigrationRepository(dbWrapper))
+	migrationService.SetRequireDownMigrations(c.config.Database.Migration.RequireDownMigrations)
+	migrationService.SetSkipAutoMigrate(c.config.Database.Migration.SkipAutoMigrate)
+	c.migrationRunner = coreServices.NewMigrationRunner(
+		migrationService,
+		cacheService,
+		coreServices.MigrationRunnerConfig{MigrationsDir: c.config.Database.Migration.Path},
+		logger.GetLogger(),
+	)
+	c.authService = coreServices.NewAuthService(
+		c.userRepo,
+		cacheService,
+		sessionStore,
+		mysql.NewAPIKeyRepository(c.db),
+		emailSender,
+		c.jwtService,
+		c.adminService,
+		c.adminAuditService,
+		coreServices.AuthServiceConfig{
+			ResetTokenTTL:    c.config.Auth.PasswordReset.TokenTTL,
+			ResetURL:         c.config.Auth.PasswordReset.ResetURL,
+			ValidatePassword: c.config.Auth.PasswordPolicy.Validate,
+			ImpersonationTTL: c.config.Auth.Impersonation.TokenTTL,
+		},
+	)
 	c.sportTypeService = coreServices.NewSportTypeService(c.sportTypeRepo, logger.GetLogger())
 	c.scoringRuleService = coreServices.NewScoringRuleService(
 		c.sportScoringRuleRepo,
+		mysql.NewScoringSimulationRepository(c.db),
 		coreServices.NewDefaultScoreCalculator(logger.GetLogger()),
 		logger.GetLogger(),
 	)
 
+	fileStorage, err := c.newFileStorage()
+	if err != nil {
+		return fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+	c.fileStorage = fileStorage
+
+	// 归档写入文件存储，存储不支持写入时清理任务拒绝执行
+	archive, _ := fileStorage.(storage.Writer)
+	c.dataRetention = coreServices.NewDataRetention(
+		c.db,
+		archive,
+		coreServices.DataRetentionConfig{
+			Enabled:   c.config.Worker.Retention.Enabled,
+			Retention: c.config.Worker.Retention.Retention,
+			Interval:  c.config.Worker.Retention.Interval,
+			BatchSize: c.config.Worker.Retention.BatchSize,
+			Prefix:    c.config.Worker.Retention.ArchivePrefix,
+		},
+		logger.GetLogger(),
+	)
+
 	logger.Info("Services initialized successfully")
 	return nil
 }
 
+// newFileStorage 按 external.file_storage.provider 创建文件存储
+func (c *Container) newFileStorage() (storage.Storage, error) {
+	fs := c.config.External.FileStorage
+	switch fs.Provider {
+	case "s3", "minio":
+		s3, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:  fs.S3Config.Endpoint,
+			Region:    fs.S3Config.Region,
+			Bucket:    fs.S3Config.Bucket,
+			AccessKey: fs.S3Config.AccessKey,
+			SecretKey: fs.S3Config.SecretKey,
+			UseSSL:    fs.S3Config.UseSSL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if b := c.newExternalBreaker("file_storage"); b != nil {
+			return storage.NewCircuitStorage(s3, b), nil
+		}
+		return s3, nil
+	default:
+		signingKey := fs.URLSigningKey
+		if signingKey == "" {
+			derived, err := deriveURLSigningKey(c.config.Auth.JWTSecret)
+			if err != nil {
+				return nil, err
+			}
+			signingKey = derived
+		}
+		return storage.NewLocalStorage(storage.LocalConfig{Dir: fs.LocalPath, SigningKey: signingKey})
+	}
+}
+
+// urlSigningKeyInfo 派生下载地址签名密钥时使用的 HKDF info，与其他用途的密钥隔离
+const urlSigningKeyInfo = "backend-go/file-storage/url-signing"
+
+// deriveURLSigningKey 未配置 url_signing_key 时用 HKDF 从 JWT 密钥派生独立的签名密钥，
+// 下载令牌与 JWT 不共用同一把密钥
+func deriveURLSigningKey(secret string) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("external.file_storage.url_signing_key or auth.jwt_secret is required for local file storage")
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte(urlSigningKeyInfo)), key); err != nil {
+		return "", fmt.Errorf("failed to derive url signing key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// badgeConfig 将 badges 配置转换为徽章服务配置，未配置徽章时使用内置徽章
+func badgeConfig(cfg config.BadgesConfig) coreServices.BadgeConfig {
+	definitions := make([]coreServices.BadgeDefinition, len(cfg.Definitions))
+	for i, def := range cfg.Definitions {
+		definitions[i] = coreServices.BadgeDefinition{
+			ID:             def.ID,
+			Name:           def.Name,
+			Description:    def.Description,
+			Kind:           coreServices.BadgeKind(def.Kind),
+			Threshold:      def.Threshold,
+			MinPredictions: def.MinPredictions,
+		}
+	}
+	return coreServices.BadgeConfig{Definitions: definitions, CacheTTL: cfg.CacheTTL}
+}
+
+// newExternalBreaker 按 external.circuit_breaker 创建外部服务熔断器，未启用时返回 nil
+func (c *Container) newExternalBreaker(name string) *breaker.Breaker {
+	cb := c.config.External.CircuitBreaker
+	if !cb.Enabled {
+		return nil
+	}
+	b := breaker.New(name, breaker.Config{
+		FailureThreshold: cb.FailureThreshold,
+		Cooldown:         cb.Cooldown,
+		Timeout:          cb.Timeout,
+	})
+	c.externalBreakers = append(c.externalBreakers, b)
+	return b
+}
+
 // GetUserService 获取用户服务
 func (c *Container) GetUserService() user.Service {
 	return c.userService
 }
 
+// GetJWTService 获取 JWT 令牌服务
+func (c *Container) GetJWTService() jwt.JWTService {
+	return c.jwtService
+}
+
+// GetAuthService 获取认证服务
+func (c *Container) GetAuthService() ports.AuthService {
+	return c.authService
+}
+
 // GetMatchService 获取比赛服务
 func (c *Container) GetMatchService() match.Service {
 	return c.matchService
@@ -294,6 +602,81 @@ func (c *Container) GetRedisClient() *redis.Client {
 	return c.redisClient
 }
 
+// GetCacheService 获取缓存服务
+func (c *Container) GetCacheService() redis.CacheService {
+	return c.cacheService
+}
+
+// GetRealtimeHub 获取实时推送订阅中心
+func (c *Container) GetRealtimeHub() *realtime.Hub {
+	return c.realtimeHub
+}
+
+// GetMatchReminderService 获取比赛提醒服务
+func (c *Container) GetMatchReminderService() *coreServices.MatchReminderService {
+	return c.matchReminderService
+}
+
+// GetStatsRollup 获取每日统计汇总服务
+func (c *Container) GetStatsRollup() *coreServices.StatsRollup {
+	return c.statsRollup
+}
+
+// GetCacheWarmer 获取排行榜缓存预热服务
+func (c *Container) GetCacheWarmer() *coreServices.CacheWarmer {
+	return c.cacheWarmer
+}
+
+// GetStatsReconciler 获取统计计数核对服务
+func (c *Container) GetStatsReconciler() *coreServices.StatsReconciler {
+	return c.statsReconciler
+}
+
+// GetRedisTTLAuditor 获取 Redis 键过期时间审计服务
+func (c *Container) GetRedisTTLAuditor() *coreServices.RedisTTLAuditor {
+	return c.redisTTLAuditor
+}
+
+// GetWorkerHeartbeat 获取后台任务心跳服务
+func (c *Container) GetWorkerHeartbeat() *coreServices.WorkerHeartbeat {
+	return c.workerHeartbeat
+}
+
+// GetFeatureFlags 获取按用户灰度的功能开关服务
+func (c *Container) GetFeatureFlags() *coreServices.FeatureFlags {
+	return c.featureFlags
+}
+
+// GetMigrationRunner 获取数据库迁移运行器
+func (c *Container) GetMigrationRunner() *coreServices.MigrationRunner {
+	return c.migrationRunner
+}
+
+// GetMatchFinishService 获取结束比赛工作流服务
+func (c *Container) GetMatchFinishService() *coreServices.MatchFinishService {
+	return c.matchFinishService
+}
+
+// GetBadgeService 获取用户徽章服务
+func (c *Container) GetBadgeService() *coreServices.BadgeService {
+	return c.badgeService
+}
+
+// GetFileStorage 获取文件存储
+func (c *Container) GetFileStorage() storage.Storage {
+	return c.fileStorage
+}
+
+// GetExternalBreakers 获取邮件与对象存储调用的熔断器，未启用熔断时为空
+func (c *Container) GetExternalBreakers() []*breaker.Breaker {
+	return c.externalBreakers
+}
+
+// GetDataRetention 获取过期数据归档清理服务
+func (c *Container) GetDataRetention() *coreServices.DataRetention {
+	return c.dataRetention
+}
+
 // Close 关闭容器资源
 func (c *Container) Close() error {
 	var err error
@@ -77,6 +77,22 @@ const (
 	AuditStatusPartial
 )
 
+// AdminSavedView 管理员保存的查询视图，记录某个列表的过滤与排序条件
+type AdminSavedView struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	AdminUserID uint           `json:"admin_user_id" gorm:"uniqueIndex:idx_admin_saved_views_owner_name;not null"`
+	Resource    string         `json:"resource" gorm:"uniqueIndex:idx_admin_saved_views_owner_name;size:50;not null"`
+	Name        string         `json:"name" gorm:"uniqueIndex:idx_admin_saved_views_owner_name;size:100;not null"`
+	Params      datatypes.JSON `json:"params"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// 可保存视图的列表
+const (
+	SavedViewResourceAuditLogs = "audit_logs"
+)
+
 // SportType 运动类型 (为了避免循环导入，这里只定义ID)
 type SportType struct {
 	ID   uint   `json:"id"`
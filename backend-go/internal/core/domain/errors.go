@@ -24,6 +24,8 @@ var (
 	ErrInvalidMatchStatus    = errors.New("invalid match status")
 	ErrInvalidStartTime      = errors.New("invalid start time")
 	ErrInvalidTournament     = errors.New("invalid tournament")
+	ErrInvalidPredictionLock = errors.New("prediction lock time must be before match start")
+	ErrInvalidSearchFilter   = errors.New("invalid match search filter")
 
 	// 预测相关错误
 	ErrPredictionNotFound         = errors.New("prediction not found")
@@ -41,11 +43,15 @@ var (
 	ErrDailyVoteLimitExceeded  = errors.New("daily vote limit exceeded")
 
 	// 认证相关错误
-	ErrUnauthorized  = errors.New("unauthorized")
-	ErrForbidden     = errors.New("forbidden")
-	ErrTokenExpired  = errors.New("token expired")
-	ErrInvalidToken  = errors.New("invalid token")
-	ErrTokenNotFound = errors.New("token not found")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrTokenExpired    = errors.New("token expired")
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrTokenNotFound   = errors.New("token not found")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session revoked")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrAPIKeyRevoked   = errors.New("api key revoked")
 
 	// 业务规则错误
 	ErrInvalidInput          = errors.New("invalid input")
@@ -87,6 +93,7 @@ const (
 	CodeInvalidMatchStatus    ErrorCode = "INVALID_MATCH_STATUS"
 	CodeInvalidStartTime      ErrorCode = "INVALID_START_TIME"
 	CodeInvalidTournament     ErrorCode = "INVALID_TOURNAMENT"
+	CodeInvalidPredictionLock ErrorCode = "INVALID_PREDICTION_LOCK"
 
 	// 预测相关错误码
 	CodePredictionNotFound         ErrorCode = "PREDICTION_NOT_FOUND"
@@ -189,6 +196,8 @@ func GetErrorCode(err error) ErrorCode {
 		return CodeInvalidStartTime
 	case ErrInvalidTournament:
 		return CodeInvalidTournament
+	case ErrInvalidPredictionLock:
+		return CodeInvalidPredictionLock
 	case ErrPredictionNotFound:
 		return CodePredictionNotFound
 	case ErrPredictionAlreadyExists:
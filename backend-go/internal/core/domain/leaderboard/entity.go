@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/types"
 )
 
 // LeaderboardEntry 排行榜条目
@@ -18,13 +19,35 @@ type LeaderboardEntry struct {
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// AccuracyEntry 准确率排行榜条目，仅统计已结束比赛的预测
+type AccuracyEntry struct {
+	UserID     uint        `json:"user_id"`
+	Username   string      `json:"username"`
+	Nickname   string      `json:"nickname"`
+	Avatar     string      `json:"avatar"`
+	Total      int         `json:"total"`    // 已结算的预测数
+	Correct    int         `json:"correct"`  // 预测正确数
+	Accuracy   types.Score `json:"accuracy"` // 准确率百分比（0-100）
+	Rank       int         `json:"rank"`
+	Tournament string      `json:"tournament"`
+}
+
+// DefaultAccuracyMinPredictions 准确率排行榜默认的最少预测数，避免 1 中 1 即 100% 的失真
+const DefaultAccuracyMinPredictions = 5
+
 // LeaderboardStats 排行榜统计信息
 type LeaderboardStats struct {
-	TotalUsers   int       `json:"total_users"`
-	TopScore     int       `json:"top_score"`
-	AverageScore float64   `json:"average_score"`
-	LastUpdated  time.Time `json:"last_updated"`
-	Tournament   string    `json:"tournament"`
+	TotalUsers   int         `json:"total_users"`
+	TopScore     int         `json:"top_score"`
+	AverageScore types.Score `json:"average_score"`
+	LastUpdated  time.Time   `json:"last_updated"`
+	Tournament   string      `json:"tournament"`
+}
+
+// RefreshReport 批量刷新排行榜缓存的结果
+type RefreshReport struct {
+	Refreshed []string          `json:"refreshed"`        // 按传入顺序
+	Failed    map[string]string `json:"failed,omitempty"` // 赛事 -> 失败原因，包括因上下文取消未执行的赛事
 }
 
 // UserRankInfo 用户排名信息
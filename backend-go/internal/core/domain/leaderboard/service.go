@@ -18,6 +18,9 @@ type Service interface {
 	// RefreshLeaderboard 刷新排行榜缓存
 	RefreshLeaderboard(ctx context.Context, tournament string) error
 
+	// RefreshAll 以最多 concurrency 个并发刷新多个赛事的排行榜缓存，单个赛事失败不影响其他赛事
+	RefreshAll(ctx context.Context, tournaments []string, concurrency int) (RefreshReport, error)
+
 	// UpdateUserPoints 更新用户积分并刷新排行榜
 	UpdateUserPoints(ctx context.Context, userID uint, points int, tournament string) error
 
@@ -26,6 +29,9 @@ type Service interface {
 
 	// GetUsersAroundRank 获取指定排名周围的用户
 	GetUsersAroundRank(ctx context.Context, tournament string, rank int, radius int) ([]LeaderboardEntry, error)
+
+	// GetAccuracyLeaderboard 按预测准确率排名，预测数少于 minPredictions 的用户不参与排名
+	GetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int) ([]AccuracyEntry, error)
 }
 
 // CacheService 排行榜缓存服务接口
@@ -53,6 +59,12 @@ type CacheService interface {
 
 	// SetLeaderboardStats 设置排行榜统计缓存
 	SetLeaderboardStats(ctx context.Context, tournament string, stats *LeaderboardStats) error
+
+	// GetAccuracyLeaderboard 从缓存获取准确率排行榜
+	GetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int) ([]AccuracyEntry, error)
+
+	// SetAccuracyLeaderboard 设置准确率排行榜缓存
+	SetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int, entries []AccuracyEntry) error
 }
 
 // Repository 排行榜仓储接口
@@ -71,4 +83,13 @@ type Repository interface {
 
 	// GetUsersAroundRank 获取指定排名周围的用户
 	GetUsersAroundRank(ctx context.Context, tournament string, rank int, radius int) ([]LeaderboardEntry, error)
+
+	// GetAccuracyLeaderboard 按用户分组统计已结束比赛的预测准确率
+	GetAccuracyLeaderboard(ctx context.Context, tournament string, minPredictions int, limit int) ([]AccuracyEntry, error)
+}
+
+// Notifier 排行榜变更通知接口
+type Notifier interface {
+	// NotifyLeaderboardUpdated 通知排行榜已刷新
+	NotifyLeaderboardUpdated(tournament string, entries []LeaderboardEntry)
 }
@@ -10,9 +10,26 @@
 package domain
 
 import (
+	"sync/atomic"
 	"time"
 )
 
+// predictionLockBeforeStart 全局预测锁定窗口：比赛开始前多久停止接受预测，默认 0 即开赛时锁定
+var predictionLockBeforeStart atomic.Int64
+
+// SetPredictionLockBeforeStart 设置全局预测锁定窗口，负值按 0 处理
+func SetPredictionLockBeforeStart(window time.Duration) {
+	if window < 0 {
+		window = 0
+	}
+	predictionLockBeforeStart.Store(int64(window))
+}
+
+// PredictionLockBeforeStart 返回全局预测锁定窗口
+func PredictionLockBeforeStart() time.Duration {
+	return time.Duration(predictionLockBeforeStart.Load())
+}
+
 // MatchStatus represents the current state of a match in the system.
 //
 // This enumeration defines all possible states a match can be in throughout
@@ -88,6 +105,9 @@ type Match struct {
 	CreatedAt   time.Time   `gorm:"column:created_at" json:"createdAt"`                // Record creation timestamp (前端兼容字段名)
 	UpdatedAt   time.Time   `gorm:"column:updated_at" json:"updatedAt"`                // Record last update timestamp (前端兼容字段名)
 
+	// 单场预测锁定时间，设置后覆盖全局锁定窗口（如决赛提前锁定）
+	PredictionLockAt *time.Time `gorm:"column:prediction_lock_at" json:"predictionLockAt,omitempty"`
+
 	// 添加前端需要的字段
 	Title              string `gorm:"-" json:"title"`          // 比赛标题 (计算字段)
 	Description        string `gorm:"-" json:"description"`    // 比赛描述 (计算字段)
@@ -98,6 +118,8 @@ type Match struct {
 	FrontendTournament string `gorm:"-" json:"tournamentType"` // 前端兼容的赛事类型 (计算字段)
 	FrontendStatus     string `gorm:"-" json:"status"`         // 前端兼容的状态 (计算字段)
 
+	EffectiveLockAt time.Time `gorm:"-" json:"effectiveLockAt"` // 实际生效的预测锁定时间 (计算字段)
+
 	// Associations
 	Predictions []Prediction `gorm:"foreignKey:MatchID;constraint:OnDelete:CASCADE" json:"predictions,omitempty"` // Associated predictions
 }
@@ -141,10 +163,29 @@ func (m *Match) IsFinished() bool {
 // status and the current time against the scheduled start time.
 //
 // Returns:
-//   - true if the match is upcoming and the start time is in the future
-//   - false if the match has started, finished, or been cancelled
+//   - true if the match is upcoming and its prediction lock time is in the future
+//   - false if predictions are locked, or the match has started, finished, or been cancelled
 func (m *Match) CanAcceptPredictions() bool {
-	return m.Status == MatchStatusUpcoming && time.Now().Before(m.StartTime)
+	return m.Status == MatchStatusUpcoming && time.Now().Before(m.PredictionLockTime())
+}
+
+// PredictionLockTime returns the time at which the match stops accepting predictions.
+//
+// A per-match PredictionLockAt takes precedence; otherwise the global
+// lock-before-start window is subtracted from the scheduled start time.
+func (m *Match) PredictionLockTime() time.Time {
+	if m.PredictionLockAt != nil {
+		return *m.PredictionLockAt
+	}
+	return m.StartTime.Add(-PredictionLockBeforeStart())
+}
+
+// ValidatePredictionLock 检查单场预测锁定时间早于比赛开始时间
+func (m *Match) ValidatePredictionLock() error {
+	if m.PredictionLockAt != nil && !m.PredictionLockAt.Before(m.StartTime) {
+		return ErrInvalidPredictionLock
+	}
+	return nil
 }
 
 // CanPredict 检查是否可以预测（别名方法）
@@ -233,6 +274,9 @@ func (m *Match) FillComputedFields() {
 	// 设置年份
 	m.Year = m.StartTime.Year()
 
+	// 设置实际生效的预测锁定时间
+	m.EffectiveLockAt = m.PredictionLockTime()
+
 	// 设置前端兼容的赛事类型
 	switch m.Tournament {
 	case TournamentSpring:
@@ -278,6 +322,7 @@ type MatchResponse struct {
 	TournamentType  string    `json:"tournamentType"` // 转换为前端格式
 	TournamentStage string    `json:"tournamentStage"`
 	Year            int       `json:"year"`
+	EffectiveLockAt time.Time `json:"effectiveLockAt"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 }
@@ -329,6 +374,7 @@ func (m *Match) ToResponse() MatchResponse {
 		TournamentType:  frontendTournament,
 		TournamentStage: "regular",
 		Year:            m.StartTime.Year(),
+		EffectiveLockAt: m.PredictionLockTime(),
 		CreatedAt:       m.CreatedAt,
 		UpdatedAt:       m.UpdatedAt,
 	}
@@ -43,6 +43,8 @@ type CreateMatchRequest struct {
 	TeamB      string     `json:"team_b" validate:"required,max=100"`
 	Tournament Tournament `json:"tournament" validate:"required"`
 	StartTime  time.Time  `json:"start_time" validate:"required"`
+	// PredictionLockAt 单场预测锁定时间，须早于开始时间；为空时使用全局锁定窗口
+	PredictionLockAt *time.Time `json:"prediction_lock_at"`
 }
 
 // UpdateMatchRequest 更新比赛请求
@@ -51,6 +53,8 @@ type UpdateMatchRequest struct {
 	TeamB      string     `json:"team_b" validate:"max=100"`
 	Tournament Tournament `json:"tournament"`
 	StartTime  *time.Time `json:"start_time"`
+	// PredictionLockAt 单场预测锁定时间，须早于开始时间
+	PredictionLockAt *time.Time `json:"prediction_lock_at"`
 }
 
 // SetResultRequest 设置比赛结果请求
@@ -16,6 +16,9 @@ type Repository interface {
 	// List 获取比赛列表
 	List(ctx context.Context, filter ListFilter) ([]Match, error)
 
+	// Search 按搜索条件返回开始时间、ID 升序的比赛，从游标之后开始，最多 filter.Limit 条
+	Search(ctx context.Context, filter MatchSearchFilter) ([]Match, error)
+
 	// Update 更新比赛信息
 	Update(ctx context.Context, match *Match) error
 
@@ -40,6 +43,9 @@ type Repository interface {
 	// GetFinishedMatches 获取所有已结束的比赛（用于积分计算）
 	GetFinishedMatches(ctx context.Context) ([]Match, error)
 
+	// GetActiveWithPredictionCounts 获取即将开始与进行中的比赛及各自的预测数，tournament 为空时不限赛事
+	GetActiveWithPredictionCounts(ctx context.Context, tournament string) ([]MatchPredictionCount, error)
+
 	// Delete 删除比赛
 	Delete(ctx context.Context, id uint) error
 }
@@ -0,0 +1,136 @@
+package match
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend-go/internal/core/domain"
+)
+
+// 比赛搜索限制，与创建比赛请求的字段校验保持一致
+const (
+	// MaxOptionLength 选项（队伍）名称的最大长度，对应 team_a/team_b 的 max=100
+	MaxOptionLength = 100
+	// MaxSearchQueryLength 搜索词最大长度，标题由两个选项以 " vs " 连接而成
+	MaxSearchQueryLength = 2*MaxOptionLength + len(titleSeparator)
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// titleSeparator 标题中两个选项的分隔符，见 Match.FillComputedFields
+const titleSeparator = " vs "
+
+// MatchSearchFilter 比赛搜索条件，各条件之间为 AND 关系
+type MatchSearchFilter struct {
+	Query       string      `json:"q"`             // 匹配任一选项；形如 "A vs B" 时分别匹配两个选项
+	Tournament  Tournament  `json:"tournament"`    // 赛事
+	SportTypeID *uint       `json:"sport_type_id"` // 运动类型
+	Status      MatchStatus `json:"status"`        // 比赛状态
+	StartFrom   *time.Time  `json:"start_from"`    // 开始时间下限（含）
+	StartTo     *time.Time  `json:"start_to"`      // 开始时间上限（含）
+	Cursor      string      `json:"cursor"`        // 上一页返回的 next_cursor
+	Limit       int         `json:"limit"`         // 每页数量，默认 20，最大 100
+}
+
+// MatchPage 按开始时间、ID 升序的一页搜索结果
+type MatchPage struct {
+	Items      []Match `json:"items"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// SearchCursor 键集分页游标，指向上一页最后一条记录
+type SearchCursor struct {
+	StartTime time.Time
+	ID        uint
+}
+
+// Validate 校验搜索条件，错误均包装 domain.ErrInvalidSearchFilter
+func (f *MatchSearchFilter) Validate() error {
+	query := strings.TrimSpace(f.Query)
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return fmt.Errorf("%w: q must be at most %d characters", domain.ErrInvalidSearchFilter, MaxSearchQueryLength)
+	}
+	optionA, optionB, isTitle := f.TitleOptions()
+	if isTitle && (utf8.RuneCountInString(optionA) > MaxOptionLength || utf8.RuneCountInString(optionB) > MaxOptionLength) {
+		return fmt.Errorf("%w: each option must be at most %d characters", domain.ErrInvalidSearchFilter, MaxOptionLength)
+	}
+	if !isTitle && utf8.RuneCountInString(query) > MaxOptionLength {
+		return fmt.Errorf("%w: option must be at most %d characters", domain.ErrInvalidSearchFilter, MaxOptionLength)
+	}
+
+	if f.Tournament != "" && !domain.IsValidTournament(string(f.Tournament)) {
+		return fmt.Errorf("%w: unknown tournament %q", domain.ErrInvalidSearchFilter, f.Tournament)
+	}
+	if f.Status != "" && !domain.IsValidMatchStatus(string(f.Status)) {
+		return fmt.Errorf("%w: unknown status %q", domain.ErrInvalidSearchFilter, f.Status)
+	}
+	if f.StartFrom != nil && f.StartTo != nil && f.StartFrom.After(*f.StartTo) {
+		return fmt.Errorf("%w: start_from must not be after start_to", domain.ErrInvalidSearchFilter)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", domain.ErrInvalidSearchFilter)
+	}
+	if f.Cursor != "" {
+		if _, err := DecodeSearchCursor(f.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TitleOptions 搜索词形如 "A vs B" 时返回两侧的选项
+func (f *MatchSearchFilter) TitleOptions() (optionA, optionB string, ok bool) {
+	query := strings.TrimSpace(f.Query)
+	idx := strings.Index(strings.ToLower(query), titleSeparator)
+	if idx < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(query[:idx]), strings.TrimSpace(query[idx+len(titleSeparator):]), true
+}
+
+// PageLimit 返回实际的每页数量
+func (f *MatchSearchFilter) PageLimit() int {
+	switch {
+	case f.Limit <= 0:
+		return DefaultSearchLimit
+	case f.Limit > MaxSearchLimit:
+		return MaxSearchLimit
+	default:
+		return f.Limit
+	}
+}
+
+// EncodeSearchCursor 编码分页游标
+func EncodeSearchCursor(cursor SearchCursor) string {
+	raw := strconv.FormatInt(cursor.StartTime.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(cursor.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSearchCursor 解码分页游标
+func DecodeSearchCursor(encoded string) (*SearchCursor, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", domain.ErrInvalidSearchFilter)
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, invalid
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || id == 0 {
+		return nil, invalid
+	}
+	return &SearchCursor{StartTime: time.Unix(0, nanos).UTC(), ID: uint(id)}, nil
+}
@@ -15,6 +15,9 @@ type Service interface {
 	// ListMatches 获取比赛列表
 	ListMatches(ctx context.Context, filter ListFilter) ([]Match, error)
 
+	// SearchMatches 按选项名称、赛事、运动类型、状态与开始时间搜索比赛，键集分页
+	SearchMatches(ctx context.Context, filter MatchSearchFilter) (*MatchPage, error)
+
 	// UpdateMatch 更新比赛信息
 	UpdateMatch(ctx context.Context, id uint, req *UpdateMatchRequest) (*Match, error)
 
@@ -35,4 +38,7 @@ type Service interface {
 
 	// GetFinishedMatches 获取已结束的比赛
 	GetFinishedMatches(ctx context.Context, limit int) ([]Match, error)
+
+	// GetTrendingMatches 按预测数与浏览量获取热门的未结束比赛，tournament 为空时不限赛事
+	GetTrendingMatches(ctx context.Context, tournament string, limit int) ([]TrendingMatch, error)
 }
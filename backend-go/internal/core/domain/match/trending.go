@@ -0,0 +1,32 @@
+package match
+
+// 热门比赛数量限制
+const (
+	DefaultTrendingLimit = 10
+	MaxTrendingLimit     = 50
+)
+
+// 热度权重：提交预测比浏览更能体现关注度
+const (
+	TrendingPredictionWeight = 5.0
+	TrendingViewWeight       = 1.0
+)
+
+// MatchPredictionCount 未结束的比赛及其预测数
+type MatchPredictionCount struct {
+	Match           Match
+	PredictionCount int64
+}
+
+// TrendingMatch 热门比赛，按 Score 降序排列
+type TrendingMatch struct {
+	Match           Match   `json:"match"`
+	PredictionCount int64   `json:"prediction_count"`
+	ViewCount       int64   `json:"view_count"`
+	Score           float64 `json:"score"`
+}
+
+// TrendingScore 计算比赛热度
+func TrendingScore(predictions, views int64) float64 {
+	return float64(predictions)*TrendingPredictionWeight + float64(views)*TrendingViewWeight
+}
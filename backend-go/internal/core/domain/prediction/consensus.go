@@ -0,0 +1,97 @@
+package prediction
+
+import (
+	"math"
+
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/types"
+)
+
+// ConsensusBucket 按预测获胜方分组的统计数据
+type ConsensusBucket struct {
+	PredictedWinner string `json:"predictedWinner" gorm:"column:predictedWinner"`
+	Count           int64  `json:"count" gorm:"column:count"`
+	VoteCount       int64  `json:"voteCount" gorm:"column:vote_count"`
+}
+
+// MatchConsensus 比赛的社区预测共识
+type MatchConsensus struct {
+	MatchID            uint        `json:"matchId"`
+	TotalPredictions   int64       `json:"totalPredictions"`
+	WinnerACount       int64       `json:"winnerACount"`
+	WinnerBCount       int64       `json:"winnerBCount"`
+	DrawCount          int64       `json:"drawCount"`
+	WinnerAPercent     float64     `json:"winnerAPercent"`
+	WinnerBPercent     float64     `json:"winnerBPercent"`
+	DrawPercent        float64     `json:"drawPercent"`
+	WeightedConfidence types.Score `json:"weightedConfidence"`
+	Hidden             bool        `json:"hidden"`
+}
+
+// NewMatchConsensus 根据分组统计构建比赛共识
+//
+// 加权置信度按每个预测 1 + 获得票数 加权，取领先方占总权重的百分比
+func NewMatchConsensus(matchID uint, buckets []ConsensusBucket) *MatchConsensus {
+	consensus := &MatchConsensus{MatchID: matchID}
+
+	weights := make(map[string]int64, len(buckets))
+	var totalWeight int64
+	for _, b := range buckets {
+		switch match.Winner(b.PredictedWinner) {
+		case match.WinnerA:
+			consensus.WinnerACount += b.Count
+		case match.WinnerB:
+			consensus.WinnerBCount += b.Count
+		case match.WinnerDraw:
+			consensus.DrawCount += b.Count
+		default:
+			continue
+		}
+		consensus.TotalPredictions += b.Count
+
+		weight := b.Count + b.VoteCount
+		weights[b.PredictedWinner] += weight
+		totalWeight += weight
+	}
+
+	if consensus.TotalPredictions == 0 {
+		return consensus
+	}
+
+	total := float64(consensus.TotalPredictions)
+	consensus.WinnerAPercent = roundPercent(float64(consensus.WinnerACount) / total)
+	consensus.WinnerBPercent = roundPercent(float64(consensus.WinnerBCount) / total)
+	consensus.DrawPercent = roundPercent(float64(consensus.DrawCount) / total)
+
+	var leading int64
+	for _, w := range weights {
+		if w > leading {
+			leading = w
+		}
+	}
+	if totalWeight > 0 {
+		consensus.WeightedConfidence = types.Score(roundPercent(float64(leading) / float64(totalWeight)))
+	}
+
+	return consensus
+}
+
+// HiddenMatchConsensus 创建被隐藏的比赛共识（盲预测模式）
+func HiddenMatchConsensus(matchID uint) *MatchConsensus {
+	return &MatchConsensus{MatchID: matchID, Hidden: true}
+}
+
+// IsConsensusVisible 判断比赛共识是否可以公开
+//
+// 开启盲预测后，比赛开始前不公开共识，避免从众效应
+func IsConsensusVisible(blindPrediction bool, m *match.Match) bool {
+	if !blindPrediction || m == nil {
+		return true
+	}
+	return !m.CanAcceptPredictions()
+}
+
+// roundPercent 将比例转换为保留两位小数的百分比
+func roundPercent(ratio float64) float64 {
+	return math.Round(ratio*10000) / 100
+}
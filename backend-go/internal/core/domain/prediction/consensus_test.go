@@ -0,0 +1,63 @@
+package prediction
+
+import (
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain"
+)
+
+func TestNewMatchConsensus(t *testing.T) {
+	buckets := []ConsensusBucket{
+		{PredictedWinner: "A", Count: 3, VoteCount: 5},
+		{PredictedWinner: "B", Count: 1, VoteCount: 0},
+	}
+
+	consensus := NewMatchConsensus(1, buckets)
+
+	if consensus.TotalPredictions != 4 {
+		t.Errorf("TotalPredictions = %d, want 4", consensus.TotalPredictions)
+	}
+	if consensus.WinnerAPercent != 75 {
+		t.Errorf("WinnerAPercent = %v, want 75", consensus.WinnerAPercent)
+	}
+	if consensus.WinnerBPercent != 25 {
+		t.Errorf("WinnerBPercent = %v, want 25", consensus.WinnerBPercent)
+	}
+	// A 方权重 3+5=8，总权重 9
+	if consensus.WeightedConfidence != 88.89 {
+		t.Errorf("WeightedConfidence = %v, want 88.89", consensus.WeightedConfidence)
+	}
+}
+
+func TestNewMatchConsensus_Empty(t *testing.T) {
+	consensus := NewMatchConsensus(1, nil)
+
+	if consensus.TotalPredictions != 0 || consensus.WinnerAPercent != 0 || consensus.WeightedConfidence != 0 {
+		t.Errorf("空比赛共识应全部为零，实际为 %+v", consensus)
+	}
+}
+
+func TestIsConsensusVisible(t *testing.T) {
+	upcoming := &domain.Match{Status: domain.MatchStatusUpcoming, StartTime: time.Now().Add(time.Hour)}
+	live := &domain.Match{Status: domain.MatchStatusLive, StartTime: time.Now().Add(-time.Hour)}
+
+	tests := []struct {
+		name     string
+		blind    bool
+		match    *domain.Match
+		expected bool
+	}{
+		{name: "未开启盲预测", blind: false, match: upcoming, expected: true},
+		{name: "盲预测隐藏未开始比赛", blind: true, match: upcoming, expected: false},
+		{name: "盲预测公开已开始比赛", blind: true, match: live, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConsensusVisible(tt.blind, tt.match); got != tt.expected {
+				t.Errorf("IsConsensusVisible() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -22,9 +22,11 @@ type Prediction struct {
 	PredictedWinner   string    `json:"predictedWinner" gorm:"column:predictedWinner;size:10;not null"`
 	PredictedScoreA   int       `json:"predictedScoreA" gorm:"column:predictedScoreA;not null"`
 	PredictedScoreB   int       `json:"predictedScoreB" gorm:"column:predictedScoreB;not null"`
+	Confidence        int       `json:"confidence" gorm:"column:confidence;not null;default:1"` // 信心倍数，积分规则启用时放大得分
 	IsCorrect         bool      `json:"isCorrect" gorm:"column:isCorrect;default:false"`
 	EarnedPoints      int       `json:"pointsEarned" gorm:"column:earnedPoints;default:0"`
 	ModificationCount int       `json:"modificationCount" gorm:"column:modification_count;default:0"`
+	Version           int       `json:"version" gorm:"column:version;not null;default:1"` // 乐观锁版本号
 	VoteCount         int       `json:"voteCount" gorm:"column:vote_count;default:0"`
 	IsFeatured        bool      `json:"isFeatured" gorm:"column:is_featured;default:false"`
 	CreatedAt         time.Time `json:"createdAt" gorm:"column:createdAt"`
@@ -133,19 +135,19 @@ func (p *Prediction) CalculatePointsWithRule(rule *ScoringRule) int {
 		return 0
 	}
 
-	// 使用规则计算基础积分
-	points := rule.CalculatePoints(p)
+	// 更新预测正确性标记
+	teamCorrect := p.PredictedWinner == p.Match.Winner
+	scoreCorrect := p.PredictedScoreA == p.Match.ScoreA && p.PredictedScoreB == p.Match.ScoreB
+	p.IsCorrect = teamCorrect || scoreCorrect
+
+	// 使用规则计算基础积分，并按信心倍数调整
+	points := rule.ApplyConfidence(rule.CalculatePoints(p), p.Confidence, p.IsCorrect)
 
 	// 根据投票数给予热门奖励（保持原有逻辑）
 	if p.VoteCount >= 10 {
 		points += 5
 	}
 
-	// 更新预测正确性标记
-	teamCorrect := p.PredictedWinner == p.Match.Winner
-	scoreCorrect := p.PredictedScoreA == p.Match.ScoreA && p.PredictedScoreB == p.Match.ScoreB
-	p.IsCorrect = teamCorrect || scoreCorrect
-
 	return points
 }
 
@@ -15,7 +15,7 @@ type Repository interface {
 	// GetPredictionByUserAndMatch 根据用户和比赛获取预测
 	GetPredictionByUserAndMatch(ctx context.Context, userID, matchID uint) (*Prediction, error)
 
-	// UpdatePrediction 更新预测
+	// UpdatePrediction 按版本号更新预测，成功后版本号加一；版本不一致时返回冲突错误
 	UpdatePrediction(ctx context.Context, prediction *Prediction) error
 
 	// GetPredictionsByMatch 获取比赛的所有预测
@@ -24,6 +24,9 @@ type Repository interface {
 	// GetPredictionsByUser 获取用户的所有预测
 	GetPredictionsByUser(ctx context.Context, userID uint) ([]Prediction, error)
 
+	// GetPredictionsByMatchAfter 按 ID 升序获取比赛中 ID 大于 afterID 的预测，最多 limit 条，用于分批导出
+	GetPredictionsByMatchAfter(ctx context.Context, matchID, afterID uint, limit int) ([]Prediction, error)
+
 	// UpdatePredictionPoints 更新预测积分
 	UpdatePredictionPoints(ctx context.Context, predictionID uint, points int, isCorrect bool) error
 
@@ -35,6 +38,9 @@ type Repository interface {
 
 	// DeletePrediction 删除预测
 	DeletePrediction(ctx context.Context, id uint) error
+
+	// GetConsensusBuckets 按预测获胜方分组统计比赛预测
+	GetConsensusBuckets(ctx context.Context, matchID uint) ([]ConsensusBucket, error)
 }
 
 // VoteRepository 投票仓储接口
@@ -63,6 +69,9 @@ type VoteRepository interface {
 	// CreateVoteWithCount 创建投票并更新计数（事务性操作）
 	CreateVoteWithCount(ctx context.Context, vote *Vote) error
 
+	// CreateVotesWithCounts 批量创建投票并按预测汇总更新计数（事务性操作）
+	CreateVotesWithCounts(ctx context.Context, votes []*Vote) error
+
 	// DeleteVoteWithCount 删除投票并更新计数（事务性操作）
 	DeleteVoteWithCount(ctx context.Context, userID, predictionID uint) error
 
@@ -14,16 +14,50 @@ type ScoringRule struct {
 	CorrectTeamWrongScore   int       `json:"correct_team_wrong_score" gorm:"default:0"`   // 预测正确队伍错误比分
 	WrongTeamCorrectScore   int       `json:"wrong_team_correct_score" gorm:"default:0"`   // 预测错误队伍正确比分
 	WrongTeamWrongScore     int       `json:"wrong_team_wrong_score" gorm:"default:0"`     // 预测错误队伍错误比分
+	EnableConfidence        bool      `json:"enable_confidence"`                           // 启用信心倍数，得分乘以预测的信心倍数
+	EnableConfidenceRisk    bool      `json:"enable_confidence_risk"`                      // 启用信心风险，预测错误时按加注倍数扣分
+	ConfidenceRiskPoints    int       `json:"confidence_risk_points" gorm:"default:0"`     // 每加注一倍在预测错误时扣除的积分
 	IsActive                bool      `json:"is_active" gorm:"default:true"`
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
 }
 
+// 信心倍数范围
+const (
+	MinConfidence = 1
+	MaxConfidence = 3
+)
+
 // TableName 指定表名
 func (ScoringRule) TableName() string {
 	return "scoring_rules"
 }
 
+// ApplyConfidence 按预测的信心倍数调整规则积分
+//
+// 未启用信心倍数时原样返回。启用后预测正确的积分乘以信心倍数；同时启用
+// 信心风险时，预测错误按超出 1 倍的部分扣分，结果可能为负数。
+func (sr *ScoringRule) ApplyConfidence(points, confidence int, isCorrect bool) int {
+	if sr == nil || !sr.EnableConfidence {
+		return points
+	}
+
+	if confidence < MinConfidence {
+		confidence = MinConfidence
+	}
+	if confidence > MaxConfidence {
+		confidence = MaxConfidence
+	}
+
+	if isCorrect {
+		return points * confidence
+	}
+	if sr.EnableConfidenceRisk {
+		points -= sr.ConfidenceRiskPoints * (confidence - MinConfidence)
+	}
+	return points
+}
+
 // CalculatePoints 根据规则计算积分
 func (sr *ScoringRule) CalculatePoints(prediction *Prediction) int {
 	if prediction.Match == nil || !prediction.Match.IsFinished() {
@@ -58,6 +92,9 @@ type CreateScoringRuleRequest struct {
 	CorrectTeamWrongScore   int    `json:"correct_team_wrong_score" validate:"min=0"`
 	WrongTeamCorrectScore   int    `json:"wrong_team_correct_score" validate:"min=0"`
 	WrongTeamWrongScore     int    `json:"wrong_team_wrong_score" validate:"min=0"`
+	EnableConfidence        bool   `json:"enable_confidence"`
+	EnableConfidenceRisk    bool   `json:"enable_confidence_risk"`
+	ConfidenceRiskPoints    int    `json:"confidence_risk_points" validate:"min=0"`
 }
 
 // UpdateScoringRuleRequest 更新积分规则请求
@@ -68,6 +105,9 @@ type UpdateScoringRuleRequest struct {
 	CorrectTeamWrongScore   *int   `json:"correct_team_wrong_score" validate:"omitempty,min=0"`
 	WrongTeamCorrectScore   *int   `json:"wrong_team_correct_score" validate:"omitempty,min=0"`
 	WrongTeamWrongScore     *int   `json:"wrong_team_wrong_score" validate:"omitempty,min=0"`
+	EnableConfidence        *bool  `json:"enable_confidence"`
+	EnableConfidenceRisk    *bool  `json:"enable_confidence_risk"`
+	ConfidenceRiskPoints    *int   `json:"confidence_risk_points" validate:"omitempty,min=0"`
 	IsActive                *bool  `json:"is_active"`
 }
 
@@ -319,3 +319,82 @@ func TestScoringRule_Creation(t *testing.T) {
 		t.Errorf("IsActive = %v, want %v", rule.IsActive, true)
 	}
 }
+
+func TestPrediction_CalculatePointsWithRule_Confidence(t *testing.T) {
+	match := &domain.Match{
+		ID:     1,
+		Status: domain.MatchStatusFinished,
+		Winner: "A",
+		ScoreA: 2,
+		ScoreB: 1,
+	}
+	newPrediction := func(winner string, confidence int) *Prediction {
+		return &Prediction{
+			PredictedWinner: winner,
+			PredictedScoreA: 2,
+			PredictedScoreB: 0,
+			Confidence:      confidence,
+			Match:           match,
+		}
+	}
+	newRule := func() *ScoringRule {
+		return &ScoringRule{
+			CorrectTeamCorrectScore: 50,
+			CorrectTeamWrongScore:   20,
+			WrongTeamCorrectScore:   10,
+			WrongTeamWrongScore:     0,
+			ConfidenceRiskPoints:    15,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		enable      bool
+		risk        bool
+		correctWant [3]int // 信心倍数 1-3 时预测正确的积分
+		wrongWant   [3]int // 信心倍数 1-3 时预测错误的积分
+	}{
+		{name: "默认关闭", correctWant: [3]int{20, 20, 20}, wrongWant: [3]int{0, 0, 0}},
+		{name: "仅风险开关不生效", risk: true, correctWant: [3]int{20, 20, 20}, wrongWant: [3]int{0, 0, 0}},
+		{name: "启用信心倍数", enable: true, correctWant: [3]int{20, 40, 60}, wrongWant: [3]int{0, 0, 0}},
+		{name: "启用信心倍数与风险", enable: true, risk: true, correctWant: [3]int{20, 40, 60}, wrongWant: [3]int{0, -15, -30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := newRule()
+			rule.EnableConfidence = tt.enable
+			rule.EnableConfidenceRisk = tt.risk
+
+			for i := 0; i < 3; i++ {
+				confidence := i + MinConfidence
+				if got := newPrediction("A", confidence).CalculatePointsWithRule(rule); got != tt.correctWant[i] {
+					t.Errorf("信心倍数 %d 预测正确: 积分 = %d, want %d", confidence, got, tt.correctWant[i])
+				}
+				if got := newPrediction("B", confidence).CalculatePointsWithRule(rule); got != tt.wrongWant[i] {
+					t.Errorf("信心倍数 %d 预测错误: 积分 = %d, want %d", confidence, got, tt.wrongWant[i])
+				}
+			}
+		})
+	}
+}
+
+func TestScoringRule_ApplyConfidence_OutOfRange(t *testing.T) {
+	rule := &ScoringRule{EnableConfidence: true, EnableConfidenceRisk: true, ConfidenceRiskPoints: 10}
+
+	// 旧数据未设置信心倍数时按 1 倍计算，超出上限时按上限计算
+	if got := rule.ApplyConfidence(30, 0, true); got != 30 {
+		t.Errorf("信心倍数为0时积分 = %d, want 30", got)
+	}
+	if got := rule.ApplyConfidence(30, 5, true); got != 90 {
+		t.Errorf("信心倍数超出上限时积分 = %d, want 90", got)
+	}
+	if got := rule.ApplyConfidence(0, 5, false); got != -20 {
+		t.Errorf("信心倍数超出上限时扣分 = %d, want -20", got)
+	}
+
+	var nilRule *ScoringRule
+	if got := nilRule.ApplyConfidence(30, 3, true); got != 30 {
+		t.Errorf("无规则时积分不应调整, got %d", got)
+	}
+}
@@ -2,6 +2,7 @@ package prediction
 
 import (
 	"context"
+	"io"
 
 	"backend-go/internal/core/domain/match"
 )
@@ -12,6 +13,8 @@ type CreatePredictionRequest struct {
 	PredictedWinner match.Winner `json:"predictedWinner" validate:"required,oneof=A B DRAW"`
 	PredictedScoreA int          `json:"predictedScoreA" validate:"min=0"`
 	PredictedScoreB int          `json:"predictedScoreB" validate:"min=0"`
+	// Confidence 信心倍数，不传时为 1
+	Confidence *int `json:"confidence,omitempty" validate:"omitempty,min=1,max=3"`
 }
 
 // UpdatePredictionRequest 更新预测请求
@@ -19,6 +22,48 @@ type UpdatePredictionRequest struct {
 	PredictedWinner match.Winner `json:"predictedWinner" validate:"required,oneof=A B DRAW"`
 	PredictedScoreA int          `json:"predictedScoreA" validate:"min=0"`
 	PredictedScoreB int          `json:"predictedScoreB" validate:"min=0"`
+	// Confidence 信心倍数，不传时保持不变
+	Confidence *int `json:"confidence,omitempty" validate:"omitempty,min=1,max=3"`
+	// Version 客户端读取预测时的版本号，与当前版本不一致时返回冲突
+	Version *int `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+// MaxBatchVotes 单次批量投票的最大条数
+const MaxBatchVotes = 50
+
+// VoteRequest 批量投票中的单条投票
+type VoteRequest struct {
+	PredictionID uint `json:"predictionId" example:"12"`
+}
+
+// CastVotesRequest 批量投票请求
+type CastVotesRequest struct {
+	Votes []VoteRequest `json:"votes" binding:"required,min=1,max=50"`
+}
+
+// VoteStatus 单条投票的处理结果
+type VoteStatus string
+
+const (
+	VoteStatusAccepted     VoteStatus = "accepted"      // 投票成功
+	VoteStatusSelfVote     VoteStatus = "self_vote"     // 不能给自己的预测投票
+	VoteStatusDuplicate    VoteStatus = "duplicate"     // 已投过票或批次内重复
+	VoteStatusNotFound     VoteStatus = "not_found"     // 预测不存在
+	VoteStatusMatchStarted VoteStatus = "match_started" // 比赛已开始
+)
+
+// VoteOutcome 单条投票结果，顺序与请求一致
+type VoteOutcome struct {
+	PredictionID uint       `json:"predictionId"`
+	Status       VoteStatus `json:"status"`
+	VoteCount    int        `json:"voteCount,omitempty"` // 投票成功后的预测票数
+}
+
+// BatchVoteResult 批量投票结果
+type BatchVoteResult struct {
+	Accepted int           `json:"accepted"`
+	Rejected int           `json:"rejected"`
+	Results  []VoteOutcome `json:"results"`
 }
 
 // Service 预测服务接口
@@ -41,6 +86,9 @@ type Service interface {
 	// VotePrediction 投票支持预测
 	VotePrediction(ctx context.Context, userID uint, predictionID uint) error
 
+	// CastVotes 批量投票，逐条校验后只写入有效投票，返回每条的处理结果
+	CastVotes(ctx context.Context, userID uint, votes []VoteRequest) (*BatchVoteResult, error)
+
 	// UnvotePrediction 取消投票
 	UnvotePrediction(ctx context.Context, userID uint, predictionID uint) error
 
@@ -55,4 +103,10 @@ type Service interface {
 
 	// CalculatePointsWithCustomRule 使用自定义规则计算积分
 	CalculatePointsWithCustomRule(ctx context.Context, matchID uint, ruleID *uint) error
+
+	// GetMatchConsensus 获取比赛的社区预测共识
+	GetMatchConsensus(ctx context.Context, matchID uint) (*MatchConsensus, error)
+
+	// ExportMatchPredictions 以 CSV 格式流式导出已结束比赛的全部预测，调用方负责权限校验
+	ExportMatchPredictions(ctx context.Context, matchID uint, w io.Writer) error
 }
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// UserArchivedStats 数据保留任务清理前汇总的用户预测统计
+//
+// 比赛及其预测、投票被清理后，统计计数在数据库中的真实值为现存数据与该汇总之和。
+type UserArchivedStats struct {
+	UserID             uint      `gorm:"column:user_id;primaryKey" json:"userId"`
+	Predictions        int64     `gorm:"column:predictions;not null;default:0" json:"predictions"`
+	CorrectPredictions int64     `gorm:"column:correct_predictions;not null;default:0" json:"correctPredictions"`
+	EarnedPoints       int64     `gorm:"column:earned_points;not null;default:0" json:"earnedPoints"`
+	VotesCast          int64     `gorm:"column:votes_cast;not null;default:0" json:"votesCast"`
+	UpdatedAt          time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (UserArchivedStats) TableName() string {
+	return "user_archived_stats"
+}
@@ -86,6 +86,11 @@ type ScoringRule struct {
 	ModifyPenaltyPoints int  `json:"modify_penalty_points" gorm:"default:2"`     // 每次修改扣分
 	MaxModifyPenalty    int  `json:"max_modify_penalty" gorm:"default:6"`        // 最大修改惩罚
 
+	// 信心倍数开关，结算时由 prediction.ScoringRule 读取同一张表的这些列
+	EnableConfidence     bool `json:"enable_confidence" gorm:"default:false"`      // 启用信心倍数，得分乘以预测的信心倍数
+	EnableConfidenceRisk bool `json:"enable_confidence_risk" gorm:"default:false"` // 启用信心风险，预测错误时按加注倍数扣分
+	ConfidenceRiskPoints int  `json:"confidence_risk_points" gorm:"default:0"`     // 每加注一倍在预测错误时扣除的积分
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
@@ -0,0 +1,85 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// API 密钥的权限范围
+const (
+	APIScopeMatchesRead  = "matches:read"  // 读取比赛数据
+	APIScopeMatchesWrite = "matches:write" // 推送比赛结果
+)
+
+// APIScopes 可授予 API 密钥的全部权限范围
+var APIScopes = []string{APIScopeMatchesRead, APIScopeMatchesWrite}
+
+// IsValidAPIScope 检查权限范围是否有效
+func IsValidAPIScope(scope string) bool {
+	for _, s := range APIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey 服务间调用（如推送比赛结果的数据源）使用的 API 密钥
+//
+// 明文密钥只在创建时返回一次，数据库中只保存 SHA-256 哈希与用于辨认的前缀。
+type APIKey struct {
+	ID         uint                        `json:"id" gorm:"primaryKey"`
+	Name       string                      `json:"name" gorm:"size:100;not null"`
+	Prefix     string                      `json:"prefix" gorm:"size:16;not null"`
+	KeyHash    string                      `json:"-" gorm:"uniqueIndex;size:64;not null"`
+	Scopes     datatypes.JSONSlice[string] `json:"scopes"`
+	IsActive   bool                        `json:"is_active" gorm:"default:true"`
+	CreatedBy  uint                        `json:"created_by"`
+	LastUsedAt *time.Time                  `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time                  `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time                   `json:"created_at"`
+	UpdatedAt  time.Time                   `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// APIKeyContext 通过 API 密钥认证的调用方
+type APIKeyContext struct {
+	KeyID     uint     `json:"key_id"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	CreatedBy uint     `json:"created_by"` // 创建密钥的管理员，服务间调用的审计记在其名下
+}
+
+// HasScope 检查调用方是否拥有权限范围
+func (c *APIKeyContext) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyRepository API 密钥仓储接口
+type APIKeyRepository interface {
+	// Create 保存新密钥
+	Create(ctx context.Context, key *APIKey) error
+
+	// GetByHash 根据密钥哈希获取密钥，不存在时返回 domain.ErrAPIKeyNotFound
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	// List 获取全部密钥（含已吊销），按创建时间倒序
+	List(ctx context.Context) ([]*APIKey, error)
+
+	// Revoke 吊销密钥，不存在或已吊销时返回 domain.ErrAPIKeyNotFound
+	Revoke(ctx context.Context, id uint, revokedAt time.Time) error
+
+	// TouchLastUsed 更新最近使用时间
+	TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error
+}
@@ -0,0 +1,51 @@
+package user
+
+import (
+	"time"
+)
+
+// 通知偏好默认值与提醒提前量范围（分钟）
+const (
+	DefaultReminderLeadMinutes = 30
+	MinReminderLeadMinutes     = 5
+	MaxReminderLeadMinutes     = 1440
+)
+
+// NotificationPreferences 用户通知偏好
+//
+// 布尔字段不使用 gorm default 标签，否则写入 false 时会被数据库默认值覆盖。
+type NotificationPreferences struct {
+	UserID              uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	EmailEnabled        bool      `json:"email_enabled" gorm:"not null"`         // 接收邮件（比赛提醒、密码重置）
+	ReminderLeadMinutes int       `json:"reminder_lead_minutes" gorm:"not null"` // 比赛开始前多少分钟提醒
+	PushEnabled         bool      `json:"push_enabled" gorm:"not null"`          // 接收实时推送
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (NotificationPreferences) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreferences 未设置过偏好的用户使用的默认值（全部开启）
+func DefaultNotificationPreferences(userID uint) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:              userID,
+		EmailEnabled:        true,
+		ReminderLeadMinutes: DefaultReminderLeadMinutes,
+		PushEnabled:         true,
+	}
+}
+
+// ReminderLead 比赛提醒提前量
+func (p *NotificationPreferences) ReminderLead() time.Duration {
+	return time.Duration(p.ReminderLeadMinutes) * time.Minute
+}
+
+// UpdateNotificationPreferencesRequest 更新通知偏好请求，未提供的字段保持不变
+type UpdateNotificationPreferencesRequest struct {
+	EmailEnabled        *bool `json:"email_enabled,omitempty"`
+	ReminderLeadMinutes *int  `json:"reminder_lead_minutes,omitempty" validate:"omitempty,min=5,max=1440"`
+	PushEnabled         *bool `json:"push_enabled,omitempty"`
+}
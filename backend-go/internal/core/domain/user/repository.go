@@ -41,4 +41,10 @@ type Repository interface {
 
 	// ChangePassword 修改用户密码
 	ChangePassword(ctx context.Context, userID uint, newPassword string) error
+
+	// GetNotificationPreferences 获取通知偏好，未设置过时返回默认值
+	GetNotificationPreferences(ctx context.Context, userID uint) (*NotificationPreferences, error)
+
+	// SaveNotificationPreferences 保存通知偏好（不存在时创建）
+	SaveNotificationPreferences(ctx context.Context, prefs *NotificationPreferences) error
 }
@@ -32,6 +32,18 @@ type AuthResponse struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
+// TokenIdentity 访问令牌对应的身份
+type TokenIdentity struct {
+	User *User
+	// ImpersonatorID 管理员代入该用户时为管理员ID，否则为 0
+	ImpersonatorID uint
+}
+
+// IsImpersonated 是否为管理员代入的身份
+func (i *TokenIdentity) IsImpersonated() bool {
+	return i.ImpersonatorID != 0
+}
+
 // Service 用户服务接口
 type Service interface {
 	// Register 用户注册
@@ -55,9 +67,18 @@ type Service interface {
 	// ValidateToken 验证令牌
 	ValidateToken(ctx context.Context, token string) (*User, error)
 
+	// Authenticate 验证访问令牌并返回身份，包含代入信息
+	Authenticate(ctx context.Context, token string) (*TokenIdentity, error)
+
 	// ChangePassword 重置/修改用户密码（管理员场景）
 	ChangePassword(ctx context.Context, userID uint, newPassword string) error
 
 	// ChangePasswordWithVerify 校验当前密码后修改（用户自助）
 	ChangePasswordWithVerify(ctx context.Context, userID uint, currentPassword, newPassword string) error
+
+	// GetPreferences 获取通知偏好
+	GetPreferences(ctx context.Context, userID uint) (*NotificationPreferences, error)
+
+	// UpdatePreferences 更新通知偏好
+	UpdatePreferences(ctx context.Context, userID uint, req *UpdateNotificationPreferencesRequest) (*NotificationPreferences, error)
 }
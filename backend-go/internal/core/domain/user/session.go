@@ -0,0 +1,14 @@
+package user
+
+import "time"
+
+// Session 一次登录产生的会话，ID 为该次登录签发令牌的 jti，刷新令牌后保持不变
+type Session struct {
+	ID        string    `json:"jti"`
+	UserID    uint      `json:"userId"`
+	Device    string    `json:"device"` // 登录时的 User-Agent
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	ExpiresAt time.Time `json:"expiresAt"` // 刷新令牌过期时间，之后会话自动失效
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
 
 	"backend-go/internal/core/domain/admin"
 )
@@ -14,6 +15,7 @@ type AdminService interface {
 	DeleteAdmin(ctx context.Context, userID uint) error
 	GetAdmin(ctx context.Context, userID uint) (*admin.AdminUser, error)
 	ListAdmins(ctx context.Context, req *ListAdminsRequest) (*ListAdminsResponse, error)
+	CleanupOrphanedAdmins(ctx context.Context) (int, error)
 
 	// 权限检查
 	HasPermission(ctx context.Context, userID uint, permission string) (bool, error)
@@ -24,11 +26,17 @@ type AdminService interface {
 	GrantPermissions(ctx context.Context, userID uint, permissions []string) error
 	RevokePermissions(ctx context.Context, userID uint, permissions []string) error
 	GrantSportAccess(ctx context.Context, userID uint, sportTypeIDs []uint) error
+	GrantSportAccessBulk(ctx context.Context, userIDs []uint, sportTypeIDs []uint) (BulkResult, error)
 	RevokeSportAccess(ctx context.Context, userID uint, sportTypeIDs []uint) error
 
 	// 权限列表
 	ListPermissions(ctx context.Context) ([]*admin.AdminPermission, error)
 	GetPermission(ctx context.Context, code string) (*admin.AdminPermission, error)
+
+	// 保存的查询视图（按管理员隔离）
+	SaveView(ctx context.Context, adminUserID uint, req *SaveViewRequest) (*admin.AdminSavedView, error)
+	ListViews(ctx context.Context, adminUserID uint, resource string) ([]*admin.AdminSavedView, error)
+	DeleteView(ctx context.Context, adminUserID uint, viewID uint) error
 }
 
 // AdminAuditService 管理员审计服务接口
@@ -78,6 +86,20 @@ type ListAdminsResponse struct {
 	TotalPages int                  `json:"total_pages"`
 }
 
+// BulkResult 批量操作结果，逐个管理员记录成功或失败
+type BulkResult struct {
+	Results   []BulkItemResult `json:"results"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+}
+
+// BulkItemResult 单个管理员的批量操作结果
+type BulkItemResult struct {
+	UserID  uint   `json:"user_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
 // AdminUserWithUser 包含用户信息的管理员
 type AdminUserWithUser struct {
 	*admin.AdminUser
@@ -85,6 +107,7 @@ type AdminUserWithUser struct {
 	Email    string `json:"email"`
 	Nickname string `json:"nickname"`
 	Avatar   string `json:"avatar"`
+	Orphaned bool   `json:"orphaned"` // 对应的用户已被删除
 }
 
 // LogActionRequest 记录操作请求
@@ -115,6 +138,15 @@ type ListAuditLogsRequest struct {
 	Status      *admin.AuditStatus  `json:"status,omitempty" form:"status"`
 	StartTime   *string             `json:"start_time,omitempty" form:"start_time"`
 	EndTime     *string             `json:"end_time,omitempty" form:"end_time"`
+	SortBy      string              `json:"sort_by,omitempty" form:"sort_by"`       // created_at（默认）、duration、action、resource
+	SortOrder   string              `json:"sort_order,omitempty" form:"sort_order"` // asc 或 desc（默认）
+}
+
+// SaveViewRequest 保存查询视图请求，同一管理员同一列表下同名视图会被覆盖
+type SaveViewRequest struct {
+	Name     string          `json:"name" binding:"required,max=100"`
+	Resource string          `json:"resource,omitempty"` // 默认 audit_logs
+	Params   json.RawMessage `json:"params" binding:"required"` // 过滤与排序条件，字段与对应列表接口的查询参数一致
 }
 
 // ListAuditLogsResponse 审计日志列表响应
@@ -131,6 +163,8 @@ type AuditStatsRequest struct {
 	StartTime   *string `json:"start_time,omitempty" form:"start_time"`
 	EndTime     *string `json:"end_time,omitempty" form:"end_time"`
 	AdminUserID *uint   `json:"admin_user_id,omitempty" form:"admin_user_id"`
+	Action      string  `json:"action,omitempty" form:"action"`
+	Resource    string  `json:"resource,omitempty" form:"resource"`
 }
 
 // AuditStatsResponse 审计统计响应
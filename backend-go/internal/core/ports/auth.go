@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/shared/jwt"
+)
+
+// AuthService 账户认证服务接口
+type AuthService interface {
+	// RequestPasswordReset 申请重置密码，无论邮箱是否存在都返回成功，避免邮箱枚举
+	RequestPasswordReset(ctx context.Context, email string) error
+
+	// ResetPassword 使用重置令牌设置新密码
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	// Impersonate 超级管理员以目标用户身份签发限时访问令牌，用于复现用户视角
+	//
+	// 令牌同时携带管理员ID与目标用户ID，不签发刷新令牌，且不能执行管理员操作。
+	Impersonate(ctx context.Context, adminID, targetUserID uint) (*jwt.TokenPair, error)
+
+	// RecordSession 登录成功后记录会话，会话ID与过期时间取自刷新令牌
+	RecordSession(ctx context.Context, refreshToken, device, ip string) (*user.Session, error)
+
+	// ListSessions 列出用户当前有效的登录会话
+	ListSessions(ctx context.Context, userID uint) ([]*user.Session, error)
+
+	// RevokeSession 吊销用户的某个会话，该会话签发的令牌随即失效
+	RevokeSession(ctx context.Context, userID uint, jti string) error
+
+	// CreateAPIKey 为服务间调用创建 API 密钥，明文密钥只在此时返回一次
+	CreateAPIKey(ctx context.Context, adminID uint, name string, scopes []string) (*user.APIKey, string, error)
+
+	// ListAPIKeys 列出全部 API 密钥（不含明文与哈希）
+	ListAPIKeys(ctx context.Context) ([]*user.APIKey, error)
+
+	// RevokeAPIKey 吊销 API 密钥，之后使用该密钥的请求立即被拒绝
+	RevokeAPIKey(ctx context.Context, id uint) error
+
+	// ValidateAPIKey 校验 X-API-Key 请求头中的密钥，返回调用方及其权限范围
+	ValidateAPIKey(ctx context.Context, key string) (*user.APIKeyContext, error)
+}
+
+// EmailSender 邮件发送接口
+type EmailSender interface {
+	// SendEmail 发送纯文本邮件
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
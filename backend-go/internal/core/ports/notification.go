@@ -0,0 +1,12 @@
+package ports
+
+// NotificationPusher 向单个用户推送实时通知
+type NotificationPusher interface {
+	// PushToUser 推送通知，返回收到通知的连接数
+	PushToUser(userID uint, event string, data interface{}) int
+}
+
+// 实时通知事件
+const (
+	NotificationMatchReminder = "match.reminder"
+)
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"backend-go/internal/core/domain/sport"
 	"backend-go/internal/core/types"
@@ -27,6 +28,15 @@ type ScoringRuleService interface {
 	
 	// 批量重算
 	RecalculateScores(ctx context.Context, sportTypeID uint, ruleID uint) (*RecalculateResult, error)
+
+	// 规则模拟：按候选规则重算已结束比赛的积分并预测排名变化，不写入任何数据
+	SimulateRule(ctx context.Context, ruleID uint, tournament string) (*SimulationResult, error)
+}
+
+// ScoringSimulationRepository 规则模拟使用的只读仓储接口
+type ScoringSimulationRepository interface {
+	// ListSettledPredictions 获取指定运动类型已结束比赛的预测，tournament 为空或 GLOBAL 时不按赛事过滤
+	ListSettledPredictions(ctx context.Context, sportTypeID uint, tournament string) ([]*SettledPrediction, error)
 }
 
 // ScoringRuleRepository 积分规则仓储接口
@@ -69,6 +79,11 @@ type CreateScoringRuleRequest struct {
 	EnableModifyPenalty bool `json:"enable_modify_penalty"`
 	ModifyPenaltyPoints int  `json:"modify_penalty_points" validate:"min=0,max=100"`
 	MaxModifyPenalty    int  `json:"max_modify_penalty" validate:"min=0,max=1000"`
+
+	// 信心倍数开关
+	EnableConfidence     bool `json:"enable_confidence"`
+	EnableConfidenceRisk bool `json:"enable_confidence_risk"`
+	ConfidenceRiskPoints int  `json:"confidence_risk_points" validate:"min=0,max=100"`
 }
 
 // UpdateScoringRuleRequest 更新积分规则请求
@@ -95,6 +110,11 @@ type UpdateScoringRuleRequest struct {
 	EnableModifyPenalty *bool `json:"enable_modify_penalty"`
 	ModifyPenaltyPoints *int  `json:"modify_penalty_points" validate:"omitempty,min=0,max=100"`
 	MaxModifyPenalty    *int  `json:"max_modify_penalty" validate:"omitempty,min=0,max=1000"`
+
+	// 信心倍数开关
+	EnableConfidence     *bool `json:"enable_confidence"`
+	EnableConfidenceRisk *bool `json:"enable_confidence_risk"`
+	ConfidenceRiskPoints *int  `json:"confidence_risk_points" validate:"omitempty,min=0,max=100"`
 }
 
 // ListScoringRulesRequest 积分规则列表请求
@@ -102,6 +122,7 @@ type ListScoringRulesRequest struct {
 	SportTypeID *uint  `json:"sport_type_id"`
 	IsActive    *bool  `json:"is_active"`
 	OrderBy     string `json:"order_by" validate:"omitempty,oneof=name created_at"`
+	SortOrder   string `json:"sort_order" validate:"omitempty,oneof=asc desc"` // 排序方向，为空时升序
 	Page        int    `json:"page" validate:"min=1"`
 	PageSize    int    `json:"page_size" validate:"min=1,max=100"`
 }
@@ -123,6 +144,45 @@ type RecalculateResult struct {
 	TotalPointsChanged  int `json:"total_points_changed"` // 总积分变化
 }
 
+// SettledPrediction 已结束比赛上的预测及其当前得分
+type SettledPrediction struct {
+	PredictionID      uint
+	UserID            uint
+	Username          string
+	Nickname          string
+	MatchID           uint
+	MatchStartTime    time.Time
+	PredictedWinner   string
+	IsCorrect         bool
+	ModificationCount int
+	VoteCount         int
+	EarnedPoints      int
+	CreatedAt         time.Time
+}
+
+// SimulationResult 积分规则模拟结果
+type SimulationResult struct {
+	RuleID           uint                 `json:"rule_id"`
+	SportTypeID      uint                 `json:"sport_type_id"`
+	Tournament       string               `json:"tournament"`
+	TotalPredictions int                  `json:"total_predictions"` // 参与重算的预测数
+	PointsChanged    int                  `json:"points_changed"`    // 积分变化的绝对值之和
+	Standings        []*SimulatedStanding `json:"standings"`         // 按模拟排名排序
+}
+
+// SimulatedStanding 单个用户的当前与模拟排名
+type SimulatedStanding struct {
+	UserID          uint   `json:"user_id"`
+	Username        string `json:"username"`
+	Nickname        string `json:"nickname"`
+	CurrentPoints   int    `json:"current_points"`
+	ProjectedPoints int    `json:"projected_points"`
+	PointsDelta     int    `json:"points_delta"`
+	CurrentRank     int    `json:"current_rank"`
+	ProjectedRank   int    `json:"projected_rank"`
+	RankDelta       int    `json:"rank_delta"` // 正数表示名次上升
+}
+
 // ListScoringRulesOptions 积分规则列表查询选项（仓储层使用）
 type ListScoringRulesOptions struct {
 	SportTypeID *uint
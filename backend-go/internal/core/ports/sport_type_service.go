@@ -37,6 +37,9 @@ type SportTypeRepository interface {
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, options *ListSportTypesOptions) ([]*sport.SportType, error)
 	Count(ctx context.Context, options *ListSportTypesOptions) (int64, error)
+	ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error) // 名称比较不区分大小写
+	ExistsByCode(ctx context.Context, code string, excludeID uint) (bool, error)
+	CountReferences(ctx context.Context, id uint) (*SportTypeReferences, error)
 	
 	// 配置相关
 	CreateConfiguration(ctx context.Context, config *sport.SportConfiguration) error
@@ -72,11 +75,12 @@ type UpdateSportTypeRequest struct {
 
 // ListSportTypesRequest 运动类型列表请求
 type ListSportTypesRequest struct {
-	Category string `json:"category" validate:"omitempty,oneof=esports traditional"`
-	IsActive *bool  `json:"is_active"`
-	OrderBy  string `json:"order_by" validate:"omitempty,oneof=name code sort_order created_at"`
-	Page     int    `json:"page" validate:"min=1"`
-	PageSize int    `json:"page_size" validate:"min=1,max=100"`
+	Category  string `json:"category" validate:"omitempty,oneof=esports traditional"`
+	IsActive  *bool  `json:"is_active"`
+	OrderBy   string `json:"order_by" validate:"omitempty,oneof=name code sort_order created_at"`
+	SortOrder string `json:"sort_order" validate:"omitempty,oneof=asc desc"` // 排序方向，为空时升序
+	Page      int    `json:"page" validate:"min=1"`
+	PageSize  int    `json:"page_size" validate:"min=1,max=100"`
 }
 
 // ListSportTypesResponse 运动类型列表响应
@@ -124,6 +128,12 @@ type SportTypeStats struct {
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// SportTypeReferences 引用运动类型的数据统计
+type SportTypeReferences struct {
+	MatchCount       int64 `json:"match_count"`
+	ScopedAdminCount int64 `json:"scoped_admin_count"`
+}
+
 // ListSportTypesOptions 运动类型列表查询选项（仓储层使用）
 type ListSportTypesOptions struct {
 	Category string
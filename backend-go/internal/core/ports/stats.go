@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+)
+
+// StatsCountRepository 统计计数在数据库中的真实值，用于校正 Redis 计数器
+type StatsCountRepository interface {
+	// ListUserIDs 按ID升序返回 afterID 之后最多 limit 个用户ID，limit<=0 表示全部
+	ListUserIDs(ctx context.Context, afterID uint, limit int) ([]uint, error)
+	// ListPredictionIDs 按ID升序返回 afterID 之后最多 limit 个预测ID，limit<=0 表示全部
+	ListPredictionIDs(ctx context.Context, afterID uint, limit int) ([]uint, error)
+	// CountPredictionsByUsers 统计用户创建的预测数
+	CountPredictionsByUsers(ctx context.Context, userIDs []uint) (map[uint]int64, error)
+	// CountVotesByVoters 统计用户投出的票数
+	CountVotesByVoters(ctx context.Context, userIDs []uint) (map[uint]int64, error)
+	// CountVotesByPredictions 统计预测获得的票数
+	CountVotesByPredictions(ctx context.Context, predictionIDs []uint) (map[uint]int64, error)
+}
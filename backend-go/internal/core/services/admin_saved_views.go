@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/ports"
+)
+
+// SaveView 保存管理员的查询视图，同一列表下同名视图覆盖原有条件
+func (s *adminService) SaveView(ctx context.Context, adminUserID uint, req *ports.SaveViewRequest) (*admin.AdminSavedView, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: view name is required", domain.ErrInvalidInput)
+	}
+	resource := req.Resource
+	if resource == "" {
+		resource = admin.SavedViewResourceAuditLogs
+	}
+	params, err := normalizeViewParams(resource, req.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	var view admin.AdminSavedView
+	err = s.db.WithContext(ctx).
+		Where("admin_user_id = ? AND resource = ? AND name = ?", adminUserID, resource, name).
+		First(&view).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		view = admin.AdminSavedView{AdminUserID: adminUserID, Resource: resource, Name: name, Params: params}
+		if err := s.db.WithContext(ctx).Create(&view).Error; err != nil {
+			return nil, fmt.Errorf("failed to create saved view: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	default:
+		view.Params = params
+		view.UpdatedAt = time.Now()
+		if err := s.db.WithContext(ctx).Save(&view).Error; err != nil {
+			return nil, fmt.Errorf("failed to update saved view: %w", err)
+		}
+	}
+
+	return &view, nil
+}
+
+// ListViews 获取管理员保存的查询视图，resource 为空时返回全部列表的视图
+func (s *adminService) ListViews(ctx context.Context, adminUserID uint, resource string) ([]*admin.AdminSavedView, error) {
+	query := s.db.WithContext(ctx).Where("admin_user_id = ?", adminUserID)
+	if resource != "" {
+		query = query.Where("resource = ?", resource)
+	}
+
+	views := []*admin.AdminSavedView{}
+	if err := query.Order("resource ASC, name ASC").Find(&views).Error; err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	return views, nil
+}
+
+// DeleteView 删除管理员自己的查询视图
+func (s *adminService) DeleteView(ctx context.Context, adminUserID uint, viewID uint) error {
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND admin_user_id = ?", viewID, adminUserID).
+		Delete(&admin.AdminSavedView{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete saved view: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: saved view %d", domain.ErrResourceNotFound, viewID)
+	}
+	return nil
+}
+
+// normalizeViewParams 按列表的查询参数校验视图条件，去掉分页页码后重新序列化
+func normalizeViewParams(resource string, raw json.RawMessage) (datatypes.JSON, error) {
+	switch resource {
+	case admin.SavedViewResourceAuditLogs:
+		var params ports.ListAuditLogsRequest
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&params); err != nil {
+			return nil, fmt.Errorf("%w: invalid view params: %v", domain.ErrInvalidInput, err)
+		}
+		if _, _, err := parseAuditTimeRange(params.StartTime, params.EndTime); err != nil {
+			return nil, err
+		}
+		if _, err := auditLogOrder(params.SortBy, params.SortOrder); err != nil {
+			return nil, err
+		}
+		// 视图只保存过滤与排序条件，打开视图时总是从第一页开始
+		params.Page = 0
+
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		return datatypes.JSON(data), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported view resource %q", domain.ErrInvalidInput, resource)
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/ports"
+)
+
+func TestAdminService_SavedViews(t *testing.T) {
+	ctx := context.Background()
+	db := newAdminTestDB(t)
+	if err := db.AutoMigrate(&admin.AdminSavedView{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	seedAuditLogs(t, db)
+	service := NewAdminService(db, nil)
+	auditService := NewAdminAuditService(db)
+
+	saved, err := service.SaveView(ctx, 1, &ports.SaveViewRequest{
+		Name:   "我的比赛修改",
+		Params: json.RawMessage(`{"page":3,"admin_user_id":1,"action":"match.update","resource":"match","sort_by":"duration","sort_order":"asc"}`),
+	})
+	if err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+	if saved.Resource != admin.SavedViewResourceAuditLogs {
+		t.Errorf("resource = %q, want 默认 %q", saved.Resource, admin.SavedViewResourceAuditLogs)
+	}
+	if _, err := service.SaveView(ctx, 2, &ports.SaveViewRequest{Name: "失败操作", Params: json.RawMessage(`{"status":2}`)}); err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+
+	// 只列出自己的视图
+	views, err := service.ListViews(ctx, 1, admin.SavedViewResourceAuditLogs)
+	if err != nil {
+		t.Fatalf("ListViews() error = %v", err)
+	}
+	if len(views) != 1 || views[0].ID != saved.ID || views[0].Name != "我的比赛修改" {
+		t.Fatalf("views = %+v, want 仅 %q", views, "我的比赛修改")
+	}
+
+	// 按视图中的条件查询审计日志，页码不会被保存
+	var req ports.ListAuditLogsRequest
+	if err := json.Unmarshal(views[0].Params, &req); err != nil {
+		t.Fatalf("解析视图条件失败: %v", err)
+	}
+	if req.Page != 0 {
+		t.Errorf("保存的页码 = %d, want 0", req.Page)
+	}
+	result, err := auditService.ListAuditLogs(ctx, &req)
+	if err != nil {
+		t.Fatalf("ListAuditLogs() error = %v", err)
+	}
+	var durations []int64
+	for _, log := range result.Logs {
+		durations = append(durations, log.Duration)
+	}
+	if len(durations) != 3 || durations[0] != 10 || durations[1] != 20 || durations[2] != 60 {
+		t.Errorf("按视图查询的耗时 = %v, want [10 20 60]", durations)
+	}
+
+	// 同名视图覆盖原有条件
+	updated, err := service.SaveView(ctx, 1, &ports.SaveViewRequest{Name: "我的比赛修改", Params: json.RawMessage(`{"action":"match.delete"}`)})
+	if err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+	if updated.ID != saved.ID {
+		t.Errorf("同名视图 ID = %d, want 覆盖 %d", updated.ID, saved.ID)
+	}
+
+	// 不能删除其他管理员的视图
+	if err := service.DeleteView(ctx, 2, saved.ID); !errors.Is(err, domain.ErrResourceNotFound) {
+		t.Errorf("删除他人视图 err = %v, want ErrResourceNotFound", err)
+	}
+	if err := service.DeleteView(ctx, 1, saved.ID); err != nil {
+		t.Fatalf("DeleteView() error = %v", err)
+	}
+	if views, _ := service.ListViews(ctx, 1, ""); len(views) != 0 {
+		t.Errorf("删除后仍有视图: %+v", views)
+	}
+}
+
+func TestAdminService_SaveViewRejectsInvalidParams(t *testing.T) {
+	db := newAdminTestDB(t)
+	if err := db.AutoMigrate(&admin.AdminSavedView{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	service := NewAdminService(db, nil)
+
+	tests := []struct {
+		name string
+		req  ports.SaveViewRequest
+	}{
+		{"空名称", ports.SaveViewRequest{Name: "  ", Params: json.RawMessage(`{}`)}},
+		{"未知列表", ports.SaveViewRequest{Name: "v", Resource: "matches", Params: json.RawMessage(`{}`)}},
+		{"未知字段", ports.SaveViewRequest{Name: "v", Params: json.RawMessage(`{"actor":"root"}`)}},
+		{"排序字段", ports.SaveViewRequest{Name: "v", Params: json.RawMessage(`{"sort_by":"ip_address"}`)}},
+		{"时间格式", ports.SaveViewRequest{Name: "v", Params: json.RawMessage(`{"start_time":"yesterday"}`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.SaveView(context.Background(), 1, &tt.req); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("err = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
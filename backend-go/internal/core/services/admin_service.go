@@ -4,26 +4,38 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"gorm.io/datatypes"
 
+	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/admin"
 	"backend-go/internal/core/domain/user"
 	"backend-go/internal/core/ports"
+	"backend-go/pkg/ctxkeys"
 	"backend-go/pkg/database"
 )
 
+// bulkSportAccessPath 批量授予运动类型访问权限的接口路径，用于审计记录
+const bulkSportAccessPath = "/api/v1/admin/admins/sport-access/bulk"
+
 // adminService 管理员服务实现
 type adminService struct {
-	db *database.DB
+	db     *database.DB
+	logger *logrus.Logger
 }
 
 // NewAdminService 创建管理员服务实例
-func NewAdminService(db *database.DB) ports.AdminService {
+func NewAdminService(db *database.DB, logger *logrus.Logger) ports.AdminService {
+	if logger == nil {
+		logger = logrus.New()
+	}
 	return &adminService{
-		db: db,
+		db:     db,
+		logger: logger,
 	}
 }
 
@@ -177,6 +189,46 @@ func (s *adminService) DeleteAdmin(ctx context.Context, userID uint) error {
 	})
 }
 
+// CleanupOrphanedAdmins 删除用户已不存在的管理员记录及其权限关联，返回删除数量
+func (s *adminService) CleanupOrphanedAdmins(ctx context.Context) (int, error) {
+	var orphans []admin.AdminUser
+	if err := s.db.WithContext(ctx).
+		Table("admin_users").
+		Select("admin_users.*").
+		Joins("LEFT JOIN users ON admin_users.user_id = users.id").
+		Where("users.id IS NULL").
+		Find(&orphans).Error; err != nil {
+		return 0, fmt.Errorf("failed to find orphaned admins: %w", err)
+	}
+	if len(orphans) == 0 {
+		return 0, nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range orphans {
+			orphan := &orphans[i]
+			if err := tx.WithContext(ctx).Model(orphan).Association("Permissions").Clear(); err != nil {
+				return fmt.Errorf("failed to clear permissions: %w", err)
+			}
+			if err := tx.WithContext(ctx).Model(orphan).Association("SportTypes").Clear(); err != nil {
+				return fmt.Errorf("failed to clear sport access: %w", err)
+			}
+			if err := tx.WithContext(ctx).Delete(orphan).Error; err != nil {
+				return fmt.Errorf("failed to delete admin: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, orphan := range orphans {
+		s.logger.WithField("user_id", orphan.UserID).Warn("Removed orphaned admin record")
+	}
+	return len(orphans), nil
+}
+
 // GetAdmin 获取管理员信息
 func (s *adminService) GetAdmin(ctx context.Context, userID uint) (*admin.AdminUser, error) {
 	var adminUser admin.AdminUser
@@ -205,7 +257,7 @@ func (s *adminService) ListAdmins(ctx context.Context, req *ports.ListAdminsRequ
 
 	query := s.db.WithContext(ctx).
 		Table("admin_users").
-		Select("admin_users.*, users.username, users.email, users.nickname, users.avatar").
+		Select("admin_users.*, users.id AS linked_user_id, users.username, users.email, users.nickname, users.avatar").
 		Joins("LEFT JOIN users ON admin_users.user_id = users.id")
 
 	// 添加过滤条件
@@ -216,8 +268,9 @@ func (s *adminService) ListAdmins(ctx context.Context, req *ports.ListAdminsRequ
 		query = query.Where("admin_users.is_active = ?", *req.IsActive)
 	}
 	if req.Search != "" {
-		query = query.Where("users.username LIKE ? OR users.email LIKE ? OR users.nickname LIKE ?",
-			"%"+req.Search+"%", "%"+req.Search+"%", "%"+req.Search+"%")
+		pattern := database.ContainsPattern(req.Search)
+		query = query.Where("users.username LIKE ? ESCAPE '!' OR users.email LIKE ? ESCAPE '!' OR users.nickname LIKE ? ESCAPE '!'",
+			pattern, pattern, pattern)
 	}
 
 	// 获取总数
@@ -233,6 +286,9 @@ func (s *adminService) ListAdmins(ctx context.Context, req *ports.ListAdminsRequ
 		Email    string `json:"email"`
 		Nickname string `json:"nickname"`
 		Avatar   string `json:"avatar"`
+
+		// 用户已被删除时为空
+		LinkedUserID *uint
 	}
 
 	offset := (req.Page - 1) * req.PageSize
@@ -253,6 +309,10 @@ func (s *adminService) ListAdmins(ctx context.Context, req *ports.ListAdminsRequ
 			Email:     result.Email,
 			Nickname:  result.Nickname,
 			Avatar:    result.Avatar,
+			Orphaned:  result.LinkedUserID == nil,
+		}
+		if admins[i].Orphaned {
+			s.logger.WithField("user_id", result.UserID).Warn("Admin record has no backing user")
 		}
 	}
 
@@ -394,6 +454,100 @@ func (s *adminService) GrantSportAccess(ctx context.Context, userID uint, sportT
 	})
 }
 
+// GrantSportAccessBulk 为多个管理员批量授予运动类型访问权限
+//
+// 每个管理员在独立事务中授权，单个失败不影响其他管理员；调用者取自上下文中的
+// 管理员ID，只能管理比自己级别低的管理员。每个管理员的结果都会写入审计日志。
+func (s *adminService) GrantSportAccessBulk(ctx context.Context, userIDs []uint, sportTypeIDs []uint) (ports.BulkResult, error) {
+	result := ports.BulkResult{Results: make([]ports.BulkItemResult, 0, len(userIDs))}
+	if len(userIDs) == 0 || len(sportTypeIDs) == 0 {
+		return result, fmt.Errorf("user ids and sport types are required")
+	}
+
+	callerID, ok := ctxkeys.AdminUserIDFrom(ctx)
+	if !ok {
+		return result, fmt.Errorf("admin user not found in context")
+	}
+	var caller admin.AdminUser
+	if err := s.db.WithContext(ctx).First(&caller, callerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return result, fmt.Errorf("only admins can manage permissions")
+		}
+		return result, fmt.Errorf("failed to get admin: %w", err)
+	}
+	if !caller.IsActive || !caller.IsSystemAdmin() {
+		return result, fmt.Errorf("system admin level required")
+	}
+
+	for _, userID := range userIDs {
+		err := s.checkManageAccess(ctx, &caller, userID)
+		if err == nil {
+			err = s.db.Transaction(func(tx *gorm.DB) error {
+				if err := s.grantSportAccessInTx(ctx, tx, userID, sportTypeIDs); err != nil {
+					return err
+				}
+				return s.logSportAccessAudit(ctx, tx, callerID, userID, sportTypeIDs, nil)
+			})
+		}
+
+		item := ports.BulkItemResult{UserID: userID, Success: err == nil}
+		if err != nil {
+			item.Error = err.Error()
+			result.Failed++
+			// 授权事务已回滚，失败记录单独写入
+			if auditErr := s.logSportAccessAudit(ctx, s.db.DB, callerID, userID, sportTypeIDs, err); auditErr != nil {
+				s.logger.WithError(auditErr).WithField("user_id", userID).Error("Failed to log bulk sport access audit")
+			}
+		} else {
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	return result, nil
+}
+
+// checkManageAccess 校验调用者是否有权管理目标管理员：不能管理同级或更高级别的其他管理员
+func (s *adminService) checkManageAccess(ctx context.Context, caller *admin.AdminUser, targetUserID uint) error {
+	var target admin.AdminUser
+	if err := s.db.WithContext(ctx).First(&target, targetUserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("target admin not found")
+		}
+		return fmt.Errorf("failed to get admin: %w", err)
+	}
+	if target.AdminLevel >= caller.AdminLevel && target.UserID != caller.UserID {
+		return fmt.Errorf("cannot manage permissions for admin with equal or higher level")
+	}
+	return nil
+}
+
+// logSportAccessAudit 记录单个管理员的运动类型授权审计日志，opErr 非空时记为失败
+func (s *adminService) logSportAccessAudit(ctx context.Context, tx *gorm.DB, callerID, targetUserID uint, sportTypeIDs []uint, opErr error) error {
+	auditLog := &admin.AdminAuditLog{
+		AdminUserID: callerID,
+		Action:      "grant_sport_access",
+		Resource:    "admin",
+		ResourceID:  fmt.Sprintf("%d", targetUserID),
+		Method:      "POST",
+		Path:        bulkSportAccessPath,
+		Status:      admin.AuditStatusSuccess,
+		CreatedAt:   time.Now(),
+	}
+	if data, err := json.Marshal(map[string][]uint{"sport_types": sportTypeIDs}); err == nil {
+		auditLog.NewValues = datatypes.JSON(data)
+	}
+	if opErr != nil {
+		auditLog.Status = admin.AuditStatusFailed
+		auditLog.ErrorMsg = opErr.Error()
+	}
+
+	if err := tx.WithContext(ctx).Create(auditLog).Error; err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
 // RevokeSportAccess 撤销运动类型访问权限
 func (s *adminService) RevokeSportAccess(ctx context.Context, userID uint, sportTypeIDs []uint) error {
 	if len(sportTypeIDs) == 0 {
@@ -598,28 +752,25 @@ func (s *adminAuditService) ListAuditLogs(ctx context.Context, req *ports.ListAu
 		req.PageSize = 20
 	}
 
-	query := s.db.WithContext(ctx).Model(&admin.AdminAuditLog{})
-
-	// 添加过滤条件
-	if req.AdminUserID != nil {
-		query = query.Where("admin_user_id = ?", *req.AdminUserID)
-	}
-	if req.Action != "" {
-		query = query.Where("action LIKE ?", "%"+req.Action+"%")
-	}
-	if req.Resource != "" {
-		query = query.Where("resource = ?", req.Resource)
+	startTime, endTime, err := parseAuditTimeRange(req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
 	}
-	if req.Status != nil {
-		query = query.Where("status = ?", *req.Status)
+	order, err := auditLogOrder(req.SortBy, req.SortOrder)
+	if err != nil {
+		return nil, err
 	}
-	if req.StartTime != nil && *req.StartTime != "" {
-		query = query.Where("created_at >= ?", *req.StartTime)
-	}
-	if req.EndTime != nil && *req.EndTime != "" {
-		query = query.Where("created_at <= ?", *req.EndTime)
+	filter := AuditFilter{
+		AdminUserID: req.AdminUserID,
+		Action:      req.Action,
+		Resource:    req.Resource,
+		Status:      req.Status,
+		StartTime:   startTime,
+		EndTime:     endTime,
 	}
 
+	query := applyAuditFilters(s.db.WithContext(ctx).Model(&admin.AdminAuditLog{}), filter)
+
 	// 获取总数
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -631,7 +782,7 @@ func (s *adminAuditService) ListAuditLogs(ctx context.Context, req *ports.ListAu
 	offset := (req.Page - 1) * req.PageSize
 	if err := query.
 		Preload("AdminUser").
-		Order("created_at DESC").
+		Order(order).
 		Limit(req.PageSize).
 		Offset(offset).
 		Find(&logs).Error; err != nil {
@@ -649,36 +800,126 @@ func (s *adminAuditService) ListAuditLogs(ctx context.Context, req *ports.ListAu
 	}, nil
 }
 
+// AuditFilter 审计日志过滤条件，列表与统计共用
+type AuditFilter struct {
+	AdminUserID *uint
+	Action      string // 模糊匹配
+	Resource    string
+	Status      *admin.AuditStatus
+	StartTime   *time.Time
+	EndTime     *time.Time
+}
+
+// auditSortColumns 审计日志列表可排序的字段
+var auditSortColumns = map[string]bool{"created_at": true, "duration": true, "action": true, "resource": true}
+
+// auditLogOrder 返回审计日志列表的排序子句，默认按创建时间倒序，相同值按 ID 排序保证分页稳定
+func auditLogOrder(sortBy, sortOrder string) (string, error) {
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	if !auditSortColumns[sortBy] {
+		return "", fmt.Errorf("%w: invalid sort_by %q", domain.ErrInvalidInput, sortBy)
+	}
+	switch strings.ToLower(sortOrder) {
+	case "", "desc":
+		return sortBy + " DESC, id DESC", nil
+	case "asc":
+		return sortBy + " ASC, id ASC", nil
+	default:
+		return "", fmt.Errorf("%w: invalid sort_order %q", domain.ErrInvalidInput, sortOrder)
+	}
+}
+
+// auditTimeLayouts 审计查询接受的时间格式，不带时区的格式按服务器本地时区解析
+var auditTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// applyAuditFilters 将过滤条件应用到审计日志查询
+func applyAuditFilters(query *gorm.DB, f AuditFilter) *gorm.DB {
+	if f.AdminUserID != nil {
+		query = query.Where("admin_user_id = ?", *f.AdminUserID)
+	}
+	if f.Action != "" {
+		query = query.Where("action LIKE ? ESCAPE '!'", database.ContainsPattern(f.Action))
+	}
+	if f.Resource != "" {
+		query = query.Where("resource = ?", f.Resource)
+	}
+	if f.Status != nil {
+		query = query.Where("status = ?", *f.Status)
+	}
+	if f.StartTime != nil {
+		query = query.Where("created_at >= ?", *f.StartTime)
+	}
+	if f.EndTime != nil {
+		query = query.Where("created_at <= ?", *f.EndTime)
+	}
+	return query
+}
+
+// parseAuditTimeRange 解析并校验查询时间范围，空值表示不限制
+func parseAuditTimeRange(start, end *string) (*time.Time, *time.Time, error) {
+	startTime, err := parseAuditTime("start_time", start)
+	if err != nil {
+		return nil, nil, err
+	}
+	endTime, err := parseAuditTime("end_time", end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if startTime != nil && endTime != nil && startTime.After(*endTime) {
+		return nil, nil, fmt.Errorf("%w: start_time is after end_time", domain.ErrInvalidInput)
+	}
+	return startTime, endTime, nil
+}
+
+// parseAuditTime 按 auditTimeLayouts 依次尝试解析时间
+func parseAuditTime(field string, value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	for _, layout := range auditTimeLayouts {
+		if t, err := time.ParseInLocation(layout, *value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: invalid %s %q", domain.ErrInvalidInput, field, *value)
+}
+
 // GetAuditStats 获取审计统计
 func (s *adminAuditService) GetAuditStats(ctx context.Context, req *ports.AuditStatsRequest) (*ports.AuditStatsResponse, error) {
-	query := s.db.WithContext(ctx).Model(&admin.AdminAuditLog{})
-
-	// 添加时间过滤
-	if req.StartTime != nil && *req.StartTime != "" {
-		query = query.Where("created_at >= ?", *req.StartTime)
+	startTime, endTime, err := parseAuditTimeRange(req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
 	}
-	if req.EndTime != nil && *req.EndTime != "" {
-		query = query.Where("created_at <= ?", *req.EndTime)
+	filter := AuditFilter{
+		AdminUserID: req.AdminUserID,
+		Action:      req.Action,
+		Resource:    req.Resource,
+		StartTime:   startTime,
+		EndTime:     endTime,
 	}
-	if req.AdminUserID != nil {
-		query = query.Where("admin_user_id = ?", *req.AdminUserID)
+
+	// 每项统计使用独立查询，避免条件在链式调用间累积
+	query := func() *gorm.DB {
+		return applyAuditFilters(s.db.WithContext(ctx).Model(&admin.AdminAuditLog{}), filter)
 	}
 
 	// 获取总操作数
 	var totalActions int64
-	if err := query.Count(&totalActions).Error; err != nil {
+	if err := query().Count(&totalActions).Error; err != nil {
 		return nil, fmt.Errorf("failed to count total actions: %w", err)
 	}
 
 	// 获取成功操作数
 	var successActions int64
-	if err := query.Where("status = ?", admin.AuditStatusSuccess).Count(&successActions).Error; err != nil {
+	if err := query().Where("status = ?", admin.AuditStatusSuccess).Count(&successActions).Error; err != nil {
 		return nil, fmt.Errorf("failed to count success actions: %w", err)
 	}
 
 	// 获取失败操作数
 	var failedActions int64
-	if err := query.Where("status = ?", admin.AuditStatusFailed).Count(&failedActions).Error; err != nil {
+	if err := query().Where("status = ?", admin.AuditStatusFailed).Count(&failedActions).Error; err != nil {
 		return nil, fmt.Errorf("failed to count failed actions: %w", err)
 	}
 
@@ -687,7 +928,7 @@ func (s *adminAuditService) GetAuditStats(ctx context.Context, req *ports.AuditS
 		Action string `json:"action"`
 		Count  int64  `json:"count"`
 	}
-	if err := query.Select("action, COUNT(*) as count").Group("action").Find(&actionsByType).Error; err != nil {
+	if err := query().Select("action, COUNT(*) as count").Group("action").Find(&actionsByType).Error; err != nil {
 		return nil, fmt.Errorf("failed to get actions by type: %w", err)
 	}
 
@@ -701,7 +942,7 @@ func (s *adminAuditService) GetAuditStats(ctx context.Context, req *ports.AuditS
 		AdminUserID uint  `json:"admin_user_id"`
 		Count       int64 `json:"count"`
 	}
-	if err := query.Select("admin_user_id, COUNT(*) as count").Group("admin_user_id").Find(&actionsByAdmin).Error; err != nil {
+	if err := query().Select("admin_user_id, COUNT(*) as count").Group("admin_user_id").Find(&actionsByAdmin).Error; err != nil {
 		return nil, fmt.Errorf("failed to get actions by admin: %w", err)
 	}
 
@@ -712,7 +953,7 @@ func (s *adminAuditService) GetAuditStats(ctx context.Context, req *ports.AuditS
 
 	// 计算平均执行时间
 	var avgDuration float64
-	if err := query.Select("AVG(duration) as avg_duration").Scan(&avgDuration).Error; err != nil {
+	if err := query().Select("AVG(duration) as avg_duration").Scan(&avgDuration).Error; err != nil {
 		return nil, fmt.Errorf("failed to get average duration: %w", err)
 	}
 
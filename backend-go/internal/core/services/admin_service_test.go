@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+	"backend-go/pkg/ctxkeys"
+	"backend-go/pkg/database"
+)
+
+// newAdminTestDB 创建内存数据库并建表
+func newAdminTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&user.User{}, &admin.AdminPermission{}, &admin.SportType{}, &admin.AdminUser{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	return &database.DB{DB: db}
+}
+
+func TestAdminService_ListAdminsMarksOrphanedAndCleanupRemovesThem(t *testing.T) {
+	ctx := context.Background()
+	db := newAdminTestDB(t)
+	service := NewAdminService(db, nil)
+
+	users := []*user.User{
+		{Username: "alice", Email: "alice@example.com", Password: "x"},
+		{Username: "bob", Email: "bob@example.com", Password: "x"},
+	}
+	permission := &admin.AdminPermission{Code: admin.PermissionAdminManage, Name: "管理员管理"}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	if err := db.Create(permission).Error; err != nil {
+		t.Fatalf("创建权限失败: %v", err)
+	}
+	for _, u := range users {
+		adminUser := &admin.AdminUser{UserID: u.ID, AdminLevel: admin.AdminLevelSport, IsActive: true, Permissions: []admin.AdminPermission{*permission}}
+		if err := db.Create(adminUser).Error; err != nil {
+			t.Fatalf("创建管理员失败: %v", err)
+		}
+	}
+
+	// 删除 bob 的用户记录，其管理员记录成为孤儿
+	if err := db.Delete(&user.User{}, users[1].ID).Error; err != nil {
+		t.Fatalf("删除用户失败: %v", err)
+	}
+
+	result, err := service.ListAdmins(ctx, &ports.ListAdminsRequest{})
+	if err != nil {
+		t.Fatalf("ListAdmins() error = %v", err)
+	}
+	if len(result.Admins) != 2 {
+		t.Fatalf("管理员数量 = %d, want 2", len(result.Admins))
+	}
+	for _, a := range result.Admins {
+		wantOrphaned := a.UserID == users[1].ID
+		if a.Orphaned != wantOrphaned {
+			t.Errorf("管理员 %d Orphaned = %v, want %v", a.UserID, a.Orphaned, wantOrphaned)
+		}
+		if !a.Orphaned && a.Username != "alice" {
+			t.Errorf("正常管理员应包含用户信息: %+v", a)
+		}
+	}
+
+	removed, err := service.CleanupOrphanedAdmins(ctx)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedAdmins() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("删除数量 = %d, want 1", removed)
+	}
+
+	result, err = service.ListAdmins(ctx, &ports.ListAdminsRequest{})
+	if err != nil {
+		t.Fatalf("ListAdmins() error = %v", err)
+	}
+	if len(result.Admins) != 1 || result.Admins[0].UserID != users[0].ID || result.Admins[0].Orphaned {
+		t.Errorf("清理后应只剩正常管理员: %+v", result.Admins)
+	}
+
+	var links int64
+	if err := db.Table("admin_user_permissions").Where("admin_user_user_id = ?", users[1].ID).Count(&links).Error; err != nil {
+		t.Fatalf("查询权限关联失败: %v", err)
+	}
+	if links != 0 {
+		t.Errorf("孤儿管理员的权限关联应被删除, 剩余 %d", links)
+	}
+
+	if removed, err := service.CleanupOrphanedAdmins(ctx); err != nil || removed != 0 {
+		t.Errorf("再次清理应无记录可删: removed = %d, err = %v", removed, err)
+	}
+}
+
+// seedAuditLogs 写入 2025-10-01 当天不同管理员、操作与状态的审计日志
+func seedAuditLogs(t *testing.T, db *database.DB) {
+	t.Helper()
+	if err := db.AutoMigrate(&admin.AdminAuditLog{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	at := func(hour int) time.Time { return time.Date(2025, 10, 1, hour, 0, 0, 0, time.Local) }
+	logs := []*admin.AdminAuditLog{
+		{AdminUserID: 1, Action: "match.update", Resource: "match", Status: admin.AuditStatusSuccess, Duration: 10, CreatedAt: at(8)},
+		{AdminUserID: 1, Action: "match.update", Resource: "match", Status: admin.AuditStatusSuccess, Duration: 20, CreatedAt: at(10)},
+		{AdminUserID: 1, Action: "match.delete", Resource: "match", Status: admin.AuditStatusFailed, Duration: 30, CreatedAt: at(11)},
+		{AdminUserID: 1, Action: "user.ban", Resource: "user", Status: admin.AuditStatusSuccess, Duration: 40, CreatedAt: at(12)},
+		{AdminUserID: 2, Action: "match.update", Resource: "match", Status: admin.AuditStatusSuccess, Duration: 50, CreatedAt: at(11)},
+		{AdminUserID: 1, Action: "match.update", Resource: "match", Status: admin.AuditStatusSuccess, Duration: 60, CreatedAt: at(15)},
+	}
+	for _, log := range logs {
+		log.Method, log.Path = "POST", "/api/v1/admin"
+	}
+	if err := db.Create(&logs).Error; err != nil {
+		t.Fatalf("写入审计日志失败: %v", err)
+	}
+}
+
+func TestAdminService_GrantSportAccessBulk(t *testing.T) {
+	db := newAdminTestDB(t)
+	if err := db.AutoMigrate(&admin.AdminAuditLog{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	service := NewAdminService(db, nil)
+
+	users := []*user.User{
+		{Username: "root", Email: "root@example.com", Password: "x"},
+		{Username: "alice", Email: "alice@example.com", Password: "x"},
+		{Username: "bob", Email: "bob@example.com", Password: "x"},
+		{Username: "carol", Email: "carol@example.com", Password: "x"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	caller, alice, bob, carol := users[0].ID, users[1].ID, users[2].ID, users[3].ID
+	admins := []*admin.AdminUser{
+		{UserID: caller, AdminLevel: admin.AdminLevelSystem, IsActive: true},
+		{UserID: alice, AdminLevel: admin.AdminLevelSport, IsActive: true},
+		{UserID: bob, AdminLevel: admin.AdminLevelSystem, IsActive: true}, // 同级，无权管理
+		{UserID: carol, AdminLevel: admin.AdminLevelSport, IsActive: true},
+	}
+	if err := db.Create(&admins).Error; err != nil {
+		t.Fatalf("创建管理员失败: %v", err)
+	}
+	lol := &admin.SportType{Name: "LOL", Code: "lol"}
+	if err := db.Create(lol).Error; err != nil {
+		t.Fatalf("创建运动类型失败: %v", err)
+	}
+
+	// 上下文中没有调用者时拒绝整个请求
+	if _, err := service.GrantSportAccessBulk(context.Background(), []uint{alice}, []uint{lol.ID}); err == nil {
+		t.Fatal("GrantSportAccessBulk() without caller error = nil")
+	}
+
+	ctx := ctxkeys.WithAdminUserID(context.Background(), caller)
+	result, err := service.GrantSportAccessBulk(ctx, []uint{alice, bob, carol, 999}, []uint{lol.ID})
+	if err != nil {
+		t.Fatalf("GrantSportAccessBulk() error = %v", err)
+	}
+
+	if result.Succeeded != 2 || result.Failed != 2 || len(result.Results) != 4 {
+		t.Fatalf("result = %+v, want 2 succeeded and 2 failed", result)
+	}
+	wantSuccess := map[uint]bool{alice: true, bob: false, carol: true, 999: false}
+	for _, item := range result.Results {
+		if item.Success != wantSuccess[item.UserID] {
+			t.Errorf("user %d success = %v, want %v (%s)", item.UserID, item.Success, wantSuccess[item.UserID], item.Error)
+		}
+		if !item.Success && item.Error == "" {
+			t.Errorf("user %d failed without error message", item.UserID)
+		}
+	}
+
+	for userID, granted := range wantSuccess {
+		if userID == 999 {
+			continue
+		}
+		// 系统管理员天然拥有全部运动权限，这里直接检查关联记录
+		count := db.Model(&admin.AdminUser{UserID: userID}).Association("SportTypes").Count()
+		if (count == 1) != granted {
+			t.Errorf("user %d sport associations = %d, granted = %v", userID, count, granted)
+		}
+	}
+
+	// 每个目标管理员各有一条审计记录
+	var logs []admin.AdminAuditLog
+	if err := db.Where("action = ?", "grant_sport_access").Order("id").Find(&logs).Error; err != nil {
+		t.Fatalf("读取审计日志失败: %v", err)
+	}
+	if len(logs) != 4 {
+		t.Fatalf("audit logs = %d, want 4", len(logs))
+	}
+	for _, log := range logs {
+		wantStatus := admin.AuditStatusFailed
+		if log.ResourceID == fmt.Sprint(alice) || log.ResourceID == fmt.Sprint(carol) {
+			wantStatus = admin.AuditStatusSuccess
+		}
+		if log.AdminUserID != caller || log.Status != wantStatus {
+			t.Errorf("audit log for %s = %+v, want status %v by %d", log.ResourceID, log, wantStatus, caller)
+		}
+	}
+}
+
+func TestAdminAuditService_ListAndStatsApplyIdenticalFilters(t *testing.T) {
+	ctx := context.Background()
+	db := newAdminTestDB(t)
+	seedAuditLogs(t, db)
+	service := NewAdminAuditService(db)
+
+	adminID := uint(1)
+	// 起止时间使用不同格式，解析后应指向同一时区
+	start := "2025-10-01 09:00:00"
+	end := time.Date(2025, 10, 1, 13, 0, 0, 0, time.Local).Format(time.RFC3339)
+
+	list, err := service.ListAuditLogs(ctx, &ports.ListAuditLogsRequest{
+		AdminUserID: &adminID, Action: "match", Resource: "match", StartTime: &start, EndTime: &end,
+	})
+	if err != nil {
+		t.Fatalf("ListAuditLogs() error = %v", err)
+	}
+	stats, err := service.GetAuditStats(ctx, &ports.AuditStatsRequest{
+		AdminUserID: &adminID, Action: "match", Resource: "match", StartTime: &start, EndTime: &end,
+	})
+	if err != nil {
+		t.Fatalf("GetAuditStats() error = %v", err)
+	}
+
+	// 仅 10:00 的 update 与 11:00 的 delete 命中
+	if list.Total != 2 || len(list.Logs) != 2 {
+		t.Fatalf("list Total/len = %d/%d, want 2/2", list.Total, len(list.Logs))
+	}
+	if stats.TotalActions != list.Total {
+		t.Errorf("stats TotalActions = %d, list Total = %d, want equal", stats.TotalActions, list.Total)
+	}
+	if stats.SuccessActions != 1 || stats.FailedActions != 1 {
+		t.Errorf("Success/Failed = %d/%d, want 1/1", stats.SuccessActions, stats.FailedActions)
+	}
+	if stats.ActionsByType["match.update"] != 1 || stats.ActionsByType["match.delete"] != 1 || len(stats.ActionsByType) != 2 {
+		t.Errorf("ActionsByType = %v, want match.update:1 match.delete:1", stats.ActionsByType)
+	}
+	if stats.ActionsByAdmin[1] != 2 || len(stats.ActionsByAdmin) != 1 {
+		t.Errorf("ActionsByAdmin = %v, want 1:2", stats.ActionsByAdmin)
+	}
+	if stats.AvgDuration != 25 {
+		t.Errorf("AvgDuration = %v, want 25", stats.AvgDuration)
+	}
+}
+
+func TestAdminAuditService_ActionFilterMatchesWildcardsLiterally(t *testing.T) {
+	ctx := context.Background()
+	db := newAdminTestDB(t)
+	seedAuditLogs(t, db)
+	service := NewAdminAuditService(db)
+
+	for _, action := range []string{"match_update", "%"} {
+		list, err := service.ListAuditLogs(ctx, &ports.ListAuditLogsRequest{Action: action})
+		if err != nil {
+			t.Fatalf("ListAuditLogs(%q) error = %v", action, err)
+		}
+		if list.Total != 0 {
+			t.Errorf("ListAuditLogs(%q) Total = %d, want 0: LIKE 通配符应按字面匹配", action, list.Total)
+		}
+	}
+}
+
+func TestAdminAuditService_RejectsInvalidTimeRange(t *testing.T) {
+	ctx := context.Background()
+	db := newAdminTestDB(t)
+	seedAuditLogs(t, db)
+	service := NewAdminAuditService(db)
+
+	invalid := "yesterday"
+	start, end := "2025-10-02", "2025-10-01"
+	cases := []struct {
+		name       string
+		start, end *string
+	}{
+		{"无法解析", &invalid, nil},
+		{"起始晚于结束", &start, &end},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := service.ListAuditLogs(ctx, &ports.ListAuditLogsRequest{StartTime: tc.start, EndTime: tc.end}); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("ListAuditLogs() error = %v, want ErrInvalidInput", err)
+			}
+			if _, err := service.GetAuditStats(ctx, &ports.AuditStatsRequest{StartTime: tc.start, EndTime: tc.end}); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("GetAuditStats() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"backend-go/internal/core/domain/match"
 	"backend-go/internal/core/domain/prediction"
 	"backend-go/internal/core/domain/shared"
@@ -17,8 +21,15 @@ type AsyncPointsIntegration struct {
 	MatchFinishHandler  *MatchFinishHandler
 	LeaderboardHandler  *LeaderboardUpdateHandler
 	NotificationHandler *PointsNotificationHandler
+
+	// 队列指标上报
+	stopMetrics chan struct{}
+	stopOnce    sync.Once
 }
 
+// 队列指标上报间隔
+const pointsCalcMetricsInterval = 15 * time.Second
+
 // NewAsyncPointsIntegration 创建异步积分计算集成服务
 func NewAsyncPointsIntegration(
 	predictionRepo prediction.Repository,
@@ -55,17 +66,41 @@ func NewAsyncPointsIntegration(
 
 	logger.Info("Async points calculation integration initialized")
 
-	return &AsyncPointsIntegration{
+	integration := &AsyncPointsIntegration{
 		EventBus:            eventBus,
 		AsyncPointsService:  asyncPointsService,
 		MatchFinishHandler:  matchFinishHandler,
 		LeaderboardHandler:  leaderboardHandler,
 		NotificationHandler: notificationHandler,
+		stopMetrics:         make(chan struct{}),
+	}
+	go integration.reportMetrics(pointsCalcMetricsInterval)
+
+	return integration
+}
+
+// reportMetrics 定期将队列状态同步到 Prometheus 指标
+func (integration *AsyncPointsIntegration) reportMetrics(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	integration.GetCalculationStatus()
+	for {
+		select {
+		case <-integration.stopMetrics:
+			return
+		case <-ticker.C:
+			integration.GetCalculationStatus()
+		}
 	}
 }
 
 // Shutdown 关闭集成服务
 func (integration *AsyncPointsIntegration) Shutdown() {
+	if integration.stopMetrics != nil {
+		integration.stopOnce.Do(func() { close(integration.stopMetrics) })
+	}
+
 	if integration.AsyncPointsService != nil {
 		integration.AsyncPointsService.Shutdown()
 	}
@@ -75,6 +110,14 @@ func (integration *AsyncPointsIntegration) Shutdown() {
 	}
 }
 
+// Drain 停止接收新的积分计算任务，并在 ctx 截止前等待队列中的任务完成
+func (integration *AsyncPointsIntegration) Drain(ctx context.Context) error {
+	if integration.AsyncPointsService == nil {
+		return nil
+	}
+	return integration.AsyncPointsService.Drain(ctx)
+}
+
 // GetEventBus 获取事件总线
 func (integration *AsyncPointsIntegration) GetEventBus() shared.EventBus {
 	return integration.EventBus
@@ -92,5 +135,7 @@ func (integration *AsyncPointsIntegration) ManualTriggerPointsCalculation(matchI
 
 // GetCalculationStatus 获取计算状态
 func (integration *AsyncPointsIntegration) GetCalculationStatus() map[string]interface{} {
-	return integration.AsyncPointsService.GetQueueStatus()
+	status := integration.AsyncPointsService.GetQueueStatus()
+	recordPointsCalcQueueStatus(status)
+	return status
 }
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 积分计算任务结果标签
+const (
+	pointsCalcResultEnqueued  = "enqueued"
+	pointsCalcResultRejected  = "rejected"
+	pointsCalcResultSucceeded = "succeeded"
+	pointsCalcResultFailed    = "failed"
+)
+
+// 异步积分计算 Prometheus 指标
+var (
+	// 队列中等待处理的任务数
+	pointsCalcQueueLength = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "points_calc_queue_length",
+			Help: "Number of points calculation tasks waiting in queue",
+		},
+	)
+
+	// 活跃任务数（排队中与处理中）
+	pointsCalcActiveTasks = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "points_calc_active_tasks",
+			Help: "Number of pending or processing points calculation tasks",
+		},
+	)
+
+	// 队列容量
+	pointsCalcQueueCapacity = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "points_calc_queue_capacity",
+			Help: "Capacity of points calculation task queue",
+		},
+	)
+
+	// 任务计数
+	pointsCalcTasksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "points_calc_tasks_total",
+			Help: "Total number of points calculation tasks by result",
+		},
+		[]string{"result"}, // enqueued, rejected, succeeded, failed
+	)
+
+	// 任务处理耗时
+	pointsCalcDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "points_calc_duration_seconds",
+			Help:    "Time taken to process points calculation tasks",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"result"},
+	)
+)
+
+// recordPointsCalcQueueStatus 根据队列状态更新仪表盘指标
+func recordPointsCalcQueueStatus(status map[string]interface{}) {
+	if v, ok := status["queue_length"].(int); ok {
+		pointsCalcQueueLength.Set(float64(v))
+	}
+	if v, ok := status["active_tasks"].(int); ok {
+		pointsCalcActiveTasks.Set(float64(v))
+	}
+	if v, ok := status["queue_capacity"].(int); ok {
+		pointsCalcQueueCapacity.Set(float64(v))
+	}
+}
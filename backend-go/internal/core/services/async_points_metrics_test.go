@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+)
+
+// gaugeValue 读取仪表盘当前值
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := gauge.Write(&metric); err != nil {
+		t.Fatalf("读取指标失败: %v", err)
+	}
+	return metric.GetGauge().GetValue()
+}
+
+// counterValue 读取计数器当前值
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := counter.Write(&metric); err != nil {
+		t.Fatalf("读取指标失败: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestAsyncPointsIntegration_QueueMetrics(t *testing.T) {
+	// 不启动工作协程，任务停留在队列中
+	service := &AsyncPointsService{
+		logger:      logrus.New(),
+		taskQueue:   make(chan *PointsCalculationTask, 3),
+		activeTasks: make(map[string]*PointsCalculationTask),
+		maxWorkers:  1,
+		queueSize:   3,
+	}
+	integration := &AsyncPointsIntegration{AsyncPointsService: service}
+
+	enqueued := pointsCalcTasksTotal.WithLabelValues(pointsCalcResultEnqueued)
+	rejected := pointsCalcTasksTotal.WithLabelValues(pointsCalcResultRejected)
+	enqueuedBefore, rejectedBefore := counterValue(t, enqueued), counterValue(t, rejected)
+
+	for i := uint(1); i <= 4; i++ {
+		integration.ManualTriggerPointsCalculation(i, nil)
+	}
+
+	status := integration.GetCalculationStatus()
+	tests := []struct {
+		name  string
+		gauge prometheus.Gauge
+		key   string
+	}{
+		{"队列长度", pointsCalcQueueLength, "queue_length"},
+		{"活跃任务", pointsCalcActiveTasks, "active_tasks"},
+		{"队列容量", pointsCalcQueueCapacity, "queue_capacity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := float64(status[tt.key].(int))
+			if got := gaugeValue(t, tt.gauge); got != want {
+				t.Errorf("%s = %v, want %v", tt.key, got, want)
+			}
+		})
+	}
+
+	if status["queue_length"] != 3 {
+		t.Errorf("queue_length = %v, want 3", status["queue_length"])
+	}
+	if got := counterValue(t, enqueued) - enqueuedBefore; got != 3 {
+		t.Errorf("入队计数增加 %v, want 3", got)
+	}
+	if got := counterValue(t, rejected) - rejectedBefore; got != 1 {
+		t.Errorf("拒绝计数增加 %v, want 1", got)
+	}
+}
@@ -47,6 +47,8 @@ type AsyncPointsService struct {
 	taskQueue   chan *PointsCalculationTask
 	activeTasks map[string]*PointsCalculationTask
 	taskMutex   sync.RWMutex
+	pendingWg   sync.WaitGroup // 已入队但未处理完成的任务
+	draining    bool
 
 	// 控制
 	ctx    context.Context
@@ -118,7 +120,10 @@ func (s *AsyncPointsService) worker(workerID int) {
 		case <-s.ctx.Done():
 			logger.Debug("Points calculation worker shutting down")
 			return
-		case task := <-s.taskQueue:
+		case task, ok := <-s.taskQueue:
+			if !ok {
+				return
+			}
 			s.processTask(task, logger)
 		}
 	}
@@ -126,6 +131,8 @@ func (s *AsyncPointsService) worker(workerID int) {
 
 // processTask 处理积分计算任务
 func (s *AsyncPointsService) processTask(task *PointsCalculationTask, logger *logrus.Entry) {
+	defer s.pendingWg.Done()
+
 	// 更新任务状态
 	s.updateTaskStatus(task.ID, TaskStatusProcessing, "")
 
@@ -142,6 +149,8 @@ func (s *AsyncPointsService) processTask(task *PointsCalculationTask, logger *lo
 	if err != nil {
 		logger.WithError(err).Error("Points calculation failed")
 		s.updateTaskStatus(task.ID, TaskStatusFailed, err.Error())
+		pointsCalcTasksTotal.WithLabelValues(pointsCalcResultFailed).Inc()
+		pointsCalcDuration.WithLabelValues(pointsCalcResultFailed).Observe(time.Since(start).Seconds())
 		return
 	}
 
@@ -159,6 +168,8 @@ func (s *AsyncPointsService) processTask(task *PointsCalculationTask, logger *lo
 	s.updateTaskStatus(task.ID, TaskStatusCompleted, "")
 
 	duration := time.Since(start)
+	pointsCalcTasksTotal.WithLabelValues(pointsCalcResultSucceeded).Inc()
+	pointsCalcDuration.WithLabelValues(pointsCalcResultSucceeded).Observe(duration.Seconds())
 	logger.WithFields(logrus.Fields{
 		"duration":     duration,
 		"predictions":  len(result.Results),
@@ -386,9 +397,15 @@ func (s *AsyncPointsService) QueuePointsCalculation(matchID uint, ruleID *uint)
 		Status:    TaskStatusPending,
 	}
 
-	// 添加到活跃任务
+	// 添加到活跃任务，排空期间不再接收新任务
 	s.taskMutex.Lock()
+	if s.draining {
+		s.taskMutex.Unlock()
+		pointsCalcTasksTotal.WithLabelValues(pointsCalcResultRejected).Inc()
+		return "", fmt.Errorf("points calculation service is draining")
+	}
 	s.activeTasks[taskID] = task
+	s.pendingWg.Add(1)
 	s.taskMutex.Unlock()
 
 	// 尝试加入队列
@@ -398,12 +415,15 @@ func (s *AsyncPointsService) QueuePointsCalculation(matchID uint, ruleID *uint)
 			"task_id":  taskID,
 			"match_id": matchID,
 		}).Info("Points calculation task queued")
+		pointsCalcTasksTotal.WithLabelValues(pointsCalcResultEnqueued).Inc()
 		return taskID, nil
 	default:
 		// 队列满了
 		s.taskMutex.Lock()
 		delete(s.activeTasks, taskID)
 		s.taskMutex.Unlock()
+		s.pendingWg.Done()
+		pointsCalcTasksTotal.WithLabelValues(pointsCalcResultRejected).Inc()
 		return "", fmt.Errorf("task queue is full")
 	}
 }
@@ -436,6 +456,33 @@ func (s *AsyncPointsService) GetQueueStatus() map[string]interface{} {
 	}
 }
 
+// Drain 停止接收新任务并等待已入队任务处理完成，超过 ctx 截止时间时返回超时错误
+func (s *AsyncPointsService) Drain(ctx context.Context) error {
+	s.taskMutex.Lock()
+	s.draining = true
+	remaining := len(s.activeTasks)
+	s.taskMutex.Unlock()
+
+	s.logger.WithField("remaining_tasks", remaining).Info("Draining async points calculation queue")
+
+	done := make(chan struct{})
+	go func() {
+		s.pendingWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("Async points calculation queue drained")
+		return nil
+	case <-ctx.Done():
+		s.taskMutex.RLock()
+		remaining = len(s.activeTasks)
+		s.taskMutex.RUnlock()
+		return fmt.Errorf("points calculation queue not drained, %d tasks remaining: %w", remaining, ctx.Err())
+	}
+}
+
 // Shutdown 关闭服务
 func (s *AsyncPointsService) Shutdown() {
 	s.logger.Info("Shutting down async points service")
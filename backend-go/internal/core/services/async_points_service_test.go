@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain/match"
+	"github.com/sirupsen/logrus"
+)
+
+// blockingMatchRepo 在释放前阻塞 GetByID，用于模拟耗时的积分计算
+type blockingMatchRepo struct {
+	match.Repository
+	release chan struct{}
+}
+
+func (r *blockingMatchRepo) GetByID(ctx context.Context, id uint) (*match.Match, error) {
+	<-r.release
+	return nil, errors.New("match not found")
+}
+
+func newBlockingPointsService() (*AsyncPointsService, *blockingMatchRepo) {
+	repo := &blockingMatchRepo{release: make(chan struct{})}
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	return NewAsyncPointsService(nil, nil, repo, nil, nil, nil, logger), repo
+}
+
+func TestAsyncPointsService_DrainWaitsForTasks(t *testing.T) {
+	service, repo := newBlockingPointsService()
+	defer service.Shutdown()
+
+	for i := uint(1); i <= 3; i++ {
+		if _, err := service.QueuePointsCalculation(i, nil); err != nil {
+			t.Fatalf("QueuePointsCalculation() error = %v", err)
+		}
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- service.Drain(context.Background()) }()
+
+	select {
+	case err := <-drained:
+		t.Fatalf("任务未完成时 Drain 已返回: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 排空期间拒绝新任务
+	if _, err := service.QueuePointsCalculation(4, nil); err == nil {
+		t.Errorf("排空期间应拒绝新任务")
+	}
+
+	close(repo.release)
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Errorf("Drain() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("任务完成后 Drain 未返回")
+	}
+
+	if status := service.GetQueueStatus(); status["active_tasks"] != 0 {
+		t.Errorf("active_tasks = %v, want 0", status["active_tasks"])
+	}
+}
+
+func TestAsyncPointsService_DrainRespectsDeadline(t *testing.T) {
+	service, repo := newBlockingPointsService()
+	defer service.Shutdown()
+	defer close(repo.release)
+
+	if _, err := service.QueuePointsCalculation(1, nil); err != nil {
+		t.Fatalf("QueuePointsCalculation() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := service.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Drain 超时后未及时返回，耗时 %v", elapsed)
+	}
+}
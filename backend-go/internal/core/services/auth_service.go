@@ -0,0 +1,450 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/shared/jwt"
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/redis"
+	"backend-go/pkg/response"
+)
+
+// 密码重置令牌相关缓存键前缀
+const (
+	passwordResetTokenPrefix = "password_reset:token:"
+	passwordResetUserPrefix  = "password_reset:user:"
+)
+
+const (
+	// apiKeyPrefix 明文 API 密钥的固定前缀，便于在日志与代码扫描中识别泄露的密钥
+	apiKeyPrefix = "yk_"
+
+	// apiKeyTouchInterval 最近使用时间的更新间隔，避免每个请求都写数据库
+	apiKeyTouchInterval = time.Minute
+)
+
+// resetTokenStore 重置令牌存储所需的缓存操作
+type resetTokenStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// AuthServiceConfig 认证服务配置
+type AuthServiceConfig struct {
+	ResetTokenTTL    time.Duration      // 重置令牌有效期
+	ResetURL         string             // 重置页面地址，令牌以 token 查询参数附加
+	ValidatePassword func(string) error // 密码策略校验
+	ImpersonationTTL time.Duration      // 代入令牌有效期
+}
+
+// authService 认证服务实现
+type authService struct {
+	userRepo    user.Repository
+	tokenStore  resetTokenStore
+	emailSender ports.EmailSender
+	config      AuthServiceConfig
+	now         func() time.Time
+
+	// 管理员代入所需依赖，未配置时代入不可用
+	jwtService   jwt.JWTService
+	adminService ports.AdminService
+	auditService ports.AdminAuditService
+
+	// 登录会话存储，未配置时会话管理不可用
+	sessions *SessionStore
+
+	// API 密钥仓储，未配置时 API 密钥认证不可用
+	apiKeys user.APIKeyRepository
+}
+
+// NewAuthService 创建认证服务
+func NewAuthService(
+	userRepo user.Repository,
+	cache redis.CacheService,
+	sessions *SessionStore,
+	apiKeys user.APIKeyRepository,
+	emailSender ports.EmailSender,
+	jwtService jwt.JWTService,
+	adminService ports.AdminService,
+	auditService ports.AdminAuditService,
+	config AuthServiceConfig,
+) ports.AuthService {
+	s := newAuthService(userRepo, cache, emailSender, config)
+	s.jwtService = jwtService
+	s.adminService = adminService
+	s.auditService = auditService
+	s.sessions = sessions
+	s.apiKeys = apiKeys
+	return s
+}
+
+func newAuthService(userRepo user.Repository, store resetTokenStore, emailSender ports.EmailSender, config AuthServiceConfig) *authService {
+	if config.ResetTokenTTL <= 0 {
+		config.ResetTokenTTL = 30 * time.Minute
+	}
+	if config.ImpersonationTTL <= 0 {
+		config.ImpersonationTTL = 15 * time.Minute
+	}
+
+	return &authService{
+		userRepo:    userRepo,
+		tokenStore:  store,
+		emailSender: emailSender,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// RequestPasswordReset 申请重置密码
+func (s *authService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return response.NewBadRequestError("邮箱不能为空", nil)
+	}
+
+	foundUser, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || foundUser == nil {
+		// 邮箱不存在时同样返回成功，避免邮箱枚举
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	tokenHash := hashResetToken(token)
+	userKey := passwordResetUserPrefix + strconv.FormatUint(uint64(foundUser.ID), 10)
+
+	// 同一用户只保留最新的令牌
+	if previous, err := s.tokenStore.Get(ctx, userKey); err == nil && previous != "" {
+		if err := s.tokenStore.Delete(ctx, passwordResetTokenPrefix+previous); err != nil {
+			logger.Warnf("Failed to delete previous reset token for user %d: %v", foundUser.ID, err)
+		}
+	}
+
+	if err := s.tokenStore.Set(ctx, passwordResetTokenPrefix+tokenHash, foundUser.ID, s.config.ResetTokenTTL); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+	if err := s.tokenStore.Set(ctx, userKey, tokenHash, s.config.ResetTokenTTL); err != nil {
+		return fmt.Errorf("failed to store reset token index: %w", err)
+	}
+
+	if s.emailSender != nil && s.emailAllowed(ctx, foundUser.ID) {
+		body := fmt.Sprintf("您好 %s：\n\n请在 %d 分钟内点击以下链接重置密码：\n%s\n\n如果这不是您本人的操作，请忽略此邮件。",
+			foundUser.Username, int(s.config.ResetTokenTTL.Minutes()), s.buildResetLink(token))
+		if err := s.emailSender.SendEmail(ctx, foundUser.Email, "重置密码", body); err != nil {
+			// 发送失败只记录日志，对外仍返回成功
+			logger.Warnf("Failed to send password reset email to user %d: %v", foundUser.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// emailAllowed 检查用户是否允许接收邮件，读取偏好失败时按默认（允许）处理
+func (s *authService) emailAllowed(ctx context.Context, userID uint) bool {
+	prefs, err := s.userRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		logger.Warnf("Failed to get notification preferences for user %d: %v", userID, err)
+		return true
+	}
+	if !prefs.EmailEnabled {
+		logger.Infof("User %d disabled email notifications, skipping password reset email", userID)
+		return false
+	}
+	return true
+}
+
+// ResetPassword 使用重置令牌设置新密码
+func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return response.NewBadRequestError("重置令牌无效或已过期", nil)
+	}
+
+	if s.config.ValidatePassword != nil {
+		if err := s.config.ValidatePassword(newPassword); err != nil {
+			return response.NewValidationError("密码不符合安全策略", err.Error())
+		}
+	}
+
+	tokenKey := passwordResetTokenPrefix + hashResetToken(token)
+	value, err := s.tokenStore.Get(ctx, tokenKey)
+	if err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return response.NewBadRequestError("重置令牌无效或已过期", nil)
+		}
+		return fmt.Errorf("failed to get reset token: %w", err)
+	}
+
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return response.NewBadRequestError("重置令牌无效或已过期", nil)
+	}
+
+	if err := s.userRepo.ChangePassword(ctx, uint(userID), newPassword); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+
+	// 令牌只能使用一次，修改成功后再删除，修改失败时用户可以用同一令牌重试
+	if err := s.tokenStore.Delete(ctx, tokenKey); err != nil {
+		logger.Errorf("Failed to invalidate reset token for user %d: %v", userID, err)
+	}
+	if err := s.tokenStore.Delete(ctx, passwordResetUserPrefix+value); err != nil {
+		logger.Warnf("Failed to delete reset token index for user %d: %v", userID, err)
+	}
+
+	// 记录修改时间，使之前签发的令牌失效
+	foundUser, err := s.userRepo.GetByID(ctx, uint(userID))
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	changedAt := s.now()
+	foundUser.LastPasswordChange = &changedAt
+	if err := s.userRepo.Update(ctx, foundUser); err != nil {
+		return fmt.Errorf("failed to update password change time: %w", err)
+	}
+
+	logger.Infof("Password reset for user ID: %d", userID)
+	return nil
+}
+
+// Impersonate 超级管理员代入目标用户
+//
+// 签发前先写入审计日志，审计失败时不签发令牌；不允许代入自己或其他管理员。
+func (s *authService) Impersonate(ctx context.Context, adminID, targetUserID uint) (*jwt.TokenPair, error) {
+	if s.jwtService == nil || s.adminService == nil || s.auditService == nil {
+		return nil, response.NewServiceUnavailableError("代入功能未启用")
+	}
+	if adminID == targetUserID {
+		return nil, response.NewBadRequestError("不能代入自己的账户", nil)
+	}
+
+	adminUser, err := s.adminService.GetAdmin(ctx, adminID)
+	if err != nil || adminUser == nil || !adminUser.IsActive || !adminUser.IsSuperAdmin() {
+		return nil, response.NewForbiddenError("只有超级管理员可以代入用户")
+	}
+
+	target, err := s.userRepo.GetByID(ctx, targetUserID)
+	if err != nil || target == nil {
+		return nil, response.NewUserNotFoundError(targetUserID)
+	}
+	if target.Role == user.UserRoleAdmin {
+		return nil, response.NewForbiddenError("不能代入管理员账户")
+	}
+
+	ttl := s.config.ImpersonationTTL
+	token, err := s.jwtService.GenerateImpersonationToken(target.ID, target.Username, string(target.Role), adminID, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &ports.LogActionRequest{
+		AdminUserID: adminID,
+		Action:      "impersonate",
+		Resource:    "user",
+		ResourceID:  strconv.FormatUint(uint64(target.ID), 10),
+		NewValues: map[string]interface{}{
+			"impersonator_id":      adminID,
+			"impersonated_user_id": target.ID,
+			"expires_in":           int64(ttl.Seconds()),
+		},
+		Status: admin.AuditStatusSuccess,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to audit impersonation: %w", err)
+	}
+
+	logger.Warnf("Admin %d started impersonating user %d", adminID, target.ID)
+	return &jwt.TokenPair{
+		AccessToken: token,
+		ExpiresIn:   int64(ttl.Seconds()),
+	}, nil
+}
+
+// RecordSession 登录成功后记录会话
+func (s *authService) RecordSession(ctx context.Context, refreshToken, device, ip string) (*user.Session, error) {
+	if s.sessions == nil || s.jwtService == nil {
+		return nil, response.NewServiceUnavailableError("会话管理未启用")
+	}
+
+	claims, err := s.jwtService.ValidateToken(refreshToken)
+	if err != nil || claims.Type != "refresh" || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, response.NewBadRequestError("刷新令牌无效", nil)
+	}
+
+	now := s.now()
+	session := &user.Session{
+		ID:        claims.ID,
+		UserID:    claims.UserID,
+		Device:    device,
+		IP:        ip,
+		CreatedAt: now,
+		LastSeen:  now,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := s.sessions.Record(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ListSessions 列出用户当前有效的登录会话
+func (s *authService) ListSessions(ctx context.Context, userID uint) ([]*user.Session, error) {
+	if s.sessions == nil {
+		return nil, response.NewServiceUnavailableError("会话管理未启用")
+	}
+	return s.sessions.List(ctx, userID)
+}
+
+// RevokeSession 吊销用户的某个会话
+func (s *authService) RevokeSession(ctx context.Context, userID uint, jti string) error {
+	if s.sessions == nil {
+		return response.NewServiceUnavailableError("会话管理未启用")
+	}
+	if err := s.sessions.Revoke(ctx, userID, jti); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return response.NewNotFoundError("会话不存在或已过期")
+		}
+		return err
+	}
+
+	logger.Infof("Session %s of user %d revoked", jti, userID)
+	return nil
+}
+
+// CreateAPIKey 创建 API 密钥
+func (s *authService) CreateAPIKey(ctx context.Context, adminID uint, name string, scopes []string) (*user.APIKey, string, error) {
+	if s.apiKeys == nil {
+		return nil, "", response.NewServiceUnavailableError("API 密钥未启用")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", response.NewBadRequestError("密钥名称不能为空", nil)
+	}
+	if len(scopes) == 0 {
+		return nil, "", response.NewBadRequestError("至少需要一个权限范围", user.APIScopes)
+	}
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !user.IsValidAPIScope(scope) {
+			return nil, "", response.NewBadRequestError("无效的权限范围: "+scope, user.APIScopes)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+
+	secret, err := generateResetToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	plain := apiKeyPrefix + secret
+	key := &user.APIKey{
+		Name:      name,
+		Prefix:    plain[:len(apiKeyPrefix)+8],
+		KeyHash:   hashResetToken(plain),
+		Scopes:    normalized,
+		IsActive:  true,
+		CreatedBy: adminID,
+	}
+	if err := s.apiKeys.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	logger.Infof("API key %d (%s) created by admin %d with scopes %v", key.ID, key.Name, adminID, normalized)
+	return key, plain, nil
+}
+
+// ListAPIKeys 列出全部 API 密钥
+func (s *authService) ListAPIKeys(ctx context.Context) ([]*user.APIKey, error) {
+	if s.apiKeys == nil {
+		return nil, response.NewServiceUnavailableError("API 密钥未启用")
+	}
+	return s.apiKeys.List(ctx)
+}
+
+// RevokeAPIKey 吊销 API 密钥
+func (s *authService) RevokeAPIKey(ctx context.Context, id uint) error {
+	if s.apiKeys == nil {
+		return response.NewServiceUnavailableError("API 密钥未启用")
+	}
+	if err := s.apiKeys.Revoke(ctx, id, s.now()); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return response.NewNotFoundError("API 密钥不存在或已吊销")
+		}
+		return err
+	}
+
+	logger.Infof("API key %d revoked", id)
+	return nil
+}
+
+// ValidateAPIKey 校验 API 密钥
+//
+// 按哈希查找密钥，不存在时返回 domain.ErrAPIKeyNotFound，已吊销时返回 domain.ErrAPIKeyRevoked。
+func (s *authService) ValidateAPIKey(ctx context.Context, key string) (*user.APIKeyContext, error) {
+	if s.apiKeys == nil {
+		return nil, response.NewServiceUnavailableError("API 密钥未启用")
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	found, err := s.apiKeys.GetByHash(ctx, hashResetToken(key))
+	if err != nil {
+		return nil, err
+	}
+	if !found.IsActive || found.RevokedAt != nil {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	if now := s.now(); found.LastUsedAt == nil || now.Sub(*found.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeys.TouchLastUsed(ctx, found.ID, now); err != nil {
+			logger.Warnf("Failed to touch api key %d: %v", found.ID, err)
+		}
+	}
+	return &user.APIKeyContext{KeyID: found.ID, Name: found.Name, Scopes: found.Scopes, CreatedBy: found.CreatedBy}, nil
+}
+
+// buildResetLink 构建重置链接
+func (s *authService) buildResetLink(token string) string {
+	if s.config.ResetURL == "" {
+		return token
+	}
+
+	separator := "?"
+	if strings.Contains(s.config.ResetURL, "?") {
+		separator = "&"
+	}
+	return s.config.ResetURL + separator + "token=" + url.QueryEscape(token)
+}
+
+// generateResetToken 生成随机重置令牌
+func generateResetToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashResetToken 计算令牌哈希，缓存中只保存哈希值
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,559 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain/admin"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+	"backend-go/internal/shared/jwt"
+	"backend-go/pkg/redis"
+	"backend-go/pkg/response"
+)
+
+// fakeUserRepo 仅实现重置密码所需方法的用户仓储
+type fakeUserRepo struct {
+	user.Repository
+	users     map[uint]*user.User
+	passwords map[uint]string
+	prefs     map[uint]*user.NotificationPreferences
+	changeErr error // 不为空时 ChangePassword 返回该错误
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*user.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepo) ChangePassword(ctx context.Context, userID uint, newPassword string) error {
+	if r.changeErr != nil {
+		return r.changeErr
+	}
+	r.passwords[userID] = newPassword
+	return nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, u *user.User) error {
+	r.users[u.ID] = u
+	return nil
+}
+
+func (r *fakeUserRepo) GetNotificationPreferences(ctx context.Context, userID uint) (*user.NotificationPreferences, error) {
+	if prefs, ok := r.prefs[userID]; ok {
+		return prefs, nil
+	}
+	return user.DefaultNotificationPreferences(userID), nil
+}
+
+// fakeTokenStore 支持过期时间的令牌存储
+type fakeTokenStore struct {
+	now    time.Time
+	values map[string]string
+	expiry map[string]time.Time
+}
+
+func (s *fakeTokenStore) Get(ctx context.Context, key string) (string, error) {
+	value, ok := s.values[key]
+	if !ok || s.now.After(s.expiry[key]) {
+		return "", redis.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (s *fakeTokenStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.values[key] = fmt.Sprint(value)
+	s.expiry[key] = s.now.Add(expiration)
+	return nil
+}
+
+func (s *fakeTokenStore) Delete(ctx context.Context, key string) error {
+	delete(s.values, key)
+	return nil
+}
+
+// fakeEmailSender 记录最后一封邮件
+type fakeEmailSender struct {
+	to   string
+	body string
+}
+
+func (s *fakeEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	s.to, s.body = to, body
+	return nil
+}
+
+func newTestAuthService() (*authService, *fakeUserRepo, *fakeTokenStore, *fakeEmailSender) {
+	repo := &fakeUserRepo{
+		users:     map[uint]*user.User{1: {ID: 1, Username: "alice", Email: "alice@example.com"}},
+		passwords: make(map[uint]string),
+	}
+	store := &fakeTokenStore{now: time.Now(), values: make(map[string]string), expiry: make(map[string]time.Time)}
+	sender := &fakeEmailSender{}
+	svc := newAuthService(repo, store, sender, AuthServiceConfig{
+		ResetTokenTTL: 30 * time.Minute,
+		ResetURL:      "https://example.com/reset",
+		ValidatePassword: func(p string) error {
+			if len(p) < 8 {
+				return errors.New("password too short")
+			}
+			return nil
+		},
+	})
+	return svc, repo, store, sender
+}
+
+// extractToken 从邮件正文中提取重置令牌
+func extractToken(t *testing.T, body string) string {
+	t.Helper()
+	idx := strings.Index(body, "token=")
+	if idx < 0 {
+		t.Fatalf("邮件中没有重置链接: %s", body)
+	}
+	return strings.Fields(body[idx+len("token="):])[0]
+}
+
+func TestAuthService_ResetPasswordHappyPath(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, store, sender := newTestAuthService()
+
+	if err := svc.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	token := extractToken(t, sender.body)
+
+	// 缓存中只保存令牌哈希
+	for key := range store.values {
+		if strings.Contains(key, token) {
+			t.Errorf("缓存键中出现明文令牌: %s", key)
+		}
+	}
+
+	if err := svc.ResetPassword(ctx, token, "NewPassword1"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if repo.passwords[1] != "NewPassword1" {
+		t.Errorf("密码未更新")
+	}
+	if repo.users[1].LastPasswordChange == nil {
+		t.Errorf("未记录密码修改时间，旧会话不会失效")
+	}
+}
+
+func TestAuthService_UnknownEmailStillSucceeds(t *testing.T) {
+	svc, _, _, sender := newTestAuthService()
+
+	if err := svc.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Errorf("未注册邮箱应返回成功，实际 error = %v", err)
+	}
+	if sender.to != "" {
+		t.Errorf("未注册邮箱不应发送邮件")
+	}
+}
+
+func TestAuthService_RejectsExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	svc, _, store, sender := newTestAuthService()
+
+	svc.RequestPasswordReset(ctx, "alice@example.com")
+	token := extractToken(t, sender.body)
+
+	store.now = store.now.Add(31 * time.Minute)
+	if err := svc.ResetPassword(ctx, token, "NewPassword1"); err == nil {
+		t.Errorf("过期令牌应被拒绝")
+	}
+}
+
+func TestAuthService_RejectsReusedToken(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, sender := newTestAuthService()
+
+	svc.RequestPasswordReset(ctx, "alice@example.com")
+	token := extractToken(t, sender.body)
+
+	if err := svc.ResetPassword(ctx, token, "NewPassword1"); err != nil {
+		t.Fatalf("首次使用令牌失败: %v", err)
+	}
+	if err := svc.ResetPassword(ctx, token, "NewPassword2"); err == nil {
+		t.Errorf("令牌重复使用应被拒绝")
+	}
+}
+
+func TestAuthService_FailedPasswordChangeKeepsToken(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, sender := newTestAuthService()
+
+	svc.RequestPasswordReset(ctx, "alice@example.com")
+	token := extractToken(t, sender.body)
+
+	repo.changeErr = errors.New("database unavailable")
+	if err := svc.ResetPassword(ctx, token, "NewPassword1"); err == nil {
+		t.Fatal("修改密码失败时应返回错误")
+	}
+
+	// 修改失败不消耗令牌，恢复后可重试
+	repo.changeErr = nil
+	if err := svc.ResetPassword(ctx, token, "NewPassword1"); err != nil {
+		t.Fatalf("修改失败后令牌应仍可使用: %v", err)
+	}
+	if repo.passwords[1] != "NewPassword1" {
+		t.Errorf("密码未更新")
+	}
+}
+
+func TestAuthService_RejectsWeakPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, sender := newTestAuthService()
+
+	svc.RequestPasswordReset(ctx, "alice@example.com")
+	token := extractToken(t, sender.body)
+
+	if err := svc.ResetPassword(ctx, token, "short"); err == nil {
+		t.Errorf("不符合策略的密码应被拒绝")
+	}
+	if _, changed := repo.passwords[1]; changed {
+		t.Errorf("策略校验失败时不应修改密码")
+	}
+	// 策略校验失败不消耗令牌
+	if err := svc.ResetPassword(ctx, token, "NewPassword1"); err != nil {
+		t.Errorf("策略校验失败后令牌应仍可使用: %v", err)
+	}
+}
+
+func TestAuthService_RequestPasswordResetRespectsEmailPreference(t *testing.T) {
+	svc, repo, store, sender := newTestAuthService()
+	repo.prefs = map[uint]*user.NotificationPreferences{1: {UserID: 1, EmailEnabled: false}}
+
+	if err := svc.RequestPasswordReset(context.Background(), "alice@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	if sender.to != "" {
+		t.Errorf("关闭邮件通知的用户不应收到重置邮件, to = %s", sender.to)
+	}
+	if len(store.values) == 0 {
+		t.Error("令牌仍应被保存")
+	}
+}
+
+// fakeAdminLookup 按用户ID返回管理员信息
+type fakeAdminLookup struct {
+	ports.AdminService
+	admins map[uint]*admin.AdminUser
+}
+
+func (s *fakeAdminLookup) GetAdmin(ctx context.Context, userID uint) (*admin.AdminUser, error) {
+	if a, ok := s.admins[userID]; ok {
+		return a, nil
+	}
+	return nil, errors.New("admin not found")
+}
+
+// fakeAuditLog 记录写入的审计日志
+type fakeAuditLog struct {
+	ports.AdminAuditService
+	entries []*ports.LogActionRequest
+}
+
+func (s *fakeAuditLog) LogAction(ctx context.Context, req *ports.LogActionRequest) error {
+	s.entries = append(s.entries, req)
+	return nil
+}
+
+func newTestImpersonation(t *testing.T) (*authService, user.Service, *fakeAuditLog) {
+	t.Helper()
+	svc, repo, _, _ := newTestAuthService()
+	repo.users[1].Role = user.UserRoleAdmin
+	repo.users[2] = &user.User{ID: 2, Username: "bob", Role: user.UserRoleUser}
+	repo.users[3] = &user.User{ID: 3, Username: "carol", Role: user.UserRoleAdmin}
+
+	jwtService := jwt.NewJWTService(jwt.Config{
+		SecretKey:       "impersonation-test-secret-with-32-chars",
+		AccessTokenTTL:  time.Hour,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	audit := &fakeAuditLog{}
+	svc.jwtService = jwtService
+	svc.adminService = &fakeAdminLookup{admins: map[uint]*admin.AdminUser{
+		1: {UserID: 1, AdminLevel: admin.AdminLevelSuper, IsActive: true},
+		3: {UserID: 3, AdminLevel: admin.AdminLevelSystem, IsActive: true},
+	}}
+	svc.auditService = audit
+	svc.config.ImpersonationTTL = 10 * time.Minute
+
+	return svc, NewUserService(repo, jwtService, nil, nil, nil, Config{}), audit
+}
+
+func TestAuthService_ImpersonateAuthenticatesAsTarget(t *testing.T) {
+	ctx := context.Background()
+	svc, userService, audit := newTestImpersonation(t)
+
+	pair, err := svc.Impersonate(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("Impersonate() error = %v", err)
+	}
+	if pair.RefreshToken != "" || pair.ExpiresIn != int64((10*time.Minute).Seconds()) {
+		t.Errorf("token pair = %+v, want access token only with 10m ttl", pair)
+	}
+
+	identity, err := userService.Authenticate(ctx, pair.AccessToken)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if identity.User.ID != 2 || identity.ImpersonatorID != 1 || !identity.IsImpersonated() {
+		t.Errorf("identity = user %d impersonated by %d, want user 2 by 1", identity.User.ID, identity.ImpersonatorID)
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(audit.entries))
+	}
+	entry := audit.entries[0]
+	values, _ := entry.NewValues.(map[string]interface{})
+	if entry.AdminUserID != 1 || entry.Action != "impersonate" || entry.ResourceID != "2" ||
+		values["impersonator_id"] != uint(1) || values["impersonated_user_id"] != uint(2) {
+		t.Errorf("audit entry = %+v, want admin 1 impersonating user 2", entry)
+	}
+}
+
+func TestAuthService_ImpersonateRejected(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminID        uint
+		targetID       uint
+		wantStatusCode int
+	}{
+		{"非超级管理员", 3, 2, 403},
+		{"普通用户", 2, 1, 403},
+		{"目标为管理员", 1, 3, 403},
+		{"代入自己", 1, 1, 400},
+		{"目标不存在", 1, 99, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, audit := newTestImpersonation(t)
+
+			_, err := svc.Impersonate(context.Background(), tt.adminID, tt.targetID)
+			var appErr *response.AppError
+			if !errors.As(err, &appErr) || appErr.StatusCode != tt.wantStatusCode {
+				t.Fatalf("Impersonate() error = %v, want status %d", err, tt.wantStatusCode)
+			}
+			if len(audit.entries) != 0 {
+				t.Errorf("被拒绝的代入不应签发令牌或写入审计: %+v", audit.entries)
+			}
+		})
+	}
+}
+
+// memorySessionCache 内存实现的会话缓存，按 now 判断键是否过期
+type memorySessionCache struct {
+	values  map[string]string
+	hashes  map[string]map[string]string
+	expires map[string]time.Time
+	now     func() time.Time
+}
+
+func newMemorySessionCache() *memorySessionCache {
+	return &memorySessionCache{
+		values:  make(map[string]string),
+		hashes:  make(map[string]map[string]string),
+		expires: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// evict 删除已过期的键
+func (c *memorySessionCache) evict(key string) {
+	if at, ok := c.expires[key]; ok && !c.now().Before(at) {
+		delete(c.values, key)
+		delete(c.hashes, key)
+		delete(c.expires, key)
+	}
+}
+
+func (c *memorySessionCache) Get(ctx context.Context, key string) (string, error) {
+	c.evict(key)
+	if value, ok := c.values[key]; ok {
+		return value, nil
+	}
+	return "", redis.ErrKeyNotFound
+}
+
+func (c *memorySessionCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.values[key] = fmt.Sprint(value)
+	if expiration > 0 {
+		c.expires[key] = c.now().Add(expiration)
+	}
+	return nil
+}
+
+func (c *memorySessionCache) HGet(ctx context.Context, key, field string) (string, error) {
+	c.evict(key)
+	if value, ok := c.hashes[key][field]; ok {
+		return value, nil
+	}
+	return "", redis.ErrKeyNotFound
+}
+
+func (c *memorySessionCache) HSet(ctx context.Context, key string, values ...interface{}) error {
+	if c.hashes[key] == nil {
+		c.hashes[key] = make(map[string]string)
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		c.hashes[key][fmt.Sprint(values[i])] = fmt.Sprint(values[i+1])
+	}
+	return nil
+}
+
+func (c *memorySessionCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	c.evict(key)
+	result := make(map[string]string, len(c.hashes[key]))
+	for field, value := range c.hashes[key] {
+		result[field] = value
+	}
+	return result, nil
+}
+
+func (c *memorySessionCache) HDelete(ctx context.Context, key string, fields ...string) error {
+	for _, field := range fields {
+		delete(c.hashes[key], field)
+	}
+	return nil
+}
+
+func (c *memorySessionCache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	c.expires[key] = c.now().Add(expiration)
+	return nil
+}
+
+func TestAuthService_RevokedSessionTokenRejected(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, _ := newTestAuthService()
+	jwtService := jwt.NewJWTService(jwt.Config{
+		SecretKey:       "session-test-secret-with-at-least-32-chars",
+		AccessTokenTTL:  time.Hour,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	sessions := NewSessionStore(newMemorySessionCache(), 24*time.Hour)
+	svc.jwtService = jwtService
+	svc.sessions = sessions
+	userService := NewUserService(repo, jwtService, nil, nil, sessions, Config{})
+
+	// 三台设备依次登录
+	now := time.Now()
+	devices := []string{"iPhone", "Chrome", "Firefox"}
+	pairs := make([]*jwt.TokenPair, len(devices))
+	for i, device := range devices {
+		svc.now = func() time.Time { return now.Add(time.Duration(i) * time.Minute) }
+		pair, err := jwtService.GenerateToken(1, "alice", "user")
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		if _, err := svc.RecordSession(ctx, pair.RefreshToken, device, "203.0.113.1"); err != nil {
+			t.Fatalf("RecordSession(%s) error = %v", device, err)
+		}
+		pairs[i] = pair
+	}
+
+	list, err := svc.ListSessions(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(list) != 3 || list[0].Device != "Firefox" || list[2].Device != "iPhone" {
+		t.Fatalf("sessions = %+v, want 3 个会话且最近登录在前", list)
+	}
+	if list[0].IP != "203.0.113.1" || list[0].UserID != 1 || list[0].ID == "" {
+		t.Errorf("session = %+v, want 记录 IP、用户与 jti", list[0])
+	}
+
+	claims, _ := jwtService.ValidateToken(pairs[1].AccessToken)
+	if err := svc.RevokeSession(ctx, 2, claims.ID); err == nil {
+		t.Error("不能吊销其他用户的会话")
+	}
+	if err := svc.RevokeSession(ctx, 1, claims.ID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+
+	if _, err := userService.Authenticate(ctx, pairs[1].AccessToken); err == nil {
+		t.Error("已吊销会话的访问令牌应被拒绝")
+	}
+	if _, err := userService.RefreshToken(ctx, pairs[1].RefreshToken); err == nil {
+		t.Error("已吊销会话的刷新令牌应被拒绝")
+	}
+	for _, i := range []int{0, 2} {
+		if _, err := userService.Authenticate(ctx, pairs[i].AccessToken); err != nil {
+			t.Errorf("%s 会话应仍然有效: %v", devices[i], err)
+		}
+	}
+
+	if list, _ := svc.ListSessions(ctx, 1); len(list) != 2 {
+		t.Errorf("sessions = %d, want 2", len(list))
+	}
+	var appErr *response.AppError
+	if err := svc.RevokeSession(ctx, 1, claims.ID); !errors.As(err, &appErr) || appErr.StatusCode != 404 {
+		t.Errorf("重复吊销 error = %v, want 404", err)
+	}
+}
+
+func TestAuthService_RefreshExtendsSessionAndRevocationOutlivesIt(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, _ := newTestAuthService()
+	jwtService := jwt.NewJWTService(jwt.Config{
+		SecretKey:       "session-test-secret-with-at-least-32-chars",
+		AccessTokenTTL:  time.Hour,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	cache := newMemorySessionCache()
+	sessions := NewSessionStore(cache, 24*time.Hour)
+	svc.jwtService = jwtService
+	svc.sessions = sessions
+	userService := NewUserService(repo, jwtService, nil, nil, sessions, Config{})
+
+	// 会话与缓存共用一个可调的时钟；登录时记录的过期时间只有 1 小时
+	start := time.Now()
+	clock := start
+	cache.now = func() time.Time { return clock }
+	sessions.now = func() time.Time { return clock }
+
+	pair, err := jwtService.GenerateToken(1, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	claims, _ := jwtService.ValidateToken(pair.RefreshToken)
+	if err := sessions.Record(ctx, &user.Session{ID: claims.ID, UserID: 1, CreatedAt: clock, LastSeen: clock, ExpiresAt: clock.Add(time.Hour)}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	// 刷新后会话延长到新刷新令牌的过期时间，超过原过期时间仍可列出和吊销
+	clock = start.Add(30 * time.Minute)
+	refreshed, err := userService.RefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	clock = start.Add(2 * time.Hour)
+	if list, _ := svc.ListSessions(ctx, 1); len(list) != 1 || list[0].ID != claims.ID {
+		t.Fatalf("sessions = %+v, 刷新后的会话应在原过期时间之后仍然存在", list)
+	}
+	if err := svc.RevokeSession(ctx, 1, claims.ID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+
+	// 吊销前签发的刷新令牌在吊销记录过期前都应被拒绝
+	clock = start.Add(25 * time.Hour)
+	if _, err := userService.RefreshToken(ctx, refreshed.RefreshToken); err == nil {
+		t.Error("吊销后刷新令牌应被拒绝")
+	}
+}
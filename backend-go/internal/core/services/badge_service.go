@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/shared"
+)
+
+const (
+	// BadgesKeyPrefix 用户徽章缓存键前缀
+	BadgesKeyPrefix = "badges:user:"
+
+	// DefaultBadgesTTL 徽章缓存时间，计分事件会提前刷新
+	DefaultBadgesTTL = 10 * time.Minute
+)
+
+// BadgeKind 徽章依据的统计项
+type BadgeKind string
+
+const (
+	BadgeKindStreak   BadgeKind = "streak"   // 最长连续预测正确场次
+	BadgeKindAccuracy BadgeKind = "accuracy" // 预测准确率（百分比）
+	BadgeKindPoints   BadgeKind = "points"   // 预测累计获得积分
+)
+
+// BadgeDefinition 徽章及其获得门槛
+type BadgeDefinition struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Kind           BadgeKind `json:"kind"`
+	Threshold      float64   `json:"threshold"`
+	MinPredictions int       `json:"minPredictions,omitempty"` // 已计分预测数下限，准确率徽章用于避免样本过少
+}
+
+// Badge 用户已获得的徽章
+type Badge struct {
+	BadgeDefinition
+	Value float64 `json:"value"` // 用户当前的统计值
+}
+
+// BadgeStats 计算徽章使用的用户预测统计，只统计已结束比赛的预测
+type BadgeStats struct {
+	ScoredPredictions  int     `json:"scoredPredictions"`
+	CorrectPredictions int     `json:"correctPredictions"`
+	BestStreak         int     `json:"bestStreak"`
+	CurrentStreak      int     `json:"currentStreak"`
+	Accuracy           float64 `json:"accuracy"`
+	Points             int     `json:"points"`
+}
+
+// DefaultBadgeDefinitions 默认徽章
+var DefaultBadgeDefinitions = []BadgeDefinition{
+	{ID: "streak_5", Name: "5 连中", Description: "连续 5 场预测正确", Kind: BadgeKindStreak, Threshold: 5},
+	{ID: "streak_10", Name: "10 连中", Description: "连续 10 场预测正确", Kind: BadgeKindStreak, Threshold: 10},
+	{ID: "accuracy_60", Name: "稳定发挥", Description: "20 场以上预测准确率达到 60%", Kind: BadgeKindAccuracy, Threshold: 60, MinPredictions: 20},
+	{ID: "accuracy_80", Name: "神预测", Description: "50 场以上预测准确率达到 80%", Kind: BadgeKindAccuracy, Threshold: 80, MinPredictions: 50},
+	{ID: "points_100", Name: "百分达人", Description: "预测累计获得 100 积分", Kind: BadgeKindPoints, Threshold: 100},
+	{ID: "points_1000", Name: "千分大师", Description: "预测累计获得 1000 积分", Kind: BadgeKindPoints, Threshold: 1000},
+}
+
+// BadgeConfig 徽章配置
+type BadgeConfig struct {
+	Definitions []BadgeDefinition // 为空时使用 DefaultBadgeDefinitions
+	CacheTTL    time.Duration     // 为 0 时使用 DefaultBadgesTTL
+}
+
+// badgePredictionSource 读取用户的预测（需预加载比赛），prediction.Repository 满足该接口
+type badgePredictionSource interface {
+	GetPredictionsByUser(ctx context.Context, userID uint) ([]prediction.Prediction, error)
+}
+
+// badgeCacheStore 缓存徽章使用的 Redis 操作
+type badgeCacheStore interface {
+	GetJSON(ctx context.Context, key string, dest interface{}) error
+	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// BadgeService 根据用户的连中、准确率与积分统计计算徽章
+//
+// 结果按用户缓存；作为 EventPointsCalculated 的事件处理器（worker 的异步计分）或由
+// MatchFinishService 同步结束比赛后调用 RefreshUsers，为本次计分涉及的用户重新计算。
+type BadgeService struct {
+	predictions badgePredictionSource
+	cache       badgeCacheStore
+	config      BadgeConfig
+	logger      *logrus.Logger
+}
+
+// NewBadgeService 创建徽章服务，cache 为空时不缓存
+func NewBadgeService(predictions badgePredictionSource, cache badgeCacheStore, config BadgeConfig, logger *logrus.Logger) *BadgeService {
+	if len(config.Definitions) == 0 {
+		config.Definitions = DefaultBadgeDefinitions
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultBadgesTTL
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &BadgeService{predictions: predictions, cache: cache, config: config, logger: logger}
+}
+
+// ComputeBadges 获取用户已获得的徽章，按配置顺序排列
+func (s *BadgeService) ComputeBadges(ctx context.Context, userID uint) ([]Badge, error) {
+	if s.cache != nil {
+		var cached []Badge
+		if err := s.cache.GetJSON(ctx, badgesKey(userID), &cached); err == nil {
+			return cached, nil
+		}
+	}
+	return s.recompute(ctx, userID)
+}
+
+// Handle 处理积分计算完成事件，重新计算涉及用户的徽章
+func (s *BadgeService) Handle(event shared.Event) error {
+	if event.GetType() != shared.EventPointsCalculated {
+		return fmt.Errorf("unexpected event type: %s", event.GetType())
+	}
+
+	payload, ok := event.GetPayload().(shared.PointsCalculatedPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload type for points calculated event")
+	}
+
+	userIDs := make([]uint, len(payload.Predictions))
+	for i, pred := range payload.Predictions {
+		userIDs[i] = pred.UserID
+	}
+	s.RefreshUsers(context.Background(), userIDs)
+	return nil
+}
+
+// RefreshUsers 重新计算并缓存给定用户的徽章，重复的用户只计算一次，失败只记录日志
+func (s *BadgeService) RefreshUsers(ctx context.Context, userIDs []uint) {
+	seen := make(map[uint]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if _, err := s.recompute(ctx, userID); err != nil {
+			s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to recompute badges")
+		}
+	}
+}
+
+// recompute 重新统计并写入缓存
+func (s *BadgeService) recompute(ctx context.Context, userID uint) ([]Badge, error) {
+	predictions, err := s.predictions.GetPredictionsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get predictions of user %d: %w", userID, err)
+	}
+
+	badges := EarnedBadges(ComputeBadgeStats(predictions), s.config.Definitions)
+	if s.cache != nil {
+		if err := s.cache.SetJSON(ctx, badgesKey(userID), badges, s.config.CacheTTL); err != nil {
+			s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to cache badges")
+		}
+	}
+	return badges, nil
+}
+
+// ComputeBadgeStats 按比赛开始时间统计已结束比赛的预测
+func ComputeBadgeStats(predictions []prediction.Prediction) BadgeStats {
+	scored := make([]prediction.Prediction, 0, len(predictions))
+	for _, p := range predictions {
+		if p.Match != nil && p.Match.Status == match.MatchStatusFinished {
+			scored = append(scored, p)
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		if !scored[i].Match.StartTime.Equal(scored[j].Match.StartTime) {
+			return scored[i].Match.StartTime.Before(scored[j].Match.StartTime)
+		}
+		return scored[i].ID < scored[j].ID
+	})
+
+	var stats BadgeStats
+	for _, p := range scored {
+		stats.ScoredPredictions++
+		stats.Points += p.EarnedPoints
+		if p.IsCorrect {
+			stats.CorrectPredictions++
+			stats.CurrentStreak++
+			if stats.CurrentStreak > stats.BestStreak {
+				stats.BestStreak = stats.CurrentStreak
+			}
+		} else {
+			stats.CurrentStreak = 0
+		}
+	}
+	if stats.ScoredPredictions > 0 {
+		stats.Accuracy = float64(stats.CorrectPredictions) / float64(stats.ScoredPredictions) * 100
+	}
+	return stats
+}
+
+// EarnedBadges 返回统计满足门槛的徽章
+func EarnedBadges(stats BadgeStats, definitions []BadgeDefinition) []Badge {
+	badges := make([]Badge, 0, len(definitions))
+	for _, def := range definitions {
+		if stats.ScoredPredictions < def.MinPredictions {
+			continue
+		}
+
+		var value float64
+		switch def.Kind {
+		case BadgeKindStreak:
+			value = float64(stats.BestStreak)
+		case BadgeKindAccuracy:
+			value = stats.Accuracy
+		case BadgeKindPoints:
+			value = float64(stats.Points)
+		default:
+			continue
+		}
+		if value >= def.Threshold {
+			badges = append(badges, Badge{BadgeDefinition: def, Value: value})
+		}
+	}
+	return badges
+}
+
+func badgesKey(userID uint) string {
+	return fmt.Sprintf("%s%d", BadgesKeyPrefix, userID)
+}
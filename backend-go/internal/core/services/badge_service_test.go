@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/shared"
+)
+
+// memoryBadgePredictions 按用户保存的预测，calls 记录读取次数
+type memoryBadgePredictions struct {
+	byUser map[uint][]prediction.Prediction
+	calls  int
+}
+
+func (s *memoryBadgePredictions) GetPredictionsByUser(ctx context.Context, userID uint) ([]prediction.Prediction, error) {
+	s.calls++
+	return s.byUser[userID], nil
+}
+
+// scoredPredictions 按结果序列生成已结束比赛的预测，正确的预测得 points 分
+func scoredPredictions(results string, points int) []prediction.Prediction {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	preds := make([]prediction.Prediction, len(results))
+	for i, r := range results {
+		preds[i] = prediction.Prediction{
+			ID:    uint(i + 1),
+			Match: &match.Match{ID: uint(i + 1), Status: match.MatchStatusFinished, StartTime: start.Add(time.Duration(i) * time.Hour)},
+		}
+		if r == '1' {
+			preds[i].IsCorrect = true
+			preds[i].EarnedPoints = points
+		}
+	}
+	return preds
+}
+
+func badgeIDs(badges []Badge) []string {
+	ids := make([]string, len(badges))
+	for i, b := range badges {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
+func newTestBadgeService(predictions *memoryBadgePredictions) *BadgeService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewBadgeService(predictions, newMemoryRollupStore(), BadgeConfig{
+		Definitions: []BadgeDefinition{
+			{ID: "streak_3", Kind: BadgeKindStreak, Threshold: 3},
+			{ID: "accuracy_75", Kind: BadgeKindAccuracy, Threshold: 75, MinPredictions: 8},
+			{ID: "points_30", Kind: BadgeKindPoints, Threshold: 30},
+		},
+	}, logger)
+}
+
+func TestBadgeService_Thresholds(t *testing.T) {
+	// 未结束比赛的预测不参与统计
+	pending := prediction.Prediction{ID: 99, IsCorrect: true, EarnedPoints: 100, Match: &match.Match{Status: match.MatchStatusLive}}
+
+	predictions := &memoryBadgePredictions{byUser: map[uint][]prediction.Prediction{
+		1: scoredPredictions("11101111", 5),                       // 连中 4，准确率 87.5%，35 分
+		2: scoredPredictions("1101101", 5),                        // 连中 2，准确率 71%，预测数不足 8，25 分
+		3: scoredPredictions("11011011", 10),                      // 连中 2，准确率 75% 恰好达标，60 分
+		4: append(scoredPredictions("110", 10), pending, pending), // 进行中的比赛不计入
+	}}
+	service := newTestBadgeService(predictions)
+
+	cases := []struct {
+		userID uint
+		want   []string
+	}{
+		{1, []string{"streak_3", "accuracy_75", "points_30"}},
+		{2, []string{}},
+		{3, []string{"accuracy_75", "points_30"}},
+		{4, []string{}},
+		{5, []string{}},
+	}
+	for _, tc := range cases {
+		badges, err := service.ComputeBadges(context.Background(), tc.userID)
+		if err != nil {
+			t.Fatalf("ComputeBadges(%d) error = %v", tc.userID, err)
+		}
+		got := badgeIDs(badges)
+		if len(got) != len(tc.want) {
+			t.Errorf("user %d badges = %v, want %v", tc.userID, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("user %d badges = %v, want %v", tc.userID, got, tc.want)
+				break
+			}
+		}
+	}
+
+	stats := ComputeBadgeStats(predictions.byUser[1])
+	if stats.BestStreak != 4 || stats.CurrentStreak != 4 || stats.ScoredPredictions != 8 || stats.Points != 35 {
+		t.Errorf("stats = %+v, want 最长连中 4、8 场、35 分", stats)
+	}
+}
+
+func TestBadgeService_RecomputesOnScoringEvent(t *testing.T) {
+	predictions := &memoryBadgePredictions{byUser: map[uint][]prediction.Prediction{
+		7: scoredPredictions("011", 10),
+	}}
+	service := newTestBadgeService(predictions)
+
+	badges, err := service.ComputeBadges(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("ComputeBadges() error = %v", err)
+	}
+	if len(badges) != 0 {
+		t.Fatalf("badges = %v, want 尚未获得徽章", badgeIDs(badges))
+	}
+
+	// 新的预测计分后缓存结果不变，直到收到计分事件
+	predictions.byUser[7] = scoredPredictions("0111", 10)
+	if badges, _ := service.ComputeBadges(context.Background(), 7); len(badges) != 0 || predictions.calls != 1 {
+		t.Fatalf("badges = %v, calls = %d, want 命中缓存", badgeIDs(badges), predictions.calls)
+	}
+
+	event := shared.NewEvent(shared.EventPointsCalculated, shared.PointsCalculatedPayload{
+		MatchID:     4,
+		Predictions: []shared.PredictionPointsInfo{{PredictionID: 4, UserID: 7, Points: 10, IsCorrect: true}},
+	})
+	if err := service.Handle(event); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	badges, err = service.ComputeBadges(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("ComputeBadges() error = %v", err)
+	}
+	if got := badgeIDs(badges); len(got) != 2 || got[0] != "streak_3" || got[1] != "points_30" {
+		t.Errorf("badges = %v, want [streak_3 points_30]", got)
+	}
+	if badges[0].Value != 3 {
+		t.Errorf("streak value = %v, want 3", badges[0].Value)
+	}
+	if predictions.calls != 2 {
+		t.Errorf("calls = %d, want 事件触发一次重新计算", predictions.calls)
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/leaderboard"
+)
+
+// leaderboardViewsKey 统计事件处理器维护的按赛事排行榜查看累计次数
+const leaderboardViewsKey = "stats:leaderboard_views:tournament:%s"
+
+// DefaultWarmupTournaments 默认预热的赛事
+var DefaultWarmupTournaments = []string{"SPRING", "SUMMER", "AUTUMN", "WINTER"}
+
+// cacheWarmerStore 读取访问计数使用的 Redis 操作
+type cacheWarmerStore interface {
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+}
+
+// leaderboardRefresher 刷新单个赛事的排行榜缓存，leaderboard.Service 满足该接口
+type leaderboardRefresher interface {
+	RefreshLeaderboard(ctx context.Context, tournament string) error
+}
+
+// leaderboardBatchRefresher 批量刷新赛事的排行榜缓存，leaderboard.Service 满足该接口
+type leaderboardBatchRefresher interface {
+	RefreshAll(ctx context.Context, tournaments []string, concurrency int) (leaderboard.RefreshReport, error)
+}
+
+// CacheWarmerConfig 排行榜缓存预热配置
+type CacheWarmerConfig struct {
+	Tournaments    []string      // 参与预热的赛事，默认四个赛季
+	Budget         int           // Warm 每轮最多刷新的赛事数，<=0 表示全部
+	MaxStaleCycles int           // 赛事连续未刷新的轮数达到该值后优先刷新，避免冷门赛事长期不刷新，默认 6
+	Concurrency    int           // 同时刷新的赛事数上限，默认 1（串行）
+	Interval       time.Duration // Run 的预热间隔，默认 5 分钟
+}
+
+// WarmupReport 一轮预热的结果
+type WarmupReport struct {
+	Refreshed []string         `json:"refreshed"` // 按刷新顺序
+	Failed    []string         `json:"failed"`
+	Deferred  []string         `json:"deferred"` // 超出预算留到后续轮次的赛事
+	Views     map[string]int64 `json:"views"`
+}
+
+// CacheWarmer 按访问频率分配排行榜缓存刷新
+//
+// 每轮按“未刷新轮数 × (查看次数 + 1)”为赛事排序，在预算内刷新得分最高的赛事：
+// 热门赛事几乎每轮刷新，冷门赛事的得分增长较慢、刷新间隔更长，
+// 连续未刷新达到 MaxStaleCycles 轮的赛事优先刷新。
+//
+// 未刷新轮数保存在进程内，重启后从零开始；多个进程同时运行时各自计数，
+// 因此只在 worker 中运行。
+type CacheWarmer struct {
+	store     cacheWarmerStore
+	refresher leaderboardBatchRefresher
+	config    CacheWarmerConfig
+	logger    *logrus.Logger
+
+	heartbeat Heartbeat
+
+	mu          sync.Mutex
+	staleCycles map[string]int // 赛事自上次成功刷新以来经过的轮数
+}
+
+// NewCacheWarmer 创建排行榜缓存预热服务
+func NewCacheWarmer(store cacheWarmerStore, refresher leaderboardBatchRefresher, config CacheWarmerConfig, logger *logrus.Logger) *CacheWarmer {
+	if len(config.Tournaments) == 0 {
+		config.Tournaments = DefaultWarmupTournaments
+	}
+	if config.MaxStaleCycles <= 0 {
+		config.MaxStaleCycles = 6
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &CacheWarmer{
+		store:       store,
+		refresher:   refresher,
+		config:      config,
+		logger:      logger,
+		staleCycles: make(map[string]int, len(config.Tournaments)),
+	}
+}
+
+// SetHeartbeat 设置每轮预热后上报的心跳
+func (w *CacheWarmer) SetHeartbeat(heartbeat Heartbeat) {
+	w.heartbeat = heartbeat
+}
+
+// Interval 返回预热间隔
+func (w *CacheWarmer) Interval() time.Duration {
+	return w.config.Interval
+}
+
+// Run 按配置间隔执行预热，直到 ctx 取消
+func (w *CacheWarmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := w.Warm(ctx)
+			if w.heartbeat != nil {
+				w.heartbeat.Beat(ctx)
+			}
+			if err != nil {
+				w.logger.WithError(err).WithField("failed", report.Failed).Warn("Failed to warmup leaderboard cache")
+				continue
+			}
+			w.logger.WithFields(logrus.Fields{
+				"refreshed": report.Refreshed,
+				"deferred":  report.Deferred,
+			}).Debug("Leaderboard cache warmed up")
+		}
+	}
+}
+
+// Warm 按配置的预算执行一轮预热
+func (w *CacheWarmer) Warm(ctx context.Context) (WarmupReport, error) {
+	return w.WarmByPriority(ctx, w.config.Budget)
+}
+
+// WarmByPriority 执行一轮预热，最多刷新 budget 个赛事，budget<=0 时刷新全部赛事
+//
+// 读取访问计数失败时按相同权重处理，仍然优先刷新最久未刷新的赛事。
+// 刷新失败的赛事保留未刷新轮数，下一轮继续优先。
+func (w *CacheWarmer) WarmByPriority(ctx context.Context, budget int) (WarmupReport, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tournaments := w.config.Tournaments
+	if budget <= 0 || budget > len(tournaments) {
+		budget = len(tournaments)
+	}
+	report := WarmupReport{Views: w.loadViews(ctx)}
+
+	type candidate struct {
+		tournament string
+		order      int
+		stale      int
+		score      int64
+	}
+	candidates := make([]candidate, len(tournaments))
+	for i, tournament := range tournaments {
+		w.staleCycles[tournament]++
+		stale := w.staleCycles[tournament]
+		candidates[i] = candidate{
+			tournament: tournament,
+			order:      i,
+			stale:      stale,
+			score:      int64(stale) * (report.Views[tournament] + 1),
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		aOverdue, bOverdue := a.stale >= w.config.MaxStaleCycles, b.stale >= w.config.MaxStaleCycles
+		if aOverdue != bOverdue {
+			return aOverdue
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if report.Views[a.tournament] != report.Views[b.tournament] {
+			return report.Views[a.tournament] > report.Views[b.tournament]
+		}
+		return a.order < b.order
+	})
+
+	selected := make([]string, 0, budget)
+	for i, c := range candidates {
+		if i >= budget {
+			report.Deferred = append(report.Deferred, c.tournament)
+			continue
+		}
+		selected = append(selected, c.tournament)
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	// 失败原因记录在报告中，按赛事更新未刷新轮数
+	refreshed, _ := w.refresher.RefreshAll(ctx, selected, w.config.Concurrency)
+	for _, tournament := range refreshed.Refreshed {
+		w.staleCycles[tournament] = 0
+	}
+	report.Refreshed = refreshed.Refreshed
+	for _, tournament := range selected {
+		if reason, ok := refreshed.Failed[tournament]; ok {
+			w.logger.WithField("tournament", tournament).Warnf("Failed to warmup leaderboard cache: %s", reason)
+			report.Failed = append(report.Failed, tournament)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	if len(report.Failed) > 0 {
+		return report, fmt.Errorf("failed to warm %d of %d leaderboards", len(report.Failed), budget)
+	}
+	return report, nil
+}
+
+// loadViews 读取各赛事的排行榜查看次数，失败时返回全 0
+func (w *CacheWarmer) loadViews(ctx context.Context) map[string]int64 {
+	views := make(map[string]int64, len(w.config.Tournaments))
+	keys := make([]string, len(w.config.Tournaments))
+	for i, tournament := range w.config.Tournaments {
+		keys[i] = fmt.Sprintf(leaderboardViewsKey, tournament)
+	}
+
+	values, err := w.store.MGet(ctx, keys...)
+	if err != nil {
+		w.logger.WithError(err).Warn("Failed to read leaderboard view counts, warming by staleness only")
+		return views
+	}
+	for i, value := range values {
+		if count, ok := parseStatsCount(value); ok && i < len(w.config.Tournaments) {
+			views[w.config.Tournaments[i]] = count
+		}
+	}
+	return views
+}
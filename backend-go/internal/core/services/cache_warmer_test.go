@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/leaderboard"
+)
+
+// recordingRefresher 记录刷新顺序，failing 中的赛事刷新失败
+type recordingRefresher struct {
+	calls   []string
+	failing map[string]bool
+}
+
+func (r *recordingRefresher) RefreshLeaderboard(ctx context.Context, tournament string) error {
+	r.calls = append(r.calls, tournament)
+	if r.failing[tournament] {
+		return errors.New("refresh failed")
+	}
+	return nil
+}
+
+// RefreshAll 按顺序逐个刷新
+func (r *recordingRefresher) RefreshAll(ctx context.Context, tournaments []string, concurrency int) (leaderboard.RefreshReport, error) {
+	report := leaderboard.RefreshReport{}
+	for _, tournament := range tournaments {
+		if err := r.RefreshLeaderboard(ctx, tournament); err != nil {
+			if report.Failed == nil {
+				report.Failed = map[string]string{}
+			}
+			report.Failed[tournament] = err.Error()
+			continue
+		}
+		report.Refreshed = append(report.Refreshed, tournament)
+	}
+	if len(report.Failed) > 0 {
+		return report, errors.New("refresh failed")
+	}
+	return report, nil
+}
+
+func newTestCacheWarmer(views map[string]string, refresher *recordingRefresher) *CacheWarmer {
+	store := newMemoryRollupStore()
+	for tournament, count := range views {
+		store.values["stats:leaderboard_views:tournament:"+tournament] = count
+	}
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewCacheWarmer(store, refresher, CacheWarmerConfig{
+		Tournaments:    []string{"WINTER", "AUTUMN", "SUMMER", "SPRING"},
+		MaxStaleCycles: 6,
+	}, log)
+}
+
+func TestCacheWarmer_WarmByPriority(t *testing.T) {
+	ctx := context.Background()
+	refresher := &recordingRefresher{}
+	warmer := newTestCacheWarmer(map[string]string{"SPRING": "1000", "SUMMER": "100", "AUTUMN": "3"}, refresher)
+
+	report, err := warmer.WarmByPriority(ctx, 2)
+	if err != nil {
+		t.Fatalf("WarmByPriority() error = %v", err)
+	}
+	if want := []string{"SPRING", "SUMMER"}; !reflect.DeepEqual(report.Refreshed, want) {
+		t.Errorf("首轮刷新 = %v, want %v", report.Refreshed, want)
+	}
+	if want := []string{"AUTUMN", "WINTER"}; !reflect.DeepEqual(report.Deferred, want) {
+		t.Errorf("首轮延后 = %v, want %v", report.Deferred, want)
+	}
+
+	counts := map[string]int{}
+	for _, tournament := range report.Refreshed {
+		counts[tournament]++
+	}
+	for cycle := 2; cycle <= 12; cycle++ {
+		report, err := warmer.WarmByPriority(ctx, 2)
+		if err != nil {
+			t.Fatalf("cycle %d: WarmByPriority() error = %v", cycle, err)
+		}
+		if len(report.Refreshed) > 2 {
+			t.Errorf("cycle %d: 刷新 %d 个赛事，超出预算 2", cycle, len(report.Refreshed))
+		}
+		for _, tournament := range report.Refreshed {
+			counts[tournament]++
+		}
+	}
+
+	if len(refresher.calls) != 24 {
+		t.Errorf("总刷新次数 = %d, want 24", len(refresher.calls))
+	}
+	if counts["SPRING"] < counts["SUMMER"] || counts["SUMMER"] <= counts["AUTUMN"] || counts["SUMMER"] <= counts["WINTER"] {
+		t.Errorf("刷新次数 = %v，热门赛事应刷新得更频繁", counts)
+	}
+	// 冷门赛事在 MaxStaleCycles 内至少刷新一次
+	if counts["AUTUMN"] == 0 || counts["WINTER"] == 0 {
+		t.Errorf("刷新次数 = %v，冷门赛事不应长期不刷新", counts)
+	}
+}
+
+func TestCacheWarmer_FailedRefreshStaysPrioritized(t *testing.T) {
+	ctx := context.Background()
+	refresher := &recordingRefresher{failing: map[string]bool{"SPRING": true}}
+	warmer := newTestCacheWarmer(map[string]string{"SPRING": "1000", "SUMMER": "100"}, refresher)
+
+	report, err := warmer.WarmByPriority(ctx, 1)
+	if err == nil || !reflect.DeepEqual(report.Failed, []string{"SPRING"}) {
+		t.Fatalf("WarmByPriority() = %+v, %v, want SPRING failed", report, err)
+	}
+
+	refresher.failing = nil
+	report, err = warmer.WarmByPriority(ctx, 1)
+	if err != nil {
+		t.Fatalf("WarmByPriority() error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Refreshed, []string{"SPRING"}) {
+		t.Errorf("刷新 = %v, 失败的热门赛事应在下一轮优先重试", report.Refreshed)
+	}
+}
+
+func TestCacheWarmer_ZeroBudgetWarmsAll(t *testing.T) {
+	refresher := &recordingRefresher{}
+	warmer := newTestCacheWarmer(nil, refresher)
+
+	report, err := warmer.WarmByPriority(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("WarmByPriority() error = %v", err)
+	}
+	// 没有访问数据时按配置顺序刷新全部赛事
+	if want := []string{"WINTER", "AUTUMN", "SUMMER", "SPRING"}; !reflect.DeepEqual(report.Refreshed, want) {
+		t.Errorf("刷新 = %v, want %v", report.Refreshed, want)
+	}
+}
+
+// chanHeartbeat 每次上报心跳时发送通知
+type chanHeartbeat chan struct{}
+
+func (h chanHeartbeat) Beat(ctx context.Context) {
+	h <- struct{}{}
+}
+
+func TestCacheWarmer_RunWarmsEachInterval(t *testing.T) {
+	refresher := &recordingRefresher{}
+	warmer := newTestCacheWarmer(nil, refresher)
+	warmer.config.Interval = 5 * time.Millisecond
+	warmer.config.Budget = 1
+	beats := make(chanHeartbeat)
+	warmer.SetHeartbeat(beats)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		warmer.Run(ctx)
+		close(done)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-beats:
+		case <-time.After(time.Second):
+			t.Fatal("Run 未按间隔执行预热")
+		}
+	}
+	cancel()
+	// 取消后可能还有一次心跳在等待发送
+	go func() {
+		for range beats {
+		}
+	}()
+	<-done
+
+	if len(refresher.calls) < 2 {
+		t.Errorf("calls = %v, want 每轮刷新一个赛事", refresher.calls)
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+)
+
+// 数据保留任务默认值
+const (
+	DefaultRetentionPeriod    = 365 * 24 * time.Hour
+	DefaultRetentionInterval  = 24 * time.Hour
+	DefaultRetentionBatchSize = 100
+	DefaultRetentionPrefix    = "archives/retention"
+)
+
+// retentionStatuses 可以清理的比赛状态，未结束的比赛不清理
+var retentionStatuses = []domain.MatchStatus{domain.MatchStatusFinished, domain.MatchStatusCancelled}
+
+// archiveWriter 写入归档文件，storage.Writer 满足该接口
+type archiveWriter interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// DataRetentionConfig 数据保留配置
+type DataRetentionConfig struct {
+	Enabled   bool          // 默认关闭，未启用时 Purge 直接返回错误
+	Retention time.Duration // 开始时间早于该时长之前的已结束比赛会被清理，默认 365 天
+	Interval  time.Duration // 执行间隔，默认 24 小时
+	BatchSize int           // 每批清理的比赛数，默认 100
+	Prefix    string        // 归档文件键前缀，默认 archives/retention
+}
+
+// RetentionReport 一次清理的结果
+type RetentionReport struct {
+	Cutoff        time.Time     `json:"cutoff"`
+	Batches       int           `json:"batches"`
+	Matches       int           `json:"matches"`
+	Predictions   int           `json:"predictions"`
+	Votes         int           `json:"votes"`
+	Modifications int           `json:"modifications"`
+	Archives      []string      `json:"archives,omitempty"` // 写入的归档文件键
+	Duration      time.Duration `json:"duration"`
+}
+
+// retentionArchive 单批清理的归档内容，保存各表的原始行
+type retentionArchive struct {
+	Cutoff        time.Time                `json:"cutoff"`
+	ArchivedAt    time.Time                `json:"archivedAt"`
+	Matches       []map[string]interface{} `json:"matches"`
+	Predictions   []map[string]interface{} `json:"predictions"`
+	Votes         []map[string]interface{} `json:"votes"`
+	Modifications []map[string]interface{} `json:"predictionModifications"`
+}
+
+// DataRetention 归档并清理超过保留期的比赛及其预测、投票与修改记录
+//
+// 按比赛ID分批处理：每批先把原始行写入归档存储，再在一个事务内把预测与投票按用户
+// 累加到 user_archived_stats，并按外键依赖顺序删除 修改记录 → 投票 → 预测 → 比赛。
+// 归档写入失败时该批不删除任何数据；用户积分保存在 users 表，不受清理影响。
+type DataRetention struct {
+	db      *gorm.DB
+	archive archiveWriter
+	config  DataRetentionConfig
+	logger  *logrus.Logger
+	now     func() time.Time
+
+	heartbeat Heartbeat
+}
+
+// NewDataRetention 创建数据保留任务
+func NewDataRetention(db *gorm.DB, archive archiveWriter, config DataRetentionConfig, logger *logrus.Logger) *DataRetention {
+	if config.Retention <= 0 {
+		config.Retention = DefaultRetentionPeriod
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultRetentionInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultRetentionBatchSize
+	}
+	if config.Prefix == "" {
+		config.Prefix = DefaultRetentionPrefix
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &DataRetention{
+		db:      db,
+		archive: archive,
+		config:  config,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// SetHeartbeat 设置每次清理后上报的心跳
+func (r *DataRetention) SetHeartbeat(heartbeat Heartbeat) {
+	r.heartbeat = heartbeat
+}
+
+// Interval 返回执行间隔
+func (r *DataRetention) Interval() time.Duration {
+	return r.config.Interval
+}
+
+// Run 按配置间隔执行清理，直到 ctx 取消
+func (r *DataRetention) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := r.Purge(ctx)
+			if r.heartbeat != nil {
+				r.heartbeat.Beat(ctx)
+			}
+			if err != nil {
+				r.logger.WithError(err).Warn("Failed to purge expired matches")
+				continue
+			}
+			r.logger.WithFields(logrus.Fields{
+				"cutoff":      report.Cutoff,
+				"matches":     report.Matches,
+				"predictions": report.Predictions,
+				"votes":       report.Votes,
+				"duration":    report.Duration,
+			}).Info("Expired matches purged")
+		}
+	}
+}
+
+// Purge 归档并清理所有超过保留期的比赛，返回已完成批次的统计
+func (r *DataRetention) Purge(ctx context.Context) (*RetentionReport, error) {
+	if !r.config.Enabled {
+		return nil, errors.New("data retention is disabled")
+	}
+	if r.archive == nil {
+		return nil, errors.New("data retention requires a writable archive storage")
+	}
+
+	start := r.now()
+	report := &RetentionReport{Cutoff: start.Add(-r.config.Retention)}
+	defer func() { report.Duration = r.now().Sub(start) }()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		var matchIDs []uint
+		err := r.db.WithContext(ctx).
+			Model(&domain.Match{}).
+			Where("start_time < ? AND status IN ?", report.Cutoff, retentionStatuses).
+			Order("id ASC").
+			Limit(r.config.BatchSize).
+			Pluck("id", &matchIDs).Error
+		if err != nil {
+			return report, fmt.Errorf("failed to list expired matches: %w", err)
+		}
+		if len(matchIDs) == 0 {
+			return report, nil
+		}
+
+		if err := r.purgeBatch(ctx, report, matchIDs); err != nil {
+			return report, err
+		}
+		if len(matchIDs) < r.config.BatchSize {
+			return report, nil
+		}
+	}
+}
+
+// purgeBatch 归档并删除一批比赛
+func (r *DataRetention) purgeBatch(ctx context.Context, report *RetentionReport, matchIDs []uint) error {
+	db := r.db.WithContext(ctx)
+
+	var predictionIDs, voteIDs, modificationIDs []uint
+	if err := db.Model(&prediction.Prediction{}).Where("matchId IN ?", matchIDs).Pluck("id", &predictionIDs).Error; err != nil {
+		return fmt.Errorf("failed to list predictions of expired matches: %w", err)
+	}
+	if len(predictionIDs) > 0 {
+		if err := db.Model(&prediction.Vote{}).Where("prediction_id IN ?", predictionIDs).Pluck("id", &voteIDs).Error; err != nil {
+			return fmt.Errorf("failed to list votes of expired matches: %w", err)
+		}
+	}
+	if err := db.Model(&domain.PredictionModification{}).Where("match_id IN ?", matchIDs).Pluck("id", &modificationIDs).Error; err != nil {
+		return fmt.Errorf("failed to list prediction modifications of expired matches: %w", err)
+	}
+
+	key, err := r.writeArchive(ctx, report.Cutoff, matchIDs, predictionIDs, voteIDs, modificationIDs)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := archiveUserStats(tx, predictionIDs, voteIDs, r.now()); err != nil {
+			return err
+		}
+		// 外键依赖顺序删除，只删除已归档的行；归档后新增的子记录会使删除失败并回滚，下次重试
+		steps := []struct {
+			model interface{}
+			ids   []uint
+		}{
+			{&domain.PredictionModification{}, modificationIDs},
+			{&prediction.Vote{}, voteIDs},
+			{&prediction.Prediction{}, predictionIDs},
+			{&domain.Match{}, matchIDs},
+		}
+		for _, step := range steps {
+			if len(step.ids) == 0 {
+				continue
+			}
+			if err := tx.Where("id IN ?", step.ids).Delete(step.model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge matches %d-%d: %w", matchIDs[0], matchIDs[len(matchIDs)-1], err)
+	}
+
+	report.Batches++
+	report.Matches += len(matchIDs)
+	report.Predictions += len(predictionIDs)
+	report.Votes += len(voteIDs)
+	report.Modifications += len(modificationIDs)
+	report.Archives = append(report.Archives, key)
+	return nil
+}
+
+// writeArchive 读取该批各表的原始行并写入归档存储，返回归档文件键
+func (r *DataRetention) writeArchive(ctx context.Context, cutoff time.Time, matchIDs, predictionIDs, voteIDs, modificationIDs []uint) (string, error) {
+	archivedAt := r.now().UTC()
+	archive := retentionArchive{Cutoff: cutoff.UTC(), ArchivedAt: archivedAt}
+
+	tables := []struct {
+		table string
+		ids   []uint
+		dest  *[]map[string]interface{}
+	}{
+		{domain.Match{}.TableName(), matchIDs, &archive.Matches},
+		{prediction.Prediction{}.TableName(), predictionIDs, &archive.Predictions},
+		{prediction.Vote{}.TableName(), voteIDs, &archive.Votes},
+		{domain.PredictionModification{}.TableName(), modificationIDs, &archive.Modifications},
+	}
+	for _, t := range tables {
+		rows := make([]map[string]interface{}, 0, len(t.ids))
+		if len(t.ids) > 0 {
+			if err := r.db.WithContext(ctx).Table(t.table).Where("id IN ?", t.ids).Order("id ASC").Find(&rows).Error; err != nil {
+				return "", fmt.Errorf("failed to read %s for archive: %w", t.table, err)
+			}
+		}
+		*t.dest = rows
+	}
+
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal archive: %w", err)
+	}
+	key := fmt.Sprintf("%s/%s/matches-%d-%d.json", r.config.Prefix, archivedAt.Format("20060102T150405Z"), matchIDs[0], matchIDs[len(matchIDs)-1])
+	if err := r.archive.Put(ctx, key, data); err != nil {
+		return "", fmt.Errorf("failed to write archive %s: %w", key, err)
+	}
+	return key, nil
+}
+
+// archiveUserStats 将待删除的预测与投票按用户累加到归档统计
+func archiveUserStats(tx *gorm.DB, predictionIDs, voteIDs []uint, now time.Time) error {
+	stats := make(map[uint]*domain.UserArchivedStats)
+	get := func(userID uint) *domain.UserArchivedStats {
+		if stats[userID] == nil {
+			stats[userID] = &domain.UserArchivedStats{UserID: userID, UpdatedAt: now}
+		}
+		return stats[userID]
+	}
+
+	if len(predictionIDs) > 0 {
+		var rows []domain.UserArchivedStats
+		err := tx.Model(&prediction.Prediction{}).
+			Select("userId AS user_id, COUNT(*) AS predictions, "+
+				"SUM(CASE WHEN isCorrect THEN 1 ELSE 0 END) AS correct_predictions, "+
+				"COALESCE(SUM(earnedPoints), 0) AS earned_points").
+			Where("id IN ?", predictionIDs).
+			Group("userId").
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to aggregate predictions: %w", err)
+		}
+		for _, row := range rows {
+			s := get(row.UserID)
+			s.Predictions, s.CorrectPredictions, s.EarnedPoints = row.Predictions, row.CorrectPredictions, row.EarnedPoints
+		}
+	}
+	if len(voteIDs) > 0 {
+		var rows []domain.UserArchivedStats
+		err := tx.Model(&prediction.Vote{}).
+			Select("user_id, COUNT(*) AS votes_cast").
+			Where("id IN ?", voteIDs).
+			Group("user_id").
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to aggregate votes: %w", err)
+		}
+		for _, row := range rows {
+			get(row.UserID).VotesCast = row.VotesCast
+		}
+	}
+
+	for _, s := range stats {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"predictions":         gorm.Expr("predictions + ?", s.Predictions),
+				"correct_predictions": gorm.Expr("correct_predictions + ?", s.CorrectPredictions),
+				"earned_points":       gorm.Expr("earned_points + ?", s.EarnedPoints),
+				"votes_cast":          gorm.Expr("votes_cast + ?", s.VotesCast),
+				"updated_at":          now,
+			}),
+		}).Create(s).Error
+		if err != nil {
+			return fmt.Errorf("failed to archive stats of user %d: %w", s.UserID, err)
+		}
+	}
+	return nil
+}
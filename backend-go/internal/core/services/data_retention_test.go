@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/storage"
+)
+
+// failingArchive 写入总是失败的归档存储
+type failingArchive struct{}
+
+func (failingArchive) Put(ctx context.Context, key string, data []byte) error {
+	return errors.New("storage unavailable")
+}
+
+func newRetentionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	// 开启外键约束，删除顺序错误时会失败
+	dsn := filepath.Join(t.TempDir(), "retention.sqlite") + "?_foreign_keys=on"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&user.User{}, &domain.Match{}, &prediction.Prediction{}, &prediction.Vote{},
+		&domain.PredictionModification{}, &domain.UserArchivedStats{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	return db
+}
+
+// seedRetentionData 生成两场过期比赛、一场过期但未结束的比赛与一场近期比赛
+func seedRetentionData(t *testing.T, db *gorm.DB, now time.Time) {
+	t.Helper()
+	old := now.AddDate(-2, 0, 0)
+	recent := now.AddDate(0, -1, 0)
+
+	rows := []interface{}{
+		&user.User{ID: 1, Username: "alice", Email: "alice@example.com", Password: "x", Points: 30},
+		&user.User{ID: 2, Username: "bob", Email: "bob@example.com", Password: "x", Points: 10},
+		&domain.Match{ID: 1, TeamA: "JDG", TeamB: "BLG", Status: domain.MatchStatusFinished, StartTime: old, Winner: "A"},
+		&domain.Match{ID: 2, TeamA: "TES", TeamB: "LNG", Status: domain.MatchStatusCancelled, StartTime: old.Add(time.Hour)},
+		&domain.Match{ID: 3, TeamA: "WBG", TeamB: "NIP", Status: domain.MatchStatusUpcoming, StartTime: old.Add(2 * time.Hour)},
+		&domain.Match{ID: 4, TeamA: "EDG", TeamB: "RNG", Status: domain.MatchStatusFinished, StartTime: recent, Winner: "B"},
+		&prediction.Prediction{ID: 10, UserID: 1, MatchID: 1, PredictedWinner: "A", IsCorrect: true, EarnedPoints: 20},
+		&prediction.Prediction{ID: 11, UserID: 2, MatchID: 1, PredictedWinner: "B"},
+		&prediction.Prediction{ID: 12, UserID: 1, MatchID: 2, PredictedWinner: "A"},
+		&prediction.Prediction{ID: 13, UserID: 1, MatchID: 3, PredictedWinner: "A"},
+		&prediction.Prediction{ID: 14, UserID: 2, MatchID: 4, PredictedWinner: "B", IsCorrect: true, EarnedPoints: 10},
+		&prediction.Vote{ID: 20, UserID: 2, PredictionID: 10},
+		&prediction.Vote{ID: 21, UserID: 1, PredictionID: 11},
+		&prediction.Vote{ID: 22, UserID: 1, PredictionID: 14},
+		&domain.PredictionModification{ID: 30, UserID: 1, MatchID: 1, PredictionID: 10, ModificationType: domain.ModificationWinner},
+		&domain.PredictionModification{ID: 31, UserID: 2, MatchID: 4, PredictionID: 14, ModificationType: domain.ModificationWinner},
+	}
+	for _, row := range rows {
+		if err := db.Omit(retentionSeedOmit).Create(row).Error; err != nil {
+			t.Fatalf("写入测试数据失败: %v", err)
+		}
+	}
+}
+
+// retentionSeedOmit 写入测试数据时跳过关联
+const retentionSeedOmit = "User,Match,Prediction,Predictions,Votes"
+
+func remainingIDs(t *testing.T, db *gorm.DB, model interface{}) []uint {
+	t.Helper()
+	var ids []uint
+	if err := db.Model(model).Order("id ASC").Pluck("id", &ids).Error; err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, name string, got []uint, want ...uint) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func newTestDataRetention(db *gorm.DB, archive archiveWriter, now time.Time) *DataRetention {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	retention := NewDataRetention(db, archive, DataRetentionConfig{Enabled: true, BatchSize: 1}, log)
+	retention.now = func() time.Time { return now }
+	return retention
+}
+
+func TestDataRetention_PurgesOnlyExpiredMatches(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	db := newRetentionTestDB(t)
+	seedRetentionData(t, db, now)
+
+	dir := t.TempDir()
+	archive, err := storage.NewLocalStorage(storage.LocalConfig{Dir: dir, SigningKey: "retention-test"})
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	retention := newTestDataRetention(db, archive, now)
+
+	report, err := retention.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if report.Batches != 2 || report.Matches != 2 || report.Predictions != 3 || report.Votes != 2 || report.Modifications != 1 {
+		t.Errorf("report = %+v, want 2 批共 2 场比赛、3 条预测、2 票、1 条修改记录", report)
+	}
+
+	// 未结束的过期比赛与近期比赛及其子记录保留
+	assertIDs(t, "matches", remainingIDs(t, db, &domain.Match{}), 3, 4)
+	assertIDs(t, "predictions", remainingIDs(t, db, &prediction.Prediction{}), 13, 14)
+	assertIDs(t, "votes", remainingIDs(t, db, &prediction.Vote{}), 22)
+	assertIDs(t, "modifications", remainingIDs(t, db, &domain.PredictionModification{}), 31)
+
+	// 清理的数据汇总到用户归档统计，用户积分不变
+	var stats []domain.UserArchivedStats
+	db.Order("user_id ASC").Find(&stats)
+	if len(stats) != 2 {
+		t.Fatalf("archived stats = %+v, want 2 个用户", stats)
+	}
+	if s := stats[0]; s.UserID != 1 || s.Predictions != 2 || s.CorrectPredictions != 1 || s.EarnedPoints != 20 || s.VotesCast != 1 {
+		t.Errorf("alice stats = %+v, want 2 条预测、1 条正确、20 分、投出 1 票", s)
+	}
+	if s := stats[1]; s.UserID != 2 || s.Predictions != 1 || s.CorrectPredictions != 0 || s.VotesCast != 1 {
+		t.Errorf("bob stats = %+v, want 1 条预测、投出 1 票", s)
+	}
+	var points []int
+	db.Model(&user.User{}).Order("id ASC").Pluck("points", &points)
+	if len(points) != 2 || points[0] != 30 || points[1] != 10 {
+		t.Errorf("user points = %v, want [30 10]", points)
+	}
+
+	// 归档包含被删除的原始行
+	if len(report.Archives) != 2 {
+		t.Fatalf("archives = %v, want 2 个文件", report.Archives)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(report.Archives[0])))
+	if err != nil {
+		t.Fatalf("读取归档失败: %v", err)
+	}
+	var archived retentionArchive
+	if err := json.Unmarshal(data, &archived); err != nil {
+		t.Fatalf("解析归档失败: %v", err)
+	}
+	if len(archived.Matches) != 1 || len(archived.Predictions) != 2 || len(archived.Votes) != 2 || len(archived.Modifications) != 1 {
+		t.Errorf("archive = %d 场比赛、%d 条预测、%d 票、%d 条修改, want 1/2/2/1",
+			len(archived.Matches), len(archived.Predictions), len(archived.Votes), len(archived.Modifications))
+	}
+
+	// 再次执行累加不会重复
+	if report, err := retention.Purge(ctx); err != nil || report.Matches != 0 {
+		t.Errorf("second Purge() = %+v, %v, want 无可清理数据", report, err)
+	}
+}
+
+func TestDataRetention_KeepsDataWhenArchiveFails(t *testing.T) {
+	now := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	db := newRetentionTestDB(t)
+	seedRetentionData(t, db, now)
+
+	if _, err := newTestDataRetention(db, failingArchive{}, now).Purge(context.Background()); err == nil {
+		t.Fatal("Purge() 应在归档失败时返回错误")
+	}
+	assertIDs(t, "matches", remainingIDs(t, db, &domain.Match{}), 1, 2, 3, 4)
+	assertIDs(t, "votes", remainingIDs(t, db, &prediction.Vote{}), 20, 21, 22)
+
+	disabled := NewDataRetention(db, failingArchive{}, DataRetentionConfig{}, nil)
+	if _, err := disabled.Purge(context.Background()); err == nil {
+		t.Error("未启用时 Purge() 应返回错误")
+	}
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+// DevSeedName 开发数据在 seed_data 表中的记录名，存在时不再重复生成
+const DevSeedName = "dev_dataset"
+
+// devSeedCorrectPoints 猜中胜方时每倍信心获得的积分
+const devSeedCorrectPoints = 3
+
+// devSeedTeams 生成比赛使用的队伍
+var devSeedTeams = []string{"JDG", "BLG", "TES", "LNG", "WBG", "NIP", "EDG", "RNG", "IG", "FPX", "OMG", "AL", "WE", "LGD", "UP", "TT"}
+
+// devSeedTournaments 比赛按顺序轮流分配的赛事
+var devSeedTournaments = []domain.Tournament{domain.TournamentSpring, domain.TournamentSummer, domain.TournamentWorlds}
+
+// DevSeedConfig 开发数据生成配置，相同配置与基准时间生成相同的数据
+type DevSeedConfig struct {
+	Seed                  int64     // 随机数种子，默认 42
+	Users                 int       // 普通用户数，另外生成一个管理员 dev_admin，默认 50
+	Matches               int       // 比赛数，约一半已结束，其余进行中或未开始，默认 30
+	PredictionRate        float64   // 每个用户对每场已开放比赛提交预测的概率，默认 0.6
+	MaxVotesPerPrediction int       // 每条预测最多获得的投票数，默认 3
+	Password              string    // 所有生成用户的登录密码，默认 password123
+	BaseTime              time.Time // 比赛时间基准，默认当天零点（UTC）
+}
+
+// DevSeedReport 开发数据生成结果
+type DevSeedReport struct {
+	Skipped     bool          `json:"skipped"` // 已生成过，本次未写入
+	Users       int           `json:"users"`
+	Matches     int           `json:"matches"`
+	Predictions int           `json:"predictions"`
+	Votes       int           `json:"votes"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// DevSeeder 为本地开发生成可复现的用户、比赛、预测与投票
+//
+// 比赛以基准时间为中心每天一场：之前的比赛已结束（少数取消），基准当天的比赛进行中，
+// 之后的比赛未开始，其中部分已提前锁定预测。预测都在锁定时间之前提交，
+// 已结束比赛的预测按胜方结算积分，用户积分与预测的投票数和明细保持一致。
+type DevSeeder struct {
+	db     *gorm.DB
+	config DevSeedConfig
+	logger *logrus.Logger
+}
+
+// NewDevSeeder 创建开发数据生成器
+func NewDevSeeder(db *gorm.DB, config DevSeedConfig, logger *logrus.Logger) *DevSeeder {
+	if config.Seed == 0 {
+		config.Seed = 42
+	}
+	if config.Users <= 0 {
+		config.Users = 50
+	}
+	if config.Matches <= 0 {
+		config.Matches = 30
+	}
+	if config.PredictionRate <= 0 || config.PredictionRate > 1 {
+		config.PredictionRate = 0.6
+	}
+	if config.MaxVotesPerPrediction <= 0 {
+		config.MaxVotesPerPrediction = 3
+	}
+	if config.Password == "" {
+		config.Password = "password123"
+	}
+	if config.BaseTime.IsZero() {
+		config.BaseTime = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &DevSeeder{
+		db:     db,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Seed 生成开发数据，已生成过时直接返回 Skipped。全部数据在一个事务内写入
+func (s *DevSeeder) Seed(ctx context.Context) (*DevSeedReport, error) {
+	start := time.Now()
+	db := s.db.WithContext(ctx)
+
+	if err := db.AutoMigrate(&domain.SeedData{}); err != nil {
+		return nil, fmt.Errorf("failed to create seed data table: %w", err)
+	}
+	var marker domain.SeedData
+	err := db.Where("name = ?", DevSeedName).First(&marker).Error
+	if err == nil && marker.Applied {
+		s.logger.WithField("applied_at", marker.AppliedAt).Info("Dev dataset already seeded, skipping")
+		return &DevSeedReport{Skipped: true, Duration: time.Since(start)}, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check seed data: %w", err)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(s.config.Password), bcrypt.MinCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	report := &DevSeedReport{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		run := &devSeedRun{DevSeeder: s, tx: tx, rng: rand.New(rand.NewSource(s.config.Seed)), password: string(hashed), report: report}
+		if err := run.seed(); err != nil {
+			return err
+		}
+
+		marker = domain.SeedData{
+			Name:        DevSeedName,
+			Description: fmt.Sprintf("users=%d matches=%d", s.config.Users, s.config.Matches),
+			Version:     fmt.Sprintf("seed-%d", s.config.Seed),
+		}
+		marker.MarkAsApplied()
+		return tx.Create(&marker).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to seed dev dataset: %w", err)
+	}
+
+	report.Duration = time.Since(start)
+	s.logger.WithFields(logrus.Fields{
+		"users":       report.Users,
+		"matches":     report.Matches,
+		"predictions": report.Predictions,
+		"votes":       report.Votes,
+		"duration":    report.Duration,
+	}).Info("Dev dataset seeded")
+	return report, nil
+}
+
+// devSeedRun 一次生成过程中的状态
+type devSeedRun struct {
+	*DevSeeder
+	tx       *gorm.DB
+	rng      *rand.Rand
+	password string
+	report   *DevSeedReport
+
+	users []*user.User
+}
+
+func (r *devSeedRun) seed() error {
+	if err := r.seedUsers(); err != nil {
+		return err
+	}
+	matches, err := r.seedMatches()
+	if err != nil {
+		return err
+	}
+	predictions, err := r.seedPredictions(matches)
+	if err != nil {
+		return err
+	}
+	if err := r.seedVotes(predictions); err != nil {
+		return err
+	}
+
+	// 用户积分为已结算预测的积分之和
+	for _, u := range r.users {
+		if u.Points == 0 {
+			continue
+		}
+		if err := r.tx.Model(u).Update("points", u.Points).Error; err != nil {
+			return fmt.Errorf("failed to update user points: %w", err)
+		}
+	}
+	return nil
+}
+
+// seedUsers 生成管理员 dev_admin 与普通用户 dev_user_001 ...
+func (r *devSeedRun) seedUsers() error {
+	admin := &user.User{Username: "dev_admin", Email: "dev_admin@example.com", Nickname: "开发管理员", Password: r.password, Role: user.UserRoleAdmin}
+	r.users = append(r.users, admin)
+	for i := 1; i <= r.config.Users; i++ {
+		name := fmt.Sprintf("dev_user_%03d", i)
+		r.users = append(r.users, &user.User{
+			Username: name,
+			Email:    name + "@example.com",
+			Nickname: fmt.Sprintf("测试用户%d", i),
+			Password: r.password,
+			Role:     user.UserRoleUser,
+		})
+	}
+	if err := r.tx.CreateInBatches(r.users, 200).Error; err != nil {
+		return fmt.Errorf("failed to create users: %w", err)
+	}
+	r.report.Users = len(r.users)
+	return nil
+}
+
+// seedMatches 以基准时间为中心每天一场比赛，按开始时间决定状态
+func (r *devSeedRun) seedMatches() ([]*domain.Match, error) {
+	matches := make([]*domain.Match, 0, r.config.Matches)
+	first := r.config.BaseTime.AddDate(0, 0, -r.config.Matches/2)
+	for i := 0; i < r.config.Matches; i++ {
+		a := r.rng.Intn(len(devSeedTeams))
+		b := (a + 1 + r.rng.Intn(len(devSeedTeams)-1)) % len(devSeedTeams)
+		m := &domain.Match{
+			TeamA:      devSeedTeams[a],
+			TeamB:      devSeedTeams[b],
+			Tournament: devSeedTournaments[i%len(devSeedTournaments)],
+			StartTime:  first.AddDate(0, 0, i).Add(time.Duration(10+r.rng.Intn(10)) * time.Hour),
+		}
+
+		switch day := m.StartTime.Truncate(24 * time.Hour); {
+		case day.Before(r.config.BaseTime) && i%10 == 9:
+			m.Status = domain.MatchStatusCancelled
+		case day.Before(r.config.BaseTime):
+			m.Status = domain.MatchStatusFinished
+			r.finishMatch(m)
+		case day.Equal(r.config.BaseTime):
+			m.Status = domain.MatchStatusLive
+		default:
+			m.Status = domain.MatchStatusUpcoming
+			// 每 4 场未开始的比赛中有一场在开赛前一天锁定预测
+			if i%4 == 3 {
+				lockAt := m.StartTime.Add(-24 * time.Hour)
+				m.PredictionLockAt = &lockAt
+			}
+		}
+		matches = append(matches, m)
+	}
+	if err := r.tx.CreateInBatches(matches, 200).Error; err != nil {
+		return nil, fmt.Errorf("failed to create matches: %w", err)
+	}
+	r.report.Matches = len(matches)
+	return matches, nil
+}
+
+// finishMatch 生成比赛结果，世界赛为 BO5，其余为 BO3
+func (r *devSeedRun) finishMatch(m *domain.Match) {
+	wins := 2
+	if m.Tournament == domain.TournamentWorlds {
+		wins = 3
+	}
+	loserScore := r.rng.Intn(wins)
+	if r.rng.Intn(2) == 0 {
+		m.Winner, m.ScoreA, m.ScoreB = "A", wins, loserScore
+	} else {
+		m.Winner, m.ScoreA, m.ScoreB = "B", loserScore, wins
+	}
+}
+
+// seedPredictions 按概率为每个用户生成预测，提交时间在比赛锁定之前
+func (r *devSeedRun) seedPredictions(matches []*domain.Match) ([]*prediction.Prediction, error) {
+	var predictions []*prediction.Prediction
+	for _, m := range matches {
+		lockAt := m.StartTime
+		if m.PredictionLockAt != nil {
+			lockAt = *m.PredictionLockAt
+		}
+		// 提交窗口为锁定前三天内，且不晚于基准时间
+		windowEnd := lockAt
+		if windowEnd.After(r.config.BaseTime) {
+			windowEnd = r.config.BaseTime
+		}
+		windowStart := lockAt.Add(-72 * time.Hour)
+		if !windowEnd.After(windowStart) {
+			continue
+		}
+
+		for _, u := range r.users {
+			if r.rng.Float64() >= r.config.PredictionRate {
+				continue
+			}
+			p := r.newPrediction(u, m)
+			p.CreatedAt = windowStart.Add(time.Duration(r.rng.Int63n(int64(windowEnd.Sub(windowStart)))))
+			p.UpdatedAt = p.CreatedAt
+			if m.Status == domain.MatchStatusFinished {
+				p.IsCorrect = p.PredictedWinner == m.Winner
+				if p.IsCorrect {
+					p.EarnedPoints = devSeedCorrectPoints * p.Confidence
+					u.Points += p.EarnedPoints
+				}
+			}
+			predictions = append(predictions, p)
+		}
+	}
+	if len(predictions) > 0 {
+		if err := r.tx.CreateInBatches(predictions, 200).Error; err != nil {
+			return nil, fmt.Errorf("failed to create predictions: %w", err)
+		}
+	}
+	r.report.Predictions = len(predictions)
+	return predictions, nil
+}
+
+// newPrediction 生成与赛制一致的比分预测
+func (r *devSeedRun) newPrediction(u *user.User, m *domain.Match) *prediction.Prediction {
+	wins := 2
+	if m.Tournament == domain.TournamentWorlds {
+		wins = 3
+	}
+	p := &prediction.Prediction{
+		UserID:     u.ID,
+		MatchID:    m.ID,
+		Confidence: prediction.MinConfidence + r.rng.Intn(prediction.MaxConfidence-prediction.MinConfidence+1),
+	}
+	loserScore := r.rng.Intn(wins)
+	if r.rng.Intn(2) == 0 {
+		p.PredictedWinner, p.PredictedScoreA, p.PredictedScoreB = "A", wins, loserScore
+	} else {
+		p.PredictedWinner, p.PredictedScoreA, p.PredictedScoreB = "B", loserScore, wins
+	}
+	return p
+}
+
+// seedVotes 为预测随机选择其他用户投票，并同步预测的投票数
+func (r *devSeedRun) seedVotes(predictions []*prediction.Prediction) error {
+	var votes []*prediction.Vote
+	for _, p := range predictions {
+		count := r.rng.Intn(r.config.MaxVotesPerPrediction + 1)
+		for _, idx := range r.rng.Perm(len(r.users)) {
+			if count == 0 {
+				break
+			}
+			voter := r.users[idx]
+			if voter.ID == p.UserID {
+				continue
+			}
+			votes = append(votes, &prediction.Vote{UserID: voter.ID, PredictionID: p.ID, CreatedAt: p.CreatedAt, UpdatedAt: p.CreatedAt})
+			p.VoteCount++
+			count--
+		}
+	}
+	if len(votes) == 0 {
+		return nil
+	}
+	if err := r.tx.CreateInBatches(votes, 200).Error; err != nil {
+		return fmt.Errorf("failed to create votes: %w", err)
+	}
+	for _, p := range predictions {
+		if p.VoteCount == 0 {
+			continue
+		}
+		if err := r.tx.Model(p).UpdateColumn("vote_count", p.VoteCount).Error; err != nil {
+			return fmt.Errorf("failed to update vote count: %w", err)
+		}
+	}
+	r.report.Votes = len(votes)
+	return nil
+}
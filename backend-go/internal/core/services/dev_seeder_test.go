@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+func newDevSeedTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db := openLegacyTestDB(t, name)
+	if err := db.AutoMigrate(&user.User{}, &domain.Match{}, &prediction.Prediction{}, &prediction.Vote{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	return db
+}
+
+func newTestDevSeeder(db *gorm.DB) *DevSeeder {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewDevSeeder(db, DevSeedConfig{
+		Seed:     7,
+		Users:    12,
+		Matches:  16,
+		BaseTime: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+	}, logger)
+}
+
+func TestDevSeeder_GeneratesConsistentDataset(t *testing.T) {
+	ctx := context.Background()
+	db := newDevSeedTestDB(t, "dev.sqlite")
+
+	report, err := newTestDevSeeder(db).Seed(ctx)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if report.Skipped || report.Users != 13 || report.Matches != 16 || report.Predictions == 0 || report.Votes == 0 {
+		t.Fatalf("report = %+v, want 13 个用户、16 场比赛及若干预测与投票", report)
+	}
+
+	counts := map[string]int64{}
+	for table, model := range map[string]interface{}{"users": &user.User{}, "matches": &domain.Match{}, "predictions": &prediction.Prediction{}, "votes": &prediction.Vote{}} {
+		var n int64
+		db.Model(model).Count(&n)
+		counts[table] = n
+	}
+	if counts["users"] != 13 || counts["matches"] != 16 || counts["predictions"] != int64(report.Predictions) || counts["votes"] != int64(report.Votes) {
+		t.Errorf("counts = %v, report = %+v", counts, report)
+	}
+
+	// 不存在孤立的预测与投票
+	orphanChecks := map[string]string{
+		"预测用户":  `SELECT COUNT(*) FROM predictions p LEFT JOIN users u ON u.id = p.userId WHERE u.id IS NULL`,
+		"预测比赛":  `SELECT COUNT(*) FROM predictions p LEFT JOIN matches m ON m.id = p.matchId WHERE m.id IS NULL`,
+		"投票用户":  `SELECT COUNT(*) FROM votes v LEFT JOIN users u ON u.id = v.user_id WHERE u.id IS NULL`,
+		"投票预测":  `SELECT COUNT(*) FROM votes v LEFT JOIN predictions p ON p.id = v.prediction_id WHERE p.id IS NULL`,
+		"给自己投票": `SELECT COUNT(*) FROM votes v JOIN predictions p ON p.id = v.prediction_id WHERE v.user_id = p.userId`,
+		"投票数":   `SELECT COUNT(*) FROM predictions p WHERE p.vote_count <> (SELECT COUNT(*) FROM votes v WHERE v.prediction_id = p.id)`,
+	}
+	for name, query := range orphanChecks {
+		var n int64
+		if err := db.Raw(query).Scan(&n).Error; err != nil {
+			t.Fatalf("%s 检查失败: %v", name, err)
+		}
+		if n != 0 {
+			t.Errorf("%s 不一致的记录数 = %d", name, n)
+		}
+	}
+
+	var matches []domain.Match
+	db.Find(&matches)
+	statuses := map[domain.MatchStatus]int{}
+	byID := map[uint]domain.Match{}
+	for _, m := range matches {
+		statuses[m.Status]++
+		byID[m.ID] = m
+		if m.Status == domain.MatchStatusFinished && (m.Winner == "" || m.ScoreA == m.ScoreB) {
+			t.Errorf("已结束比赛 %d 缺少结果: %+v", m.ID, m)
+		}
+	}
+	for _, status := range []domain.MatchStatus{domain.MatchStatusFinished, domain.MatchStatusLive, domain.MatchStatusUpcoming} {
+		if statuses[status] == 0 {
+			t.Errorf("statuses = %v, want 包含 %s", statuses, status)
+		}
+	}
+
+	// 预测在锁定前提交，已结束比赛的预测按胜方结算，用户积分为结算积分之和
+	var predictions []prediction.Prediction
+	db.Find(&predictions)
+	points := map[uint]int{}
+	for _, p := range predictions {
+		m := byID[p.MatchID]
+		lockAt := m.StartTime
+		if m.PredictionLockAt != nil {
+			lockAt = *m.PredictionLockAt
+		}
+		if !p.CreatedAt.Before(lockAt) {
+			t.Errorf("预测 %d 提交于 %v，晚于锁定时间 %v", p.ID, p.CreatedAt, lockAt)
+		}
+		if m.Status == domain.MatchStatusCancelled {
+			t.Errorf("已取消比赛 %d 存在预测 %d", m.ID, p.ID)
+		}
+		if m.Status == domain.MatchStatusFinished && p.IsCorrect != (p.PredictedWinner == m.Winner) {
+			t.Errorf("预测 %d 结算结果 = %v, want %v", p.ID, p.IsCorrect, p.PredictedWinner == m.Winner)
+		}
+		points[p.UserID] += p.EarnedPoints
+	}
+	var users []user.User
+	db.Find(&users)
+	for _, u := range users {
+		if u.Points != points[u.ID] {
+			t.Errorf("用户 %s 积分 = %d, want %d", u.Username, u.Points, points[u.ID])
+		}
+	}
+}
+
+func TestDevSeeder_IdempotentAndDeterministic(t *testing.T) {
+	ctx := context.Background()
+	first := newDevSeedTestDB(t, "first.sqlite")
+	report, err := newTestDevSeeder(first).Seed(ctx)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+
+	// 再次运行跳过，数据不变
+	again, err := newTestDevSeeder(first).Seed(ctx)
+	if err != nil {
+		t.Fatalf("second Seed() error = %v", err)
+	}
+	if !again.Skipped {
+		t.Errorf("second report = %+v, want Skipped", again)
+	}
+	var users, predictions int64
+	first.Model(&user.User{}).Count(&users)
+	first.Model(&prediction.Prediction{}).Count(&predictions)
+	if users != int64(report.Users) || predictions != int64(report.Predictions) {
+		t.Errorf("重复运行后 users = %d, predictions = %d, want %d, %d", users, predictions, report.Users, report.Predictions)
+	}
+
+	// 相同种子在另一个库中生成相同的数据
+	second := newDevSeedTestDB(t, "second.sqlite")
+	other, err := newTestDevSeeder(second).Seed(ctx)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if other.Predictions != report.Predictions || other.Votes != report.Votes {
+		t.Fatalf("report = %+v, want 与首次生成一致 %+v", other, report)
+	}
+	var a, b []prediction.Prediction
+	first.Order("id").Find(&a)
+	second.Order("id").Find(&b)
+	for i := range a {
+		if a[i].UserID != b[i].UserID || a[i].MatchID != b[i].MatchID || a[i].PredictedWinner != b[i].PredictedWinner ||
+			a[i].Confidence != b[i].Confidence || a[i].VoteCount != b[i].VoteCount || !a[i].CreatedAt.Equal(b[i].CreatedAt) {
+			t.Fatalf("第 %d 条预测不同: %+v vs %+v", i, a[i], b[i])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// memoryFlagStore 内存中的灰度配置哈希
+type memoryFlagStore struct {
+	hashes map[string]map[string]string
+}
+
+func newMemoryFlagStore() *memoryFlagStore {
+	return &memoryFlagStore{hashes: map[string]map[string]string{}}
+}
+
+func (s *memoryFlagStore) HSet(ctx context.Context, key string, values ...interface{}) error {
+	if s.hashes[key] == nil {
+		s.hashes[key] = map[string]string{}
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		s.hashes[key][fmt.Sprint(values[i])] = fmt.Sprint(values[i+1])
+	}
+	return nil
+}
+
+func (s *memoryFlagStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.hashes[key], nil
+}
+
+func (s *memoryFlagStore) HDelete(ctx context.Context, key string, fields ...string) error {
+	for _, field := range fields {
+		delete(s.hashes[key], field)
+	}
+	return nil
+}
+
+func TestFeatureFlags_BucketingIsDeterministic(t *testing.T) {
+	store := newMemoryFlagStore()
+	flags := NewFeatureFlags(store, nil)
+	if err := flags.SetRollout(context.Background(), "new_scoring", FeatureRollout{Percentage: 50}); err != nil {
+		t.Fatalf("SetRollout() error = %v", err)
+	}
+
+	// 另一个实例从 Redis 加载同一配置，结果应与本实例一致
+	other := NewFeatureFlags(store, nil)
+	if err := other.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	for userID := uint(1); userID <= 200; userID++ {
+		first := flags.IsEnabledForUser("new_scoring", userID)
+		for i := 0; i < 3; i++ {
+			if flags.IsEnabledForUser("new_scoring", userID) != first {
+				t.Fatalf("用户 %d 的结果不稳定", userID)
+			}
+		}
+		if other.IsEnabledForUser("new_scoring", userID) != first {
+			t.Fatalf("用户 %d 在不同实例上的结果不一致", userID)
+		}
+	}
+}
+
+func TestFeatureFlags_EnabledFractionMatchesPercentage(t *testing.T) {
+	flags := NewFeatureFlags(newMemoryFlagStore(), nil)
+	ctx := context.Background()
+	const users = 20000
+
+	for _, percentage := range []int{0, 10, 25, 50, 90, 100} {
+		if err := flags.SetRollout(ctx, "new_scoring", FeatureRollout{Percentage: percentage}); err != nil {
+			t.Fatalf("SetRollout() error = %v", err)
+		}
+
+		enabled := 0
+		for userID := uint(1); userID <= users; userID++ {
+			if flags.IsEnabledForUser("new_scoring", userID) {
+				enabled++
+			}
+		}
+
+		got := float64(enabled) / users * 100
+		if diff := got - float64(percentage); diff < -2 || diff > 2 {
+			t.Errorf("percentage=%d: 开启比例 %.2f%%", percentage, got)
+		}
+	}
+}
+
+func TestFeatureFlags_AllowAndDenyOverrideBucketing(t *testing.T) {
+	flags := NewFeatureFlags(newMemoryFlagStore(), nil)
+	ctx := context.Background()
+
+	if err := flags.SetRollout(ctx, "closed", FeatureRollout{Percentage: 0, AllowUsers: []uint{7}}); err != nil {
+		t.Fatalf("SetRollout() error = %v", err)
+	}
+	if err := flags.SetRollout(ctx, "open", FeatureRollout{Percentage: 100, DenyUsers: []uint{7}}); err != nil {
+		t.Fatalf("SetRollout() error = %v", err)
+	}
+	if err := flags.SetRollout(ctx, "both", FeatureRollout{Percentage: 100, AllowUsers: []uint{7}, DenyUsers: []uint{7}}); err != nil {
+		t.Fatalf("SetRollout() error = %v", err)
+	}
+
+	if !flags.IsEnabledForUser("closed", 7) || flags.IsEnabledForUser("closed", 8) {
+		t.Error("allow 列表中的用户应开启，其他用户按 0% 关闭")
+	}
+	if flags.IsEnabledForUser("open", 7) || !flags.IsEnabledForUser("open", 8) {
+		t.Error("deny 列表中的用户应关闭，其他用户按 100% 开启")
+	}
+	if flags.IsEnabledForUser("both", 7) {
+		t.Error("deny 应优先于 allow")
+	}
+	if flags.IsEnabledForUser("unknown", 7) {
+		t.Error("未配置的开关应关闭")
+	}
+
+	if err := flags.DeleteRollout(ctx, "open"); err != nil {
+		t.Fatalf("DeleteRollout() error = %v", err)
+	}
+	if flags.IsEnabledForUser("open", 8) {
+		t.Error("删除后开关应关闭")
+	}
+}
+
+func TestFeatureFlags_RejectsInvalidPercentage(t *testing.T) {
+	flags := NewFeatureFlags(newMemoryFlagStore(), nil)
+	for _, percentage := range []int{-1, 101} {
+		if err := flags.SetRollout(context.Background(), "new_scoring", FeatureRollout{Percentage: percentage}); err == nil {
+			t.Errorf("SetRollout(percentage=%d) 应返回错误", percentage)
+		}
+	}
+}
@@ -67,7 +67,7 @@ func (s *HotPredictionsService) handleVoteEvent(event shared.Event) error {
 	ctx := context.Background()
 	pred, err := s.predictionRepo.GetPredictionByID(ctx, payload.PredictionID)
 	if err != nil {
-		logger.Error("Failed to get prediction %d for hot predictions update: %v", payload.PredictionID, err)
+		logger.Errorf("Failed to get prediction %d for hot predictions update: %v", payload.PredictionID, err)
 		return err
 	}
 
@@ -132,7 +132,7 @@ func (s *HotPredictionsService) updateHotPredictionsForMatch(matchID uint) {
 	// 刷新热门预测数据
 	hotPredictions, err := s.refreshHotPredictions(ctx, matchID, 0) // 获取所有预测
 	if err != nil {
-		logger.Error("Failed to refresh hot predictions for match %d: %v", matchID, err)
+		logger.Errorf("Failed to refresh hot predictions for match %d: %v", matchID, err)
 		return
 	}
 
@@ -145,10 +145,10 @@ func (s *HotPredictionsService) updateHotPredictionsForMatch(matchID uint) {
 	})
 
 	if err := s.eventBus.Publish(event); err != nil {
-		logger.Error("Failed to publish hot predictions update event: %v", err)
+		logger.Errorf("Failed to publish hot predictions update event: %v", err)
 	}
 
-	logger.Debug("Hot predictions updated for match %d", matchID)
+	logger.Debugf("Hot predictions updated for match %d", matchID)
 }
 
 // sortPredictionsByVotes 按投票数排序预测
@@ -276,7 +276,7 @@ func (s *HotPredictionsService) ClearCache(matchID uint) {
 	delete(s.hotPredictionsCache, matchID)
 	delete(s.cacheExpiry, matchID)
 
-	logger.Debug("Cleared hot predictions cache for match %d", matchID)
+	logger.Debugf("Cleared hot predictions cache for match %d", matchID)
 }
 
 // ClearAllCache 清除所有缓存
@@ -369,7 +369,7 @@ func (s *HotPredictionsService) cleanupExpiredCache() {
 	}
 
 	if len(expiredMatches) > 0 {
-		logger.Debug("Cleaned up %d expired cache entries", len(expiredMatches))
+		logger.Debugf("Cleaned up %d expired cache entries", len(expiredMatches))
 	}
 }
 
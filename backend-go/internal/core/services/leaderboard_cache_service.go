@@ -2,10 +2,13 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"backend-go/internal/core/domain/user"
 	"backend-go/internal/shared/logger"
 	"backend-go/pkg/redis"
@@ -52,6 +55,10 @@ type leaderboardCacheService struct {
 	// 缓存配置
 	cacheExpiration time.Duration
 	refreshInterval time.Duration
+	freshFor        time.Duration
+
+	// 后台刷新按赛事合并，同一赛事同时只有一次刷新
+	revalidateGroup singleflight.Group
 
 	// 统计信息
 	stats      CacheStats
@@ -67,6 +74,7 @@ type leaderboardCacheService struct {
 type LeaderboardCacheConfig struct {
 	CacheExpiration time.Duration `mapstructure:"cache_expiration"`
 	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	FreshFor        time.Duration `mapstructure:"fresh_for"` // 缓存超过该时长后读取时仍直接返回，并在后台刷新，0 表示不提前刷新
 }
 
 // NewLeaderboardCacheService 创建排行榜缓存服务
@@ -88,6 +96,7 @@ func NewLeaderboardCacheService(
 		cacheService:    cacheService,
 		cacheExpiration: config.CacheExpiration,
 		refreshInterval: config.RefreshInterval,
+		freshFor:        config.FreshFor,
 		stats: CacheStats{
 			LastUpdated: time.Now(),
 		},
@@ -103,11 +112,17 @@ func (s *leaderboardCacheService) GetLeaderboard(ctx context.Context, tournament
 
 	// 尝试从缓存获取
 	var entries []user.LeaderboardEntry
-	err := s.cacheService.GetJSON(ctx, cacheKey, &entries)
+	data, ttl, err := s.cacheService.GetWithTTL(ctx, cacheKey)
 	if err == nil {
-		s.incrementCacheHits()
-		logger.Debugf("Leaderboard cache hit for tournament: %s", tournament)
-		return entries, nil
+		if err = json.Unmarshal([]byte(data), &entries); err == nil {
+			s.incrementCacheHits()
+			logger.Debugf("Leaderboard cache hit for tournament: %s", tournament)
+			if s.isStale(ttl) {
+				s.revalidate(tournament)
+			}
+			return entries, nil
+		}
+		err = fmt.Errorf("failed to unmarshal leaderboard cache %s: %w", cacheKey, err)
 	}
 
 	// 缓存未命中，从数据库获取
@@ -188,6 +203,30 @@ func (s *leaderboardCacheService) RefreshCache(ctx context.Context, tournament s
 	return nil
 }
 
+// isStale 根据剩余过期时间推算缓存已存在的时长，超过 freshFor 视为需要刷新
+func (s *leaderboardCacheService) isStale(ttl time.Duration) bool {
+	if s.freshFor <= 0 || ttl < 0 {
+		return false
+	}
+	return s.cacheExpiration-ttl > s.freshFor
+}
+
+// revalidate 在后台刷新排行榜缓存，读取方不等待刷新结果
+//
+// DoChan 在调用方同步登记刷新，刷新进行中的其他读取会合并到同一次刷新
+func (s *leaderboardCacheService) revalidate(tournament string) {
+	s.revalidateGroup.DoChan(tournament, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := s.RefreshCache(ctx, tournament); err != nil {
+			logger.Warnf("Failed to revalidate leaderboard cache for tournament %s: %v", tournament, err)
+			return nil, err
+		}
+		return nil, nil
+	})
+}
+
 // GetCacheStats 获取缓存统计信息
 func (s *leaderboardCacheService) GetCacheStats() CacheStats {
 	s.statsMutex.RLock()
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/redis"
+)
+
+// ttlLeaderboardCache 返回固定剩余时间的排行榜缓存，刷新写入通过 refreshed 通知
+type ttlLeaderboardCache struct {
+	redis.CacheService
+	data      string
+	ttl       time.Duration
+	refreshed chan []user.LeaderboardEntry
+}
+
+func (c *ttlLeaderboardCache) GetWithTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	return c.data, c.ttl, nil
+}
+
+func (c *ttlLeaderboardCache) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.refreshed <- value.([]user.LeaderboardEntry)
+	return nil
+}
+
+// blockingLeaderboardRepo 统计排行榜查询次数，查询在 release 关闭前阻塞
+type blockingLeaderboardRepo struct {
+	user.Repository
+	calls   int32
+	release chan struct{}
+}
+
+func (r *blockingLeaderboardRepo) GetLeaderboard(ctx context.Context, tournament string, limit int) ([]user.LeaderboardEntry, error) {
+	atomic.AddInt32(&r.calls, 1)
+	<-r.release
+	return []user.LeaderboardEntry{{UserID: 1, Username: "fresh", Rank: 1, Tournament: tournament}}, nil
+}
+
+func newSWRLeaderboardCache(t *testing.T, ttl time.Duration) (LeaderboardCacheService, *ttlLeaderboardCache, *blockingLeaderboardRepo) {
+	t.Helper()
+	data, err := json.Marshal([]user.LeaderboardEntry{{UserID: 2, Username: "cached", Rank: 1, Tournament: "SPRING"}})
+	if err != nil {
+		t.Fatalf("序列化缓存失败: %v", err)
+	}
+	cache := &ttlLeaderboardCache{data: string(data), ttl: ttl, refreshed: make(chan []user.LeaderboardEntry, 10)}
+	repo := &blockingLeaderboardRepo{release: make(chan struct{})}
+	service := NewLeaderboardCacheService(repo, cache, LeaderboardCacheConfig{
+		CacheExpiration: 5 * time.Minute,
+		RefreshInterval: 2 * time.Minute,
+		FreshFor:        time.Minute,
+	})
+	return service, cache, repo
+}
+
+func TestLeaderboardCache_StaleEntryServedWhileRefreshing(t *testing.T) {
+	// 缓存已存在 3 分钟，超过 1 分钟的新鲜期
+	service, cache, repo := newSWRLeaderboardCache(t, 2*time.Minute)
+
+	// 刷新阻塞期间的多次读取都立即返回旧值
+	for i := 0; i < 5; i++ {
+		entries, err := service.GetLeaderboard(context.Background(), "SPRING")
+		if err != nil {
+			t.Fatalf("GetLeaderboard() error = %v", err)
+		}
+		if len(entries) != 1 || entries[0].Username != "cached" {
+			t.Fatalf("entries = %+v, want 缓存中的旧值", entries)
+		}
+	}
+
+	close(repo.release)
+	select {
+	case entries := <-cache.refreshed:
+		if len(entries) != 1 || entries[0].Username != "fresh" {
+			t.Errorf("刷新写入 = %+v, want 数据库中的新值", entries)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("后台刷新未写入缓存")
+	}
+	if calls := atomic.LoadInt32(&repo.calls); calls != 1 {
+		t.Errorf("数据库查询次数 = %d, want 1", calls)
+	}
+	select {
+	case <-cache.refreshed:
+		t.Error("触发了多次后台刷新")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestLeaderboardCache_FreshEntryNotRefreshed(t *testing.T) {
+	// 缓存只存在 30 秒
+	service, cache, repo := newSWRLeaderboardCache(t, 5*time.Minute-30*time.Second)
+	close(repo.release)
+
+	entries, err := service.GetLeaderboard(context.Background(), "SPRING")
+	if err != nil {
+		t.Fatalf("GetLeaderboard() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Username != "cached" {
+		t.Fatalf("entries = %+v, want 缓存中的值", entries)
+	}
+
+	select {
+	case <-cache.refreshed:
+		t.Error("新鲜缓存不应触发后台刷新")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if calls := atomic.LoadInt32(&repo.calls); calls != 0 {
+		t.Errorf("数据库查询次数 = %d, want 0", calls)
+	}
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+// legacyMatchTimeLayouts 旧版 matches.matchTime 文本的时间格式
+var legacyMatchTimeLayouts = []string{"2006-01-02 15:04:05.000", "2006-01-02 15:04:05", time.RFC3339}
+
+// legacyTournaments 旧版赛事类型到当前赛事的映射，未知类型按春季赛导入
+var legacyTournaments = map[string]domain.Tournament{
+	"spring": domain.TournamentSpring,
+	"summer": domain.TournamentSummer,
+	"annual": domain.TournamentWorlds,
+}
+
+// legacyMatchStatuses 旧版比赛状态到当前状态的映射
+var legacyMatchStatuses = map[string]domain.MatchStatus{
+	"not_started": domain.MatchStatusUpcoming,
+	"in_progress": domain.MatchStatusLive,
+	"completed":   domain.MatchStatusFinished,
+	"cancelled":   domain.MatchStatusCancelled,
+}
+
+// legacyMatch 旧版 SQLite matches 表的记录
+type legacyMatch struct {
+	ID             uint      `gorm:"column:id"`
+	OptionA        string    `gorm:"column:optionA"`
+	OptionB        string    `gorm:"column:optionB"`
+	MatchTime      string    `gorm:"column:matchTime"`
+	Status         string    `gorm:"column:status"`
+	Winner         *string   `gorm:"column:winner"`
+	ScoreA         int       `gorm:"column:scoreA"`
+	ScoreB         int       `gorm:"column:scoreB"`
+	TournamentType string    `gorm:"column:tournamentType"`
+	CreatedAt      time.Time `gorm:"column:createdAt"`
+	UpdatedAt      time.Time `gorm:"column:updatedAt"`
+}
+
+// LegacyImportConfig 旧版数据导入配置
+type LegacyImportConfig struct {
+	BatchSize   int  // 每批插入的行数，<=0 时使用 500
+	PreserveIDs bool // 保留原ID；目标表中已被占用的ID改由自增分配并重写子表外键
+	// RejectLatePredictions 跳过比赛开始后才创建的预测；默认只在报告中列出，仍然导入
+	RejectLatePredictions bool
+}
+
+// LegacyTableReport 单张表的导入结果
+type LegacyTableReport struct {
+	Table    string `json:"table"`
+	Missing  bool   `json:"missing,omitempty"` // 源库中不存在该表
+	Read     int    `json:"read"`
+	Imported int    `json:"imported"`
+	Remapped int    `json:"remapped"` // 重新分配ID的行数
+	Skipped  int    `json:"skipped"`
+}
+
+// LegacySkippedRow 导入时跳过的行
+type LegacySkippedRow struct {
+	Table  string `json:"table"`
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// LegacyTimingViolation 创建时间不早于比赛开始时间的预测，正常情况下无法在开赛后提交
+type LegacyTimingViolation struct {
+	PredictionID   uint          `json:"prediction_id"` // 旧版预测 ID
+	MatchID        uint          `json:"match_id"`      // 旧版比赛 ID
+	CreatedAt      time.Time     `json:"created_at"`
+	MatchStartTime time.Time     `json:"match_start_time"`
+	Late           time.Duration `json:"late"`     // 晚于开赛的时长
+	Rejected       bool          `json:"rejected"` // 是否因 RejectLatePredictions 未导入
+}
+
+// LegacyImportReport 旧版数据导入结果
+type LegacyImportReport struct {
+	Tables           []LegacyTableReport     `json:"tables"`
+	Skipped          []LegacySkippedRow      `json:"skipped,omitempty"`
+	TimingViolations []LegacyTimingViolation `json:"timing_violations,omitempty"`
+	Duration         time.Duration           `json:"duration"`
+}
+
+// LegacyImporter 将旧版 SQLite 数据库导入当前数据库
+//
+// 按外键依赖顺序导入 users → matches → predictions → votes → prediction_modifications，
+// 父记录缺失的行会被跳过并记录在报告中，整个导入在一个事务内完成。比赛开始后
+// 才创建的预测记录在报告的 TimingViolations 中，便于发现数据质量问题。
+type LegacyImporter struct {
+	source *gorm.DB
+	target *gorm.DB
+	config LegacyImportConfig
+	logger *logrus.Logger
+}
+
+// NewLegacyImporter 创建旧版数据导入器
+func NewLegacyImporter(source, target *gorm.DB, config LegacyImportConfig, logger *logrus.Logger) *LegacyImporter {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &LegacyImporter{
+		source: source,
+		target: target,
+		config: config,
+		logger: logger,
+	}
+}
+
+// legacyImportRun 一次导入过程中的状态
+type legacyImportRun struct {
+	*LegacyImporter
+	ctx    context.Context
+	tx     *gorm.DB
+	report *LegacyImportReport
+
+	// 各父表原ID到导入后ID的映射，只包含已导入的行
+	userIDs       map[uint]uint
+	matchIDs      map[uint]uint
+	predictionIDs map[uint]uint
+
+	// 旧版比赛ID到开始时间的映射，用于检查预测的创建时间
+	matchStartTimes map[uint]time.Time
+}
+
+// Import 执行导入
+func (i *LegacyImporter) Import(ctx context.Context) (*LegacyImportReport, error) {
+	start := time.Now()
+	report := &LegacyImportReport{}
+
+	err := i.target.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		run := &legacyImportRun{LegacyImporter: i, ctx: ctx, tx: tx, report: report}
+		steps := []func() error{
+			run.importUsers,
+			run.importMatches,
+			run.importPredictions,
+			run.importVotes,
+			run.importModifications,
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// readTable 读取源表的全部记录，源表不存在时返回 false
+func (r *legacyImportRun) readTable(table string, dest interface{}) (bool, error) {
+	if !r.source.Migrator().HasTable(table) {
+		r.logger.Warnf("Legacy table %s not found, skipping", table)
+		r.report.Tables = append(r.report.Tables, LegacyTableReport{Table: table, Missing: true})
+		return false, nil
+	}
+	if err := r.source.WithContext(r.ctx).Table(table).Order("id").Find(dest).Error; err != nil {
+		return false, fmt.Errorf("failed to read legacy %s: %w", table, err)
+	}
+	return true, nil
+}
+
+// skip 记录跳过的行
+func (r *legacyImportRun) skip(table string, id uint, reason string) {
+	r.report.Skipped = append(r.report.Skipped, LegacySkippedRow{Table: table, ID: id, Reason: reason})
+}
+
+// finishTable 记录单表导入结果
+func (r *legacyImportRun) finishTable(table string, read, imported, remapped int) {
+	r.report.Tables = append(r.report.Tables, LegacyTableReport{
+		Table:    table,
+		Read:     read,
+		Imported: imported,
+		Remapped: remapped,
+		Skipped:  read - imported,
+	})
+	r.logger.Infof("Imported %d/%d legacy %s (%d remapped)", imported, read, table, remapped)
+}
+
+func (r *legacyImportRun) importUsers() error {
+	var users []user.User
+	if ok, err := r.readTable("users", &users); !ok || err != nil {
+		return err
+	}
+
+	ids, remapped, err := insertRemapped(r, "users", users, func(u *user.User) *uint { return &u.ID })
+	if err != nil {
+		return err
+	}
+	r.userIDs = ids
+	r.finishTable("users", len(users), len(ids), remapped)
+	return nil
+}
+
+func (r *legacyImportRun) importMatches() error {
+	var legacy []legacyMatch
+	if ok, err := r.readTable("matches", &legacy); !ok || err != nil {
+		return err
+	}
+
+	matches := make([]domain.Match, 0, len(legacy))
+	r.matchStartTimes = make(map[uint]time.Time, len(legacy))
+	for _, m := range legacy {
+		startTime, err := parseLegacyTime(m.MatchTime)
+		if err != nil {
+			r.skip("matches", m.ID, fmt.Sprintf("invalid matchTime %q", m.MatchTime))
+			continue
+		}
+		tournament, ok := legacyTournaments[m.TournamentType]
+		if !ok {
+			tournament = domain.TournamentSpring
+		}
+		status, ok := legacyMatchStatuses[m.Status]
+		if !ok {
+			status = domain.MatchStatusUpcoming
+		}
+		match := domain.Match{
+			ID:         m.ID,
+			TeamA:      m.OptionA,
+			TeamB:      m.OptionB,
+			Tournament: tournament,
+			Status:     status,
+			StartTime:  startTime,
+			ScoreA:     m.ScoreA,
+			ScoreB:     m.ScoreB,
+			CreatedAt:  m.CreatedAt,
+			UpdatedAt:  m.UpdatedAt,
+		}
+		if m.Winner != nil {
+			match.Winner = *m.Winner
+		}
+		r.matchStartTimes[m.ID] = startTime
+		matches = append(matches, match)
+	}
+
+	ids, remapped, err := insertRemapped(r, "matches", matches, func(m *domain.Match) *uint { return &m.ID })
+	if err != nil {
+		return err
+	}
+	r.matchIDs = ids
+	r.finishTable("matches", len(legacy), len(ids), remapped)
+	return nil
+}
+
+func (r *legacyImportRun) importPredictions() error {
+	var legacy []domain.Prediction
+	if ok, err := r.readTable("predictions", &legacy); !ok || err != nil {
+		return err
+	}
+
+	predictions := make([]domain.Prediction, 0, len(legacy))
+	for _, p := range legacy {
+		userID, ok := r.userIDs[p.UserID]
+		if !ok {
+			r.skip("predictions", p.ID, fmt.Sprintf("user %d not found", p.UserID))
+			continue
+		}
+		matchID, ok := r.matchIDs[p.MatchID]
+		if !ok {
+			r.skip("predictions", p.ID, fmt.Sprintf("match %d not found", p.MatchID))
+			continue
+		}
+		if !r.checkPredictionTiming(p) {
+			r.skip("predictions", p.ID, fmt.Sprintf("created after match %d started", p.MatchID))
+			continue
+		}
+		p.UserID, p.MatchID = userID, matchID
+		predictions = append(predictions, p)
+	}
+
+	ids, remapped, err := insertRemapped(r, "predictions", predictions, func(p *domain.Prediction) *uint { return &p.ID })
+	if err != nil {
+		return err
+	}
+	r.predictionIDs = ids
+	r.finishTable("predictions", len(legacy), len(ids), remapped)
+	return nil
+}
+
+// checkPredictionTiming 记录比赛开始后才创建的预测，返回该预测是否继续导入
+func (r *legacyImportRun) checkPredictionTiming(p domain.Prediction) bool {
+	startTime, ok := r.matchStartTimes[p.MatchID]
+	if !ok || p.CreatedAt.Before(startTime) {
+		return true
+	}
+
+	r.report.TimingViolations = append(r.report.TimingViolations, LegacyTimingViolation{
+		PredictionID:   p.ID,
+		MatchID:        p.MatchID,
+		CreatedAt:      p.CreatedAt,
+		MatchStartTime: startTime,
+		Late:           p.CreatedAt.Sub(startTime),
+		Rejected:       r.config.RejectLatePredictions,
+	})
+	return !r.config.RejectLatePredictions
+}
+
+func (r *legacyImportRun) importVotes() error {
+	var legacy []prediction.Vote
+	if ok, err := r.readTable("votes", &legacy); !ok || err != nil {
+		return err
+	}
+
+	votes := make([]prediction.Vote, 0, len(legacy))
+	for _, v := range legacy {
+		userID, ok := r.userIDs[v.UserID]
+		if !ok {
+			r.skip("votes", v.ID, fmt.Sprintf("user %d not found", v.UserID))
+			continue
+		}
+		predictionID, ok := r.predictionIDs[v.PredictionID]
+		if !ok {
+			r.skip("votes", v.ID, fmt.Sprintf("prediction %d not found", v.PredictionID))
+			continue
+		}
+		v.UserID, v.PredictionID = userID, predictionID
+		votes = append(votes, v)
+	}
+
+	ids, remapped, err := insertRemapped(r, "votes", votes, func(v *prediction.Vote) *uint { return &v.ID })
+	if err != nil {
+		return err
+	}
+	r.finishTable("votes", len(legacy), len(ids), remapped)
+	return nil
+}
+
+func (r *legacyImportRun) importModifications() error {
+	var legacy []domain.PredictionModification
+	if ok, err := r.readTable("prediction_modifications", &legacy); !ok || err != nil {
+		return err
+	}
+
+	modifications := make([]domain.PredictionModification, 0, len(legacy))
+	for _, m := range legacy {
+		userID, ok := r.userIDs[m.UserID]
+		if !ok {
+			r.skip("prediction_modifications", m.ID, fmt.Sprintf("user %d not found", m.UserID))
+			continue
+		}
+		matchID, ok := r.matchIDs[m.MatchID]
+		if !ok {
+			r.skip("prediction_modifications", m.ID, fmt.Sprintf("match %d not found", m.MatchID))
+			continue
+		}
+		predictionID, ok := r.predictionIDs[m.PredictionID]
+		if !ok {
+			r.skip("prediction_modifications", m.ID, fmt.Sprintf("prediction %d not found", m.PredictionID))
+			continue
+		}
+		m.UserID, m.MatchID, m.PredictionID = userID, matchID, predictionID
+		modifications = append(modifications, m)
+	}
+
+	ids, remapped, err := insertRemapped(r, "prediction_modifications", modifications, func(m *domain.PredictionModification) *uint { return &m.ID })
+	if err != nil {
+		return err
+	}
+	r.finishTable("prediction_modifications", len(legacy), len(ids), remapped)
+	return nil
+}
+
+// insertRemapped 批量插入记录，返回原ID到导入后ID的映射与重新分配ID的行数
+//
+// 保留原ID时，目标表中已被占用的ID清零后由自增分配；两类记录分开插入，
+// 保证自增分配的ID能按顺序回填。
+func insertRemapped[T any](r *legacyImportRun, table string, rows []T, idOf func(*T) *uint) (map[uint]uint, int, error) {
+	ids := make(map[uint]uint, len(rows))
+	if len(rows) == 0 {
+		return ids, 0, nil
+	}
+
+	taken := make(map[uint]bool)
+	if r.config.PreserveIDs {
+		originalIDs := make([]uint, len(rows))
+		for idx := range rows {
+			originalIDs[idx] = *idOf(&rows[idx])
+		}
+		for start := 0; start < len(originalIDs); start += r.config.BatchSize {
+			end := min(start+r.config.BatchSize, len(originalIDs))
+			var existing []uint
+			if err := r.tx.Table(table).Where("id IN ?", originalIDs[start:end]).Pluck("id", &existing).Error; err != nil {
+				return nil, 0, fmt.Errorf("failed to check existing %s ids: %w", table, err)
+			}
+			for _, id := range existing {
+				taken[id] = true
+			}
+		}
+	}
+
+	var preserved, reassigned []T
+	var reassignedFrom []uint
+	for _, row := range rows {
+		id := idOf(&row)
+		if r.config.PreserveIDs && !taken[*id] {
+			preserved = append(preserved, row)
+			continue
+		}
+		reassignedFrom = append(reassignedFrom, *id)
+		*id = 0
+		reassigned = append(reassigned, row)
+	}
+
+	for _, batch := range [][]T{preserved, reassigned} {
+		if len(batch) == 0 {
+			continue
+		}
+		if err := r.tx.Omit(clause.Associations).CreateInBatches(batch, r.config.BatchSize).Error; err != nil {
+			return nil, 0, fmt.Errorf("failed to import %s: %w", table, err)
+		}
+	}
+
+	for idx := range preserved {
+		id := *idOf(&preserved[idx])
+		ids[id] = id
+	}
+	for idx := range reassigned {
+		ids[reassignedFrom[idx]] = *idOf(&reassigned[idx])
+	}
+	return ids, len(reassigned), nil
+}
+
+// parseLegacyTime 解析旧版不带时区的时间文本，按服务器本地时区处理
+func parseLegacyTime(value string) (time.Time, error) {
+	for _, layout := range legacyMatchTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+// legacyFixture 旧版 SQLite 表结构与数据：预测 3 的用户 99 不存在，投票 2 指向该孤儿预测
+const legacyFixture = `
+CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, username varchar NOT NULL, nickname varchar, password varchar NOT NULL, email varchar NOT NULL, avatar varchar, points integer NOT NULL DEFAULT 0, role varchar NOT NULL DEFAULT 'user', createdAt datetime NOT NULL DEFAULT (datetime('now')), updatedAt datetime NOT NULL DEFAULT (datetime('now')), lastPasswordChange datetime);
+CREATE TABLE matches (id integer PRIMARY KEY AUTOINCREMENT, title varchar NOT NULL, optionA varchar NOT NULL, optionB varchar NOT NULL, matchTime text NOT NULL, status varchar NOT NULL DEFAULT 'not_started', winner varchar, scoreA integer NOT NULL DEFAULT 0, scoreB integer NOT NULL DEFAULT 0, tournamentType varchar NOT NULL DEFAULT 'summer', createdAt datetime NOT NULL DEFAULT (datetime('now')), updatedAt datetime NOT NULL DEFAULT (datetime('now')));
+CREATE TABLE predictions (id integer PRIMARY KEY AUTOINCREMENT, userId integer NOT NULL, matchId integer NOT NULL, predictedWinner varchar NOT NULL, predictedScoreA integer NOT NULL, predictedScoreB integer NOT NULL, isVerified boolean NOT NULL DEFAULT 0, isCorrect boolean NOT NULL DEFAULT 0, earnedPoints integer NOT NULL DEFAULT 0, isProcessed boolean NOT NULL DEFAULT 0, createdAt datetime NOT NULL DEFAULT (datetime('now')), updatedAt datetime NOT NULL DEFAULT (datetime('now')));
+CREATE TABLE votes (id integer PRIMARY KEY AUTOINCREMENT, user_id integer NOT NULL, prediction_id integer NOT NULL, created_at datetime NOT NULL DEFAULT (datetime('now')), updated_at datetime NOT NULL DEFAULT (datetime('now')));
+INSERT INTO users (id, username, password, email, points, role) VALUES (1, 'root', 'x', 'root@example.com', 10, 'admin'), (2, 'alice', 'x', 'alice@example.com', 5, 'user');
+INSERT INTO matches (id, title, optionA, optionB, matchTime, status, winner, scoreA, scoreB, tournamentType) VALUES (1, 'JDG vs DYG', 'JDG', 'DYG', '2025-04-19 09:02:00.000', 'completed', 'A', 3, 1, 'spring');
+INSERT INTO predictions (id, userId, matchId, predictedWinner, predictedScoreA, predictedScoreB) VALUES (1, 1, 1, 'A', 3, 1), (2, 2, 1, 'B', 1, 3), (3, 99, 1, 'A', 3, 0);
+INSERT INTO votes (id, user_id, prediction_id) VALUES (1, 2, 1), (2, 1, 3);
+`
+
+// openLegacyTestDB 在临时目录中打开 SQLite 数据库
+func openLegacyTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name)), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	return db
+}
+
+func TestLegacyImporter_SkipsOrphansAndRemapsForeignKeys(t *testing.T) {
+	ctx := context.Background()
+
+	source := openLegacyTestDB(t, "legacy.sqlite")
+	if err := source.Exec(legacyFixture).Error; err != nil {
+		t.Fatalf("写入旧版数据失败: %v", err)
+	}
+
+	target := openLegacyTestDB(t, "target.sqlite")
+	if err := target.AutoMigrate(&user.User{}, &domain.Match{}, &domain.Prediction{}, &prediction.Vote{}, &domain.PredictionModification{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	// 目标库已有 ID 为 1 的用户，旧版用户 1 需要重新分配 ID
+	if err := target.Create(&user.User{ID: 1, Username: "existing", Email: "existing@example.com", Password: "x"}).Error; err != nil {
+		t.Fatalf("创建已有用户失败: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	importer := NewLegacyImporter(source, target, LegacyImportConfig{BatchSize: 2, PreserveIDs: true}, log)
+	report, err := importer.Import(ctx)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	tables := make(map[string]LegacyTableReport)
+	for _, table := range report.Tables {
+		tables[table.Table] = table
+	}
+	if users := tables["users"]; users.Imported != 2 || users.Remapped != 1 {
+		t.Errorf("users report = %+v, want 2 imported, 1 remapped", users)
+	}
+	if predictions := tables["predictions"]; predictions.Read != 3 || predictions.Imported != 2 || predictions.Skipped != 1 {
+		t.Errorf("predictions report = %+v, want 3 read, 2 imported, 1 skipped", predictions)
+	}
+	if votes := tables["votes"]; votes.Imported != 1 || votes.Skipped != 1 {
+		t.Errorf("votes report = %+v, want 1 imported, 1 skipped", votes)
+	}
+	if modifications := tables["prediction_modifications"]; !modifications.Missing {
+		t.Errorf("源库缺少的表应标记为 missing: %+v", modifications)
+	}
+
+	skipped := make(map[string]LegacySkippedRow)
+	for _, row := range report.Skipped {
+		skipped[row.Table] = row
+	}
+	if row := skipped["predictions"]; row.ID != 3 || row.Reason != "user 99 not found" {
+		t.Errorf("skipped prediction = %+v, want id 3 with missing user 99", row)
+	}
+	if row := skipped["votes"]; row.ID != 2 || row.Reason != "prediction 3 not found" {
+		t.Errorf("skipped vote = %+v, want id 2 with missing prediction 3", row)
+	}
+
+	// 旧版 root 用户被重新分配 ID，其预测与投票的外键同步改写
+	var root user.User
+	if err := target.Where("username = ?", "root").First(&root).Error; err != nil {
+		t.Fatalf("查询导入的用户失败: %v", err)
+	}
+	if root.ID == 1 {
+		t.Fatal("冲突的用户 ID 应被重新分配")
+	}
+	var rootPrediction domain.Prediction
+	if err := target.First(&rootPrediction, 1).Error; err != nil {
+		t.Fatalf("查询导入的预测失败: %v", err)
+	}
+	if rootPrediction.UserID != root.ID {
+		t.Errorf("prediction 1 userId = %d, want remapped %d", rootPrediction.UserID, root.ID)
+	}
+	var alicePrediction domain.Prediction
+	if err := target.First(&alicePrediction, 2).Error; err != nil || alicePrediction.UserID != 2 {
+		t.Errorf("未冲突的用户应保留原 ID: prediction 2 = %+v, err = %v", alicePrediction, err)
+	}
+
+	var match domain.Match
+	if err := target.First(&match, 1).Error; err != nil {
+		t.Fatalf("查询导入的比赛失败: %v", err)
+	}
+	if match.TeamA != "JDG" || match.Status != domain.MatchStatusFinished || match.Tournament != domain.TournamentSpring || match.Winner != "A" {
+		t.Errorf("match = %+v, want mapped legacy fields", match)
+	}
+
+	var votes []prediction.Vote
+	if err := target.Find(&votes).Error; err != nil {
+		t.Fatalf("查询导入的投票失败: %v", err)
+	}
+	if len(votes) != 1 || votes[0].UserID != 2 || votes[0].PredictionID != 1 {
+		t.Errorf("votes = %+v, want one vote by user 2 on prediction 1", votes)
+	}
+}
+
+// legacyTimingFixture 预测 1 在开赛前一天创建，预测 2 在开赛后一天创建
+const legacyTimingFixture = `
+CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, username varchar NOT NULL, nickname varchar, password varchar NOT NULL, email varchar NOT NULL, avatar varchar, points integer NOT NULL DEFAULT 0, role varchar NOT NULL DEFAULT 'user', createdAt datetime NOT NULL DEFAULT (datetime('now')), updatedAt datetime NOT NULL DEFAULT (datetime('now')), lastPasswordChange datetime);
+CREATE TABLE matches (id integer PRIMARY KEY AUTOINCREMENT, title varchar NOT NULL, optionA varchar NOT NULL, optionB varchar NOT NULL, matchTime text NOT NULL, status varchar NOT NULL DEFAULT 'not_started', winner varchar, scoreA integer NOT NULL DEFAULT 0, scoreB integer NOT NULL DEFAULT 0, tournamentType varchar NOT NULL DEFAULT 'summer', createdAt datetime NOT NULL DEFAULT (datetime('now')), updatedAt datetime NOT NULL DEFAULT (datetime('now')));
+CREATE TABLE predictions (id integer PRIMARY KEY AUTOINCREMENT, userId integer NOT NULL, matchId integer NOT NULL, predictedWinner varchar NOT NULL, predictedScoreA integer NOT NULL, predictedScoreB integer NOT NULL, isVerified boolean NOT NULL DEFAULT 0, isCorrect boolean NOT NULL DEFAULT 0, earnedPoints integer NOT NULL DEFAULT 0, isProcessed boolean NOT NULL DEFAULT 0, createdAt datetime NOT NULL DEFAULT (datetime('now')), updatedAt datetime NOT NULL DEFAULT (datetime('now')));
+INSERT INTO users (id, username, password, email) VALUES (1, 'alice', 'x', 'alice@example.com'), (2, 'bob', 'x', 'bob@example.com');
+INSERT INTO matches (id, title, optionA, optionB, matchTime, status, winner, scoreA, scoreB, tournamentType) VALUES (1, 'JDG vs DYG', 'JDG', 'DYG', '2025-04-19 09:02:00.000', 'completed', 'A', 3, 1, 'spring');
+INSERT INTO predictions (id, userId, matchId, predictedWinner, predictedScoreA, predictedScoreB, createdAt, updatedAt) VALUES (1, 1, 1, 'A', 3, 1, '2025-04-18 09:00:00', '2025-04-18 09:00:00'), (2, 2, 1, 'A', 3, 1, '2025-04-20 09:00:00', '2025-04-20 09:00:00');
+`
+
+// importLegacyTimingFixture 导入开赛前后各一条预测的旧版数据
+func importLegacyTimingFixture(t *testing.T, config LegacyImportConfig) (*LegacyImportReport, *gorm.DB) {
+	t.Helper()
+	source := openLegacyTestDB(t, "legacy.sqlite")
+	if err := source.Exec(legacyTimingFixture).Error; err != nil {
+		t.Fatalf("写入旧版数据失败: %v", err)
+	}
+	target := openLegacyTestDB(t, "target.sqlite")
+	if err := target.AutoMigrate(&user.User{}, &domain.Match{}, &domain.Prediction{}, &prediction.Vote{}, &domain.PredictionModification{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	report, err := NewLegacyImporter(source, target, config, log).Import(context.Background())
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	return report, target
+}
+
+func TestLegacyImporter_ReportsPredictionsCreatedAfterMatchStart(t *testing.T) {
+	report, target := importLegacyTimingFixture(t, LegacyImportConfig{PreserveIDs: true})
+
+	if len(report.TimingViolations) != 1 {
+		t.Fatalf("timing violations = %+v, want 1", report.TimingViolations)
+	}
+	violation := report.TimingViolations[0]
+	if violation.PredictionID != 2 || violation.MatchID != 1 || violation.Rejected {
+		t.Errorf("violation = %+v, want prediction 2 on match 1, not rejected", violation)
+	}
+	if violation.Late <= 0 || !violation.CreatedAt.After(violation.MatchStartTime) {
+		t.Errorf("violation late = %v, created %v, start %v", violation.Late, violation.CreatedAt, violation.MatchStartTime)
+	}
+
+	// 默认只报告，两条预测都导入
+	var count int64
+	if err := target.Model(&domain.Prediction{}).Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("imported predictions = %d, err = %v, want 2", count, err)
+	}
+	if len(report.Skipped) != 0 {
+		t.Errorf("skipped = %+v, want none", report.Skipped)
+	}
+}
+
+func TestLegacyImporter_RejectsLatePredictionsWhenConfigured(t *testing.T) {
+	report, target := importLegacyTimingFixture(t, LegacyImportConfig{PreserveIDs: true, RejectLatePredictions: true})
+
+	if len(report.TimingViolations) != 1 || !report.TimingViolations[0].Rejected {
+		t.Fatalf("timing violations = %+v, want 1 rejected", report.TimingViolations)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Table != "predictions" || report.Skipped[0].ID != 2 {
+		t.Errorf("skipped = %+v, want prediction 2", report.Skipped)
+	}
+
+	var predictions []domain.Prediction
+	if err := target.Find(&predictions).Error; err != nil {
+		t.Fatalf("查询导入的预测失败: %v", err)
+	}
+	if len(predictions) != 1 || predictions[0].ID != 1 {
+		t.Errorf("predictions = %+v, want only prediction 1", predictions)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/scoring"
+)
+
+// MatchFinishSummary 结束比赛并计分的结果
+type MatchFinishSummary struct {
+	MatchID              uint   `json:"matchId"`
+	ScoreA               int    `json:"scoreA"`
+	ScoreB               int    `json:"scoreB"`
+	Winner               string `json:"winner"`
+	AlreadyFinished      bool   `json:"alreadyFinished"`      // 比赛此前已按相同结果结束，本次未修改比赛
+	PredictionsScored    int    `json:"predictionsScored"`    // 参与计分的预测数
+	TotalPoints          int    `json:"totalPoints"`          // 本场比赛发放的总积分
+	LeaderboardRefreshed bool   `json:"leaderboardRefreshed"` // 刷新排行榜失败不影响结果，由下次刷新补齐
+}
+
+// badgeRefresher 计分后重新计算用户徽章，BadgeService 满足该接口
+type badgeRefresher interface {
+	RefreshUsers(ctx context.Context, userIDs []uint)
+}
+
+// MatchFinishService 管理员结束比赛的工作流：校验结果、结束比赛、为全部预测计分并刷新排行榜与徽章
+//
+// 同一结果重复提交是幂等的：比赛不会被再次修改，计分服务按比赛去重，不会重复发放积分，
+// 因此计分或刷新排行榜失败后可以直接重试。
+type MatchFinishService struct {
+	matches     match.Service
+	scoring     scoring.Service
+	leaderboard leaderboardRefresher
+	badges      badgeRefresher
+	logger      *logrus.Logger
+
+	// 串行执行，避免并发结束同一场比赛时重复计分
+	mu sync.Mutex
+}
+
+// NewMatchFinishService 创建结束比赛工作流服务
+func NewMatchFinishService(matches match.Service, scoringService scoring.Service, leaderboard leaderboardRefresher, logger *logrus.Logger) *MatchFinishService {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &MatchFinishService{
+		matches:     matches,
+		scoring:     scoringService,
+		leaderboard: leaderboard,
+		logger:      logger,
+	}
+}
+
+// SetBadgeRefresher 设置计分后刷新徽章的服务，未设置时不刷新
+func (s *MatchFinishService) SetBadgeRefresher(badges badgeRefresher) {
+	s.badges = badges
+}
+
+// Finish 按结果结束比赛并计分
+//
+// 已取消的比赛返回 domain.ErrInvalidMatchStatus；已按其他结果结束的比赛返回
+// domain.ErrMatchAlreadyFinished，避免按新结果重复发放积分。
+func (s *MatchFinishService) Finish(ctx context.Context, id uint, req *match.SetResultRequest) (*MatchFinishSummary, error) {
+	result, err := normalizeMatchResult(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.matches.GetMatch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &MatchFinishSummary{MatchID: id, ScoreA: result.ScoreA, ScoreB: result.ScoreB, Winner: result.Winner}
+	switch m.Status {
+	case match.MatchStatusCancelled:
+		return nil, domain.ErrInvalidMatchStatus
+	case match.MatchStatusFinished:
+		if m.ScoreA != result.ScoreA || m.ScoreB != result.ScoreB || m.Winner != result.Winner {
+			return nil, domain.ErrMatchAlreadyFinished
+		}
+		summary.AlreadyFinished = true
+	default:
+		if err := s.matches.SetResult(ctx, id, result); err != nil {
+			return nil, err
+		}
+	}
+
+	calculation, err := s.scoring.CalculateMatchPoints(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("score match %d: %w", id, err)
+	}
+	summary.PredictionsScored = len(calculation.Results)
+	summary.TotalPoints = calculation.TotalPoints
+
+	if err := s.leaderboard.RefreshLeaderboard(ctx, string(m.Tournament)); err != nil {
+		s.logger.WithError(err).WithField("match_id", id).Warn("Failed to refresh leaderboard after finishing match")
+	} else {
+		summary.LeaderboardRefreshed = true
+	}
+
+	if s.badges != nil {
+		userIDs := make([]uint, len(calculation.Results))
+		for i, result := range calculation.Results {
+			userIDs[i] = result.UserID
+		}
+		s.badges.RefreshUsers(ctx, userIDs)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"match_id":           id,
+		"already_finished":   summary.AlreadyFinished,
+		"predictions_scored": summary.PredictionsScored,
+		"total_points":       summary.TotalPoints,
+	}).Info("Match finished and scored")
+
+	return summary, nil
+}
+
+// normalizeMatchResult 校验比分与获胜方，未指定获胜方时按比分推断，平局不设获胜方
+func normalizeMatchResult(req *match.SetResultRequest) (*match.SetResultRequest, error) {
+	if req.ScoreA < 0 || req.ScoreB < 0 {
+		return nil, domain.ErrInvalidScore
+	}
+
+	leader := ""
+	if req.ScoreA > req.ScoreB {
+		leader = "A"
+	} else if req.ScoreB > req.ScoreA {
+		leader = "B"
+	}
+
+	result := *req
+	switch {
+	case result.Winner == "":
+		result.Winner = leader
+	case result.Winner != "A" && result.Winner != "B":
+		return nil, domain.ErrInvalidWinner
+	case leader != "" && result.Winner != leader:
+		return nil, domain.ErrInvalidWinner
+	}
+	return &result, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/scoring"
+)
+
+// finishMatchService 内存中的比赛，记录设置结果的次数
+type finishMatchService struct {
+	match.Service
+	matches    map[uint]*match.Match
+	setResults int
+}
+
+func (s *finishMatchService) GetMatch(ctx context.Context, id uint) (*match.Match, error) {
+	m, ok := s.matches[id]
+	if !ok {
+		return nil, domain.ErrMatchNotFound
+	}
+	copied := *m
+	return &copied, nil
+}
+
+func (s *finishMatchService) SetResult(ctx context.Context, id uint, req *match.SetResultRequest) error {
+	s.setResults++
+	m := s.matches[id]
+	return m.SetResult(req.ScoreA, req.ScoreB, req.Winner)
+}
+
+// finishScoringService 与真实实现一样按比赛去重，已计分的比赛返回已有结果
+type finishScoringService struct {
+	scoring.Service
+	predictions  map[uint][]scoring.PointsCalculationResult
+	calculations map[uint]*scoring.MatchPointsCalculation
+	awarded      int
+}
+
+func (s *finishScoringService) CalculateMatchPoints(ctx context.Context, matchID uint) (*scoring.MatchPointsCalculation, error) {
+	if calculation, ok := s.calculations[matchID]; ok {
+		return calculation, nil
+	}
+	calculation := &scoring.MatchPointsCalculation{MatchID: matchID, Results: s.predictions[matchID]}
+	for _, result := range calculation.Results {
+		calculation.TotalPoints += result.Points
+	}
+	s.awarded += calculation.TotalPoints
+	s.calculations[matchID] = calculation
+	return calculation, nil
+}
+
+func newTestMatchFinishService() (*MatchFinishService, *finishMatchService, *finishScoringService, *recordingRefresher) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	matches := &finishMatchService{matches: map[uint]*match.Match{
+		7: {ID: 7, Status: match.MatchStatusLive, Tournament: domain.TournamentSummer},
+		8: {ID: 8, Status: match.MatchStatusCancelled},
+	}}
+	scoringService := &finishScoringService{
+		predictions: map[uint][]scoring.PointsCalculationResult{
+			7: {{PredictionID: 1, UserID: 10, Points: 3}, {PredictionID: 2, UserID: 11, Points: 0}, {PredictionID: 3, UserID: 12, Points: 3}},
+		},
+		calculations: map[uint]*scoring.MatchPointsCalculation{},
+	}
+	refresher := &recordingRefresher{}
+	return NewMatchFinishService(matches, scoringService, refresher, logger), matches, scoringService, refresher
+}
+
+func TestMatchFinishService_FinishesAndScores(t *testing.T) {
+	service, matches, scoringService, refresher := newTestMatchFinishService()
+
+	// 未指定获胜方时按比分推断
+	summary, err := service.Finish(context.Background(), 7, &match.SetResultRequest{ScoreA: 2, ScoreB: 1})
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if summary.AlreadyFinished || summary.Winner != "A" || summary.PredictionsScored != 3 || summary.TotalPoints != 6 || !summary.LeaderboardRefreshed {
+		t.Errorf("summary = %+v, want 新结束的比赛，3 个预测共 6 分", summary)
+	}
+	if got := matches.matches[7]; got.Status != match.MatchStatusFinished || got.Winner != "A" {
+		t.Errorf("match = %+v, want 已结束且 A 获胜", got)
+	}
+	if len(refresher.calls) != 1 || refresher.calls[0] != "SUMMER" {
+		t.Errorf("refreshed = %v, want [SUMMER]", refresher.calls)
+	}
+
+	// 相同结果重复提交不修改比赛，也不重复发放积分
+	summary, err = service.Finish(context.Background(), 7, &match.SetResultRequest{ScoreA: 2, ScoreB: 1, Winner: "A"})
+	if err != nil {
+		t.Fatalf("重复 Finish() error = %v", err)
+	}
+	if !summary.AlreadyFinished || summary.PredictionsScored != 3 {
+		t.Errorf("重复提交 summary = %+v, want AlreadyFinished", summary)
+	}
+	if matches.setResults != 1 || scoringService.awarded != 6 {
+		t.Errorf("setResults = %d, awarded = %d, want 1/6", matches.setResults, scoringService.awarded)
+	}
+
+	// 以其他结果重新结束被拒绝
+	if _, err := service.Finish(context.Background(), 7, &match.SetResultRequest{ScoreA: 1, ScoreB: 2}); !errors.Is(err, domain.ErrMatchAlreadyFinished) {
+		t.Errorf("修改结果 error = %v, want ErrMatchAlreadyFinished", err)
+	}
+}
+
+func TestMatchFinishService_RejectsInvalidResults(t *testing.T) {
+	service, matches, _, _ := newTestMatchFinishService()
+
+	cases := []struct {
+		name string
+		id   uint
+		req  match.SetResultRequest
+		want error
+	}{
+		{"负分", 7, match.SetResultRequest{ScoreA: -1, ScoreB: 0}, domain.ErrInvalidScore},
+		{"无效获胜方", 7, match.SetResultRequest{ScoreA: 1, ScoreB: 0, Winner: "C"}, domain.ErrInvalidWinner},
+		{"获胜方与比分矛盾", 7, match.SetResultRequest{ScoreA: 0, ScoreB: 3, Winner: "A"}, domain.ErrInvalidWinner},
+		{"已取消", 8, match.SetResultRequest{ScoreA: 1, ScoreB: 0}, domain.ErrInvalidMatchStatus},
+		{"不存在", 99, match.SetResultRequest{ScoreA: 1, ScoreB: 0}, domain.ErrMatchNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := service.Finish(context.Background(), tc.id, &tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if matches.setResults != 0 {
+		t.Errorf("setResults = %d, want 0", matches.setResults)
+	}
+}
+
+func TestMatchFinishService_RefreshesBadgesOfScoredUsers(t *testing.T) {
+	service, _, _, _ := newTestMatchFinishService()
+	predictions := &memoryBadgePredictions{byUser: map[uint][]prediction.Prediction{
+		10: scoredPredictions("11", 10),
+	}}
+	badges := newTestBadgeService(predictions)
+	service.SetBadgeRefresher(badges)
+
+	// 计分前读取并缓存了空的徽章
+	if got, _ := badges.ComputeBadges(context.Background(), 10); len(got) != 0 {
+		t.Fatalf("badges = %v, want 尚未获得徽章", badgeIDs(got))
+	}
+
+	predictions.byUser[10] = scoredPredictions("111", 10)
+	if _, err := service.Finish(context.Background(), 7, &match.SetResultRequest{ScoreA: 2, ScoreB: 1}); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	got, err := badges.ComputeBadges(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ComputeBadges() error = %v", err)
+	}
+	if ids := badgeIDs(got); len(ids) != 2 || ids[0] != "streak_3" || ids[1] != "points_30" {
+		t.Errorf("badges = %v, want 结束比赛后刷新为 [streak_3 points_30]", ids)
+	}
+	// 3 个预测涉及 3 个用户，各重新计算一次，加上计分前的一次读取
+	if predictions.calls != 4 {
+		t.Errorf("calls = %d, want 4", predictions.calls)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+)
+
+// matchReminderKeyPrefix 提醒去重锁键前缀
+const matchReminderKeyPrefix = "match_reminder:"
+
+// reminderLock 提醒去重所需的分布式锁，多实例部署时同一提醒只发送一次
+type reminderLock interface {
+	Lock(ctx context.Context, key string, expiration time.Duration) (bool, error)
+}
+
+// MatchReminderConfig 比赛提醒配置
+type MatchReminderConfig struct {
+	Interval   time.Duration // 扫描间隔
+	MaxMatches int           // 每次扫描的即将开始比赛上限
+}
+
+// MatchReminder 比赛提醒推送内容
+type MatchReminder struct {
+	MatchID           uint      `json:"match_id"`
+	TeamA             string    `json:"team_a"`
+	TeamB             string    `json:"team_b"`
+	StartTime         time.Time `json:"start_time"`
+	MinutesUntilStart int       `json:"minutes_until_start"`
+}
+
+// MatchReminderService 比赛开始前提醒已预测的用户
+//
+// 提醒时间由用户的提前量决定，邮件与实时推送分别受通知偏好控制。
+type MatchReminderService struct {
+	matchRepo      match.Repository
+	predictionRepo prediction.Repository
+	userRepo       user.Repository
+	emailSender    ports.EmailSender
+	pusher         ports.NotificationPusher
+	lock           reminderLock
+	config         MatchReminderConfig
+	logger         *logrus.Logger
+	now            func() time.Time
+}
+
+// NewMatchReminderService 创建比赛提醒服务，emailSender 与 pusher 可为空
+func NewMatchReminderService(
+	matchRepo match.Repository,
+	predictionRepo prediction.Repository,
+	userRepo user.Repository,
+	emailSender ports.EmailSender,
+	pusher ports.NotificationPusher,
+	lock reminderLock,
+	config MatchReminderConfig,
+	logger *logrus.Logger,
+) *MatchReminderService {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.MaxMatches <= 0 {
+		config.MaxMatches = 200
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &MatchReminderService{
+		matchRepo:      matchRepo,
+		predictionRepo: predictionRepo,
+		userRepo:       userRepo,
+		emailSender:    emailSender,
+		pusher:         pusher,
+		lock:           lock,
+		config:         config,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// Run 按配置间隔扫描并发送提醒，直到 ctx 取消
+func (s *MatchReminderService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SendDueReminders(ctx); err != nil {
+				s.logger.WithError(err).Warn("Failed to send match reminders")
+			}
+		}
+	}
+}
+
+// SendDueReminders 为已到提醒时间的用户发送提醒，返回本次提醒的用户数
+func (s *MatchReminderService) SendDueReminders(ctx context.Context) (int, error) {
+	now := s.now()
+	maxLead := time.Duration(user.MaxReminderLeadMinutes) * time.Minute
+
+	matches, err := s.matchRepo.GetUpcoming(ctx, s.config.MaxMatches)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get upcoming matches: %w", err)
+	}
+
+	sent := 0
+	for i := range matches {
+		m := &matches[i]
+		untilStart := m.StartTime.Sub(now)
+		if untilStart <= 0 {
+			continue
+		}
+		if untilStart > maxLead {
+			// 按开始时间升序，之后的比赛都超出最大提前量
+			break
+		}
+
+		predictions, err := s.predictionRepo.GetPredictionsByMatch(ctx, m.ID, nil)
+		if err != nil {
+			s.logger.WithError(err).WithField("match_id", m.ID).Warn("Failed to get predictions for reminder")
+			continue
+		}
+
+		for _, pred := range predictions {
+			if pred.Prediction == nil {
+				continue
+			}
+			if s.remind(ctx, m, pred.UserID, untilStart) {
+				sent++
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// remind 按用户偏好发送单条提醒，返回是否已提醒
+func (s *MatchReminderService) remind(ctx context.Context, m *match.Match, userID uint, untilStart time.Duration) bool {
+	prefs, err := s.userRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to get notification preferences")
+		return false
+	}
+	if untilStart > prefs.ReminderLead() {
+		return false
+	}
+
+	sendEmail := prefs.EmailEnabled && s.emailSender != nil
+	sendPush := prefs.PushEnabled && s.pusher != nil
+	if !sendEmail && !sendPush {
+		return false
+	}
+
+	// 锁保留到比赛开始后，避免重复提醒
+	if s.lock != nil {
+		key := fmt.Sprintf("%s%d:%d", matchReminderKeyPrefix, m.ID, userID)
+		acquired, err := s.lock.Lock(ctx, key, untilStart+time.Hour)
+		if err != nil {
+			s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to acquire reminder lock")
+			return false
+		}
+		if !acquired {
+			return false
+		}
+	}
+
+	reminder := MatchReminder{
+		MatchID:           m.ID,
+		TeamA:             m.TeamA,
+		TeamB:             m.TeamB,
+		StartTime:         m.StartTime,
+		MinutesUntilStart: int(untilStart.Round(time.Minute).Minutes()),
+	}
+
+	if sendPush {
+		s.pusher.PushToUser(userID, ports.NotificationMatchReminder, reminder)
+	}
+	if sendEmail {
+		s.sendReminderEmail(ctx, userID, reminder)
+	}
+	return true
+}
+
+// sendReminderEmail 发送提醒邮件，失败只记录日志
+func (s *MatchReminderService) sendReminderEmail(ctx context.Context, userID uint, reminder MatchReminder) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || u.Email == "" {
+		s.logger.WithField("user_id", userID).Warn("Skipping reminder email: user email unavailable")
+		return
+	}
+
+	subject := fmt.Sprintf("比赛提醒：%s vs %s", reminder.TeamA, reminder.TeamB)
+	body := fmt.Sprintf("您好 %s：\n\n您预测的比赛 %s vs %s 将在 %d 分钟后（%s）开始。\n\n如不想再收到提醒，可在个人设置中关闭邮件通知。",
+		u.GetDisplayName(), reminder.TeamA, reminder.TeamB, reminder.MinutesUntilStart,
+		reminder.StartTime.Local().Format("2006-01-02 15:04"))
+	if err := s.emailSender.SendEmail(ctx, u.Email, subject, body); err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to send reminder email")
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+)
+
+// fakeUpcomingMatchRepo 返回固定的即将开始比赛
+type fakeUpcomingMatchRepo struct {
+	match.Repository
+	matches []match.Match
+}
+
+func (r *fakeUpcomingMatchRepo) GetUpcoming(ctx context.Context, limit int) ([]match.Match, error) {
+	return r.matches, nil
+}
+
+// fakeMatchPredictionRepo 按比赛返回预测
+type fakeMatchPredictionRepo struct {
+	prediction.Repository
+	byMatch map[uint][]prediction.PredictionWithVotes
+}
+
+func (r *fakeMatchPredictionRepo) GetPredictionsByMatch(ctx context.Context, matchID uint, userID *uint) ([]prediction.PredictionWithVotes, error) {
+	return r.byMatch[matchID], nil
+}
+
+// recordingPusher 记录推送的用户
+type recordingPusher struct {
+	users []uint
+}
+
+func (p *recordingPusher) PushToUser(userID uint, event string, data interface{}) int {
+	p.users = append(p.users, userID)
+	return 1
+}
+
+// recordingEmailSender 记录所有收件人
+type recordingEmailSender struct {
+	to []string
+}
+
+func (s *recordingEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	s.to = append(s.to, to)
+	return nil
+}
+
+// memoryLock 内存锁，同一键只能获取一次
+type memoryLock struct {
+	keys map[string]bool
+}
+
+func (l *memoryLock) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	if l.keys[key] {
+		return false, nil
+	}
+	l.keys[key] = true
+	return true, nil
+}
+
+func newTestMatchReminderService(start time.Time, userIDs ...uint) (*MatchReminderService, *fakeUserRepo, *recordingPusher, *recordingEmailSender) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	users := &fakeUserRepo{
+		users: make(map[uint]*user.User),
+		prefs: make(map[uint]*user.NotificationPreferences),
+	}
+	var predictions []prediction.PredictionWithVotes
+	for _, id := range userIDs {
+		users.users[id] = &user.User{ID: id, Username: "user", Email: "user@example.com"}
+		predictions = append(predictions, prediction.PredictionWithVotes{Prediction: &prediction.Prediction{MatchID: 1, UserID: id}})
+	}
+
+	pusher := &recordingPusher{}
+	sender := &recordingEmailSender{}
+	svc := NewMatchReminderService(
+		&fakeUpcomingMatchRepo{matches: []match.Match{{ID: 1, TeamA: "T1", TeamB: "GEN", StartTime: start}}},
+		&fakeMatchPredictionRepo{byMatch: map[uint][]prediction.PredictionWithVotes{1: predictions}},
+		users, sender, pusher, &memoryLock{keys: make(map[string]bool)},
+		MatchReminderConfig{}, logger,
+	)
+	return svc, users, pusher, sender
+}
+
+func TestSendDueReminders_RespectsLeadTime(t *testing.T) {
+	now := time.Date(2025, 10, 14, 12, 0, 0, 0, time.UTC)
+	svc, users, pusher, _ := newTestMatchReminderService(now.Add(90*time.Minute), 1, 2)
+	// 用户1 提前 60 分钟，用户2 使用默认的 30 分钟
+	users.prefs[1] = &user.NotificationPreferences{UserID: 1, EmailEnabled: true, ReminderLeadMinutes: 60, PushEnabled: true}
+
+	svc.now = func() time.Time { return now }
+	if sent, _ := svc.SendDueReminders(context.Background()); sent != 0 {
+		t.Errorf("开赛前 90 分钟不应提醒, sent = %d", sent)
+	}
+
+	svc.now = func() time.Time { return now.Add(30 * time.Minute) }
+	if sent, _ := svc.SendDueReminders(context.Background()); sent != 1 || len(pusher.users) != 1 || pusher.users[0] != 1 {
+		t.Errorf("开赛前 60 分钟应只提醒用户1, sent = %d, pushed = %v", sent, pusher.users)
+	}
+
+	// 再次扫描不应重复提醒
+	if sent, _ := svc.SendDueReminders(context.Background()); sent != 0 {
+		t.Errorf("同一比赛不应重复提醒, sent = %d", sent)
+	}
+}
+
+func TestSendDueReminders_RespectsChannelPreferences(t *testing.T) {
+	now := time.Date(2025, 10, 14, 12, 0, 0, 0, time.UTC)
+	svc, users, pusher, sender := newTestMatchReminderService(now.Add(10*time.Minute), 1, 2)
+	svc.now = func() time.Time { return now }
+	users.prefs[1] = &user.NotificationPreferences{UserID: 1, EmailEnabled: false, ReminderLeadMinutes: 30, PushEnabled: true}
+	users.prefs[2] = &user.NotificationPreferences{UserID: 2, EmailEnabled: false, ReminderLeadMinutes: 30, PushEnabled: false}
+
+	if sent, _ := svc.SendDueReminders(context.Background()); sent != 1 {
+		t.Errorf("只有开启推送的用户应被提醒, sent = %d", sent)
+	}
+	if len(sender.to) != 0 {
+		t.Errorf("关闭邮件的用户不应收到邮件: %v", sender.to)
+	}
+	if len(pusher.users) != 1 || pusher.users[0] != 1 {
+		t.Errorf("pushed = %v, want [1]", pusher.users)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/adapters/persistence/mysql"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+)
+
+var searchBaseTime = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newSearchTestService 创建使用内存数据库的比赛服务并写入种子比赛
+func newSearchTestService(t *testing.T) match.Service {
+	t.Helper()
+	db := newSimulationTestDB(t)
+
+	football, basketball := uint(1), uint(2)
+	seeds := []domain.Match{
+		{TeamA: "Gen.G", TeamB: "T1", Tournament: domain.TournamentSpring, SportTypeID: &football, Status: domain.MatchStatusFinished, StartTime: searchBaseTime},
+		{TeamA: "T1", TeamB: "HLE", Tournament: domain.TournamentSpring, SportTypeID: &football, Status: domain.MatchStatusFinished, StartTime: searchBaseTime.Add(24 * time.Hour)},
+		{TeamA: "JDG", TeamB: "BLG", Tournament: domain.TournamentSummer, SportTypeID: &basketball, Status: domain.MatchStatusLive, StartTime: searchBaseTime.Add(48 * time.Hour)},
+		{TeamA: "BLG", TeamB: "T1", Tournament: domain.TournamentSummer, SportTypeID: &football, Status: domain.MatchStatusUpcoming, StartTime: searchBaseTime.Add(72 * time.Hour)},
+		{TeamA: "100%_Team", TeamB: "HLE", Tournament: domain.TournamentWorlds, Status: domain.MatchStatusUpcoming, StartTime: searchBaseTime.Add(96 * time.Hour)},
+		{TeamA: "100 Thieves", TeamB: "TL", Tournament: domain.TournamentWorlds, Status: domain.MatchStatusUpcoming, StartTime: searchBaseTime.Add(96 * time.Hour)},
+	}
+	if err := db.Create(&seeds).Error; err != nil {
+		t.Fatalf("创建比赛失败: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewMatchService(mysql.NewMatchRepository(db), nil, nil, nil, nil, log)
+}
+
+func searchTitles(t *testing.T, svc match.Service, filter match.MatchSearchFilter) []string {
+	t.Helper()
+	page, err := svc.SearchMatches(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("SearchMatches(%+v) error = %v", filter, err)
+	}
+	titles := make([]string, len(page.Items))
+	for i, m := range page.Items {
+		titles[i] = m.Title
+	}
+	return titles
+}
+
+func TestMatchService_SearchMatchesFilters(t *testing.T) {
+	svc := newSearchTestService(t)
+	football := uint(1)
+	from, to := searchBaseTime.Add(24*time.Hour), searchBaseTime.Add(72*time.Hour)
+
+	tests := []struct {
+		name   string
+		filter match.MatchSearchFilter
+		want   []string
+	}{
+		{"无条件", match.MatchSearchFilter{}, []string{"Gen.G vs T1", "T1 vs HLE", "JDG vs BLG", "BLG vs T1", "100%_Team vs HLE", "100 Thieves vs TL"}},
+		{"任一选项", match.MatchSearchFilter{Query: "t1"}, []string{"Gen.G vs T1", "T1 vs HLE", "BLG vs T1"}},
+		{"标题两侧", match.MatchSearchFilter{Query: "BLG vs T1"}, []string{"BLG vs T1"}},
+		{"通配符按字面匹配", match.MatchSearchFilter{Query: "100%_"}, []string{"100%_Team vs HLE"}},
+		{"赛事", match.MatchSearchFilter{Tournament: domain.TournamentSummer}, []string{"JDG vs BLG", "BLG vs T1"}},
+		{"运动类型", match.MatchSearchFilter{SportTypeID: &football}, []string{"Gen.G vs T1", "T1 vs HLE", "BLG vs T1"}},
+		{"状态", match.MatchSearchFilter{Status: domain.MatchStatusUpcoming}, []string{"BLG vs T1", "100%_Team vs HLE", "100 Thieves vs TL"}},
+		{"时间范围", match.MatchSearchFilter{StartFrom: &from, StartTo: &to}, []string{"T1 vs HLE", "JDG vs BLG", "BLG vs T1"}},
+		{"组合条件", match.MatchSearchFilter{Query: "T1", SportTypeID: &football, StartFrom: &from, Status: domain.MatchStatusFinished}, []string{"T1 vs HLE"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchTitles(t, svc, tt.filter)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("结果 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchService_SearchMatchesPagination(t *testing.T) {
+	svc := newSearchTestService(t)
+	ctx := context.Background()
+
+	var titles []string
+	filter := match.MatchSearchFilter{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("分页未结束")
+		}
+		page, err := svc.SearchMatches(ctx, filter)
+		if err != nil {
+			t.Fatalf("SearchMatches() error = %v", err)
+		}
+		if len(page.Items) > 2 {
+			t.Fatalf("每页 %d 条, want <= 2", len(page.Items))
+		}
+		for _, m := range page.Items {
+			titles = append(titles, m.Title)
+		}
+		if !page.HasMore {
+			if page.NextCursor != "" {
+				t.Errorf("最后一页不应返回游标")
+			}
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	// 开始时间相同的比赛按 ID 排序，跨页不重复不遗漏
+	want := searchTitles(t, svc, match.MatchSearchFilter{})
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Errorf("逐页结果 = %v, want %v", titles, want)
+	}
+
+	// 同一游标重复请求结果稳定
+	first, _ := svc.SearchMatches(ctx, match.MatchSearchFilter{Limit: 5})
+	again, _ := svc.SearchMatches(ctx, match.MatchSearchFilter{Limit: 5, Cursor: first.NextCursor})
+	repeat, _ := svc.SearchMatches(ctx, match.MatchSearchFilter{Limit: 5, Cursor: first.NextCursor})
+	if len(again.Items) != 1 || len(repeat.Items) != 1 || again.Items[0].ID != repeat.Items[0].ID || again.Items[0].Title != "100 Thieves vs TL" {
+		t.Errorf("游标翻页结果不稳定: %+v / %+v", again.Items, repeat.Items)
+	}
+}
+
+func TestMatchService_SearchMatchesValidation(t *testing.T) {
+	svc := newSearchTestService(t)
+	from, to := searchBaseTime.Add(time.Hour), searchBaseTime
+
+	tests := []struct {
+		name   string
+		filter match.MatchSearchFilter
+	}{
+		{"选项超长", match.MatchSearchFilter{Query: strings.Repeat("a", match.MaxOptionLength+1)}},
+		{"标题一侧超长", match.MatchSearchFilter{Query: "T1 vs " + strings.Repeat("b", match.MaxOptionLength+1)}},
+		{"未知赛事", match.MatchSearchFilter{Tournament: "MSI"}},
+		{"未知状态", match.MatchSearchFilter{Status: "PAUSED"}},
+		{"时间范围颠倒", match.MatchSearchFilter{StartFrom: &from, StartTo: &to}},
+		{"非法游标", match.MatchSearchFilter{Cursor: "not-a-cursor"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SearchMatches(context.Background(), tt.filter); !errors.Is(err, domain.ErrInvalidSearchFilter) {
+				t.Errorf("err = %v, want ErrInvalidSearchFilter", err)
+			}
+		})
+	}
+
+	// 两侧均不超过选项长度的标题可以搜索
+	if _, err := svc.SearchMatches(context.Background(), match.MatchSearchFilter{
+		Query: strings.Repeat("a", match.MaxOptionLength) + " vs " + strings.Repeat("b", match.MaxOptionLength),
+	}); err != nil {
+		t.Errorf("最大长度标题 err = %v", err)
+	}
+}
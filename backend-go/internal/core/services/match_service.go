@@ -7,19 +7,27 @@ import (
 	"backend-go/internal/core/domain"
 	"backend-go/internal/core/domain/match"
 	"backend-go/internal/core/domain/shared"
+	"backend-go/pkg/redis"
 	"github.com/sirupsen/logrus"
 )
 
+// TagInvalidator 按标签批量失效缓存
+type TagInvalidator interface {
+	InvalidateTag(ctx context.Context, tag string) error
+}
+
 // MatchService 比赛服务实现
 type MatchService struct {
 	matchRepo    match.Repository
 	cacheService *MatchCacheService
+	cacheTags    TagInvalidator
+	stats        MatchStatsStore
 	eventBus     shared.EventBus
 	logger       *logrus.Logger
 }
 
-// NewMatchService 创建比赛服务实例
-func NewMatchService(matchRepo match.Repository, cacheService *MatchCacheService, eventBus shared.EventBus, logger *logrus.Logger) match.Service {
+// NewMatchService 创建比赛服务实例，cacheTags 用于清理挂在比赛标签下的派生缓存，stats 用于热门比赛（均可为 nil）
+func NewMatchService(matchRepo match.Repository, cacheService *MatchCacheService, cacheTags TagInvalidator, stats MatchStatsStore, eventBus shared.EventBus, logger *logrus.Logger) match.Service {
 	if logger == nil {
 		logger = logrus.New()
 	}
@@ -27,11 +35,31 @@ func NewMatchService(matchRepo match.Repository, cacheService *MatchCacheService
 	return &MatchService{
 		matchRepo:    matchRepo,
 		cacheService: cacheService,
+		cacheTags:    cacheTags,
+		stats:        stats,
 		eventBus:     eventBus,
 		logger:       logger,
 	}
 }
 
+// invalidateMatchCaches 使比赛详情、列表及比赛标签下的派生缓存（如预测共识）失效
+func (s *MatchService) invalidateMatchCaches(ctx context.Context, id uint) {
+	if s.cacheService != nil {
+		if err := s.cacheService.InvalidateMatch(ctx, id); err != nil {
+			s.logger.WithError(err).Warn("Failed to invalidate match cache")
+		}
+		if err := s.cacheService.InvalidateMatchLists(ctx); err != nil {
+			s.logger.WithError(err).Warn("Failed to invalidate match lists cache")
+		}
+	}
+
+	if s.cacheTags != nil {
+		if err := s.cacheTags.InvalidateTag(ctx, redis.MatchTag(id)); err != nil {
+			s.logger.WithError(err).WithField("match_id", id).Warn("Failed to invalidate match tag")
+		}
+	}
+}
+
 // CreateMatch 创建比赛
 func (s *MatchService) CreateMatch(ctx context.Context, req *match.CreateMatchRequest) (*match.Match, error) {
 	// 验证输入
@@ -57,6 +85,11 @@ func (s *MatchService) CreateMatch(ctx context.Context, req *match.CreateMatchRe
 		Status:     match.MatchStatusUpcoming,
 		ScoreA:     0,
 		ScoreB:     0,
+
+		PredictionLockAt: req.PredictionLockAt,
+	}
+	if err := m.ValidatePredictionLock(); err != nil {
+		return nil, err
 	}
 
 	// 保存到数据库
@@ -118,6 +151,38 @@ func (s *MatchService) ListMatches(ctx context.Context, filter match.ListFilter)
 	return matches, nil
 }
 
+// SearchMatches 搜索比赛
+//
+// 按 (start_time, id) 键集分页，多取一条判断是否还有下一页，翻页期间新增的比赛不会导致重复或遗漏。
+func (s *MatchService) SearchMatches(ctx context.Context, filter match.MatchSearchFilter) (*match.MatchPage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	limit := filter.PageLimit()
+	filter.Limit = limit + 1
+	matches, err := s.matchRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &match.MatchPage{Items: matches}
+	if len(matches) > limit {
+		page.Items = matches[:limit]
+		page.HasMore = true
+		last := page.Items[limit-1]
+		page.NextCursor = match.EncodeSearchCursor(match.SearchCursor{StartTime: last.StartTime, ID: last.ID})
+	}
+	if page.Items == nil {
+		page.Items = []match.Match{}
+	}
+	for i := range page.Items {
+		page.Items[i].FillComputedFields()
+	}
+
+	return page, nil
+}
+
 // UpdateMatch 更新比赛信息
 func (s *MatchService) UpdateMatch(ctx context.Context, id uint, req *match.UpdateMatchRequest) (*match.Match, error) {
 	// 获取现有比赛
@@ -154,6 +219,14 @@ func (s *MatchService) UpdateMatch(ctx context.Context, id uint, req *match.Upda
 		m.StartTime = *req.StartTime
 	}
 
+	// 调整开始时间后，已有的单场锁定时间同样需要早于开始时间
+	if req.PredictionLockAt != nil {
+		m.PredictionLockAt = req.PredictionLockAt
+	}
+	if err := m.ValidatePredictionLock(); err != nil {
+		return nil, err
+	}
+
 	// 保存更新
 	err = s.matchRepo.Update(ctx, m)
 	if err != nil {
@@ -161,14 +234,7 @@ func (s *MatchService) UpdateMatch(ctx context.Context, id uint, req *match.Upda
 	}
 
 	// 使缓存失效
-	if s.cacheService != nil {
-		if err := s.cacheService.InvalidateMatch(ctx, id); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match cache")
-		}
-		if err := s.cacheService.InvalidateMatchLists(ctx); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match lists cache")
-		}
-	}
+	s.invalidateMatchCaches(ctx, id)
 
 	return m, nil
 }
@@ -195,14 +261,7 @@ func (s *MatchService) StartMatch(ctx context.Context, id uint) error {
 	}
 
 	// 使缓存失效
-	if s.cacheService != nil {
-		if err := s.cacheService.InvalidateMatch(ctx, id); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match cache")
-		}
-		if err := s.cacheService.InvalidateMatchLists(ctx); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match lists cache")
-		}
-	}
+	s.invalidateMatchCaches(ctx, id)
 
 	// 发布比赛开始事件
 	if s.eventBus != nil {
@@ -264,14 +323,7 @@ func (s *MatchService) SetResult(ctx context.Context, id uint, req *match.SetRes
 	}
 
 	// 使缓存失效
-	if s.cacheService != nil {
-		if err := s.cacheService.InvalidateMatch(ctx, id); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match cache")
-		}
-		if err := s.cacheService.InvalidateMatchLists(ctx); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match lists cache")
-		}
-	}
+	s.invalidateMatchCaches(ctx, id)
 
 	// 发布比赛结束事件
 	if s.eventBus != nil {
@@ -345,14 +397,7 @@ func (s *MatchService) CancelMatch(ctx context.Context, id uint) error {
 	}
 
 	// 使缓存失效
-	if s.cacheService != nil {
-		if err := s.cacheService.InvalidateMatch(ctx, id); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match cache")
-		}
-		if err := s.cacheService.InvalidateMatchLists(ctx); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match lists cache")
-		}
-	}
+	s.invalidateMatchCaches(ctx, id)
 
 	// 发布比赛取消事件
 	if s.eventBus != nil {
@@ -484,14 +529,7 @@ func (s *MatchService) UpdateScore(ctx context.Context, id uint, scoreA, scoreB
 	}
 
 	// 使缓存失效
-	if s.cacheService != nil {
-		if err := s.cacheService.InvalidateMatch(ctx, id); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match cache")
-		}
-		if err := s.cacheService.InvalidateMatchLists(ctx); err != nil {
-			s.logger.WithError(err).Warn("Failed to invalidate match lists cache")
-		}
-	}
+	s.invalidateMatchCaches(ctx, id)
 
 	// 发布比分更新事件
 	if s.eventBus != nil {
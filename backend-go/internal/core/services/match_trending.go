@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+)
+
+const (
+	// MatchViewsKeyPrefix 比赛浏览计数键前缀，由统计事件处理器累加
+	MatchViewsKeyPrefix = "stats:match:views:"
+
+	// TrendingMatchesKeyPrefix 热门比赛缓存键前缀
+	TrendingMatchesKeyPrefix = "match:trending:"
+
+	// TrendingMatchesTTL 热门比赛缓存时间 (30秒)
+	TrendingMatchesTTL = 30 * time.Second
+)
+
+// MatchStatsStore 读取比赛浏览计数并缓存热门比赛，redis.CacheService 满足该接口
+type MatchStatsStore interface {
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+	GetJSON(ctx context.Context, key string, dest interface{}) error
+	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// GetTrendingMatches 获取热门比赛
+//
+// 只统计即将开始与进行中的比赛，热度由预测数与浏览量加权得出，热度相同时先开始的比赛靠前。
+// 结果短暂缓存，未配置 stats 时浏览量按 0 计算且不缓存。
+func (s *MatchService) GetTrendingMatches(ctx context.Context, tournament string, limit int) ([]match.TrendingMatch, error) {
+	if tournament != "" && !domain.IsValidTournament(tournament) {
+		return nil, domain.ErrInvalidTournament
+	}
+	if limit <= 0 {
+		limit = match.DefaultTrendingLimit
+	}
+	if limit > match.MaxTrendingLimit {
+		limit = match.MaxTrendingLimit
+	}
+
+	key := fmt.Sprintf("%s%s:%d", TrendingMatchesKeyPrefix, tournament, limit)
+	if s.stats != nil {
+		var cached []match.TrendingMatch
+		if err := s.stats.GetJSON(ctx, key, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	candidates, err := s.matchRepo.GetActiveWithPredictionCounts(ctx, tournament)
+	if err != nil {
+		return nil, err
+	}
+	views := s.matchViewCounts(ctx, candidates)
+
+	trending := make([]match.TrendingMatch, len(candidates))
+	for i, candidate := range candidates {
+		m := candidate.Match
+		m.FillComputedFields()
+		trending[i] = match.TrendingMatch{
+			Match:           m,
+			PredictionCount: candidate.PredictionCount,
+			ViewCount:       views[m.ID],
+			Score:           match.TrendingScore(candidate.PredictionCount, views[m.ID]),
+		}
+	}
+	sort.SliceStable(trending, func(i, j int) bool {
+		if trending[i].Score != trending[j].Score {
+			return trending[i].Score > trending[j].Score
+		}
+		if !trending[i].Match.StartTime.Equal(trending[j].Match.StartTime) {
+			return trending[i].Match.StartTime.Before(trending[j].Match.StartTime)
+		}
+		return trending[i].Match.ID < trending[j].Match.ID
+	})
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+
+	if s.stats != nil {
+		if err := s.stats.SetJSON(ctx, key, trending, TrendingMatchesTTL); err != nil {
+			s.logger.WithError(err).Warn("Failed to cache trending matches")
+		}
+	}
+	return trending, nil
+}
+
+// matchViewCounts 批量读取比赛浏览计数，读取失败时按 0 计算
+func (s *MatchService) matchViewCounts(ctx context.Context, candidates []match.MatchPredictionCount) map[uint]int64 {
+	views := make(map[uint]int64, len(candidates))
+	if s.stats == nil || len(candidates) == 0 {
+		return views
+	}
+
+	keys := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		keys[i] = fmt.Sprintf("%s%d", MatchViewsKeyPrefix, candidate.Match.ID)
+	}
+	values, err := s.stats.MGet(ctx, keys...)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get match view counts")
+		return views
+	}
+	for i, value := range values {
+		if i >= len(candidates) {
+			break
+		}
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if count, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			views[candidates[i].Match.ID] = count
+		}
+	}
+	return views
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"backend-go/internal/adapters/persistence/mysql"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+)
+
+// memoryMatchStats 内存版浏览计数与热门比赛缓存
+type memoryMatchStats struct {
+	MatchStatsStore
+	values map[string]string
+	sets   int
+}
+
+func (s *memoryMatchStats) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if value, ok := s.values[key]; ok {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *memoryMatchStats) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	value, ok := s.values[key]
+	if !ok {
+		return errors.New("cache miss")
+	}
+	return json.Unmarshal([]byte(value), dest)
+}
+
+func (s *memoryMatchStats) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.values[key] = string(data)
+	s.sets++
+	return nil
+}
+
+// seedTrendingMatches 写入比赛、预测与浏览计数，返回按队伍 A 名称索引的比赛 ID
+func seedTrendingMatches(t *testing.T, db *gorm.DB, stats *memoryMatchStats) map[string]uint {
+	t.Helper()
+	start := time.Now().Add(24 * time.Hour)
+	seeds := []struct {
+		match       domain.Match
+		predictions int
+		views       int
+	}{
+		// 热度 = 预测数*5 + 浏览量
+		{domain.Match{TeamA: "Quiet", TeamB: "X", Tournament: domain.TournamentSpring, Status: domain.MatchStatusUpcoming, StartTime: start}, 1, 0},         // 5
+		{domain.Match{TeamA: "Viewed", TeamB: "X", Tournament: domain.TournamentSpring, Status: domain.MatchStatusUpcoming, StartTime: start}, 0, 40},       // 40
+		{domain.Match{TeamA: "Predicted", TeamB: "X", Tournament: domain.TournamentSpring, Status: domain.MatchStatusLive, StartTime: start}, 6, 2},         // 32
+		{domain.Match{TeamA: "Mixed", TeamB: "X", Tournament: domain.TournamentSpring, Status: domain.MatchStatusUpcoming, StartTime: start}, 4, 20},        // 40，同分按 ID
+		{domain.Match{TeamA: "Finished", TeamB: "X", Tournament: domain.TournamentSpring, Status: domain.MatchStatusFinished, StartTime: start}, 20, 100},   // 已结束
+		{domain.Match{TeamA: "Cancelled", TeamB: "X", Tournament: domain.TournamentSpring, Status: domain.MatchStatusCancelled, StartTime: start}, 20, 100}, // 已取消
+		{domain.Match{TeamA: "Summer", TeamB: "X", Tournament: domain.TournamentSummer, Status: domain.MatchStatusUpcoming, StartTime: start}, 2, 0},        // 10
+	}
+
+	ids := make(map[string]uint, len(seeds))
+	for _, seed := range seeds {
+		m := seed.match
+		if err := db.Create(&m).Error; err != nil {
+			t.Fatalf("创建比赛失败: %v", err)
+		}
+		ids[m.TeamA] = m.ID
+		for i := 0; i < seed.predictions; i++ {
+			p := prediction.Prediction{UserID: uint(i + 1), MatchID: m.ID, PredictedWinner: "A"}
+			if err := db.Create(&p).Error; err != nil {
+				t.Fatalf("创建预测失败: %v", err)
+			}
+		}
+		if seed.views > 0 {
+			stats.values[fmt.Sprintf("%s%d", MatchViewsKeyPrefix, m.ID)] = fmt.Sprint(seed.views)
+		}
+	}
+	return ids
+}
+
+func newTrendingTestService(t *testing.T) (match.Service, *memoryMatchStats, map[string]uint) {
+	t.Helper()
+	db := newSimulationTestDB(t)
+	stats := &memoryMatchStats{values: map[string]string{}}
+	ids := seedTrendingMatches(t, db, stats)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewMatchService(mysql.NewMatchRepository(db), nil, nil, stats, nil, log), stats, ids
+}
+
+func trendingTeams(items []match.TrendingMatch) []string {
+	teams := make([]string, len(items))
+	for i, item := range items {
+		teams[i] = item.Match.TeamA
+	}
+	return teams
+}
+
+func TestMatchService_GetTrendingMatchesRanking(t *testing.T) {
+	svc, _, _ := newTrendingTestService(t)
+
+	items, err := svc.GetTrendingMatches(context.Background(), string(domain.TournamentSpring), 0)
+	if err != nil {
+		t.Fatalf("GetTrendingMatches() error = %v", err)
+	}
+	want := []string{"Viewed", "Mixed", "Predicted", "Quiet"}
+	if got := trendingTeams(items); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("排序 = %v, want %v", got, want)
+	}
+	if items[2].PredictionCount != 6 || items[2].ViewCount != 2 || items[2].Score != 32 {
+		t.Errorf("Predicted = %+v, want 6 条预测、2 次浏览、热度 32", items[2])
+	}
+
+	all, err := svc.GetTrendingMatches(context.Background(), "", 2)
+	if err != nil {
+		t.Fatalf("GetTrendingMatches() error = %v", err)
+	}
+	if got := trendingTeams(all); fmt.Sprint(got) != "[Viewed Mixed]" {
+		t.Errorf("不限赛事前 2 名 = %v, want [Viewed Mixed]", got)
+	}
+}
+
+func TestMatchService_GetTrendingMatchesExcludesFinished(t *testing.T) {
+	svc, _, _ := newTrendingTestService(t)
+
+	items, err := svc.GetTrendingMatches(context.Background(), "", match.MaxTrendingLimit)
+	if err != nil {
+		t.Fatalf("GetTrendingMatches() error = %v", err)
+	}
+	for _, item := range items {
+		if item.Match.Status != domain.MatchStatusUpcoming && item.Match.Status != domain.MatchStatusLive {
+			t.Errorf("包含了状态为 %s 的比赛 %s", item.Match.Status, item.Match.TeamA)
+		}
+	}
+	if len(items) != 5 {
+		t.Errorf("数量 = %d, want 5", len(items))
+	}
+
+	if _, err := svc.GetTrendingMatches(context.Background(), "UNKNOWN", 10); !errors.Is(err, domain.ErrInvalidTournament) {
+		t.Errorf("未知赛事 error = %v, want ErrInvalidTournament", err)
+	}
+}
+
+func TestMatchService_GetTrendingMatchesCached(t *testing.T) {
+	svc, stats, ids := newTrendingTestService(t)
+
+	first, err := svc.GetTrendingMatches(context.Background(), string(domain.TournamentSpring), 10)
+	if err != nil {
+		t.Fatalf("GetTrendingMatches() error = %v", err)
+	}
+
+	// 缓存期内浏览量变化不影响结果
+	stats.values[fmt.Sprintf("%s%d", MatchViewsKeyPrefix, ids["Quiet"])] = "1000"
+	second, err := svc.GetTrendingMatches(context.Background(), string(domain.TournamentSpring), 10)
+	if err != nil {
+		t.Fatalf("GetTrendingMatches() error = %v", err)
+	}
+	if fmt.Sprint(trendingTeams(second)) != fmt.Sprint(trendingTeams(first)) || stats.sets != 1 {
+		t.Errorf("第二次结果 = %v（写入缓存 %d 次）, want 命中缓存 %v", trendingTeams(second), stats.sets, trendingTeams(first))
+	}
+}
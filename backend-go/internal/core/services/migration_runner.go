@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/pkg/deadline"
+)
+
+// migrationRunLockKey is the distributed lock held for the duration of a run.
+const migrationRunLockKey = "lock:migration:run"
+
+// defaultMigrationRunTimeout bounds a background run and the lock that guards it.
+const defaultMigrationRunTimeout = 30 * time.Minute
+
+// ErrMigrationRunning is returned when another run holds the migration lock.
+var ErrMigrationRunning = errors.New("migration is already running")
+
+// MigrationJobStatus is the state of a background migration run.
+type MigrationJobStatus string
+
+const (
+	MigrationJobRunning   MigrationJobStatus = "running"
+	MigrationJobSucceeded MigrationJobStatus = "succeeded"
+	MigrationJobFailed    MigrationJobStatus = "failed"
+)
+
+// MigrationLocker is the distributed lock used to serialize runs across instances;
+// redis.CacheService satisfies it.
+type MigrationLocker interface {
+	Lock(ctx context.Context, key string, expiration time.Duration) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
+// migrationExecutor is the part of MigrationService used by the runner.
+type migrationExecutor interface {
+	RunUpMigrations(ctx context.Context, migrationsDir string) error
+	GetMigrationStatus(ctx context.Context) (map[string]interface{}, error)
+}
+
+// MigrationJob describes a migration run started through MigrationRunner.
+type MigrationJob struct {
+	ID          string             `json:"id"`
+	Status      MigrationJobStatus `json:"status"`
+	RequestedBy uint               `json:"requested_by"`
+	StartedAt   time.Time          `json:"started_at"`
+	FinishedAt  *time.Time         `json:"finished_at,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// MigrationRunStatus combines the schema migration status with the latest run.
+type MigrationRunStatus struct {
+	Locked     bool                   `json:"locked"`
+	Migrations map[string]interface{} `json:"migrations"`
+	LastJob    *MigrationJob          `json:"last_job,omitempty"`
+}
+
+// MigrationRunnerConfig configures MigrationRunner.
+type MigrationRunnerConfig struct {
+	MigrationsDir string
+	Timeout       time.Duration // maximum duration of a run, also the lock TTL; defaults to 30 minutes
+}
+
+// MigrationRunner starts migration runs in the background so they can be
+// triggered over HTTP. A distributed lock guarantees at most one run at a time
+// across all API instances.
+type MigrationRunner struct {
+	migrator migrationExecutor
+	locker   MigrationLocker
+	config   MigrationRunnerConfig
+	logger   *logrus.Logger
+
+	mu      sync.Mutex
+	lastJob *MigrationJob
+}
+
+// NewMigrationRunner creates a migration runner.
+func NewMigrationRunner(migrator migrationExecutor, locker MigrationLocker, config MigrationRunnerConfig, logger *logrus.Logger) *MigrationRunner {
+	if config.MigrationsDir == "" {
+		config.MigrationsDir = "migrations"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultMigrationRunTimeout
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &MigrationRunner{
+		migrator: migrator,
+		locker:   locker,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// Start acquires the migration lock and runs RunUpMigrations in the background.
+// It returns ErrMigrationRunning when another run holds the lock.
+func (r *MigrationRunner) Start(ctx context.Context, requestedBy uint) (*MigrationJob, error) {
+	acquired, err := r.locker.Lock(ctx, migrationRunLockKey, r.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !acquired {
+		return nil, ErrMigrationRunning
+	}
+
+	id, err := newMigrationJobID()
+	if err != nil {
+		r.unlock()
+		return nil, err
+	}
+	job := &MigrationJob{
+		ID:          id,
+		Status:      MigrationJobRunning,
+		RequestedBy: requestedBy,
+		StartedAt:   time.Now(),
+	}
+	r.mu.Lock()
+	r.lastJob = job
+	snapshot := *job
+	r.mu.Unlock()
+
+	r.logger.WithFields(logrus.Fields{"job_id": id, "requested_by": requestedBy}).Warn("Migration run started")
+	go r.run(job)
+
+	return &snapshot, nil
+}
+
+// Status returns the schema migration status, whether a run is in progress and the latest run.
+func (r *MigrationRunner) Status(ctx context.Context) (*MigrationRunStatus, error) {
+	migrations, err := r.migrator.GetMigrationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationRunStatus{Migrations: migrations}
+	if locked, ok := migrations["migration_locked"].(bool); ok {
+		status.Locked = locked
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastJob != nil {
+		job := *r.lastJob
+		status.LastJob = &job
+		status.Locked = status.Locked || job.Status == MigrationJobRunning
+	}
+	return status, nil
+}
+
+// run executes the migrations, releases the lock and records the outcome on job.
+// The lock is released first so that a finished job always means a new run can start.
+func (r *MigrationRunner) run(job *MigrationJob) {
+	// DDL can run far longer than database.query_timeout, so a run is bounded only by its own timeout
+	ctx, cancel := context.WithTimeout(deadline.WithoutCallTimeout(context.Background()), r.config.Timeout)
+	err := r.migrator.RunUpMigrations(ctx, r.config.MigrationsDir)
+	cancel()
+	r.unlock()
+	finished := time.Now()
+
+	r.mu.Lock()
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = MigrationJobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = MigrationJobSucceeded
+	}
+	r.mu.Unlock()
+
+	entry := r.logger.WithFields(logrus.Fields{"job_id": job.ID, "duration": finished.Sub(job.StartedAt)})
+	if err != nil {
+		entry.WithError(err).Error("Migration run failed")
+		return
+	}
+	entry.Info("Migration run completed")
+}
+
+func (r *MigrationRunner) unlock() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.locker.Unlock(ctx, migrationRunLockKey); err != nil {
+		r.logger.WithError(err).Error("Failed to release migration lock")
+	}
+}
+
+func newMigrationJobID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate migration job id: %w", err)
+	}
+	return "mig_" + hex.EncodeToString(buf), nil
+}
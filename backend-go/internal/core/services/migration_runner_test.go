@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"backend-go/pkg/database"
+)
+
+// memoryMigrationLocker 进程内的迁移锁
+type memoryMigrationLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *memoryMigrationLocker) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] {
+		return false, nil
+	}
+	l.held[key] = true
+	return true, nil
+}
+
+func (l *memoryMigrationLocker) Unlock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.held, key)
+	return nil
+}
+
+// blockingMigrator 的迁移在 release 关闭前阻塞，结束后通过 done 通知
+type blockingMigrator struct {
+	release chan struct{}
+	done    chan struct{}
+	err     error
+}
+
+func (m *blockingMigrator) RunUpMigrations(ctx context.Context, migrationsDir string) error {
+	defer close(m.done)
+	<-m.release
+	return m.err
+}
+
+func (m *blockingMigrator) GetMigrationStatus(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{"applied_count": 3, "failed_count": 0, "migration_locked": false}, nil
+}
+
+func newTestMigrationRunner(migrator *blockingMigrator) *MigrationRunner {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewMigrationRunner(migrator, &memoryMigrationLocker{held: map[string]bool{}}, MigrationRunnerConfig{}, logger)
+}
+
+func waitMigrationJob(t *testing.T, runner *MigrationRunner, done chan struct{}) *MigrationRunStatus {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("迁移未结束")
+	}
+	// 迁移返回后状态与锁在同一协程内更新，稍作等待
+	deadline := time.Now().Add(time.Second)
+	for {
+		status, err := runner.Status(context.Background())
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if !status.Locked || time.Now().After(deadline) {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMigrationRunner_RejectsConcurrentRun(t *testing.T) {
+	migrator := &blockingMigrator{release: make(chan struct{}), done: make(chan struct{})}
+	runner := newTestMigrationRunner(migrator)
+
+	job, err := runner.Start(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if job.ID == "" || job.Status != MigrationJobRunning || job.RequestedBy != 1 {
+		t.Fatalf("job = %+v, want 运行中的任务", job)
+	}
+
+	if _, err := runner.Start(context.Background(), 2); !errors.Is(err, ErrMigrationRunning) {
+		t.Fatalf("第二次 Start() error = %v, want ErrMigrationRunning", err)
+	}
+
+	status, err := runner.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Locked || status.LastJob == nil || status.LastJob.ID != job.ID {
+		t.Fatalf("status = %+v, want 锁定且最近任务为 %s", status, job.ID)
+	}
+
+	close(migrator.release)
+	status = waitMigrationJob(t, runner, migrator.done)
+	if status.Locked || status.LastJob.Status != MigrationJobSucceeded || status.LastJob.FinishedAt == nil {
+		t.Fatalf("status = %+v, want 已解锁且任务成功", status)
+	}
+	if status.Migrations["applied_count"] != 3 {
+		t.Errorf("migrations = %v, want 包含迁移统计", status.Migrations)
+	}
+
+	// 锁释放后可以再次发起
+	next := &blockingMigrator{release: make(chan struct{}), done: make(chan struct{})}
+	close(next.release)
+	runner.migrator = next
+	if _, err := runner.Start(context.Background(), 1); err != nil {
+		t.Fatalf("锁释放后 Start() error = %v", err)
+	}
+	waitMigrationJob(t, runner, next.done)
+}
+
+func TestMigrationRunner_RecordsFailure(t *testing.T) {
+	migrator := &blockingMigrator{release: make(chan struct{}), done: make(chan struct{}), err: errors.New("syntax error")}
+	close(migrator.release)
+	runner := newTestMigrationRunner(migrator)
+
+	if _, err := runner.Start(context.Background(), 1); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	status := waitMigrationJob(t, runner, migrator.done)
+	if status.Locked || status.LastJob.Status != MigrationJobFailed || status.LastJob.Error != "syntax error" {
+		t.Errorf("status = %+v, want 任务失败并记录错误", status.LastJob)
+	}
+}
+
+// budgetedMigrator 在注册了单条语句超时回调的数据库上执行迁移语句
+type budgetedMigrator struct {
+	db   *gorm.DB
+	done chan struct{}
+	err  error
+}
+
+func (m *budgetedMigrator) RunUpMigrations(ctx context.Context, migrationsDir string) error {
+	defer close(m.done)
+	m.err = m.db.WithContext(ctx).Exec("CREATE TABLE slow_ddl (id INTEGER PRIMARY KEY)").Error
+	return m.err
+}
+
+func (m *budgetedMigrator) GetMigrationStatus(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func TestMigrationRunner_IgnoresPerStatementTimeout(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 单条语句超时极短：普通调用必然超时，迁移应只受整体时限约束
+	if err := database.RegisterDeadlineBudget(db, time.Nanosecond, 0); err != nil {
+		t.Fatalf("RegisterDeadlineBudget() error = %v", err)
+	}
+	if err := db.Exec("SELECT 1").Error; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("普通语句 error = %v, want DeadlineExceeded", err)
+	}
+
+	migrator := &budgetedMigrator{db: db, done: make(chan struct{})}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	runner := NewMigrationRunner(migrator, &memoryMigrationLocker{held: map[string]bool{}}, MigrationRunnerConfig{}, logger)
+
+	if _, err := runner.Start(context.Background(), 1); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	status := waitMigrationJob(t, runner, migrator.done)
+	if migrator.err != nil || status.LastJob.Status != MigrationJobSucceeded {
+		t.Fatalf("迁移 error = %v, status = %+v, want 成功", migrator.err, status.LastJob)
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// schemaInspector reports whether tables and columns exist in the live schema.
+// gorm.Migrator satisfies this interface.
+type schemaInspector interface {
+	HasTable(dst interface{}) bool
+	HasColumn(dst interface{}, field string) bool
+}
+
+// SchemaConflict describes a pending manual migration statement that disagrees with the live schema.
+type SchemaConflict struct {
+	Version string
+	Table   string
+	Column  string // empty for table-level conflicts
+	Reason  string
+}
+
+// String formats the conflict for logs.
+func (c SchemaConflict) String() string {
+	target := c.Table
+	if c.Column != "" {
+		target += "." + c.Column
+	}
+	return fmt.Sprintf("%s: %s (%s)", c.Version, target, c.Reason)
+}
+
+var (
+	sqlLineComment     = regexp.MustCompile(`--[^\n]*`)
+	sqlBlockComment    = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	createTablePattern = regexp.MustCompile("(?is)^CREATE\\s+TABLE\\s+(IF\\s+NOT\\s+EXISTS\\s+)?`?(\\w+)`?")
+	alterTablePattern  = regexp.MustCompile("(?is)^ALTER\\s+TABLE\\s+`?(\\w+)`?\\s+(.*)$")
+	addColumnPattern   = regexp.MustCompile("(?is)^ADD\\s+(COLUMN\\s+)?`?(\\w+)`?")
+	dropColumnPattern  = regexp.MustCompile("(?is)^DROP\\s+(COLUMN\\s+)?`?(\\w+)`?")
+)
+
+// nonColumnKeywords follow ADD/DROP when the clause targets something other than a column.
+var nonColumnKeywords = map[string]bool{
+	"INDEX": true, "KEY": true, "UNIQUE": true, "PRIMARY": true, "FOREIGN": true,
+	"CONSTRAINT": true, "FULLTEXT": true, "SPATIAL": true, "CHECK": true, "PARTITION": true,
+}
+
+// CheckSchemaConsistency compares the live schema against what the pending manual
+// migrations expect, reporting column adds and table creates that AutoMigrate has
+// already applied, column drops for columns that do not exist, and alters of tables
+// that neither exist nor are created by an earlier pending statement.
+func (s *MigrationService) CheckSchemaConsistency(ctx context.Context, migrationsDir string) ([]SchemaConflict, error) {
+	pending, err := s.GetPendingMigrationFiles(ctx, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	return findSchemaConflicts(s.db.WithContext(ctx).Migrator(), pending), nil
+}
+
+// findSchemaConflicts checks each statement of the pending up migrations against inspector.
+func findSchemaConflicts(inspector schemaInspector, pending []MigrationFile) []SchemaConflict {
+	var conflicts []SchemaConflict
+	created := make(map[string]bool)
+	for _, file := range pending {
+		for _, stmt := range splitSQLStatements(file.Content) {
+			if m := createTablePattern.FindStringSubmatch(stmt); m != nil {
+				if m[1] == "" && inspector.HasTable(m[2]) {
+					conflicts = append(conflicts, SchemaConflict{Version: file.Version, Table: m[2], Reason: "table already exists"})
+				}
+				created[strings.ToLower(m[2])] = true
+				continue
+			}
+
+			m := alterTablePattern.FindStringSubmatch(stmt)
+			if m == nil {
+				continue
+			}
+			table := m[1]
+			if created[strings.ToLower(table)] {
+				continue
+			}
+			if !inspector.HasTable(table) {
+				conflicts = append(conflicts, SchemaConflict{Version: file.Version, Table: table, Reason: "table does not exist"})
+				continue
+			}
+			for _, clause := range splitTopLevel(m[2]) {
+				if column, ok := columnClause(addColumnPattern, clause); ok {
+					if inspector.HasColumn(table, column) {
+						conflicts = append(conflicts, SchemaConflict{Version: file.Version, Table: table, Column: column, Reason: "column already exists"})
+					}
+				} else if column, ok := columnClause(dropColumnPattern, clause); ok {
+					if !inspector.HasColumn(table, column) {
+						conflicts = append(conflicts, SchemaConflict{Version: file.Version, Table: table, Column: column, Reason: "column to drop does not exist"})
+					}
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// columnClause returns the column targeted by an ADD/DROP clause, ignoring index and constraint clauses.
+func columnClause(pattern *regexp.Regexp, clause string) (string, bool) {
+	m := pattern.FindStringSubmatch(clause)
+	if m == nil {
+		return "", false
+	}
+	if m[1] == "" && nonColumnKeywords[strings.ToUpper(m[2])] {
+		return "", false
+	}
+	return m[2], true
+}
+
+// splitSQLStatements strips comments and splits content into trimmed statements.
+func splitSQLStatements(content string) []string {
+	content = sqlBlockComment.ReplaceAllString(content, "")
+	content = sqlLineComment.ReplaceAllString(content, "")
+
+	var statements []string
+	for _, stmt := range strings.Split(content, ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
+
+// splitTopLevel splits an ALTER TABLE body on commas outside parentheses and quotes.
+func splitTopLevel(body string) []string {
+	var (
+		parts []string
+		depth int
+		quote rune
+		start int
+	)
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(body[start:]))
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain"
+	"backend-go/pkg/database"
+)
+
+// writeSchemaMigration 在临时目录写入一个 up/down 迁移
+func writeSchemaMigration(t *testing.T, version, upSQL string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		version + "_schema_change.up.sql":   upSQL,
+		version + "_schema_change.down.sql": "SELECT 1;",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func newSchemaCheckService(t *testing.T, repo MigrationRepository) (*MigrationService, func() []*logrus.Entry) {
+	t.Helper()
+	service, hook := newTestMigrationService(repo)
+	service.db = &database.DB{DB: newSimulationTestDB(t)}
+	return service, hook.AllEntries
+}
+
+func TestMigrationService_CheckSchemaConsistency_DetectsConflictingColumnAdd(t *testing.T) {
+	// predictions.confidence 已由 AutoMigrate 创建，手动迁移再次添加会失败
+	dir := writeSchemaMigration(t, "20251014000003", `
+-- 为预测表添加信心倍数
+ALTER TABLE predictions
+ADD COLUMN confidence TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '信心倍数（1-3）',
+ADD COLUMN risk_level INT DEFAULT 0,
+ADD INDEX idx_predictions_confidence (confidence);
+
+ALTER TABLE matches DROP COLUMN legacy_round;
+CREATE TABLE IF NOT EXISTS users (id INT);
+CREATE TABLE matches (id INT);
+`)
+	service, _ := newSchemaCheckService(t, &fakeMigrationRepository{})
+
+	conflicts, err := service.CheckSchemaConsistency(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("CheckSchemaConsistency() error = %v", err)
+	}
+
+	var got []string
+	for _, conflict := range conflicts {
+		got = append(got, conflict.String())
+	}
+	want := []string{
+		"20251014000003: predictions.confidence (column already exists)",
+		"20251014000003: matches.legacy_round (column to drop does not exist)",
+		"20251014000003: matches (table already exists)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("conflicts =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMigrationService_CheckSchemaConsistency_IgnoresAppliedMigrations(t *testing.T) {
+	dir := writeSchemaMigration(t, "20251014000003", "ALTER TABLE predictions ADD COLUMN confidence INT;")
+	repo := &fakeMigrationRepository{applied: []domain.Migration{{Version: "20251014000003"}}}
+	service, _ := newSchemaCheckService(t, repo)
+
+	conflicts, err := service.CheckSchemaConsistency(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("CheckSchemaConsistency() error = %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v, 已执行的迁移不应参与检查", conflicts)
+	}
+}
+
+func TestMigrationService_CheckSchemaConsistency_DetectsMissingTable(t *testing.T) {
+	// 未执行 AutoMigrate 的空库中基础表不存在，同一批迁移中新建的表不算冲突
+	dir := writeSchemaMigration(t, "20250902000002", `
+ALTER TABLE tournaments ADD COLUMN region VARCHAR(32);
+CREATE TABLE audit_trail (id INT);
+ALTER TABLE audit_trail ADD COLUMN actor VARCHAR(64);
+`)
+	service, _ := newSchemaCheckService(t, &fakeMigrationRepository{})
+
+	conflicts, err := service.CheckSchemaConsistency(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("CheckSchemaConsistency() error = %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].String() != "20250902000002: tournaments (table does not exist)" {
+		t.Errorf("conflicts = %v, want only the missing tournaments table", conflicts)
+	}
+}
+
+func TestMigrationService_RunUpMigrations_SkipAutoMigrate(t *testing.T) {
+	dir := writeSchemaMigration(t, "20251014000003", "ALTER TABLE predictions ADD COLUMN risk_level INT;")
+	repo := &fakeMigrationRepository{}
+	service, _ := newSchemaCheckService(t, repo)
+	service.SetSkipAutoMigrate(true)
+
+	if err := service.RunUpMigrations(context.Background(), dir); err != nil {
+		t.Fatalf("RunUpMigrations() error = %v", err)
+	}
+
+	// 跳过 AutoMigrate 时不会创建 auto_ 迁移记录，只执行手动迁移
+	if got := strings.Join(repo.created, ","); got != "20251014000003" {
+		t.Errorf("executed = %s, want only the manual migration", got)
+	}
+}
+
+func TestMigrationService_RunUpMigrations_SkipAutoMigrateRejectsConflicts(t *testing.T) {
+	dir := writeSchemaMigration(t, "20251014000003", "ALTER TABLE predictions ADD COLUMN confidence INT;")
+	repo := &fakeMigrationRepository{}
+	service, entries := newSchemaCheckService(t, repo)
+	service.SetSkipAutoMigrate(true)
+
+	err := service.RunUpMigrations(context.Background(), dir)
+	if err == nil || !strings.Contains(err.Error(), "predictions.confidence") {
+		t.Fatalf("RunUpMigrations() error = %v, want schema conflict", err)
+	}
+	if len(repo.created) != 0 {
+		t.Errorf("executed = %v, 存在冲突时不应执行任何迁移", repo.created)
+	}
+
+	warned := false
+	for _, entry := range entries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "predictions.confidence") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Error("应对与现有结构冲突的迁移记录警告")
+	}
+}
@@ -54,6 +54,11 @@ type MigrationService struct {
 	db         *database.DB
 	repository MigrationRepository
 	logger     *logrus.Logger
+
+	// requireDownMigrations rejects running up migrations when any pending version lacks a down file.
+	requireDownMigrations bool
+	// skipAutoMigrate makes RunUpMigrations apply manual migrations only.
+	skipAutoMigrate bool
 }
 
 // NewMigrationService creates a new migration service instance.
@@ -65,6 +70,18 @@ func NewMigrationService(db *database.DB, repository MigrationRepository) *Migra
 	}
 }
 
+// SetRequireDownMigrations enables strict mode, in which RunMigrations refuses to
+// execute anything while a pending migration has no matching down file.
+func (s *MigrationService) SetRequireDownMigrations(require bool) {
+	s.requireDownMigrations = require
+}
+
+// SetSkipAutoMigrate disables GORM auto-migration in RunUpMigrations so that the
+// schema is managed by manual migrations only.
+func (s *MigrationService) SetSkipAutoMigrate(skip bool) {
+	s.skipAutoMigrate = skip
+}
+
 // MigrationFile represents a migration file.
 type MigrationFile struct {
 	Version   string
@@ -90,7 +107,7 @@ func (s *MigrationService) InitializeMigrationSystem(ctx context.Context) error
 
 	// Clear stale locks (older than 1 hour)
 	if err := s.repository.ClearStaleLocks(ctx, time.Hour); err != nil {
-		s.logger.Warn("Failed to clear stale migration locks: %v", err)
+		s.logger.Warnf("Failed to clear stale migration locks: %v", err)
 	}
 
 	s.logger.Info("Migration system initialized successfully")
@@ -104,6 +121,8 @@ func (s *MigrationService) AutoMigrate(ctx context.Context) error {
 	// Define all models that need to be migrated
 	models := []interface{}{
 		&user.User{},
+		&user.NotificationPreferences{},
+		&user.APIKey{},
 		&domain.Match{},
 		&domain.Prediction{},
 		&domain.PredictionModification{},
@@ -139,16 +158,45 @@ func (s *MigrationService) AutoMigrate(ctx context.Context) error {
 	// Mark as completed
 	migration.MarkAsCompleted(time.Since(start))
 	if err := s.repository.SaveMigration(ctx, migration); err != nil {
-		s.logger.Warn("Failed to update auto-migration record: %v", err)
+		s.logger.Warnf("Failed to update auto-migration record: %v", err)
+	}
+
+	s.logger.Infof("GORM auto-migration completed successfully in %v", time.Since(start))
+	return nil
+}
+
+// RunUpMigrations runs GORM auto-migration unless skipped, warns about pending manual
+// migrations that disagree with the resulting schema, and then executes them.
+//
+// With auto-migration skipped nothing else builds the base tables, so any conflict
+// aborts the run before a manual migration is applied to a half-built schema.
+func (s *MigrationService) RunUpMigrations(ctx context.Context, migrationsDir string) error {
+	if s.skipAutoMigrate {
+		s.logger.Info("Skipping GORM auto-migration, applying manual migrations only")
+	} else if err := s.AutoMigrate(ctx); err != nil {
+		return fmt.Errorf("auto-migration failed: %w", err)
+	}
+
+	conflicts, err := s.CheckSchemaConsistency(ctx, migrationsDir)
+	if err != nil {
+		return fmt.Errorf("schema consistency check failed: %w", err)
+	}
+	for _, conflict := range conflicts {
+		s.logger.Warnf("Pending migration disagrees with live schema: %s", conflict)
+	}
+	if s.skipAutoMigrate && len(conflicts) > 0 {
+		return fmt.Errorf("%d pending migration statement(s) disagree with the live schema (first: %s); enable auto-migration or fix the schema before skipping it", len(conflicts), conflicts[0])
 	}
 
-	s.logger.Info("GORM auto-migration completed successfully in %v", time.Since(start))
+	if err := s.RunMigrations(ctx, migrationsDir); err != nil {
+		return fmt.Errorf("manual migrations failed: %w", err)
+	}
 	return nil
 }
 
 // RunMigrations executes all pending migrations from the migrations directory.
 func (s *MigrationService) RunMigrations(ctx context.Context, migrationsDir string) error {
-	s.logger.Info("Running manual migrations from directory: %s", migrationsDir)
+	s.logger.Infof("Running manual migrations from directory: %s", migrationsDir)
 
 	// Check for migration lock
 	locked, err := s.repository.CheckMigrationLock(ctx)
@@ -159,21 +207,62 @@ func (s *MigrationService) RunMigrations(ctx context.Context, migrationsDir stri
 		return fmt.Errorf("migration is already running")
 	}
 
-	// Load migration files
 	migrationFiles, err := s.loadMigrationFiles(migrationsDir)
 	if err != nil {
 		return fmt.Errorf("failed to load migration files: %w", err)
 	}
 
+	pendingMigrations, err := s.filterPendingMigrations(ctx, migrationFiles)
+	if err != nil {
+		return err
+	}
+
+	if len(pendingMigrations) == 0 {
+		s.logger.Info("No pending migrations to run")
+		return nil
+	}
+
+	s.logger.Infof("Found %d pending migrations", len(pendingMigrations))
+
+	// Check that every pending migration can be rolled back
+	if missingDown := findMissingDownMigrations(migrationFiles, pendingMigrations); len(missingDown) > 0 {
+		if s.requireDownMigrations {
+			return fmt.Errorf("pending migrations missing down files: %s", strings.Join(missingDown, ", "))
+		}
+		s.logger.Warnf("Pending migrations missing down files: %v", missingDown)
+	}
+
+	// Execute pending migrations
+	for _, migrationFile := range pendingMigrations {
+		if err := s.executeMigration(ctx, migrationFile); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", migrationFile.Version, err)
+		}
+	}
+
+	s.logger.Info("All migrations completed successfully")
+	return nil
+}
+
+// GetPendingMigrationFiles returns the up migrations in migrationsDir that have not been applied, ordered by version.
+func (s *MigrationService) GetPendingMigrationFiles(ctx context.Context, migrationsDir string) ([]MigrationFile, error) {
+	migrationFiles, err := s.loadMigrationFiles(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migration files: %w", err)
+	}
+
 	if len(migrationFiles) == 0 {
 		s.logger.Info("No migration files found")
-		return nil
+		return nil, nil
 	}
 
-	// Get applied migrations
+	return s.filterPendingMigrations(ctx, migrationFiles)
+}
+
+// filterPendingMigrations returns the up migrations among migrationFiles that have not been applied.
+func (s *MigrationService) filterPendingMigrations(ctx context.Context, migrationFiles []MigrationFile) ([]MigrationFile, error) {
 	appliedMigrations, err := s.repository.GetAppliedMigrations(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
 	appliedVersions := make(map[string]bool)
@@ -181,7 +270,6 @@ func (s *MigrationService) RunMigrations(ctx context.Context, migrationsDir stri
 		appliedVersions[migration.Version] = true
 	}
 
-	// Filter pending migrations
 	var pendingMigrations []MigrationFile
 	for _, file := range migrationFiles {
 		if file.Type == domain.MigrationTypeUp && !appliedVersions[file.Version] {
@@ -189,22 +277,7 @@ func (s *MigrationService) RunMigrations(ctx context.Context, migrationsDir stri
 		}
 	}
 
-	if len(pendingMigrations) == 0 {
-		s.logger.Info("No pending migrations to run")
-		return nil
-	}
-
-	s.logger.Info("Found %d pending migrations", len(pendingMigrations))
-
-	// Execute pending migrations
-	for _, migrationFile := range pendingMigrations {
-		if err := s.executeMigration(ctx, migrationFile); err != nil {
-			return fmt.Errorf("failed to execute migration %s: %w", migrationFile.Version, err)
-		}
-	}
-
-	s.logger.Info("All migrations completed successfully")
-	return nil
+	return pendingMigrations, nil
 }
 
 // RollbackMigration rolls back the last applied migration.
@@ -281,10 +354,10 @@ func (s *MigrationService) RollbackMigration(ctx context.Context, migrationsDir
 	// Mark rollback as completed
 	rollbackMigration.MarkAsCompleted(time.Since(start))
 	if err := s.repository.SaveMigration(ctx, rollbackMigration); err != nil {
-		s.logger.Warn("Failed to update rollback migration record: %v", err)
+		s.logger.Warnf("Failed to update rollback migration record: %v", err)
 	}
 
-	s.logger.Info("Migration %s rolled back successfully", lastMigration.Version)
+	s.logger.Infof("Migration %s rolled back successfully", lastMigration.Version)
 	return nil
 }
 
@@ -324,7 +397,7 @@ func (s *MigrationService) GetMigrationStatus(ctx context.Context) (map[string]i
 
 // RunSeedData executes seed data scripts.
 func (s *MigrationService) RunSeedData(ctx context.Context, seedDataDir string) error {
-	s.logger.Info("Running seed data from directory: %s", seedDataDir)
+	s.logger.Infof("Running seed data from directory: %s", seedDataDir)
 
 	// Load seed data files
 	seedFiles, err := s.loadSeedDataFiles(seedDataDir)
@@ -358,26 +431,8 @@ func (s *MigrationService) ValidateMigrations(ctx context.Context, migrationsDir
 	}
 
 	// Check for missing down migrations
-	upMigrations := make(map[string]bool)
-	downMigrations := make(map[string]bool)
-
-	for _, file := range migrationFiles {
-		if file.Type == domain.MigrationTypeUp {
-			upMigrations[file.Version] = true
-		} else {
-			downMigrations[file.Version] = true
-		}
-	}
-
-	var missingDown []string
-	for version := range upMigrations {
-		if !downMigrations[version] {
-			missingDown = append(missingDown, version)
-		}
-	}
-
-	if len(missingDown) > 0 {
-		s.logger.Warn("Missing down migrations for versions: %v", missingDown)
+	if missingDown := findMissingDownMigrations(migrationFiles, migrationFiles); len(missingDown) > 0 {
+		s.logger.Warnf("Missing down migrations for versions: %v", missingDown)
 	}
 
 	// Validate applied migrations against files
@@ -398,7 +453,7 @@ func (s *MigrationService) ValidateMigrations(ctx context.Context, migrationsDir
 			}
 		}
 		if !found {
-			s.logger.Warn("Applied migration %s not found in migration files", applied.Version)
+			s.logger.Warnf("Applied migration %s not found in migration files", applied.Version)
 		}
 	}
 
@@ -474,8 +529,27 @@ func (s *MigrationService) loadMigrationFiles(migrationsDir string) ([]Migration
 	return migrationFiles, nil
 }
 
+// findMissingDownMigrations returns the versions of the up migrations in candidates
+// that have no down migration among migrationFiles, in candidate order.
+func findMissingDownMigrations(migrationFiles, candidates []MigrationFile) []string {
+	downMigrations := make(map[string]bool)
+	for _, file := range migrationFiles {
+		if file.Type == domain.MigrationTypeDown {
+			downMigrations[file.Version] = true
+		}
+	}
+
+	var missingDown []string
+	for _, file := range candidates {
+		if file.Type == domain.MigrationTypeUp && !downMigrations[file.Version] {
+			missingDown = append(missingDown, file.Version)
+		}
+	}
+	return missingDown
+}
+
 func (s *MigrationService) executeMigration(ctx context.Context, migrationFile MigrationFile) error {
-	s.logger.Info("Executing migration: %s - %s", migrationFile.Version, migrationFile.Name)
+	s.logger.Infof("Executing migration: %s - %s", migrationFile.Version, migrationFile.Name)
 
 	// Create migration record
 	migration := &domain.Migration{
@@ -509,10 +583,10 @@ func (s *MigrationService) executeMigration(ctx context.Context, migrationFile M
 	// Mark as completed
 	migration.MarkAsCompleted(time.Since(start))
 	if err := s.repository.SaveMigration(ctx, migration); err != nil {
-		s.logger.Warn("Failed to update migration record: %v", err)
+		s.logger.Warnf("Failed to update migration record: %v", err)
 	}
 
-	s.logger.Info("Migration %s completed in %v", migrationFile.Version, time.Since(start))
+	s.logger.Infof("Migration %s completed in %v", migrationFile.Version, time.Since(start))
 	return nil
 }
 
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/gorm"
+
+	"backend-go/internal/core/domain"
+)
+
+// fakeMigrationRepository 记录已创建的迁移，不执行 SQL
+type fakeMigrationRepository struct {
+	MigrationRepository
+	applied []domain.Migration
+	created []string
+}
+
+func (r *fakeMigrationRepository) CheckMigrationLock(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeMigrationRepository) GetAppliedMigrations(ctx context.Context) ([]domain.Migration, error) {
+	return r.applied, nil
+}
+
+func (r *fakeMigrationRepository) CreateMigration(ctx context.Context, migration *domain.Migration) error {
+	r.created = append(r.created, migration.Version)
+	return nil
+}
+
+func (r *fakeMigrationRepository) SaveMigration(ctx context.Context, migration *domain.Migration) error {
+	return nil
+}
+
+func (r *fakeMigrationRepository) ExecuteInTransaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	return nil
+}
+
+// writeMigrationFiles 在临时目录写入迁移文件：001 有 down，002 与 003 缺少 down
+func writeMigrationFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{
+		"001_create_users.up.sql",
+		"001_create_users.down.sql",
+		"002_add_index.up.sql",
+		"003_add_column.up.sql",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func newTestMigrationService(repo MigrationRepository) (*MigrationService, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	return &MigrationService{repository: repo, logger: logger}, hook
+}
+
+func TestMigrationService_RunMigrations_StrictRejectsMissingDown(t *testing.T) {
+	repo := &fakeMigrationRepository{}
+	service, _ := newTestMigrationService(repo)
+	service.SetRequireDownMigrations(true)
+
+	err := service.RunMigrations(context.Background(), writeMigrationFiles(t))
+	if err == nil {
+		t.Fatal("RunMigrations() error = nil, want missing down files error")
+	}
+	if !strings.Contains(err.Error(), "002, 003") {
+		t.Errorf("error = %q, want versions 002, 003 listed", err)
+	}
+	if len(repo.created) != 0 {
+		t.Errorf("严格模式下不应执行任何迁移，实际执行了 %v", repo.created)
+	}
+}
+
+func TestMigrationService_RunMigrations_StrictIgnoresAppliedVersions(t *testing.T) {
+	// 已执行的 002、003 不再阻止后续迁移
+	repo := &fakeMigrationRepository{applied: []domain.Migration{{Version: "002"}, {Version: "003"}}}
+	service, _ := newTestMigrationService(repo)
+	service.SetRequireDownMigrations(true)
+
+	if err := service.RunMigrations(context.Background(), writeMigrationFiles(t)); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if len(repo.created) != 1 || repo.created[0] != "001" {
+		t.Errorf("executed = %v, want [001]", repo.created)
+	}
+}
+
+func TestMigrationService_RunMigrations_LenientWarnsAndProceeds(t *testing.T) {
+	repo := &fakeMigrationRepository{}
+	service, hook := newTestMigrationService(repo)
+
+	if err := service.RunMigrations(context.Background(), writeMigrationFiles(t)); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if got := strings.Join(repo.created, ","); got != "001,002,003" {
+		t.Errorf("executed = %s, want 001,002,003", got)
+	}
+
+	warned := false
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "[002 003]") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Error("宽松模式应记录缺少 down 文件的警告")
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/redis"
+	"backend-go/pkg/response"
+)
+
+// memoryDailyCounter 内存 Redis 计数器，记录每个键的过期时间
+type memoryDailyCounter struct {
+	redis.CacheService
+	counts map[string]int64
+	ttls   map[string]time.Duration
+}
+
+func newMemoryDailyCounter() *memoryDailyCounter {
+	return &memoryDailyCounter{counts: map[string]int64{}, ttls: map[string]time.Duration{}}
+}
+
+func (c *memoryDailyCounter) Increment(ctx context.Context, key string) (int64, error) {
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func (c *memoryDailyCounter) Decrement(ctx context.Context, key string) (int64, error) {
+	c.counts[key]--
+	return c.counts[key], nil
+}
+
+func (c *memoryDailyCounter) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	c.ttls[key] = expiration
+	return nil
+}
+
+func (c *memoryDailyCounter) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func newDailyLimitTestService(limit int, now *time.Time) (*PredictionService, *creatingPredictionRepo, *memoryDailyCounter) {
+	upcoming := match.Match{ID: 1, Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(2 * time.Hour)}
+	predictionRepo := &creatingPredictionRepo{}
+	userRepo := &fakeUserRepo{users: map[uint]*user.User{
+		1: {ID: 1, Role: user.UserRoleUser},
+		2: {ID: 2, Role: user.UserRoleAdmin},
+	}}
+	counter := newMemoryDailyCounter()
+
+	service := NewPredictionService(predictionRepo, nil, &fixedMatchRepo{match: upcoming}, userRepo, nil, nil, counter,
+		PredictionServiceConfig{DailyLimit: limit}, nil).(*PredictionService)
+	service.now = func() time.Time { return *now }
+	return service, predictionRepo, counter
+}
+
+func createTestPrediction(service *PredictionService, userID uint) error {
+	_, err := service.CreatePrediction(context.Background(), userID, &prediction.CreatePredictionRequest{
+		MatchID: 1, PredictedWinner: "A", PredictedScoreA: 2, PredictedScoreB: 1,
+	})
+	return err
+}
+
+func TestPredictionService_DailyLimit(t *testing.T) {
+	now := time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC)
+	service, predictionRepo, counter := newDailyLimitTestService(3, &now)
+
+	for i := 0; i < 3; i++ {
+		if err := createTestPrediction(service, 1); err != nil {
+			t.Fatalf("第 %d 次预测 err = %v, want nil", i+1, err)
+		}
+	}
+
+	err := createTestPrediction(service, 1)
+	appErr, ok := err.(*response.AppError)
+	if !ok || appErr.StatusCode != 429 || appErr.Code != response.CodePredictionLimit {
+		t.Fatalf("超出上限 err = %v, want 429 %s", err, response.CodePredictionLimit)
+	}
+	details, _ := appErr.Details.(map[string]interface{})
+	if resetAt, _ := details["reset_at"].(time.Time); !resetAt.Equal(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("details reset_at = %v, want 次日零点", details["reset_at"])
+	}
+	if len(predictionRepo.created) != 3 {
+		t.Errorf("创建了 %d 条预测, want 3", len(predictionRepo.created))
+	}
+
+	key := redis.DailyPredictionCountKey(1, "2025-06-01")
+	if counter.counts[key] != 3 {
+		t.Errorf("计数 = %d, want 3（被拒绝的请求不占用额度）", counter.counts[key])
+	}
+	if counter.ttls[key] != dailyPredictionCountTTL {
+		t.Errorf("计数过期时间 = %v, want %v", counter.ttls[key], dailyPredictionCountTTL)
+	}
+
+	// 次日重新计数
+	now = now.Add(time.Hour)
+	if err := createTestPrediction(service, 1); err != nil {
+		t.Fatalf("次日预测 err = %v, want nil", err)
+	}
+	if got := counter.counts[redis.DailyPredictionCountKey(1, "2025-06-02")]; got != 1 {
+		t.Errorf("次日计数 = %d, want 1", got)
+	}
+}
+
+func TestPredictionService_DailyLimitExemptions(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// 管理员不受限制
+	service, _, _ := newDailyLimitTestService(1, &now)
+	for i := 0; i < 3; i++ {
+		if err := createTestPrediction(service, 2); err != nil {
+			t.Fatalf("管理员第 %d 次预测 err = %v, want nil", i+1, err)
+		}
+	}
+
+	// 上限为 0 时不限制也不计数
+	service, _, counter := newDailyLimitTestService(0, &now)
+	for i := 0; i < 3; i++ {
+		if err := createTestPrediction(service, 1); err != nil {
+			t.Fatalf("不限制时第 %d 次预测 err = %v, want nil", i+1, err)
+		}
+	}
+	if len(counter.counts) != 0 {
+		t.Errorf("不限制时不应计数: %v", counter.counts)
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend-go/internal/adapters/persistence/mysql"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/pkg/response"
+)
+
+func TestPredictionService_ExportMatchPredictions(t *testing.T) {
+	db := newSimulationTestDB(t)
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	finished := domain.Match{TeamA: "T1", TeamB: "GEN", Status: domain.MatchStatusFinished, StartTime: createdAt.Add(2 * time.Hour), Winner: "A"}
+	other := domain.Match{TeamA: "JDG", TeamB: "BLG", Status: domain.MatchStatusFinished, StartTime: createdAt.Add(4 * time.Hour)}
+	for _, m := range []*domain.Match{&finished, &other} {
+		if err := db.Create(m).Error; err != nil {
+			t.Fatalf("创建比赛失败: %v", err)
+		}
+	}
+
+	// 5 条预测跨越 3 批（每批 2 条），另一场比赛的预测不应导出
+	var seeded []prediction.Prediction
+	for i := 0; i < 5; i++ {
+		winner, points := "A", 3
+		if i%2 == 1 {
+			winner, points = "B", 0
+		}
+		seeded = append(seeded, prediction.Prediction{
+			UserID:          uint(100 + i),
+			MatchID:         finished.ID,
+			PredictedWinner: winner,
+			Confidence:      i%3 + 1,
+			IsCorrect:       winner == "A",
+			EarnedPoints:    points,
+			CreatedAt:       createdAt.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	seeded = append(seeded, prediction.Prediction{UserID: 200, MatchID: other.ID, PredictedWinner: "A", Confidence: 1, CreatedAt: createdAt})
+	if err := db.Create(&seeded).Error; err != nil {
+		t.Fatalf("创建预测失败: %v", err)
+	}
+
+	service := NewPredictionService(mysql.NewPredictionRepository(db), nil, mysql.NewMatchRepository(db), nil, nil, nil, nil,
+		PredictionServiceConfig{ExportBatchSize: 2}, nil)
+
+	var buf bytes.Buffer
+	if err := service.ExportMatchPredictions(ctx, finished.ID, &buf); err != nil {
+		t.Fatalf("ExportMatchPredictions() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("解析 CSV 失败: %v", err)
+	}
+	if len(records) != 6 {
+		t.Fatalf("行数 = %d, want 表头 + 5 条预测", len(records))
+	}
+	want := []string{"user_id", "predicted_winner", "confidence", "is_correct", "earned_points", "created_at"}
+	for i, column := range want {
+		if records[0][i] != column {
+			t.Errorf("表头 = %v, want %v", records[0], want)
+			break
+		}
+	}
+	for i, row := range records[1:] {
+		p := seeded[i]
+		expected := []string{
+			strconv.Itoa(int(p.UserID)),
+			p.PredictedWinner,
+			strconv.Itoa(p.Confidence),
+			strconv.FormatBool(p.IsCorrect),
+			strconv.Itoa(p.EarnedPoints),
+			p.CreatedAt.Format(time.RFC3339),
+		}
+		for j := range expected {
+			if row[j] != expected[j] {
+				t.Errorf("第 %d 行 = %v, want %v", i+1, row, expected)
+				break
+			}
+		}
+	}
+}
+
+func TestPredictionService_ExportRejectsUnfinishedMatch(t *testing.T) {
+	service := NewPredictionService(nil, nil, &upcomingMatchRepo{}, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+
+	var buf bytes.Buffer
+	err := service.ExportMatchPredictions(context.Background(), 1, &buf)
+	if appErr, ok := err.(*response.AppError); !ok || appErr.StatusCode != 400 {
+		t.Fatalf("err = %v, want 400 AppError", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("未结束的比赛不应写出内容: %q", buf.String())
+	}
+}
@@ -2,15 +2,39 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
 
 	"backend-go/internal/core/domain/match"
 	"backend-go/internal/core/domain/prediction"
 	"backend-go/internal/core/domain/shared"
 	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/redis"
 	"backend-go/pkg/response"
 )
 
+// PredictionServiceConfig 预测服务配置
+type PredictionServiceConfig struct {
+	BlindPrediction   bool          // 比赛开始前隐藏预测共识
+	ConsensusCacheTTL time.Duration // 预测共识缓存时间
+	ExportBatchSize   int           // 导出预测时每批读取的条数，默认 500
+	DailyLimit        int           // 每个用户每天（UTC）最多创建的预测数，0 表示不限制，管理员不受限制
+}
+
+// dailyPredictionCountTTL 每日预测计数的过期时间，计数键按日期区分，过期只用于清理
+const dailyPredictionCountTTL = 24 * time.Hour
+
+// defaultExportBatchSize 导出预测时默认每批读取的条数
+const defaultExportBatchSize = 500
+
+// predictionExportHeader 预测导出 CSV 的表头
+var predictionExportHeader = []string{"user_id", "predicted_winner", "confidence", "is_correct", "earned_points", "created_at"}
+
 // PredictionService 预测服务实现
 type PredictionService struct {
 	predictionRepo  prediction.Repository
@@ -19,6 +43,10 @@ type PredictionService struct {
 	userRepo        user.Repository
 	scoringRuleRepo prediction.ScoringRuleRepository
 	eventBus        shared.EventBus
+	cache           redis.CacheService
+	config          PredictionServiceConfig
+	logger          *logrus.Logger
+	now             func() time.Time
 }
 
 // NewPredictionService 创建预测服务
@@ -29,7 +57,20 @@ func NewPredictionService(
 	userRepo user.Repository,
 	scoringRuleRepo prediction.ScoringRuleRepository,
 	eventBus shared.EventBus,
+	cache redis.CacheService,
+	config PredictionServiceConfig,
+	logger *logrus.Logger,
 ) prediction.Service {
+	if config.ConsensusCacheTTL <= 0 {
+		config.ConsensusCacheTTL = redis.ExpirationMatchData
+	}
+	if config.ExportBatchSize <= 0 {
+		config.ExportBatchSize = defaultExportBatchSize
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
 	return &PredictionService{
 		predictionRepo:  predictionRepo,
 		voteRepo:        voteRepo,
@@ -37,6 +78,10 @@ func NewPredictionService(
 		userRepo:        userRepo,
 		scoringRuleRepo: scoringRuleRepo,
 		eventBus:        eventBus,
+		cache:           cache,
+		config:          config,
+		logger:          logger,
+		now:             time.Now,
 	}
 }
 
@@ -49,8 +94,8 @@ func (s *PredictionService) CreatePrediction(ctx context.Context, userID uint, r
 	}
 
 	// 检查是否可以预测
-	if !matchEntity.CanAcceptPredictions() {
-		return nil, response.NewMatchStartedError(req.MatchID)
+	if err := checkPredictionWindow(matchEntity); err != nil {
+		return nil, err
 	}
 
 	// 检查用户是否已经有预测
@@ -59,6 +104,12 @@ func (s *PredictionService) CreatePrediction(ctx context.Context, userID uint, r
 		return nil, response.NewPredictionExistsError(userID, req.MatchID)
 	}
 
+	// 检查每日预测上限
+	countKey, err := s.reserveDailyPrediction(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建预测
 	pred := &prediction.Prediction{
 		UserID:          userID,
@@ -66,12 +117,27 @@ func (s *PredictionService) CreatePrediction(ctx context.Context, userID uint, r
 		PredictedWinner: string(req.PredictedWinner),
 		PredictedScoreA: req.PredictedScoreA,
 		PredictedScoreB: req.PredictedScoreB,
+		Confidence:      prediction.MinConfidence,
+		Version:         1, // MySQL 下 GORM 不回读列默认值，显式设置以便响应返回正确的版本号
+	}
+	if req.Confidence != nil {
+		pred.Confidence = *req.Confidence
 	}
 
 	if err := s.predictionRepo.CreatePrediction(ctx, pred); err != nil {
+		s.releaseDailyPrediction(ctx, countKey)
+		// 并发提交时由唯一索引兜底
+		if response.IsErrorCode(err, response.CodeConflict) {
+			return nil, response.NewPredictionExistsError(userID, req.MatchID)
+		}
+		if appErr, ok := err.(*response.AppError); ok {
+			return nil, appErr
+		}
 		return nil, fmt.Errorf("failed to create prediction: %w", err)
 	}
 
+	s.invalidateConsensus(ctx, req.MatchID)
+
 	// 加载关联数据
 	pred.Match = matchEntity
 	return pred, nil
@@ -96,24 +162,52 @@ func (s *PredictionService) UpdatePrediction(ctx context.Context, userID uint, p
 		return nil, fmt.Errorf("failed to get match: %w", err)
 	}
 
-	if !matchEntity.CanAcceptPredictions() {
-		return nil, response.NewMatchStartedError(pred.MatchID)
+	if err := checkPredictionWindow(matchEntity); err != nil {
+		return nil, err
+	}
+
+	// 乐观锁：客户端持有的版本已过期说明预测已被其他请求修改
+	if req.Version != nil && *req.Version != pred.Version {
+		return nil, response.NewConflictError("预测已被修改，请刷新后重试", map[string]int{
+			"expected_version": *req.Version,
+			"current_version":  pred.Version,
+		})
 	}
 
 	// 更新预测
 	pred.PredictedWinner = string(req.PredictedWinner)
 	pred.PredictedScoreA = req.PredictedScoreA
 	pred.PredictedScoreB = req.PredictedScoreB
+	if req.Confidence != nil {
+		pred.Confidence = *req.Confidence
+	}
 	pred.IncrementModificationCount()
 
 	if err := s.predictionRepo.UpdatePrediction(ctx, pred); err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			return nil, appErr
+		}
 		return nil, fmt.Errorf("failed to update prediction: %w", err)
 	}
 
+	s.invalidateConsensus(ctx, pred.MatchID)
+
 	pred.Match = matchEntity
 	return pred, nil
 }
 
+// checkPredictionWindow 检查比赛是否仍在预测窗口内：已开赛返回比赛已开始，
+// 未开赛但已过锁定时间（单场或全局窗口）返回预测已锁定并附带生效的锁定时间
+func checkPredictionWindow(matchEntity *match.Match) *response.AppError {
+	if matchEntity.CanAcceptPredictions() {
+		return nil
+	}
+	if matchEntity.IsUpcoming() && time.Now().Before(matchEntity.StartTime) {
+		return response.NewPredictionLockedError(matchEntity.ID, matchEntity.PredictionLockTime())
+	}
+	return response.NewMatchStartedError(matchEntity.ID)
+}
+
 // GetPrediction 获取预测详情
 func (s *PredictionService) GetPrediction(ctx context.Context, id uint) (*prediction.Prediction, error) {
 	pred, err := s.predictionRepo.GetPredictionByID(ctx, id)
@@ -166,6 +260,12 @@ func (s *PredictionService) VotePrediction(ctx context.Context, userID uint, pre
 	// 创建投票并更新计数（事务性操作）
 	vote := prediction.NewVote(userID, predictionID)
 	if err := s.voteRepo.CreateVoteWithCount(ctx, vote); err != nil {
+		if response.IsErrorCode(err, response.CodeConflict) {
+			return response.NewVoteExistsError(userID, predictionID)
+		}
+		if appErr, ok := err.(*response.AppError); ok {
+			return appErr
+		}
 		return fmt.Errorf("failed to create vote with count: %w", err)
 	}
 
@@ -173,7 +273,7 @@ func (s *PredictionService) VotePrediction(ctx context.Context, userID uint, pre
 	updatedPred, err := s.predictionRepo.GetPredictionByID(ctx, predictionID)
 	if err != nil {
 		// 记录错误但不影响投票操作
-		fmt.Printf("Warning: failed to get updated prediction for event: %v", err)
+		s.logger.WithError(err).WithField("prediction_id", predictionID).Warn("Failed to get updated prediction for vote event")
 	} else {
 		// 发布投票事件
 		if s.eventBus != nil {
@@ -184,7 +284,7 @@ func (s *PredictionService) VotePrediction(ctx context.Context, userID uint, pre
 			})
 
 			if err := s.eventBus.Publish(event); err != nil {
-				fmt.Printf("Warning: failed to publish vote event: %v", err)
+				s.logger.WithError(err).WithField("prediction_id", predictionID).Warn("Failed to publish vote event")
 			}
 		}
 	}
@@ -192,6 +292,103 @@ func (s *PredictionService) VotePrediction(ctx context.Context, userID uint, pre
 	return nil
 }
 
+// CastVotes 批量投票
+//
+// 每条投票单独校验（预测存在、不给自己投票、未重复投票、比赛未开始），
+// 有效投票在同一事务中写入并按预测汇总更新票数，无效投票在结果中标明原因。
+func (s *PredictionService) CastVotes(ctx context.Context, userID uint, votes []prediction.VoteRequest) (*prediction.BatchVoteResult, error) {
+	if len(votes) == 0 {
+		return nil, response.NewBadRequestError("投票列表不能为空", nil)
+	}
+	if len(votes) > prediction.MaxBatchVotes {
+		return nil, response.NewBadRequestError(fmt.Sprintf("单次最多投票 %d 条", prediction.MaxBatchVotes), nil)
+	}
+
+	result := &prediction.BatchVoteResult{Results: make([]prediction.VoteOutcome, len(votes))}
+	seen := make(map[uint]bool, len(votes))
+	var accepted []*prediction.Vote
+	acceptedIndex := make(map[uint]int)
+
+	for i, req := range votes {
+		outcome := prediction.VoteOutcome{PredictionID: req.PredictionID}
+		status, pred, err := s.validateVote(ctx, userID, req.PredictionID, seen)
+		if err != nil {
+			return nil, err
+		}
+		seen[req.PredictionID] = true
+
+		outcome.Status = status
+		if status == prediction.VoteStatusAccepted {
+			accepted = append(accepted, prediction.NewVote(userID, req.PredictionID))
+			acceptedIndex[req.PredictionID] = i
+			outcome.VoteCount = pred.VoteCount + 1
+			result.Accepted++
+		} else {
+			result.Rejected++
+		}
+		result.Results[i] = outcome
+	}
+
+	if len(accepted) == 0 {
+		return result, nil
+	}
+	if err := s.voteRepo.CreateVotesWithCounts(ctx, accepted); err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			return nil, appErr
+		}
+		return nil, fmt.Errorf("failed to create votes with counts: %w", err)
+	}
+
+	// 发布投票事件
+	if s.eventBus != nil {
+		for _, vote := range accepted {
+			event := shared.NewEvent(shared.EventPredictionVoted, &shared.PredictionVotedPayload{
+				PredictionID: vote.PredictionID,
+				UserID:       userID,
+				VoteCount:    result.Results[acceptedIndex[vote.PredictionID]].VoteCount,
+			})
+			if err := s.eventBus.Publish(event); err != nil {
+				s.logger.WithError(err).WithField("prediction_id", vote.PredictionID).Warn("Failed to publish vote event")
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// validateVote 校验批量投票中的单条投票，seen 为本批次已处理的预测
+func (s *PredictionService) validateVote(ctx context.Context, userID, predictionID uint, seen map[uint]bool) (prediction.VoteStatus, *prediction.Prediction, error) {
+	if seen[predictionID] {
+		return prediction.VoteStatusDuplicate, nil, nil
+	}
+
+	pred, err := s.predictionRepo.GetPredictionByID(ctx, predictionID)
+	if err != nil {
+		if appErr, ok := err.(*response.AppError); ok && appErr.Code == response.CodeNotFound {
+			return prediction.VoteStatusNotFound, nil, nil
+		}
+		return "", nil, fmt.Errorf("failed to get prediction: %w", err)
+	}
+
+	switch prediction.CanVote(userID, pred) {
+	case nil:
+	case prediction.ErrCannotVoteOwnPrediction:
+		return prediction.VoteStatusSelfVote, nil, nil
+	default:
+		return prediction.VoteStatusMatchStarted, nil, nil
+	}
+
+	exists, err := s.voteRepo.ExistsVote(ctx, userID, predictionID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to check vote existence: %w", err)
+	}
+	if exists {
+		return prediction.VoteStatusDuplicate, nil, nil
+	}
+
+	return prediction.VoteStatusAccepted, pred, nil
+}
+
 // UnvotePrediction 取消投票
 func (s *PredictionService) UnvotePrediction(ctx context.Context, userID uint, predictionID uint) error {
 	// 检查投票是否存在
@@ -212,7 +409,7 @@ func (s *PredictionService) UnvotePrediction(ctx context.Context, userID uint, p
 	updatedPred, err := s.predictionRepo.GetPredictionByID(ctx, predictionID)
 	if err != nil {
 		// 记录错误但不影响取消投票操作
-		fmt.Printf("Warning: failed to get updated prediction for unvote event: %v", err)
+		s.logger.WithError(err).WithField("prediction_id", predictionID).Warn("Failed to get updated prediction for unvote event")
 	} else {
 		// 发布取消投票事件
 		if s.eventBus != nil {
@@ -223,7 +420,7 @@ func (s *PredictionService) UnvotePrediction(ctx context.Context, userID uint, p
 			})
 
 			if err := s.eventBus.Publish(event); err != nil {
-				fmt.Printf("Warning: failed to publish unvote event: %v", err)
+				s.logger.WithError(err).WithField("prediction_id", predictionID).Warn("Failed to publish unvote event")
 			}
 		}
 	}
@@ -322,3 +519,143 @@ func (s *PredictionService) UpdateFeaturedPredictions(ctx context.Context) error
 	// 暂时返回成功
 	return nil
 }
+
+// GetMatchConsensus 获取比赛的社区预测共识
+func (s *PredictionService) GetMatchConsensus(ctx context.Context, matchID uint) (*prediction.MatchConsensus, error) {
+	matchEntity, err := s.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+
+	// 盲预测模式下比赛开始前不公开共识
+	if !prediction.IsConsensusVisible(s.config.BlindPrediction, matchEntity) {
+		return prediction.HiddenMatchConsensus(matchID), nil
+	}
+
+	key := redis.ConsensusKey(matchID)
+	if s.cache != nil {
+		var cached prediction.MatchConsensus
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	buckets, err := s.predictionRepo.GetConsensusBuckets(ctx, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consensus buckets: %w", err)
+	}
+
+	consensus := prediction.NewMatchConsensus(matchID, buckets)
+	if s.cache != nil {
+		if err := s.cache.SetJSON(ctx, key, consensus, s.config.ConsensusCacheTTL); err != nil {
+			s.logger.WithError(err).WithField("match_id", matchID).Warn("Failed to cache match consensus")
+		} else if err := s.cache.TagKey(ctx, key, s.config.ConsensusCacheTTL, redis.MatchTag(matchID)); err != nil {
+			s.logger.WithError(err).WithField("match_id", matchID).Warn("Failed to tag match consensus")
+		}
+	}
+
+	return consensus, nil
+}
+
+// ExportMatchPredictions 导出比赛预测
+//
+// 按 ID 分批读取并逐批写出，内存占用与预测总数无关。比赛未结束时不导出，避免提前泄露预测分布。
+func (s *PredictionService) ExportMatchPredictions(ctx context.Context, matchID uint, w io.Writer) error {
+	matchEntity, err := s.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return fmt.Errorf("failed to get match: %w", err)
+	}
+	if !matchEntity.IsFinished() {
+		return response.NewBadRequestError("Match is not finished", map[string]interface{}{"match_id": matchID})
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(predictionExportHeader); err != nil {
+		return err
+	}
+
+	var afterID uint
+	for {
+		batch, err := s.predictionRepo.GetPredictionsByMatchAfter(ctx, matchID, afterID, s.config.ExportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, p := range batch {
+			if err := writer.Write([]string{
+				strconv.FormatUint(uint64(p.UserID), 10),
+				p.PredictedWinner,
+				strconv.Itoa(p.Confidence),
+				strconv.FormatBool(p.IsCorrect),
+				strconv.Itoa(p.EarnedPoints),
+				p.CreatedAt.UTC().Format(time.RFC3339),
+			}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		if len(batch) < s.config.ExportBatchSize {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// invalidateConsensus 失效比赛共识缓存
+func (s *PredictionService) invalidateConsensus(ctx context.Context, matchID uint) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Delete(ctx, redis.ConsensusKey(matchID)); err != nil {
+		s.logger.WithError(err).WithField("match_id", matchID).Warn("Failed to invalidate match consensus")
+	}
+}
+
+// reserveDailyPrediction 占用用户当天的一次预测额度，返回计数键供创建失败时归还。
+// 未配置上限或缓存不可用时不限制；缓存出错时放行，避免 Redis 故障阻断预测
+func (s *PredictionService) reserveDailyPrediction(ctx context.Context, userID uint) (string, error) {
+	if s.config.DailyLimit <= 0 || s.cache == nil {
+		return "", nil
+	}
+
+	today := s.now().UTC()
+	key := redis.DailyPredictionCountKey(userID, today.Format("2006-01-02"))
+	count, err := s.cache.Increment(ctx, key)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", userID).Warn("Failed to count daily predictions")
+		return "", nil
+	}
+	if count == 1 {
+		if err := s.cache.Expire(ctx, key, dailyPredictionCountTTL); err != nil {
+			s.logger.WithError(err).WithField("key", key).Warn("Failed to set daily prediction count expiration")
+		}
+	}
+	if count <= int64(s.config.DailyLimit) {
+		return key, nil
+	}
+
+	// 超出上限时才查询用户，管理员不受限制
+	if s.userRepo != nil {
+		if u, err := s.userRepo.GetByID(ctx, userID); err == nil && u.IsAdmin() {
+			return key, nil
+		}
+	}
+
+	// 被拒绝的请求不占用额度
+	s.releaseDailyPrediction(ctx, key)
+	resetAt := time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, time.UTC)
+	return "", response.NewDailyPredictionLimitError(s.config.DailyLimit, resetAt)
+}
+
+// releaseDailyPrediction 归还 reserveDailyPrediction 占用的额度
+func (s *PredictionService) releaseDailyPrediction(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if _, err := s.cache.Decrement(ctx, key); err != nil {
+		s.logger.WithError(err).WithField("key", key).Warn("Failed to release daily prediction count")
+	}
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/match"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/pkg/database"
+	"backend-go/pkg/response"
+)
+
+// versionedPredictionRepo 按版本号条件更新的内存预测仓储
+type versionedPredictionRepo struct {
+	prediction.Repository
+	stored prediction.Prediction
+}
+
+func (r *versionedPredictionRepo) GetPredictionByID(ctx context.Context, id uint) (*prediction.Prediction, error) {
+	pred := r.stored
+	return &pred, nil
+}
+
+func (r *versionedPredictionRepo) UpdatePrediction(ctx context.Context, pred *prediction.Prediction) error {
+	if pred.Version != r.stored.Version {
+		return response.NewConflictError("预测已被修改，请刷新后重试", nil)
+	}
+	pred.Version++
+	r.stored = *pred
+	return nil
+}
+
+// upcomingMatchRepo 始终返回未开始的比赛
+type upcomingMatchRepo struct {
+	match.Repository
+}
+
+func (r *upcomingMatchRepo) GetByID(ctx context.Context, id uint) (*match.Match, error) {
+	return &match.Match{ID: id, Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(time.Hour)}, nil
+}
+
+func newVersionedPredictionService() (prediction.Service, *versionedPredictionRepo) {
+	repo := &versionedPredictionRepo{stored: prediction.Prediction{
+		ID: 1, UserID: 7, MatchID: 3, PredictedWinner: "A", PredictedScoreA: 2, PredictedScoreB: 1, Version: 1,
+	}}
+	return NewPredictionService(repo, nil, &upcomingMatchRepo{}, nil, nil, nil, nil, PredictionServiceConfig{}, nil), repo
+}
+
+func TestUpdatePrediction_VersionIncrements(t *testing.T) {
+	service, repo := newVersionedPredictionService()
+	version := 1
+
+	pred, err := service.UpdatePrediction(context.Background(), 7, 1, &prediction.UpdatePredictionRequest{
+		PredictedWinner: "B", PredictedScoreA: 0, PredictedScoreB: 2, Version: &version,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePrediction() error = %v", err)
+	}
+	if pred.Version != 2 || repo.stored.Version != 2 {
+		t.Errorf("版本号 = %d (存储 %d), want 2", pred.Version, repo.stored.Version)
+	}
+	if repo.stored.PredictedWinner != "B" {
+		t.Errorf("PredictedWinner = %s, want B", repo.stored.PredictedWinner)
+	}
+}
+
+func TestUpdatePrediction_StaleVersionConflicts(t *testing.T) {
+	service, repo := newVersionedPredictionService()
+	repo.stored.Version = 3 // 另一个标签页已更新过两次
+	stale := 1
+
+	_, err := service.UpdatePrediction(context.Background(), 7, 1, &prediction.UpdatePredictionRequest{
+		PredictedWinner: "B", PredictedScoreA: 0, PredictedScoreB: 2, Version: &stale,
+	})
+	appErr, ok := err.(*response.AppError)
+	if !ok || appErr.StatusCode != 409 {
+		t.Fatalf("UpdatePrediction() error = %v, want 409 冲突", err)
+	}
+	if repo.stored.PredictedWinner != "A" || repo.stored.Version != 3 {
+		t.Errorf("冲突时不应写入: winner = %s, version = %d", repo.stored.PredictedWinner, repo.stored.Version)
+	}
+}
+
+// roundTripPredictionRepo 创建时按原样保存（不回读数据库默认值），更新时校验版本号
+type roundTripPredictionRepo struct {
+	versionedPredictionRepo
+}
+
+func (r *roundTripPredictionRepo) GetPredictionByUserAndMatch(ctx context.Context, userID, matchID uint) (*prediction.Prediction, error) {
+	return nil, response.NewPredictionNotFoundError(matchID)
+}
+
+func (r *roundTripPredictionRepo) CreatePrediction(ctx context.Context, pred *prediction.Prediction) error {
+	pred.ID = 1
+	r.stored = *pred
+	return nil
+}
+
+func TestCreatePrediction_VersionRoundTripsIntoUpdate(t *testing.T) {
+	setGlobalLockWindow(t, 0)
+	repo := &roundTripPredictionRepo{}
+	service := NewPredictionService(repo, nil, &upcomingMatchRepo{}, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+
+	created, err := service.CreatePrediction(context.Background(), 7, &prediction.CreatePredictionRequest{
+		MatchID: 3, PredictedWinner: "A", PredictedScoreA: 2, PredictedScoreB: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreatePrediction() error = %v", err)
+	}
+	if created.Version != 1 {
+		t.Fatalf("创建响应的版本号 = %d, want 1", created.Version)
+	}
+
+	// 客户端原样回传创建时返回的版本号
+	version := created.Version
+	updated, err := service.UpdatePrediction(context.Background(), 7, created.ID, &prediction.UpdatePredictionRequest{
+		PredictedWinner: "B", PredictedScoreA: 0, PredictedScoreB: 2, Version: &version,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePrediction() error = %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("更新后版本号 = %d, want 2", updated.Version)
+	}
+}
+
+// votingPredictionRepo 以内存预测作为投票目标
+type votingPredictionRepo struct {
+	prediction.Repository
+	predictions map[uint]*prediction.Prediction
+}
+
+func (r *votingPredictionRepo) GetPredictionByID(ctx context.Context, id uint) (*prediction.Prediction, error) {
+	pred, ok := r.predictions[id]
+	if !ok {
+		return nil, response.NewNotFoundError("预测不存在")
+	}
+	copied := *pred
+	return &copied, nil
+}
+
+// memoryVoteRepo 内存投票仓储，写入时同步更新预测票数
+type memoryVoteRepo struct {
+	prediction.VoteRepository
+	predictions map[uint]*prediction.Prediction
+	votes       map[[2]uint]bool
+	batches     int
+}
+
+func (r *memoryVoteRepo) ExistsVote(ctx context.Context, userID, predictionID uint) (bool, error) {
+	return r.votes[[2]uint{userID, predictionID}], nil
+}
+
+func (r *memoryVoteRepo) CreateVotesWithCounts(ctx context.Context, votes []*prediction.Vote) error {
+	r.batches++
+	for _, vote := range votes {
+		r.votes[[2]uint{vote.UserID, vote.PredictionID}] = true
+		r.predictions[vote.PredictionID].VoteCount++
+	}
+	return nil
+}
+
+func TestCastVotes_PerItemOutcomes(t *testing.T) {
+	upcoming := &match.Match{Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(time.Hour)}
+	predictions := map[uint]*prediction.Prediction{
+		1: {ID: 1, UserID: 8, VoteCount: 2, Match: upcoming},
+		2: {ID: 2, UserID: 7, VoteCount: 5, Match: upcoming}, // 自己的预测
+		3: {ID: 3, UserID: 9, VoteCount: 1, Match: upcoming}, // 已投过票
+		4: {ID: 4, UserID: 9, VoteCount: 0, Match: upcoming},
+	}
+	voteRepo := &memoryVoteRepo{
+		predictions: predictions,
+		votes:       map[[2]uint]bool{{7, 3}: true},
+	}
+	service := NewPredictionService(&votingPredictionRepo{predictions: predictions}, voteRepo, nil, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+
+	result, err := service.CastVotes(context.Background(), 7, []prediction.VoteRequest{
+		{PredictionID: 1},
+		{PredictionID: 2},
+		{PredictionID: 3},
+		{PredictionID: 4},
+		{PredictionID: 1},  // 批次内重复
+		{PredictionID: 99}, // 不存在
+	})
+	if err != nil {
+		t.Fatalf("CastVotes() error = %v", err)
+	}
+
+	want := []prediction.VoteStatus{
+		prediction.VoteStatusAccepted,
+		prediction.VoteStatusSelfVote,
+		prediction.VoteStatusDuplicate,
+		prediction.VoteStatusAccepted,
+		prediction.VoteStatusDuplicate,
+		prediction.VoteStatusNotFound,
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("结果条数 = %d, want %d", len(result.Results), len(want))
+	}
+	for i, status := range want {
+		if result.Results[i].Status != status {
+			t.Errorf("第 %d 条结果 = %s, want %s", i, result.Results[i].Status, status)
+		}
+	}
+	if result.Accepted != 2 || result.Rejected != 4 {
+		t.Errorf("accepted = %d, rejected = %d, want 2/4", result.Accepted, result.Rejected)
+	}
+	if result.Results[0].VoteCount != 3 || result.Results[3].VoteCount != 1 {
+		t.Errorf("投票后票数 = %d/%d, want 3/1", result.Results[0].VoteCount, result.Results[3].VoteCount)
+	}
+
+	// 只有有效投票影响票数，且在一次批量写入中完成
+	for id, count := range map[uint]int{1: 3, 2: 5, 3: 1, 4: 1} {
+		if predictions[id].VoteCount != count {
+			t.Errorf("预测 %d 票数 = %d, want %d", id, predictions[id].VoteCount, count)
+		}
+	}
+	if voteRepo.batches != 1 {
+		t.Errorf("批量写入次数 = %d, want 1", voteRepo.batches)
+	}
+}
+
+func TestCastVotes_NoValidVotesSkipsWrite(t *testing.T) {
+	predictions := map[uint]*prediction.Prediction{
+		2: {ID: 2, UserID: 7},
+	}
+	voteRepo := &memoryVoteRepo{predictions: predictions, votes: map[[2]uint]bool{}}
+	service := NewPredictionService(&votingPredictionRepo{predictions: predictions}, voteRepo, nil, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+
+	result, err := service.CastVotes(context.Background(), 7, []prediction.VoteRequest{{PredictionID: 2}})
+	if err != nil {
+		t.Fatalf("CastVotes() error = %v", err)
+	}
+	if result.Accepted != 0 || voteRepo.batches != 0 {
+		t.Errorf("无有效投票时不应写入: accepted = %d, batches = %d", result.Accepted, voteRepo.batches)
+	}
+
+	tooMany := make([]prediction.VoteRequest, prediction.MaxBatchVotes+1)
+	if _, err := service.CastVotes(context.Background(), 7, tooMany); err == nil {
+		t.Error("超过批量上限应返回错误")
+	}
+}
+
+// fixedMatchRepo 返回固定比赛
+type fixedMatchRepo struct {
+	match.Repository
+	match match.Match
+}
+
+func (r *fixedMatchRepo) GetByID(ctx context.Context, id uint) (*match.Match, error) {
+	m := r.match
+	return &m, nil
+}
+
+// creatingPredictionRepo 记录新建预测的内存仓储
+type creatingPredictionRepo struct {
+	prediction.Repository
+	created   []*prediction.Prediction
+	createErr error
+}
+
+func (r *creatingPredictionRepo) GetPredictionByUserAndMatch(ctx context.Context, userID, matchID uint) (*prediction.Prediction, error) {
+	return nil, response.NewPredictionNotFoundError(matchID)
+}
+
+func (r *creatingPredictionRepo) CreatePrediction(ctx context.Context, pred *prediction.Prediction) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.created = append(r.created, pred)
+	return nil
+}
+
+// setGlobalLockWindow 临时调整全局锁定窗口，测试结束后恢复
+func setGlobalLockWindow(t *testing.T, window time.Duration) {
+	t.Helper()
+	previous := domain.PredictionLockBeforeStart()
+	domain.SetPredictionLockBeforeStart(window)
+	t.Cleanup(func() { domain.SetPredictionLockBeforeStart(previous) })
+}
+
+// assertPredictionLocked 断言返回预测已锁定错误且详情中包含生效的锁定时间
+func assertPredictionLocked(t *testing.T, err error, wantLockAt time.Time) {
+	t.Helper()
+	appErr, ok := err.(*response.AppError)
+	if !ok || appErr.Code != response.CodePredictionLocked {
+		t.Fatalf("error = %v, want %s", err, response.CodePredictionLocked)
+	}
+	details, _ := appErr.Details.(map[string]interface{})
+	if lockAt, _ := details["lock_at"].(time.Time); !lockAt.Equal(wantLockAt) {
+		t.Errorf("details lock_at = %v, want %v", details["lock_at"], wantLockAt)
+	}
+}
+
+func TestPredictionWindow_PerMatchLockOverridesGlobal(t *testing.T) {
+	setGlobalLockWindow(t, 0)
+
+	// 全局规则下开赛前两小时仍可预测，但该场比赛已提前锁定
+	lockAt := time.Now().Add(-time.Minute)
+	final := match.Match{ID: 3, Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(2 * time.Hour), PredictionLockAt: &lockAt}
+	matchRepo := &fixedMatchRepo{match: final}
+
+	predictionRepo := &creatingPredictionRepo{}
+	service := NewPredictionService(predictionRepo, nil, matchRepo, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+	_, err := service.CreatePrediction(context.Background(), 7, &prediction.CreatePredictionRequest{
+		MatchID: 3, PredictedWinner: "A", PredictedScoreA: 2, PredictedScoreB: 1,
+	})
+	assertPredictionLocked(t, err, lockAt)
+	if len(predictionRepo.created) != 0 {
+		t.Error("锁定后不应创建预测")
+	}
+
+	versioned := &versionedPredictionRepo{stored: prediction.Prediction{ID: 1, UserID: 7, MatchID: 3, PredictedWinner: "A", Version: 1}}
+	service = NewPredictionService(versioned, nil, matchRepo, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+	_, err = service.UpdatePrediction(context.Background(), 7, 1, &prediction.UpdatePredictionRequest{
+		PredictedWinner: "B", PredictedScoreA: 0, PredictedScoreB: 2,
+	})
+	assertPredictionLocked(t, err, lockAt)
+	if versioned.stored.PredictedWinner != "A" {
+		t.Error("锁定后不应修改预测")
+	}
+}
+
+func TestPredictionWindow_GlobalLockBeforeStart(t *testing.T) {
+	setGlobalLockWindow(t, 30*time.Minute)
+
+	soon := match.Match{ID: 4, Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(10 * time.Minute)}
+	predictionRepo := &creatingPredictionRepo{}
+	service := NewPredictionService(predictionRepo, nil, &fixedMatchRepo{match: soon}, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+	_, err := service.CreatePrediction(context.Background(), 7, &prediction.CreatePredictionRequest{
+		MatchID: 4, PredictedWinner: "A", PredictedScoreA: 2, PredictedScoreB: 1,
+	})
+	assertPredictionLocked(t, err, soon.StartTime.Add(-30*time.Minute))
+
+	later := match.Match{ID: 5, Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(time.Hour)}
+	service = NewPredictionService(predictionRepo, nil, &fixedMatchRepo{match: later}, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+	if _, err := service.CreatePrediction(context.Background(), 7, &prediction.CreatePredictionRequest{
+		MatchID: 5, PredictedWinner: "A", PredictedScoreA: 2, PredictedScoreB: 1,
+	}); err != nil {
+		t.Fatalf("锁定窗口之外应允许预测: %v", err)
+	}
+	if len(predictionRepo.created) != 1 {
+		t.Errorf("created = %d, want 1", len(predictionRepo.created))
+	}
+
+	later.FillComputedFields()
+	if want := later.StartTime.Add(-30 * time.Minute); !later.EffectiveLockAt.Equal(want) {
+		t.Errorf("EffectiveLockAt = %v, want %v", later.EffectiveLockAt, want)
+	}
+}
+
+func TestCreatePrediction_DuplicateInsertReturnsPredictionExists(t *testing.T) {
+	setGlobalLockWindow(t, 0)
+
+	// 存在性检查之后、插入之前另一请求已提交同一场比赛的预测
+	upcoming := match.Match{ID: 6, Status: match.MatchStatusUpcoming, StartTime: time.Now().Add(time.Hour)}
+	duplicate := fmt.Errorf("failed to create prediction: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '7-6' for key 'predictions.idx_user_match'"})
+	predictionRepo := &creatingPredictionRepo{createErr: database.ClassifyError(duplicate)}
+	service := NewPredictionService(predictionRepo, nil, &fixedMatchRepo{match: upcoming}, nil, nil, nil, nil, PredictionServiceConfig{}, nil)
+
+	_, err := service.CreatePrediction(context.Background(), 7, &prediction.CreatePredictionRequest{
+		MatchID: 6, PredictedWinner: "A", PredictedScoreA: 1, PredictedScoreB: 0,
+	})
+	appErr, ok := err.(*response.AppError)
+	if !ok || appErr.Code != response.CodePredictionExists || appErr.StatusCode != 409 {
+		t.Fatalf("error = %v, want %s", err, response.CodePredictionExists)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// noExpiryTTL Redis TTL 返回 -1 表示键存在但未设置过期时间
+const noExpiryTTL = time.Duration(-1)
+
+// ttlAuditScanPatterns 审计扫描的键前缀
+var ttlAuditScanPatterns = []string{"stats:*", "metrics:*"}
+
+// TTLRule 键模式与应有的过期时间，与统计事件处理器、指标收集器中的 Expire 保持一致
+type TTLRule struct {
+	Pattern string        `json:"pattern"` // path.Match 通配模式
+	TTL     time.Duration `json:"ttl"`
+}
+
+// DefaultTTLRules 统计与指标计数器的过期规则
+//
+// 未列出的键（如 stats:registrations:total、stats:user:*）按设计永久保留，不做审计。
+var DefaultTTLRules = []TTLRule{
+	{Pattern: "stats:registrations:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "stats:registrations:monthly:*", TTL: 365 * 24 * time.Hour},
+	{Pattern: "stats:dau:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "stats:mau:*", TTL: 365 * 24 * time.Hour},
+	{Pattern: "stats:predictions:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "stats:predictions:monthly:*", TTL: statsMonthlyRollupTTL},
+	{Pattern: "stats:votes:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "stats:match_views:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "stats:leaderboard_views:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "stats:page_views:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "metrics:registrations:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "metrics:dau:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "metrics:wau:*", TTL: 4 * 7 * 24 * time.Hour},
+	{Pattern: "metrics:mau:*", TTL: 12 * 30 * 24 * time.Hour},
+	{Pattern: "metrics:predictions:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "metrics:votes:daily:*", TTL: 7 * 24 * time.Hour},
+	{Pattern: "metrics:votes:hourly:*", TTL: 24 * time.Hour},
+	{Pattern: "metrics:errors:daily:*", TTL: 7 * 24 * time.Hour},
+}
+
+// ttlAuditStore Redis 键扫描与过期时间读写
+type ttlAuditStore interface {
+	ScanKeys(ctx context.Context, pattern string) ([]string, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+}
+
+// MissingTTLKey 缺少过期时间的键
+type MissingTTLKey struct {
+	Key         string        `json:"key"`
+	Pattern     string        `json:"pattern"`
+	ExpectedTTL time.Duration `json:"expected_ttl"`
+	Repaired    bool          `json:"repaired"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// TTLAuditReport TTL 审计结果
+type TTLAuditReport struct {
+	KeysScanned int             `json:"keys_scanned"`
+	KeysMatched int             `json:"keys_matched"` // 命中过期规则的键数
+	Missing     []MissingTTLKey `json:"missing"`
+	Repaired    int             `json:"repaired"`
+	Failed      int             `json:"failed"`
+	Duration    time.Duration   `json:"duration"`
+}
+
+// RedisTTLAuditor 审计并修复缺少过期时间的统计/指标键
+//
+// 计数器的 Expire 调用失败时只记录日志，键会永久保留并不断累积，
+// 审计按键模式找出 TTL 为 -1 的键，修复时按规则补设过期时间。
+type RedisTTLAuditor struct {
+	store  ttlAuditStore
+	rules  []TTLRule
+	logger *logrus.Logger
+}
+
+// NewRedisTTLAuditor 创建 Redis TTL 审计服务，rules 为空时使用 DefaultTTLRules
+func NewRedisTTLAuditor(store ttlAuditStore, rules []TTLRule, logger *logrus.Logger) *RedisTTLAuditor {
+	if len(rules) == 0 {
+		rules = DefaultTTLRules
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &RedisTTLAuditor{
+		store:  store,
+		rules:  rules,
+		logger: logger,
+	}
+}
+
+// Audit 扫描统计与指标键，报告缺少过期时间的键
+func (a *RedisTTLAuditor) Audit(ctx context.Context) (*TTLAuditReport, error) {
+	return a.run(ctx, false)
+}
+
+// Repair 扫描统计与指标键，并为缺少过期时间的键补设过期时间
+func (a *RedisTTLAuditor) Repair(ctx context.Context) (*TTLAuditReport, error) {
+	return a.run(ctx, true)
+}
+
+func (a *RedisTTLAuditor) run(ctx context.Context, repair bool) (*TTLAuditReport, error) {
+	start := time.Now()
+	report := &TTLAuditReport{Missing: []MissingTTLKey{}}
+
+	var keys []string
+	for _, pattern := range ttlAuditScanPatterns {
+		scanned, err := a.store.ScanKeys(ctx, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("扫描键 %s 失败: %w", pattern, err)
+		}
+		keys = append(keys, scanned...)
+	}
+	sort.Strings(keys)
+	report.KeysScanned = len(keys)
+
+	for _, key := range keys {
+		rule, ok := a.matchRule(key)
+		if !ok {
+			continue
+		}
+		report.KeysMatched++
+
+		ttl, err := a.store.TTL(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("读取键 %s 的过期时间失败: %w", key, err)
+		}
+		if ttl != noExpiryTTL {
+			continue
+		}
+
+		missing := MissingTTLKey{Key: key, Pattern: rule.Pattern, ExpectedTTL: rule.TTL}
+		if repair {
+			if err := a.store.Expire(ctx, key, rule.TTL); err != nil {
+				missing.Error = err.Error()
+				report.Failed++
+			} else {
+				missing.Repaired = true
+				report.Repaired++
+			}
+		}
+		report.Missing = append(report.Missing, missing)
+	}
+
+	report.Duration = time.Since(start)
+	a.logger.WithFields(logrus.Fields{
+		"scanned":  report.KeysScanned,
+		"missing":  len(report.Missing),
+		"repaired": report.Repaired,
+		"failed":   report.Failed,
+	}).Info("Redis TTL audit completed")
+
+	return report, nil
+}
+
+// matchRule 返回键命中的第一条过期规则
+func (a *RedisTTLAuditor) matchRule(key string) (TTLRule, bool) {
+	for _, rule := range a.rules {
+		if matched, _ := path.Match(rule.Pattern, key); matched {
+			return rule, true
+		}
+	}
+	return TTLRule{}, false
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"io"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memoryTTLStore 内存 Redis 键与过期时间，TTL 为 -1 表示未设置过期
+type memoryTTLStore struct {
+	ttls map[string]time.Duration
+}
+
+func (s *memoryTTLStore) ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	for key := range s.ttls {
+		if matched, _ := path.Match(pattern, key); matched {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *memoryTTLStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, ok := s.ttls[key]
+	if !ok {
+		return -2, nil
+	}
+	return ttl, nil
+}
+
+func (s *memoryTTLStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	s.ttls[key] = expiration
+	return nil
+}
+
+func newTestTTLAuditor(store *memoryTTLStore) *RedisTTLAuditor {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewRedisTTLAuditor(store, nil, logger)
+}
+
+func seedTTLStore() *memoryTTLStore {
+	return &memoryTTLStore{ttls: map[string]time.Duration{
+		"stats:predictions:daily:2025-10-01":  noExpiryTTL,
+		"stats:predictions:daily:2025-10-02":  3 * 24 * time.Hour,
+		"stats:mau:2025-10":                   noExpiryTTL,
+		"metrics:votes:hourly:2025-10-01-13":  noExpiryTTL,
+		"metrics:wau:2025-40":                 10 * 24 * time.Hour,
+		"stats:registrations:total":           noExpiryTTL, // 按设计永久保留
+		"stats:user:predictions:7":            noExpiryTTL, // 按设计永久保留
+		"leaderboard:global":                  noExpiryTTL, // 不在扫描范围内
+		"metrics:errors:validation:high":      noExpiryTTL, // 无过期规则
+		"stats:leaderboard_views:daily:10-01": time.Hour,
+	}}
+}
+
+func TestRedisTTLAuditor_AuditReportsKeysWithoutTTL(t *testing.T) {
+	store := seedTTLStore()
+	report, err := newTestTTLAuditor(store).Audit(context.Background())
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	if report.KeysScanned != 9 {
+		t.Errorf("应扫描 9 个 stats/metrics 键, got %d", report.KeysScanned)
+	}
+	if report.KeysMatched != 6 {
+		t.Errorf("应有 6 个键命中过期规则, got %d", report.KeysMatched)
+	}
+
+	want := map[string]time.Duration{
+		"metrics:votes:hourly:2025-10-01-13": 24 * time.Hour,
+		"stats:mau:2025-10":                  365 * 24 * time.Hour,
+		"stats:predictions:daily:2025-10-01": 7 * 24 * time.Hour,
+	}
+	if len(report.Missing) != len(want) {
+		t.Fatalf("缺少过期时间的键数量 = %d, want %d: %+v", len(report.Missing), len(want), report.Missing)
+	}
+	for _, missing := range report.Missing {
+		if expected, ok := want[missing.Key]; !ok || missing.ExpectedTTL != expected {
+			t.Errorf("意外的审计结果: %+v", missing)
+		}
+		if missing.Repaired {
+			t.Errorf("仅审计时不应修复: %s", missing.Key)
+		}
+	}
+	if store.ttls["stats:predictions:daily:2025-10-01"] != noExpiryTTL {
+		t.Error("仅审计时不应修改过期时间")
+	}
+}
+
+func TestRedisTTLAuditor_RepairSetsExpectedTTL(t *testing.T) {
+	store := seedTTLStore()
+	report, err := newTestTTLAuditor(store).Repair(context.Background())
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+	if report.Repaired != 3 || report.Failed != 0 {
+		t.Errorf("应修复 3 个键, repaired = %d, failed = %d", report.Repaired, report.Failed)
+	}
+
+	for key, want := range map[string]time.Duration{
+		"stats:predictions:daily:2025-10-01": 7 * 24 * time.Hour,
+		"stats:mau:2025-10":                  365 * 24 * time.Hour,
+		"metrics:votes:hourly:2025-10-01-13": 24 * time.Hour,
+		"stats:predictions:daily:2025-10-02": 3 * 24 * time.Hour,
+		"stats:registrations:total":          noExpiryTTL,
+		"metrics:errors:validation:high":     noExpiryTTL,
+	} {
+		if got := store.ttls[key]; got != want {
+			t.Errorf("%s 过期时间 = %v, want %v", key, got, want)
+		}
+	}
+
+	again, err := newTestTTLAuditor(store).Audit(context.Background())
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if len(again.Missing) != 0 {
+		t.Errorf("修复后不应再有缺少过期时间的键: %+v", again.Missing)
+	}
+}
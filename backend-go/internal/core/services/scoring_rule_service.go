@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"backend-go/internal/core/domain/sport"
@@ -15,6 +16,7 @@ import (
 // ScoringRuleService 积分规则服务实现
 type ScoringRuleService struct {
 	scoringRuleRepo ports.ScoringRuleRepository
+	simulationRepo  ports.ScoringSimulationRepository
 	scoreCalculator ScoreCalculator
 	logger          *logrus.Logger
 }
@@ -22,11 +24,13 @@ type ScoringRuleService struct {
 // NewScoringRuleService 创建积分规则服务实例
 func NewScoringRuleService(
 	scoringRuleRepo ports.ScoringRuleRepository,
+	simulationRepo ports.ScoringSimulationRepository,
 	scoreCalculator ScoreCalculator,
 	logger *logrus.Logger,
 ) *ScoringRuleService {
 	return &ScoringRuleService{
 		scoringRuleRepo: scoringRuleRepo,
+		simulationRepo:  simulationRepo,
 		scoreCalculator: scoreCalculator,
 		logger:          logger,
 	}
@@ -64,6 +68,11 @@ func (s *ScoringRuleService) CreateScoringRule(ctx context.Context, req *ports.C
 		EnableModifyPenalty: req.EnableModifyPenalty,
 		ModifyPenaltyPoints: req.ModifyPenaltyPoints,
 		MaxModifyPenalty:    req.MaxModifyPenalty,
+
+		// 信心倍数
+		EnableConfidence:     req.EnableConfidence,
+		EnableConfidenceRisk: req.EnableConfidenceRisk,
+		ConfidenceRiskPoints: req.ConfidenceRiskPoints,
 	}
 
 	// 验证业务规则
@@ -161,6 +170,15 @@ func (s *ScoringRuleService) UpdateScoringRule(ctx context.Context, id uint, req
 	if req.MaxModifyPenalty != nil {
 		rule.MaxModifyPenalty = *req.MaxModifyPenalty
 	}
+	if req.EnableConfidence != nil {
+		rule.EnableConfidence = *req.EnableConfidence
+	}
+	if req.EnableConfidenceRisk != nil {
+		rule.EnableConfidenceRisk = *req.EnableConfidenceRisk
+	}
+	if req.ConfidenceRiskPoints != nil {
+		rule.ConfidenceRiskPoints = *req.ConfidenceRiskPoints
+	}
 
 	// 验证业务规则
 	if err := s.validateScoringRule(rule); err != nil {
@@ -218,7 +236,7 @@ func (s *ScoringRuleService) ListScoringRules(ctx context.Context, req *ports.Li
 	options := &ports.ListScoringRulesOptions{
 		SportTypeID: req.SportTypeID,
 		IsActive:    req.IsActive,
-		OrderBy:     req.OrderBy,
+		OrderBy:     orderClause(req.OrderBy, req.SortOrder),
 		Limit:       req.PageSize,
 		Offset:      (req.Page - 1) * req.PageSize,
 	}
@@ -325,6 +343,101 @@ func (s *ScoringRuleService) RecalculateScores(ctx context.Context, sportTypeID
 	return result, nil
 }
 
+// SimulateRule 使用候选规则重算已结束比赛的积分，返回模拟排名及与当前排名的差异
+//
+// 模拟只读取预测数据并在内存中计算，不会修改预测积分、用户积分或排行榜。
+func (s *ScoringRuleService) SimulateRule(ctx context.Context, ruleID uint, tournament string) (*ports.SimulationResult, error) {
+	rule, err := s.scoringRuleRepo.GetByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	predictions, err := s.simulationRepo.ListSettledPredictions(ctx, rule.SportTypeID, tournament)
+	if err != nil {
+		s.logger.WithError(err).WithField("rule_id", ruleID).Error("Failed to list settled predictions")
+		return nil, fmt.Errorf("failed to list settled predictions: %w", err)
+	}
+
+	result := &ports.SimulationResult{
+		RuleID:           rule.ID,
+		SportTypeID:      rule.SportTypeID,
+		Tournament:       tournament,
+		TotalPredictions: len(predictions),
+		Standings:        []*ports.SimulatedStanding{},
+	}
+
+	byUser := make(map[uint]*ports.SimulatedStanding)
+	for _, p := range predictions {
+		breakdown, err := s.scoreCalculator.CalculateScore(ctx, &PredictionInfo{
+			ID:                p.PredictionID,
+			UserID:            p.UserID,
+			MatchID:           p.MatchID,
+			PredictedWinner:   p.PredictedWinner,
+			IsCorrect:         p.IsCorrect,
+			ModificationCount: p.ModificationCount,
+			VoteCount:         p.VoteCount,
+			CreatedAt:         p.CreatedAt,
+		}, &MatchInfo{
+			ID:          p.MatchID,
+			SportTypeID: rule.SportTypeID,
+			StartTime:   p.MatchStartTime,
+		}, rule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate score for prediction %d: %w", p.PredictionID, err)
+		}
+
+		standing, ok := byUser[p.UserID]
+		if !ok {
+			standing = &ports.SimulatedStanding{UserID: p.UserID, Username: p.Username, Nickname: p.Nickname}
+			byUser[p.UserID] = standing
+			result.Standings = append(result.Standings, standing)
+		}
+		standing.CurrentPoints += p.EarnedPoints
+		standing.ProjectedPoints += breakdown.TotalScore
+	}
+
+	// 先按当前积分排名，再按模拟积分排名，结果保持模拟排名顺序
+	rankStandings(result.Standings,
+		func(st *ports.SimulatedStanding) int { return st.CurrentPoints },
+		func(st *ports.SimulatedStanding, rank int) { st.CurrentRank = rank })
+	rankStandings(result.Standings,
+		func(st *ports.SimulatedStanding) int { return st.ProjectedPoints },
+		func(st *ports.SimulatedStanding, rank int) { st.ProjectedRank = rank })
+
+	for _, standing := range result.Standings {
+		standing.PointsDelta = standing.ProjectedPoints - standing.CurrentPoints
+		standing.RankDelta = standing.CurrentRank - standing.ProjectedRank
+		if standing.PointsDelta < 0 {
+			result.PointsChanged -= standing.PointsDelta
+		} else {
+			result.PointsChanged += standing.PointsDelta
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"rule_id":           ruleID,
+		"tournament":        tournament,
+		"total_predictions": result.TotalPredictions,
+		"users":             len(result.Standings),
+		"points_changed":    result.PointsChanged,
+	}).Info("Scoring rule simulation completed")
+
+	return result, nil
+}
+
+// rankStandings 按积分从高到低排序并设置名次，同分按用户ID升序
+func rankStandings(standings []*ports.SimulatedStanding, points func(*ports.SimulatedStanding) int, setRank func(*ports.SimulatedStanding, int)) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		if points(standings[i]) != points(standings[j]) {
+			return points(standings[i]) > points(standings[j])
+		}
+		return standings[i].UserID < standings[j].UserID
+	})
+	for i, standing := range standings {
+		setRank(standing, i+1)
+	}
+}
+
 // validateScoringRule 验证积分规则
 func (s *ScoringRuleService) validateScoringRule(rule *sport.ScoringRule) error {
 	if rule.Name == "" {
@@ -372,5 +485,14 @@ func (s *ScoringRuleService) validateScoringRule(rule *sport.ScoringRule) error
 		}
 	}
 
+	if rule.EnableConfidenceRisk {
+		if !rule.EnableConfidence {
+			return fmt.Errorf("confidence risk requires confidence to be enabled")
+		}
+		if rule.ConfidenceRiskPoints < 0 {
+			return fmt.Errorf("confidence risk points cannot be negative")
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
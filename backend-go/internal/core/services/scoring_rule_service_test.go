@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/internal/adapters/persistence/mysql"
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/prediction"
+	"backend-go/internal/core/domain/sport"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/core/ports"
+)
+
+// newSimulationTestDB 创建内存数据库并建表
+func newSimulationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&user.User{}, &sport.SportType{}, &sport.ScoringRule{}, &domain.Match{}, &prediction.Prediction{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	return db
+}
+
+// simulationSnapshot 记录模拟前后需保持不变的持久化状态
+type simulationSnapshot struct {
+	earnedPoints map[uint]int
+	userPoints   map[uint]int
+	activeRules  map[uint]bool
+}
+
+func takeSimulationSnapshot(t *testing.T, db *gorm.DB) simulationSnapshot {
+	t.Helper()
+	snapshot := simulationSnapshot{earnedPoints: map[uint]int{}, userPoints: map[uint]int{}, activeRules: map[uint]bool{}}
+
+	var predictions []prediction.Prediction
+	var users []user.User
+	var rules []sport.ScoringRule
+	if err := db.Find(&predictions).Error; err != nil {
+		t.Fatalf("读取预测失败: %v", err)
+	}
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("读取用户失败: %v", err)
+	}
+	if err := db.Find(&rules).Error; err != nil {
+		t.Fatalf("读取规则失败: %v", err)
+	}
+	for _, p := range predictions {
+		snapshot.earnedPoints[p.ID] = p.EarnedPoints
+	}
+	for _, u := range users {
+		snapshot.userPoints[u.ID] = u.Points
+	}
+	for _, r := range rules {
+		snapshot.activeRules[r.ID] = r.IsActive
+	}
+	return snapshot
+}
+
+func TestScoringRuleService_SimulateRule(t *testing.T) {
+	ctx := context.Background()
+	db := newSimulationTestDB(t)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	service := NewScoringRuleService(
+		mysql.NewSportScoringRuleRepository(db),
+		mysql.NewScoringSimulationRepository(db),
+		NewDefaultScoreCalculator(log),
+		log,
+	)
+
+	lol := &sport.SportType{Name: "LOL", Code: "lol", Category: sport.SportCategoryEsports}
+	football := &sport.SportType{Name: "Football", Code: "football", Category: sport.SportCategoryTraditional}
+	if err := db.Create([]*sport.SportType{lol, football}).Error; err != nil {
+		t.Fatalf("创建运动类型失败: %v", err)
+	}
+
+	// 候选规则：提前 24 小时预测奖励 20 分
+	candidate := &sport.ScoringRule{
+		SportTypeID: lol.ID, Name: "early bird", BasePoints: 10,
+		EnableTimeReward: true, TimeRewardPoints: 20, TimeRewardHours: 24,
+	}
+	if err := db.Create(candidate).Error; err != nil {
+		t.Fatalf("创建积分规则失败: %v", err)
+	}
+	if err := db.Model(candidate).Update("is_active", false).Error; err != nil {
+		t.Fatalf("更新积分规则失败: %v", err)
+	}
+
+	users := []*user.User{
+		{Username: "alice", Email: "alice@example.com", Password: "x", Points: 20},
+		{Username: "bob", Email: "bob@example.com", Password: "x", Points: 25},
+		{Username: "carol", Email: "carol@example.com", Password: "x"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	alice, bob, carol := users[0].ID, users[1].ID, users[2].ID
+
+	start := time.Now().Add(-2 * time.Hour)
+	newMatch := func(sportTypeID uint, status domain.MatchStatus) uint {
+		m := &domain.Match{TeamA: "A", TeamB: "B", Tournament: domain.TournamentSpring, SportTypeID: &sportTypeID, Status: status, StartTime: start}
+		if err := db.Create(m).Error; err != nil {
+			t.Fatalf("创建比赛失败: %v", err)
+		}
+		return m.ID
+	}
+	finished1 := newMatch(lol.ID, domain.MatchStatusFinished)
+	finished2 := newMatch(lol.ID, domain.MatchStatusFinished)
+	upcoming := newMatch(lol.ID, domain.MatchStatusUpcoming)
+	otherSport := newMatch(football.ID, domain.MatchStatusFinished)
+
+	newPrediction := func(userID, matchID uint, correct bool, earned int, madeBeforeStart time.Duration) {
+		p := &prediction.Prediction{
+			UserID: userID, MatchID: matchID, PredictedWinner: "A",
+			IsCorrect: correct, EarnedPoints: earned, CreatedAt: start.Add(-madeBeforeStart),
+		}
+		if err := db.Create(p).Error; err != nil {
+			t.Fatalf("创建预测失败: %v", err)
+		}
+	}
+	newPrediction(alice, finished1, true, 10, time.Hour)
+	newPrediction(alice, finished2, true, 10, time.Hour)
+	newPrediction(bob, finished1, true, 15, 48*time.Hour) // 当前 15，新规则 10+20
+	newPrediction(carol, finished2, false, 0, 48*time.Hour)
+	newPrediction(alice, upcoming, true, 0, 48*time.Hour)  // 未结束，不参与模拟
+	newPrediction(bob, otherSport, true, 10, 48*time.Hour) // 其他运动类型，不参与模拟
+
+	before := takeSimulationSnapshot(t, db)
+
+	result, err := service.SimulateRule(ctx, candidate.ID, "")
+	if err != nil {
+		t.Fatalf("SimulateRule() error = %v", err)
+	}
+
+	if result.RuleID != candidate.ID || result.SportTypeID != lol.ID || result.TotalPredictions != 4 {
+		t.Errorf("result = %+v, want 4 predictions for rule %d", result, candidate.ID)
+	}
+	want := []struct {
+		userID                    uint
+		current, projected        int
+		currentRank, projectedRnk int
+	}{
+		{bob, 15, 30, 2, 1},
+		{alice, 20, 20, 1, 2},
+		{carol, 0, 0, 3, 3},
+	}
+	if len(result.Standings) != len(want) {
+		t.Fatalf("standings = %d, want %d", len(result.Standings), len(want))
+	}
+	for i, w := range want {
+		st := result.Standings[i]
+		if st.UserID != w.userID || st.CurrentPoints != w.current || st.ProjectedPoints != w.projected ||
+			st.CurrentRank != w.currentRank || st.ProjectedRank != w.projectedRnk {
+			t.Errorf("standings[%d] = %+v, want %+v", i, st, w)
+		}
+		if st.PointsDelta != w.projected-w.current || st.RankDelta != w.currentRank-w.projectedRnk {
+			t.Errorf("standings[%d] delta = %d/%d", i, st.PointsDelta, st.RankDelta)
+		}
+	}
+	if result.PointsChanged != 15 {
+		t.Errorf("PointsChanged = %d, want 15", result.PointsChanged)
+	}
+
+	// 模拟不得修改任何持久化状态
+	after := takeSimulationSnapshot(t, db)
+	for id, points := range before.earnedPoints {
+		if after.earnedPoints[id] != points {
+			t.Errorf("预测 %d 积分被修改: %d -> %d", id, points, after.earnedPoints[id])
+		}
+	}
+	for id, points := range before.userPoints {
+		if after.userPoints[id] != points {
+			t.Errorf("用户 %d 积分被修改: %d -> %d", id, points, after.userPoints[id])
+		}
+	}
+	if after.activeRules[candidate.ID] {
+		t.Error("模拟不应激活候选规则")
+	}
+
+	// 赛事过滤
+	summer, err := service.SimulateRule(ctx, candidate.ID, string(domain.TournamentSummer))
+	if err != nil {
+		t.Fatalf("SimulateRule(SUMMER) error = %v", err)
+	}
+	if summer.TotalPredictions != 0 || len(summer.Standings) != 0 {
+		t.Errorf("SUMMER result = %+v, want empty", summer)
+	}
+}
+
+func TestScoringRuleService_ConfidenceToggles(t *testing.T) {
+	ctx := context.Background()
+	db := newSimulationTestDB(t)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	service := NewScoringRuleService(mysql.NewSportScoringRuleRepository(db), nil, NewDefaultScoreCalculator(log), log)
+
+	lol := &sport.SportType{Name: "LOL", Code: "lol", Category: sport.SportCategoryEsports}
+	if err := db.Create(lol).Error; err != nil {
+		t.Fatalf("创建运动类型失败: %v", err)
+	}
+
+	created, err := service.CreateScoringRule(ctx, &ports.CreateScoringRuleRequest{
+		SportTypeID: lol.ID, Name: "stakes", BasePoints: 10, TimeRewardHours: 24,
+		EnableConfidence: true, EnableConfidenceRisk: true, ConfidenceRiskPoints: 5,
+	})
+	if err != nil {
+		t.Fatalf("CreateScoringRule() error = %v", err)
+	}
+
+	// 结算使用的 prediction.ScoringRule 读取同一张表，应看到管理端保存的开关
+	var settled prediction.ScoringRule
+	if err := db.First(&settled, created.ID).Error; err != nil {
+		t.Fatalf("读取规则失败: %v", err)
+	}
+	if !settled.EnableConfidence || !settled.EnableConfidenceRisk || settled.ConfidenceRiskPoints != 5 {
+		t.Errorf("settled rule = %+v, want confidence enabled with risk 5", settled)
+	}
+
+	disabled := false
+	if _, err := service.UpdateScoringRule(ctx, created.ID, &ports.UpdateScoringRuleRequest{EnableConfidenceRisk: &disabled}); err != nil {
+		t.Fatalf("UpdateScoringRule() error = %v", err)
+	}
+	if _, err := service.UpdateScoringRule(ctx, created.ID, &ports.UpdateScoringRuleRequest{EnableConfidence: &disabled}); err != nil {
+		t.Fatalf("UpdateScoringRule() error = %v", err)
+	}
+	settled = prediction.ScoringRule{}
+	if err := db.First(&settled, created.ID).Error; err != nil {
+		t.Fatalf("读取规则失败: %v", err)
+	}
+	if settled.EnableConfidence || settled.EnableConfidenceRisk {
+		t.Errorf("settled rule = %+v, want confidence disabled", settled)
+	}
+
+	// 信心风险依赖信心倍数
+	enabled := true
+	if _, err := service.UpdateScoringRule(ctx, created.ID, &ports.UpdateScoringRuleRequest{EnableConfidenceRisk: &enabled}); err == nil {
+		t.Error("启用信心风险但未启用信心倍数时应返回错误")
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend-go/internal/core/domain"
+	"backend-go/internal/core/domain/user"
+	"backend-go/internal/shared/logger"
+	"backend-go/pkg/redis"
+)
+
+const (
+	// SessionsKeyPrefix 用户会话哈希键前缀，字段为 jti，值为会话 JSON
+	SessionsKeyPrefix = "auth:sessions:"
+
+	// RevokedTokenKeyPrefix 已吊销 jti 的黑名单键前缀，至少保留一个刷新令牌有效期
+	RevokedTokenKeyPrefix = "auth:revoked:"
+
+	// sessionTouchInterval 最近活跃时间的更新间隔，避免每个请求都写 Redis
+	sessionTouchInterval = time.Minute
+)
+
+// sessionCache 会话存储使用的 Redis 操作，redis.CacheService 满足该接口
+type sessionCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	HGet(ctx context.Context, key, field string) (string, error)
+	HSet(ctx context.Context, key string, values ...interface{}) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDelete(ctx context.Context, key string, fields ...string) error
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+}
+
+// SessionStore 按用户记录登录会话，并维护已吊销 jti 的黑名单
+type SessionStore struct {
+	cache      sessionCache
+	refreshTTL time.Duration // 刷新令牌有效期，吊销记录至少保留这么久
+	now        func() time.Time
+}
+
+// NewSessionStore 创建会话存储
+//
+// 刷新会沿用 jti 签发新的刷新令牌，吊销前签发的令牌最晚在 refreshTTL 后过期，
+// 黑名单因此至少保留 refreshTTL。
+func NewSessionStore(cache sessionCache, refreshTTL time.Duration) *SessionStore {
+	return &SessionStore{cache: cache, refreshTTL: refreshTTL, now: time.Now}
+}
+
+// Record 保存会话，会话哈希的过期时间随最晚过期的会话延长
+func (s *SessionStore) Record(ctx context.Context, session *user.Session) error {
+	if session.ID == "" {
+		return errors.New("session id is required")
+	}
+	if !session.ExpiresAt.After(s.now()) {
+		return nil
+	}
+
+	if err := s.save(ctx, session); err != nil {
+		return err
+	}
+	return s.expireSessions(ctx, session)
+}
+
+// Extend 刷新令牌后将会话的过期时间延长到新刷新令牌的过期时间
+//
+// 没有记录的会话（功能上线前登录）保持原样。
+func (s *SessionStore) Extend(ctx context.Context, userID uint, jti string, expiresAt time.Time) error {
+	session, err := s.get(ctx, userID, jti)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !expiresAt.After(session.ExpiresAt) {
+		return nil
+	}
+
+	session.ExpiresAt = expiresAt
+	if err := s.save(ctx, session); err != nil {
+		return err
+	}
+	return s.expireSessions(ctx, session)
+}
+
+// expireSessions 将会话哈希的过期时间设为用户所有会话中最晚的过期时间
+func (s *SessionStore) expireSessions(ctx context.Context, session *user.Session) error {
+	ttl := session.ExpiresAt.Sub(s.now())
+	if current, err := s.List(ctx, session.UserID); err == nil {
+		for _, other := range current {
+			if remaining := other.ExpiresAt.Sub(s.now()); remaining > ttl {
+				ttl = remaining
+			}
+		}
+	}
+	if err := s.cache.Expire(ctx, sessionsKey(session.UserID), ttl); err != nil {
+		return fmt.Errorf("failed to expire sessions of user %d: %w", session.UserID, err)
+	}
+	return nil
+}
+
+// List 返回用户未过期的会话，按最近活跃时间倒序；已过期的会话顺带清理
+func (s *SessionStore) List(ctx context.Context, userID uint) ([]*user.Session, error) {
+	key := sessionsKey(userID)
+	values, err := s.cache.HGetAll(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions of user %d: %w", userID, err)
+	}
+
+	now := s.now()
+	sessions := make([]*user.Session, 0, len(values))
+	var stale []string
+	for jti, raw := range values {
+		var session user.Session
+		if err := json.Unmarshal([]byte(raw), &session); err != nil || !session.ExpiresAt.After(now) {
+			stale = append(stale, jti)
+			continue
+		}
+		sessions = append(sessions, &session)
+	}
+	if len(stale) > 0 {
+		if err := s.cache.HDelete(ctx, key, stale...); err != nil {
+			logger.Warnf("Failed to delete expired sessions of user %d: %v", userID, err)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeen.Equal(sessions[j].LastSeen) {
+			return sessions[i].LastSeen.After(sessions[j].LastSeen)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions, nil
+}
+
+// Revoke 吊销用户的会话，jti 加入黑名单后该会话的访问令牌与刷新令牌均不再有效
+func (s *SessionStore) Revoke(ctx context.Context, userID uint, jti string) error {
+	session, err := s.get(ctx, userID, jti)
+	if err != nil {
+		return err
+	}
+
+	ttl := session.ExpiresAt.Sub(s.now())
+	if ttl < s.refreshTTL {
+		ttl = s.refreshTTL
+	}
+	if ttl > 0 {
+		if err := s.cache.Set(ctx, RevokedTokenKeyPrefix+jti, userID, ttl); err != nil {
+			return fmt.Errorf("failed to revoke session %s: %w", jti, err)
+		}
+	}
+	if err := s.cache.HDelete(ctx, sessionsKey(userID), jti); err != nil {
+		return fmt.Errorf("failed to delete session %s: %w", jti, err)
+	}
+	return nil
+}
+
+// CheckSession 校验令牌所属会话未被吊销，并更新会话的最近活跃时间
+//
+// 没有 jti 的旧令牌不做校验；黑名单读取失败时放行并记录日志，避免 Redis 故障导致全部用户无法访问。
+func (s *SessionStore) CheckSession(ctx context.Context, userID uint, jti string) error {
+	if jti == "" {
+		return nil
+	}
+
+	if _, err := s.cache.Get(ctx, RevokedTokenKeyPrefix+jti); err == nil {
+		return domain.ErrSessionRevoked
+	} else if !errors.Is(err, redis.ErrKeyNotFound) {
+		logger.Warnf("Failed to check revoked session %s: %v", jti, err)
+		return nil
+	}
+
+	session, err := s.get(ctx, userID, jti)
+	if err != nil {
+		// 功能上线前登录的会话没有记录
+		return nil
+	}
+	if now := s.now(); now.Sub(session.LastSeen) >= sessionTouchInterval {
+		session.LastSeen = now
+		if err := s.save(ctx, session); err != nil {
+			logger.Warnf("Failed to touch session %s: %v", jti, err)
+		}
+	}
+	return nil
+}
+
+func (s *SessionStore) get(ctx context.Context, userID uint, jti string) (*user.Session, error) {
+	raw, err := s.cache.HGet(ctx, sessionsKey(userID), jti)
+	if err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session %s: %w", jti, err)
+	}
+
+	var session user.Session
+	if err := json.Unmarshal([]byte(raw), &session); err != nil || !session.ExpiresAt.After(s.now()) {
+		return nil, domain.ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (s *SessionStore) save(ctx context.Context, session *user.Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := s.cache.HSet(ctx, sessionsKey(session.UserID), session.ID, string(data)); err != nil {
+		return fmt.Errorf("failed to save session %s: %w", session.ID, err)
+	}
+	return nil
+}
+
+func sessionsKey(userID uint) string {
+	return fmt.Sprintf("%s%d", SessionsKeyPrefix, userID)
+}
@@ -5,12 +5,20 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"unicode/utf8"
 
 	"backend-go/internal/core/domain/sport"
 	"backend-go/internal/core/ports"
+	"backend-go/pkg/response"
 	"github.com/sirupsen/logrus"
 )
 
+// 运动类型名称与代码长度限制，与数据库列长度一致
+const (
+	maxSportTypeNameLength = 100
+	maxSportTypeCodeLength = 20
+)
+
 // SportTypeService 运动类型服务实现
 type SportTypeService struct {
 	sportTypeRepo ports.SportTypeRepository
@@ -38,12 +46,19 @@ func (s *SportTypeService) CreateSportType(ctx context.Context, req *ports.Creat
 		return nil, fmt.Errorf("invalid sport category: %s", req.Category)
 	}
 
-	// 标准化代码（转小写）
+	// 标准化名称与代码（代码转小写）
+	name := strings.TrimSpace(req.Name)
 	code := strings.ToLower(strings.TrimSpace(req.Code))
-	
+	if err := validateSportTypeIdentity(name, code); err != nil {
+		return nil, err
+	}
+	if err := s.ensureSportTypeUnique(ctx, name, code, 0); err != nil {
+		return nil, err
+	}
+
 	// 创建运动类型实体
 	sportType := &sport.SportType{
-		Name:        strings.TrimSpace(req.Name),
+		Name:        name,
 		Code:        code,
 		Category:    req.Category,
 		Icon:        strings.TrimSpace(req.Icon),
@@ -132,6 +147,15 @@ func (s *SportTypeService) UpdateSportType(ctx context.Context, id uint, req *po
 		sportType.SortOrder = *req.SortOrder
 	}
 
+	if req.Name != nil || req.Code != nil {
+		if err := validateSportTypeIdentity(sportType.Name, sportType.Code); err != nil {
+			return nil, err
+		}
+		if err := s.ensureSportTypeUnique(ctx, sportType.Name, sportType.Code, sportType.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 保存更新
 	if err := s.sportTypeRepo.Update(ctx, sportType); err != nil {
 		s.logger.WithError(err).Error("Failed to update sport type")
@@ -146,6 +170,19 @@ func (s *SportTypeService) UpdateSportType(ctx context.Context, id uint, req *po
 func (s *SportTypeService) DeleteSportType(ctx context.Context, id uint) error {
 	s.logger.WithField("id", id).Info("Deleting sport type")
 
+	// 仍被比赛或管理员权限引用时禁止删除
+	refs, err := s.sportTypeRepo.CountReferences(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to check sport type references")
+		return fmt.Errorf("failed to delete sport type: %w", err)
+	}
+	if refs.MatchCount > 0 || refs.ScopedAdminCount > 0 {
+		return response.NewConflictError(
+			fmt.Sprintf("sport type is still referenced by %d match(es) and %d scoped admin(s)", refs.MatchCount, refs.ScopedAdminCount),
+			refs,
+		)
+	}
+
 	if err := s.sportTypeRepo.Delete(ctx, id); err != nil {
 		s.logger.WithError(err).Error("Failed to delete sport type")
 		return fmt.Errorf("failed to delete sport type: %w", err)
@@ -169,7 +206,7 @@ func (s *SportTypeService) ListSportTypes(ctx context.Context, req *ports.ListSp
 	options := &ports.ListSportTypesOptions{
 		Category: req.Category,
 		IsActive: req.IsActive,
-		OrderBy:  req.OrderBy,
+		OrderBy:  orderClause(req.OrderBy, req.SortOrder),
 		Limit:    req.PageSize,
 		Offset:   (req.Page - 1) * req.PageSize,
 	}
@@ -298,6 +335,62 @@ func (s *SportTypeService) GetSportTypeStats(ctx context.Context, sportTypeID ui
 	}, nil
 }
 
+// validateSportTypeIdentity 校验名称与代码非空且不超过长度限制
+func validateSportTypeIdentity(name, code string) error {
+	if name == "" {
+		return response.NewBadRequestError("sport type name is required", nil)
+	}
+	if utf8.RuneCountInString(name) > maxSportTypeNameLength {
+		return response.NewBadRequestError(fmt.Sprintf("sport type name must be at most %d characters", maxSportTypeNameLength), nil)
+	}
+	if code == "" {
+		return response.NewBadRequestError("sport type code is required", nil)
+	}
+	if len(code) > maxSportTypeCodeLength {
+		return response.NewBadRequestError(fmt.Sprintf("sport type code must be at most %d characters", maxSportTypeCodeLength), nil)
+	}
+	for _, r := range code {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return response.NewBadRequestError("sport type code must be alphanumeric", code)
+		}
+	}
+	return nil
+}
+
+// orderClause 将排序字段和方向拼成排序子句，方向只接受 asc、desc，为空时交由数据库默认升序
+//
+// orderBy 已由请求校验限定在白名单内。
+func orderClause(orderBy, sortOrder string) string {
+	if orderBy == "" {
+		return ""
+	}
+	switch strings.ToLower(sortOrder) {
+	case "asc", "desc":
+		return orderBy + " " + strings.ToUpper(sortOrder)
+	}
+	return orderBy
+}
+
+// ensureSportTypeUnique 确保名称和代码未被其他运动类型占用（不区分大小写）
+func (s *SportTypeService) ensureSportTypeUnique(ctx context.Context, name, code string, excludeID uint) error {
+	exists, err := s.sportTypeRepo.ExistsByName(ctx, name, excludeID)
+	if err != nil {
+		return fmt.Errorf("failed to check sport type name: %w", err)
+	}
+	if exists {
+		return response.NewConflictError("sport type name already exists", name)
+	}
+
+	exists, err = s.sportTypeRepo.ExistsByCode(ctx, code, excludeID)
+	if err != nil {
+		return fmt.Errorf("failed to check sport type code: %w", err)
+	}
+	if exists {
+		return response.NewConflictError("sport type code already exists", code)
+	}
+	return nil
+}
+
 // createDefaultConfiguration 创建默认配置
 func (s *SportTypeService) createDefaultConfiguration(sportTypeID uint, category sport.SportCategory) *sport.SportConfiguration {
 	config := &sport.SportConfiguration{
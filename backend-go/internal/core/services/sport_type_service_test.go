@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"backend-go/internal/core/domain/sport"
+	"backend-go/internal/core/ports"
+	"backend-go/pkg/response"
+	"github.com/sirupsen/logrus"
+)
+
+// memorySportTypeRepo 内存运动类型仓储
+type memorySportTypeRepo struct {
+	ports.SportTypeRepository
+	sportTypes []*sport.SportType
+	refs       map[uint]*ports.SportTypeReferences
+	deleted    []uint
+}
+
+func (r *memorySportTypeRepo) Create(ctx context.Context, sportType *sport.SportType) error {
+	sportType.ID = uint(len(r.sportTypes) + 1)
+	r.sportTypes = append(r.sportTypes, sportType)
+	return nil
+}
+
+func (r *memorySportTypeRepo) GetByID(ctx context.Context, id uint) (*sport.SportType, error) {
+	for _, st := range r.sportTypes {
+		if st.ID == id {
+			copied := *st
+			return &copied, nil
+		}
+	}
+	return nil, response.NewNotFoundError("sport type not found")
+}
+
+func (r *memorySportTypeRepo) Update(ctx context.Context, sportType *sport.SportType) error {
+	return nil
+}
+
+func (r *memorySportTypeRepo) CreateConfiguration(ctx context.Context, config *sport.SportConfiguration) error {
+	return nil
+}
+
+func (r *memorySportTypeRepo) ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error) {
+	for _, st := range r.sportTypes {
+		if st.ID != excludeID && strings.EqualFold(st.Name, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memorySportTypeRepo) ExistsByCode(ctx context.Context, code string, excludeID uint) (bool, error) {
+	for _, st := range r.sportTypes {
+		if st.ID != excludeID && strings.EqualFold(st.Code, code) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memorySportTypeRepo) CountReferences(ctx context.Context, id uint) (*ports.SportTypeReferences, error) {
+	if refs, ok := r.refs[id]; ok {
+		return refs, nil
+	}
+	return &ports.SportTypeReferences{}, nil
+}
+
+func (r *memorySportTypeRepo) Delete(ctx context.Context, id uint) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func newTestSportTypeService() (*SportTypeService, *memorySportTypeRepo) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &memorySportTypeRepo{
+		sportTypes: []*sport.SportType{{ID: 1, Name: "LOL", Code: "lol", Category: sport.SportCategoryEsports}},
+		refs:       make(map[uint]*ports.SportTypeReferences),
+	}
+	return NewSportTypeService(repo, logger), repo
+}
+
+func TestCreateSportType_Success(t *testing.T) {
+	service, repo := newTestSportTypeService()
+
+	created, err := service.CreateSportType(context.Background(), &ports.CreateSportTypeRequest{
+		Name: " 王者荣耀 ", Code: "WZRY", Category: sport.SportCategoryEsports,
+	})
+	if err != nil {
+		t.Fatalf("CreateSportType() error = %v", err)
+	}
+	if created.Name != "王者荣耀" || created.Code != "wzry" {
+		t.Errorf("名称/代码应被标准化: %q / %q", created.Name, created.Code)
+	}
+	if len(repo.sportTypes) != 2 {
+		t.Errorf("仓储中运动类型数量 = %d, want 2", len(repo.sportTypes))
+	}
+}
+
+func TestCreateSportType_RejectsDuplicateName(t *testing.T) {
+	service, repo := newTestSportTypeService()
+
+	_, err := service.CreateSportType(context.Background(), &ports.CreateSportTypeRequest{
+		Name: "lol", Code: "league", Category: sport.SportCategoryEsports,
+	})
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != 409 {
+		t.Fatalf("大小写不同的重复名称应返回冲突错误, got %v", err)
+	}
+	if len(repo.sportTypes) != 1 {
+		t.Error("重复名称不应写入仓储")
+	}
+
+	// 更新为其他运动类型已使用的代码同样冲突，自身代码不冲突
+	repo.sportTypes = append(repo.sportTypes, &sport.SportType{ID: 2, Name: "足球", Code: "football", Category: sport.SportCategoryTraditional})
+	code := "LOL"
+	if _, err := service.UpdateSportType(context.Background(), 2, &ports.UpdateSportTypeRequest{Code: &code}); !errors.As(err, &appErr) || appErr.StatusCode != 409 {
+		t.Errorf("重复代码应返回冲突错误, got %v", err)
+	}
+	own := "Football"
+	if _, err := service.UpdateSportType(context.Background(), 2, &ports.UpdateSportTypeRequest{Code: &own}); err != nil {
+		t.Errorf("保留自身代码不应冲突, got %v", err)
+	}
+}
+
+func TestCreateSportType_RejectsEmptyName(t *testing.T) {
+	service, _ := newTestSportTypeService()
+
+	_, err := service.CreateSportType(context.Background(), &ports.CreateSportTypeRequest{
+		Name: "   ", Code: "dota", Category: sport.SportCategoryEsports,
+	})
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != 400 {
+		t.Errorf("空名称应返回400, got %v", err)
+	}
+}
+
+func TestDeleteSportType_RejectsReferencedSportType(t *testing.T) {
+	service, repo := newTestSportTypeService()
+	repo.refs[1] = &ports.SportTypeReferences{MatchCount: 3, ScopedAdminCount: 1}
+
+	err := service.DeleteSportType(context.Background(), 1)
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != 409 {
+		t.Fatalf("被引用的运动类型应返回冲突错误, got %v", err)
+	}
+	if !strings.Contains(appErr.Message, "3 match(es)") || !strings.Contains(appErr.Message, "1 scoped admin(s)") {
+		t.Errorf("错误信息应说明引用情况: %s", appErr.Message)
+	}
+	if len(repo.deleted) != 0 {
+		t.Error("被引用的运动类型不应被删除")
+	}
+
+	repo.refs[1] = &ports.SportTypeReferences{}
+	if err := service.DeleteSportType(context.Background(), 1); err != nil || len(repo.deleted) != 1 {
+		t.Errorf("无引用时应删除成功, err = %v", err)
+	}
+}
+
+func TestOrderClause(t *testing.T) {
+	cases := []struct {
+		orderBy, sortOrder, want string
+	}{
+		{"", "desc", ""},
+		{"name", "", "name"},
+		{"name", "desc", "name DESC"},
+		{"created_at", "asc", "created_at ASC"},
+		{"name", "sideways", "name"},
+	}
+	for _, tc := range cases {
+		if got := orderClause(tc.orderBy, tc.sortOrder); got != tc.want {
+			t.Errorf("orderClause(%q, %q) = %q, want %q", tc.orderBy, tc.sortOrder, got, tc.want)
+		}
+	}
+}
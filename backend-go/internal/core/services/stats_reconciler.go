@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/ports"
+	"backend-go/pkg/redis"
+)
+
+// 需要与数据库核对的 Redis 计数器键（由统计事件处理器维护）
+const (
+	statsUserPredictionsKey = "stats:user:predictions:%d"
+	statsUserVotesKey       = "stats:user:votes:%d"
+	statsPredictionVotesKey = "stats:prediction:votes:%d"
+)
+
+// statsCounterStore Redis 计数器读写
+type statsCounterStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// StatsReconcilerConfig 统计计数核对配置
+type StatsReconcilerConfig struct {
+	Interval   time.Duration // 核对间隔
+	SampleSize int           // 每次核对的用户数与预测数上限，<=0 表示全部
+}
+
+// StatsCounterFix 一次计数器校正
+type StatsCounterFix struct {
+	Key    string `json:"key"`
+	Cached string `json:"cached"` // 校正前的 Redis 值，键不存在时为空
+	Actual int64  `json:"actual"`
+}
+
+// ReconcileReport 统计计数核对结果
+type ReconcileReport struct {
+	UsersChecked       int               `json:"users_checked"`
+	PredictionsChecked int               `json:"predictions_checked"`
+	CountersChecked    int               `json:"counters_checked"`
+	Fixed              int               `json:"fixed"`
+	Failed             int               `json:"failed"`
+	Fixes              []StatsCounterFix `json:"fixes,omitempty"`
+	Duration           time.Duration     `json:"duration"`
+}
+
+// StatsReconciler 以数据库为准校正漂移的 Redis 统计计数器
+//
+// 每次按ID顺序核对一批用户与预测，下次从上次结束处继续，到末尾后从头开始，
+// 多次运行后覆盖全部数据。
+type StatsReconciler struct {
+	repo   ports.StatsCountRepository
+	store  statsCounterStore
+	config StatsReconcilerConfig
+	logger *logrus.Logger
+
+	heartbeat Heartbeat
+
+	mu               sync.Mutex
+	userCursor       uint
+	predictionCursor uint
+}
+
+// NewStatsReconciler 创建统计计数核对服务
+func NewStatsReconciler(repo ports.StatsCountRepository, store statsCounterStore, config StatsReconcilerConfig, logger *logrus.Logger) *StatsReconciler {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &StatsReconciler{
+		repo:   repo,
+		store:  store,
+		config: config,
+		logger: logger,
+	}
+}
+
+// SetHeartbeat 设置每次核对后上报的心跳
+func (s *StatsReconciler) SetHeartbeat(heartbeat Heartbeat) {
+	s.heartbeat = heartbeat
+}
+
+// Interval 返回核对间隔
+func (s *StatsReconciler) Interval() time.Duration {
+	return s.config.Interval
+}
+
+// Run 按配置间隔执行核对，直到 ctx 取消
+func (s *StatsReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.ReconcileStats(ctx)
+			if s.heartbeat != nil {
+				s.heartbeat.Beat(ctx)
+			}
+			if err != nil {
+				s.logger.WithError(err).Warn("Failed to reconcile stats counters")
+				continue
+			}
+			s.logger.WithFields(logrus.Fields{
+				"users_checked":       report.UsersChecked,
+				"predictions_checked": report.PredictionsChecked,
+				"fixed":               report.Fixed,
+				"failed":              report.Failed,
+				"duration":            report.Duration,
+			}).Info("Stats counters reconciled")
+		}
+	}
+}
+
+// ReconcileStats 核对一批用户与预测的计数器，修正与数据库不一致的值
+//
+// 核对与写入之间的并发计数可能造成短暂偏差，下次核对时会再次修正。
+func (s *StatsReconciler) ReconcileStats(ctx context.Context) (ReconcileReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	report := ReconcileReport{}
+
+	userIDs, err := s.nextBatch(ctx, s.repo.ListUserIDs, s.userCursor)
+	if err != nil {
+		return report, err
+	}
+	if err := s.reconcileUsers(ctx, userIDs, &report); err != nil {
+		return report, err
+	}
+	s.userCursor = s.nextCursor(userIDs)
+
+	predictionIDs, err := s.nextBatch(ctx, s.repo.ListPredictionIDs, s.predictionCursor)
+	if err != nil {
+		return report, err
+	}
+	if err := s.reconcilePredictions(ctx, predictionIDs, &report); err != nil {
+		return report, err
+	}
+	s.predictionCursor = s.nextCursor(predictionIDs)
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// reconcileUsers 核对用户预测数与投票数
+func (s *StatsReconciler) reconcileUsers(ctx context.Context, userIDs []uint, report *ReconcileReport) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	predictionCounts, err := s.repo.CountPredictionsByUsers(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	voteCounts, err := s.repo.CountVotesByVoters(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range userIDs {
+		s.reconcileCounter(ctx, fmt.Sprintf(statsUserPredictionsKey, id), predictionCounts[id], report)
+		s.reconcileCounter(ctx, fmt.Sprintf(statsUserVotesKey, id), voteCounts[id], report)
+	}
+	report.UsersChecked += len(userIDs)
+	return nil
+}
+
+// reconcilePredictions 核对预测获得的票数
+func (s *StatsReconciler) reconcilePredictions(ctx context.Context, predictionIDs []uint, report *ReconcileReport) error {
+	if len(predictionIDs) == 0 {
+		return nil
+	}
+
+	voteCounts, err := s.repo.CountVotesByPredictions(ctx, predictionIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range predictionIDs {
+		s.reconcileCounter(ctx, fmt.Sprintf(statsPredictionVotesKey, id), voteCounts[id], report)
+	}
+	report.PredictionsChecked += len(predictionIDs)
+	return nil
+}
+
+// reconcileCounter 核对单个计数器，不一致时写入数据库真实值
+func (s *StatsReconciler) reconcileCounter(ctx context.Context, key string, actual int64, report *ReconcileReport) {
+	report.CountersChecked++
+
+	cached, err := s.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, redis.ErrKeyNotFound) {
+			report.Failed++
+			s.logger.WithError(err).WithField("key", key).Warn("Failed to read stats counter")
+			return
+		}
+		// 计数为0时无需创建计数器
+		if actual == 0 {
+			return
+		}
+	} else if value, parseErr := strconv.ParseInt(cached, 10, 64); parseErr == nil && value == actual {
+		return
+	}
+
+	// 统计计数器不设过期时间，与统计事件处理器一致
+	if err := s.store.Set(ctx, key, actual, 0); err != nil {
+		report.Failed++
+		s.logger.WithError(err).WithField("key", key).Warn("Failed to correct stats counter")
+		return
+	}
+
+	report.Fixed++
+	report.Fixes = append(report.Fixes, StatsCounterFix{Key: key, Cached: cached, Actual: actual})
+}
+
+// nextBatch 获取游标之后的一批ID，上一批恰好取到末尾时从头开始
+func (s *StatsReconciler) nextBatch(ctx context.Context, list func(context.Context, uint, int) ([]uint, error), cursor uint) ([]uint, error) {
+	ids, err := list(ctx, cursor, s.config.SampleSize)
+	if err != nil || len(ids) > 0 || cursor == 0 {
+		return ids, err
+	}
+	return list(ctx, 0, s.config.SampleSize)
+}
+
+// nextCursor 返回下次核对的起点，未取满一批说明已到末尾，下次从头开始
+func (s *StatsReconciler) nextCursor(ids []uint) uint {
+	if s.config.SampleSize <= 0 || len(ids) < s.config.SampleSize {
+		return 0
+	}
+	return ids[len(ids)-1]
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/pkg/redis"
+)
+
+// fakeStatsCountRepo 以内存数据作为数据库真实值
+type fakeStatsCountRepo struct {
+	userIDs         []uint
+	predictionIDs   []uint
+	userPredictions map[uint]int64
+	userVotes       map[uint]int64
+	predictionVotes map[uint]int64
+}
+
+func (r *fakeStatsCountRepo) ListUserIDs(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	return pageIDs(r.userIDs, afterID, limit), nil
+}
+
+func (r *fakeStatsCountRepo) ListPredictionIDs(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	return pageIDs(r.predictionIDs, afterID, limit), nil
+}
+
+func (r *fakeStatsCountRepo) CountPredictionsByUsers(ctx context.Context, userIDs []uint) (map[uint]int64, error) {
+	return r.userPredictions, nil
+}
+
+func (r *fakeStatsCountRepo) CountVotesByVoters(ctx context.Context, userIDs []uint) (map[uint]int64, error) {
+	return r.userVotes, nil
+}
+
+func (r *fakeStatsCountRepo) CountVotesByPredictions(ctx context.Context, predictionIDs []uint) (map[uint]int64, error) {
+	return r.predictionVotes, nil
+}
+
+func pageIDs(ids []uint, afterID uint, limit int) []uint {
+	var page []uint
+	for _, id := range ids {
+		if id > afterID && (limit <= 0 || len(page) < limit) {
+			page = append(page, id)
+		}
+	}
+	return page
+}
+
+// memoryCounterStore 内存 Redis 计数器
+type memoryCounterStore struct {
+	values map[string]string
+}
+
+func (s *memoryCounterStore) Get(ctx context.Context, key string) (string, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return "", redis.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (s *memoryCounterStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func newTestStatsReconciler(sampleSize int) (*StatsReconciler, *fakeStatsCountRepo, *memoryCounterStore) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &fakeStatsCountRepo{
+		userIDs:         []uint{1, 2},
+		predictionIDs:   []uint{10},
+		userPredictions: map[uint]int64{1: 2},
+		userVotes:       map[uint]int64{1: 1},
+		predictionVotes: map[uint]int64{10: 3},
+	}
+	store := &memoryCounterStore{values: map[string]string{
+		"stats:user:predictions:1":  "7", // 漂移
+		"stats:user:votes:1":        "1",
+		"stats:prediction:votes:10": "3",
+	}}
+	return NewStatsReconciler(repo, store, StatsReconcilerConfig{SampleSize: sampleSize}, logger), repo, store
+}
+
+func TestReconcileStats_CorrectsDivergentCounter(t *testing.T) {
+	reconciler, _, store := newTestStatsReconciler(0)
+
+	report, err := reconciler.ReconcileStats(context.Background())
+	if err != nil {
+		t.Fatalf("ReconcileStats() error = %v", err)
+	}
+	if got := store.values["stats:user:predictions:1"]; got != "2" {
+		t.Errorf("漂移的计数器应被校正为数据库值, got %s", got)
+	}
+	if report.Fixed != 1 || len(report.Fixes) != 1 {
+		t.Fatalf("应只校正一个计数器, report = %+v", report)
+	}
+	if fix := report.Fixes[0]; fix.Key != "stats:user:predictions:1" || fix.Cached != "7" || fix.Actual != 2 {
+		t.Errorf("校正记录不正确: %+v", fix)
+	}
+	if report.UsersChecked != 2 || report.PredictionsChecked != 1 {
+		t.Errorf("核对数量不正确: %+v", report)
+	}
+	// 用户2在数据库中计数为0，不应创建计数器
+	if _, ok := store.values["stats:user:predictions:2"]; ok {
+		t.Error("计数为0时不应创建计数器")
+	}
+
+	report, _ = reconciler.ReconcileStats(context.Background())
+	if report.Fixed != 0 {
+		t.Errorf("校正后再次核对不应有修正, fixed = %d", report.Fixed)
+	}
+}
+
+func TestReconcileStats_RestoresMissingCounter(t *testing.T) {
+	reconciler, _, store := newTestStatsReconciler(0)
+	delete(store.values, "stats:prediction:votes:10")
+
+	report, _ := reconciler.ReconcileStats(context.Background())
+	if got := store.values["stats:prediction:votes:10"]; got != "3" {
+		t.Errorf("丢失的计数器应按数据库值恢复, got %q", got)
+	}
+	if report.Fixed != 2 {
+		t.Errorf("fixed = %d, want 2", report.Fixed)
+	}
+}
+
+func TestReconcileStats_SamplesInBatches(t *testing.T) {
+	reconciler, _, store := newTestStatsReconciler(1)
+
+	// 第一批只核对用户1
+	if report, _ := reconciler.ReconcileStats(context.Background()); report.UsersChecked != 1 || report.Fixed != 1 {
+		t.Errorf("第一批 report = %+v", report)
+	}
+
+	// 第二批从用户2继续，然后回到开头
+	store.values["stats:user:predictions:1"] = "9"
+	if report, _ := reconciler.ReconcileStats(context.Background()); report.UsersChecked != 1 || report.Fixed != 0 {
+		t.Errorf("第二批 report = %+v", report)
+	}
+	if report, _ := reconciler.ReconcileStats(context.Background()); report.Fixed != 1 {
+		t.Errorf("遍历完后应从头开始核对, report = %+v", report)
+	}
+}
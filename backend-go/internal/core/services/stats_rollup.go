@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 预测统计汇总使用的 Redis 键
+const (
+	statsPredictionsDailyKey   = "stats:predictions:daily:%s"   // 统计事件处理器维护的每日计数，保留 7 天
+	statsPredictionsMonthlyKey = "stats:predictions:monthly:%s" // 哈希，字段为日期，值为当日计数
+	statsPredictionsSeriesKey  = "stats:predictions:series:%dd" // 最近 N 天的预计算序列（JSON）
+)
+
+const (
+	statsRollupDateLayout  = "2006-01-02"
+	statsRollupMonthLayout = "2006-01"
+	// statsMonthlyRollupTTL 月度汇总保留时长，与 stats:registrations:monthly 一致
+	statsMonthlyRollupTTL = 365 * 24 * time.Hour
+)
+
+// DefaultStatsSeriesDays 预计算的序列长度
+var DefaultStatsSeriesDays = []int{30, 90}
+
+// statsRollupStore 汇总任务使用的 Redis 操作
+type statsRollupStore interface {
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+	HSet(ctx context.Context, key string, values ...interface{}) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	GetJSON(ctx context.Context, key string, dest interface{}) error
+}
+
+// StatsRollupConfig 每日统计汇总配置
+type StatsRollupConfig struct {
+	Interval     time.Duration // 汇总间隔，默认 24 小时
+	LookbackDays int           // 每次汇总回看的天数（含当天），不应超过每日计数的保留天数，默认 7
+	SeriesDays   []int         // 预计算的序列长度，默认 30 与 90 天
+}
+
+// StatsPoint 序列中的单日数据
+type StatsPoint struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// StatsSeries 最近 N 天的预测数序列，缺失的日期计为 0
+type StatsSeries struct {
+	Days        int          `json:"days"`
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Total       int64        `json:"total"`
+	Points      []StatsPoint `json:"points"`
+	GeneratedAt time.Time    `json:"generated_at"`
+}
+
+// RollupReport 一次汇总的结果
+type RollupReport struct {
+	DaysRolledUp  int              `json:"days_rolled_up"`
+	MonthlyTotals map[string]int64 `json:"monthly_totals"`
+	SeriesCached  []int            `json:"series_cached"`
+	Duration      time.Duration    `json:"duration"`
+}
+
+// StatsRollup 将每日预测计数汇总为月度数据，并预计算最近 N 天的序列
+//
+// 每日计数只保留 7 天，汇总把每天的计数写入月度哈希的对应日期字段，
+// 重复执行只会覆盖同一字段，因此可以安全重跑。
+type StatsRollup struct {
+	store  statsRollupStore
+	config StatsRollupConfig
+	logger *logrus.Logger
+	now    func() time.Time
+
+	heartbeat Heartbeat
+
+	mu sync.Mutex
+}
+
+// NewStatsRollup 创建每日统计汇总服务
+func NewStatsRollup(store statsRollupStore, config StatsRollupConfig, logger *logrus.Logger) *StatsRollup {
+	if config.Interval <= 0 {
+		config.Interval = 24 * time.Hour
+	}
+	if config.LookbackDays <= 0 {
+		config.LookbackDays = 7
+	}
+	if len(config.SeriesDays) == 0 {
+		config.SeriesDays = DefaultStatsSeriesDays
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &StatsRollup{
+		store:  store,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// SetHeartbeat 设置每次汇总后上报的心跳
+func (s *StatsRollup) SetHeartbeat(heartbeat Heartbeat) {
+	s.heartbeat = heartbeat
+}
+
+// Interval 返回汇总间隔
+func (s *StatsRollup) Interval() time.Duration {
+	return s.config.Interval
+}
+
+// Run 启动时立即汇总一次，之后按配置间隔执行，直到 ctx 取消
+func (s *StatsRollup) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := s.Rollup(ctx)
+		if s.heartbeat != nil {
+			s.heartbeat.Beat(ctx)
+		}
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to roll up daily stats")
+		} else {
+			s.logger.WithFields(logrus.Fields{
+				"days_rolled_up": report.DaysRolledUp,
+				"series_cached":  report.SeriesCached,
+				"duration":       report.Duration,
+			}).Info("Daily stats rolled up")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rollup 汇总回看窗口内的每日计数，并刷新预计算序列
+func (s *StatsRollup) Rollup(ctx context.Context) (RollupReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	today := s.today()
+	report := RollupReport{MonthlyTotals: map[string]int64{}}
+
+	dates := statsDates(today, s.config.LookbackDays)
+	keys := make([]string, len(dates))
+	for i, date := range dates {
+		keys[i] = fmt.Sprintf(statsPredictionsDailyKey, date)
+	}
+	values, err := s.store.MGet(ctx, keys...)
+	if err != nil {
+		return report, fmt.Errorf("read daily stats: %w", err)
+	}
+
+	// 按月分组写入，每日计数已过期的日期保留月度哈希中的原值
+	fields := map[string][]interface{}{}
+	for i, value := range values {
+		count, ok := parseStatsCount(value)
+		if !ok {
+			continue
+		}
+		month := dates[i][:len(statsRollupMonthLayout)]
+		fields[month] = append(fields[month], dates[i], count)
+		report.DaysRolledUp++
+	}
+	for month, pairs := range fields {
+		key := fmt.Sprintf(statsPredictionsMonthlyKey, month)
+		if err := s.store.HSet(ctx, key, pairs...); err != nil {
+			return report, fmt.Errorf("write monthly rollup %s: %w", month, err)
+		}
+		if err := s.store.Expire(ctx, key, statsMonthlyRollupTTL); err != nil {
+			s.logger.WithError(err).WithField("key", key).Warn("Failed to set monthly rollup TTL")
+		}
+	}
+
+	daily, err := s.loadMonths(ctx, statsMonths(today, maxInt(s.config.SeriesDays...)))
+	if err != nil {
+		return report, err
+	}
+	for date, count := range daily {
+		report.MonthlyTotals[date[:len(statsRollupMonthLayout)]] += count
+	}
+
+	for _, days := range s.config.SeriesDays {
+		series := buildStatsSeries(today, days, daily)
+		series.GeneratedAt = s.now().UTC()
+		// 多保留一个周期，避免某次汇总失败时序列立即消失
+		if err := s.store.SetJSON(ctx, fmt.Sprintf(statsPredictionsSeriesKey, days), series, 2*s.config.Interval); err != nil {
+			return report, fmt.Errorf("cache %d day series: %w", days, err)
+		}
+		report.SeriesCached = append(report.SeriesCached, days)
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// MonthlyTotal 返回指定月份（2006-01）的预测总数
+func (s *StatsRollup) MonthlyTotal(ctx context.Context, month string) (int64, error) {
+	daily, err := s.loadMonths(ctx, []string{month})
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, count := range daily {
+		total += count
+	}
+	return total, nil
+}
+
+// CachedSeries 读取预计算的最近 N 天序列，尚未汇总时返回 redis.ErrKeyNotFound
+func (s *StatsRollup) CachedSeries(ctx context.Context, days int) (*StatsSeries, error) {
+	var series StatsSeries
+	if err := s.store.GetJSON(ctx, fmt.Sprintf(statsPredictionsSeriesKey, days), &series); err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// loadMonths 读取月度哈希，返回日期到计数的映射
+func (s *StatsRollup) loadMonths(ctx context.Context, months []string) (map[string]int64, error) {
+	daily := map[string]int64{}
+	for _, month := range months {
+		values, err := s.store.HGetAll(ctx, fmt.Sprintf(statsPredictionsMonthlyKey, month))
+		if err != nil {
+			return nil, fmt.Errorf("read monthly rollup %s: %w", month, err)
+		}
+		for date, value := range values {
+			if count, err := strconv.ParseInt(value, 10, 64); err == nil {
+				daily[date] = count
+			}
+		}
+	}
+	return daily, nil
+}
+
+// today 返回当前日期（与统计事件处理器一致使用本地时区）
+func (s *StatsRollup) today() time.Time {
+	now := s.now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// buildStatsSeries 生成截至 today 的最近 days 天序列
+func buildStatsSeries(today time.Time, days int, daily map[string]int64) StatsSeries {
+	dates := statsDates(today, days)
+	series := StatsSeries{Days: days, From: dates[0], To: dates[len(dates)-1], Points: make([]StatsPoint, len(dates))}
+	for i, date := range dates {
+		series.Points[i] = StatsPoint{Date: date, Count: daily[date]}
+		series.Total += daily[date]
+	}
+	return series
+}
+
+// statsDates 返回截至 today 的最近 days 天日期，按时间升序
+func statsDates(today time.Time, days int) []string {
+	dates := make([]string, days)
+	for i := 0; i < days; i++ {
+		dates[i] = today.AddDate(0, 0, i-days+1).Format(statsRollupDateLayout)
+	}
+	return dates
+}
+
+// statsMonths 返回最近 days 天覆盖的月份
+func statsMonths(today time.Time, days int) []string {
+	seen := map[string]bool{}
+	for _, date := range statsDates(today, days) {
+		seen[date[:len(statsRollupMonthLayout)]] = true
+	}
+	months := make([]string, 0, len(seen))
+	for month := range seen {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	return months
+}
+
+// parseStatsCount 解析 MGET 返回的计数，键不存在或无法解析时返回 false
+func parseStatsCount(value interface{}) (int64, bool) {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return 0, false
+	}
+	count, err := strconv.ParseInt(raw, 10, 64)
+	return count, err == nil
+}
+
+func maxInt(values ...int) int {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
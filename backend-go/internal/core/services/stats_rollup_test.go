@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/pkg/redis"
+)
+
+// memoryRollupStore 内存版 Redis 字符串与哈希
+type memoryRollupStore struct {
+	values map[string]string
+	hashes map[string]map[string]string
+}
+
+func newMemoryRollupStore() *memoryRollupStore {
+	return &memoryRollupStore{values: map[string]string{}, hashes: map[string]map[string]string{}}
+}
+
+func (s *memoryRollupStore) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if value, ok := s.values[key]; ok {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *memoryRollupStore) HSet(ctx context.Context, key string, values ...interface{}) error {
+	if s.hashes[key] == nil {
+		s.hashes[key] = map[string]string{}
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		s.hashes[key][fmt.Sprint(values[i])] = fmt.Sprint(values[i+1])
+	}
+	return nil
+}
+
+func (s *memoryRollupStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.hashes[key], nil
+}
+
+func (s *memoryRollupStore) HDelete(ctx context.Context, key string, fields ...string) error {
+	for _, field := range fields {
+		delete(s.hashes[key], field)
+	}
+	return nil
+}
+
+func (s *memoryRollupStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+
+func (s *memoryRollupStore) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.values[key] = string(data)
+	return nil
+}
+
+func (s *memoryRollupStore) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	value, ok := s.values[key]
+	if !ok {
+		return redis.ErrKeyNotFound
+	}
+	return json.Unmarshal([]byte(value), dest)
+}
+
+func TestStatsRollup_RollsUpDailyKeys(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryRollupStore()
+
+	// 每日计数跨越月份边界；09-05 的每日键已过期，只存在于上次汇总写入的月度哈希中
+	daily := map[string]int64{
+		"2025-09-28": 3, "2025-09-29": 5, "2025-09-30": 2,
+		"2025-10-01": 4, "2025-10-02": 6, "2025-10-03": 1,
+	}
+	for date, count := range daily {
+		store.values[fmt.Sprintf(statsPredictionsDailyKey, date)] = fmt.Sprint(count)
+	}
+	store.hashes["stats:predictions:monthly:2025-09"] = map[string]string{"2025-09-05": "10"}
+	store.hashes["stats:predictions:monthly:2025-07"] = map[string]string{"2025-07-10": "7"}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	rollup := NewStatsRollup(store, StatsRollupConfig{}, logger)
+	rollup.now = func() time.Time { return time.Date(2025, 10, 3, 23, 30, 0, 0, time.Local) }
+
+	wantMonthly := map[string]int64{"2025-09": 20, "2025-10": 11}
+	for run := 1; run <= 2; run++ {
+		report, err := rollup.Rollup(ctx)
+		if err != nil {
+			t.Fatalf("第 %d 次 Rollup() error = %v", run, err)
+		}
+		if report.DaysRolledUp != len(daily) {
+			t.Errorf("第 %d 次 DaysRolledUp = %d, want %d", run, report.DaysRolledUp, len(daily))
+		}
+		// 重复执行结果不变
+		for month, want := range wantMonthly {
+			got, err := rollup.MonthlyTotal(ctx, month)
+			if err != nil || got != want {
+				t.Errorf("第 %d 次 MonthlyTotal(%s) = %d, %v, want %d", run, month, got, err, want)
+			}
+		}
+	}
+
+	series30, err := rollup.CachedSeries(ctx, 30)
+	if err != nil {
+		t.Fatalf("CachedSeries(30) error = %v", err)
+	}
+	if series30.From != "2025-09-04" || series30.To != "2025-10-03" || len(series30.Points) != 30 || series30.Total != 31 {
+		t.Errorf("series30 = %s..%s, %d points, total %d, want 2025-09-04..2025-10-03, 30 points, total 31",
+			series30.From, series30.To, len(series30.Points), series30.Total)
+	}
+	if p := series30.Points[1]; p.Date != "2025-09-05" || p.Count != 10 {
+		t.Errorf("series30.Points[1] = %+v, want 2025-09-05 计数 10", p)
+	}
+
+	// 90 天序列包含 7 月的月度数据
+	series90, err := rollup.CachedSeries(ctx, 90)
+	if err != nil {
+		t.Fatalf("CachedSeries(90) error = %v", err)
+	}
+	if len(series90.Points) != 90 || series90.Total != 38 {
+		t.Errorf("series90 = %d points, total %d, want 90 points, total 38", len(series90.Points), series90.Total)
+	}
+
+	// 当天计数继续增长后重跑，覆盖而不是累加
+	store.values[fmt.Sprintf(statsPredictionsDailyKey, "2025-10-03")] = "4"
+	if _, err := rollup.Rollup(ctx); err != nil {
+		t.Fatalf("Rollup() error = %v", err)
+	}
+	if got, _ := rollup.MonthlyTotal(ctx, "2025-10"); got != 14 {
+		t.Errorf("MonthlyTotal(2025-10) = %d, want 14", got)
+	}
+}
+
+func TestStatsRollup_CachedSeriesMissingBeforeFirstRun(t *testing.T) {
+	rollup := NewStatsRollup(newMemoryRollupStore(), StatsRollupConfig{}, nil)
+	if _, err := rollup.CachedSeries(context.Background(), 30); err != redis.ErrKeyNotFound {
+		t.Errorf("CachedSeries() error = %v, want redis.ErrKeyNotFound", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"backend-go/internal/shared/jwt"
 	"backend-go/internal/shared/logger"
 	"backend-go/internal/shared/password"
+	"backend-go/pkg/response"
 )
 
 // userService 用户服务实现
@@ -19,9 +20,16 @@ type userService struct {
 	jwtService       jwt.JWTService
 	passwordService  password.Service
 	leaderboardCache LeaderboardCacheService
+	sessions         sessionChecker           // 为空时不校验会话吊销
 	loginAttempts    map[string]*LoginAttempt // 简单的内存存储，生产环境应使用 Redis
 }
 
+// sessionChecker 校验令牌所属会话是否被吊销，刷新后延长会话，SessionStore 满足该接口
+type sessionChecker interface {
+	CheckSession(ctx context.Context, userID uint, jti string) error
+	Extend(ctx context.Context, userID uint, jti string, expiresAt time.Time) error
+}
+
 // LoginAttempt 登录尝试记录
 type LoginAttempt struct {
 	Count       int
@@ -41,6 +49,7 @@ func NewUserService(
 	jwtService jwt.JWTService,
 	passwordService password.Service,
 	leaderboardCache LeaderboardCacheService,
+	sessions sessionChecker,
 	config Config,
 ) user.Service {
 	if config.MaxLoginAttempts == 0 {
@@ -55,6 +64,7 @@ func NewUserService(
 		jwtService:       jwtService,
 		passwordService:  passwordService,
 		leaderboardCache: leaderboardCache,
+		sessions:         sessions,
 		loginAttempts:    make(map[string]*LoginAttempt),
 	}
 }
@@ -78,7 +88,7 @@ func (s *userService) Register(ctx context.Context, req *user.RegisterRequest) (
 	// 检查用户名是否已存在
 	exists, err := s.userRepo.ExistsByUsername(ctx, req.Username)
 	if err != nil {
-		logger.Error("Failed to check username existence: %v", err)
+		logger.Errorf("Failed to check username existence: %v", err)
 		return nil, errors.New("failed to check username availability")
 	}
 	if exists {
@@ -88,7 +98,7 @@ func (s *userService) Register(ctx context.Context, req *user.RegisterRequest) (
 	// 检查邮箱是否已存在
 	exists, err = s.userRepo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
-		logger.Error("Failed to check email existence: %v", err)
+		logger.Errorf("Failed to check email existence: %v", err)
 		return nil, errors.New("failed to check email availability")
 	}
 	if exists {
@@ -112,7 +122,11 @@ func (s *userService) Register(ctx context.Context, req *user.RegisterRequest) (
 
 	// 创建用户
 	if err := s.userRepo.Create(ctx, newUser); err != nil {
-		logger.Error("Failed to create user: %v", err)
+		// 并发注册时唯一索引兜底，与上面的存在性检查返回相同语义
+		if response.IsErrorCode(err, response.CodeConflict) {
+			return nil, registerConflictError(err, newUser)
+		}
+		logger.Errorf("Failed to create user: %v", err)
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -120,6 +134,27 @@ func (s *userService) Register(ctx context.Context, req *user.RegisterRequest) (
 	return newUser, nil
 }
 
+// registerConflictError 按冲突的唯一索引区分用户名与邮箱已被注册，无法识别索引时返回通用提示
+func registerConflictError(err error, u *user.User) *response.AppError {
+	var key string
+	if appErr, ok := err.(*response.AppError); ok {
+		if details, ok := appErr.Details.(map[string]interface{}); ok {
+			key, _ = details["key"].(string)
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(key, "idx_username"):
+		return response.NewUserExistsError(u.Username).WithCause(err)
+	case strings.HasSuffix(key, "idx_email"):
+		return response.NewUserExistsError(u.Email).WithCause(err)
+	}
+	appErr := response.NewUserExistsError("").WithCause(err)
+	appErr.Message = "用户名或邮箱已被注册"
+	appErr.Details = nil
+	return appErr
+}
+
 // Login 用户登录
 func (s *userService) Login(ctx context.Context, req *user.LoginRequest) (*user.AuthResponse, error) {
 	if req == nil {
@@ -228,6 +263,55 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *user.
 	return existingUser, nil
 }
 
+// GetPreferences 获取通知偏好
+func (s *userService) GetPreferences(ctx context.Context, userID uint) (*user.NotificationPreferences, error) {
+	if userID == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	prefs, err := s.userRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences 更新通知偏好
+func (s *userService) UpdatePreferences(ctx context.Context, userID uint, req *user.UpdateNotificationPreferencesRequest) (*user.NotificationPreferences, error) {
+	if userID == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if req == nil {
+		return nil, errors.New("update request cannot be nil")
+	}
+
+	if req.ReminderLeadMinutes != nil &&
+		(*req.ReminderLeadMinutes < user.MinReminderLeadMinutes || *req.ReminderLeadMinutes > user.MaxReminderLeadMinutes) {
+		return nil, response.NewBadRequestError(
+			fmt.Sprintf("提醒提前量需在 %d 到 %d 分钟之间", user.MinReminderLeadMinutes, user.MaxReminderLeadMinutes), nil)
+	}
+
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.EmailEnabled != nil {
+		prefs.EmailEnabled = *req.EmailEnabled
+	}
+	if req.ReminderLeadMinutes != nil {
+		prefs.ReminderLeadMinutes = *req.ReminderLeadMinutes
+	}
+	if req.PushEnabled != nil {
+		prefs.PushEnabled = *req.PushEnabled
+	}
+
+	if err := s.userRepo.SaveNotificationPreferences(ctx, prefs); err != nil {
+		logger.Errorf("Failed to update notification preferences for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
 // GetLeaderboard 获取排行榜
 func (s *userService) GetLeaderboard(ctx context.Context, tournament string) ([]user.LeaderboardEntry, error) {
 	// 使用缓存服务获取排行榜
@@ -262,11 +346,20 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*u
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	if issuedBeforePasswordChange(claims, foundUser) {
+		return nil, errors.New("token revoked by password change")
+	}
+
+	if err := s.checkSession(ctx, claims); err != nil {
+		return nil, err
+	}
+
 	// 生成新的令牌对
 	tokenPair, err := s.jwtService.RefreshTokenWithUserInfo(refreshToken, foundUser.Username, string(foundUser.Role))
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
+	s.extendSession(ctx, tokenPair.RefreshToken)
 
 	return &user.AuthResponse{
 		User:         foundUser,
@@ -278,6 +371,15 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*u
 
 // ValidateToken 验证令牌
 func (s *userService) ValidateToken(ctx context.Context, token string) (*user.User, error) {
+	identity, err := s.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return identity.User, nil
+}
+
+// Authenticate 验证访问令牌并返回身份，代入令牌同时返回发起代入的管理员ID
+func (s *userService) Authenticate(ctx context.Context, token string) (*user.TokenIdentity, error) {
 	if token == "" {
 		return nil, errors.New("token cannot be empty")
 	}
@@ -298,7 +400,15 @@ func (s *userService) ValidateToken(ctx context.Context, token string) (*user.Us
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	return foundUser, nil
+	if issuedBeforePasswordChange(claims, foundUser) {
+		return nil, errors.New("token revoked by password change")
+	}
+
+	if err := s.checkSession(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return &user.TokenIdentity{User: foundUser, ImpersonatorID: claims.ImpersonatorID}, nil
 }
 
 // ChangePassword 重置/修改用户密码（管理员调用）
@@ -343,6 +453,40 @@ func (s *userService) ChangePasswordWithVerify(ctx context.Context, userID uint,
 	return s.ChangePassword(ctx, userID, newPassword)
 }
 
+// checkSession 拒绝已吊销会话的令牌
+func (s *userService) checkSession(ctx context.Context, claims *jwt.Claims) error {
+	if s.sessions == nil {
+		return nil
+	}
+	if err := s.sessions.CheckSession(ctx, claims.UserID, claims.ID); err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+	return nil
+}
+
+// extendSession 将会话的过期时间延长到新刷新令牌的过期时间，失败只记录日志
+func (s *userService) extendSession(ctx context.Context, refreshToken string) {
+	if s.sessions == nil {
+		return
+	}
+	claims, err := s.jwtService.ValidateToken(refreshToken)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return
+	}
+	if err := s.sessions.Extend(ctx, claims.UserID, claims.ID, claims.ExpiresAt.Time); err != nil {
+		logger.Warnf("Failed to extend session %s of user %d: %v", claims.ID, claims.UserID, err)
+	}
+}
+
+// issuedBeforePasswordChange 检查令牌是否签发于最近一次修改密码之前
+func issuedBeforePasswordChange(claims *jwt.Claims, u *user.User) bool {
+	if u.LastPasswordChange == nil || claims.IssuedAt == nil {
+		return false
+	}
+	// JWT 签发时间精确到秒
+	return claims.IssuedAt.Time.Before(u.LastPasswordChange.Truncate(time.Second))
+}
+
 // validateRegisterRequest 验证注册请求
 func (s *userService) validateRegisterRequest(req *user.RegisterRequest) error {
 	if req.Username == "" {
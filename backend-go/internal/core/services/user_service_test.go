@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/core/domain/user"
+	"backend-go/pkg/response"
+)
+
+func TestRegisterConflictError_DistinguishesViolatedIndex(t *testing.T) {
+	u := &user.User{Username: "alice", Email: "alice@example.com"}
+	tests := []struct {
+		name       string
+		details    interface{}
+		identifier interface{}
+		message    string
+	}{
+		{"username", map[string]interface{}{"key": "users.idx_username"}, "alice", "用户已存在"},
+		{"email", map[string]interface{}{"key": "idx_email"}, "alice@example.com", "用户已存在"},
+		{"unknown", nil, nil, "用户名或邮箱已被注册"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := response.NewConflictError("数据已存在，请勿重复提交", tt.details)
+			got := registerConflictError(conflict, u)
+
+			if got.Code != response.CodeUserExists || got.Message != tt.message {
+				t.Errorf("code=%s message=%q, want %s %q", got.Code, got.Message, response.CodeUserExists, tt.message)
+			}
+			var identifier interface{}
+			if details, ok := got.Details.(map[string]interface{}); ok {
+				identifier = details["identifier"]
+			}
+			if identifier != tt.identifier {
+				t.Errorf("identifier=%v, want %v", identifier, tt.identifier)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// workerHeartbeatKey 保存各定时任务心跳的 Redis 哈希，字段为任务名
+	workerHeartbeatKey = "worker:heartbeats"
+	// workerHeartbeatStaleFactor 超过多少个执行间隔未运行视为停滞
+	workerHeartbeatStaleFactor = 2
+	// workerHeartbeatExpireFactor 超过多少个执行间隔未运行视为任务已停用，从报告和哈希中移除；
+	// 每次上报也按该倍数设置哈希过期时间，worker 整体停止后哈希随之过期
+	workerHeartbeatExpireFactor = 5
+)
+
+// heartbeatStore 心跳读写
+type heartbeatStore interface {
+	HSet(ctx context.Context, key string, values ...interface{}) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDelete(ctx context.Context, key string, fields ...string) error
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+}
+
+// Heartbeat 定时任务每次执行后调用 Beat 上报心跳
+type Heartbeat interface {
+	Beat(ctx context.Context)
+}
+
+// workerHeartbeatRecord 心跳哈希中单个任务的值
+type workerHeartbeatRecord struct {
+	LastRun  time.Time     `json:"last_run"`
+	Interval time.Duration `json:"interval"`
+}
+
+// WorkerJobStatus 单个定时任务的运行状态
+type WorkerJobStatus struct {
+	Job      string        `json:"job"`
+	LastRun  time.Time     `json:"last_run"`
+	Interval time.Duration `json:"interval"` // 预期执行间隔
+	Age      time.Duration `json:"age"`      // 距上次运行的时间
+	Stale    bool          `json:"stale"`    // 超过 2 个执行间隔未运行
+}
+
+// WorkerHealthReport 后台任务健康状况
+type WorkerHealthReport struct {
+	Healthy   bool              `json:"healthy"`
+	Jobs      []WorkerJobStatus `json:"jobs"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// WorkerHeartbeat 记录与检查后台定时任务的心跳
+//
+// worker 每次执行任务后写入运行时间与预期间隔，API 读取后判断任务是否按时运行，
+// 因此 API 无需知道 worker 启用了哪些任务。从未上报过心跳的任务不会出现在报告中，
+// 超过 5 个间隔未上报的任务视为已停用并移除；没有任何任务上报时报告为不健康。
+type WorkerHeartbeat struct {
+	store  heartbeatStore
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+// NewWorkerHeartbeat 创建后台任务心跳服务
+func NewWorkerHeartbeat(store heartbeatStore, logger *logrus.Logger) *WorkerHeartbeat {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &WorkerHeartbeat{
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Record 记录任务 job 刚刚运行，interval 为该任务的预期执行间隔
+func (h *WorkerHeartbeat) Record(ctx context.Context, job string, interval time.Duration) error {
+	value, err := json.Marshal(workerHeartbeatRecord{LastRun: h.now().UTC(), Interval: interval})
+	if err != nil {
+		return err
+	}
+	if err := h.store.HSet(ctx, workerHeartbeatKey, job, string(value)); err != nil {
+		return fmt.Errorf("failed to record heartbeat for %s: %w", job, err)
+	}
+	return h.store.Expire(ctx, workerHeartbeatKey, workerHeartbeatExpireFactor*interval)
+}
+
+// Job 返回任务 job 的心跳上报器，上报失败只记录日志
+func (h *WorkerHeartbeat) Job(job string, interval time.Duration) Heartbeat {
+	return &jobHeartbeat{heartbeat: h, job: job, interval: interval}
+}
+
+// Check 读取各任务心跳，超过 2 个执行间隔未运行的任务标记为停滞
+//
+// 超过 5 个执行间隔未运行的任务不再列出并从哈希中删除。没有任何任务上报心跳时
+// （worker 未启动或已停止）报告为不健康。
+func (h *WorkerHeartbeat) Check(ctx context.Context) (*WorkerHealthReport, error) {
+	values, err := h.store.HGetAll(ctx, workerHeartbeatKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker heartbeats: %w", err)
+	}
+
+	now := h.now().UTC()
+	report := &WorkerHealthReport{Jobs: make([]WorkerJobStatus, 0, len(values)), CheckedAt: now}
+	var expired []string
+	for job, value := range values {
+		status := WorkerJobStatus{Job: job, Stale: true}
+		var record workerHeartbeatRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			h.logger.WithError(err).WithField("job", job).Warn("Invalid worker heartbeat")
+		} else {
+			status.LastRun = record.LastRun
+			status.Interval = record.Interval
+			status.Age = now.Sub(record.LastRun)
+			status.Stale = status.Age > workerHeartbeatStaleFactor*record.Interval
+			if status.Age > workerHeartbeatExpireFactor*record.Interval {
+				expired = append(expired, job)
+				continue
+			}
+		}
+		report.Jobs = append(report.Jobs, status)
+	}
+	if len(expired) > 0 {
+		if err := h.store.HDelete(ctx, workerHeartbeatKey, expired...); err != nil {
+			h.logger.WithError(err).WithField("jobs", expired).Warn("Failed to remove expired worker heartbeats")
+		}
+	}
+
+	report.Healthy = len(report.Jobs) > 0
+	for _, job := range report.Jobs {
+		if job.Stale {
+			report.Healthy = false
+		}
+	}
+	sort.Slice(report.Jobs, func(i, j int) bool { return report.Jobs[i].Job < report.Jobs[j].Job })
+	return report, nil
+}
+
+// jobHeartbeat 单个任务的心跳上报器
+type jobHeartbeat struct {
+	heartbeat *WorkerHeartbeat
+	job       string
+	interval  time.Duration
+}
+
+// Beat 上报任务心跳
+func (j *jobHeartbeat) Beat(ctx context.Context) {
+	if err := j.heartbeat.Record(ctx, j.job, j.interval); err != nil {
+		j.heartbeat.logger.WithError(err).WithField("job", j.job).Warn("Failed to record worker heartbeat")
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestWorkerHeartbeat(now *time.Time) (*WorkerHeartbeat, *memoryRollupStore) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	store := newMemoryRollupStore()
+	heartbeat := NewWorkerHeartbeat(store, logger)
+	heartbeat.now = func() time.Time { return *now }
+	return heartbeat, store
+}
+
+func TestWorkerHeartbeat_ReportsMissedHeartbeatAsStale(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat, _ := newTestWorkerHeartbeat(&now)
+
+	heartbeat.Job("outbox_relay", time.Minute).Beat(ctx)
+	heartbeat.Job("stats_reconcile", time.Hour).Beat(ctx)
+
+	report, err := heartbeat.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !report.Healthy || len(report.Jobs) != 2 {
+		t.Fatalf("刚上报心跳时 report = %+v, want 2 个正常任务", report)
+	}
+
+	// 中继错过多次心跳，核对任务仍在 2 个间隔内
+	now = now.Add(3 * time.Minute)
+	report, err = heartbeat.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if report.Healthy {
+		t.Error("存在停滞任务时 Healthy = true")
+	}
+	want := map[string]bool{"outbox_relay": true, "stats_reconcile": false}
+	for _, job := range report.Jobs {
+		if job.Stale != want[job.Job] {
+			t.Errorf("%s stale = %v, want %v", job.Job, job.Stale, want[job.Job])
+		}
+		if job.Age != 3*time.Minute {
+			t.Errorf("%s age = %v, want 3m", job.Job, job.Age)
+		}
+	}
+
+	// 恢复上报后不再停滞
+	heartbeat.Job("outbox_relay", time.Minute).Beat(ctx)
+	if report, _ = heartbeat.Check(ctx); !report.Healthy {
+		t.Errorf("恢复心跳后 report = %+v, want healthy", report)
+	}
+}
+
+func TestWorkerHeartbeat_InvalidRecordIsStale(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat, store := newTestWorkerHeartbeat(&now)
+
+	_ = store.HSet(ctx, workerHeartbeatKey, "stats_rollup", "not-json")
+
+	report, err := heartbeat.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if report.Healthy || len(report.Jobs) != 1 || !report.Jobs[0].Stale {
+		t.Errorf("无法解析的心跳 report = %+v, want stale", report)
+	}
+}
+
+func TestWorkerHeartbeat_NoJobsIsUnhealthy(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat, _ := newTestWorkerHeartbeat(&now)
+
+	report, err := heartbeat.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if report.Healthy || len(report.Jobs) != 0 {
+		t.Errorf("无心跳 report = %+v, want unhealthy", report)
+	}
+}
+
+func TestWorkerHeartbeat_DropsDisabledJobs(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat, store := newTestWorkerHeartbeat(&now)
+
+	heartbeat.Job("cache_warmup", time.Minute).Beat(ctx)
+	heartbeat.Job("stats_reconcile", time.Hour).Beat(ctx)
+
+	// 预热任务停用后超过 5 个间隔未上报，核对任务仍在运行
+	now = now.Add(10 * time.Minute)
+	heartbeat.Job("stats_reconcile", time.Hour).Beat(ctx)
+
+	report, err := heartbeat.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !report.Healthy || len(report.Jobs) != 1 || report.Jobs[0].Job != "stats_reconcile" {
+		t.Errorf("report = %+v, want 只剩正常运行的 stats_reconcile", report)
+	}
+	if _, ok := store.hashes[workerHeartbeatKey]["cache_warmup"]; ok {
+		t.Error("已停用任务的心跳未从哈希中删除")
+	}
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultScorePrecision 非整数分值默认保留的小数位数
+const DefaultScorePrecision = 2
+
+// maxScorePrecision 允许配置的最大小数位数
+const maxScorePrecision = 6
+
+var scorePrecision atomic.Int32
+
+func init() {
+	scorePrecision.Store(DefaultScorePrecision)
+}
+
+// SetScorePrecision 设置非整数分值序列化时保留的小数位数，超出 0-6 时按边界处理
+func SetScorePrecision(precision int) {
+	if precision < 0 {
+		precision = 0
+	}
+	if precision > maxScorePrecision {
+		precision = maxScorePrecision
+	}
+	scorePrecision.Store(int32(precision))
+}
+
+// ScorePrecision 返回当前的分值小数位数
+func ScorePrecision() int {
+	return int(scorePrecision.Load())
+}
+
+// Score 积分/分数值
+//
+// 整数值序列化为不带小数点的数字（100），非整数按配置的精度输出固定位数（88.50），
+// 保证各接口返回的分值格式一致。
+type Score float64
+
+// NewScore 将浮点分值（如 Redis 有序集合的 ZScore/ZRangeWithScores 结果）
+// 按当前精度舍入，消除浮点误差
+func NewScore(value float64) Score {
+	return Score(roundScore(value, ScorePrecision()))
+}
+
+// Float64 返回分值的浮点数形式
+func (s Score) Float64() float64 {
+	return float64(s)
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (s Score) MarshalJSON() ([]byte, error) {
+	value := float64(s)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return []byte("0"), nil
+	}
+
+	precision := ScorePrecision()
+	value = roundScore(value, precision)
+	if value == math.Trunc(value) {
+		return strconv.AppendFloat(nil, value, 'f', 0, 64), nil
+	}
+	return strconv.AppendFloat(nil, value, 'f', precision, 64), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (s *Score) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*s = Score(value)
+	return nil
+}
+
+// roundScore 按小数位数四舍五入
+func roundScore(value float64, precision int) float64 {
+	factor := math.Pow10(precision)
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// withScorePrecision 临时修改分值精度
+func withScorePrecision(t *testing.T, precision int) {
+	t.Helper()
+	old := ScorePrecision()
+	SetScorePrecision(precision)
+	t.Cleanup(func() { SetScorePrecision(old) })
+}
+
+func TestScore_MarshalJSON(t *testing.T) {
+	withScorePrecision(t, DefaultScorePrecision)
+
+	tests := []struct {
+		name  string
+		score Score
+		want  string
+	}{
+		{name: "整数不带小数点", score: 100, want: "100"},
+		{name: "浮点误差的整数", score: Score(0.1 + 0.2 + 99.7), want: "100"},
+		{name: "小数按精度补齐", score: 88.5, want: "88.50"},
+		{name: "小数按精度舍入", score: 66.666666, want: "66.67"},
+		{name: "负数", score: -12.345, want: "-12.35"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.score)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal(%v) = %s, want %s", float64(tt.score), data, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore_ConfiguredPrecision(t *testing.T) {
+	withScorePrecision(t, 3)
+
+	payload := struct {
+		WeightedConfidence Score `json:"weightedConfidence"`
+		AverageScore       Score `json:"average_score"`
+	}{WeightedConfidence: 88.88888, AverageScore: 120}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"weightedConfidence":88.889,"average_score":120}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded struct {
+		WeightedConfidence Score `json:"weightedConfidence"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.WeightedConfidence != 88.889 {
+		t.Errorf("Unmarshal() = %v, %v", decoded.WeightedConfidence, err)
+	}
+}
+
+func TestNewScore_RoundsRedisScores(t *testing.T) {
+	withScorePrecision(t, DefaultScorePrecision)
+
+	// ZINCRBYFLOAT 累加小数后常见的浮点误差
+	if got := NewScore(0.1 + 0.2); got != 0.3 {
+		t.Errorf("NewScore(0.1+0.2) = %v, want 0.3", float64(got))
+	}
+	if got := NewScore(1530); got != 1530 {
+		t.Errorf("NewScore(1530) = %v, want 1530", float64(got))
+	}
+
+	SetScorePrecision(10)
+	if ScorePrecision() != maxScorePrecision {
+		t.Errorf("超出上限的精度应按上限处理, got %d", ScorePrecision())
+	}
+}
@@ -1,7 +1,10 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,6 +18,7 @@ type JWTService interface {
 	RefreshTokenWithUserInfo(refreshToken string, username string, role string) (*TokenPair, error)
 	GenerateAccessToken(userID uint, username string, role string) (string, error)
 	GenerateRefreshToken(userID uint) (string, error)
+	GenerateImpersonationToken(userID uint, username string, role string, impersonatorID uint, ttl time.Duration) (string, error)
 }
 
 // Claims JWT 声明
@@ -23,6 +27,8 @@ type Claims struct {
 	Username string `json:"username"`
 	Role     string `json:"role"`
 	Type     string `json:"type"` // "access" or "refresh"
+	// ImpersonatorID 代入令牌中发起代入的管理员ID，普通令牌为 0
+	ImpersonatorID uint `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -34,40 +40,77 @@ type TokenPair struct {
 	RefreshIn    int64  `json:"refresh_in"` // 刷新令牌过期时间（秒）
 }
 
+// ErrUnknownKeyID 令牌头中的 kid 不在密钥集中
+var ErrUnknownKeyID = errors.New("unknown signing key id")
+
 // jwtService JWT 服务实现
 type jwtService struct {
-	secretKey       []byte
-	accessTokenTTL  time.Duration
-	refreshTokenTTL time.Duration
-	issuer          string
+	secretKey        []byte
+	keyID            string
+	verificationKeys map[string][]byte // kid -> 密钥，包含当前密钥和已轮换的旧密钥
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+	issuer           string
 }
 
 // Config JWT 配置
 type Config struct {
 	SecretKey       string        `mapstructure:"secret_key"`
+	KeyID           string        `mapstructure:"key_id"`        // 当前签名密钥ID，写入令牌头 kid
+	PreviousKeys    []Key         `mapstructure:"previous_keys"` // 已轮换的旧密钥，仅用于验证
 	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
 	Issuer          string        `mapstructure:"issuer"`
 }
 
+// Key 带ID的签名密钥
+type Key struct {
+	ID     string `mapstructure:"id"`
+	Secret string `mapstructure:"secret"`
+}
+
 // NewJWTService 创建 JWT 服务
+//
+// 新令牌始终使用当前密钥签名；验证时按令牌头 kid 选择密钥，
+// 没有 kid 的令牌（启用轮换前签发）依次尝试当前密钥和旧密钥。
 func NewJWTService(config Config) JWTService {
+	verificationKeys := make(map[string][]byte, len(config.PreviousKeys)+1)
+	for _, key := range config.PreviousKeys {
+		verificationKeys[key.ID] = []byte(key.Secret)
+	}
+	if config.KeyID != "" {
+		verificationKeys[config.KeyID] = []byte(config.SecretKey)
+	}
+
 	return &jwtService{
-		secretKey:       []byte(config.SecretKey),
-		accessTokenTTL:  config.AccessTokenTTL,
-		refreshTokenTTL: config.RefreshTokenTTL,
-		issuer:          config.Issuer,
+		secretKey:        []byte(config.SecretKey),
+		keyID:            config.KeyID,
+		verificationKeys: verificationKeys,
+		accessTokenTTL:   config.AccessTokenTTL,
+		refreshTokenTTL:  config.RefreshTokenTTL,
+		issuer:           config.Issuer,
 	}
 }
 
 // GenerateToken 生成令牌对
+//
+// 每次登录生成新的会话ID，作为访问令牌与刷新令牌共同的 jti。
 func (j *jwtService) GenerateToken(userID uint, username string, role string) (*TokenPair, error) {
-	accessToken, err := j.GenerateAccessToken(userID, username, role)
+	sessionID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return j.generateTokenPair(userID, username, role, sessionID)
+}
+
+// generateTokenPair 生成属于同一会话的令牌对
+func (j *jwtService) generateTokenPair(userID uint, username string, role string, sessionID string) (*TokenPair, error) {
+	accessToken, err := j.generateAccessToken(userID, username, role, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := j.GenerateRefreshToken(userID)
+	refreshToken, err := j.generateRefreshToken(userID, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +125,14 @@ func (j *jwtService) GenerateToken(userID uint, username string, role string) (*
 
 // GenerateAccessToken 生成访问令牌
 func (j *jwtService) GenerateAccessToken(userID uint, username string, role string) (string, error) {
+	sessionID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	return j.generateAccessToken(userID, username, role, sessionID)
+}
+
+func (j *jwtService) generateAccessToken(userID uint, username string, role string, sessionID string) (string, error) {
 	now := time.Now()
 	claims := &Claims{
 		UserID:   userID,
@@ -89,6 +140,7 @@ func (j *jwtService) GenerateAccessToken(userID uint, username string, role stri
 		Role:     role,
 		Type:     "access",
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			Issuer:    j.issuer,
 			Subject:   username,
 			Audience:  []string{"prediction-system"},
@@ -98,17 +150,53 @@ func (j *jwtService) GenerateAccessToken(userID uint, username string, role stri
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretKey)
+	return j.sign(claims)
+}
+
+// GenerateImpersonationToken 生成管理员代入用户的访问令牌
+//
+// 令牌以被代入用户的身份认证，同时记录发起代入的管理员ID；有效期由调用方指定，不签发刷新令牌。
+func (j *jwtService) GenerateImpersonationToken(userID uint, username string, role string, impersonatorID uint, ttl time.Duration) (string, error) {
+	if impersonatorID == 0 {
+		return "", errors.New("impersonator ID is required")
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		UserID:         userID,
+		Username:       username,
+		Role:           role,
+		Type:           "access",
+		ImpersonatorID: impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   username,
+			Audience:  []string{"prediction-system"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return j.sign(claims)
 }
 
 // GenerateRefreshToken 生成刷新令牌
 func (j *jwtService) GenerateRefreshToken(userID uint) (string, error) {
+	sessionID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	return j.generateRefreshToken(userID, sessionID)
+}
+
+func (j *jwtService) generateRefreshToken(userID uint, sessionID string) (string, error) {
 	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		Type:   "refresh",
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			Issuer:    j.issuer,
 			Audience:  []string{"prediction-system"},
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.refreshTokenTTL)),
@@ -117,8 +205,7 @@ func (j *jwtService) GenerateRefreshToken(userID uint) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretKey)
+	return j.sign(claims)
 }
 
 // ValidateToken 验证令牌
@@ -128,7 +215,7 @@ func (j *jwtService) ValidateToken(tokenString string) (*Claims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return j.secretKey, nil
+		return j.verificationKey(token)
 	})
 
 	if err != nil {
@@ -147,6 +234,39 @@ func (j *jwtService) ValidateToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// sign 使用当前密钥签名，配置了密钥ID时写入 kid
+func (j *jwtService) sign(claims *Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if j.keyID != "" {
+		token.Header["kid"] = j.keyID
+	}
+	return token.SignedString(j.secretKey)
+}
+
+// verificationKey 按令牌头 kid 选择验证密钥
+func (j *jwtService) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if len(j.verificationKeys) == 0 {
+			return j.secretKey, nil
+		}
+		// 启用轮换前签发的令牌没有 kid，依次尝试当前密钥和旧密钥
+		keySet := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{j.secretKey}}
+		for id, key := range j.verificationKeys {
+			if id != j.keyID {
+				keySet.Keys = append(keySet.Keys, key)
+			}
+		}
+		return keySet, nil
+	}
+
+	key, ok := j.verificationKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+	}
+	return key, nil
+}
+
 // RefreshToken 刷新令牌
 func (j *jwtService) RefreshToken(refreshToken string) (*TokenPair, error) {
 	claims, err := j.ValidateToken(refreshToken)
@@ -176,8 +296,20 @@ func (j *jwtService) RefreshTokenWithUserInfo(refreshToken string, username stri
 		return nil, errors.New("invalid refresh token")
 	}
 
-	// 生成新的令牌对
-	return j.GenerateToken(claims.UserID, username, role)
+	// 生成新的令牌对，沿用原会话ID；旧版本签发的刷新令牌没有 jti，分配新的会话ID
+	if claims.ID == "" {
+		return j.GenerateToken(claims.UserID, username, role)
+	}
+	return j.generateTokenPair(claims.UserID, username, role, claims.ID)
+}
+
+// newTokenID 生成随机的令牌ID（jti）
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
 }
 
 // Validate 实现 Claims 验证接口
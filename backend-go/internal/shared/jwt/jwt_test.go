@@ -0,0 +1,141 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	oldSecret     = "old-secret-key-with-at-least-32-characters"
+	currentSecret = "current-secret-key-with-at-least-32-chars"
+)
+
+func newTestService(keyID string, secret string, previous ...Key) JWTService {
+	return NewJWTService(Config{
+		SecretKey:       secret,
+		KeyID:           keyID,
+		PreviousKeys:    previous,
+		AccessTokenTTL:  time.Hour,
+		RefreshTokenTTL: 24 * time.Hour,
+		Issuer:          "test",
+	})
+}
+
+func TestValidateToken_AcceptsTokenSignedWithPreviousKey(t *testing.T) {
+	before := newTestService("2025-04", oldSecret)
+	token, err := before.GenerateAccessToken(1, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	// 轮换后旧密钥移入 PreviousKeys
+	after := newTestService("2025-10", currentSecret, Key{ID: "2025-04", Secret: oldSecret})
+	claims, err := after.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("旧密钥签发的令牌应在过期前仍然有效: %v", err)
+	}
+	if claims.UserID != 1 {
+		t.Errorf("UserID = %d, want 1", claims.UserID)
+	}
+}
+
+func TestGenerateToken_SignsWithCurrentKey(t *testing.T) {
+	service := newTestService("2025-10", currentSecret, Key{ID: "2025-04", Secret: oldSecret})
+	pair, err := service.GenerateToken(1, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	for _, tokenString := range []string{pair.AccessToken, pair.RefreshToken} {
+		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+			if kid := token.Header["kid"]; kid != "2025-10" {
+				t.Errorf("kid = %v, want 2025-10", kid)
+			}
+			return []byte(currentSecret), nil
+		})
+		if err != nil || !token.Valid {
+			t.Errorf("新令牌应使用当前密钥签名: %v", err)
+		}
+	}
+
+	// 不再信任旧密钥后，新令牌依然有效
+	if _, err := newTestService("2025-10", currentSecret).ValidateToken(pair.AccessToken); err != nil {
+		t.Errorf("ValidateToken() error = %v", err)
+	}
+}
+
+func TestValidateToken_RejectsUnknownKeyID(t *testing.T) {
+	unknown := newTestService("rogue", currentSecret)
+	token, _ := unknown.GenerateAccessToken(1, "alice", "user")
+
+	service := newTestService("2025-10", currentSecret, Key{ID: "2025-04", Secret: oldSecret})
+	if _, err := service.ValidateToken(token); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("未知 kid 应被拒绝, got %v", err)
+	}
+}
+
+func TestValidateToken_LegacyTokenWithoutKeyID(t *testing.T) {
+	legacy := newTestService("", oldSecret)
+	token, _ := legacy.GenerateAccessToken(1, "alice", "user")
+
+	// 首次轮换：旧密钥签发的令牌没有 kid
+	service := newTestService("2025-10", currentSecret, Key{ID: "legacy", Secret: oldSecret})
+	if _, err := service.ValidateToken(token); err != nil {
+		t.Errorf("无 kid 的旧令牌应使用旧密钥验证通过: %v", err)
+	}
+	if _, err := newTestService("2025-10", currentSecret).ValidateToken(token); err == nil {
+		t.Error("移除旧密钥后无 kid 的旧令牌应被拒绝")
+	}
+}
+
+func TestGenerateImpersonationToken(t *testing.T) {
+	service := newTestService("2025-10", currentSecret)
+	token, err := service.GenerateImpersonationToken(2, "bob", "user", 1, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken() error = %v", err)
+	}
+
+	claims, err := service.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if claims.UserID != 2 || claims.Username != "bob" || claims.ImpersonatorID != 1 || claims.Type != "access" {
+		t.Errorf("claims = %+v, want user 2 impersonated by 1", claims)
+	}
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl > 10*time.Minute {
+		t.Errorf("token ttl = %v, want <= 10m", ttl)
+	}
+
+	if _, err := service.GenerateImpersonationToken(2, "bob", "user", 0, time.Minute); err == nil {
+		t.Error("GenerateImpersonationToken() without impersonator should fail")
+	}
+}
+
+func TestGenerateToken_SessionIDSurvivesRefresh(t *testing.T) {
+	service := newTestService("", currentSecret)
+	first, err := service.GenerateToken(1, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	access, _ := service.ValidateToken(first.AccessToken)
+	refresh, _ := service.ValidateToken(first.RefreshToken)
+	if access.ID == "" || access.ID != refresh.ID {
+		t.Fatalf("jti = %q / %q, want 令牌对共享非空会话ID", access.ID, refresh.ID)
+	}
+
+	refreshed, err := service.RefreshTokenWithUserInfo(first.RefreshToken, "alice", "user")
+	if err != nil {
+		t.Fatalf("RefreshTokenWithUserInfo() error = %v", err)
+	}
+	if claims, _ := service.ValidateToken(refreshed.AccessToken); claims.ID != access.ID {
+		t.Errorf("刷新后 jti = %q, want 沿用 %q", claims.ID, access.ID)
+	}
+
+	other, _ := service.GenerateToken(1, "alice", "user")
+	if claims, _ := service.ValidateToken(other.AccessToken); claims.ID == access.ID {
+		t.Error("再次登录应生成新的会话ID")
+	}
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AllowedLevels 允许运行时设置的日志级别
+var AllowedLevels = []string{"trace", "debug", "info", "warn", "error"}
+
+// LevelStatus 当前日志级别状态
+type LevelStatus struct {
+	Level    string     `json:"level"`
+	RevertTo string     `json:"revert_to,omitempty"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+var (
+	levelMu     sync.Mutex
+	revertTimer *time.Timer
+	revertTo    string
+	revertAt    time.Time
+)
+
+// GetLevel 获取当前日志级别状态
+func GetLevel() LevelStatus {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	return levelStatusLocked()
+}
+
+// SetLevel 运行时设置日志级别
+//
+// revertAfter 大于 0 时在到期后恢复到调整前的级别；连续调整时恢复目标
+// 保持为最初的级别，避免调试级别残留。
+func SetLevel(level string, revertAfter time.Duration) (LevelStatus, error) {
+	parsed, err := parseAllowedLevel(level)
+	if err != nil {
+		return LevelStatus{}, err
+	}
+	if log == nil {
+		return LevelStatus{}, fmt.Errorf("logger not initialized")
+	}
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	previous := log.GetLevel().String()
+	if revertTimer != nil {
+		revertTimer.Stop()
+		revertTimer = nil
+		previous = revertTo
+	}
+	revertTo = ""
+
+	log.SetLevel(parsed)
+
+	if revertAfter > 0 && previous != parsed.String() {
+		revertTo = previous
+		revertAt = time.Now().Add(revertAfter)
+		var timer *time.Timer
+		timer = time.AfterFunc(revertAfter, func() {
+			levelMu.Lock()
+			defer levelMu.Unlock()
+			// 计时器已被新的设置替换
+			if revertTimer != timer {
+				return
+			}
+			target, _ := logrus.ParseLevel(revertTo)
+			revertTimer = nil
+			revertTo = ""
+			log.SetLevel(target)
+		})
+		revertTimer = timer
+	}
+
+	return levelStatusLocked(), nil
+}
+
+// parseAllowedLevel 校验并解析日志级别
+func parseAllowedLevel(level string) (logrus.Level, error) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	for _, allowed := range AllowedLevels {
+		if level == allowed {
+			return logrus.ParseLevel(level)
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q, allowed: %s", level, strings.Join(AllowedLevels, ", "))
+}
+
+func levelStatusLocked() LevelStatus {
+	status := LevelStatus{}
+	if log != nil {
+		status.Level = log.GetLevel().String()
+	}
+	if revertTimer != nil {
+		at := revertAt
+		status.RevertTo = revertTo
+		status.RevertAt = &at
+	}
+	return status
+}
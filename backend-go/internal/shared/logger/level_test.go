@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSetLevel_DebugAndBack(t *testing.T) {
+	Init("info")
+
+	if _, err := SetLevel("debug", 0); err != nil {
+		t.Fatalf("SetLevel(debug) error = %v", err)
+	}
+	if GetLogger().GetLevel() != logrus.DebugLevel {
+		t.Errorf("级别 = %v, want debug", GetLogger().GetLevel())
+	}
+
+	status, err := SetLevel("INFO", 0)
+	if err != nil {
+		t.Fatalf("SetLevel(INFO) error = %v", err)
+	}
+	if status.Level != "info" || status.RevertAt != nil {
+		t.Errorf("status = %+v, want info without revert", status)
+	}
+}
+
+func TestSetLevel_RejectsInvalidLevel(t *testing.T) {
+	Init("info")
+
+	for _, level := range []string{"verbose", "", "panic", "fatal"} {
+		if _, err := SetLevel(level, 0); err == nil {
+			t.Errorf("SetLevel(%q) 应返回错误", level)
+		}
+	}
+	if GetLogger().GetLevel() != logrus.InfoLevel {
+		t.Errorf("非法级别不应改变当前级别, got %v", GetLogger().GetLevel())
+	}
+}
+
+func TestSetLevel_AutoRevert(t *testing.T) {
+	Init("warn")
+
+	status, err := SetLevel("debug", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SetLevel(debug) error = %v", err)
+	}
+	if status.RevertTo != "warning" || status.RevertAt == nil {
+		t.Errorf("status = %+v, want revert to warning", status)
+	}
+
+	// 再次调整时恢复目标仍为最初的级别
+	if _, err := SetLevel("trace", 50*time.Millisecond); err != nil {
+		t.Fatalf("SetLevel(trace) error = %v", err)
+	}
+	if got := GetLevel().RevertTo; got != "warning" {
+		t.Errorf("RevertTo = %s, want warning", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for GetLogger().GetLevel() != logrus.WarnLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("自动恢复超时, 当前级别 = %v", GetLogger().GetLevel())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := GetLevel(); status.RevertAt != nil {
+		t.Errorf("恢复后不应再有待执行的恢复: %+v", status)
+	}
+}
@@ -12,11 +12,14 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/natefinch/lumberjack.v2"
+
+	"backend-go/pkg/ctxkeys"
 )
 
 var (
 	log           *logrus.Logger
 	defaultFields logrus.Fields
+	sampling      *SamplingHook // 未启用采样时为 nil
 )
 
 // LogConfig 日志配置
@@ -29,20 +32,10 @@ type LogConfig struct {
 	MaxAge     int    `json:"max_age"`
 	Compress   bool   `json:"compress"`
 	LocalTime  bool   `json:"local_time"`
+	// SamplingWindow 重复 warn 日志的合并窗口，<=0 表示不采样
+	SamplingWindow time.Duration `json:"sampling_window"`
 }
 
-// ContextKey 上下文键类型
-type ContextKey string
-
-const (
-	// RequestIDKey 请求ID上下文键
-	RequestIDKey ContextKey = "request_id"
-	// UserIDKey 用户ID上下文键
-	UserIDKey ContextKey = "user_id"
-	// TraceIDKey 追踪ID上下文键
-	TraceIDKey ContextKey = "trace_id"
-)
-
 // CustomFormatter 自定义格式化器
 type CustomFormatter struct {
 	logrus.JSONFormatter
@@ -85,6 +78,8 @@ func Init(level string) {
 		MaxAge:     28,
 		Compress:   true,
 		LocalTime:  true,
+
+		SamplingWindow: DefaultSamplingWindow,
 	})
 }
 
@@ -162,6 +157,12 @@ func InitWithConfig(config *LogConfig) {
 
 	log.SetOutput(output)
 
+	// Redis 故障时大量相同的 warn 日志会淹没其他信息，窗口内只输出首条并定期汇总
+	sampling = nil
+	if config.SamplingWindow > 0 {
+		sampling = InstallSampling(log, SamplingConfig{Window: config.SamplingWindow})
+	}
+
 	// 设置默认字段
 	defaultFields = logrus.Fields{
 		"service": "prediction-system",
@@ -275,13 +276,13 @@ func WithContext(ctx context.Context) *logrus.Entry {
 	}
 
 	// 从上下文中提取字段
-	if requestID := ctx.Value(RequestIDKey); requestID != nil {
+	if requestID, ok := ctxkeys.CorrelationIDFrom(ctx); ok {
 		fields["request_id"] = requestID
 	}
-	if userID := ctx.Value(UserIDKey); userID != nil {
+	if userID, ok := ctxkeys.UserIDFrom(ctx); ok {
 		fields["user_id"] = userID
 	}
-	if traceID := ctx.Value(TraceIDKey); traceID != nil {
+	if traceID, ok := ctxkeys.TraceIDFrom(ctx); ok {
 		fields["trace_id"] = traceID
 	}
 
@@ -296,6 +297,13 @@ func WithError(err error) *logrus.Entry {
 	return nil
 }
 
+// Flush 写出采样窗口内尚未输出的重复日志汇总，进程退出前调用
+func Flush() {
+	if sampling != nil {
+		sampling.Flush()
+	}
+}
+
 // GetLogger 获取日志器实例
 func GetLogger() *logrus.Logger {
 	return log
@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultSamplingWindow 重复日志的默认合并窗口
+const DefaultSamplingWindow = time.Minute
+
+// SamplingConfig 重复日志采样配置
+type SamplingConfig struct {
+	Window time.Duration  // 同一消息在窗口内只输出首条，默认 1 分钟
+	Levels []logrus.Level // 参与采样的级别，默认只采样 warn
+}
+
+// sampleState 单条消息在当前窗口内的状态
+type sampleState struct {
+	level       logrus.Level
+	windowStart time.Time
+	suppressed  int
+}
+
+// SamplingHook 按消息合并重复日志的 logrus 钩子
+//
+// logrus 钩子无法阻止条目写出，因此由钩子接管实际输出：安装后 logger 的输出设为 io.Discard，
+// 钩子使用 logger 的格式化器写入原输出。同一级别、同一消息在窗口内只写出首条，
+// 窗口结束后补写一条 "suppressed N similar messages" 汇总。
+type SamplingHook struct {
+	out       io.Writer
+	formatter logrus.Formatter
+	window    time.Duration
+	sampled   map[logrus.Level]bool
+	now       func() time.Time
+
+	mu        sync.Mutex
+	states    map[string]*sampleState
+	lastSweep time.Time
+}
+
+// NewSamplingHook 创建采样钩子，out 与 formatter 为日志原本的输出与格式化器
+func NewSamplingHook(out io.Writer, formatter logrus.Formatter, config SamplingConfig) *SamplingHook {
+	if config.Window <= 0 {
+		config.Window = DefaultSamplingWindow
+	}
+	if len(config.Levels) == 0 {
+		config.Levels = []logrus.Level{logrus.WarnLevel}
+	}
+
+	sampled := make(map[logrus.Level]bool, len(config.Levels))
+	for _, level := range config.Levels {
+		sampled[level] = true
+	}
+	return &SamplingHook{
+		out:       out,
+		formatter: formatter,
+		window:    config.Window,
+		sampled:   sampled,
+		now:       time.Now,
+		states:    make(map[string]*sampleState),
+	}
+}
+
+// InstallSampling 为 logger 安装采样钩子并接管其输出
+func InstallSampling(l *logrus.Logger, config SamplingConfig) *SamplingHook {
+	hook := NewSamplingHook(l.Out, l.Formatter, config)
+	l.AddHook(hook)
+	l.SetOutput(io.Discard)
+	return hook
+}
+
+// Levels 钩子接管全部级别的输出
+func (h *SamplingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire 写出日志条目，窗口内重复的采样级别消息只计数
+func (h *SamplingHook) Fire(entry *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	if now.Sub(h.lastSweep) >= h.window {
+		h.sweep(now, entry.Logger)
+		h.lastSweep = now
+	}
+
+	if h.sampled[entry.Level] {
+		key := entry.Level.String() + "|" + entry.Message
+		if state, ok := h.states[key]; ok {
+			if now.Sub(state.windowStart) < h.window {
+				state.suppressed++
+				return nil
+			}
+			h.writeSummary(entry.Logger, entry.Message, state)
+		}
+		h.states[key] = &sampleState{level: entry.Level, windowStart: now}
+	}
+
+	return h.write(entry)
+}
+
+// Flush 写出所有未输出的汇总，用于退出前
+func (h *SamplingHook) Flush() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, state := range h.states {
+		h.writeSummary(nil, messageOf(key, state), state)
+		delete(h.states, key)
+	}
+}
+
+// sweep 清理已结束的窗口并补写汇总，避免消息只出现一批后汇总一直不输出
+func (h *SamplingHook) sweep(now time.Time, l *logrus.Logger) {
+	for key, state := range h.states {
+		if now.Sub(state.windowStart) < h.window {
+			continue
+		}
+		h.writeSummary(l, messageOf(key, state), state)
+		delete(h.states, key)
+	}
+}
+
+// writeSummary 有被合并的消息时写出汇总
+func (h *SamplingHook) writeSummary(l *logrus.Logger, message string, state *sampleState) {
+	if state.suppressed == 0 {
+		return
+	}
+	if l == nil {
+		l = logrus.StandardLogger()
+	}
+
+	summary := logrus.NewEntry(l).WithFields(logrus.Fields{
+		"sampled_message": message,
+		"suppressed":      state.suppressed,
+		"window":          h.window.String(),
+	})
+	summary.Time = h.now()
+	summary.Level = state.level
+	summary.Message = fmt.Sprintf("suppressed %d similar messages", state.suppressed)
+	_ = h.write(summary)
+	state.suppressed = 0
+}
+
+func (h *SamplingHook) write(entry *logrus.Entry) error {
+	data, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.out.Write(data)
+	return err
+}
+
+// messageOf 从状态键中取回原始消息
+func messageOf(key string, state *sampleState) string {
+	return key[len(state.level.String())+1:]
+}
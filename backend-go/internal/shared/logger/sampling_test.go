@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newSampledLogger 创建使用文本格式、可控时钟的采样日志器
+func newSampledLogger(window time.Duration) (*logrus.Logger, *SamplingHook, *bytes.Buffer, *time.Time) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	hook := InstallSampling(l, SamplingConfig{Window: window})
+	hook.now = func() time.Time { return now }
+	return l, hook, &buf, &now
+}
+
+func logLines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestSamplingHook_CoalescesRepeatedWarnings(t *testing.T) {
+	l, _, buf, now := newSampledLogger(time.Minute)
+
+	for i := 0; i < 100; i++ {
+		l.Warn("Failed to increment daily leaderboard view count")
+	}
+	if lines := logLines(buf); len(lines) != 1 || !strings.Contains(lines[0], "Failed to increment") {
+		t.Fatalf("窗口内只应输出首条，实际:\n%s", buf.String())
+	}
+
+	// 窗口结束后再次出现：先输出汇总，再输出新一条
+	*now = now.Add(time.Minute)
+	l.Warn("Failed to increment daily leaderboard view count")
+	lines := logLines(buf)
+	if len(lines) != 3 {
+		t.Fatalf("lines = %d, want 3:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[1], "suppressed 99 similar messages") ||
+		!strings.Contains(lines[1], `sampled_message="Failed to increment daily leaderboard view count"`) ||
+		!strings.Contains(lines[1], "level=warning") {
+		t.Errorf("汇总行 = %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "Failed to increment") {
+		t.Errorf("新窗口首条 = %s", lines[2])
+	}
+}
+
+func TestSamplingHook_PeriodicSummaryWithoutRepeat(t *testing.T) {
+	l, _, buf, now := newSampledLogger(time.Minute)
+
+	for i := 0; i < 10; i++ {
+		l.Warn("Failed to increment match view count")
+	}
+	// 该消息不再出现，其他日志写出时补写汇总
+	*now = now.Add(2 * time.Minute)
+	l.Info("worker heartbeat")
+
+	lines := logLines(buf)
+	if len(lines) != 3 || !strings.Contains(lines[1], "suppressed 9 similar messages") || !strings.Contains(lines[2], "worker heartbeat") {
+		t.Errorf("输出:\n%s", buf.String())
+	}
+}
+
+func TestSamplingHook_OnlySamplesIdenticalWarnings(t *testing.T) {
+	l, _, buf, _ := newSampledLogger(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		l.Warn("Failed to increment page view count")
+		l.Warn("Failed to increment daily page view count")
+		l.Info("Page view statistics updated")
+		l.Error("Redis connection refused")
+	}
+
+	counts := map[string]int{}
+	for _, line := range logLines(buf) {
+		for _, msg := range []string{`"Failed to increment page view count"`, `"Failed to increment daily page view count"`, `"Page view statistics updated"`, `"Redis connection refused"`} {
+			if strings.Contains(line, msg) {
+				counts[msg]++
+			}
+		}
+	}
+	want := map[string]int{
+		`"Failed to increment page view count"`:       1,
+		`"Failed to increment daily page view count"`: 1,
+		`"Page view statistics updated"`:              3,
+		`"Redis connection refused"`:                  3,
+	}
+	for msg, n := range want {
+		if counts[msg] != n {
+			t.Errorf("%s 输出 %d 次, want %d", msg, counts[msg], n)
+		}
+	}
+}
+
+func TestSamplingHook_FlushWritesPendingSummaries(t *testing.T) {
+	l, hook, buf, _ := newSampledLogger(time.Minute)
+
+	for i := 0; i < 5; i++ {
+		l.Warn("Failed to increment prediction count")
+	}
+	hook.Flush()
+
+	lines := logLines(buf)
+	if len(lines) != 2 || !strings.Contains(lines[1], "suppressed 4 similar messages") {
+		t.Errorf("输出:\n%s", buf.String())
+	}
+}
+
+func TestFlush_WritesSummariesOfInstalledSampling(t *testing.T) {
+	InitWithConfig(&LogConfig{Level: "warn", Format: "text", Output: "stdout", SamplingWindow: time.Minute})
+	var buf bytes.Buffer
+	sampling.out = &buf
+
+	for i := 0; i < 3; i++ {
+		Warn("Redis unavailable")
+	}
+	Flush()
+
+	if !strings.Contains(buf.String(), "suppressed 2 similar messages") {
+		t.Errorf("退出前应写出被合并日志的汇总, 输出:\n%s", buf.String())
+	}
+}
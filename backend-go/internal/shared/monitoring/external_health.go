@@ -0,0 +1,329 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/pkg/breaker"
+	"backend-go/pkg/mailer"
+	"backend-go/pkg/middleware"
+)
+
+// 外部依赖名称
+const (
+	ExternalEmail             = "email"
+	ExternalFileStorage       = "file_storage"
+	ExternalMonitoringMetrics = "monitoring_metrics"
+	ExternalMonitoringTracing = "monitoring_tracing"
+)
+
+// emptyPayloadHash 空请求体的 SHA-256，用于 S3 签名
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// CheckResult 单个外部依赖的检查结果
+type CheckResult struct {
+	Status   middleware.HealthStatus `json:"status"`
+	Target   string                  `json:"target"`
+	Message  string                  `json:"message"`
+	Duration time.Duration           `json:"duration"`
+}
+
+// ExternalHealthChecker 外部依赖（SMTP、S3/MinIO、监控端点）健康检查器，未启用的依赖不检查
+type ExternalHealthChecker struct {
+	config   config.ExternalConfig
+	timeout  time.Duration
+	client   *http.Client
+	breakers []*breaker.Breaker
+}
+
+// NewExternalHealthChecker 创建外部依赖健康检查器
+func NewExternalHealthChecker(cfg config.ExternalConfig, timeout time.Duration) *ExternalHealthChecker {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return &ExternalHealthChecker{
+		config:  cfg,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// AddCircuitBreakers 在检查结果中附带外部服务熔断器状态，任一熔断器打开时降级
+func (h *ExternalHealthChecker) AddCircuitBreakers(breakers ...*breaker.Breaker) {
+	h.breakers = append(h.breakers, breakers...)
+}
+
+// Name 返回检查器名称
+func (h *ExternalHealthChecker) Name() string {
+	return "external"
+}
+
+// Check 汇总外部依赖检查结果；外部依赖不可达只降级，不影响就绪状态
+func (h *ExternalHealthChecker) Check(ctx context.Context) middleware.ComponentHealth {
+	start := time.Now()
+	results := h.CheckAll(ctx)
+
+	status := middleware.HealthStatusHealthy
+	message := "External dependencies are healthy"
+	details := make(map[string]interface{}, len(results))
+	var failing []string
+	for name, result := range results {
+		details[name] = result
+		if result.Status != middleware.HealthStatusHealthy {
+			failing = append(failing, name)
+		}
+	}
+
+	if len(results) == 0 {
+		message = "No external dependencies enabled"
+	} else if len(failing) > 0 {
+		status = middleware.HealthStatusDegraded
+		message = fmt.Sprintf("%d of %d external dependencies unreachable", len(failing), len(results))
+	}
+
+	if len(h.breakers) > 0 {
+		breakers := make([]breaker.Status, 0, len(h.breakers))
+		var open []string
+		for _, b := range h.breakers {
+			breakerStatus := b.Status()
+			breakers = append(breakers, breakerStatus)
+			if breakerStatus.State == breaker.StateOpen.String() {
+				open = append(open, breakerStatus.Name)
+			}
+		}
+		details["circuit_breakers"] = breakers
+		if len(open) > 0 {
+			status = middleware.HealthStatusDegraded
+			message = fmt.Sprintf("Circuit breaker open for %s", strings.Join(open, ", "))
+		}
+	}
+
+	return middleware.ComponentHealth{
+		Status:    status,
+		Message:   message,
+		Details:   details,
+		Timestamp: time.Now(),
+		Duration:  time.Since(start),
+	}
+}
+
+// CheckAll 并发检查所有已启用的外部依赖，返回按依赖名称索引的结果
+func (h *ExternalHealthChecker) CheckAll(ctx context.Context) map[string]CheckResult {
+	checks := make(map[string]func(context.Context) CheckResult)
+
+	if email := h.config.Email; email.Enabled && email.Provider == "smtp" {
+		checks[ExternalEmail] = h.checkSMTP
+	}
+	if provider := h.config.FileStorage.Provider; provider == "s3" || provider == "minio" {
+		checks[ExternalFileStorage] = h.checkBucket
+	}
+	if monitoring := h.config.Monitoring; monitoring.Enabled {
+		if monitoring.MetricsURL != "" {
+			checks[ExternalMonitoringMetrics] = h.probeURL(monitoring.MetricsURL)
+		}
+		if monitoring.TracingURL != "" {
+			checks[ExternalMonitoringTracing] = h.probeURL(monitoring.TracingURL)
+		}
+	}
+
+	results := make(map[string]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) CheckResult) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+
+			start := time.Now()
+			result := check(checkCtx)
+			result.Duration = time.Since(start)
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// checkSMTP 连接 SMTP 服务器并完成 HELO/NOOP/QUIT 往返
+func (h *ExternalHealthChecker) checkSMTP(ctx context.Context) CheckResult {
+	email := h.config.Email
+	addr := net.JoinHostPort(email.Host, strconv.Itoa(email.Port))
+
+	client, err := mailer.Dial(ctx, email.Host, email.Port)
+	if err != nil {
+		return unhealthy(addr, "SMTP %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return unhealthy(addr, "SMTP HELO failed: %v", err)
+	}
+	if err := client.Noop(); err != nil {
+		return unhealthy(addr, "SMTP NOOP failed: %v", err)
+	}
+	_ = client.Quit()
+
+	return CheckResult{Status: middleware.HealthStatusHealthy, Target: addr, Message: "SMTP server is reachable"}
+}
+
+// checkBucket 以签名的 HEAD Bucket 请求验证存储桶可访问
+func (h *ExternalHealthChecker) checkBucket(ctx context.Context) CheckResult {
+	s3 := h.config.FileStorage.S3Config
+	u, err := bucketURL(s3)
+	if err != nil {
+		return unhealthy(s3.Bucket, "invalid storage endpoint: %v", err)
+	}
+	target := u.String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return unhealthy(target, "failed to build bucket request: %v", err)
+	}
+	if s3.AccessKey != "" {
+		signS3Request(req, s3, time.Now())
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return unhealthy(target, "bucket request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return CheckResult{Status: middleware.HealthStatusHealthy, Target: target, Message: "Bucket is accessible"}
+	case http.StatusForbidden:
+		return unhealthy(target, "bucket access denied (HTTP %d)", resp.StatusCode)
+	case http.StatusNotFound:
+		return unhealthy(target, "bucket not found (HTTP %d)", resp.StatusCode)
+	default:
+		return unhealthy(target, "unexpected bucket response (HTTP %d)", resp.StatusCode)
+	}
+}
+
+// probeURL 返回探测监控端点的检查函数，收到 5xx 以外的响应即视为可达
+func (h *ExternalHealthChecker) probeURL(target string) func(context.Context) CheckResult {
+	return func(ctx context.Context) CheckResult {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return unhealthy(target, "invalid URL: %v", err)
+		}
+
+		resp, err := h.client.Do(req)
+		if err != nil {
+			return unhealthy(target, "request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return unhealthy(target, "endpoint returned HTTP %d", resp.StatusCode)
+		}
+		return CheckResult{
+			Status:  middleware.HealthStatusHealthy,
+			Target:  target,
+			Message: fmt.Sprintf("Endpoint is reachable (HTTP %d)", resp.StatusCode),
+		}
+	}
+}
+
+// unhealthy 构造不健康的检查结果
+func unhealthy(target, format string, args ...interface{}) CheckResult {
+	return CheckResult{
+		Status:  middleware.HealthStatusUnhealthy,
+		Target:  target,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// bucketURL 以路径风格拼接存储桶地址，兼容 MinIO；未配置 endpoint 时使用 AWS 区域域名
+func bucketURL(s3 config.S3Config) (*url.URL, error) {
+	endpoint := s3.Endpoint
+	if endpoint == "" {
+		endpoint = "s3." + s3Region(s3) + ".amazonaws.com"
+	}
+	if !strings.Contains(endpoint, "://") {
+		scheme := "http"
+		if s3.UseSSL {
+			scheme = "https"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s3.Bucket
+	return u, nil
+}
+
+// s3Region 返回签名使用的区域，MinIO 未配置时默认 us-east-1
+func s3Region(s3 config.S3Config) string {
+	if s3.Region == "" {
+		return "us-east-1"
+	}
+	return s3.Region
+}
+
+// signS3Request 按 AWS Signature Version 4 为无请求体的请求签名
+func signS3Request(req *http.Request, s3 config.S3Config, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	region := s3Region(s3)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + emptyPayloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := dateStamp + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s3.SecretKey), dateStamp)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
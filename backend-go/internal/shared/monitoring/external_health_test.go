@@ -0,0 +1,163 @@
+package monitoring
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/pkg/breaker"
+	"backend-go/pkg/middleware"
+)
+
+// startFakeSMTP 启动只应答 EHLO/NOOP/QUIT 的 SMTP 服务，返回主机与端口
+func startFakeSMTP(t *testing.T) (string, int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte("220 fake ESMTP\r\n"))
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					switch strings.ToUpper(strings.Fields(line)[0]) {
+					case "QUIT":
+						conn.Write([]byte("221 bye\r\n"))
+						return
+					default:
+						conn.Write([]byte("250 OK\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// startFakeS3 启动仅存在 assets 存储桶且要求 SigV4 签名的 S3 服务
+func startFakeS3(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodHead || r.URL.Path != "/assets" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// closedPort 返回一个已释放、无服务监听的端口
+func closedPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	return port
+}
+
+func TestExternalHealthChecker_ReachableDependenciesHealthy(t *testing.T) {
+	host, port := startFakeSMTP(t)
+	cfg := config.ExternalConfig{
+		Email: config.EmailConfig{Enabled: true, Provider: "smtp", Host: host, Port: port},
+		FileStorage: config.FileStorageConfig{Provider: "minio", S3Config: config.S3Config{
+			Bucket: "assets", AccessKey: "minio", SecretKey: "secret", Endpoint: startFakeS3(t),
+		}},
+	}
+
+	checker := NewExternalHealthChecker(cfg, 2*time.Second)
+	results := checker.CheckAll(context.Background())
+
+	if len(results) != 2 {
+		t.Fatalf("results = %v, want email and file_storage only", results)
+	}
+	for _, name := range []string{ExternalEmail, ExternalFileStorage} {
+		if results[name].Status != middleware.HealthStatusHealthy {
+			t.Errorf("%s = %+v, want healthy", name, results[name])
+		}
+	}
+	if health := checker.Check(context.Background()); health.Status != middleware.HealthStatusHealthy {
+		t.Errorf("Check() = %+v, want healthy", health)
+	}
+}
+
+func TestExternalHealthChecker_DownDependencyUnhealthy(t *testing.T) {
+	cfg := config.ExternalConfig{
+		Email: config.EmailConfig{Enabled: true, Provider: "smtp", Host: "127.0.0.1", Port: closedPort(t)},
+		FileStorage: config.FileStorageConfig{Provider: "s3", S3Config: config.S3Config{
+			Bucket: "missing", AccessKey: "minio", SecretKey: "secret", Endpoint: startFakeS3(t),
+		}},
+		// 监控未启用，即使配置了地址也不检查
+		Monitoring: config.MonitoringConfig{MetricsURL: "http://127.0.0.1:" + strconv.Itoa(closedPort(t))},
+	}
+
+	checker := NewExternalHealthChecker(cfg, 2*time.Second)
+	results := checker.CheckAll(context.Background())
+
+	if _, ok := results[ExternalMonitoringMetrics]; ok {
+		t.Error("未启用的监控端点不应检查")
+	}
+	if email := results[ExternalEmail]; email.Status != middleware.HealthStatusUnhealthy || !strings.Contains(email.Message, "SMTP dial failed") {
+		t.Errorf("email = %+v, want unhealthy with dial error", email)
+	}
+	if storage := results[ExternalFileStorage]; storage.Status != middleware.HealthStatusUnhealthy || !strings.Contains(storage.Message, "bucket not found") {
+		t.Errorf("file_storage = %+v, want unhealthy bucket not found", storage)
+	}
+
+	health := checker.Check(context.Background())
+	if health.Status != middleware.HealthStatusDegraded {
+		t.Errorf("Check() status = %s, want degraded", health.Status)
+	}
+	if _, ok := health.Details[ExternalEmail].(CheckResult); !ok {
+		t.Errorf("Check() details = %v, want per-dependency results", health.Details)
+	}
+}
+
+func TestExternalHealthChecker_ReportsOpenCircuitBreaker(t *testing.T) {
+	email := breaker.New(ExternalEmail, breaker.Config{FailureThreshold: 1, Cooldown: time.Minute})
+	storage := breaker.New(ExternalFileStorage, breaker.Config{FailureThreshold: 1, Cooldown: time.Minute})
+	email.Execute(context.Background(), func(ctx context.Context) error { return errors.New("smtp down") })
+
+	checker := NewExternalHealthChecker(config.ExternalConfig{}, time.Second)
+	checker.AddCircuitBreakers(email, storage)
+	health := checker.Check(context.Background())
+
+	if health.Status != middleware.HealthStatusDegraded || !strings.Contains(health.Message, ExternalEmail) {
+		t.Errorf("Check() = %s %q, want degraded naming the open breaker", health.Status, health.Message)
+	}
+	statuses, ok := health.Details["circuit_breakers"].([]breaker.Status)
+	if !ok || len(statuses) != 2 || statuses[0].State != "open" || statuses[1].State != "closed" {
+		t.Errorf("circuit_breakers = %+v, want email open and file_storage closed", health.Details["circuit_breakers"])
+	}
+}
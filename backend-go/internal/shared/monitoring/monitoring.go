@@ -6,6 +6,7 @@ import (
 
 	"backend-go/internal/config"
 	"backend-go/internal/shared/logger"
+	"backend-go/pkg/breaker"
 	"backend-go/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -19,15 +20,34 @@ type MonitoringService struct {
 	config          *config.Config
 	healthService   *middleware.HealthService
 	businessMetrics *middleware.BusinessMetrics
+	sloTracker      *middleware.SLOTracker
+	probeState      *middleware.ProbeState
+	breakers        []*breaker.Breaker
 }
 
 // NewMonitoringService 创建监控服务
 func NewMonitoringService(cfg *config.Config) *MonitoringService {
-	return &MonitoringService{
+	s := &MonitoringService{
 		config:          cfg,
 		healthService:   middleware.NewHealthService("1.0.0", cfg.External.Monitoring.HealthCheck.Timeout),
 		businessMetrics: middleware.GetBusinessMetrics(),
+		probeState:      middleware.NewProbeState(),
 	}
+	s.healthService.SetCacheTTL(cfg.External.Monitoring.HealthCheck.CacheTTL)
+
+	if slo := cfg.External.Monitoring.SLO; slo.Enabled {
+		s.sloTracker = middleware.NewSLOTracker(middleware.SLOConfig{
+			Target:     slo.Target,
+			Window:     slo.Window,
+			MaxSamples: slo.MaxSamples,
+		})
+	}
+	return s
+}
+
+// SetCircuitBreakers 设置外部服务熔断器，需在 Initialize 之前调用，状态随外部依赖健康检查返回
+func (s *MonitoringService) SetCircuitBreakers(breakers []*breaker.Breaker) {
+	s.breakers = breakers
 }
 
 // Initialize 初始化监控服务
@@ -52,6 +72,12 @@ func (s *MonitoringService) Initialize(db *gorm.DB, redisClient redis.UniversalC
 	s.healthService.AddChecker(memoryChecker)
 	logger.Info("Added memory health checker")
 
+	// 外部依赖检查器，仅检查已启用的邮件、文件存储与监控端点
+	externalChecker := NewExternalHealthChecker(s.config.External, s.config.External.Monitoring.HealthCheck.Timeout)
+	externalChecker.AddCircuitBreakers(s.breakers...)
+	s.healthService.AddChecker(externalChecker)
+	logger.Info("Added external dependencies health checker")
+
 	logger.Info("Monitoring service initialized successfully")
 	return nil
 }
@@ -73,6 +99,8 @@ func (s *MonitoringService) SetupMiddleware(router *gin.Engine) {
 			"/health/detailed",
 			"/ready",
 			"/live",
+			middleware.LivezPath,
+			middleware.ReadyzPath,
 			"/metrics",
 			"/favicon.ico",
 		},
@@ -128,29 +156,40 @@ func (s *MonitoringService) SetupRoutes(router *gin.Engine) {
 		logger.Infof("Prometheus metrics available at %s", s.config.External.Monitoring.Prometheus.Path)
 	}
 
+	// Kubernetes 探针端点，不受 health_check.enabled 影响
+	router.GET(middleware.LivezPath, middleware.LivezHandler())
+	router.GET(middleware.ReadyzPath, middleware.ReadyzHandler(s.healthService, s.probeState))
+
 	// 健康检查端点已通过中间件处�?
 	logger.Info("Health check endpoints:")
 	logger.Info("  - /health - Simple health check")
-	logger.Info("  - /health/detailed - Detailed health check")
+	logger.Info("  - /health/detailed - Detailed health check (cached, ?fresh=true to recheck at most once per second)")
 	logger.Info("  - /health/metrics - Health metrics")
 	logger.Info("  - /ready - Readiness probe")
 	logger.Info("  - /live - Liveness probe")
+	logger.Info("  - /livez - Kubernetes liveness probe")
+	logger.Info("  - /readyz - Kubernetes readiness probe")
 
 	logger.Info("Monitoring routes setup completed")
 }
 
-// StartupProbe 执行启动探针
+// StartupProbe 执行启动探针，通过后 /readyz 才会返回就绪
 func (s *MonitoringService) StartupProbe() error {
 	if !s.config.External.Monitoring.HealthCheck.Enabled {
 		logger.Info("Health check disabled, skipping startup probe")
+		s.probeState.MarkStarted()
 		return nil
 	}
 
-	return middleware.StartupProbe(
+	if err := middleware.StartupProbe(
 		s.healthService,
 		s.config.External.Monitoring.HealthCheck.StartupRetries,
 		s.config.External.Monitoring.HealthCheck.StartupInterval,
-	)
+	); err != nil {
+		return err
+	}
+	s.probeState.MarkStarted()
+	return nil
 }
 
 // GetHealthService 获取健康检查服务
@@ -158,6 +197,16 @@ func (s *MonitoringService) GetHealthService() *middleware.HealthService {
 	return s.healthService
 }
 
+// GetProbeState 获取就绪探针状态
+func (s *MonitoringService) GetProbeState() *middleware.ProbeState {
+	return s.probeState
+}
+
+// GetSLOTracker 获取接口延迟 SLO 追踪器，未启用时返回 nil
+func (s *MonitoringService) GetSLOTracker() *middleware.SLOTracker {
+	return s.sloTracker
+}
+
 // GetBusinessMetrics 获取业务指标
 func (s *MonitoringService) GetBusinessMetrics() *middleware.BusinessMetrics {
 	return s.businessMetrics
@@ -0,0 +1,2 @@
+-- 删除预测版本号
+ALTER TABLE predictions DROP COLUMN version;
@@ -0,0 +1,3 @@
+-- 为预测表添加乐观锁版本号，防止并发修改覆盖
+ALTER TABLE predictions
+ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 COMMENT '乐观锁版本号' AFTER modification_count;
@@ -0,0 +1,2 @@
+-- 删除用户通知偏好表
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,15 @@
+-- 创建用户通知偏好表，默认全部开启并提前 30 分钟提醒
+CREATE TABLE notification_preferences (
+    user_id BIGINT UNSIGNED PRIMARY KEY COMMENT '用户ID',
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE COMMENT '是否接收邮件（比赛提醒、密码重置）',
+    reminder_lead_minutes INT NOT NULL DEFAULT 30 COMMENT '比赛开始前多少分钟提醒',
+    push_enabled BOOLEAN NOT NULL DEFAULT TRUE COMMENT '是否接收实时推送',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户通知偏好表';
+
+-- 为已有用户写入默认偏好
+INSERT INTO notification_preferences (user_id)
+SELECT id FROM users;
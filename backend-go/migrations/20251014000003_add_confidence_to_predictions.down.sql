@@ -0,0 +1,8 @@
+-- 删除积分规则信心倍数开关
+ALTER TABLE scoring_rules
+DROP COLUMN confidence_risk_points,
+DROP COLUMN enable_confidence_risk,
+DROP COLUMN enable_confidence;
+
+-- 删除预测信心倍数
+ALTER TABLE predictions DROP COLUMN confidence;
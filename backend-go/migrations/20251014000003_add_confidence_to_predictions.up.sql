@@ -0,0 +1,9 @@
+-- 为预测表添加信心倍数，积分规则启用时放大得分
+ALTER TABLE predictions
+ADD COLUMN confidence TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '信心倍数（1-3）';
+
+-- 为积分规则添加信心倍数开关，默认关闭
+ALTER TABLE scoring_rules
+ADD COLUMN enable_confidence BOOLEAN DEFAULT FALSE COMMENT '是否启用信心倍数',
+ADD COLUMN enable_confidence_risk BOOLEAN DEFAULT FALSE COMMENT '是否启用信心风险扣分',
+ADD COLUMN confidence_risk_points INT DEFAULT 0 COMMENT '每加注一倍在预测错误时扣除的积分';
@@ -0,0 +1,2 @@
+-- 删除单场预测锁定时间
+ALTER TABLE matches DROP COLUMN prediction_lock_at;
@@ -0,0 +1,3 @@
+-- 为比赛表添加单场预测锁定时间，设置后覆盖全局锁定窗口
+ALTER TABLE matches
+ADD COLUMN prediction_lock_at DATETIME NULL COMMENT '预测锁定时间（为空时使用全局锁定窗口）' AFTER start_time;
@@ -0,0 +1,2 @@
+-- 删除事件发件箱表
+DROP TABLE IF EXISTS event_outbox;
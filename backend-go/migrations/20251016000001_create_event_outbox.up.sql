@@ -0,0 +1,13 @@
+-- 创建事件发件箱表，事件与业务数据在同一事务中写入，由 worker 转发到事件总线
+CREATE TABLE event_outbox (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL COMMENT '事件类型',
+    payload TEXT NOT NULL COMMENT '事件载荷（JSON）',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '投递次数',
+    last_error TEXT NULL COMMENT '最近一次投递失败原因',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP NULL COMMENT '投递完成时间，为空表示待投递',
+
+    INDEX idx_event_outbox_event_type (event_type),
+    INDEX idx_event_outbox_dispatched_at (dispatched_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='事件发件箱表';
@@ -0,0 +1,10 @@
+-- 删除常用查询路径的复合索引
+DROP INDEX idx_predictions_user_created ON predictions;
+DROP INDEX idx_predictions_match ON predictions;
+
+DROP INDEX idx_audit_status_created ON admin_audit_logs;
+DROP INDEX idx_audit_resource_created ON admin_audit_logs;
+DROP INDEX idx_audit_action_created ON admin_audit_logs;
+DROP INDEX idx_audit_admin_created ON admin_audit_logs;
+
+DROP INDEX idx_admin_users_level_active_created ON admin_users;
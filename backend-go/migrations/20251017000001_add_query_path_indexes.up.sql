@@ -0,0 +1,15 @@
+-- 为常用查询路径添加复合索引
+
+-- 管理员列表：按级别、状态过滤并按创建时间倒序分页
+-- 用户名/邮箱/昵称使用前后模糊匹配，B-Tree 索引无法命中，不在此处理
+CREATE INDEX idx_admin_users_level_active_created ON admin_users (admin_level, is_active, created_at);
+
+-- 审计日志：按管理员、操作、资源、状态过滤并限定时间范围
+CREATE INDEX idx_audit_admin_created ON admin_audit_logs (admin_user_id, created_at);
+CREATE INDEX idx_audit_action_created ON admin_audit_logs (action, created_at);
+CREATE INDEX idx_audit_resource_created ON admin_audit_logs (resource, created_at);
+CREATE INDEX idx_audit_status_created ON admin_audit_logs (status, created_at);
+
+-- 预测：按比赛查询预测列表，按用户查询预测历史
+CREATE INDEX idx_predictions_match ON predictions (matchId);
+CREATE INDEX idx_predictions_user_created ON predictions (userId, createdAt);
@@ -0,0 +1,2 @@
+-- 删除管理员保存的查询视图表
+DROP TABLE IF EXISTS admin_saved_views;
@@ -0,0 +1,13 @@
+-- 创建管理员保存的查询视图表，同一管理员同一列表下视图名称唯一
+CREATE TABLE admin_saved_views (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    admin_user_id BIGINT UNSIGNED NOT NULL COMMENT '管理员用户ID',
+    resource VARCHAR(50) NOT NULL COMMENT '视图所属列表，如 audit_logs',
+    name VARCHAR(100) NOT NULL COMMENT '视图名称',
+    params JSON COMMENT '过滤与排序条件',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE INDEX idx_admin_saved_views_owner_name (admin_user_id, resource, name),
+    FOREIGN KEY (admin_user_id) REFERENCES admin_users(user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='管理员保存的查询视图表';
@@ -0,0 +1,2 @@
+-- 删除用户归档统计表
+DROP TABLE IF EXISTS user_archived_stats;
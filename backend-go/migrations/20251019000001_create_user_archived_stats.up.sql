@@ -0,0 +1,11 @@
+-- 创建用户归档统计表，数据保留任务清理旧比赛前将其预测与投票按用户汇总到此表
+CREATE TABLE user_archived_stats (
+    user_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    predictions BIGINT NOT NULL DEFAULT 0 COMMENT '已清理的预测数',
+    correct_predictions BIGINT NOT NULL DEFAULT 0 COMMENT '已清理的正确预测数',
+    earned_points BIGINT NOT NULL DEFAULT 0 COMMENT '已清理预测获得的积分',
+    votes_cast BIGINT NOT NULL DEFAULT 0 COMMENT '已清理的投出票数',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户归档统计表';
@@ -0,0 +1,2 @@
+-- 删除 API 密钥表
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,16 @@
+-- 创建 API 密钥表，供数据源等服务间调用认证，只保存密钥的 SHA-256 哈希
+CREATE TABLE api_keys (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '密钥名称，如调用方系统名',
+    prefix VARCHAR(16) NOT NULL COMMENT '明文密钥前缀，用于辨认',
+    key_hash VARCHAR(64) NOT NULL COMMENT '密钥 SHA-256 哈希',
+    scopes JSON COMMENT '权限范围，如 ["matches:write"]',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT UNSIGNED COMMENT '创建密钥的管理员用户ID',
+    last_used_at TIMESTAMP NULL COMMENT '最近使用时间',
+    revoked_at TIMESTAMP NULL COMMENT '吊销时间',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE INDEX idx_api_keys_key_hash (key_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API 密钥表';
@@ -0,0 +1,182 @@
+// Package breaker 为外部服务调用（邮件、对象存储）提供熔断
+//
+// 外部服务持续失败或超时时，继续调用只会让请求处理器排队等待。连续失败达到阈值后
+// 熔断器打开，之后的调用立即返回 ErrOpen；冷却时间过后放行一次试探调用（半开），
+// 成功则恢复，失败则重新打开。
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen 熔断器打开，调用未执行
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State 熔断器状态
+type State int
+
+const (
+	StateClosed   State = iota // 正常放行
+	StateOpen                  // 快速失败
+	StateHalfOpen              // 冷却结束，放行一次试探调用
+)
+
+// String 返回状态名称
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// Config 熔断配置
+type Config struct {
+	FailureThreshold int           // 连续失败多少次后打开，<=0 时使用 5
+	Cooldown         time.Duration // 打开后多久进入半开，<=0 时使用 30s
+	Timeout          time.Duration // 单次调用超时，超时计为失败，0 表示不限制
+}
+
+// Status 熔断器当前状态，用于健康检查
+type Status struct {
+	Name     string     `json:"name"`
+	State    string     `json:"state"`
+	Failures int        `json:"failures"`            // 当前连续失败次数
+	OpenedAt *time.Time `json:"opened_at,omitempty"` // 最近一次打开的时间
+	RetryAt  *time.Time `json:"retry_at,omitempty"`  // 打开状态下允许试探调用的时间
+}
+
+// Breaker 熔断器，可被多个协程共享
+type Breaker struct {
+	name   string
+	config Config
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool // 半开状态下试探调用是否在进行中
+}
+
+// New 创建熔断器，name 为外部服务名称
+func New(name string, config Config) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	return &Breaker{name: name, config: config, now: time.Now}
+}
+
+// Name 返回外部服务名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Execute 在熔断器允许时执行 fn，打开状态下不执行并返回 ErrOpen
+//
+// 调用方取消 ctx 导致的失败不计入连续失败次数。
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !b.allow() {
+		return ErrOpen
+	}
+
+	callCtx := ctx
+	if b.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, b.config.Timeout)
+		defer cancel()
+	}
+
+	err := fn(callCtx)
+	b.record(err, ctx.Err() != nil)
+	return err
+}
+
+// allow 判断是否放行本次调用，冷却结束后的第一次调用作为试探
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.config.Cooldown {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true
+	case StateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record 记录调用结果
+func (b *Breaker) record(err error, canceled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.probing = false
+	}
+	switch {
+	case err == nil:
+		b.state = StateClosed
+		b.failures = 0
+	case canceled:
+		// 调用方放弃等待，不代表外部服务失败；半开时留待下一次调用试探
+	case b.state == StateHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+}
+
+// State 返回当前状态，打开且冷却已结束时返回半开
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.config.Cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Status 返回当前状态快照
+func (b *Breaker) Status() Status {
+	state := b.State()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := Status{Name: b.name, State: state.String(), Failures: b.failures}
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+		if state == StateOpen {
+			retryAt := openedAt.Add(b.config.Cooldown)
+			status.RetryAt = &retryAt
+		}
+	}
+	return status
+}
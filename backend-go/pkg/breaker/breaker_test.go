@@ -0,0 +1,86 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("service unavailable")
+
+// newTestBreaker 创建使用可控时钟的熔断器
+func newTestBreaker(config Config) (*Breaker, *time.Time) {
+	now := time.Now()
+	b := New("test", config)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail(ctx context.Context) error { return errUnavailable }
+func succeed(ctx context.Context) error { return nil }
+
+func TestBreaker_HalfOpenAllowsSingleProbe(t *testing.T) {
+	b, now := newTestBreaker(Config{FailureThreshold: 2, Cooldown: time.Minute})
+	ctx := context.Background()
+
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	if err := b.Execute(ctx, succeed); !errors.Is(err, ErrOpen) {
+		t.Fatalf("打开状态下 err = %v, want ErrOpen", err)
+	}
+
+	*now = now.Add(time.Minute)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("冷却后 state = %s, want half_open", b.State())
+	}
+
+	// 试探调用进行中时其他调用仍快速失败
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- b.Execute(ctx, func(ctx context.Context) error {
+			close(probing)
+			<-release
+			return errUnavailable
+		})
+	}()
+	<-probing
+	if err := b.Execute(ctx, succeed); !errors.Is(err, ErrOpen) {
+		t.Errorf("试探期间 err = %v, want ErrOpen", err)
+	}
+	close(release)
+	<-done
+
+	// 试探失败后重新打开，需再等一个冷却时间
+	if status := b.Status(); status.State != "open" || status.RetryAt == nil || !status.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("试探失败后 status = %+v, want open until next cooldown", status)
+	}
+}
+
+func TestBreaker_TimeoutCountsAsFailure(t *testing.T) {
+	b, _ := newTestBreaker(Config{FailureThreshold: 1, Cooldown: time.Minute, Timeout: 10 * time.Millisecond})
+
+	err := b.Execute(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if b.State() != StateOpen {
+		t.Errorf("超时后 state = %s, want open", b.State())
+	}
+}
+
+func TestBreaker_CallerCancellationIsNotAFailure(t *testing.T) {
+	b, _ := newTestBreaker(Config{FailureThreshold: 1, Cooldown: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.Execute(ctx, func(ctx context.Context) error { return ctx.Err() })
+	if b.State() != StateClosed {
+		t.Errorf("调用方取消后 state = %s, want closed", b.State())
+	}
+}
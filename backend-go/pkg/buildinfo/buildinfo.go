@@ -0,0 +1,84 @@
+// Package buildinfo 记录构建时通过 ldflags 注入的版本信息
+//
+// 构建时注入：
+//
+//	go build -ldflags "-X backend-go/pkg/buildinfo.Version=v1.2.0 \
+//		-X backend-go/pkg/buildinfo.GitCommit=abc1234 \
+//		-X backend-go/pkg/buildinfo.BuildDate=2025-10-01T00:00:00Z" ./cmd/api
+//
+// 未注入时版本为 dev，提交号回退到 Go 工具链记录的 vcs.revision。
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// 构建时由 ldflags 注入
+var (
+	Version   string
+	GitCommit string
+	BuildDate string
+)
+
+const (
+	// DefaultVersion 未注入版本号时的值
+	DefaultVersion = "dev"
+	// Unknown 无法确定提交号或构建时间时的值
+	Unknown = "unknown"
+)
+
+// Info 运行中程序的构建信息
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get 返回构建信息，未注入的字段使用默认值
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+	if info.GitCommit == "" || info.BuildDate == "" {
+		revision, buildTime := vcsInfo()
+		if info.GitCommit == "" {
+			info.GitCommit = revision
+		}
+		if info.BuildDate == "" {
+			info.BuildDate = buildTime
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = DefaultVersion
+	}
+	if info.GitCommit == "" {
+		info.GitCommit = Unknown
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = Unknown
+	}
+	return info
+}
+
+// vcsInfo 读取 go build 嵌入的版本控制信息，go run 与测试中通常为空
+func vcsInfo() (revision, buildTime string) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			buildTime = setting.Value
+		}
+	}
+	return revision, buildTime
+}
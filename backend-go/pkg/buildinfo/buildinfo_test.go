@@ -0,0 +1,39 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+)
+
+// setBuildVars 临时设置注入变量，测试结束后恢复
+func setBuildVars(t *testing.T, version, commit, date string) {
+	t.Helper()
+	oldVersion, oldCommit, oldDate := Version, GitCommit, BuildDate
+	Version, GitCommit, BuildDate = version, commit, date
+	t.Cleanup(func() { Version, GitCommit, BuildDate = oldVersion, oldCommit, oldDate })
+}
+
+func TestGet_ReturnsInjectedValues(t *testing.T) {
+	setBuildVars(t, "v1.4.2", "abc1234", "2025-10-01T08:00:00Z")
+
+	info := Get()
+	if info.Version != "v1.4.2" || info.GitCommit != "abc1234" || info.BuildDate != "2025-10-01T08:00:00Z" {
+		t.Errorf("Get() = %+v, want 注入的版本信息", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+}
+
+func TestGet_DefaultsWhenNotInjected(t *testing.T) {
+	setBuildVars(t, "", "", "")
+
+	info := Get()
+	if info.Version != DefaultVersion {
+		t.Errorf("Version = %q, want %q", info.Version, DefaultVersion)
+	}
+	// 测试二进制不嵌入 vcs 信息
+	if info.GitCommit == "" || info.BuildDate == "" {
+		t.Errorf("Get() = %+v, 未注入的字段不应为空", info)
+	}
+}
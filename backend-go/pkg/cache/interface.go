@@ -66,4 +66,9 @@ type CacheStats struct {
 	TotalHits    int64   `json:"total_hits"`
 	TotalMisses  int64   `json:"total_misses"`
 	HitRate      float64 `json:"hit_rate"`
+
+	// 内存层容量信息（仅 LRU 内存层提供）
+	MemoryEntries   int   `json:"memory_entries,omitempty"`
+	MemoryBytes     int64 `json:"memory_bytes,omitempty"`
+	MemoryEvictions int64 `json:"memory_evictions,omitempty"`
 }
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRUCache 有容量上限的内存 LRU 缓存
+type LRUCache struct {
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List
+	maxEntries int
+	maxBytes   int64
+	usedBytes  int64
+	evictions  int64
+	now        func() time.Time
+}
+
+// lruEntry LRU 缓存项
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache 创建 LRU 缓存，maxEntries 或 maxBytes 小于等于 0 表示不限制该维度
+func NewLRUCache(maxEntries int, maxBytes int64) *LRUCache {
+	return &LRUCache{
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		now:        time.Now,
+	}
+}
+
+// Get 获取缓存值
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, ErrCacheNotFound
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if c.expired(entry) {
+		c.removeElement(elem)
+		return nil, ErrCacheNotFound
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, nil
+}
+
+// Set 设置缓存值，超出容量时淘汰最久未使用的项
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	size := entrySize(key, value)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		// 单项超过预算时不进入内存缓存
+		c.Delete(ctx, key)
+		return nil
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		c.usedBytes += size - entrySize(entry.key, entry.value)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+	} else {
+		c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+		c.usedBytes += size
+	}
+
+	c.evict()
+	return nil
+}
+
+// Delete 删除缓存
+func (c *LRUCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	return nil
+}
+
+// DeletePattern 批量删除匹配模式的缓存键
+func (c *LRUCache) DeletePattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if matchPattern(key, pattern) {
+			c.removeElement(elem)
+		}
+	}
+	return nil
+}
+
+// Exists 检查缓存是否存在
+func (c *LRUCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.Get(ctx, key)
+	return err == nil, nil
+}
+
+// Clear 清空所有缓存
+func (c *LRUCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	c.usedBytes = 0
+}
+
+// Len 获取缓存项数量
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// UsedBytes 获取已使用的字节数
+func (c *LRUCache) UsedBytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usedBytes
+}
+
+// Evictions 获取因容量淘汰的次数
+func (c *LRUCache) Evictions() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
+
+// evict 淘汰超出容量的缓存项
+func (c *LRUCache) evict() {
+	for c.overBudget() {
+		elem := c.order.Back()
+		if elem == nil {
+			return
+		}
+		c.removeElement(elem)
+		c.evictions++
+	}
+}
+
+// overBudget 检查是否超出容量
+func (c *LRUCache) overBudget() bool {
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		return true
+	}
+	return c.maxBytes > 0 && c.usedBytes > c.maxBytes
+}
+
+// removeElement 移除缓存项
+func (c *LRUCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*lruEntry)
+	c.order.Remove(elem)
+	delete(c.items, entry.key)
+	c.usedBytes -= entrySize(entry.key, entry.value)
+}
+
+// expired 检查缓存项是否过期
+func (c *LRUCache) expired(entry *lruEntry) bool {
+	return !entry.expiresAt.IsZero() && c.now().After(entry.expiresAt)
+}
+
+// entrySize 估算缓存项占用的字节数
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"backend-go/pkg/redis"
+	"github.com/sirupsen/logrus"
+)
+
+// RemoteCache 二级缓存（Redis）所需的操作，redis.CacheService 已实现该接口
+type RemoteCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	InvalidatePattern(ctx context.Context, pattern string) error
+}
+
+// MultiLevelConfig 多级缓存配置
+type MultiLevelConfig struct {
+	L1TTL        time.Duration // 一级缓存最长存活时间
+	L1MaxEntries int           // 一级缓存最大条目数
+	L1MaxBytes   int64         // 一级缓存最大字节数
+}
+
+// DefaultMultiLevelConfig 默认多级缓存配置
+func DefaultMultiLevelConfig() MultiLevelConfig {
+	return MultiLevelConfig{
+		L1TTL:        30 * time.Second,
+		L1MaxEntries: 10000,
+		L1MaxBytes:   64 << 20,
+	}
+}
+
+// MultiLevelCache 多级缓存实现 (LRU 内存 L1 + Redis L2)
+//
+// Delete/DeletePattern 只清除本实例的 L1，其他实例的 L1 在 L1TTL 到期前仍返回旧值，
+// 只适合能容忍短暂不一致的数据。
+type MultiLevelCache struct {
+	l1     *LRUCache
+	l2     RemoteCache
+	config MultiLevelConfig
+	logger *logrus.Logger
+
+	// 统计信息
+	memoryHits   int64
+	memoryMisses int64
+	redisHits    int64
+	redisMisses  int64
+}
+
+// NewMultiLevelCache 创建多级缓存实例
+func NewMultiLevelCache(l2 RemoteCache, config MultiLevelConfig, logger *logrus.Logger) *MultiLevelCache {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	defaults := DefaultMultiLevelConfig()
+	if config.L1TTL <= 0 {
+		config.L1TTL = defaults.L1TTL
+	}
+	if config.L1MaxEntries <= 0 {
+		config.L1MaxEntries = defaults.L1MaxEntries
+	}
+	if config.L1MaxBytes <= 0 {
+		config.L1MaxBytes = defaults.L1MaxBytes
+	}
+
+	return &MultiLevelCache{
+		l1:     NewLRUCache(config.L1MaxEntries, config.L1MaxBytes),
+		l2:     l2,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Get 获取缓存值 (先查 L1，再查 L2 并回填 L1)
+func (mc *MultiLevelCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := mc.l1.Get(ctx, key); err == nil {
+		atomic.AddInt64(&mc.memoryHits, 1)
+		return value, nil
+	}
+	atomic.AddInt64(&mc.memoryMisses, 1)
+
+	value, err := mc.GetFromRedis(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrCacheNotFound) {
+			atomic.AddInt64(&mc.redisMisses, 1)
+		}
+		return nil, err
+	}
+	atomic.AddInt64(&mc.redisHits, 1)
+
+	if err := mc.SetToMemory(ctx, key, value, mc.config.L1TTL); err != nil {
+		mc.logger.WithError(err).Warn("Failed to set memory cache")
+	}
+
+	return value, nil
+}
+
+// Set 设置缓存值 (写穿两级缓存)
+func (mc *MultiLevelCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := mc.SetToRedis(ctx, key, value, ttl); err != nil {
+		// L2 写入失败时不保留 L1，避免两级数据不一致
+		mc.l1.Delete(ctx, key)
+		return err
+	}
+
+	return mc.SetToMemory(ctx, key, value, ttl)
+}
+
+// Delete 删除缓存 (同时清除两级缓存)
+func (mc *MultiLevelCache) Delete(ctx context.Context, key string) error {
+	mc.l1.Delete(ctx, key)
+	if err := mc.l2.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete key %s from redis: %w", key, err)
+	}
+	return nil
+}
+
+// DeletePattern 批量删除匹配模式的缓存键
+func (mc *MultiLevelCache) DeletePattern(ctx context.Context, pattern string) error {
+	mc.l1.DeletePattern(ctx, pattern)
+	if err := mc.l2.InvalidatePattern(ctx, pattern); err != nil {
+		return fmt.Errorf("failed to invalidate pattern %s in redis: %w", pattern, err)
+	}
+	return nil
+}
+
+// Exists 检查缓存是否存在
+func (mc *MultiLevelCache) Exists(ctx context.Context, key string) (bool, error) {
+	if exists, _ := mc.l1.Exists(ctx, key); exists {
+		return true, nil
+	}
+	return mc.l2.Exists(ctx, key)
+}
+
+// SetTTL 设置缓存过期时间
+func (mc *MultiLevelCache) SetTTL(ctx context.Context, key string, ttl time.Duration) error {
+	// L1 条目直接丢弃，下次读取时按新的 TTL 回填
+	mc.l1.Delete(ctx, key)
+	return mc.l2.Expire(ctx, key, ttl)
+}
+
+// GetTTL 获取缓存剩余过期时间
+func (mc *MultiLevelCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	return mc.l2.TTL(ctx, key)
+}
+
+// GetFromMemory 从内存缓存获取
+func (mc *MultiLevelCache) GetFromMemory(ctx context.Context, key string) ([]byte, error) {
+	return mc.l1.Get(ctx, key)
+}
+
+// GetFromRedis 从Redis缓存获取
+func (mc *MultiLevelCache) GetFromRedis(ctx context.Context, key string) ([]byte, error) {
+	value, err := mc.l2.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return nil, ErrCacheNotFound
+		}
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// SetToMemory 设置到内存缓存，TTL 不超过 L1TTL
+func (mc *MultiLevelCache) SetToMemory(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 || ttl > mc.config.L1TTL {
+		ttl = mc.config.L1TTL
+	}
+	return mc.l1.Set(ctx, key, value, ttl)
+}
+
+// SetToRedis 设置到Redis缓存
+func (mc *MultiLevelCache) SetToRedis(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := mc.l2.Set(ctx, key, value, ttl); err != nil {
+		return fmt.Errorf("failed to set key %s to redis: %w", key, err)
+	}
+	return nil
+}
+
+// InvalidateMemory 清除内存缓存
+func (mc *MultiLevelCache) InvalidateMemory(ctx context.Context, pattern string) error {
+	return mc.l1.DeletePattern(ctx, pattern)
+}
+
+// GetStats 获取缓存统计信息
+func (mc *MultiLevelCache) GetStats() CacheStats {
+	memoryHits := atomic.LoadInt64(&mc.memoryHits)
+	memoryMisses := atomic.LoadInt64(&mc.memoryMisses)
+	redisHits := atomic.LoadInt64(&mc.redisHits)
+	redisMisses := atomic.LoadInt64(&mc.redisMisses)
+
+	// 内存未命中但 Redis 命中的请求最终是命中的
+	totalHits := memoryHits + redisHits
+	totalMisses := redisMisses
+	total := totalHits + totalMisses
+
+	var hitRate float64
+	if total > 0 {
+		hitRate = float64(totalHits) / float64(total)
+	}
+
+	return CacheStats{
+		MemoryHits:      memoryHits,
+		MemoryMisses:    memoryMisses,
+		RedisHits:       redisHits,
+		RedisMisses:     redisMisses,
+		TotalHits:       totalHits,
+		TotalMisses:     totalMisses,
+		HitRate:         hitRate,
+		MemoryEntries:   mc.l1.Len(),
+		MemoryBytes:     mc.l1.UsedBytes(),
+		MemoryEvictions: mc.l1.Evictions(),
+	}
+}
+
+// ResetStats 重置统计信息
+func (mc *MultiLevelCache) ResetStats() {
+	atomic.StoreInt64(&mc.memoryHits, 0)
+	atomic.StoreInt64(&mc.memoryMisses, 0)
+	atomic.StoreInt64(&mc.redisHits, 0)
+	atomic.StoreInt64(&mc.redisMisses, 0)
+}
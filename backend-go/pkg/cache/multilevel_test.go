@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/pkg/redis"
+)
+
+// countingRemote 统计调用次数的二级缓存
+type countingRemote struct {
+	data map[string]string
+	gets int
+}
+
+func newCountingRemote() *countingRemote {
+	return &countingRemote{data: make(map[string]string)}
+}
+
+func (r *countingRemote) Get(ctx context.Context, key string) (string, error) {
+	r.gets++
+	value, ok := r.data[key]
+	if !ok {
+		return "", redis.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (r *countingRemote) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	r.data[key] = string(value.([]byte))
+	return nil
+}
+
+func (r *countingRemote) Delete(ctx context.Context, key string) error {
+	delete(r.data, key)
+	return nil
+}
+
+func (r *countingRemote) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok := r.data[key]
+	return ok, nil
+}
+
+func (r *countingRemote) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+
+func (r *countingRemote) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return time.Minute, nil
+}
+
+func (r *countingRemote) InvalidatePattern(ctx context.Context, pattern string) error {
+	prefix := strings.TrimSuffix(pattern, "*")
+	for key := range r.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(r.data, key)
+		}
+	}
+	return nil
+}
+
+func TestMultiLevelCache_SecondReadHitsL1(t *testing.T) {
+	ctx := context.Background()
+	remote := newCountingRemote()
+	remote.data["match:1"] = "value"
+	mc := NewMultiLevelCache(remote, DefaultMultiLevelConfig(), nil)
+
+	for i := 0; i < 2; i++ {
+		value, err := mc.Get(ctx, "match:1")
+		if err != nil || string(value) != "value" {
+			t.Fatalf("Get() = %q, %v", value, err)
+		}
+	}
+
+	if remote.gets != 1 {
+		t.Errorf("Redis 调用次数 = %d, want 1", remote.gets)
+	}
+	stats := mc.GetStats()
+	if stats.MemoryHits != 1 || stats.RedisHits != 1 {
+		t.Errorf("统计信息不正确: %+v", stats)
+	}
+}
+
+func TestMultiLevelCache_InvalidationClearsBothLayers(t *testing.T) {
+	ctx := context.Background()
+	remote := newCountingRemote()
+	mc := NewMultiLevelCache(remote, DefaultMultiLevelConfig(), nil)
+
+	mc.Set(ctx, "match:1", []byte("a"), time.Minute)
+	mc.Set(ctx, "match:2", []byte("b"), time.Minute)
+
+	if err := mc.Delete(ctx, "match:1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := mc.DeletePattern(ctx, "match:*"); err != nil {
+		t.Fatalf("DeletePattern() error = %v", err)
+	}
+
+	for _, key := range []string{"match:1", "match:2"} {
+		if _, err := mc.GetFromMemory(ctx, key); err != ErrCacheNotFound {
+			t.Errorf("L1 中 %s 未被清除", key)
+		}
+		if _, ok := remote.data[key]; ok {
+			t.Errorf("L2 中 %s 未被清除", key)
+		}
+	}
+}
+
+func TestMultiLevelCache_L1ExpiryFallsBackToL2(t *testing.T) {
+	ctx := context.Background()
+	remote := newCountingRemote()
+	mc := NewMultiLevelCache(remote, DefaultMultiLevelConfig(), nil)
+
+	now := time.Now()
+	mc.l1.now = func() time.Time { return now }
+	mc.Set(ctx, "match:1", []byte("value"), time.Hour)
+
+	// L1 条目超过 L1TTL 后应回落到 L2
+	mc.l1.now = func() time.Time { return now.Add(mc.config.L1TTL + time.Second) }
+	value, err := mc.Get(ctx, "match:1")
+	if err != nil || string(value) != "value" {
+		t.Fatalf("Get() = %q, %v", value, err)
+	}
+	if remote.gets != 1 {
+		t.Errorf("Redis 调用次数 = %d, want 1", remote.gets)
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRUCache(2, 0)
+
+	lru.Set(ctx, "a", []byte("1"), 0)
+	lru.Set(ctx, "b", []byte("2"), 0)
+	lru.Get(ctx, "a")
+	lru.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := lru.Get(ctx, "b"); err != ErrCacheNotFound {
+		t.Errorf("最久未使用的键 b 应被淘汰")
+	}
+	if lru.Len() != 2 || lru.Evictions() != 1 {
+		t.Errorf("Len() = %d, Evictions() = %d", lru.Len(), lru.Evictions())
+	}
+}
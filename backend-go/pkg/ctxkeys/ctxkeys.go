@@ -0,0 +1,104 @@
+// Package ctxkeys 定义统一的类型化上下文键，避免字符串键冲突
+//
+// 中间件在认证、生成请求 ID 时写入请求上下文，服务层与日志可直接从
+// context.Context 读取，无需依赖 gin.Context。
+package ctxkeys
+
+import "context"
+
+// key 私有键类型，其他包无法构造相同的键
+type key int
+
+const (
+	userIDKey key = iota
+	usernameKey
+	userRoleKey
+	correlationIDKey
+	traceIDKey
+	adminUserIDKey
+	impersonatorIDKey
+)
+
+// WithUserID 写入当前用户ID
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFrom 读取当前用户ID
+func UserIDFrom(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(userIDKey).(uint)
+	return userID, ok
+}
+
+// WithUsername 写入当前用户名
+func WithUsername(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, usernameKey, username)
+}
+
+// UsernameFrom 读取当前用户名
+func UsernameFrom(ctx context.Context) (string, bool) {
+	username, ok := ctx.Value(usernameKey).(string)
+	return username, ok
+}
+
+// WithUserRole 写入当前用户角色
+func WithUserRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, userRoleKey, role)
+}
+
+// UserRoleFrom 读取当前用户角色
+func UserRoleFrom(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(userRoleKey).(string)
+	return role, ok
+}
+
+// WithCorrelationID 写入请求关联ID（即 X-Request-ID）
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, correlationID)
+}
+
+// CorrelationIDFrom 读取请求关联ID
+func CorrelationIDFrom(ctx context.Context) (string, bool) {
+	correlationID, ok := ctx.Value(correlationIDKey).(string)
+	return correlationID, ok
+}
+
+// WithTraceID 写入追踪ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceIDFrom 读取追踪ID
+func TraceIDFrom(ctx context.Context) (string, bool) {
+	traceID, ok := ctx.Value(traceIDKey).(string)
+	return traceID, ok
+}
+
+// WithAdminUserID 写入已通过管理员校验的用户ID（admin_users.user_id）
+func WithAdminUserID(ctx context.Context, adminUserID uint) context.Context {
+	return context.WithValue(ctx, adminUserIDKey, adminUserID)
+}
+
+// AdminUserIDFrom 读取当前管理员ID
+func AdminUserIDFrom(ctx context.Context) (uint, bool) {
+	adminUserID, ok := ctx.Value(adminUserIDKey).(uint)
+	return adminUserID, ok
+}
+
+// WithImpersonatorID 写入代入当前用户的管理员ID，仅在使用代入令牌时存在
+func WithImpersonatorID(ctx context.Context, impersonatorID uint) context.Context {
+	return context.WithValue(ctx, impersonatorIDKey, impersonatorID)
+}
+
+// ImpersonatorIDFrom 读取代入当前用户的管理员ID
+func ImpersonatorIDFrom(ctx context.Context) (uint, bool) {
+	impersonatorID, ok := ctx.Value(impersonatorIDKey).(uint)
+	return impersonatorID, ok
+}
+
+// WithUser 一次写入用户ID、用户名与角色
+func WithUser(ctx context.Context, userID uint, username, role string) context.Context {
+	ctx = WithUserID(ctx, userID)
+	ctx = WithUsername(ctx, username)
+	return WithUserRole(ctx, role)
+}
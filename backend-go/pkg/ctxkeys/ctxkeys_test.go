@@ -0,0 +1,84 @@
+package ctxkeys
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTypedValues(t *testing.T) {
+	ctx := context.Background()
+	ctx = WithUser(ctx, 42, "alice", "admin")
+	ctx = WithCorrelationID(ctx, "req-1")
+	ctx = WithTraceID(ctx, "trace-1")
+	ctx = WithAdminUserID(ctx, 7)
+	ctx = WithImpersonatorID(ctx, 3)
+
+	if got, ok := UserIDFrom(ctx); !ok || got != 42 {
+		t.Errorf("UserIDFrom() = %v, %v, want 42, true", got, ok)
+	}
+	if got, ok := AdminUserIDFrom(ctx); !ok || got != 7 {
+		t.Errorf("AdminUserIDFrom() = %v, %v, want 7, true", got, ok)
+	}
+	if got, ok := ImpersonatorIDFrom(ctx); !ok || got != 3 {
+		t.Errorf("ImpersonatorIDFrom() = %v, %v, want 3, true", got, ok)
+	}
+
+	strTests := []struct {
+		name string
+		get  func(context.Context) (string, bool)
+		want string
+	}{
+		{"用户名", UsernameFrom, "alice"},
+		{"角色", UserRoleFrom, "admin"},
+		{"关联ID", CorrelationIDFrom, "req-1"},
+		{"追踪ID", TraceIDFrom, "trace-1"},
+	}
+	for _, tt := range strTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := tt.get(ctx); !ok || got != tt.want {
+				t.Errorf("got %q, %v, want %q, true", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingValues(t *testing.T) {
+	ctx := context.Background()
+
+	if got, ok := UserIDFrom(ctx); ok || got != 0 {
+		t.Errorf("UserIDFrom() = %v, %v, want 0, false", got, ok)
+	}
+	if got, ok := AdminUserIDFrom(ctx); ok || got != 0 {
+		t.Errorf("AdminUserIDFrom() = %v, %v, want 0, false", got, ok)
+	}
+	if got, ok := ImpersonatorIDFrom(ctx); ok || got != 0 {
+		t.Errorf("ImpersonatorIDFrom() = %v, %v, want 0, false", got, ok)
+	}
+	for name, get := range map[string]func(context.Context) (string, bool){
+		"用户名":  UsernameFrom,
+		"角色":   UserRoleFrom,
+		"关联ID": CorrelationIDFrom,
+		"追踪ID": TraceIDFrom,
+	} {
+		if got, ok := get(ctx); ok || got != "" {
+			t.Errorf("%s: got %q, %v, want \"\", false", name, got, ok)
+		}
+	}
+}
+
+func TestStringKeysDoNotCollide(t *testing.T) {
+	// 其他包使用同名键时不会覆盖类型化的值
+	type otherKey string
+	ctx := context.WithValue(context.Background(), otherKey("user_id"), "999")
+	if _, ok := UserIDFrom(ctx); ok {
+		t.Errorf("其他包的 user_id 键不应被识别为用户ID")
+	}
+
+	ctx = WithUserID(ctx, 1)
+	if got, _ := UserIDFrom(ctx); got != 1 {
+		t.Errorf("UserIDFrom() = %v, want 1", got)
+	}
+	if got := ctx.Value(otherKey("user_id")); got != "999" {
+		t.Errorf("其他包键的值被覆盖: %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend-go/pkg/deadline"
+)
+
+// budgetStateKey 保存本次语句派生前的上下文与取消函数
+const budgetStateKey = "database:deadline_budget"
+
+type budgetState struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// RegisterDeadlineBudget 注册按请求剩余时间预算限制查询时长的回调
+//
+// 每条语句的超时取 queryTimeout 与上下文剩余时间中的较小值，queryTimeout<=0 表示只受上下文约束；
+// 剩余时间不足 minBudget 时语句不会执行，直接返回 deadline.ErrBudgetExhausted。
+func RegisterDeadlineBudget(db *gorm.DB, queryTimeout, minBudget time.Duration) error {
+	before := func(tx *gorm.DB) {
+		ctx, cancel, err := deadline.WithBudget(tx.Statement.Context, queryTimeout, minBudget)
+		if err != nil {
+			_ = tx.AddError(err)
+			return
+		}
+		tx.InstanceSet(budgetStateKey, budgetState{parent: tx.Statement.Context, cancel: cancel})
+		tx.Statement.Context = ctx
+	}
+	// 链式复用同一语句（如先 Count 再 Find）时需恢复原上下文，否则后续语句会使用已取消的上下文
+	after := func(tx *gorm.DB) {
+		if value, ok := tx.InstanceGet(budgetStateKey); ok {
+			state := value.(budgetState)
+			state.cancel()
+			tx.Statement.Context = state.parent
+		}
+	}
+	// Row 返回的 *sql.Rows 在回调结束后才被读取，不能提前取消，只做预算检查
+	checkOnly := func(tx *gorm.DB) {
+		if _, err := deadline.Effective(tx.Statement.Context, queryTimeout, minBudget); err != nil {
+			_ = tx.AddError(err)
+		}
+	}
+
+	callback := db.Callback()
+	registrations := []struct {
+		name string
+		err  error
+	}{
+		{"create:before", callback.Create().Before("*").Register("deadline_budget:before_create", before)},
+		{"create:after", callback.Create().After("*").Register("deadline_budget:after_create", after)},
+		{"query:before", callback.Query().Before("*").Register("deadline_budget:before_query", before)},
+		{"query:after", callback.Query().After("*").Register("deadline_budget:after_query", after)},
+		{"update:before", callback.Update().Before("*").Register("deadline_budget:before_update", before)},
+		{"update:after", callback.Update().After("*").Register("deadline_budget:after_update", after)},
+		{"delete:before", callback.Delete().Before("*").Register("deadline_budget:before_delete", before)},
+		{"delete:after", callback.Delete().After("*").Register("deadline_budget:after_delete", after)},
+		{"raw:before", callback.Raw().Before("*").Register("deadline_budget:before_raw", before)},
+		{"raw:after", callback.Raw().After("*").Register("deadline_budget:after_raw", after)},
+		{"row:before", callback.Row().Before("*").Register("deadline_budget:before_row", checkOnly)},
+	}
+	for _, r := range registrations {
+		if r.err != nil {
+			return fmt.Errorf("failed to register deadline budget %s callback: %w", r.name, r.err)
+		}
+	}
+	return nil
+}
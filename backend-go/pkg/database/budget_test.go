@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend-go/pkg/deadline"
+)
+
+type budgetRecord struct {
+	ID   uint
+	Name string
+}
+
+// newBudgetTestDB 创建注册了预算回调的内存数据库
+func newBudgetTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&budgetRecord{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := RegisterDeadlineBudget(db, 5*time.Second, 50*time.Millisecond); err != nil {
+		t.Fatalf("RegisterDeadlineBudget() error = %v", err)
+	}
+	return db
+}
+
+func TestDeadlineBudget_FailsFastWhenNearlyExpired(t *testing.T) {
+	db := newBudgetTestDB(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var records []budgetRecord
+	err := db.WithContext(ctx).Find(&records).Error
+	if !errors.Is(err, deadline.ErrBudgetExhausted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Find() error = %v, want ErrBudgetExhausted", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Find() took %s, want immediate failure", elapsed)
+	}
+
+	// 预算不足时写入同样不会执行
+	if err := db.WithContext(ctx).Create(&budgetRecord{Name: "late"}).Error; !errors.Is(err, deadline.ErrBudgetExhausted) {
+		t.Fatalf("Create() error = %v, want ErrBudgetExhausted", err)
+	}
+	var count int64
+	if err := db.Model(&budgetRecord{}).Count(&count).Error; err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("count = %d, want 0", count)
+	}
+}
+
+func TestDeadlineBudget_RunsWithinBudget(t *testing.T) {
+	db := newBudgetTestDB(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := db.WithContext(ctx).Create(&[]budgetRecord{{Name: "a"}, {Name: "b"}}).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// 链式复用同一语句时，后一条语句不应继承前一条已取消的上下文
+	query := db.WithContext(ctx).Model(&budgetRecord{}).Where("name <> ?", "")
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	var records []budgetRecord
+	if err := query.Find(&records).Error; err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if count != 2 || len(records) != 2 {
+		t.Errorf("count = %d, records = %d, want 2", count, len(records))
+	}
+
+	var name string
+	if err := db.WithContext(ctx).Model(&budgetRecord{}).Select("name").Where("id = ?", records[0].ID).Row().Scan(&name); err != nil {
+		t.Fatalf("Row().Scan() error = %v", err)
+	}
+	if name != "a" {
+		t.Errorf("name = %q, want a", name)
+	}
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+
+	"backend-go/pkg/response"
+)
+
+// 需要区分处理的 MySQL 错误码
+const (
+	mysqlErrDuplicateEntry   uint16 = 1062 // 唯一约束冲突
+	mysqlErrNoReferencedRow  uint16 = 1452 // 外键引用的记录不存在
+	mysqlErrRowIsReferenced  uint16 = 1451 // 记录仍被外键引用，无法删除或修改
+	mysqlErrLockWaitTimeout  uint16 = 1205 // 等待行锁超时
+	mysqlErrLockDeadlock     uint16 = 1213 // 死锁，事务已被回滚
+	mysqlErrQueryInterrupted uint16 = 1317 // 查询被中断
+)
+
+// duplicateKeyPattern 从 1062 错误信息中提取冲突的索引名，不返回冲突的值
+var duplicateKeyPattern = regexp.MustCompile(`for key '([^']+)'`)
+
+// ClassifyError 将常见的 MySQL 错误映射为带状态码的 AppError
+//
+// 唯一约束冲突返回 409，外键约束失败返回 400，死锁与锁等待超时返回可重试的 503。
+// err 为 nil 或不是可识别的数据库错误时返回 nil，调用方按原有逻辑处理。
+func ClassifyError(err error) *response.AppError {
+	if err == nil {
+		return nil
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrDuplicateEntry:
+			var details interface{}
+			if m := duplicateKeyPattern.FindStringSubmatch(mysqlErr.Message); m != nil {
+				details = map[string]interface{}{"key": m[1]}
+			}
+			return response.NewConflictError("数据已存在，请勿重复提交", details).WithCause(err)
+		case mysqlErrNoReferencedRow:
+			return response.NewBadRequestError("关联的数据不存在", nil).WithCause(err)
+		case mysqlErrRowIsReferenced:
+			return response.NewConflictError("数据仍被其他记录引用", nil).WithCause(err)
+		case mysqlErrLockDeadlock, mysqlErrLockWaitTimeout, mysqlErrQueryInterrupted:
+			return newRetryableError(err)
+		}
+		return nil
+	}
+
+	// 开启 TranslateError 时 GORM 已将驱动错误翻译为通用错误
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return response.NewConflictError("数据已存在，请勿重复提交", nil).WithCause(err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return response.NewBadRequestError("关联的数据不存在", nil).WithCause(err)
+	}
+	return nil
+}
+
+// IsRetryable 判断错误是否可以通过重试整个事务解决（死锁、锁等待超时）
+func IsRetryable(err error) bool {
+	appErr := ClassifyError(err)
+	return appErr != nil && appErr.Code == response.CodeDatabaseRetryable
+}
+
+func newRetryableError(cause error) *response.AppError {
+	return response.NewAppError(response.ErrorTypeDatabase, response.CodeDatabaseRetryable,
+		"数据库繁忙，请稍后重试", http.StatusServiceUnavailable).
+		WithDetails(map[string]interface{}{"retryable": true}).
+		WithCause(cause)
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+
+	"backend-go/pkg/response"
+)
+
+func TestClassifyError_MapsMySQLErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantStatus int
+	}{
+		{
+			name:       "duplicate entry",
+			err:        &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@example.com' for key 'users.idx_users_email'"},
+			wantCode:   response.CodeConflict,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "foreign key",
+			err:        &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails"},
+			wantCode:   response.CodeBadRequest,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "deadlock",
+			err:        &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"},
+			wantCode:   response.CodeDatabaseRetryable,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "lock wait timeout",
+			err:        &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"},
+			wantCode:   response.CodeDatabaseRetryable,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "gorm translated duplicate",
+			err:        gorm.ErrDuplicatedKey,
+			wantCode:   response.CodeConflict,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 仓储层通常会再包装一层
+			wrapped := fmt.Errorf("failed to create user: %w", tt.err)
+
+			appErr := ClassifyError(wrapped)
+			if appErr == nil {
+				t.Fatal("ClassifyError() = nil")
+			}
+			if appErr.Code != tt.wantCode || appErr.StatusCode != tt.wantStatus {
+				t.Errorf("ClassifyError() = %s/%d, want %s/%d", appErr.Code, appErr.StatusCode, tt.wantCode, tt.wantStatus)
+			}
+			if !errors.Is(appErr, tt.err) {
+				t.Error("AppError 应保留原始错误")
+			}
+			if got := IsRetryable(wrapped); got != (tt.wantCode == response.CodeDatabaseRetryable) {
+				t.Errorf("IsRetryable() = %v", got)
+			}
+		})
+	}
+}
+
+func TestClassifyError_DuplicateDetailsNameKeyOnly(t *testing.T) {
+	appErr := ClassifyError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@example.com' for key 'users.idx_users_email'"})
+	details, ok := appErr.Details.(map[string]interface{})
+	if !ok || details["key"] != "users.idx_users_email" {
+		t.Errorf("Details = %#v, want conflicting key name", appErr.Details)
+	}
+	if s := appErr.Error(); strings.Contains(s, "a@example.com") {
+		t.Errorf("错误信息不应包含冲突的值: %s", s)
+	}
+}
+
+func TestClassifyError_IgnoresUnknownErrors(t *testing.T) {
+	for _, err := range []error{
+		nil,
+		errors.New("connection refused"),
+		gorm.ErrRecordNotFound,
+		&mysql.MySQLError{Number: 1146, Message: "Table 'app.missing' doesn't exist"},
+	} {
+		if appErr := ClassifyError(err); appErr != nil {
+			t.Errorf("ClassifyError(%v) = %v, want nil", err, appErr)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package database
+
+import "strings"
+
+// likeEscaper 转义 LIKE 通配符，使用 ! 作为转义字符以兼容 MySQL 与 SQLite
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// ContainsPattern 构造包含匹配的 LIKE 模式，查询条件需带 ESCAPE '!'
+func ContainsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
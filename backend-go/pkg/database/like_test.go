@@ -0,0 +1,17 @@
+package database
+
+import "testing"
+
+func TestContainsPattern_EscapesWildcards(t *testing.T) {
+	tests := map[string]string{
+		"login":      "%login%",
+		"100%":       "%100!%%",
+		"user_login": "%user!_login%",
+		"a!b":        "%a!!b%",
+	}
+	for term, want := range tests {
+		if got := ContainsPattern(term); got != want {
+			t.Errorf("ContainsPattern(%q) = %q, want %q", term, got, want)
+		}
+	}
+}
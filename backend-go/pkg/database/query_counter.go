@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// maxCountedStatements 每个计数器最多保留的 SQL 条数，超出部分只计数
+const maxCountedStatements = 100
+
+type queryCounterKey struct{}
+
+// QueryCounter 统计同一上下文（通常是一个 HTTP 请求）内执行的语句数，用于发现 N+1 查询
+type QueryCounter struct {
+	mu         sync.Mutex
+	count      int
+	statements []string
+}
+
+// WithQueryCounter 返回携带新计数器的上下文，之后使用该上下文执行的语句都会计入
+func WithQueryCounter(ctx context.Context) (context.Context, *QueryCounter) {
+	counter := &QueryCounter{}
+	return context.WithValue(ctx, queryCounterKey{}, counter), counter
+}
+
+// QueryCounterFromContext 获取上下文中的计数器
+func QueryCounterFromContext(ctx context.Context) (*QueryCounter, bool) {
+	counter, ok := ctx.Value(queryCounterKey{}).(*QueryCounter)
+	return counter, ok
+}
+
+// Count 已执行的语句数
+func (c *QueryCounter) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+// Statements 已执行的 SQL（含占位符），最多保留 maxCountedStatements 条
+func (c *QueryCounter) Statements() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.statements...)
+}
+
+func (c *QueryCounter) record(sql string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+	if len(c.statements) < maxCountedStatements {
+		c.statements = append(c.statements, sql)
+	}
+}
+
+// RegisterQueryCounter 注册语句计数回调，仅供开发环境排查 N+1 查询
+//
+// 只统计上下文中带有 WithQueryCounter 计数器的语句，未携带计数器时回调不做任何事。
+func RegisterQueryCounter(db *gorm.DB) error {
+	count := func(tx *gorm.DB) {
+		if tx.Statement.Context == nil {
+			return
+		}
+		if counter, ok := QueryCounterFromContext(tx.Statement.Context); ok {
+			counter.record(tx.Statement.SQL.String())
+		}
+	}
+
+	callback := db.Callback()
+	registrations := []struct {
+		name string
+		err  error
+	}{
+		{"create", callback.Create().After("*").Register("query_counter:create", count)},
+		{"query", callback.Query().After("*").Register("query_counter:query", count)},
+		{"update", callback.Update().After("*").Register("query_counter:update", count)},
+		{"delete", callback.Delete().After("*").Register("query_counter:delete", count)},
+		{"raw", callback.Raw().After("*").Register("query_counter:raw", count)},
+		{"row", callback.Row().After("*").Register("query_counter:row", count)},
+	}
+	for _, r := range registrations {
+		if r.err != nil {
+			return fmt.Errorf("failed to register query counter %s callback: %w", r.name, r.err)
+		}
+	}
+	return nil
+}
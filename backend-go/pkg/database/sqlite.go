@@ -24,6 +24,9 @@ type Config struct {
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	QueryTimeout    time.Duration // 单条语句超时，与请求剩余预算取较小值，0 表示只受请求上下文约束
+	MinQueryBudget  time.Duration // 发起语句所需的最小剩余预算，不足时快速失败
+	CountQueries    bool          // 注册单请求语句计数回调（开发环境排查 N+1 查询）
 }
 
 // NewConnection 创建 MySQL 数据库连接
@@ -72,6 +75,15 @@ func NewConnection(cfg Config) (*gorm.DB, error) {
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
+	if err := RegisterDeadlineBudget(db, cfg.QueryTimeout, cfg.MinQueryBudget); err != nil {
+		return nil, err
+	}
+	if cfg.CountQueries {
+		if err := RegisterQueryCounter(db); err != nil {
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
 
@@ -117,5 +129,14 @@ func NewSQLiteConnection(cfg Config) (*gorm.DB, error) {
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
+	if err := RegisterDeadlineBudget(db, cfg.QueryTimeout, cfg.MinQueryBudget); err != nil {
+		return nil, err
+	}
+	if cfg.CountQueries {
+		if err := RegisterQueryCounter(db); err != nil {
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
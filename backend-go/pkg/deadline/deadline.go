@@ -0,0 +1,66 @@
+// Package deadline 根据请求上下文的剩余时间预算计算下游调用的超时
+//
+// 超时中间件为请求设置截止时间后，数据库与缓存调用应使用
+// min(自身配置的超时, 剩余预算)，而不是各自固定的超时；
+// 剩余预算不足以完成一次调用时直接快速失败，避免发起注定超时的操作。
+package deadline
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrBudgetExhausted 剩余时间预算不足，可用 errors.Is(err, context.DeadlineExceeded) 判断
+var ErrBudgetExhausted = fmt.Errorf("deadline budget exhausted: %w", context.DeadlineExceeded)
+
+// noCallTimeoutKey 标记上下文中的调用不受调用方自身配置的超时限制
+type noCallTimeoutKey struct{}
+
+// WithoutCallTimeout 返回不受调用方自身超时（如 database.query_timeout）限制的上下文
+//
+// 用于数据库迁移等已自行设定总时限、单条语句可能很慢的后台任务；ctx 的截止时间仍然生效。
+func WithoutCallTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCallTimeoutKey{}, true)
+}
+
+// Effective 返回本次调用的有效超时
+//
+// configured 为调用方自身的超时，<=0 表示不限制；minBudget 为发起调用所需的最小剩余时间。
+// ctx 没有截止时间时返回 configured；剩余时间不足 minBudget 时返回 ErrBudgetExhausted。
+// 返回 0 表示无需额外设置超时。
+func Effective(ctx context.Context, configured, minBudget time.Duration) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if exempt, _ := ctx.Value(noCallTimeoutKey{}).(bool); exempt {
+		configured = 0
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return max(configured, 0), nil
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 || remaining < minBudget {
+		return 0, ErrBudgetExhausted
+	}
+	if configured > 0 && configured < remaining {
+		return configured, nil
+	}
+	return remaining, nil
+}
+
+// WithBudget 派生使用有效超时的上下文，预算不足时返回 ErrBudgetExhausted
+func WithBudget(ctx context.Context, configured, minBudget time.Duration) (context.Context, context.CancelFunc, error) {
+	timeout, err := Effective(ctx, configured, minBudget)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+	if timeout <= 0 {
+		return ctx, func() {}, nil
+	}
+	child, cancel := context.WithTimeout(ctx, timeout)
+	return child, cancel, nil
+}
@@ -0,0 +1,91 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEffective(t *testing.T) {
+	withDeadline := func(d time.Duration) context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		configured time.Duration
+		minBudget  time.Duration
+		wantMax    time.Duration
+		wantMin    time.Duration
+		wantErr    bool
+	}{
+		{name: "无截止时间使用配置超时", ctx: context.Background(), configured: 3 * time.Second, wantMin: 3 * time.Second, wantMax: 3 * time.Second},
+		{name: "无截止时间且未配置", ctx: context.Background()},
+		{name: "配置超时更短", ctx: withDeadline(time.Minute), configured: time.Second, wantMin: time.Second, wantMax: time.Second},
+		{name: "剩余预算更短", ctx: withDeadline(500 * time.Millisecond), configured: 3 * time.Second, wantMin: 400 * time.Millisecond, wantMax: 500 * time.Millisecond},
+		{name: "未配置时使用剩余预算", ctx: withDeadline(500 * time.Millisecond), wantMin: 400 * time.Millisecond, wantMax: 500 * time.Millisecond},
+		{name: "预算即将耗尽", ctx: withDeadline(5 * time.Millisecond), configured: 3 * time.Second, minBudget: 50 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Effective(tt.ctx, tt.configured, tt.minBudget)
+			if tt.wantErr {
+				if !errors.Is(err, ErrBudgetExhausted) || !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("Effective() error = %v, want ErrBudgetExhausted", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Effective() error = %v", err)
+			}
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("Effective() = %s, want within [%s, %s]", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestEffective_ExpiredContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if _, err := Effective(ctx, time.Second, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Effective() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ctx, release, err := WithBudget(parent, 100*time.Millisecond, 0)
+	if err != nil {
+		t.Fatalf("WithBudget() error = %v", err)
+	}
+	defer release()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > 100*time.Millisecond {
+		t.Errorf("deadline = %v, want within 100ms", deadline)
+	}
+}
+
+func TestEffective_WithoutCallTimeout(t *testing.T) {
+	if got, err := Effective(WithoutCallTimeout(context.Background()), time.Second, 0); err != nil || got != 0 {
+		t.Errorf("无截止时间 Effective() = %s, %v, want 0", got, err)
+	}
+
+	// 仍受上下文截止时间约束
+	ctx, cancel := context.WithTimeout(WithoutCallTimeout(context.Background()), time.Minute)
+	defer cancel()
+	got, err := Effective(ctx, time.Second, 0)
+	if err != nil || got < 59*time.Second {
+		t.Errorf("Effective() = %s, %v, want remaining budget", got, err)
+	}
+}
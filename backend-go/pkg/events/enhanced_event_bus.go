@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -94,7 +95,14 @@ func (m *EventMetrics) UpdateProcessTime(duration time.Duration) {
 func (m *EventMetrics) GetMetrics() EventMetrics {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
-	return *m
+	return EventMetrics{
+		PublishedEvents:    m.PublishedEvents,
+		ProcessedEvents:    m.ProcessedEvents,
+		FailedEvents:       m.FailedEvents,
+		RetryEvents:        m.RetryEvents,
+		HandlerPanics:      m.HandlerPanics,
+		AverageProcessTime: m.AverageProcessTime,
+	}
 }
 
 // EventWrapper 事件包装器，用于重试机制
@@ -315,10 +323,31 @@ func (bus *EnhancedEventBus) executeHandler(handler shared.EventHandler, event s
 	}
 }
 
-// sortHandlersByPriority 按优先级排序处理器
+// sortHandlersByPriority 按元数据中的优先级排序处理器，未设置元数据的使用默认优先级，相同优先级保持订阅顺序
 func (bus *EnhancedEventBus) sortHandlersByPriority(eventType string, handlers []shared.EventHandler) []shared.EventHandler {
-	// 简单实现：返回原始顺序，可以根据需要实现优先级排序
-	return handlers
+	bus.mutex.RLock()
+	priorities := make([]int, len(handlers))
+	for i, handler := range handlers {
+		priorities[i] = DefaultHandlerPriority
+		if meta, ok := bus.handlerMeta[fmt.Sprintf("%s:%p", eventType, handler)]; ok && meta != nil {
+			priorities[i] = meta.Priority
+		}
+	}
+	bus.mutex.RUnlock()
+
+	order := make([]int, len(handlers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return priorities[order[a]] < priorities[order[b]]
+	})
+
+	sorted := make([]shared.EventHandler, len(handlers))
+	for i, idx := range order {
+		sorted[i] = handlers[idx]
+	}
+	return sorted
 }
 
 // reportMetrics 报告指标
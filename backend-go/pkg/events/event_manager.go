@@ -310,20 +310,17 @@ func (m *EventManager) SetNotificationHandler(notifier EventNotifier) error {
 
 // GetMetrics 获取事件管理器指标
 func (m *EventManager) GetMetrics() EventManagerMetrics {
-	busMetrics := m.eventBus.GetMetrics()
-	registryStats := m.handlerRegistry.GetRegistryStats()
-
-	metrics := EventManagerMetrics{
-		EventBusMetrics: busMetrics,
-		RegistryStats:   registryStats,
-	}
-
 	// 添加内置处理器指标
+	var handlerMetrics map[string]*EventTypeMetrics
 	if m.metricsHandler != nil {
-		metrics.HandlerMetrics = m.metricsHandler.GetMetrics()
+		handlerMetrics = m.metricsHandler.GetMetrics()
 	}
 
-	return metrics
+	return EventManagerMetrics{
+		EventBusMetrics: m.eventBus.GetMetrics(),
+		RegistryStats:   m.handlerRegistry.GetRegistryStats(),
+		HandlerMetrics:  handlerMetrics,
+	}
 }
 
 // EventManagerMetrics 事件管理器指标
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"backend-go/internal/core/domain/shared"
+)
+
+// DefaultHandlerPriority 未指定优先级时的默认值，与 HandlerRegistry 的默认元数据一致
+const DefaultHandlerPriority = 100
+
+// SubscribeOption 订阅选项
+type SubscribeOption func(*subscription)
+
+// WithPriority 设置处理器优先级，数字越小越先执行，相同优先级按订阅顺序执行
+//
+// 只有同步总线保证执行顺序，异步总线会忽略该选项。
+func WithPriority(priority int) SubscribeOption {
+	return func(s *subscription) {
+		s.priority = priority
+	}
+}
+
+// WithFilter 只把 filter 返回 true 的事件交给处理器，用于处理器按载荷自行筛选事件
+func WithFilter(filter func(shared.Event) bool) SubscribeOption {
+	return func(s *subscription) {
+		s.filter = filter
+	}
+}
+
+// OptionSubscriber 支持订阅选项的事件总线
+type OptionSubscriber interface {
+	SubscribeWith(eventType string, handler shared.EventHandler, opts ...SubscribeOption) error
+}
+
+// Subscribe 按选项订阅事件
+//
+// 总线支持 OptionSubscriber 时直接使用；否则用 FilterHandler 包装处理器，只保留过滤条件，
+// 此时取消订阅需要传入包装后的处理器。
+func Subscribe(bus shared.EventBus, eventType string, handler shared.EventHandler, opts ...SubscribeOption) error {
+	if subscriber, ok := bus.(OptionSubscriber); ok {
+		return subscriber.SubscribeWith(eventType, handler, opts...)
+	}
+
+	sub := newSubscription(handler, opts)
+	if sub.filter != nil {
+		handler = &FilterHandler{Handler: handler, Filter: sub.filter}
+	}
+	return bus.Subscribe(eventType, handler)
+}
+
+// FilterHandler 只处理满足过滤条件的事件
+type FilterHandler struct {
+	Handler shared.EventHandler
+	Filter  func(shared.Event) bool
+}
+
+// Handle 处理事件，不满足过滤条件时直接跳过
+func (h *FilterHandler) Handle(event shared.Event) error {
+	if h.Filter != nil && !h.Filter(event) {
+		return nil
+	}
+	return h.Handler.Handle(event)
+}
+
+// subscription 一次订阅的处理器及其选项
+type subscription struct {
+	handler  shared.EventHandler
+	priority int
+	filter   func(shared.Event) bool
+}
+
+func newSubscription(handler shared.EventHandler, opts []SubscribeOption) subscription {
+	sub := subscription{handler: handler, priority: DefaultHandlerPriority}
+	for _, opt := range opts {
+		opt(&sub)
+	}
+	return sub
+}
+
+// accepts 事件是否交给该处理器
+func (s subscription) accepts(event shared.Event) bool {
+	return s.filter == nil || s.filter(event)
+}
+
+// insertSubscription 按优先级插入，相同优先级排在已有订阅之后
+func insertSubscription(subs []subscription, sub subscription) []subscription {
+	i := len(subs)
+	for i > 0 && subs[i-1].priority > sub.priority {
+		i--
+	}
+	subs = append(subs, subscription{})
+	copy(subs[i+1:], subs[i:])
+	subs[i] = sub
+	return subs
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"backend-go/internal/core/domain/shared"
+	"github.com/sirupsen/logrus"
+)
+
+// SyncEventBus 同步事件总线，Publish 依次执行处理器并返回处理器错误
+//
+// 适用于事件重放等需要知道处理结果的场景；在线请求仍应使用异步总线。
+// 处理器按优先级执行（见 WithPriority），设置了过滤条件的处理器只接收匹配的事件（见 WithFilter）。
+type SyncEventBus struct {
+	handlers map[string][]subscription
+	mutex    sync.RWMutex
+	logger   *logrus.Logger
+}
+
+// NewSyncEventBus 创建同步事件总线
+func NewSyncEventBus(logger *logrus.Logger) *SyncEventBus {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &SyncEventBus{
+		handlers: make(map[string][]subscription),
+		logger:   logger,
+	}
+}
+
+// Publish 同步发布事件，匹配的处理器都会执行，返回合并后的错误
+func (bus *SyncEventBus) Publish(event shared.Event) error {
+	bus.mutex.RLock()
+	subs := append([]subscription(nil), bus.handlers[event.GetType()]...)
+	bus.mutex.RUnlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if !sub.accepts(event) {
+			continue
+		}
+		if err := bus.handle(sub.handler, event); err != nil {
+			bus.logger.WithFields(logrus.Fields{
+				"event_type": event.GetType(),
+				"error":      err,
+			}).Warn("Event handler failed")
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handle 执行单个处理器，panic 转换为错误
+func (bus *SyncEventBus) handle(handler shared.EventHandler, event shared.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+	return handler.Handle(event)
+}
+
+// Subscribe 以默认优先级订阅事件
+func (bus *SyncEventBus) Subscribe(eventType string, handler shared.EventHandler) error {
+	return bus.SubscribeWith(eventType, handler)
+}
+
+// SubscribeWith 按选项订阅事件
+func (bus *SyncEventBus) SubscribeWith(eventType string, handler shared.EventHandler, opts ...SubscribeOption) error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	bus.handlers[eventType] = insertSubscription(bus.handlers[eventType], newSubscription(handler, opts))
+	return nil
+}
+
+// Unsubscribe 取消订阅事件
+func (bus *SyncEventBus) Unsubscribe(eventType string, handler shared.EventHandler) error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+
+	subs := bus.handlers[eventType]
+	for i, sub := range subs {
+		if sub.handler == handler {
+			bus.handlers[eventType] = append(subs[:i], subs[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("handler not found for event type: %s", eventType)
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/core/domain/shared"
+)
+
+const testErrorEvent = "error.encountered"
+
+// recordingHandler 把收到的事件按处理器名称记录到共享的调用序列
+type recordingHandler struct {
+	name  string
+	calls *[]string
+}
+
+func (h *recordingHandler) Handle(event shared.Event) error {
+	*h.calls = append(*h.calls, h.name)
+	return nil
+}
+
+func newTestSyncBus() *SyncEventBus {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewSyncEventBus(log)
+}
+
+func severityIs(severity string) func(shared.Event) bool {
+	return func(event shared.Event) bool {
+		payload, ok := event.GetPayload().(map[string]string)
+		return ok && payload["severity"] == severity
+	}
+}
+
+func TestSyncEventBus_RunsHandlersByPriority(t *testing.T) {
+	bus := newTestSyncBus()
+	var calls []string
+
+	subscribe := func(name string, opts ...SubscribeOption) {
+		t.Helper()
+		if err := bus.SubscribeWith(testErrorEvent, &recordingHandler{name: name, calls: &calls}, opts...); err != nil {
+			t.Fatalf("SubscribeWith(%s) error = %v", name, err)
+		}
+	}
+	subscribe("audit")
+	subscribe("alert", WithPriority(10))
+	subscribe("metrics", WithPriority(50))
+	subscribe("archive")
+	// Subscribe 不带选项时使用默认优先级
+	if err := bus.Subscribe(testErrorEvent, &recordingHandler{name: "legacy", calls: &calls}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	if err := bus.Publish(shared.NewEvent(testErrorEvent, nil)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	want := []string{"alert", "metrics", "audit", "archive", "legacy"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestSyncEventBus_FilterSelectsMatchingEvents(t *testing.T) {
+	bus := newTestSyncBus()
+	var critical, all []string
+
+	criticalHandler := &recordingHandler{name: "critical", calls: &critical}
+	if err := bus.SubscribeWith(testErrorEvent, criticalHandler, WithFilter(severityIs("critical"))); err != nil {
+		t.Fatalf("SubscribeWith() error = %v", err)
+	}
+	if err := bus.Subscribe(testErrorEvent, &recordingHandler{name: "all", calls: &all}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	for _, severity := range []string{"low", "critical", "medium", "critical"} {
+		if err := bus.Publish(shared.NewEvent(testErrorEvent, map[string]string{"severity": severity})); err != nil {
+			t.Fatalf("Publish(%s) error = %v", severity, err)
+		}
+	}
+
+	if len(critical) != 2 {
+		t.Errorf("带过滤条件的处理器收到 %d 个事件, want 2", len(critical))
+	}
+	if len(all) != 4 {
+		t.Errorf("未过滤的处理器收到 %d 个事件, want 4", len(all))
+	}
+
+	// 取消订阅使用原始处理器
+	if err := bus.Unsubscribe(testErrorEvent, criticalHandler); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	if err := bus.Publish(shared.NewEvent(testErrorEvent, map[string]string{"severity": "critical"})); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(critical) != 2 {
+		t.Errorf("取消订阅后仍收到事件, calls = %d", len(critical))
+	}
+}
+
+// plainBus 不支持订阅选项的总线，只记录订阅的处理器
+type plainBus struct {
+	shared.EventBus
+	handlers []shared.EventHandler
+}
+
+func (b *plainBus) Subscribe(eventType string, handler shared.EventHandler) error {
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+func TestSubscribe_WrapsFilterForPlainBus(t *testing.T) {
+	bus := &plainBus{}
+	var calls []string
+
+	if err := Subscribe(bus, testErrorEvent, &recordingHandler{name: "critical", calls: &calls}, WithFilter(severityIs("critical"))); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(bus.handlers) != 1 {
+		t.Fatalf("订阅了 %d 个处理器, want 1", len(bus.handlers))
+	}
+	if _, ok := bus.handlers[0].(*FilterHandler); !ok {
+		t.Fatalf("handler = %T, want *FilterHandler", bus.handlers[0])
+	}
+
+	handler := bus.handlers[0]
+	_ = handler.Handle(shared.NewEvent(testErrorEvent, map[string]string{"severity": "low"}))
+	_ = handler.Handle(shared.NewEvent(testErrorEvent, map[string]string{"severity": "critical"}))
+	if len(calls) != 1 {
+		t.Errorf("calls = %v, want 只处理 critical 事件", calls)
+	}
+}
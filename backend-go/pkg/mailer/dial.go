@@ -0,0 +1,32 @@
+// Package mailer 提供受上下文截止时间约束的 SMTP 连接
+//
+// net/smtp 的 Dial 与 SendMail 不接受 context，SMTP 服务无响应时会一直阻塞；
+// 邮件发送与外部依赖健康检查都通过 Dial 建立连接。
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// Dial 连接 SMTP 服务器并读取问候，连接与之后的整个会话受 ctx 的截止时间约束
+func Dial(ctx context.Context, host string, port int) (*smtp.Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+	return client, nil
+}
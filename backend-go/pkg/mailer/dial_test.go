@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listen 启动 TCP 服务，handle 处理每个连接，返回主机与端口
+func listen(t *testing.T, handle func(net.Conn)) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestDial_ReadsGreeting(t *testing.T) {
+	host, port := listen(t, func(conn net.Conn) {
+		defer conn.Close()
+		conn.Write([]byte("220 fake ESMTP\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.HasPrefix(line, "QUIT") {
+				conn.Write([]byte("221 bye\r\n"))
+				return
+			}
+			conn.Write([]byte("250 ok\r\n"))
+		}
+	})
+
+	client, err := Dial(context.Background(), host, port)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	if err := client.Noop(); err != nil {
+		t.Errorf("Noop() error = %v", err)
+	}
+	client.Quit()
+}
+
+func TestDial_SilentServerTimesOut(t *testing.T) {
+	host, port := listen(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+		conn.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := Dial(ctx, host, port)
+	if err == nil || !strings.Contains(err.Error(), "handshake failed") {
+		t.Fatalf("Dial() error = %v, want handshake failure", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("无响应的服务应在截止时间后返回, 用时 %s", elapsed)
+	}
+}
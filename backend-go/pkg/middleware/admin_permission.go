@@ -14,6 +14,7 @@ import (
 
 	"backend-go/internal/core/domain/admin"
 	"backend-go/internal/core/ports"
+	"backend-go/pkg/ctxkeys"
 	"backend-go/pkg/response"
 )
 
@@ -147,6 +148,7 @@ func (m *AdminPermissionMiddleware) RequireAdminLevel(level admin.AdminLevel) gi
 		// 将管理员信息存储到上下文中
 		c.Set("admin_user", adminUser)
 		c.Set("admin_level", adminUser.AdminLevel)
+		c.Request = c.Request.WithContext(ctxkeys.WithAdminUserID(c.Request.Context(), adminUser.UserID))
 		c.Next()
 	}
 }
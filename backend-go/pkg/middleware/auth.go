@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"backend-go/internal/shared/jwt"
+	"backend-go/pkg/ctxkeys"
 	"backend-go/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -47,10 +48,7 @@ func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("user_role", claims.Role)
-		c.Set("claims", claims)
+		setCurrentClaims(c, claims)
 
 		c.Next()
 	}
@@ -100,15 +98,22 @@ func (a *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("user_role", claims.Role)
-		c.Set("claims", claims)
+		setCurrentClaims(c, claims)
 
 		c.Next()
 	}
 }
 
+// setCurrentClaims 将令牌声明写入 gin 上下文与请求上下文
+func setCurrentClaims(c *gin.Context, claims *jwt.Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("user_role", claims.Role)
+	c.Set("claims", claims)
+
+	c.Request = c.Request.WithContext(ctxkeys.WithUser(c.Request.Context(), claims.UserID, claims.Username, claims.Role))
+}
+
 // extractToken 从请求中提取令牌
 func (a *AuthMiddleware) extractToken(c *gin.Context) string {
 	// 从 Authorization header 中提取
@@ -160,6 +165,11 @@ func GetCurrentUser(c *gin.Context) (userID uint, username string, role string,
 
 // GetCurrentUserID 获取当前用户ID的辅助函数
 func GetCurrentUserID(c *gin.Context) (uint, bool) {
+	if c.Request != nil {
+		if userID, ok := ctxkeys.UserIDFrom(c.Request.Context()); ok {
+			return userID, true
+		}
+	}
 	userID, _, _, exists := GetCurrentUser(c)
 	return userID, exists
 }
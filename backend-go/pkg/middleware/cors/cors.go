@@ -2,6 +2,9 @@ package cors
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -40,3 +43,80 @@ func CORS() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Config 按配置生效的 CORS 设置
+type Config struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// New 按配置创建跨域中间件，只为允许的来源返回 CORS 响应头
+//
+// AllowedHeaders 包含 "*" 时回显预检请求的 Access-Control-Request-Headers。
+func New(config Config) gin.HandlerFunc {
+	allowAll := false
+	origins := make(map[string]bool, len(config.AllowedOrigins))
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		origins[origin] = true
+	}
+	allowAnyHeader := false
+	for _, header := range config.AllowedHeaders {
+		if header == "*" {
+			allowAnyHeader = true
+		}
+	}
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if origin != "" && (allowAll || origins[origin]) {
+			if allowAll && !config.AllowCredentials {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+
+			if c.Request.Method == http.MethodOptions {
+				if methods != "" {
+					c.Header("Access-Control-Allow-Methods", methods)
+				}
+				if allowAnyHeader {
+					if requested := c.Request.Header.Get("Access-Control-Request-Headers"); requested != "" {
+						c.Header("Access-Control-Allow-Headers", requested)
+					}
+				} else if headers != "" {
+					c.Header("Access-Control-Allow-Headers", headers)
+				}
+				if config.MaxAge > 0 {
+					c.Header("Access-Control-Max-Age", maxAge)
+				}
+			}
+		}
+
+		// 处理预检请求
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
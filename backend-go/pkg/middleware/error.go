@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime/debug"
@@ -120,10 +122,8 @@ func RecoveryMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 					"user_agent": c.Request.UserAgent(),
 				}).Error("Panic recovered")
 
-				// 返回500错误
-				if !c.Writer.Written() {
-					response.InternalError(c, "服务器内部错误")
-				}
+				// 携带 AppError 的 panic 按其状态码响应，其他 panic 返回脱敏的 500
+				response.HandleError(c, panicError(err))
 				c.Abort()
 			}
 		}()
@@ -146,17 +146,17 @@ func (h *ErrorHandler) handlePanic(c *gin.Context, err interface{}) {
 		"request_id": getRequestID(c),
 	}).Error("Panic recovered")
 
-	// 创建内部错误
-	appErr := response.NewInternalError("服务器内部错误")
-	if h.enableStack {
-		appErr.WithStack()
-	}
+	// 携带 AppError 的 panic 按其状态码响应，其他 panic 返回脱敏的 500
+	response.HandleError(c, panicError(err))
+	c.Abort()
+}
 
-	// 返回错误响应
-	if !c.Writer.Written() {
-		response.Error(c, http.StatusInternalServerError, "Internal server error", appErr.Error())
+// panicError 将 recover 得到的值转换为 error
+func panicError(recovered interface{}) error {
+	if err, ok := recovered.(error); ok {
+		return err
 	}
-	c.Abort()
+	return fmt.Errorf("panic: %v", recovered)
 }
 
 // handleErrors 处理错误
@@ -172,39 +172,13 @@ func (h *ErrorHandler) handleErrors(c *gin.Context) {
 	// 记录错误日志
 	h.logError(c, err)
 
-	// 如果已经写入响应，则不再处理
-	if c.Writer.Written() {
-		return
-	}
-
-	// 处理不同类型的错误
-	switch {
-	case response.IsAppError(err):
-		h.handleAppError(c, err.(*response.AppError))
-	default:
-		h.handleGenericError(c, err)
-	}
-}
-
-// handleAppError 处理应用错误
-func (h *ErrorHandler) handleAppError(c *gin.Context, err *response.AppError) {
 	// 添加堆栈信息（如果启用）
-	if h.enableStack && err.Stack == "" {
-		err.WithStack()
-	}
-
-	response.Error(c, err.StatusCode, err.Message, err.Error())
-}
-
-// handleGenericError 处理通用错误
-func (h *ErrorHandler) handleGenericError(c *gin.Context, err error) {
-	// 创建内部错误
-	appErr := response.NewInternalError(err.Error())
-	if h.enableStack {
+	if appErr, ok := err.(*response.AppError); ok && h.enableStack && appErr.Stack == "" {
 		appErr.WithStack()
 	}
 
-	response.Error(c, http.StatusInternalServerError, "Internal server error", appErr.Error())
+	// 按错误携带的状态码响应，响应已写出时不再处理
+	response.HandleError(c, err)
 }
 
 // logError 记录错误日志
@@ -362,40 +336,42 @@ func ValidationErrorHandler() gin.HandlerFunc {
 	}
 }
 
-// TimeoutErrorHandler 超时错误处理器
-func TimeoutErrorHandler(timeout time.Duration) gin.HandlerFunc {
+// TimeoutErrorHandler 为请求设置处理时限，下游数据库与缓存调用按剩余时间预算执行
+//
+// 处理器在当前协程中同步执行，超时后由下游调用按 ctx 取消返回，避免处理器与超时响应
+// 并发写入同一个 gin.Context。处理器超时且未写响应时返回 408。路径以 skipPrefixes
+// 中任一前缀开头的请求（SSE、WebSocket 等长连接）不设时限；timeout <= 0 时不启用。
+func TimeoutErrorHandler(timeout time.Duration, skipPrefixes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 设置超时上下文
-		ctx, cancel := c.Request.Context(), func() {}
-		if timeout > 0 {
-			ctx, cancel = c.Request.Context(), cancel
+		if timeout <= 0 || hasPathPrefix(c.Request.URL.Path, skipPrefixes) {
+			c.Next()
+			return
 		}
-		defer cancel()
 
-		// 替换请求上下文
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
 		c.Request = c.Request.WithContext(ctx)
 
-		// 处理请求
-		done := make(chan struct{})
-		go func() {
-			defer close(done)
-			c.Next()
-		}()
+		c.Next()
 
-		select {
-		case <-done:
-			// 请求正常完成
-		case <-ctx.Done():
-			// 请求超时
-			if !c.Writer.Written() {
-				timeoutErr := response.NewTimeoutError("请求处理超时")
-				response.Error(c, http.StatusRequestTimeout, "Request timeout", timeoutErr.Error())
-			}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			timeoutErr := response.NewTimeoutError("请求处理超时")
+			response.Error(c, http.StatusRequestTimeout, "Request timeout", timeoutErr.Error())
 			c.Abort()
 		}
 	}
 }
 
+// hasPathPrefix 判断路径是否以任一前缀开头
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // NotFoundHandler 404处理器
 func NotFoundHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/pkg/response"
+)
+
+// recordingReporter 记录上报的错误
+type recordingReporter struct {
+	errs []error
+}
+
+func (r *recordingReporter) Report(c *gin.Context, err error) {
+	r.errs = append(r.errs, err)
+}
+
+// serveWithRecovery 经过错误报告与恢复中间件执行 handler
+func serveWithRecovery(t *testing.T, handler gin.HandlerFunc) (*httptest.ResponseRecorder, response.Response, *recordingReporter) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	reporter := &recordingReporter{}
+
+	router := gin.New()
+	router.Use(ErrorReportingMiddleware(reporter))
+	router.Use(RecoveryMiddleware(log))
+	router.GET("/", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var body response.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("响应不是合法 JSON: %v, body = %s", err, w.Body.String())
+	}
+	return w, body, reporter
+}
+
+func TestRecoveryMiddleware_PanicWithAppError(t *testing.T) {
+	w, body, reporter := serveWithRecovery(t, func(c *gin.Context) {
+		panic(response.NewNotFoundError("比赛不存在"))
+	})
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	if body.Success || body.Error == nil || body.Error.Code != http.StatusNotFound || body.Message != "比赛不存在" {
+		t.Errorf("body = %+v, want structured 404", body)
+	}
+	if len(reporter.errs) != 1 || !response.IsNotFoundError(reporter.errs[0]) {
+		t.Errorf("reported = %v, want the not found error", reporter.errs)
+	}
+}
+
+func TestRecoveryMiddleware_PanicWithWrappedAppError(t *testing.T) {
+	w, _, _ := serveWithRecovery(t, func(c *gin.Context) {
+		panic(fmt.Errorf("load match: %w", response.NewConflictError("比赛已结束", nil)))
+	})
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", w.Code)
+	}
+}
+
+func TestRecoveryMiddleware_PanicWithPlainErrorIsRedacted(t *testing.T) {
+	w, body, reporter := serveWithRecovery(t, func(c *gin.Context) {
+		panic(errors.New("dial tcp 10.0.0.5:3306: password=hunter2"))
+	})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if body.Error == nil || body.Error.Code != http.StatusInternalServerError {
+		t.Errorf("body = %+v, want structured 500", body)
+	}
+	if strings.Contains(w.Body.String(), "hunter2") || strings.Contains(w.Body.String(), "10.0.0.5") {
+		t.Errorf("响应泄露了原始错误: %s", w.Body.String())
+	}
+	if len(reporter.errs) != 1 {
+		t.Errorf("reported = %v, want the original error", reporter.errs)
+	}
+}
+
+func TestRecoveryMiddleware_PanicWithNonError(t *testing.T) {
+	w, _, _ := serveWithRecovery(t, func(c *gin.Context) {
+		panic("boom")
+	})
+
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "boom") {
+		t.Errorf("status = %d, body = %s, want redacted 500", w.Code, w.Body.String())
+	}
+}
+
+func TestErrorHandlerMiddleware_ReturnedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	router := gin.New()
+	router.Use(NewErrorHandler(WithLogger(log)).ErrorHandlerMiddleware())
+	router.GET("/forbidden", func(c *gin.Context) {
+		_ = c.Error(response.NewForbiddenError("无权访问"))
+	})
+	router.GET("/plain", func(c *gin.Context) {
+		_ = c.Error(errors.New("sql: connection refused"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/forbidden", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("/forbidden status = %d, want 403", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/plain", nil))
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "connection refused") {
+		t.Errorf("/plain status = %d, body = %s, want redacted 500", w.Code, w.Body.String())
+	}
+}
+
+func TestTimeoutErrorHandler_SetsDeadlineExceptStreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(TimeoutErrorHandler(20*time.Millisecond, "/api/stream/"))
+	deadlineOf := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"has_deadline": ok})
+	}
+	router.GET("/api/matches", deadlineOf)
+	router.GET("/api/stream/notifications", deadlineOf)
+	router.GET("/api/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+
+	for path, want := range map[string]bool{"/api/matches": true, "/api/stream/notifications": false} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if got := strings.Contains(w.Body.String(), `"has_deadline":true`); got != want {
+			t.Errorf("%s has deadline = %v, want %v", path, got, want)
+		}
+	}
+
+	// 处理器超时且未写响应时返回 408
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if w.Code != http.StatusRequestTimeout {
+		t.Errorf("slow handler status = %d, want 408", w.Code)
+	}
+}
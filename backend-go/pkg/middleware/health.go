@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	redis "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
@@ -282,12 +284,27 @@ func (h *MemoryHealthChecker) Check(ctx context.Context) ComponentHealth {
 	}
 }
 
+// HealthFreshQuery 详细健康检查跳过缓存、重新检查的查询参数，用于排查
+const HealthFreshQuery = "fresh"
+
+// healthFreshMinInterval fresh 请求重新检查的最小间隔，间隔内仍返回上次结果，
+// 避免未认证的 ?fresh=true 被用来反复访问数据库与 Redis
+const healthFreshMinInterval = time.Second
+
 // HealthService 健康检查服务
 type HealthService struct {
 	checkers []HealthChecker
 	version  string
 	timeout  time.Duration
 	mu       sync.RWMutex
+
+	// 详细健康检查结果缓存，cacheTTL 为 0 时不缓存
+	cacheTTL time.Duration
+	cacheMu  sync.Mutex
+	cached   HealthResponse
+	cachedAt time.Time
+	now      func() time.Time
+	inflight singleflight.Group
 }
 
 // NewHealthService 创建健康检查服务
@@ -299,7 +316,48 @@ func NewHealthService(version string, timeout time.Duration) *HealthService {
 		checkers: make([]HealthChecker, 0),
 		version:  version,
 		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// SetCacheTTL 设置详细健康检查结果的缓存时长，0 表示每次重新检查
+//
+// 大量实例与监控面板同时轮询详细健康检查时，窗口内的请求共享同一次检查结果，
+// 避免反复访问数据库、Redis 与外部依赖。
+func (s *HealthService) SetCacheTTL(ttl time.Duration) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cacheTTL = ttl
+	s.cachedAt = time.Time{}
+}
+
+// CheckCached 返回缓存时长内的上次检查结果，过期或 fresh 为 true 时重新检查
+//
+// fresh 请求距上次检查不足 healthFreshMinInterval 时仍返回上次结果。
+// 并发请求共享同一次检查，检查不随单个请求取消而中断，避免把客户端断开缓存成不健康。
+func (s *HealthService) CheckCached(ctx context.Context, fresh bool) HealthResponse {
+	s.cacheMu.Lock()
+	if s.cacheTTL > 0 && !s.cachedAt.IsZero() {
+		age := s.now().Sub(s.cachedAt)
+		if age < s.cacheTTL && (!fresh || age < healthFreshMinInterval) {
+			health := s.cached
+			s.cacheMu.Unlock()
+			return health
+		}
 	}
+	s.cacheMu.Unlock()
+
+	result, _, _ := s.inflight.Do("detailed", func() (interface{}, error) {
+		health := s.Check(context.WithoutCancel(ctx))
+		s.cacheMu.Lock()
+		if s.cacheTTL > 0 {
+			s.cached = health
+			s.cachedAt = s.now()
+		}
+		s.cacheMu.Unlock()
+		return health, nil
+	})
+	return result.(HealthResponse)
 }
 
 // AddChecker 添加健康检查器
@@ -385,10 +443,10 @@ func HealthMiddleware(service *HealthService) gin.HandlerFunc {
 			return
 		}
 
-		// 详细健康检查
+		// 详细健康检查，默认复用缓存时长内的结果，?fresh=true 时重新检查
 		if c.Request.URL.Path == "/health/detailed" {
-			ctx := c.Request.Context()
-			health := service.Check(ctx)
+			fresh, _ := strconv.ParseBool(c.Query(HealthFreshQuery))
+			health := service.CheckCached(c.Request.Context(), fresh)
 
 			statusCode := http.StatusOK
 			if health.Status == HealthStatusUnhealthy {
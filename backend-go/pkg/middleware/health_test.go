@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countingHealthChecker 记录检查次数的健康检查器
+type countingHealthChecker struct {
+	name  string
+	calls atomic.Int32
+}
+
+func (h *countingHealthChecker) Name() string {
+	return h.name
+}
+
+func (h *countingHealthChecker) Check(ctx context.Context) ComponentHealth {
+	h.calls.Add(1)
+	return ComponentHealth{Status: HealthStatusHealthy, Timestamp: time.Now()}
+}
+
+func newDetailedHealthRouter(ttl time.Duration, checkers ...HealthChecker) (*gin.Engine, *HealthService) {
+	gin.SetMode(gin.TestMode)
+
+	service := NewHealthService("test", time.Second)
+	service.SetCacheTTL(ttl)
+	for _, checker := range checkers {
+		service.AddChecker(checker)
+	}
+
+	router := gin.New()
+	router.Use(HealthMiddleware(service))
+	router.GET(ReadyzPath, ReadyzHandler(service, startedProbeState()))
+	return router, service
+}
+
+func startedProbeState() *ProbeState {
+	state := NewProbeState()
+	state.MarkStarted()
+	return state
+}
+
+func TestDetailedHealth_ReusesCachedResultWithinWindow(t *testing.T) {
+	database := &countingHealthChecker{name: "database"}
+	redis := &countingHealthChecker{name: "redis"}
+	router, service := newDetailedHealthRouter(3*time.Second, database, redis)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		if w := probe(router, "/health/detailed"); w.Code != http.StatusOK {
+			t.Fatalf("/health/detailed = %d, want 200", w.Code)
+		}
+	}
+	if database.calls.Load() != 1 || redis.calls.Load() != 1 {
+		t.Errorf("缓存时长内应只检查一次, database=%d redis=%d", database.calls.Load(), redis.calls.Load())
+	}
+
+	// 缓存过期后重新检查
+	now = now.Add(3 * time.Second)
+	probe(router, "/health/detailed")
+	if database.calls.Load() != 2 {
+		t.Errorf("缓存过期后应重新检查, database=%d", database.calls.Load())
+	}
+}
+
+func TestDetailedHealth_FreshQueryBypassesCache(t *testing.T) {
+	database := &countingHealthChecker{name: "database"}
+	router, service := newDetailedHealthRouter(time.Minute, database)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	probe(router, "/health/detailed")
+	now = now.Add(healthFreshMinInterval)
+	probe(router, "/health/detailed?fresh=true")
+	now = now.Add(healthFreshMinInterval)
+	probe(router, "/health/detailed?fresh=1")
+	if got := database.calls.Load(); got != 3 {
+		t.Errorf("fresh 请求应跳过缓存, database=%d, want 3", got)
+	}
+
+	// fresh 请求的结果也会刷新缓存
+	probe(router, "/health/detailed")
+	if got := database.calls.Load(); got != 3 {
+		t.Errorf("fresh 请求后应复用其结果, database=%d, want 3", got)
+	}
+}
+
+func TestReadyz_DoesNotUseDetailedHealthCache(t *testing.T) {
+	database := &countingHealthChecker{name: "database"}
+	router, _ := newDetailedHealthRouter(time.Minute, database)
+
+	probe(router, "/health/detailed")
+	probe(router, ReadyzPath)
+	probe(router, ReadyzPath)
+	if got := database.calls.Load(); got != 3 {
+		t.Errorf("/readyz 每次都应检查依赖, database=%d, want 3", got)
+	}
+}
+
+func TestDetailedHealth_FreshQueryIsRateLimited(t *testing.T) {
+	database := &countingHealthChecker{name: "database"}
+	router, service := newDetailedHealthRouter(time.Minute, database)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	probe(router, "/health/detailed")
+	for i := 0; i < 5; i++ {
+		probe(router, "/health/detailed?fresh=true")
+	}
+	if got := database.calls.Load(); got != 1 {
+		t.Errorf("最小间隔内的 fresh 请求应复用上次结果, database=%d, want 1", got)
+	}
+}
+
+// ctxHealthChecker 按传入 context 是否已取消返回健康状态
+type ctxHealthChecker struct{}
+
+func (ctxHealthChecker) Name() string {
+	return "database"
+}
+
+func (ctxHealthChecker) Check(ctx context.Context) ComponentHealth {
+	if ctx.Err() != nil {
+		return ComponentHealth{Status: HealthStatusUnhealthy, Timestamp: time.Now()}
+	}
+	return ComponentHealth{Status: HealthStatusHealthy, Timestamp: time.Now()}
+}
+
+func TestCheckCached_IgnoresCallerCancellation(t *testing.T) {
+	service := NewHealthService("test", time.Second)
+	service.SetCacheTTL(time.Minute)
+	service.AddChecker(ctxHealthChecker{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := service.CheckCached(ctx, false).Status; got != HealthStatusHealthy {
+		t.Errorf("客户端取消不应影响检查结果, status=%s", got)
+	}
+	if got := service.CheckCached(context.Background(), false).Status; got != HealthStatusHealthy {
+		t.Errorf("缓存的结果 status=%s, want healthy", got)
+	}
+}
@@ -2,12 +2,13 @@ package middleware
 
 import (
 	"bytes"
-	"context"
 	"io"
 	"strconv"
 	"time"
 
 	"backend-go/internal/shared/logger"
+	"backend-go/pkg/ctxkeys"
+	"backend-go/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -80,7 +81,7 @@ func LoggingMiddleware(config *LoggingConfig) gin.HandlerFunc {
 		}
 
 		// 创建上下文
-		ctx := context.WithValue(c.Request.Context(), logger.RequestIDKey, requestID)
+		ctx := ctxkeys.WithCorrelationID(c.Request.Context(), requestID)
 		c.Request = c.Request.WithContext(ctx)
 
 		// 读取请求体
@@ -198,7 +199,7 @@ func ErrorLoggingMiddleware() gin.HandlerFunc {
 		// 记录错误
 		for _, err := range c.Errors {
 			requestID := GetRequestID(c)
-			ctx := context.WithValue(c.Request.Context(), logger.RequestIDKey, requestID)
+			ctx := ctxkeys.WithCorrelationID(c.Request.Context(), requestID)
 
 			logger.WithContext(ctx).WithFields(logrus.Fields{
 				"method":     c.Request.Method,
@@ -214,7 +215,7 @@ func ErrorLoggingMiddleware() gin.HandlerFunc {
 func RecoveryLoggingMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		requestID := GetRequestID(c)
-		ctx := context.WithValue(c.Request.Context(), logger.RequestIDKey, requestID)
+		ctx := ctxkeys.WithCorrelationID(c.Request.Context(), requestID)
 
 		logger.WithContext(ctx).WithFields(logrus.Fields{
 			"method":   c.Request.Method,
@@ -223,7 +224,8 @@ func RecoveryLoggingMiddleware() gin.HandlerFunc {
 			"panic":    recovered,
 		}).Error("Panic recovered")
 
-		c.AbortWithStatus(500)
+		response.HandleError(c, panicError(recovered))
+		c.Abort()
 	})
 }
 
@@ -333,7 +335,7 @@ func StructuredLoggingMiddleware() gin.HandlerFunc {
 		requestID := GetRequestID(c)
 		
 		// 添加到上下文
-		ctx := context.WithValue(c.Request.Context(), logger.RequestIDKey, requestID)
+		ctx := ctxkeys.WithCorrelationID(c.Request.Context(), requestID)
 		c.Request = c.Request.WithContext(ctx)
 
 		// 添加到响应头
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// MetricsSnapshotContentType 指标快照的内容类型（Prometheus 文本格式）
+var MetricsSnapshotContentType = string(expfmt.NewFormat(expfmt.TypeTextPlain))
+
+// MetricsSnapshotFilename 返回指标快照的下载文件名
+func MetricsSnapshotFilename(takenAt time.Time) string {
+	return fmt.Sprintf("metrics-snapshot-%s.prom", takenAt.UTC().Format("20060102T150405Z"))
+}
+
+// WriteMetricsSnapshot 将当前指标以 Prometheus 文本格式写出，用于离线分析
+//
+// 开头的注释行记录快照时间与构建信息，解析器会忽略这些注释；
+// gatherer 为空时使用与 /metrics 相同的默认注册表。
+func WriteMetricsSnapshot(w io.Writer, gatherer prometheus.Gatherer, takenAt time.Time) error {
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "# Metrics snapshot taken at %s\n", takenAt.UTC().Format(time.RFC3339))
+	for _, line := range buildInfoLines() {
+		fmt.Fprintf(out, "# %s\n", line)
+	}
+
+	encoder := expfmt.NewEncoder(out, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return fmt.Errorf("failed to encode metric %s: %w", family.GetName(), err)
+		}
+	}
+	return out.Flush()
+}
+
+// buildInfoLines 返回 Go 版本、模块版本与 VCS 信息
+func buildInfoLines() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return []string{"Go version: " + runtime.Version()}
+	}
+
+	lines := []string{
+		"Go version: " + info.GoVersion,
+		fmt.Sprintf("Module: %s %s", info.Main.Path, info.Main.Version),
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			lines = append(lines, fmt.Sprintf("%s: %s", setting.Key, setting.Value))
+		}
+	}
+	return lines
+}
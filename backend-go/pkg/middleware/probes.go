@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Kubernetes 探针路径
+const (
+	LivezPath  = "/livez"
+	ReadyzPath = "/readyz"
+)
+
+// 未就绪原因
+const (
+	NotReadyStarting    = "starting"
+	NotReadyMaintenance = "maintenance"
+	NotReadyUnhealthy   = "unhealthy"
+)
+
+// ProbeState 就绪探针状态
+//
+// 启动探针通过前以及维护模式期间 /readyz 返回未就绪，/livez 不受影响。
+type ProbeState struct {
+	started     atomic.Bool
+	maintenance atomic.Bool
+}
+
+// NewProbeState 创建就绪探针状态，初始为启动中
+func NewProbeState() *ProbeState {
+	return &ProbeState{}
+}
+
+// MarkStarted 标记启动完成
+func (s *ProbeState) MarkStarted() {
+	s.started.Store(true)
+}
+
+// Started 是否已启动完成
+func (s *ProbeState) Started() bool {
+	return s.started.Load()
+}
+
+// SetMaintenance 开启或关闭维护模式
+func (s *ProbeState) SetMaintenance(enabled bool) {
+	s.maintenance.Store(enabled)
+}
+
+// InMaintenance 是否处于维护模式
+func (s *ProbeState) InMaintenance() bool {
+	return s.maintenance.Load()
+}
+
+// LivezHandler 存活探针，只表示进程可以处理请求，不检查任何依赖
+func LivezHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// ReadyzHandler 就绪探针，启动完成、未处于维护模式且依赖组件均不是 unhealthy 时返回 200，否则返回 503
+//
+// degraded 组件（如内存偏高）不影响就绪，避免实例被整体摘除流量。
+func ReadyzHandler(service *HealthService, state *ProbeState) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !state.Started() {
+			notReady(c, NotReadyStarting, nil)
+			return
+		}
+		if state.InMaintenance() {
+			notReady(c, NotReadyMaintenance, nil)
+			return
+		}
+
+		health := service.Check(c.Request.Context())
+		if health.Status == HealthStatusUnhealthy {
+			var failing []string
+			for name, component := range health.Components {
+				if component.Status == HealthStatusUnhealthy {
+					failing = append(failing, name)
+				}
+			}
+			sort.Strings(failing)
+			notReady(c, NotReadyUnhealthy, failing)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	}
+}
+
+// notReady 返回 503 及未就绪原因
+func notReady(c *gin.Context, reason string, failing []string) {
+	body := gin.H{"status": "not_ready", "reason": reason}
+	if len(failing) > 0 {
+		body["failing"] = failing
+	}
+	c.JSON(http.StatusServiceUnavailable, body)
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// staticHealthChecker 返回固定状态的健康检查器
+type staticHealthChecker struct {
+	name   string
+	status HealthStatus
+}
+
+func (h *staticHealthChecker) Name() string {
+	return h.name
+}
+
+func (h *staticHealthChecker) Check(ctx context.Context) ComponentHealth {
+	return ComponentHealth{Status: h.status, Timestamp: time.Now()}
+}
+
+func newProbeRouter(state *ProbeState, checkers ...HealthChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := NewHealthService("test", time.Second)
+	for _, checker := range checkers {
+		service.AddChecker(checker)
+	}
+
+	router := gin.New()
+	router.GET(LivezPath, LivezHandler())
+	router.GET(ReadyzPath, ReadyzHandler(service, state))
+	return router
+}
+
+func probe(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestProbes_RedisDown(t *testing.T) {
+	state := NewProbeState()
+	state.MarkStarted()
+	router := newProbeRouter(state,
+		&staticHealthChecker{name: "database", status: HealthStatusHealthy},
+		&staticHealthChecker{name: "redis", status: HealthStatusUnhealthy},
+	)
+
+	if w := probe(router, LivezPath); w.Code != http.StatusOK {
+		t.Errorf("Redis 不可用时 /livez 应返回 200, got %d", w.Code)
+	}
+
+	w := probe(router, ReadyzPath)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Redis 不可用时 /readyz 应返回 503, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"failing":["redis"]`) {
+		t.Errorf("响应应列出不健康组件: %s", w.Body.String())
+	}
+}
+
+func TestProbes_ReadyWhenHealthyOrDegraded(t *testing.T) {
+	state := NewProbeState()
+	state.MarkStarted()
+	router := newProbeRouter(state,
+		&staticHealthChecker{name: "redis", status: HealthStatusHealthy},
+		&staticHealthChecker{name: "memory", status: HealthStatusDegraded},
+	)
+
+	if w := probe(router, ReadyzPath); w.Code != http.StatusOK {
+		t.Errorf("依赖正常时 /readyz 应返回 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestProbes_NotReadyDuringStartupAndMaintenance(t *testing.T) {
+	state := NewProbeState()
+	router := newProbeRouter(state, &staticHealthChecker{name: "redis", status: HealthStatusHealthy})
+
+	w := probe(router, ReadyzPath)
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), NotReadyStarting) {
+		t.Errorf("启动完成前应未就绪, got %d: %s", w.Code, w.Body.String())
+	}
+
+	state.MarkStarted()
+	state.SetMaintenance(true)
+	w = probe(router, ReadyzPath)
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), NotReadyMaintenance) {
+		t.Errorf("维护模式应未就绪, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := probe(router, LivezPath); w.Code != http.StatusOK {
+		t.Errorf("维护模式下 /livez 应返回 200, got %d", w.Code)
+	}
+
+	state.SetMaintenance(false)
+	if w := probe(router, ReadyzPath); w.Code != http.StatusOK {
+		t.Errorf("退出维护模式后应就绪, got %d", w.Code)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/ctxkeys"
 )
 
 const (
@@ -79,8 +81,7 @@ func RequestIDMiddleware(generator RequestIDGenerator) gin.HandlerFunc {
 		}
 
 		// 设置请求ID到上下文和响应头
-		c.Set(RequestIDKey, requestID)
-		c.Header(RequestIDHeader, requestID)
+		SetRequestID(c, requestID)
 
 		c.Next()
 	}
@@ -108,12 +109,18 @@ func GetRequestID(c *gin.Context) string {
 			return id
 		}
 	}
+	if c.Request != nil {
+		if id, ok := ctxkeys.CorrelationIDFrom(c.Request.Context()); ok {
+			return id
+		}
+	}
 	return ""
 }
 
-// SetRequestID 设置请求ID到上下文
+// SetRequestID 设置请求ID到上下文，同时写入请求上下文供服务层读取
 func SetRequestID(c *gin.Context, requestID string) {
 	c.Set(RequestIDKey, requestID)
+	c.Request = c.Request.WithContext(ctxkeys.WithCorrelationID(c.Request.Context(), requestID))
 	c.Header(RequestIDHeader, requestID)
 }
 
@@ -262,8 +269,7 @@ func ValidatingRequestIDMiddleware(generator RequestIDGenerator, validator Reque
 			requestID = generator.Generate()
 		}
 
-		c.Set(RequestIDKey, requestID)
-		c.Header(RequestIDHeader, requestID)
+		SetRequestID(c, requestID)
 
 		c.Next()
 	}
@@ -303,8 +309,7 @@ func MetricsRequestIDMiddleware(generator RequestIDGenerator, metrics *RequestID
 			metrics.GeneratedCount++
 		}
 
-		c.Set(RequestIDKey, requestID)
-		c.Header(RequestIDHeader, requestID)
+		SetRequestID(c, requestID)
 
 		c.Next()
 	}
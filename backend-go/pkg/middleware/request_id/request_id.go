@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 
 	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/ctxkeys"
 )
 
 const (
@@ -25,8 +27,9 @@ func RequestID() gin.HandlerFunc {
 			requestID = generateRequestID()
 		}
 
-		// 设置到上下文和响应头
+		// 设置到上下文和响应头，请求上下文中的关联ID供服务层与日志读取
 		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(ctxkeys.WithCorrelationID(c.Request.Context(), requestID))
 		c.Header(RequestIDHeader, requestID)
 
 		c.Next()
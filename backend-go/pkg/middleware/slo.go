@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SLOConfig 接口延迟 SLO 配置
+type SLOConfig struct {
+	// Target 延迟目标，低于该值的请求计为达标
+	Target time.Duration
+	// Window 滚动统计窗口
+	Window time.Duration
+	// MaxSamples 每个路由保留的最大样本数，限制内存占用
+	MaxSamples int
+}
+
+// DefaultSLOConfig 默认 SLO 配置（100ms 目标，5 分钟窗口）
+func DefaultSLOConfig() SLOConfig {
+	return SLOConfig{
+		Target:     100 * time.Millisecond,
+		Window:     5 * time.Minute,
+		MaxSamples: 10000,
+	}
+}
+
+// RouteSLO 单个路由的 SLO 达成情况
+type RouteSLO struct {
+	Method       string  `json:"method"`
+	Route        string  `json:"route"`
+	Requests     int     `json:"requests"`
+	WithinTarget int     `json:"within_target"`
+	Attainment   float64 `json:"attainment"` // 达标比例（百分比）
+	P95Ms        float64 `json:"p95_ms"`
+	P99Ms        float64 `json:"p99_ms"`
+}
+
+// SLOReport SLO 报告
+type SLOReport struct {
+	TargetMs    float64    `json:"target_ms"`
+	Window      string     `json:"window"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Routes      []RouteSLO `json:"routes"`
+}
+
+// sloSample 单次请求样本
+type sloSample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// routeSamples 路由样本环形缓冲
+type routeSamples struct {
+	samples []sloSample
+	next    int
+}
+
+// SLOTracker 按路由记录请求延迟并计算窗口内的 SLO 达成率
+type SLOTracker struct {
+	config SLOConfig
+	now    func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*routeSamples
+}
+
+// NewSLOTracker 创建 SLO 追踪器
+func NewSLOTracker(config SLOConfig) *SLOTracker {
+	defaults := DefaultSLOConfig()
+	if config.Target <= 0 {
+		config.Target = defaults.Target
+	}
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.MaxSamples <= 0 {
+		config.MaxSamples = defaults.MaxSamples
+	}
+
+	return &SLOTracker{
+		config: config,
+		now:    time.Now,
+		routes: make(map[string]*routeSamples),
+	}
+}
+
+// Middleware SLO 记录中间件，按路由模板（如 /api/matches/:id）聚合
+func (t *SLOTracker) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			// 未匹配的路由不计入，避免任意路径撑爆内存
+			return
+		}
+		t.Record(c.Request.Method, route, time.Since(start))
+	}
+}
+
+// Record 记录一次请求延迟
+func (t *SLOTracker) Record(method, route string, latency time.Duration) {
+	key := method + " " + route
+	sample := sloSample{at: t.now(), latency: latency}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rs, ok := t.routes[key]
+	if !ok {
+		rs = &routeSamples{}
+		t.routes[key] = rs
+	}
+	if len(rs.samples) < t.config.MaxSamples {
+		rs.samples = append(rs.samples, sample)
+		return
+	}
+	rs.samples[rs.next] = sample
+	rs.next = (rs.next + 1) % t.config.MaxSamples
+}
+
+// Report 生成窗口内各路由的 SLO 报告，按达成率升序排列，最差的路由在前
+func (t *SLOTracker) Report() SLOReport {
+	now := t.now()
+	cutoff := now.Add(-t.config.Window)
+
+	report := SLOReport{
+		TargetMs:    durationMs(t.config.Target),
+		Window:      t.config.Window.String(),
+		GeneratedAt: now,
+		Routes:      []RouteSLO{},
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, rs := range t.routes {
+		latencies := make([]time.Duration, 0, len(rs.samples))
+		for _, s := range rs.samples {
+			if s.at.After(cutoff) {
+				latencies = append(latencies, s.latency)
+			}
+		}
+		if len(latencies) == 0 {
+			delete(t.routes, key)
+			continue
+		}
+
+		method, route, _ := strings.Cut(key, " ")
+		report.Routes = append(report.Routes, computeRouteSLO(method, route, latencies, t.config.Target))
+	}
+
+	sort.Slice(report.Routes, func(i, j int) bool {
+		if report.Routes[i].Attainment != report.Routes[j].Attainment {
+			return report.Routes[i].Attainment < report.Routes[j].Attainment
+		}
+		return report.Routes[i].Route < report.Routes[j].Route
+	})
+	return report
+}
+
+// computeRouteSLO 计算达成率与 P95/P99
+func computeRouteSLO(method, route string, latencies []time.Duration, target time.Duration) RouteSLO {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	within := 0
+	for _, l := range latencies {
+		if l <= target {
+			within++
+		}
+	}
+
+	return RouteSLO{
+		Method:       method,
+		Route:        route,
+		Requests:     len(latencies),
+		WithinTarget: within,
+		Attainment:   math.Round(float64(within)/float64(len(latencies))*10000) / 100,
+		P95Ms:        durationMs(percentile(latencies, 0.95)),
+		P99Ms:        durationMs(percentile(latencies, 0.99)),
+	}
+}
+
+// percentile 最近秩法计算分位数，sorted 需已升序排列
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
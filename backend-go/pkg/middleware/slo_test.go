@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSLOTracker_AttainmentAndPercentiles(t *testing.T) {
+	tracker := NewSLOTracker(SLOConfig{Target: 100 * time.Millisecond, Window: time.Minute})
+
+	// 90 个达标样本（1ms..90ms）与 10 个超标样本（110ms..200ms）
+	for i := 1; i <= 90; i++ {
+		tracker.Record(http.MethodGet, "/api/matches", time.Duration(i)*time.Millisecond)
+	}
+	for i := 1; i <= 10; i++ {
+		tracker.Record(http.MethodGet, "/api/matches", time.Duration(100+i*10)*time.Millisecond)
+	}
+	// 第二个路由全部达标
+	for i := 0; i < 20; i++ {
+		tracker.Record(http.MethodGet, "/api/leaderboard", 5*time.Millisecond)
+	}
+
+	report := tracker.Report()
+	if report.TargetMs != 100 {
+		t.Errorf("TargetMs = %v, want 100", report.TargetMs)
+	}
+	if len(report.Routes) != 2 {
+		t.Fatalf("路由数 = %d, want 2", len(report.Routes))
+	}
+
+	// 达成率低的路由排在前面
+	matches := report.Routes[0]
+	if matches.Route != "/api/matches" {
+		t.Fatalf("第一个路由 = %s, want /api/matches", matches.Route)
+	}
+	if matches.Requests != 100 || matches.WithinTarget != 90 {
+		t.Errorf("Requests/WithinTarget = %d/%d, want 100/90", matches.Requests, matches.WithinTarget)
+	}
+	if matches.Attainment != 90 {
+		t.Errorf("Attainment = %v, want 90", matches.Attainment)
+	}
+	// 升序第 95 个样本为 150ms，第 99 个为 190ms
+	if matches.P95Ms != 150 {
+		t.Errorf("P95Ms = %v, want 150", matches.P95Ms)
+	}
+	if matches.P99Ms != 190 {
+		t.Errorf("P99Ms = %v, want 190", matches.P99Ms)
+	}
+
+	if lb := report.Routes[1]; lb.Attainment != 100 || lb.P99Ms != 5 {
+		t.Errorf("leaderboard Attainment/P99 = %v/%v, want 100/5", lb.Attainment, lb.P99Ms)
+	}
+}
+
+func TestSLOTracker_RollingWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewSLOTracker(SLOConfig{Target: 100 * time.Millisecond, Window: time.Minute})
+	tracker.now = func() time.Time { return now }
+
+	// 窗口外的慢请求不应计入
+	for i := 0; i < 10; i++ {
+		tracker.Record(http.MethodGet, "/api/matches", time.Second)
+	}
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 4; i++ {
+		tracker.Record(http.MethodGet, "/api/matches", 10*time.Millisecond)
+	}
+
+	report := tracker.Report()
+	if len(report.Routes) != 1 {
+		t.Fatalf("路由数 = %d, want 1", len(report.Routes))
+	}
+	if got := report.Routes[0]; got.Requests != 4 || got.Attainment != 100 {
+		t.Errorf("Requests/Attainment = %d/%v, want 4/100", got.Requests, got.Attainment)
+	}
+
+	// 窗口内无样本的路由从报告中移除
+	now = now.Add(2 * time.Minute)
+	if report := tracker.Report(); len(report.Routes) != 0 {
+		t.Errorf("过期后路由数 = %d, want 0", len(report.Routes))
+	}
+}
+
+func TestSLOTracker_MaxSamples(t *testing.T) {
+	tracker := NewSLOTracker(SLOConfig{Target: 100 * time.Millisecond, Window: time.Minute, MaxSamples: 5})
+
+	for i := 0; i < 5; i++ {
+		tracker.Record(http.MethodGet, "/api/matches", time.Second)
+	}
+	// 新样本覆盖最旧的样本
+	for i := 0; i < 5; i++ {
+		tracker.Record(http.MethodGet, "/api/matches", time.Millisecond)
+	}
+
+	if got := tracker.Report().Routes[0]; got.Requests != 5 || got.Attainment != 100 {
+		t.Errorf("Requests/Attainment = %d/%v, want 5/100", got.Requests, got.Attainment)
+	}
+}
+
+func TestSLOTracker_MiddlewareUsesRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracker := NewSLOTracker(DefaultSLOConfig())
+
+	router := gin.New()
+	router.Use(tracker.Middleware())
+	router.GET("/api/matches/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/matches/1", "/api/matches/2", "/not-found"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	report := tracker.Report()
+	if len(report.Routes) != 1 {
+		t.Fatalf("路由数 = %d, want 1", len(report.Routes))
+	}
+	if got := report.Routes[0]; got.Route != "/api/matches/:id" || got.Requests != 2 {
+		t.Errorf("Route/Requests = %s/%d, want /api/matches/:id/2", got.Route, got.Requests)
+	}
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// jsonBatchStore 批量 JSON 操作依赖的缓存操作
+type jsonBatchStore interface {
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+	setMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error
+}
+
+// mgetJSON 批量读取并解码 JSON，返回值与 keys 一一对应，缺失位置为 nil
+//
+// 无法解码的值按缺失处理，调用方回源后会重新写入。
+func mgetJSON(ctx context.Context, store jsonBatchStore, keys []string, newElem func() interface{}) ([]interface{}, []string, error) {
+	if len(keys) == 0 {
+		return nil, nil, nil
+	}
+
+	raw, err := store.MGet(ctx, keys...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]interface{}, len(keys))
+	var missing []string
+	for i, key := range keys {
+		var data []byte
+		if i < len(raw) {
+			switch v := raw[i].(type) {
+			case string:
+				data = []byte(v)
+			case []byte:
+				data = v
+			}
+		}
+		if data == nil {
+			missing = append(missing, key)
+			continue
+		}
+
+		elem := newElem()
+		if err := json.Unmarshal(data, elem); err != nil {
+			missing = append(missing, key)
+			continue
+		}
+		values[i] = elem
+	}
+
+	return values, missing, nil
+}
+
+// msetJSON 编码所有值后批量写入，每个键都设置过期时间
+func msetJSON(ctx context.Context, store jsonBatchStore, values map[string]interface{}, expiration time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON for key %s: %w", key, err)
+		}
+		encoded[key] = data
+	}
+
+	return store.setMany(ctx, encoded, expiration)
+}
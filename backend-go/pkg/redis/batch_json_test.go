@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeBatchStore 在 fakeStore 基础上支持批量读写
+type fakeBatchStore struct {
+	*fakeStore
+	setManyCalls int
+}
+
+func (f *fakeBatchStore) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if value, err := f.Get(ctx, key); err == nil {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+func (f *fakeBatchStore) setMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error {
+	f.setManyCalls++
+	for key, value := range values {
+		f.Set(ctx, key, string(value), expiration)
+	}
+	return nil
+}
+
+type cachedTeam struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestMGetJSON_PresentAndMissing(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeBatchStore{fakeStore: newFakeStore()}
+	store.Set(ctx, "team:1", `{"id":1,"name":"T1"}`, time.Minute)
+	store.Set(ctx, "team:3", `{"id":3,"name":"GEN"}`, time.Minute)
+	store.Set(ctx, "team:4", `not json`, time.Minute)
+
+	keys := []string{"team:1", "team:2", "team:3", "team:4"}
+	values, missing, err := mgetJSON(ctx, store, keys, func() interface{} { return &cachedTeam{} })
+	if err != nil {
+		t.Fatalf("mgetJSON() error = %v", err)
+	}
+
+	if len(values) != len(keys) {
+		t.Fatalf("len(values) = %d, want %d", len(values), len(keys))
+	}
+	if team, ok := values[0].(*cachedTeam); !ok || team.Name != "T1" {
+		t.Errorf("values[0] = %#v, want T1", values[0])
+	}
+	if team, ok := values[2].(*cachedTeam); !ok || team.Name != "GEN" {
+		t.Errorf("values[2] = %#v, want GEN", values[2])
+	}
+	if values[1] != nil || values[3] != nil {
+		t.Errorf("缺失或无法解码的位置应为 nil: %#v, %#v", values[1], values[3])
+	}
+	if len(missing) != 2 || missing[0] != "team:2" || missing[1] != "team:4" {
+		t.Errorf("missing = %v, want [team:2 team:4]", missing)
+	}
+}
+
+func TestMSetJSON_AppliesTTLPerKey(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeBatchStore{fakeStore: newFakeStore()}
+
+	err := msetJSON(ctx, store, map[string]interface{}{
+		"team:1": cachedTeam{ID: 1, Name: "T1"},
+		"team:2": cachedTeam{ID: 2, Name: "BLG"},
+	}, 30*time.Second)
+	if err != nil {
+		t.Fatalf("msetJSON() error = %v", err)
+	}
+	if store.setManyCalls != 1 {
+		t.Errorf("setMany 调用次数 = %d, want 1", store.setManyCalls)
+	}
+
+	for _, key := range []string{"team:1", "team:2"} {
+		if got := store.expiry[key].Sub(store.now); got != 30*time.Second {
+			t.Errorf("%s TTL = %v, want 30s", key, got)
+		}
+	}
+
+	values, missing, _ := mgetJSON(ctx, store, []string{"team:1", "team:2"}, func() interface{} { return &cachedTeam{} })
+	if len(missing) != 0 || values[1].(*cachedTeam).Name != "BLG" {
+		t.Errorf("写入后读取: values = %#v, missing = %v", values, missing)
+	}
+
+	// 过期后全部缺失
+	store.now = store.now.Add(31 * time.Second)
+	if _, missing, _ := mgetJSON(ctx, store, []string{"team:1", "team:2"}, func() interface{} { return &cachedTeam{} }); len(missing) != 2 {
+		t.Errorf("过期后 missing = %v, want 2 个键", missing)
+	}
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"backend-go/pkg/deadline"
+)
+
+// budgetHook 按请求剩余时间预算限制命令耗时
+//
+// 每个命令（或流水线）的超时取 timeout 与上下文剩余时间中的较小值；
+// 剩余时间不足 minBudget 时命令不会发出，直接以 ErrOperationTimeout 失败。
+type budgetHook struct {
+	timeout   time.Duration
+	minBudget time.Duration
+}
+
+func newBudgetHook(timeout, minBudget time.Duration) *budgetHook {
+	return &budgetHook{timeout: timeout, minBudget: minBudget}
+}
+
+// DialHook 建连超时由 DialTimeout 与上下文控制，无需额外处理
+func (h *budgetHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 为单个命令应用剩余预算
+func (h *budgetHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, cancel, err := deadline.WithBudget(ctx, h.timeout, h.minBudget)
+		defer cancel()
+		if err != nil {
+			err = budgetError(err)
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook 为整条流水线应用剩余预算
+func (h *budgetHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, cancel, err := deadline.WithBudget(ctx, h.timeout, h.minBudget)
+		defer cancel()
+		if err != nil {
+			err = budgetError(err)
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// budgetError 包装为 ErrOperationTimeout，同时保留 context.DeadlineExceeded
+func budgetError(err error) error {
+	return fmt.Errorf("%w: %w", ErrOperationTimeout, err)
+}
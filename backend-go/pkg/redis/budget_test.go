@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newBlackholeClient 返回连接到只接受连接、从不响应的服务端的客户端
+func newBlackholeClient(t *testing.T, minBudget time.Duration) *redis.Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:                  listener.Addr().String(),
+		MaxRetries:            -1,
+		ReadTimeout:           5 * time.Second,
+		WriteTimeout:          5 * time.Second,
+		ContextTimeoutEnabled: true,
+		DisableIdentity:       true,
+		Protocol:              2,
+	})
+	rdb.AddHook(newBudgetHook(3*time.Second, minBudget))
+	t.Cleanup(func() { rdb.Close() })
+	return rdb
+}
+
+func TestBudgetHook_FailsFastWhenNearlyExpired(t *testing.T) {
+	rdb := newBlackholeClient(t, 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := rdb.Get(ctx, "key").Err()
+	if !errors.Is(err, ErrOperationTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get() error = %v, want ErrOperationTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Get() took %s, want immediate failure", elapsed)
+	}
+
+	pipe := rdb.Pipeline()
+	get := pipe.Get(ctx, "key")
+	if _, err := pipe.Exec(ctx); !errors.Is(err, ErrOperationTimeout) || !errors.Is(get.Err(), ErrOperationTimeout) {
+		t.Errorf("Pipeline Exec() error = %v, cmd error = %v, want ErrOperationTimeout", err, get.Err())
+	}
+}
+
+func TestBudgetHook_UsesRemainingBudget(t *testing.T) {
+	rdb := newBlackholeClient(t, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// 服务端不响应时按剩余预算超时，而不是等待 5 秒的读超时
+	start := time.Now()
+	err := rdb.Get(ctx, "key").Err()
+	if err == nil {
+		t.Fatal("Get() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get() took %s, want to stop at the 100ms budget", elapsed)
+	}
+}
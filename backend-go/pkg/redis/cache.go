@@ -7,12 +7,17 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"backend-go/internal/config"
 )
 
 // CacheService Redis 缓存服务接口
 type CacheService interface {
 	// 基础操作
 	Get(ctx context.Context, key string) (string, error)
+	// GetWithTTL 读取值及剩余过期时间，未设置过期时间时 TTL 为 -1
+	GetWithTTL(ctx context.Context, key string) (string, time.Duration, error)
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
@@ -25,6 +30,8 @@ type CacheService interface {
 	// JSON 操作
 	GetJSON(ctx context.Context, key string, dest interface{}) error
 	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	MGetJSON(ctx context.Context, keys []string, newElem func() interface{}) ([]interface{}, []string, error)
+	MSetJSON(ctx context.Context, values map[string]interface{}, expiration time.Duration) error
 
 	// 哈希操作
 	HGet(ctx context.Context, key, field string) (string, error)
@@ -57,6 +64,9 @@ type CacheService interface {
 	Expire(ctx context.Context, key string, expiration time.Duration) error
 	TTL(ctx context.Context, key string) (time.Duration, error)
 
+	// 键扫描（SCAN，不阻塞 Redis）
+	ScanKeys(ctx context.Context, pattern string) ([]string, error)
+
 	// 高级操作
 	Increment(ctx context.Context, key string) (int64, error)
 	IncrementBy(ctx context.Context, key string, value int64) (int64, error)
@@ -70,10 +80,14 @@ type CacheService interface {
 	// 缓存模式
 	GetOrSet(ctx context.Context, key string, expiration time.Duration, fn func() (interface{}, error)) (interface{}, error)
 	Remember(ctx context.Context, key string, expiration time.Duration, fn func() (interface{}, error)) (interface{}, error)
+	GetOrSetWithOptions(ctx context.Context, key string, expiration time.Duration, fn func() (interface{}, error), opts ...GetOrSetOption) (interface{}, error)
 
 	// 缓存失效
 	InvalidatePattern(ctx context.Context, pattern string) error
-	FlushDB(ctx context.Context) error
+	TagKey(ctx context.Context, key string, expiration time.Duration, tags ...string) error
+	InvalidateTag(ctx context.Context, tag string) error
+	// FlushDB 清空整个 Redis 数据库，生产环境下 force 为 false 时返回 ErrFlushRefused
+	FlushDB(ctx context.Context, force bool) error
 }
 
 // cacheService 缓存服务实现
@@ -115,6 +129,29 @@ func (s *cacheService) Get(ctx context.Context, key string) (string, error) {
 	return result.Val(), nil
 }
 
+func (s *cacheService) GetWithTTL(ctx context.Context, key string) (string, time.Duration, error) {
+	start := time.Now()
+	defer func() {
+		s.client.metrics.RecordOperation("get_with_ttl", time.Since(start), nil)
+	}()
+
+	// MULTI/EXEC 事务内读取，保证值与剩余时间对应同一版本
+	pipe := s.client.rdb.TxPipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		s.client.metrics.RecordOperation("get_with_ttl", time.Since(start), err)
+		return "", 0, fmt.Errorf("failed to get key %s with ttl: %w", key, err)
+	}
+	if get.Err() == redis.Nil {
+		s.client.metrics.RecordCacheMiss(key)
+		return "", 0, ErrKeyNotFound
+	}
+
+	s.client.metrics.RecordCacheHit(key)
+	return get.Val(), ttl.Val(), nil
+}
+
 func (s *cacheService) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	start := time.Now()
 	defer func() {
@@ -231,6 +268,32 @@ func (s *cacheService) SetJSON(ctx context.Context, key string, value interface{
 	return s.Set(ctx, key, data, expiration)
 }
 
+// MGetJSON 批量读取 JSON，newElem 创建解码目标；返回值与 keys 顺序一致，缺失的键为 nil 并列入 missing
+func (s *cacheService) MGetJSON(ctx context.Context, keys []string, newElem func() interface{}) ([]interface{}, []string, error) {
+	return mgetJSON(ctx, s, keys, newElem)
+}
+
+// MSetJSON 批量写入 JSON，通过 pipeline 为每个键设置过期时间
+func (s *cacheService) MSetJSON(ctx context.Context, values map[string]interface{}, expiration time.Duration) error {
+	return msetJSON(ctx, s, values, expiration)
+}
+
+// setMany 通过 pipeline 批量写入带过期时间的值（MSET 不支持过期时间）
+func (s *cacheService) setMany(ctx context.Context, values map[string][]byte, expiration time.Duration) error {
+	start := time.Now()
+	_, err := s.client.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, key, value, expiration)
+		}
+		return nil
+	})
+	s.client.metrics.RecordOperation("mset_json", time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("failed to mset JSON: %w", err)
+	}
+	return nil
+}
+
 // 哈希操作实现
 
 func (s *cacheService) HGet(ctx context.Context, key, field string) (string, error) {
@@ -568,6 +631,34 @@ func (s *cacheService) TTL(ctx context.Context, key string) (time.Duration, erro
 	return result.Val(), nil
 }
 
+func (s *cacheService) ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	start := time.Now()
+	defer func() {
+		s.client.metrics.RecordOperation("scan", time.Since(start), nil)
+	}()
+
+	var cursor uint64
+	var keys []string
+
+	for {
+		result := s.client.rdb.Scan(ctx, cursor, pattern, 100)
+		if err := result.Err(); err != nil {
+			s.client.metrics.RecordOperation("scan", time.Since(start), err)
+			return nil, fmt.Errorf("failed to scan keys with pattern %s: %w", pattern, err)
+		}
+
+		scanKeys, newCursor := result.Val()
+		keys = append(keys, scanKeys...)
+		cursor = newCursor
+
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
 // 高级操作实现
 
 func (s *cacheService) Increment(ctx context.Context, key string) (int64, error) {
@@ -634,28 +725,11 @@ func (s *cacheService) Unlock(ctx context.Context, key string) error {
 // 缓存模式实现
 
 func (s *cacheService) GetOrSet(ctx context.Context, key string, expiration time.Duration, fn func() (interface{}, error)) (interface{}, error) {
-	// 尝试从缓存获取
-	value, err := s.Get(ctx, key)
-	if err == nil {
-		return value, nil
-	}
-
-	if err != ErrKeyNotFound {
-		return nil, err
-	}
-
-	// 缓存未命中，执行函数获取值
-	result, err := fn()
-	if err != nil {
-		return nil, err
-	}
-
-	// 设置缓存
-	if err := s.Set(ctx, key, result, expiration); err != nil {
-		s.client.logger.WithError(err).Warnf("Failed to set cache for key: %s", key)
-	}
+	return s.GetOrSetWithOptions(ctx, key, expiration, fn)
+}
 
-	return result, nil
+func (s *cacheService) GetOrSetWithOptions(ctx context.Context, key string, expiration time.Duration, fn func() (interface{}, error), opts ...GetOrSetOption) (interface{}, error) {
+	return getOrSet(ctx, s, s.client.logger, key, expiration, fn, opts...)
 }
 
 func (s *cacheService) Remember(ctx context.Context, key string, expiration time.Duration, fn func() (interface{}, error)) (interface{}, error) {
@@ -664,41 +738,51 @@ func (s *cacheService) Remember(ctx context.Context, key string, expiration time
 
 // 缓存失效实现
 
-func (s *cacheService) InvalidatePattern(ctx context.Context, pattern string) error {
-	start := time.Now()
-	defer func() {
-		s.client.metrics.RecordOperation("invalidate_pattern", time.Since(start), nil)
-	}()
-
-	// 使用 SCAN 命令查找匹配的键
-	var cursor uint64
-	var keys []string
-
-	for {
-		result := s.client.rdb.Scan(ctx, cursor, pattern, 100)
-		if err := result.Err(); err != nil {
-			s.client.metrics.RecordOperation("invalidate_pattern", time.Since(start), err)
-			return fmt.Errorf("failed to scan keys with pattern %s: %w", pattern, err)
-		}
+// TagKey 将缓存键登记到标签下，expiration 应与缓存键的过期时间一致
+func (s *cacheService) TagKey(ctx context.Context, key string, expiration time.Duration, tags ...string) error {
+	return tagKey(ctx, s, key, expiration, tags...)
+}
 
-		scanKeys, newCursor := result.Val()
-		keys = append(keys, scanKeys...)
-		cursor = newCursor
+// InvalidateTag 删除标签下登记的所有缓存键
+func (s *cacheService) InvalidateTag(ctx context.Context, tag string) error {
+	start := time.Now()
+	err := invalidateTag(ctx, s, tag)
+	s.client.metrics.RecordOperation("invalidate_tag", time.Since(start), err)
+	return err
+}
 
-		if cursor == 0 {
-			break
-		}
-	}
+// InvalidatePattern 分批删除匹配 pattern 的所有键
+func (s *cacheService) InvalidatePattern(ctx context.Context, pattern string) error {
+	start := time.Now()
+	deleted, err := invalidatePattern(ctx, s, pattern)
+	s.client.metrics.RecordOperation("invalidate_pattern", time.Since(start), err)
+	s.client.logger.WithFields(logrus.Fields{
+		"pattern": pattern,
+		"deleted": deleted,
+	}).Debug("Invalidated cache keys by pattern")
+	return err
+}
 
-	// 删除找到的键
-	if len(keys) > 0 {
-		return s.MDelete(ctx, keys...)
+// checkFlushAllowed 生产环境必须显式 force 才允许清空数据库
+func checkFlushAllowed(env config.Environment, force bool) error {
+	if env.IsProduction() && !force {
+		return ErrFlushRefused
 	}
-
 	return nil
 }
 
-func (s *cacheService) FlushDB(ctx context.Context) error {
+func (s *cacheService) FlushDB(ctx context.Context, force bool) error {
+	env := config.GetEnvironment()
+	fields := logrus.Fields{"environment": env, "force": force}
+	if s.client.config != nil {
+		fields["database"] = s.client.config.Database
+	}
+	if err := checkFlushAllowed(env, force); err != nil {
+		s.client.logger.WithFields(fields).Error("Refused to flush Redis database")
+		return err
+	}
+	s.client.logger.WithFields(fields).Warn("Flushing entire Redis database")
+
 	start := time.Now()
 	defer func() {
 		s.client.metrics.RecordOperation("flushdb", time.Since(start), nil)
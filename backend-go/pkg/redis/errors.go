@@ -25,6 +25,7 @@ var (
 	ErrOperationTimeout  = errors.New("redis operation timeout")
 	ErrTransactionFailed = errors.New("redis transaction failed")
 	ErrScriptError       = errors.New("redis script execution error")
+	ErrFlushRefused      = errors.New("refusing to flush redis database in production without force")
 
 	// 锁错误
 	ErrLockFailed  = errors.New("failed to acquire lock")
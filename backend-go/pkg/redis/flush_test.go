@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// newUnreachableCacheService 连接到不可用地址的缓存服务，通过守卫的 FlushDB 会返回连接错误
+func newUnreachableCacheService(t *testing.T) (CacheService, *test.Hook) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	return NewCacheService(&Client{rdb: rdb, logger: logger, metrics: NewMetrics()}), hook
+}
+
+func TestFlushDB_RefusedInProductionWithoutForce(t *testing.T) {
+	t.Setenv("GO_ENV", "production")
+	cache, hook := newUnreachableCacheService(t)
+
+	if err := cache.FlushDB(context.Background(), false); !errors.Is(err, ErrFlushRefused) {
+		t.Fatalf("FlushDB(force=false) error = %v, want ErrFlushRefused", err)
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Level != logrus.ErrorLevel {
+		t.Errorf("拒绝清空时应记录错误日志, got %v", entry)
+	}
+
+	// 显式 force 时通过守卫，实际执行因连接不可用而失败
+	err := cache.FlushDB(context.Background(), true)
+	if err == nil || errors.Is(err, ErrFlushRefused) {
+		t.Errorf("FlushDB(force=true) error = %v, want connection error", err)
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Level != logrus.WarnLevel {
+		t.Errorf("清空前应记录警告日志, got %v", entry)
+	}
+}
+
+func TestFlushDB_AllowedOutsideProduction(t *testing.T) {
+	for _, env := range []string{"development", "testing", "staging"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv("GO_ENV", env)
+			cache, _ := newUnreachableCacheService(t)
+
+			err := cache.FlushDB(context.Background(), false)
+			if errors.Is(err, ErrFlushRefused) {
+				t.Errorf("FlushDB() in %s error = %v, want guard to pass", env, err)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package redis
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -157,16 +156,20 @@ func (hc *HealthChecker) checkMemory(ctx context.Context, result *HealthResult)
 		return
 	}
 
-	memoryInfo := parseRedisInfo(info)
+	memoryInfo, err := ParseRedisInfo(info)
+	if err != nil {
+		result.Checks["memory"] = CheckResult{
+			Healthy:  false,
+			Message:  fmt.Sprintf("Failed to parse memory info: %v", err),
+			Duration: time.Since(start),
+		}
+		return
+	}
 
 	// 解析内存使用情况
-	usedMemory, _ := strconv.ParseInt(memoryInfo["used_memory"], 10, 64)
-	maxMemory, _ := strconv.ParseInt(memoryInfo["maxmemory"], 10, 64)
-
-	var memoryUsagePercent float64
-	if maxMemory > 0 {
-		memoryUsagePercent = float64(usedMemory) / float64(maxMemory) * 100
-	}
+	usedMemory := memoryInfo.UsedMemory
+	maxMemory := memoryInfo.MaxMemory
+	memoryUsagePercent := memoryInfo.MemoryUsagePercent()
 
 	// 检查内存使用率
 	healthy := true
@@ -189,7 +192,7 @@ func (hc *HealthChecker) checkMemory(ctx context.Context, result *HealthResult)
 		"used_memory_mb":             float64(usedMemory) / 1024 / 1024,
 		"max_memory_mb":              float64(maxMemory) / 1024 / 1024,
 		"memory_usage_percent":       memoryUsagePercent,
-		"memory_fragmentation_ratio": memoryInfo["mem_fragmentation_ratio"],
+		"memory_fragmentation_ratio": memoryInfo.MemFragmentationRatio,
 	}
 }
 
@@ -313,17 +316,24 @@ func (hc *HealthChecker) checkReplication(ctx context.Context, result *HealthRes
 		return
 	}
 
-	replicationInfo := parseRedisInfo(info)
-	role := replicationInfo["role"]
+	replicationInfo, err := ParseRedisInfo(info)
+	if err != nil {
+		result.Checks["replication"] = CheckResult{
+			Healthy:  false,
+			Message:  fmt.Sprintf("Failed to parse replication info: %v", err),
+			Duration: time.Since(start),
+		}
+		return
+	}
+	role := replicationInfo.Role
 
 	healthy := true
 	message := fmt.Sprintf("Role: %s", role)
 
 	if role == "master" {
-		connectedSlaves, _ := strconv.Atoi(replicationInfo["connected_slaves"])
-		message = fmt.Sprintf("Master with %d slaves", connectedSlaves)
+		message = fmt.Sprintf("Master with %d slaves", replicationInfo.ConnectedSlaves)
 	} else if role == "slave" {
-		masterLinkStatus := replicationInfo["master_link_status"]
+		masterLinkStatus := replicationInfo.MasterLinkStatus
 		if masterLinkStatus != "up" {
 			healthy = false
 			message = fmt.Sprintf("Slave disconnected from master: %s", masterLinkStatus)
@@ -338,7 +348,7 @@ func (hc *HealthChecker) checkReplication(ctx context.Context, result *HealthRes
 		Duration: time.Since(start),
 	}
 
-	result.Details["replication"] = replicationInfo
+	result.Details["replication"] = replicationInfo.Fields
 }
 
 // checkPersistence 检查持久化状态
@@ -355,23 +365,28 @@ func (hc *HealthChecker) checkPersistence(ctx context.Context, result *HealthRes
 		return
 	}
 
-	persistenceInfo := parseRedisInfo(info)
+	persistenceInfo, err := ParseRedisInfo(info)
+	if err != nil {
+		result.Checks["persistence"] = CheckResult{
+			Healthy:  false,
+			Message:  fmt.Sprintf("Failed to parse persistence info: %v", err),
+			Duration: time.Since(start),
+		}
+		return
+	}
 
 	healthy := true
 	message := "Persistence normal"
 
 	// 检查 RDB 状态
-	rdbLastBgsaveStatus := persistenceInfo["rdb_last_bgsave_status"]
-	if rdbLastBgsaveStatus == "err" {
+	if persistenceInfo.RDBLastBgsaveStatus == "err" {
 		healthy = false
 		message = "RDB background save failed"
 	}
 
 	// 检查 AOF 状态
-	aofEnabled := persistenceInfo["aof_enabled"] == "1"
-	if aofEnabled {
-		aofLastBgrewriteStatus := persistenceInfo["aof_last_bgrewrite_status"]
-		if aofLastBgrewriteStatus == "err" {
+	if persistenceInfo.AOFEnabled {
+		if persistenceInfo.AOFLastBgrewriteStatus == "err" {
 			healthy = false
 			message = "AOF background rewrite failed"
 		}
@@ -383,7 +398,7 @@ func (hc *HealthChecker) checkPersistence(ctx context.Context, result *HealthRes
 		Duration: time.Since(start),
 	}
 
-	result.Details["persistence"] = persistenceInfo
+	result.Details["persistence"] = persistenceInfo.Fields
 }
 
 // calculateOverallHealth 计算总体健康状态
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RedisInfo Redis INFO 命令输出中健康检查关心的字段
+//
+// 缺失或为空的字段保持零值，INFO 只返回部分分区时可以直接使用；
+// 全部原始字段保存在 Fields 中。
+type RedisInfo struct {
+	// server
+	RedisVersion    string
+	UptimeInSeconds int64
+
+	// memory
+	UsedMemory            int64
+	MaxMemory             int64
+	MemFragmentationRatio float64
+
+	// replication
+	Role             string
+	ConnectedSlaves  int
+	MasterLinkStatus string
+
+	// persistence
+	RDBLastBgsaveStatus    string
+	AOFEnabled             bool
+	AOFLastBgrewriteStatus string
+
+	Fields map[string]string
+}
+
+// ParseRedisInfo 解析 Redis INFO 命令输出
+//
+// 跳过分区标题与空行，字段缺失或为空时使用零值，数值字段格式错误时返回错误。
+func ParseRedisInfo(raw string) (*RedisInfo, error) {
+	info := &RedisInfo{Fields: make(map[string]string)}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			info.Fields[key] = value
+		}
+	}
+
+	p := infoParser{fields: info.Fields}
+	info.RedisVersion = info.Fields["redis_version"]
+	info.UptimeInSeconds = p.int64("uptime_in_seconds")
+	info.UsedMemory = p.int64("used_memory")
+	info.MaxMemory = p.int64("maxmemory")
+	info.MemFragmentationRatio = p.float64("mem_fragmentation_ratio")
+	info.Role = info.Fields["role"]
+	info.ConnectedSlaves = int(p.int64("connected_slaves"))
+	info.MasterLinkStatus = info.Fields["master_link_status"]
+	info.RDBLastBgsaveStatus = info.Fields["rdb_last_bgsave_status"]
+	info.AOFEnabled = p.int64("aof_enabled") == 1
+	info.AOFLastBgrewriteStatus = info.Fields["aof_last_bgrewrite_status"]
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	return info, nil
+}
+
+// MemoryUsagePercent 已用内存占 maxmemory 的百分比，未设置 maxmemory 时返回 0
+func (i *RedisInfo) MemoryUsagePercent() float64 {
+	if i.MaxMemory <= 0 {
+		return 0
+	}
+	return float64(i.UsedMemory) / float64(i.MaxMemory) * 100
+}
+
+// infoParser 解析数值字段，只保留第一个错误
+type infoParser struct {
+	fields map[string]string
+	err    error
+}
+
+func (p *infoParser) int64(key string) int64 {
+	value := p.fields[key]
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid redis info field %s=%q: %w", key, value, err)
+	}
+	return n
+}
+
+func (p *infoParser) float64(key string) float64 {
+	value := p.fields[key]
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid redis info field %s=%q: %w", key, value, err)
+	}
+	return f
+}
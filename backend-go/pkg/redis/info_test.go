@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"strings"
+	"testing"
+)
+
+// 节选自 Redis 7 的 INFO 输出，replication 分区缺少 connected_slaves，persistence 分区的 aof_enabled 为空
+const sampleRedisInfo = "# Server\r\n" +
+	"redis_version:7.2.4\r\n" +
+	"redis_mode:standalone\r\n" +
+	"uptime_in_seconds:86400\r\n" +
+	"\r\n" +
+	"# Memory\r\n" +
+	"used_memory:943718400\r\n" +
+	"used_memory_human:900.00M\r\n" +
+	"maxmemory:1073741824\r\n" +
+	"mem_fragmentation_ratio:1.25\r\n" +
+	"\r\n" +
+	"# Replication\r\n" +
+	"role:slave\r\n" +
+	"master_host:10.0.0.5\r\n" +
+	"master_link_status:down\r\n" +
+	"\r\n" +
+	"# Persistence\r\n" +
+	"rdb_last_bgsave_status:err\r\n" +
+	"aof_enabled:\r\n"
+
+func TestParseRedisInfo_RepresentativeDump(t *testing.T) {
+	info, err := ParseRedisInfo(sampleRedisInfo)
+	if err != nil {
+		t.Fatalf("ParseRedisInfo() error = %v", err)
+	}
+
+	if info.RedisVersion != "7.2.4" || info.UptimeInSeconds != 86400 {
+		t.Errorf("server = %s/%d, want 7.2.4/86400", info.RedisVersion, info.UptimeInSeconds)
+	}
+	if info.UsedMemory != 943718400 || info.MaxMemory != 1073741824 || info.MemFragmentationRatio != 1.25 {
+		t.Errorf("memory = %d/%d/%v", info.UsedMemory, info.MaxMemory, info.MemFragmentationRatio)
+	}
+	if got := info.MemoryUsagePercent(); got < 87.8 || got > 87.9 {
+		t.Errorf("MemoryUsagePercent() = %.2f, want 87.89", got)
+	}
+	if info.Role != "slave" || info.MasterLinkStatus != "down" || info.ConnectedSlaves != 0 {
+		t.Errorf("replication = %s/%s/%d, want slave/down/0", info.Role, info.MasterLinkStatus, info.ConnectedSlaves)
+	}
+	if info.RDBLastBgsaveStatus != "err" || info.AOFEnabled || info.AOFLastBgrewriteStatus != "" {
+		t.Errorf("persistence = %s/%v/%q, want err/false/空", info.RDBLastBgsaveStatus, info.AOFEnabled, info.AOFLastBgrewriteStatus)
+	}
+	// 未建模的字段保留在 Fields 中
+	if info.Fields["master_host"] != "10.0.0.5" || info.Fields["used_memory_human"] != "900.00M" {
+		t.Errorf("Fields = %v, want 保留原始字段", info.Fields)
+	}
+}
+
+func TestParseRedisInfo_MissingSectionsAndMalformedValues(t *testing.T) {
+	// 只请求 persistence 分区时其他字段保持零值，兼容 \n 换行
+	info, err := ParseRedisInfo("# Persistence\naof_enabled:1\naof_last_bgrewrite_status:ok\n")
+	if err != nil {
+		t.Fatalf("ParseRedisInfo() error = %v", err)
+	}
+	if !info.AOFEnabled || info.AOFLastBgrewriteStatus != "ok" || info.Role != "" || info.MaxMemory != 0 {
+		t.Errorf("info = %+v", info)
+	}
+	if info.MemoryUsagePercent() != 0 {
+		t.Errorf("未设置 maxmemory 时 MemoryUsagePercent() = %v, want 0", info.MemoryUsagePercent())
+	}
+
+	if info, err := ParseRedisInfo(""); err != nil || len(info.Fields) != 0 {
+		t.Errorf("空输出 info = %+v, err = %v", info, err)
+	}
+
+	_, err = ParseRedisInfo("# Memory\r\nused_memory:12M\r\n")
+	if err == nil || !strings.Contains(err.Error(), "used_memory") {
+		t.Errorf("ParseRedisInfo() error = %v, want used_memory 格式错误", err)
+	}
+}
@@ -37,6 +37,8 @@ const (
 	KeyPrefixPrediction     = "prediction"
 	KeyPrefixPredictionList = "prediction:list"
 	KeyPrefixPredictionVote = "prediction:vote"
+	KeyPrefixConsensus      = "prediction:consensus"
+	KeyPrefixDailyLimit     = "prediction:daily_limit"
 
 	// 排行榜相关
 	KeyPrefixLeaderboard    = "leaderboard"
@@ -159,6 +161,16 @@ func (km *CacheKeyManager) UserPredictionKey(userID uint, matchID uint) string {
 	return km.buildKey(KeyPrefixPrediction, "user", fmt.Sprintf("%d", userID), fmt.Sprintf("%d", matchID))
 }
 
+// ConsensusKey 生成比赛预测共识键
+func (km *CacheKeyManager) ConsensusKey(matchID uint) string {
+	return km.buildKey(KeyPrefixConsensus, fmt.Sprintf("%d", matchID))
+}
+
+// DailyPredictionCountKey 生成用户每日预测计数键，date 格式为 2006-01-02
+func (km *CacheKeyManager) DailyPredictionCountKey(userID uint, date string) string {
+	return km.buildKey(KeyPrefixDailyLimit, fmt.Sprintf("%d", userID), date)
+}
+
 // 排行榜相关键生成
 
 // LeaderboardKey 生成排行榜键
@@ -347,6 +359,16 @@ func UserPredictionKey(userID uint, matchID uint) string {
 	return defaultKeyManager.UserPredictionKey(userID, matchID)
 }
 
+// ConsensusKey 生成比赛预测共识键
+func ConsensusKey(matchID uint) string {
+	return defaultKeyManager.ConsensusKey(matchID)
+}
+
+// DailyPredictionCountKey 生成用户每日预测计数键
+func DailyPredictionCountKey(userID uint, date string) string {
+	return defaultKeyManager.DailyPredictionCountKey(userID, date)
+}
+
 // LeaderboardKey 生成排行榜键
 func LeaderboardKey(tournament string) string {
 	return defaultKeyManager.LeaderboardKey(tournament)
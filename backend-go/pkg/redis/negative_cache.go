@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// negativeCacheSentinel 负缓存标记值，表示数据源中不存在该数据
+const negativeCacheSentinel = "__not_found__"
+
+// ExpirationNegative 默认负缓存时间 (30秒)
+const ExpirationNegative = 30 * time.Second
+
+// GetOrSetOption GetOrSet 调用选项
+type GetOrSetOption func(*getOrSetOptions)
+
+type getOrSetOptions struct {
+	negativeTTL time.Duration
+	notFoundErr error
+}
+
+// WithNegativeCache 开启负缓存：当 fn 返回的错误匹配 notFoundErr 时缓存“不存在”标记，
+// 之后 ttl 内的查询直接返回 notFoundErr 而不访问数据源。ttl 不宜过长，以免遮蔽新创建的数据
+func WithNegativeCache(ttl time.Duration, notFoundErr error) GetOrSetOption {
+	return func(o *getOrSetOptions) {
+		if ttl <= 0 {
+			ttl = ExpirationNegative
+		}
+		o.negativeTTL = ttl
+		o.notFoundErr = notFoundErr
+	}
+}
+
+// keyValueStore GetOrSet 依赖的读写操作
+type keyValueStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// getOrSet 缓存读取，未命中时回源并写入缓存
+func getOrSet(ctx context.Context, store keyValueStore, logger *logrus.Logger, key string, expiration time.Duration, fn func() (interface{}, error), opts ...GetOrSetOption) (interface{}, error) {
+	var options getOrSetOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	negative := options.notFoundErr != nil
+
+	// 尝试从缓存获取
+	value, err := store.Get(ctx, key)
+	if err == nil {
+		if value != negativeCacheSentinel {
+			return value, nil
+		}
+		// 未开启负缓存的调用忽略标记，重新回源
+		if negative {
+			return nil, options.notFoundErr
+		}
+	} else if err != ErrKeyNotFound {
+		return nil, err
+	}
+
+	// 缓存未命中，执行函数获取值
+	result, err := fn()
+	if err != nil {
+		if negative && errors.Is(err, options.notFoundErr) {
+			if setErr := store.Set(ctx, key, negativeCacheSentinel, options.negativeTTL); setErr != nil && logger != nil {
+				logger.WithError(setErr).Warnf("Failed to set negative cache for key: %s", key)
+			}
+		}
+		return nil, err
+	}
+
+	// 设置缓存
+	if err := store.Set(ctx, key, result, expiration); err != nil && logger != nil {
+		logger.WithError(err).Warnf("Failed to set cache for key: %s", key)
+	}
+
+	return result, nil
+}
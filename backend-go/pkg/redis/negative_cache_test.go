@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errEntityNotFound = errors.New("entity not found")
+
+// fakeStore 带过期时间的内存键值存储
+type fakeStore struct {
+	now    time.Time
+	values map[string]string
+	expiry map[string]time.Time
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		now:    time.Now(),
+		values: make(map[string]string),
+		expiry: make(map[string]time.Time),
+	}
+}
+
+func (f *fakeStore) Get(ctx context.Context, key string) (string, error) {
+	value, ok := f.values[key]
+	if !ok || (!f.expiry[key].IsZero() && f.now.After(f.expiry[key])) {
+		return "", ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (f *fakeStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	f.values[key] = value.(string)
+	if expiration > 0 {
+		f.expiry[key] = f.now.Add(expiration)
+	}
+	return nil
+}
+
+func TestGetOrSet_NegativeCache(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	calls := 0
+	source := func() (interface{}, error) {
+		calls++
+		return nil, errEntityNotFound
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := getOrSet(ctx, store, nil, "match:404", time.Minute, source, WithNegativeCache(10*time.Second, errEntityNotFound))
+		if !errors.Is(err, errEntityNotFound) {
+			t.Fatalf("第 %d 次查询错误 = %v, want %v", i+1, err, errEntityNotFound)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("数据源调用次数 = %d, want 1", calls)
+	}
+
+	// 负缓存过期后重新回源
+	store.now = store.now.Add(11 * time.Second)
+	getOrSet(ctx, store, nil, "match:404", time.Minute, source, WithNegativeCache(10*time.Second, errEntityNotFound))
+	if calls != 2 {
+		t.Errorf("负缓存过期后数据源调用次数 = %d, want 2", calls)
+	}
+}
+
+func TestGetOrSet_NegativeCacheIsOptIn(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	calls := 0
+	source := func() (interface{}, error) {
+		calls++
+		return nil, errEntityNotFound
+	}
+
+	for i := 0; i < 2; i++ {
+		getOrSet(ctx, store, nil, "match:404", time.Minute, source)
+	}
+	if calls != 2 {
+		t.Errorf("未开启负缓存时数据源调用次数 = %d, want 2", calls)
+	}
+
+	// 其他调用写入的标记不影响未开启负缓存的调用
+	store.Set(ctx, "match:1", negativeCacheSentinel, time.Minute)
+	value, err := getOrSet(ctx, store, nil, "match:1", time.Minute, func() (interface{}, error) {
+		return "created", nil
+	})
+	if err != nil || value != "created" {
+		t.Errorf("getOrSet() = %v, %v, want created", value, err)
+	}
+}
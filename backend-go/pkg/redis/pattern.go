@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// patternScanCount 每次 SCAN 建议返回的键数
+	patternScanCount = 100
+	// patternDeleteBatch 按模式删除时每批删除的键数
+	patternDeleteBatch = 500
+)
+
+// patternStore 按模式删除依赖的缓存操作，cacheService 满足该接口
+type patternStore interface {
+	scanPage(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error)
+	MDelete(ctx context.Context, keys ...string) error
+}
+
+// scanPage 执行一次 SCAN，返回本页的键和下一个游标
+func (s *cacheService) scanPage(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	return s.client.rdb.Scan(ctx, cursor, pattern, count).Result()
+}
+
+// invalidatePattern 边扫描边删除匹配 pattern 的键，返回删除的键数
+//
+// 每累计 patternDeleteBatch 个键删除一次，内存占用不随键空间增长，删除压力也分散到
+// 整个扫描过程。SCAN 期间删除已返回的键不影响后续游标。
+func invalidatePattern(ctx context.Context, store patternStore, pattern string) (int, error) {
+	var cursor uint64
+	batch := make([]string, 0, patternDeleteBatch)
+	deleted := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := store.MDelete(ctx, batch...); err != nil {
+			return fmt.Errorf("failed to delete keys with pattern %s: %w", pattern, err)
+		}
+		deleted += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		keys, next, err := store.scanPage(ctx, cursor, pattern, patternScanCount)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan keys with pattern %s: %w", pattern, err)
+		}
+
+		for _, key := range keys {
+			batch = append(batch, key)
+			if len(batch) == patternDeleteBatch {
+				if err := flush(); err != nil {
+					return deleted, err
+				}
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	if err := flush(); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
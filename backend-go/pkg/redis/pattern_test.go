@@ -0,0 +1,129 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"testing"
+)
+
+// fakePatternStore 按游标分页扫描的内存键空间，记录每次 MDelete 的键数
+type fakePatternStore struct {
+	keys       map[string]bool
+	order      []string // 固定的扫描顺序
+	scans      int
+	scanErrAt  int   // 第几次扫描返回错误，0 表示不出错
+	batches    []int // 每次 MDelete 的键数
+	scansAtDel []int // 每次 MDelete 时已完成的扫描次数
+}
+
+func newFakePatternStore(keys ...string) *fakePatternStore {
+	f := &fakePatternStore{keys: make(map[string]bool)}
+	for _, key := range keys {
+		f.keys[key] = true
+		f.order = append(f.order, key)
+	}
+	sort.Strings(f.order)
+	return f
+}
+
+func (f *fakePatternStore) scanPage(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	f.scans++
+	if f.scans == f.scanErrAt {
+		return nil, 0, errors.New("connection reset")
+	}
+
+	end := cursor + uint64(count)
+	if end >= uint64(len(f.order)) {
+		end = uint64(len(f.order))
+	}
+	var page []string
+	for _, key := range f.order[cursor:end] {
+		if matched, _ := path.Match(pattern, key); matched && f.keys[key] {
+			page = append(page, key)
+		}
+	}
+	if end == uint64(len(f.order)) {
+		end = 0
+	}
+	return page, end, nil
+}
+
+func (f *fakePatternStore) MDelete(ctx context.Context, keys ...string) error {
+	f.batches = append(f.batches, len(keys))
+	f.scansAtDel = append(f.scansAtDel, f.scans)
+	for _, key := range keys {
+		delete(f.keys, key)
+	}
+	return nil
+}
+
+func seedPatternKeys(prefix string, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s:%05d", prefix, i)
+	}
+	return keys
+}
+
+func TestInvalidatePattern_DeletesInBatchesWhileScanning(t *testing.T) {
+	ctx := context.Background()
+	matching := seedPatternKeys("match:list", 3210)
+	others := seedPatternKeys("user:profile", 150)
+	store := newFakePatternStore(append(matching, others...)...)
+
+	deleted, err := invalidatePattern(ctx, store, "match:list:*")
+	if err != nil {
+		t.Fatalf("invalidatePattern() error = %v", err)
+	}
+	if deleted != len(matching) {
+		t.Errorf("deleted = %d, want %d", deleted, len(matching))
+	}
+	for _, key := range matching {
+		if store.keys[key] {
+			t.Fatalf("key %s was not deleted", key)
+		}
+	}
+	for _, key := range others {
+		if !store.keys[key] {
+			t.Fatalf("unmatched key %s was deleted", key)
+		}
+	}
+
+	// 3210 个键分 7 批删除，前 6 批各 500 个
+	want := []int{500, 500, 500, 500, 500, 500, 210}
+	if fmt.Sprint(store.batches) != fmt.Sprint(want) {
+		t.Errorf("batches = %v, want %v", store.batches, want)
+	}
+	// 第一批在扫描结束前就已删除，说明没有先收集全部键
+	if store.scansAtDel[0] >= store.scans {
+		t.Errorf("first delete after %d of %d scans, want before scanning finished", store.scansAtDel[0], store.scans)
+	}
+}
+
+func TestInvalidatePattern_NoMatchesSkipsDelete(t *testing.T) {
+	store := newFakePatternStore(seedPatternKeys("user:profile", 10)...)
+
+	deleted, err := invalidatePattern(context.Background(), store, "match:*")
+	if err != nil || deleted != 0 {
+		t.Fatalf("invalidatePattern() = %d, %v, want 0, nil", deleted, err)
+	}
+	if len(store.batches) != 0 {
+		t.Errorf("batches = %v, want no MDelete calls", store.batches)
+	}
+}
+
+func TestInvalidatePattern_ScanErrorReportsDeletedSoFar(t *testing.T) {
+	store := newFakePatternStore(seedPatternKeys("match:list", 1200)...)
+	store.scanErrAt = 8
+
+	deleted, err := invalidatePattern(context.Background(), store, "match:list:*")
+	if err == nil {
+		t.Fatal("invalidatePattern() error = nil, want scan error")
+	}
+	if deleted != 500 {
+		t.Errorf("deleted = %d, want 500 from the batch flushed before the error", deleted)
+	}
+}
@@ -39,7 +39,6 @@ package redis
 import (
 	"context"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
@@ -159,6 +158,8 @@ func NewClient(cfg *config.RedisConfig, logger *logrus.Logger) (*Client, error)
 			PoolTimeout:     cfg.PoolTimeout,
 			ConnMaxIdleTime: cfg.IdleTimeout,
 			ConnMaxLifetime: cfg.MaxConnAge,
+			// 读写超时同时受上下文截止时间约束，使命令遵守请求剩余预算
+			ContextTimeoutEnabled: true,
 		})
 	} else {
 		// 单机模式
@@ -175,9 +176,13 @@ func NewClient(cfg *config.RedisConfig, logger *logrus.Logger) (*Client, error)
 			PoolTimeout:     cfg.PoolTimeout,
 			ConnMaxIdleTime: cfg.IdleTimeout,
 			ConnMaxLifetime: cfg.MaxConnAge,
+			// 读写超时同时受上下文截止时间约束，使命令遵守请求剩余预算
+			ContextTimeoutEnabled: true,
 		})
 	}
 
+	rdb.AddHook(newBudgetHook(cfg.OperationTimeout, cfg.MinOperationBudget))
+
 	client := &Client{
 		rdb:     rdb,
 		config:  cfg,
@@ -262,6 +267,30 @@ func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
 	return c.rdb.Incr(ctx, key).Result()
 }
 
+// Increment 一次计数增量
+type Increment struct {
+	Key   string
+	Delta int64
+	TTL   time.Duration // >0 时同时刷新过期时间
+}
+
+// IncrBatch 通过一次 pipeline 执行一批 INCRBY（及 EXPIRE）
+func (c *Client) IncrBatch(ctx context.Context, increments []Increment) error {
+	if len(increments) == 0 {
+		return nil
+	}
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, inc := range increments {
+			pipe.IncrBy(ctx, inc.Key, inc.Delta)
+			if inc.TTL > 0 {
+				pipe.Expire(ctx, inc.Key, inc.TTL)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
 func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) error {
 	return c.rdb.LPush(ctx, key, values...).Err()
 }
@@ -390,7 +419,9 @@ func (c *Client) GetStatus() map[string]interface{} {
 	defer cancel()
 
 	if info, err := c.rdb.Info(ctx, "server", "memory", "stats").Result(); err == nil {
-		status["server_info"] = parseRedisInfo(info)
+		if parsed, err := ParseRedisInfo(info); err == nil {
+			status["server_info"] = parsed.Fields
+		}
 	}
 
 	return status
@@ -403,20 +434,3 @@ func (c *Client) IsHealthy() bool {
 
 	return c.Ping(ctx) == nil
 }
-
-// parseRedisInfo 解析 Redis INFO 命令输出
-func parseRedisInfo(info string) map[string]string {
-	result := make(map[string]string)
-	lines := strings.Split(info, "\r\n")
-
-	for _, line := range lines {
-		if strings.Contains(line, ":") && !strings.HasPrefix(line, "#") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				result[parts[0]] = parts[1]
-			}
-		}
-	}
-
-	return result
-}
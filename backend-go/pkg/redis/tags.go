@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// KeyPrefixTag 标签集合键前缀，每个标签对应一个保存缓存键的集合
+const KeyPrefixTag = "tag"
+
+// TagSetKey 生成标签集合键，例如 tag:match:123
+func TagSetKey(tag string) string {
+	return KeyPrefixTag + ":" + tag
+}
+
+// MatchTag 比赛标签，比赛派生的缓存（共识、列表等）都挂在该标签下
+func MatchTag(matchID uint) string {
+	return fmt.Sprintf("%s:%d", KeyPrefixMatch, matchID)
+}
+
+// TournamentTag 锦标赛标签
+func TournamentTag(tournament string) string {
+	return "tournament:" + tournament
+}
+
+// tagStore 标签操作依赖的缓存操作
+type tagStore interface {
+	SAdd(ctx context.Context, key string, members ...interface{}) error
+	SMembers(ctx context.Context, key string) ([]string, error)
+	MDelete(ctx context.Context, keys ...string) error
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
+// tagKey 将缓存键登记到标签集合，集合过期时间不短于缓存键，避免键仍存在而标签已丢失
+func tagKey(ctx context.Context, store tagStore, key string, expiration time.Duration, tags ...string) error {
+	for _, tag := range tags {
+		setKey := TagSetKey(tag)
+		if err := store.SAdd(ctx, setKey, key); err != nil {
+			return fmt.Errorf("failed to tag key %s with %s: %w", key, tag, err)
+		}
+
+		if expiration <= 0 {
+			continue
+		}
+		// TTL 为负表示集合永不过期或刚创建，两种情况都以缓存键过期时间为准
+		ttl, err := store.TTL(ctx, setKey)
+		if err != nil {
+			return fmt.Errorf("failed to get ttl of tag %s: %w", tag, err)
+		}
+		if ttl < expiration {
+			if err := store.Expire(ctx, setKey, expiration); err != nil {
+				return fmt.Errorf("failed to set ttl of tag %s: %w", tag, err)
+			}
+		}
+	}
+	return nil
+}
+
+// invalidateTag 删除标签下的所有缓存键及标签集合本身
+func invalidateTag(ctx context.Context, store tagStore, tag string) error {
+	setKey := TagSetKey(tag)
+	keys, err := store.SMembers(ctx, setKey)
+	if err != nil {
+		return fmt.Errorf("failed to get keys of tag %s: %w", tag, err)
+	}
+
+	if err := store.MDelete(ctx, append(keys, setKey)...); err != nil {
+		return fmt.Errorf("failed to invalidate tag %s: %w", tag, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeTagStore 在 fakeStore 基础上支持集合操作
+type fakeTagStore struct {
+	*fakeStore
+	sets map[string]map[string]bool
+}
+
+func newFakeTagStore() *fakeTagStore {
+	return &fakeTagStore{fakeStore: newFakeStore(), sets: make(map[string]map[string]bool)}
+}
+
+func (f *fakeTagStore) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	if f.sets[key] == nil {
+		f.sets[key] = make(map[string]bool)
+	}
+	for _, m := range members {
+		f.sets[key][m.(string)] = true
+	}
+	return nil
+}
+
+func (f *fakeTagStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	members := make([]string, 0, len(f.sets[key]))
+	for m := range f.sets[key] {
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (f *fakeTagStore) MDelete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(f.values, key)
+		delete(f.sets, key)
+		delete(f.expiry, key)
+	}
+	return nil
+}
+
+func (f *fakeTagStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	f.expiry[key] = f.now.Add(expiration)
+	return nil
+}
+
+func (f *fakeTagStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	exp, ok := f.expiry[key]
+	if !ok {
+		return -1, nil
+	}
+	return exp.Sub(f.now), nil
+}
+
+func TestInvalidateTag_RemovesOnlyTaggedKeys(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTagStore()
+
+	keys := map[string][]string{
+		ConsensusKey(1):         {MatchTag(1)},
+		"match:list:SPRING":     {MatchTag(1), MatchTag(2), TournamentTag("SPRING")},
+		ConsensusKey(2):         {MatchTag(2)},
+		"leaderboard:SPRING":    {TournamentTag("SPRING")},
+		"user:profile:untagged": nil,
+	}
+	for key, tags := range keys {
+		store.Set(ctx, key, "v", time.Minute)
+		if err := tagKey(ctx, store, key, time.Minute, tags...); err != nil {
+			t.Fatalf("tagKey(%s) error = %v", key, err)
+		}
+	}
+
+	if err := invalidateTag(ctx, store, MatchTag(1)); err != nil {
+		t.Fatalf("invalidateTag() error = %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{ConsensusKey(1), false},
+		{"match:list:SPRING", false},
+		{ConsensusKey(2), true},
+		{"leaderboard:SPRING", true},
+		{"user:profile:untagged", true},
+	}
+	for _, tt := range tests {
+		if _, err := store.Get(ctx, tt.key); (err == nil) != tt.want {
+			t.Errorf("%s 存在 = %v, want %v", tt.key, err == nil, tt.want)
+		}
+	}
+	if _, ok := store.sets[TagSetKey(MatchTag(1))]; ok {
+		t.Errorf("标签集合本身应被删除")
+	}
+}
+
+func TestTagKey_ExtendsTagTTL(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeTagStore()
+	setKey := TagSetKey(MatchTag(1))
+
+	tagKey(ctx, store, "short", time.Minute, MatchTag(1))
+	tagKey(ctx, store, "long", time.Hour, MatchTag(1))
+	tagKey(ctx, store, "shorter", time.Second, MatchTag(1))
+
+	if ttl, _ := store.TTL(ctx, setKey); ttl != time.Hour {
+		t.Errorf("标签集合 TTL = %v, want %v", ttl, time.Hour)
+	}
+}
+
+func TestTagSetKey(t *testing.T) {
+	if got := TagSetKey(MatchTag(123)); got != "tag:match:123" {
+		t.Errorf("TagSetKey(MatchTag(123)) = %s, want tag:match:123", got)
+	}
+	if got := TagSetKey(TournamentTag("SPRING")); got != "tag:tournament:SPRING" {
+		t.Errorf("TagSetKey(TournamentTag(SPRING)) = %s, want tag:tournament:SPRING", got)
+	}
+}
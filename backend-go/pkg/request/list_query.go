@@ -0,0 +1,182 @@
+// Package request 解析列表接口通用的查询参数
+//
+// 列表接口共用 page、page_size、sort_by、sort_order 以及 search、status、is_active
+// 等过滤参数。ParseListQuery 统一解析和校验这些参数，所有无效字段一次性以
+// 422 校验错误返回，避免各处理器各自解析、行为不一致。
+//
+// 每页数量上限由分页中间件按 server.pagination 配置通过 SetPageSizeLimit 写入请求，
+// 未设置时使用 MaxPageSize 并截断。
+package request
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/response"
+)
+
+const (
+	// DefaultPage 默认页码
+	DefaultPage = 1
+	// DefaultPageSize 默认每页数量
+	DefaultPageSize = 20
+	// MaxPageSize 未通过 SetPageSizeLimit 设置上限时的每页数量上限，超过时截断为该值
+	MaxPageSize = 100
+
+	// SortAsc 升序
+	SortAsc = "asc"
+	// SortDesc 降序
+	SortDesc = "desc"
+)
+
+// pageSizeLimitKey 每页数量上限在 gin.Context 中的键
+const pageSizeLimitKey = "request.page_size_limit"
+
+// PageSizeLimit 每页数量上限
+type PageSizeLimit struct {
+	Max    int  // 上限，<=0 表示不限制
+	Reject bool // 为 true 时超出上限返回校验错误，否则截断为上限
+}
+
+// SetPageSizeLimit 设置当前请求解析 page_size 时使用的上限
+func SetPageSizeLimit(c *gin.Context, limit PageSizeLimit) {
+	c.Set(pageSizeLimitKey, limit)
+}
+
+// pageSizeLimit 返回当前请求的每页数量上限，未设置时为 MaxPageSize
+func pageSizeLimit(c *gin.Context) PageSizeLimit {
+	if value, ok := c.Get(pageSizeLimitKey); ok {
+		if limit, ok := value.(PageSizeLimit); ok {
+			return limit
+		}
+	}
+	return PageSizeLimit{Max: MaxPageSize}
+}
+
+// ListQuery 列表接口的查询参数
+type ListQuery struct {
+	Page      int
+	PageSize  int
+	SortBy    string // 排序字段，未指定时为空，由调用方使用默认排序
+	SortOrder string // asc 或 desc，未指定时为空，由调用方决定默认方向
+	Search    string // 关键字，取自 search 或 q
+	Status    string
+	IsActive  *bool
+}
+
+// Offset 返回当前页的偏移量
+func (q ListQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// OrderClause 返回排序子句，未指定排序字段时返回 defaultClause，未指定方向时按降序
+//
+// SortBy 已经过白名单校验，可以直接拼入 SQL。
+func (q ListQuery) OrderClause(defaultClause string) string {
+	if q.SortBy == "" {
+		return defaultClause
+	}
+	order := q.SortOrder
+	if order == "" {
+		order = SortDesc
+	}
+	return q.SortBy + " " + strings.ToUpper(order)
+}
+
+// ParseListQuery 解析并校验列表查询参数
+//
+// sort_by（兼容旧参数 order_by）必须在 allowedSortFields 中；page_size 超过
+// 请求的上限（见 SetPageSizeLimit）时截断或报错。参数无效时返回 *response.AppError，
+// Details 为字段到错误信息的映射。
+func ParseListQuery(c *gin.Context, allowedSortFields []string) (ListQuery, error) {
+	query := ListQuery{
+		Page:     DefaultPage,
+		PageSize: DefaultPageSize,
+		Search:   strings.TrimSpace(firstQuery(c, "search", "q")),
+		Status:   strings.TrimSpace(c.Query("status")),
+	}
+	fields := map[string]string{}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			fields["page"] = "必须是大于 0 的整数"
+		} else {
+			query.Page = page
+		}
+	}
+
+	if value := c.Query("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		limit := pageSizeLimit(c)
+		switch {
+		case err != nil || pageSize < 1:
+			fields["page_size"] = "必须是大于 0 的整数"
+		case limit.Max > 0 && pageSize > limit.Max && limit.Reject:
+			fields["page_size"] = "不能超过 " + strconv.Itoa(limit.Max)
+		case limit.Max > 0 && pageSize > limit.Max:
+			query.PageSize = limit.Max
+		default:
+			query.PageSize = pageSize
+		}
+	}
+
+	if value := firstQuery(c, "sort_by", "order_by"); value != "" {
+		if containsField(allowedSortFields, value) {
+			query.SortBy = value
+		} else {
+			fields["sort_by"] = "不支持的排序字段，可选值: " + strings.Join(sortedFields(allowedSortFields), ", ")
+		}
+	}
+
+	if value := c.Query("sort_order"); value != "" {
+		switch order := strings.ToLower(value); order {
+		case SortAsc, SortDesc:
+			query.SortOrder = order
+		default:
+			fields["sort_order"] = "必须是 asc 或 desc"
+		}
+	}
+
+	if value := c.Query("is_active"); value != "" {
+		isActive, err := strconv.ParseBool(value)
+		if err != nil {
+			fields["is_active"] = "必须是布尔值"
+		} else {
+			query.IsActive = &isActive
+		}
+	}
+
+	if len(fields) > 0 {
+		return ListQuery{}, response.NewValidationError("查询参数无效", fields)
+	}
+	return query, nil
+}
+
+// firstQuery 返回第一个非空的查询参数
+func firstQuery(c *gin.Context, keys ...string) string {
+	for _, key := range keys {
+		if value := c.Query(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedFields(fields []string) []string {
+	sorted := append([]string(nil), fields...)
+	sort.Strings(sorted)
+	return sorted
+}
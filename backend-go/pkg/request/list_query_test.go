@@ -0,0 +1,121 @@
+package request
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"backend-go/pkg/response"
+)
+
+var testSortFields = []string{"created_at", "priority"}
+
+func parseQuery(t *testing.T, rawQuery string) (ListQuery, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/items?"+rawQuery, nil)
+	return ParseListQuery(c, testSortFields)
+}
+
+// validationFields 断言 err 为校验错误并返回字段错误
+func validationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) || appErr.Code != response.CodeValidationFailed || appErr.StatusCode != 422 {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+	fields, ok := appErr.Details.(map[string]string)
+	if !ok {
+		t.Fatalf("details = %#v, want map[string]string", appErr.Details)
+	}
+	return fields
+}
+
+func TestParseListQuery_Valid(t *testing.T) {
+	q, err := parseQuery(t, "page=3&page_size=50&sort_by=priority&sort_order=ASC&q=%20cup%20&status=open&is_active=false")
+	if err != nil {
+		t.Fatalf("ParseListQuery() err = %v", err)
+	}
+	if q.Page != 3 || q.PageSize != 50 || q.Offset() != 100 {
+		t.Errorf("page = %d, page_size = %d, offset = %d", q.Page, q.PageSize, q.Offset())
+	}
+	if q.SortBy != "priority" || q.SortOrder != SortAsc || q.OrderClause("id DESC") != "priority ASC" {
+		t.Errorf("sort = %q %q", q.SortBy, q.SortOrder)
+	}
+	if q.Search != "cup" || q.Status != "open" || q.IsActive == nil || *q.IsActive {
+		t.Errorf("filters = %q %q %v", q.Search, q.Status, q.IsActive)
+	}
+}
+
+func TestParseListQuery_Defaults(t *testing.T) {
+	q, err := parseQuery(t, "")
+	if err != nil {
+		t.Fatalf("ParseListQuery() err = %v", err)
+	}
+	if q.Page != DefaultPage || q.PageSize != DefaultPageSize || q.SortOrder != "" || q.IsActive != nil {
+		t.Errorf("q = %+v, want defaults", q)
+	}
+	if got := q.OrderClause("priority DESC, created_at DESC"); got != "priority DESC, created_at DESC" {
+		t.Errorf("OrderClause() = %q, want default clause", got)
+	}
+}
+
+func TestParseListQuery_ClampsPageSize(t *testing.T) {
+	q, err := parseQuery(t, "page_size=1000")
+	if err != nil {
+		t.Fatalf("ParseListQuery() err = %v", err)
+	}
+	if q.PageSize != MaxPageSize {
+		t.Errorf("page_size = %d, want %d", q.PageSize, MaxPageSize)
+	}
+}
+
+func TestParseListQuery_UsesConfiguredPageSizeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(limit PageSizeLimit, rawQuery string) (ListQuery, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/items?"+rawQuery, nil)
+		SetPageSizeLimit(c, limit)
+		return ParseListQuery(c, testSortFields)
+	}
+
+	if q, err := parse(PageSizeLimit{Max: 500}, "page_size=300"); err != nil || q.PageSize != 300 {
+		t.Errorf("max 500: page_size = %d, err = %v, want 300", q.PageSize, err)
+	}
+	if q, err := parse(PageSizeLimit{Max: 50}, "page_size=300"); err != nil || q.PageSize != 50 {
+		t.Errorf("max 50: page_size = %d, err = %v, want 截断为 50", q.PageSize, err)
+	}
+	if q, err := parse(PageSizeLimit{}, "page_size=1000"); err != nil || q.PageSize != 1000 {
+		t.Errorf("不限制: page_size = %d, err = %v, want 1000", q.PageSize, err)
+	}
+	_, err := parse(PageSizeLimit{Max: 50, Reject: true}, "page_size=300")
+	if fields := validationFields(t, err); fields["page_size"] == "" {
+		t.Errorf("fields = %v, want page_size error", fields)
+	}
+}
+
+func TestParseListQuery_RejectsDisallowedSortField(t *testing.T) {
+	_, err := parseQuery(t, "sort_by=password")
+	if fields := validationFields(t, err); fields["sort_by"] == "" {
+		t.Errorf("fields = %v, want sort_by error", fields)
+	}
+
+	// 旧参数 order_by 同样受白名单限制
+	_, err = parseQuery(t, "order_by=password")
+	if fields := validationFields(t, err); fields["sort_by"] == "" {
+		t.Errorf("fields = %v, want sort_by error", fields)
+	}
+}
+
+func TestParseListQuery_RejectsInvalidSortOrder(t *testing.T) {
+	_, err := parseQuery(t, "sort_by=created_at&sort_order=sideways&page=0&is_active=maybe")
+	fields := validationFields(t, err)
+	for _, field := range []string{"sort_order", "page", "is_active"} {
+		if fields[field] == "" {
+			t.Errorf("fields = %v, want %s error", fields, field)
+		}
+	}
+}
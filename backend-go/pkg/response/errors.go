@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // AppError 应用错误
@@ -79,6 +80,7 @@ const (
 	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
 	CodeTimeout            = "TIMEOUT"
 	CodeRateLimit          = "RATE_LIMIT_EXCEEDED"
+	CodeRequestTooLarge    = "REQUEST_TOO_LARGE"
 
 	// 业务错误代码
 	CodeUserNotFound       = "USER_NOT_FOUND"
@@ -95,6 +97,7 @@ const (
 	CodePredictionNotFound = "PREDICTION_NOT_FOUND"
 	CodePredictionExists   = "PREDICTION_EXISTS"
 	CodePredictionLocked   = "PREDICTION_LOCKED"
+	CodePredictionLimit    = "PREDICTION_DAILY_LIMIT"
 
 	CodeVoteExists   = "VOTE_EXISTS"
 	CodeVoteNotFound = "VOTE_NOT_FOUND"
@@ -108,6 +111,7 @@ const (
 	CodeDatabaseConnection  = "DATABASE_CONNECTION_ERROR"
 	CodeDatabaseQuery       = "DATABASE_QUERY_ERROR"
 	CodeDatabaseTransaction = "DATABASE_TRANSACTION_ERROR"
+	CodeDatabaseRetryable   = "DATABASE_RETRYABLE" // 死锁或锁等待超时，可重试
 
 	// 缓存错误代码
 	CodeCacheConnection = "CACHE_CONNECTION_ERROR"
@@ -300,6 +304,17 @@ func NewMatchStartedError(matchID interface{}) *AppError {
 	}
 }
 
+// NewPredictionLockedError 预测已锁定错误，lockAt 为实际生效的锁定时间
+func NewPredictionLockedError(matchID interface{}, lockAt time.Time) *AppError {
+	return &AppError{
+		Type:       ErrorTypeBusiness,
+		Code:       CodePredictionLocked,
+		Message:    "预测已锁定，无法提交或修改预测",
+		Details:    map[string]interface{}{"match_id": matchID, "lock_at": lockAt},
+		StatusCode: 400,
+	}
+}
+
 // NewPredictionNotFoundError 预测不存在错误
 func NewPredictionNotFoundError(predictionID interface{}) *AppError {
 	return &AppError{
@@ -401,6 +416,28 @@ func NewRateLimitError(limit int, window string) *AppError {
 	}
 }
 
+// NewDailyPredictionLimitError 每日预测次数超限错误，resetAt 为计数重置时间
+func NewDailyPredictionLimitError(limit int, resetAt time.Time) *AppError {
+	return &AppError{
+		Type:       ErrorTypeRateLimit,
+		Code:       CodePredictionLimit,
+		Message:    fmt.Sprintf("今日预测次数已达上限（%d 次），请明天再试", limit),
+		Details:    map[string]interface{}{"limit": limit, "window": "24h", "reset_at": resetAt},
+		StatusCode: 429,
+	}
+}
+
+// NewRequestTooLargeError 请求体过大错误
+func NewRequestTooLargeError(maxBytes int64) *AppError {
+	return &AppError{
+		Type:       ErrorTypeValidation,
+		Code:       CodeRequestTooLarge,
+		Message:    "请求体过大",
+		Details:    map[string]interface{}{"max_bytes": maxBytes},
+		StatusCode: 413,
+	}
+}
+
 // 错误包装函数
 
 // WrapError 包装错误
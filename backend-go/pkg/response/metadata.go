@@ -0,0 +1,43 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestStartKey 请求开始时间在 gin.Context 中的键，存在时成功响应附带元信息
+const requestStartKey = "response_request_start"
+
+// Metadata 成功响应附带的调试元信息，用于客户端排查延迟
+type Metadata struct {
+	DurationMs float64   `json:"duration_ms"` // 服务端处理耗时（毫秒）
+	ServerTime time.Time `json:"server_time"` // 服务端生成响应的时间
+}
+
+// WithMetadata 记录请求开始时间，此后的成功响应附带 Metadata
+//
+// 应尽早注册以覆盖完整的处理耗时；未注册时响应中不包含元信息。
+func WithMetadata() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(requestStartKey, time.Now())
+		c.Next()
+	}
+}
+
+// metadataFor 返回当前请求的元信息，未启用时返回 nil
+func metadataFor(c *gin.Context) *Metadata {
+	value, ok := c.Get(requestStartKey)
+	if !ok {
+		return nil
+	}
+	start, ok := value.(time.Time)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	return &Metadata{
+		DurationMs: float64(now.Sub(start).Microseconds()) / 1000,
+		ServerTime: now.UTC(),
+	}
+}
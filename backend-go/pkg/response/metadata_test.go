@@ -0,0 +1,83 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// renderWithMetadata 注册 WithMetadata 后执行 handler 并解析 JSON 响应
+func renderWithMetadata(t *testing.T, accept string, handler gin.HandlerFunc) map[string]interface{} {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(WithMetadata())
+	router.GET("/", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("响应不是合法 JSON: %v", err)
+	}
+	return body
+}
+
+func TestSuccess_MetadataWhenEnabled(t *testing.T) {
+	payload := map[string]interface{}{"id": float64(1)}
+	handler := func(c *gin.Context) {
+		time.Sleep(2 * time.Millisecond)
+		OK(c, "ok", payload)
+	}
+
+	v1 := renderWithMetadata(t, "", handler)
+	meta, ok := v1["meta"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("v1 envelope = %v, want meta", v1)
+	}
+	if d, _ := meta["duration_ms"].(float64); d < 2 {
+		t.Errorf("duration_ms = %v, want >= 2", meta["duration_ms"])
+	}
+	serverTime, err := time.Parse(time.RFC3339Nano, meta["server_time"].(string))
+	if err != nil || time.Since(serverTime) > time.Minute {
+		t.Errorf("server_time = %v, err = %v", meta["server_time"], err)
+	}
+	if data, _ := v1["data"].(map[string]interface{}); data["id"] != float64(1) || len(data) != 1 {
+		t.Errorf("data = %v, want payload unchanged", v1["data"])
+	}
+
+	v2 := renderWithMetadata(t, MediaTypeV2, handler)
+	meta2, _ := v2["meta"].(map[string]interface{})
+	if meta2["version"] != "v2" || meta2["duration_ms"] == nil || meta2["server_time"] == nil {
+		t.Errorf("v2 meta = %v", v2["meta"])
+	}
+}
+
+func TestSuccess_NoMetadataWhenDisabled(t *testing.T) {
+	handler := func(c *gin.Context) { OK(c, "ok", nil) }
+
+	_, v1 := render(t, "", handler)
+	if _, ok := v1["meta"]; ok {
+		t.Errorf("v1 envelope = %v, want no meta", v1)
+	}
+	_, v2 := render(t, MediaTypeV2, handler)
+	meta, _ := v2["meta"].(map[string]interface{})
+	if _, ok := meta["duration_ms"]; ok {
+		t.Errorf("v2 meta = %v, want no timing", meta)
+	}
+
+	// 错误响应不附带元信息
+	errBody := renderWithMetadata(t, "", func(c *gin.Context) { BadRequest(c, "bad") })
+	if _, ok := errBody["meta"]; ok {
+		t.Errorf("error envelope = %v, want no meta", errBody)
+	}
+}
@@ -0,0 +1,104 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NamingStrategy 响应数据的 JSON 字段命名策略
+type NamingStrategy string
+
+const (
+	// NamingPreserve 保持结构体 json 标签原样输出（兼容旧客户端的 camelCase 字段）
+	NamingPreserve NamingStrategy = "preserve"
+	// NamingSnakeCase 统一输出 snake_case 字段，是 API 的规范命名
+	NamingSnakeCase NamingStrategy = "snake_case"
+)
+
+// namingStrategyKey 命名策略在 gin.Context 中的键
+const namingStrategyKey = "response_naming_strategy"
+
+// camelIdentifier 需要转换的字段名：小写字母开头且只含字母数字，包含大写字母
+var camelIdentifier = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*[A-Z][a-zA-Z0-9]*$`)
+
+// WithNamingStrategy 为后续的成功响应指定字段命名策略
+func WithNamingStrategy(strategy NamingStrategy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(namingStrategyKey, strategy)
+		c.Next()
+	}
+}
+
+// ToSnakeCase 将 camelCase 标识符转换为 snake_case，连续大写视为一个缩写词
+//
+//	predictedWinner -> predicted_winner
+//	winnerAPercent  -> winner_a_percent
+//	avatarURL       -> avatar_url
+func ToSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// applyNaming 按请求的命名策略转换响应数据，未指定策略或无法序列化时原样返回
+//
+// 转换在序列化结果上进行，map 中形如 camelCase 标识符的键同样会被转换。
+func applyNaming(c *gin.Context, data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	value, ok := c.Get(namingStrategyKey)
+	if !ok || value != NamingSnakeCase {
+		return data
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return data
+	}
+	return renameKeys(decoded)
+}
+
+// renameKeys 递归转换对象的字段名
+func renameKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if camelIdentifier.MatchString(key) {
+				key = ToSnakeCase(key)
+			}
+			renamed[key] = renameKeys(item)
+		}
+		return renamed
+	case []interface{}:
+		for i, item := range v {
+			v[i] = renameKeys(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,123 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// legacyVote、legacyPrediction 模拟沿用旧 SQLite camelCase 字段的实体
+type legacyVote struct {
+	UserID    uint      `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type legacyPrediction struct {
+	ID              uint         `json:"id"`
+	MatchID         uint         `json:"matchId"`
+	PredictedWinner string       `json:"predictedWinner"`
+	PredictedScoreA int          `json:"predictedScoreA"`
+	WinnerAPercent  float64      `json:"winnerAPercent"`
+	AvatarURL       string       `json:"avatarURL"`
+	EarnedPoints    int          `json:"earned_points"`
+	Votes           []legacyVote `json:"votes"`
+	Latest          *legacyVote  `json:"latestVote,omitempty"`
+}
+
+func renderNamed(t *testing.T, strategy NamingStrategy, data interface{}) map[string]interface{} {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if strategy != "" {
+		router.Use(WithNamingStrategy(strategy))
+	}
+	router.GET("/", func(c *gin.Context) { OK(c, "ok", data) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("响应不是合法 JSON: %v", err)
+	}
+	return body
+}
+
+// collectKeys 递归收集 JSON 对象中的所有字段名
+func collectKeys(value interface{}, keys *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			*keys = append(*keys, key)
+			collectKeys(item, keys)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectKeys(item, keys)
+		}
+	}
+}
+
+func TestSuccess_SnakeCaseNaming(t *testing.T) {
+	vote := legacyVote{UserID: 7, CreatedAt: time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)}
+	payload := []legacyPrediction{{
+		ID: 1, MatchID: 3, PredictedWinner: "A", PredictedScoreA: 2, WinnerAPercent: 62.5,
+		AvatarURL: "/a.png", EarnedPoints: 10, Votes: []legacyVote{vote}, Latest: &vote,
+	}}
+
+	body := renderNamed(t, NamingSnakeCase, payload)
+	var keys []string
+	collectKeys(body, &keys)
+	snake := regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	for _, key := range keys {
+		if !snake.MatchString(key) {
+			t.Errorf("字段 %q 不是 snake_case", key)
+		}
+	}
+
+	first := body["data"].([]interface{})[0].(map[string]interface{})
+	want := map[string]interface{}{
+		"match_id": float64(3), "predicted_winner": "A", "predicted_score_a": float64(2),
+		"winner_a_percent": 62.5, "avatar_url": "/a.png", "earned_points": float64(10),
+	}
+	for key, value := range want {
+		if first[key] != value {
+			t.Errorf("data[0].%s = %v, want %v", key, first[key], value)
+		}
+	}
+	latest := first["latest_vote"].(map[string]interface{})
+	if latest["user_id"] != float64(7) || latest["created_at"] != "2025-10-01T08:00:00Z" {
+		t.Errorf("latest_vote = %v", latest)
+	}
+}
+
+func TestSuccess_PreserveNamingByDefault(t *testing.T) {
+	for _, strategy := range []NamingStrategy{"", NamingPreserve} {
+		body := renderNamed(t, strategy, legacyPrediction{MatchID: 3})
+		data := body["data"].(map[string]interface{})
+		if _, ok := data["matchId"]; !ok {
+			t.Errorf("strategy %q: data = %v, want 原样的 matchId", strategy, data)
+		}
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"predictedWinner": "predicted_winner",
+		"matchId":         "match_id",
+		"winnerAPercent":  "winner_a_percent",
+		"avatarURL":       "avatar_url",
+		"top10Users":      "top10_users",
+		"earned_points":   "earned_points",
+		"id":              "id",
+	}
+	for in, want := range tests {
+		if got := ToSnakeCase(in); got != want {
+			t.Errorf("ToSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -64,6 +64,7 @@ type Response struct {
 	Message string      `json:"message" example:"Operation successful"` // Human-readable message
 	Data    interface{} `json:"data,omitempty"`                         // Response payload (success only)
 	Error   *ErrorInfo  `json:"error,omitempty"`                        // Error details (failure only)
+	Meta    *Metadata   `json:"meta,omitempty"`                         // Debug metadata (success only, when enabled)
 }
 
 // ErrorInfo represents detailed error information for failed API operations.
@@ -107,11 +108,28 @@ type ErrorInfo struct {
 //	  "message": "User retrieved successfully",
 //	  "data": {"id": 1, "name": "John"}
 //	}
+//
+// Requests negotiating EnvelopeV2 receive the same payload in a ResponseV2 envelope.
+// When WithMetadata is registered, the processing duration and server time are
+// added to "meta" without changing the data payload. When WithNamingStrategy
+// selects NamingSnakeCase, the data payload's field names are converted to snake_case.
 func Success(c *gin.Context, statusCode int, message string, data interface{}) {
+	data = applyNaming(c, data)
+	metadata := metadataFor(c)
+	if NegotiateVersion(c) == EnvelopeV2 {
+		meta := metaV2(message)
+		meta.Metadata = metadata
+		writeV2(c, statusCode, ResponseV2{
+			Data: data,
+			Meta: meta,
+		})
+		return
+	}
 	c.JSON(statusCode, Response{
 		Success: true,
 		Message: message,
 		Data:    data,
+		Meta:    metadata,
 	})
 }
 
@@ -141,7 +159,21 @@ func Success(c *gin.Context, statusCode int, message string, data interface{}) {
 //	    "details": "Email format is invalid"
 //	  }
 //	}
+//
+// Requests negotiating EnvelopeV2 receive a ResponseV2 envelope whose error code
+// is derived from the status code.
 func Error(c *gin.Context, statusCode int, message string, details string) {
+	if NegotiateVersion(c) == EnvelopeV2 {
+		errInfo := &ErrorInfoV2{Status: statusCode, Code: codeForStatus(statusCode), Message: message}
+		if details != "" {
+			errInfo.Details = details
+		}
+		writeV2(c, statusCode, ResponseV2{
+			Error: errInfo,
+			Meta:  metaV2(message),
+		})
+		return
+	}
 	c.JSON(statusCode, Response{
 		Success: false,
 		Message: message,
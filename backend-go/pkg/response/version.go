@@ -0,0 +1,159 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvelopeVersion 响应信封版本
+type EnvelopeVersion int
+
+const (
+	// EnvelopeV1 当前信封：success/message/data/error，默认版本
+	EnvelopeV1 EnvelopeVersion = 1
+	// EnvelopeV2 新信封：data/error 与 meta 分离，错误码为字符串
+	EnvelopeV2 EnvelopeVersion = 2
+)
+
+// MediaTypeV2 请求 v2 信封的 Accept 媒体类型
+const MediaTypeV2 = "application/vnd.yuce.v2+json"
+
+// envelopeVersionKey 路由组指定的信封版本在 gin.Context 中的键
+const envelopeVersionKey = "response_envelope_version"
+
+// ResponseV2 v2 响应信封
+type ResponseV2 struct {
+	Data  interface{}  `json:"data,omitempty"`
+	Error *ErrorInfoV2 `json:"error,omitempty"`
+	Meta  MetaV2       `json:"meta"`
+}
+
+// ErrorInfoV2 v2 错误信息，Code 为机器可读的错误代码，Status 为 HTTP 状态码
+type ErrorInfoV2 struct {
+	Status  int         `json:"status"`
+	Code    string      `json:"code"`
+	Type    string      `json:"type,omitempty"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// MetaV2 v2 响应元信息，启用 WithMetadata 时成功响应额外包含耗时与服务端时间
+type MetaV2 struct {
+	Version string `json:"version"`
+	Message string `json:"message"`
+	*Metadata
+}
+
+// UseEnvelopeVersion 为路由组固定信封版本，用于按路径前缀（如 /api/v2）选择版本
+func UseEnvelopeVersion(version EnvelopeVersion) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeVersionKey, version)
+		c.Next()
+	}
+}
+
+// NegotiateVersion 返回当前请求使用的信封版本
+//
+// 路由组通过 UseEnvelopeVersion 指定的版本优先，其次是 Accept 头中的
+// application/vnd.yuce.v2+json，否则使用 v1。
+func NegotiateVersion(c *gin.Context) EnvelopeVersion {
+	if value, ok := c.Get(envelopeVersionKey); ok {
+		if version, ok := value.(EnvelopeVersion); ok {
+			return version
+		}
+	}
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if strings.EqualFold(mediaType, MediaTypeV2) {
+			return EnvelopeV2
+		}
+	}
+	return EnvelopeV1
+}
+
+// FromAppError 按请求协商的信封版本输出应用错误
+func FromAppError(c *gin.Context, err *AppError) {
+	if NegotiateVersion(c) == EnvelopeV2 {
+		writeV2(c, err.StatusCode, ResponseV2{
+			Error: &ErrorInfoV2{
+				Status:  err.StatusCode,
+				Code:    err.Code,
+				Type:    err.Type,
+				Message: err.Message,
+				Details: err.Details,
+			},
+			Meta: metaV2(err.Message),
+		})
+		return
+	}
+	Error(c, err.StatusCode, err.Message, err.Error())
+}
+
+// HandleError 将错误转换为对应状态码的结构化响应，并记录到 c.Errors 供错误报告中间件收集
+//
+// 错误链中包含 AppError 时使用其 StatusCode（未设置时为 500）；其他错误一律返回 500，
+// 且响应中不包含原始错误信息，避免泄露内部细节。响应已写出时只记录不再输出。
+func HandleError(c *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	if last := c.Errors.Last(); last == nil || last.Err != err {
+		_ = c.Error(err)
+	}
+	if c.Writer.Written() {
+		return
+	}
+
+	var appErr *AppError
+	if !errors.As(err, &appErr) {
+		appErr = NewInternalError("服务器内部错误")
+	}
+	if appErr.StatusCode == 0 {
+		withStatus := *appErr
+		withStatus.StatusCode = http.StatusInternalServerError
+		appErr = &withStatus
+	}
+	FromAppError(c, appErr)
+}
+
+// metaV2 构造 v2 元信息
+func metaV2(message string) MetaV2 {
+	return MetaV2{Version: "v2", Message: message}
+}
+
+// writeV2 以 v2 媒体类型输出响应
+func writeV2(c *gin.Context, statusCode int, body ResponseV2) {
+	c.Header("Content-Type", MediaTypeV2+"; charset=utf-8")
+	c.JSON(statusCode, body)
+}
+
+// codeForStatus 为仅有状态码的错误推导 v2 错误代码
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodeRequestTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimit
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return CodeTimeout
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternalError
+	}
+}
@@ -0,0 +1,137 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// render 使用指定的 Accept 头执行 handler 并解析 JSON 响应
+func render(t *testing.T, accept string, handler gin.HandlerFunc) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("响应不是合法 JSON: %v", err)
+	}
+	return w, body
+}
+
+func TestSuccess_EnvelopeByAcceptHeader(t *testing.T) {
+	payload := map[string]interface{}{"id": 1, "name": "alice"}
+	handler := func(c *gin.Context) { OK(c, "User retrieved", payload) }
+
+	w, v1 := render(t, "application/json", handler)
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Errorf("v1 Content-Type = %q", got)
+	}
+	if v1["success"] != true || v1["message"] != "User retrieved" {
+		t.Errorf("v1 envelope = %v", v1)
+	}
+	if _, ok := v1["meta"]; ok {
+		t.Error("v1 envelope should not contain meta")
+	}
+
+	w, v2 := render(t, "text/html, "+MediaTypeV2+"; q=0.9", handler)
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, MediaTypeV2) {
+		t.Errorf("v2 Content-Type = %q, want %s", got, MediaTypeV2)
+	}
+	if _, ok := v2["success"]; ok {
+		t.Error("v2 envelope should not contain success")
+	}
+	meta, _ := v2["meta"].(map[string]interface{})
+	if meta["version"] != "v2" || meta["message"] != "User retrieved" {
+		t.Errorf("v2 meta = %v", v2["meta"])
+	}
+
+	// 两个版本的 data 相同
+	if d1, d2 := v1["data"].(map[string]interface{}), v2["data"].(map[string]interface{}); d1["name"] != "alice" || d2["name"] != "alice" {
+		t.Errorf("data v1 = %v, v2 = %v", d1, d2)
+	}
+}
+
+func TestFromAppError_EnvelopeByAcceptHeader(t *testing.T) {
+	handler := func(c *gin.Context) { FromAppError(c, NewMatchNotFoundError(7)) }
+
+	w, v1 := render(t, "", handler)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	errV1, _ := v1["error"].(map[string]interface{})
+	if v1["success"] != false || errV1["code"] != float64(http.StatusNotFound) {
+		t.Errorf("v1 envelope = %v", v1)
+	}
+
+	w, v2 := render(t, MediaTypeV2, handler)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	errV2, _ := v2["error"].(map[string]interface{})
+	if errV2["code"] != CodeMatchNotFound || errV2["status"] != float64(http.StatusNotFound) || errV2["type"] != ErrorTypeBusiness {
+		t.Errorf("v2 error = %v", errV2)
+	}
+	if details, _ := errV2["details"].(map[string]interface{}); details["match_id"] != float64(7) {
+		t.Errorf("v2 details = %v, want structured match_id", errV2["details"])
+	}
+}
+
+func TestError_V2DerivesCodeFromStatus(t *testing.T) {
+	_, body := render(t, MediaTypeV2, func(c *gin.Context) { BadRequest(c, "email is invalid") })
+
+	errV2, _ := body["error"].(map[string]interface{})
+	if errV2["code"] != CodeBadRequest || errV2["details"] != "email is invalid" {
+		t.Errorf("v2 error = %v", errV2)
+	}
+}
+
+func TestUseEnvelopeVersion_OverridesAcceptHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v2 := router.Group("/api/v2", UseEnvelopeVersion(EnvelopeV2))
+	v2.GET("/ping", func(c *gin.Context) { OK(c, "pong", nil) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/ping", nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("响应不是合法 JSON: %v", err)
+	}
+	if _, ok := body["meta"]; !ok {
+		t.Errorf("路径前缀组应输出 v2 信封: %v", body)
+	}
+}
+
+func TestHandleError_StatusFromAppError(t *testing.T) {
+	w, v2 := render(t, MediaTypeV2, func(c *gin.Context) {
+		HandleError(c, NewAppError(ErrorTypeBusiness, "CUSTOM", "no status", 0))
+		if len(c.Errors) != 1 {
+			t.Errorf("c.Errors = %v, want the handled error recorded", c.Errors)
+		}
+	})
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 for AppError without status", w.Code)
+	}
+	if errV2, _ := v2["error"].(map[string]interface{}); errV2["code"] != "CUSTOM" {
+		t.Errorf("v2 error = %v", v2["error"])
+	}
+
+	w, _ = render(t, "", func(c *gin.Context) { HandleError(c, NewUnauthorizedError("请先登录")) })
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", w.Code)
+	}
+}
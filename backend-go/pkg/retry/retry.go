@@ -0,0 +1,73 @@
+// Package retry 为启动阶段的依赖连接提供带指数退避的重试
+//
+// 容器编排中应用可能先于数据库或 Redis 就绪，初始连接失败时应等待依赖启动，
+// 而不是直接退出后被反复拉起。
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Policy 重试策略
+type Policy struct {
+	Attempts    int           // 最大尝试次数，<=1 表示只尝试一次
+	Interval    time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	MaxInterval time.Duration // 单次等待上限，<=0 表示不限制
+}
+
+// delay 返回第 attempt 次失败后的等待时间
+func (p Policy) delay(attempt int) time.Duration {
+	d := p.Interval
+	for i := 1; i < attempt; i++ {
+		if p.MaxInterval > 0 && d >= p.MaxInterval {
+			break
+		}
+		d *= 2
+	}
+	if p.MaxInterval > 0 && d > p.MaxInterval {
+		d = p.MaxInterval
+	}
+	return d
+}
+
+// Do 按策略执行 fn 直到成功，每次失败记录日志；次数耗尽时返回最后一次错误
+func Do(ctx context.Context, name string, policy Policy, logger *logrus.Logger, fn func() error) error {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	attempts := max(policy.Attempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(); err == nil {
+			if attempt > 1 {
+				logger.WithField("attempts", attempt).Infof("Connected to %s", name)
+			}
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		wait := policy.delay(attempt)
+		logger.WithError(err).WithFields(logrus.Fields{
+			"attempt":      attempt,
+			"max_attempts": attempts,
+			"retry_in":     wait.String(),
+		}).Warnf("Failed to connect to %s, retrying", name)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: gave up after %d attempts: %w", name, attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	return fmt.Errorf("%s unavailable after %d attempts: %w", name, attempts, err)
+}
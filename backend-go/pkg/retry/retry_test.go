@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// flakyConnector 前 failures 次连接失败，之后成功
+type flakyConnector struct {
+	failures int
+	calls    int
+}
+
+var errNotReady = errors.New("connection refused")
+
+func (c *flakyConnector) connect() error {
+	c.calls++
+	if c.calls <= c.failures {
+		return errNotReady
+	}
+	return nil
+}
+
+func TestDo_ConnectsAfterTransientFailures(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	connector := &flakyConnector{failures: 2}
+
+	err := Do(context.Background(), "database", Policy{Attempts: 5, Interval: time.Millisecond}, logger, connector.connect)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if connector.calls != 3 {
+		t.Errorf("calls = %d, want 3", connector.calls)
+	}
+
+	// 两次失败各记录一条警告，成功后记录一条信息
+	var warnings int
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings++
+		}
+	}
+	if warnings != 2 {
+		t.Errorf("warnings = %d, want 2", warnings)
+	}
+	if last := hook.LastEntry(); last == nil || last.Level != logrus.InfoLevel || last.Data["attempts"] != 3 {
+		t.Errorf("last entry = %+v, want info with attempts=3", last)
+	}
+}
+
+func TestDo_FailsAfterExhaustingAttempts(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	connector := &flakyConnector{failures: 10}
+
+	err := Do(context.Background(), "redis", Policy{Attempts: 3, Interval: time.Millisecond}, logger, connector.connect)
+	if !errors.Is(err, errNotReady) {
+		t.Fatalf("Do() error = %v, want wrapped last error", err)
+	}
+	if connector.calls != 3 {
+		t.Errorf("calls = %d, want 3", connector.calls)
+	}
+}
+
+func TestDo_StopsWhenContextDone(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	connector := &flakyConnector{failures: 10}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Do(ctx, "redis", Policy{Attempts: 3, Interval: time.Hour}, logger, connector.connect)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do() error = %v, want context.Canceled", err)
+	}
+	if connector.calls != 1 {
+		t.Errorf("calls = %d, want 1", connector.calls)
+	}
+}
+
+func TestPolicy_DelayBacksOffUpToMax(t *testing.T) {
+	policy := Policy{Interval: time.Second, MaxInterval: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := policy.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
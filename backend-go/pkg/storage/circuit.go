@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend-go/pkg/breaker"
+	"backend-go/pkg/response"
+)
+
+// writableStorage 可写入文件的存储
+type writableStorage interface {
+	Storage
+	Writer
+}
+
+// CircuitStorage 带熔断的文件存储
+//
+// 只有 Put 会访问对象存储，SignedURL 在本地计算签名，不经过熔断器。
+type CircuitStorage struct {
+	next    writableStorage
+	breaker *breaker.Breaker
+}
+
+// NewCircuitStorage 为可写入的文件存储加上熔断，熔断打开时 Put 立即返回外部服务错误
+func NewCircuitStorage(next writableStorage, b *breaker.Breaker) *CircuitStorage {
+	return &CircuitStorage{next: next, breaker: b}
+}
+
+// SignedURL 返回 ttl 内有效的下载地址
+func (s *CircuitStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.next.SignedURL(ctx, key, ttl)
+}
+
+// Put 写入文件，对象存储连续失败后快速失败
+func (s *CircuitStorage) Put(ctx context.Context, key string, data []byte) error {
+	// 无效的文件键是调用方的问题，不计入外部服务失败
+	if _, err := cleanKey(key); err != nil {
+		return err
+	}
+	err := s.breaker.Execute(ctx, func(ctx context.Context) error {
+		return s.next.Put(ctx, key, data)
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		return response.NewExternalServiceError(s.breaker.Name(), err)
+	}
+	return err
+}
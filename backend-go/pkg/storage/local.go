@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocalURLPrefix 本地文件下载接口的路径前缀
+const DefaultLocalURLPrefix = "/api/files"
+
+// LocalConfig 本地存储配置
+type LocalConfig struct {
+	Dir        string // 文件根目录
+	URLPrefix  string // 下载接口路径前缀，默认 /api/files
+	SigningKey string // 下载令牌的签名密钥
+}
+
+// LocalStorage 本地磁盘存储
+//
+// 下载地址形如 /api/files/avatars/a.png?expires=1700000000&signature=...，
+// 签名覆盖文件键与过期时间，下载接口通过 Verify 校验。
+type LocalStorage struct {
+	dir       string
+	urlPrefix string
+	key       []byte
+	now       func() time.Time
+}
+
+// NewLocalStorage 创建本地存储，签名密钥不能为空
+func NewLocalStorage(config LocalConfig) (*LocalStorage, error) {
+	if config.SigningKey == "" {
+		return nil, errors.New("local storage signing key is required")
+	}
+	if config.URLPrefix == "" {
+		config.URLPrefix = DefaultLocalURLPrefix
+	}
+	return &LocalStorage{
+		dir:       config.Dir,
+		urlPrefix: strings.TrimSuffix(config.URLPrefix, "/"),
+		key:       []byte(config.SigningKey),
+		now:       time.Now,
+	}, nil
+}
+
+// SignedURL 返回带签名令牌的下载接口地址
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if err := validateTTL(ttl); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	return s.urlPrefix + "/" + escapeKey(key) + "?expires=" + expires + "&signature=" + s.sign(key, expires), nil
+}
+
+// Verify 校验下载令牌，通过时返回文件在磁盘上的路径
+func (s *LocalStorage) Verify(key, expires, signature string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return "", ErrInvalidSignature
+	}
+	if s.now().Unix() > expiresAt {
+		return "", ErrURLExpired
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put 写入文件，按需创建上级目录
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
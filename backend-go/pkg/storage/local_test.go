@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStorage(t *testing.T, now time.Time) *LocalStorage {
+	t.Helper()
+	s, err := NewLocalStorage(LocalConfig{Dir: "/data/uploads", SigningKey: "test-signing-key"})
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+	s.now = func() time.Time { return now }
+	return s
+}
+
+// parseLocalURL 拆出下载地址中的文件键、过期时间与签名
+func parseLocalURL(t *testing.T, raw string) (key, expires, signature string) {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("解析地址失败: %v", err)
+	}
+	if !strings.HasPrefix(u.Path, DefaultLocalURLPrefix+"/") {
+		t.Fatalf("地址 = %s, want 前缀 %s", raw, DefaultLocalURLPrefix)
+	}
+	return strings.TrimPrefix(u.Path, DefaultLocalURLPrefix+"/"), u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestLocalStorage_SignedURLEncodesTTL(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestLocalStorage(t, now)
+
+	raw, err := s.SignedURL(context.Background(), "exports/report 2025.csv", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+	if !strings.HasPrefix(raw, "/api/files/exports/report%202025.csv?expires=") {
+		t.Errorf("地址 = %s, want 转义后的文件键", raw)
+	}
+
+	key, expires, signature := parseLocalURL(t, raw)
+	if want := "1748780100"; expires != want {
+		t.Errorf("expires = %s, want %s（当前时间 + 15 分钟）", expires, want)
+	}
+	path, err := s.Verify(key, expires, signature)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if want := filepath.Join("/data/uploads", "exports", "report 2025.csv"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+
+	// 修改过期时间或文件键都会使签名失效
+	if _, err := s.Verify(key, "1999999999", signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("延长过期时间 error = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := s.Verify("exports/other.csv", expires, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("替换文件键 error = %v, want ErrInvalidSignature", err)
+	}
+
+	s.now = func() time.Time { return now.Add(16 * time.Minute) }
+	if _, err := s.Verify(key, expires, signature); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("过期后 error = %v, want ErrURLExpired", err)
+	}
+}
+
+func TestLocalStorage_RejectsInvalidInput(t *testing.T) {
+	s := newTestLocalStorage(t, time.Now())
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "avatars/../../secret", `avatars\a.png`} {
+		if _, err := s.SignedURL(context.Background(), key, time.Minute); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("SignedURL(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+	for _, ttl := range []time.Duration{0, -time.Minute, MaxSignedURLTTL + time.Second} {
+		if _, err := s.SignedURL(context.Background(), "avatars/a.png", ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("SignedURL(ttl=%s) error = %v, want ErrInvalidTTL", ttl, err)
+		}
+	}
+	if _, err := NewLocalStorage(LocalConfig{Dir: "./uploads"}); err == nil {
+		t.Error("缺少签名密钥时应返回错误")
+	}
+}
@@ -0,0 +1,87 @@
+// Package storage 为存储在本地磁盘或 S3/MinIO 的文件生成限时下载地址
+//
+// 使用对象存储时由客户端直接从存储桶下载，不再经过 API 转发文件内容；
+// 本地存储没有独立的下载服务，返回带签名令牌的 API 地址，由下载接口校验后提供文件。
+package storage
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+)
+
+// MaxSignedURLTTL 签名地址的最长有效期，与 S3 预签名的上限一致
+const MaxSignedURLTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidKey 文件键为空、为绝对路径或包含 ..
+	ErrInvalidKey = errors.New("invalid storage key")
+	// ErrInvalidTTL 有效期不在 (0, MaxSignedURLTTL] 范围内
+	ErrInvalidTTL = errors.New("invalid signed url ttl")
+	// ErrInvalidSignature 下载令牌与文件键不匹配
+	ErrInvalidSignature = errors.New("invalid download signature")
+	// ErrURLExpired 下载地址已过期
+	ErrURLExpired = errors.New("download url expired")
+)
+
+// Storage 文件存储
+type Storage interface {
+	// SignedURL 返回 ttl 内有效的下载地址
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Writer 可写入文件的存储，LocalStorage 与 S3Storage 均实现该接口
+type Writer interface {
+	// Put 写入文件，已存在时覆盖
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// cleanKey 规范化文件键，拒绝越出存储根目录的键
+func cleanKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return path.Clean(key), nil
+}
+
+func validateTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxSignedURLTTL {
+		return ErrInvalidTTL
+	}
+	return nil
+}
+
+// escapeKey 按路径段转义文件键，保留分隔符
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEncode 按 RFC 3986 转义，只保留非保留字符，与 AWS 签名的编码规则一致
+func uriEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0x0F])
+	}
+	return b.String()
+}
@@ -62,7 +62,7 @@ fi
 echo -e "${YELLOW}构建二进制文件...${NC}"
 
 # 设置构建标志
-LDFLAGS="-X main.Version=${VERSION} -X main.BuildTime=${BUILD_TIME} -X main.GitCommit=${GIT_COMMIT}"
+LDFLAGS="-X backend-go/pkg/buildinfo.Version=${VERSION} -X backend-go/pkg/buildinfo.BuildDate=${BUILD_TIME} -X backend-go/pkg/buildinfo.GitCommit=${GIT_COMMIT}"
 
 # 构建 API 服务
 echo -e "${YELLOW}构建 API 服务...${NC}"